@@ -2,14 +2,14 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
-	"github.com/AlecAivazis/survey/v2"
 	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
 	"github.com/jashkahar/open-workbench-platform/internal/resources"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var addResourceCmd = &cobra.Command{
@@ -99,6 +99,7 @@ func runAddResource(cmd *cobra.Command, args []string) error {
 
 	// Print success message with next steps and actionable guidance
 	printAddResourceSuccessMessage(serviceName, resourceName, resourceType, blueprint, resourceConfig)
+	refreshServiceReadmes(projectRoot, manifest, serviceName)
 
 	return nil
 }
@@ -121,14 +122,13 @@ func getResourceParameters(cmd *cobra.Command, manifest *manifestPkg.WorkbenchMa
 			return "", "", "", fmt.Errorf("no services found in workbench.yaml")
 		}
 
-		var selectedService string
-		prompt := &survey.Select{
+		selectedService, err := prompter.Select(prompt.Question{
+			Name:    "service",
 			Message: "Which service should this resource belong to?",
 			Options: serviceNames,
 			Help:    "Select the service that will use this resource",
-		}
-
-		if err := survey.AskOne(prompt, &selectedService); err != nil {
+		})
+		if err != nil {
 			return "", "", "", fmt.Errorf("failed to get service selection: %w", err)
 		}
 
@@ -156,14 +156,13 @@ func getResourceParameters(cmd *cobra.Command, manifest *manifestPkg.WorkbenchMa
 			}
 		}
 
-		var selectedOption string
-		prompt := &survey.Select{
+		selectedOption, err := prompter.Select(prompt.Question{
+			Name:    "resourceType",
 			Message: "Which type of resource would you like to add?",
 			Options: options,
 			Help:    "Select the type of resource to add to your service",
-		}
-
-		if err := survey.AskOne(prompt, &selectedOption); err != nil {
+		})
+		if err != nil {
 			return "", "", "", fmt.Errorf("failed to get resource type selection: %w", err)
 		}
 
@@ -182,13 +181,12 @@ func getResourceParameters(cmd *cobra.Command, manifest *manifestPkg.WorkbenchMa
 
 	if resourceName == "" {
 		// Interactive mode - prompt for resource name
-		var name string
-		prompt := &survey.Input{
+		name, err := prompter.Input(prompt.Question{
+			Name:    "resourceName",
 			Message: "What should this resource be named?",
 			Help:    "Enter a descriptive name for this resource (e.g., user_database, cache_store)",
-		}
-
-		if err := survey.AskOne(prompt, &name); err != nil {
+		})
+		if err != nil {
 			return "", "", "", fmt.Errorf("failed to get resource name: %w", err)
 		}
 
@@ -201,7 +199,7 @@ func getResourceParameters(cmd *cobra.Command, manifest *manifestPkg.WorkbenchMa
 func validateResourceConfiguration(manifest *manifestPkg.WorkbenchManifest, serviceName, resourceType, resourceName string) error {
 	// Validate service exists
 	if _, exists := manifest.Services[serviceName]; !exists {
-		return fmt.Errorf("service '%s' not found in workbench.yaml", serviceName)
+		return newNotFoundError("service '%s' not found in workbench.yaml", serviceName)
 	}
 
 	// Validate resource type
@@ -212,13 +210,13 @@ func validateResourceConfiguration(manifest *manifestPkg.WorkbenchManifest, serv
 
 	// Validate resource name format
 	if resourceName == "" {
-		return fmt.Errorf("resource name cannot be empty")
+		return newValidationError("resource name cannot be empty")
 	}
 
 	// Check for duplicate resource name in the service
 	service := manifest.Services[serviceName]
 	if _, exists := service.Resources[resourceName]; exists {
-		return fmt.Errorf("resource '%s' already exists in service '%s'", resourceName, serviceName)
+		return newValidationError("resource '%s' already exists in service '%s'", resourceName, serviceName)
 	}
 
 	return nil
@@ -241,41 +239,44 @@ func collectResourceParameters(blueprint resources.ResourceBlueprint) (map[strin
 
 			switch param.Type {
 			case "select":
-				var selected string
-				prompt := &survey.Select{
+				selected, err := prompter.Select(prompt.Question{
+					Name:    param.Name,
 					Message: fmt.Sprintf("%s:", param.Description),
 					Options: param.Options,
 					Help:    fmt.Sprintf("Select %s for %s", param.Description, blueprint.Name),
-				}
-
-				if err := survey.AskOne(prompt, &selected); err != nil {
+				})
+				if err != nil {
 					return nil, fmt.Errorf("failed to get %s: %w", param.Name, err)
 				}
 				value = selected
 
 			case "string":
-				var input string
-				prompt := &survey.Input{
+				input, err := prompter.Input(prompt.Question{
+					Name:    param.Name,
 					Message: fmt.Sprintf("%s:", param.Description),
 					Help:    fmt.Sprintf("Enter %s for %s", param.Description, blueprint.Name),
-				}
-
-				if err := survey.AskOne(prompt, &input); err != nil {
+				})
+				if err != nil {
 					return nil, fmt.Errorf("failed to get %s: %w", param.Name, err)
 				}
 				value = input
 
 			case "number":
-				var input int
-				prompt := &survey.Input{
+				input, err := prompter.Input(prompt.Question{
+					Name:    param.Name,
 					Message: fmt.Sprintf("%s:", param.Description),
 					Help:    fmt.Sprintf("Enter %s for %s", param.Description, blueprint.Name),
-				}
-
-				if err := survey.AskOne(prompt, &input); err != nil {
+					Validate: func(val string) error {
+						if _, err := strconv.Atoi(val); err != nil {
+							return fmt.Errorf("%s must be a number", param.Description)
+						}
+						return nil
+					},
+				})
+				if err != nil {
 					return nil, fmt.Errorf("failed to get %s: %w", param.Name, err)
 				}
-				value = fmt.Sprintf("%d", input)
+				value = input
 
 			default:
 				return nil, fmt.Errorf("unsupported parameter type: %s", param.Type)
@@ -309,20 +310,10 @@ func addResourceToManifest(manifest *manifestPkg.WorkbenchManifest, serviceName,
 	return nil
 }
 
+// saveWorkbenchManifest writes the manifest back to workbench.yaml, keeping
+// the comments, key order, and anchors of the existing file
 func saveWorkbenchManifest(manifest *manifestPkg.WorkbenchManifest, projectRoot string) error {
-	// Marshal manifest to YAML
-	data, err := yaml.Marshal(manifest)
-	if err != nil {
-		return fmt.Errorf("failed to marshal manifest: %w", err)
-	}
-
-	// Write to workbench.yaml
-	workbenchPath := projectRoot + "/workbench.yaml"
-	if err := os.WriteFile(workbenchPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write workbench.yaml: %w", err)
-	}
-
-	return nil
+	return manifestPkg.Save(workspaceFS, filepath.Join(projectRoot, "workbench.yaml"), manifest)
 }
 
 func printAddResourceSuccessMessage(serviceName, resourceName, resourceType string, blueprint resources.ResourceBlueprint, cfg map[string]string) {
@@ -347,7 +338,7 @@ func printAddResourceSuccessMessage(serviceName, resourceName, resourceType stri
 	// Actionable guidance (concise)
 	fmt.Println("\n🧭 Guidance:")
 	if v := cfg["port"]; v != "" {
-		fmt.Printf("  - Port: %s (host port maps to container)\n", v)
+		fmt.Printf("  - Port: %s (reachable as %s-%s:%s on the compose network; set expose: host to publish it on localhost)\n", v, serviceName, resourceName, v)
 	}
 	if v := cfg["databaseName"]; v != "" {
 		fmt.Printf("  - Database: %s\n", v)
@@ -357,15 +348,15 @@ func printAddResourceSuccessMessage(serviceName, resourceName, resourceType stri
 	}
 	switch strings.ToLower(resourceType) {
 	case "postgres", "postgres-db":
-		fmt.Println("  - Example connection: postgres://<user>:<password>@localhost:<port>/<db>?sslmode=disable")
+		fmt.Println("  - Example connection: postgres://<user>:<password>@<service>-<resource>:<port>/<db>?sslmode=disable")
 		fmt.Println("  - Mount init SQL: ./<service>/init:/docker-entrypoint-initdb.d (optional)")
 	case "mysql", "mysql-db":
-		fmt.Println("  - Example connection: mysql://<user>:<password>@localhost:<port>/<db>")
+		fmt.Println("  - Example connection: mysql://<user>:<password>@<service>-<resource>:<port>/<db>")
 		fmt.Println("  - Mount init SQL: ./<service>/init:/docker-entrypoint-initdb.d (optional)")
 	case "mongodb", "mongo":
-		fmt.Println("  - Example connection: mongodb://<user>:<password>@localhost:<port>/<db>")
+		fmt.Println("  - Example connection: mongodb://<user>:<password>@<service>-<resource>:<port>/<db>")
 	case "redis", "redis-cache":
-		fmt.Println("  - Example connection: redis://:<password>@localhost:<port>")
+		fmt.Println("  - Example connection: redis://:<password>@<service>-<resource>:<port>")
 	}
 	fmt.Println("  - Volume name: <service>_<resource>_data (defined in docker-compose.yml)")
 
@@ -3,22 +3,25 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
-	"github.com/AlecAivazis/survey/v2"
-	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/jashkahar/open-workbench-platform/internal/debuglog"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/hooks"
 	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/policy"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 
 	"github.com/jashkahar/open-workbench-platform/internal/templating"
 )
 
 var addCmd = &cobra.Command{
-	Use:   "add",
-	Short: "Add a new component to your project.",
+	Use:         "add",
+	Short:       "Add a new component to your project.",
+	Annotations: map[string]string{dryRunAnnotation: "true"},
 }
 
 var addServiceCmd = &cobra.Command{
@@ -43,7 +46,27 @@ Examples:
   # Direct mode with minimal parameters (others will be prompted)
   om add service --name backend --template fastapi-basic
 
-Available templates: react-typescript, nextjs-full-stack, fastapi-basic, express-api, vue-nuxt`,
+  # Scaffold into a nested directory; the service is named "backend"
+  om add service --path apps/backend --template fastapi-basic
+
+  # Use a template kept in a git repository, pinned to a tag
+  om add service --name billing --template github.com/acme/om-templates/python-api@v1.2.0
+
+Services are created in a directory named after them at the project root,
+or under the directory set by layout.services in workbench.yaml:
+
+  layout:
+    services: apps
+
+Available templates: react-typescript, nextjs-full-stack, fastapi-basic, express-api, vue-nuxt, node-grpc, go-api, expo-app
+
+--template also accepts a git repository named like a Go module,
+<host>/<owner>/<repo>[/<dir>][@<tag or branch>]; it is cloned into the
+template cache ($OM_TEMPLATE_CACHE) and must hold a template.json.
+
+Templates in ~/.openworkbench/templates and in the directories listed in
+$OM_TEMPLATES_PATH are available too, replacing built-in templates of the
+same name.`,
 	RunE: runAddService,
 }
 
@@ -66,7 +89,7 @@ Examples:
   # Direct mode with all parameters
   om add component --name gateway --template nginx-gateway
 
-Available templates: react-typescript, nextjs-full-stack, fastapi-basic, express-api, vue-nuxt, nginx-gateway, redis-cache`,
+Available templates: react-typescript, nextjs-full-stack, fastapi-basic, express-api, vue-nuxt, nginx-gateway, traefik-gateway, graphql-gateway, redis-cache`,
 	RunE: runAddComponent,
 }
 
@@ -85,13 +108,24 @@ func initAddCommands() {
 
 	// Add flags for the service command (optional for interactive mode)
 	addServiceCmd.Flags().String("name", "", "Service name (optional - will prompt if not provided)")
-	addServiceCmd.Flags().String("template", "", "Template name (optional - will prompt if not provided)")
+	addServiceCmd.Flags().String("template", "", "Template name or git repository, e.g. github.com/org/tpl@v1.2.0 (optional - will prompt if not provided)")
 	addServiceCmd.Flags().StringToString("params", nil, "Template parameters as key=value pairs (e.g., --params IncludeTesting=true,Framework=React)")
+	addServiceCmd.Flags().String("path", "", "Service directory relative to the project root, e.g. apps/backend (default: layout.services/<name>)")
 
 	// Add flags for the component command (optional for interactive mode)
 	addComponentCmd.Flags().String("name", "", "Component name (optional - will prompt if not provided)")
-	addComponentCmd.Flags().String("template", "", "Template name (optional - will prompt if not provided)")
+	addComponentCmd.Flags().String("template", "", "Template name or git repository, e.g. github.com/org/tpl@v1.2.0 (optional - will prompt if not provided)")
 	addComponentCmd.Flags().StringToString("params", nil, "Template parameters as key=value pairs")
+
+	// Record who owns what is added
+	addServiceCmd.Flags().String("team", "", "Team that owns the service, e.g. payments")
+	addServiceCmd.Flags().String("owner", "", "Person responsible for the service, e.g. @jane")
+	addComponentCmd.Flags().String("team", "", "Team that owns the component, e.g. platform")
+	addComponentCmd.Flags().String("owner", "", "Person responsible for the component, e.g. @jane")
+
+	// Ask advanced template questions without the gate
+	addServiceCmd.Flags().BoolVar(&advancedPrompts, "advanced", false, "Ask advanced template questions too")
+	addComponentCmd.Flags().BoolVar(&advancedPrompts, "advanced", false, "Ask advanced template questions too")
 }
 
 func init() {
@@ -104,8 +138,9 @@ func runAddService(cmd *cobra.Command, args []string) error {
 	nameFlag, _ := cmd.Flags().GetString("name")
 	templateFlag, _ := cmd.Flags().GetString("template")
 	paramsFlag, _ := cmd.Flags().GetStringToString("params")
+	pathFlag, _ := cmd.Flags().GetString("path")
 
-	isDirectMode := nameFlag != "" || templateFlag != "" || len(paramsFlag) > 0
+	isDirectMode := nameFlag != "" || templateFlag != "" || len(paramsFlag) > 0 || pathFlag != ""
 
 	if isDirectMode {
 		// Direct mode - use provided parameters
@@ -118,45 +153,82 @@ func runAddService(cmd *cobra.Command, args []string) error {
 
 // runAddServiceInteractive executes the add service command in interactive mode
 func runAddServiceInteractive(cmd *cobra.Command, args []string) error {
+	// Every step of the command shares one view of the templates
+	catalog := templating.NewTemplateCatalog(templatesFS)
+
 	// Step 1: Find project root and load manifest
 	projectRoot, manifest, err := findProjectRootAndLoadManifest()
 	if err != nil {
 		return err
 	}
+	if err := checkWritable(projectRoot); err != nil {
+		return err
+	}
 
 	// Step 2: Prompt for new service details
-	serviceName, templateName, err := promptForNewService()
+	serviceName, templateName, err := promptForNewService(catalog, manifest)
 	if err != nil {
 		return err
 	}
 
-	// Step 3: Perform critical safety checks
-	if err := performSafetyChecks(manifest, projectRoot, serviceName); err != nil {
+	// Step 3: Resolve the service directory and perform critical safety checks
+	serviceDir, err := resolveServicePath(cmd, manifest, serviceName)
+	if err != nil {
+		return err
+	}
+	if err := performSafetyChecks(manifest, projectRoot, serviceName, serviceDir); err != nil {
+		return err
+	}
+	ownership, err := ownershipFlags(cmd, "services."+serviceName)
+	if err != nil {
 		return err
 	}
 
-	// Step 4: Create service directory
-	servicePath := filepath.Join(projectRoot, serviceName)
-	if err := os.MkdirAll(servicePath, 0755); err != nil {
+	// Step 4: Check disk space and path lengths, then create service directory
+	servicePath := filepath.Join(projectRoot, filepath.FromSlash(serviceDir))
+	if err := runScaffoldPreflight(cmd, catalog, templateName, servicePath); err != nil {
+		return err
+	}
+	serviceHookData := map[string]string{"service": serviceName, "template": templateName, "path": serviceDir}
+	if err := runLifecycleHook(cmd, projectRoot, manifest, manifestPkg.HookPreAddService, serviceHookData); err != nil {
+		return err
+	}
+	if err := workspaceFS.MkdirAll(servicePath, 0755); err != nil {
 		return fmt.Errorf("failed to create service directory: %w", err)
 	}
 
 	// Step 5: Run the scaffolder
-	if err := scaffoldService(templateName, servicePath, true, "", ""); err != nil {
+	params, err := scaffoldService(catalog, templateName, servicePath, true, "", "")
+	if err != nil {
 		// Clean up the created directory if scaffolding fails
-		os.RemoveAll(servicePath)
+		workspaceFS.RemoveAll(servicePath)
 		return fmt.Errorf("failed to scaffold service: %w", err)
 	}
 
-	// Step 6: Update workbench.yaml (atomic update)
-	if err := updateWorkbenchManifest(manifest, serviceName, templateName, projectRoot); err != nil {
+	// Step 6: Apply the project's scaffolding policy on top of the template
+	if err := applyScaffoldPolicy(projectRoot, servicePath); err != nil {
+		workspaceFS.RemoveAll(servicePath)
+		return err
+	}
+
+	// Step 7: Update workbench.yaml (atomic update)
+	if err := updateWorkbenchManifest(manifest, serviceName, templateName, serviceDir, projectRoot, ownership); err != nil {
 		// Clean up the created directory if manifest update fails
-		os.RemoveAll(servicePath)
+		workspaceFS.RemoveAll(servicePath)
 		return fmt.Errorf("failed to update workbench.yaml: %w", err)
 	}
 
-	// Step 7: Print success message
-	printAddServiceSuccessMessage(serviceName, templateName)
+	// Step 8: Document the service and print success message
+	writeNewServiceReadme(catalog, projectRoot, manifest, serviceName, templateName, params)
+	printAddServiceSuccessMessage(serviceName, templateName, serviceDir)
+	printTemplateDocsLink(catalog, templateName)
+	refreshCodeOwners(projectRoot, manifest)
+	fireHook(cmd, hooks.EventScaffoldComplete, manifest.Metadata.Name, projectRoot, map[string]string{
+		"kind": "service", "name": serviceName, "template": templateName, "path": serviceDir,
+	})
+	if err := runLifecycleHook(cmd, projectRoot, manifest, manifestPkg.HookPostAddService, serviceHookData); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -168,45 +240,95 @@ func runAddServiceDirect(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if err := checkWritable(projectRoot); err != nil {
+		return err
+	}
+
+	// Every step of the command shares one view of the templates, fetched
+	// first when --template names a git repository
+	catalog, err := templateCatalog(cmd)
+	if err != nil {
+		return err
+	}
 
 	// Step 2: Get parameters from command line flags
-	serviceName, templateName, params, err := getDirectServiceParameters(cmd)
+	serviceName, templateName, params, err := getDirectServiceParameters(cmd, catalog)
 	if err != nil {
 		return err
 	}
 
-	// Step 3: Perform critical safety checks
-	if err := performSafetyChecks(manifest, projectRoot, serviceName); err != nil {
+	// Step 3: Resolve the service directory and perform critical safety checks
+	serviceDir, err := resolveServicePath(cmd, manifest, serviceName)
+	if err != nil {
+		return err
+	}
+	if err := performSafetyChecks(manifest, projectRoot, serviceName, serviceDir); err != nil {
+		return err
+	}
+	ownership, err := ownershipFlags(cmd, "services."+serviceName)
+	if err != nil {
 		return err
 	}
 
-	// Step 4: Validate template and parameters
-	if err := validateTemplateAndParameters(templateName, params); err != nil {
+	// Step 4: Fill in team preset answers and the project defaults, then
+	// validate template and parameters
+	servicePath := filepath.Join(projectRoot, filepath.FromSlash(serviceDir))
+	params, err = applyTeamPreset(catalog, templateName, params)
+	if err != nil {
+		return err
+	}
+	templateManifest, err := catalog.Manifest(templateName)
+	if err != nil {
+		return fmt.Errorf("failed to load template manifest: %w", err)
+	}
+	params = seedProjectParameters(templateManifest, params, servicePath, manifest.Metadata.Name)
+	if err := validateTemplateAndParameters(catalog, templateName, params); err != nil {
 		return err
 	}
 
-	// Step 5: Create service directory
-	servicePath := filepath.Join(projectRoot, serviceName)
-	if err := os.MkdirAll(servicePath, 0755); err != nil {
+	// Step 5: Check disk space and path lengths, then create service directory
+	if err := runScaffoldPreflight(cmd, catalog, templateName, servicePath); err != nil {
+		return err
+	}
+	serviceHookData := map[string]string{"service": serviceName, "template": templateName, "path": serviceDir}
+	if err := runLifecycleHook(cmd, projectRoot, manifest, manifestPkg.HookPreAddService, serviceHookData); err != nil {
+		return err
+	}
+	if err := workspaceFS.MkdirAll(servicePath, 0755); err != nil {
 		return fmt.Errorf("failed to create service directory: %w", err)
 	}
 
 	// Step 6: Run the scaffolder with direct parameters
-	if err := scaffoldServiceDirect(templateName, servicePath, params); err != nil {
+	if err := scaffoldServiceDirect(catalog, templateName, servicePath, params); err != nil {
 		// Clean up the created directory if scaffolding fails
-		os.RemoveAll(servicePath)
+		workspaceFS.RemoveAll(servicePath)
 		return fmt.Errorf("failed to scaffold service: %w", err)
 	}
 
-	// Step 7: Update workbench.yaml (atomic update)
-	if err := updateWorkbenchManifest(manifest, serviceName, templateName, projectRoot); err != nil {
+	// Step 7: Apply the project's scaffolding policy on top of the template
+	if err := applyScaffoldPolicy(projectRoot, servicePath); err != nil {
+		workspaceFS.RemoveAll(servicePath)
+		return err
+	}
+
+	// Step 8: Update workbench.yaml (atomic update)
+	if err := updateWorkbenchManifest(manifest, serviceName, templateName, serviceDir, projectRoot, ownership); err != nil {
 		// Clean up the created directory if manifest update fails
-		os.RemoveAll(servicePath)
+		workspaceFS.RemoveAll(servicePath)
 		return fmt.Errorf("failed to update workbench.yaml: %w", err)
 	}
 
-	// Step 8: Print success message
-	printAddServiceSuccessMessage(serviceName, templateName)
+	// Step 9: Document the service and print success message
+	writeNewServiceReadme(catalog, projectRoot, manifest, serviceName, templateName, params)
+	printAddServiceSuccessMessage(serviceName, templateName, serviceDir)
+	printTemplateDocsLink(catalog, templateName)
+	refreshCodeOwners(projectRoot, manifest)
+	fireHook(cmd, hooks.EventScaffoldComplete, manifest.Metadata.Name, projectRoot, map[string]string{
+		"kind": "service", "name": serviceName, "template": templateName, "path": serviceDir,
+	})
+	if err := runLifecycleHook(cmd, projectRoot, manifest, manifestPkg.HookPostAddService, serviceHookData); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -232,6 +354,15 @@ func runListTemplates(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%d. %s\n", i+1, template.Name)
 		fmt.Printf("   Description: %s\n", template.Description)
 		fmt.Printf("   Template ID: %s\n", template.Name)
+		if notice := template.Manifest.DeprecationNotice(); notice != "" {
+			fmt.Printf("   ⚠️  Status: %s\n", notice)
+		}
+		if template.Manifest != nil && template.Manifest.MinimumCLIVersion != "" {
+			fmt.Printf("   Requires: om %s or newer\n", template.Manifest.MinimumCLIVersion)
+		}
+		if template.Manifest != nil && template.Manifest.DocsURL != "" {
+			fmt.Printf("   Docs: %s\n", template.Manifest.DocsURL)
+		}
 
 		if template.Manifest != nil && len(template.Manifest.Parameters) > 0 {
 			fmt.Printf("   Parameters:\n")
@@ -243,7 +374,7 @@ func runListTemplates(cmd *cobra.Command, args []string) error {
 
 				fmt.Printf("     - %s%s (%s)\n", param.Name, required, param.Type)
 				if param.HelpText != "" {
-					fmt.Printf("       %s\n", param.HelpText)
+					fmt.Print(renderParameterHelp(param.HelpText, "       "))
 				}
 
 				if param.Type == "select" && len(param.Options) > 0 {
@@ -275,6 +406,9 @@ func runListTemplates(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	fmt.Println("# Direct mode with minimal parameters (others will be prompted):")
 	fmt.Println("om add service --name backend --template fastapi-basic")
+	fmt.Println()
+	fmt.Println("# Everything about one template:")
+	fmt.Println("om template describe fastapi-basic")
 
 	return nil
 }
@@ -282,7 +416,7 @@ func runListTemplates(cmd *cobra.Command, args []string) error {
 // findProjectRootAndLoadManifest finds the project root by searching for workbench.yaml
 // and loads the manifest file
 func findProjectRootAndLoadManifest() (string, *manifestPkg.WorkbenchManifest, error) {
-	currentDir, err := os.Getwd()
+	currentDir, err := workingDir()
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to get current directory: %w", err)
 	}
@@ -290,22 +424,29 @@ func findProjectRootAndLoadManifest() (string, *manifestPkg.WorkbenchManifest, e
 	// Search for workbench.yaml in current directory and parent directories
 	projectRoot, err := findWorkbenchYaml(currentDir)
 	if err != nil {
-		return "", nil, fmt.Errorf("could not find workbench.yaml in current or parent directories: %w", err)
+		return "", nil, manifestPkg.NewNotFoundError(currentDir+" or its parent directories", err)
 	}
 
 	// Load and parse the workbench.yaml file
-	manifestPath := filepath.Join(projectRoot, "workbench.yaml")
-	data, err := os.ReadFile(manifestPath)
+	manifest, err := loadManifest(filepath.Join(projectRoot, "workbench.yaml"))
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to read workbench.yaml: %w", err)
+		return "", nil, err
 	}
 
-	var manifest manifestPkg.WorkbenchManifest
-	if err := yaml.Unmarshal(data, &manifest); err != nil {
-		return "", nil, fmt.Errorf("failed to parse workbench.yaml: %w", err)
-	}
+	return projectRoot, manifest, nil
+}
+
+// manifestLoader caches parsed manifests for the current workspace
+var manifestLoader *manifestPkg.Loader
 
-	return projectRoot, &manifest, nil
+// loadManifest loads and validates a workbench.yaml file through the shared
+// loader, creating a new loader whenever the workspace file system changes
+func loadManifest(path string) (*manifestPkg.WorkbenchManifest, error) {
+	if manifestLoader == nil || manifestLoader.FS() != workspaceFS {
+		manifestLoader = manifestPkg.NewLoader(workspaceFS)
+	}
+	debuglog.Printf("loading project manifest %s", path)
+	return manifestLoader.Load(path)
 }
 
 // findWorkbenchYaml searches for workbench.yaml in the given directory and its parents
@@ -314,7 +455,7 @@ func findWorkbenchYaml(startDir string) (string, error) {
 	for {
 		// Check if workbench.yaml exists in current directory
 		manifestPath := filepath.Join(current, "workbench.yaml")
-		if _, err := os.Stat(manifestPath); err == nil {
+		if _, err := workspaceFS.Stat(manifestPath); err == nil {
 			return current, nil
 		}
 
@@ -328,10 +469,41 @@ func findWorkbenchYaml(startDir string) (string, error) {
 	}
 }
 
+// templateOptionLabel returns the label of a template in selection prompts,
+// flagging deprecated templates
+func templateOptionLabel(template templating.TemplateInfo) string {
+	label := fmt.Sprintf("%s - %s", template.Name, template.Description)
+	if notice := template.Manifest.DeprecationNotice(); notice != "" {
+		label += fmt.Sprintf(" (%s)", notice)
+	}
+	return label
+}
+
+// checkTemplateCompatibility refuses a template that requires a newer om and
+// warns when it is deprecated
+func checkTemplateCompatibility(catalog *templating.TemplateCatalog, templateName string) error {
+	manifest, err := catalog.Manifest(templateName)
+	if err != nil {
+		return fmt.Errorf("failed to load template manifest: %w", err)
+	}
+	return checkManifestCompatibility(templateName, manifest)
+}
+
+// checkManifestCompatibility is checkTemplateCompatibility for a loaded manifest
+func checkManifestCompatibility(templateName string, manifest *templating.TemplateManifest) error {
+	if err := templating.CheckCLIVersion(templateName, manifest, Version); err != nil {
+		return newValidationError("%w", err)
+	}
+	if notice := manifest.DeprecationNotice(); notice != "" {
+		fmt.Printf("⚠️  Template '%s' is %s.\n", templateName, notice)
+	}
+	return nil
+}
+
 // promptForNewService prompts the user for the new service details
-func promptForNewService() (string, string, error) {
+func promptForNewService(catalog *templating.TemplateCatalog, manifest *manifestPkg.WorkbenchManifest) (string, string, error) {
 	// Discover available templates
-	templates, err := templating.DiscoverTemplates(templatesFS)
+	templates, err := catalog.Templates()
 	if err != nil {
 		return "", "", fmt.Errorf("could not discover templates: %w", err)
 	}
@@ -344,23 +516,18 @@ func promptForNewService() (string, string, error) {
 	var templateOptions []string
 	templateMap := make(map[string]string)
 	for _, template := range templates {
-		templateOptions = append(templateOptions, fmt.Sprintf("%s - %s", template.Name, template.Description))
-		templateMap[fmt.Sprintf("%s - %s", template.Name, template.Description)] = template.Name
+		templateOptions = append(templateOptions, templateOptionLabel(template))
+		templateMap[templateOptionLabel(template)] = template.Name
 	}
 
 	// Prompt for template selection
-	var selectedTemplateOption string
-	templateQuestion := &survey.Select{
+	selectedTemplateOption, err := prompter.Select(prompt.Question{
+		Name:    "template",
 		Message: "Choose a template for your new service:",
 		Options: templateOptions,
 		Help:    "This will be used to scaffold your new service",
-	}
-	err = survey.AskOne(templateQuestion, &selectedTemplateOption)
+	})
 	if err != nil {
-		if errors.Is(err, terminal.InterruptErr) {
-			fmt.Println("\nOperation cancelled.")
-			os.Exit(0)
-		}
 		return "", "", fmt.Errorf("could not select template: %w", err)
 	}
 
@@ -370,39 +537,42 @@ func promptForNewService() (string, string, error) {
 	if err := ValidateTemplateName(selectedTemplate); err != nil {
 		return "", "", fmt.Errorf("invalid template name: %w", err)
 	}
+	if err := checkTemplateCompatibility(catalog, selectedTemplate); err != nil {
+		return "", "", err
+	}
 
 	// Prompt for service name
-	var serviceName string
-	servicePrompt := &survey.Input{
-		Message: "What is your service name?",
-		Default: "backend",
-		Help:    "This will be used as the service directory name",
-	}
-	err = survey.AskOne(servicePrompt, &serviceName, survey.WithValidator(survey.Required))
+	serviceName, err := prompter.Input(prompt.Question{
+		Name:     "serviceName",
+		Message:  "What is your service name?",
+		Default:  "backend",
+		Help:     "This will be used as the service directory name",
+		Required: true,
+	})
 	if err != nil {
-		if errors.Is(err, terminal.InterruptErr) {
-			fmt.Println("\nOperation cancelled.")
-			os.Exit(0)
-		}
 		return "", "", fmt.Errorf("could not get service name: %w", err)
 	}
 
-	// Validate and sanitize service name
-	sanitizedServiceName, err := ValidateAndSanitizeName(serviceName, nil)
+	// Turn the answer into a valid service name
+	sanitizedServiceName, err := nameFromAnswer("service", serviceName, serviceNameTaken(manifest))
 	if err != nil {
 		return "", "", err
 	}
 
-	// Check for suspicious patterns
-	if err := CheckForSuspiciousPatterns(sanitizedServiceName); err != nil {
-		return "", "", err
-	}
-
 	return sanitizedServiceName, selectedTemplate, nil
 }
 
+// serviceNameTaken reports whether a service of the manifest already has a
+// name
+func serviceNameTaken(manifest *manifestPkg.WorkbenchManifest) func(string) bool {
+	return func(name string) bool {
+		_, exists := manifest.Services[name]
+		return exists
+	}
+}
+
 // getDirectServiceParameters extracts parameters from command line flags
-func getDirectServiceParameters(cmd *cobra.Command) (string, string, map[string]interface{}, error) {
+func getDirectServiceParameters(cmd *cobra.Command, catalog *templating.TemplateCatalog) (string, string, map[string]interface{}, error) {
 	serviceName, err := cmd.Flags().GetString("name")
 	if err != nil {
 		return "", "", nil, fmt.Errorf("failed to get service name: %w", err)
@@ -419,40 +589,28 @@ func getDirectServiceParameters(cmd *cobra.Command) (string, string, map[string]
 		return "", "", nil, fmt.Errorf("failed to get parameters: %w", err)
 	}
 
-	// Convert string parameters to interface{} map
-	params := make(map[string]interface{})
-	for key, value := range paramStrings {
-		// Try to convert to appropriate type
-		if value == "true" || value == "false" {
-			params[key] = value == "true"
-		} else if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
-			// Handle array values (e.g., "[item1,item2]")
-			items := strings.Trim(value, "[]")
-			if items == "" {
-				params[key] = []string{}
-			} else {
-				params[key] = strings.Split(items, ",")
-			}
-		} else {
-			params[key] = value
-		}
+	params := parseParamsFlag(paramStrings)
+
+	// A service scaffolded into a nested path is named after its directory
+	if pathFlag, _ := cmd.Flags().GetString("path"); serviceName == "" && pathFlag != "" {
+		serviceName = path.Base(filepath.ToSlash(filepath.Clean(pathFlag)))
 	}
 
 	// If service name is not provided, prompt for it
 	if serviceName == "" {
-		servicePrompt := &survey.Input{
-			Message: "What is your service name?",
-			Default: "backend",
-			Help:    "This will be used as the service directory name",
-		}
-		err = survey.AskOne(servicePrompt, &serviceName, survey.WithValidator(survey.Required))
+		serviceName, err = prompter.Input(prompt.Question{
+			Name:     "serviceName",
+			Message:  "What is your service name?",
+			Default:  "backend",
+			Help:     "This will be used as the service directory name",
+			Required: true,
+		})
 		if err != nil {
-			if errors.Is(err, terminal.InterruptErr) {
-				fmt.Println("\nOperation cancelled.")
-				os.Exit(0)
-			}
 			return "", "", nil, fmt.Errorf("could not get service name: %w", err)
 		}
+		if serviceName, err = nameFromAnswer("service", serviceName, nil); err != nil {
+			return "", "", nil, err
+		}
 	}
 
 	// Validate and sanitize service name
@@ -469,7 +627,7 @@ func getDirectServiceParameters(cmd *cobra.Command) (string, string, map[string]
 	// If template name is not provided, prompt for it
 	if templateName == "" {
 		// Discover available templates
-		templates, err := templating.DiscoverTemplates(templatesFS)
+		templates, err := catalog.Templates()
 		if err != nil {
 			return "", "", nil, fmt.Errorf("could not discover templates: %w", err)
 		}
@@ -482,23 +640,18 @@ func getDirectServiceParameters(cmd *cobra.Command) (string, string, map[string]
 		var templateOptions []string
 		templateMap := make(map[string]string)
 		for _, template := range templates {
-			templateOptions = append(templateOptions, fmt.Sprintf("%s - %s", template.Name, template.Description))
-			templateMap[fmt.Sprintf("%s - %s", template.Name, template.Description)] = template.Name
+			templateOptions = append(templateOptions, templateOptionLabel(template))
+			templateMap[templateOptionLabel(template)] = template.Name
 		}
 
 		// Prompt for template selection
-		var selectedTemplateOption string
-		templateQuestion := &survey.Select{
+		selectedTemplateOption, err := prompter.Select(prompt.Question{
+			Name:    "template",
 			Message: "Choose a template for your new service:",
 			Options: templateOptions,
 			Help:    "This will be used to scaffold your new service",
-		}
-		err = survey.AskOne(templateQuestion, &selectedTemplateOption)
+		})
 		if err != nil {
-			if errors.Is(err, terminal.InterruptErr) {
-				fmt.Println("\nOperation cancelled.")
-				os.Exit(0)
-			}
 			return "", "", nil, fmt.Errorf("could not select template: %w", err)
 		}
 
@@ -513,13 +666,40 @@ func getDirectServiceParameters(cmd *cobra.Command) (string, string, map[string]
 	return sanitizedServiceName, templateName, params, nil
 }
 
+// parseParamsFlag converts the key=value pairs of --params into template
+// answers: "true" and "false" become booleans, "[a,b]" becomes a list, and
+// everything else stays a string
+func parseParamsFlag(paramStrings map[string]string) map[string]interface{} {
+	params := make(map[string]interface{})
+	for key, value := range paramStrings {
+		// Try to convert to appropriate type
+		if value == "true" || value == "false" {
+			params[key] = value == "true"
+		} else if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+			// Handle array values (e.g., "[item1,item2]")
+			items := strings.Trim(value, "[]")
+			if items == "" {
+				params[key] = []string{}
+			} else {
+				params[key] = strings.Split(items, ",")
+			}
+		} else {
+			params[key] = value
+		}
+	}
+	return params
+}
+
 // validateTemplateAndParameters validates the template and its parameters
-func validateTemplateAndParameters(templateName string, params map[string]interface{}) error {
+func validateTemplateAndParameters(catalog *templating.TemplateCatalog, templateName string, params map[string]interface{}) error {
 	// Load template manifest to validate parameters
-	manifest, err := templating.LoadTemplateManifest(templatesFS, templateName)
+	manifest, err := catalog.Manifest(templateName)
 	if err != nil {
 		return fmt.Errorf("failed to load template manifest: %w", err)
 	}
+	if err := checkManifestCompatibility(templateName, manifest); err != nil {
+		return err
+	}
 
 	// Create parameter processor to validate parameters
 	processor := templating.NewParameterProcessor(manifest)
@@ -538,7 +718,7 @@ func validateTemplateAndParameters(templateName string, params map[string]interf
 		}
 
 		if !found {
-			return fmt.Errorf("unknown parameter: %s", paramName)
+			return newValidationError("unknown parameter: %s", paramName)
 		}
 
 		// Validate the parameter value
@@ -554,7 +734,7 @@ func validateTemplateAndParameters(templateName string, params map[string]interf
 	for _, param := range manifest.Parameters {
 		if param.Required {
 			if _, exists := params[param.Name]; !exists {
-				return fmt.Errorf("required parameter missing: %s", param.Name)
+				return newValidationError("required parameter missing: %s", param.Name)
 			}
 		}
 	}
@@ -563,68 +743,175 @@ func validateTemplateAndParameters(templateName string, params map[string]interf
 }
 
 // scaffoldServiceDirect scaffolds a service with direct parameter specification
-func scaffoldServiceDirect(templateName, servicePath string, params map[string]interface{}) error {
+func scaffoldServiceDirect(catalog *templating.TemplateCatalog, templateName, servicePath string, params map[string]interface{}) error {
+	processor, err := scaffoldServiceFiles(catalog, templateName, servicePath, params)
+	if err != nil {
+		return err
+	}
+
+	// Execute post-scaffold commands
+	if err := processor.ExecutePostScaffoldCommands(servicePath); err != nil {
+		return fmt.Errorf("failed to execute post-scaffold actions: %w", err)
+	}
+
+	return nil
+}
+
+// scaffoldServiceFiles scaffolds the files of a service with direct
+// parameter specification and returns the processor, whose post-scaffold
+// commands are left for the caller to run
+func scaffoldServiceFiles(catalog *templating.TemplateCatalog, templateName, servicePath string, params map[string]interface{}) (*templating.TemplateProcessor, error) {
 	// Load template manifest
-	manifest, err := templating.LoadTemplateManifest(templatesFS, templateName)
+	manifest, err := catalog.Manifest(templateName)
 	if err != nil {
-		return fmt.Errorf("failed to load template manifest: %w", err)
+		return nil, fmt.Errorf("failed to load template manifest: %w", err)
 	}
 
 	// Create template processor with the provided parameters
 	processor := templating.NewTemplateProcessor(manifest, params, false)
+	processor.SetFileSystem(workspaceFS)
+	processor.SetCommandEnvironment(packageCacheEnvironment())
 
 	// Scaffold the project
-	if err := processor.ScaffoldProject(templatesFS, templateName, servicePath); err != nil {
-		return fmt.Errorf("failed to scaffold project: %w", err)
+	if err := processor.ScaffoldProject(catalog.FS(), templateName, servicePath); err != nil {
+		return nil, fmt.Errorf("failed to scaffold project: %w", err)
 	}
 
-	// Execute post-scaffold actions
-	if err := processor.ExecutePostScaffoldActions(servicePath); err != nil {
-		return fmt.Errorf("failed to execute post-scaffold actions: %w", err)
+	// Delete the files the template drops for these parameters
+	if err := processor.ExecutePostScaffoldDeletions(servicePath); err != nil {
+		return nil, fmt.Errorf("failed to execute post-scaffold actions: %w", err)
 	}
 
+	return processor, nil
+}
+
+// applyScaffoldPolicy applies .om/policy.yaml, when the project has one, to a
+// newly scaffolded service
+func applyScaffoldPolicy(projectRoot, servicePath string) error {
+	scaffoldPolicy, err := policy.Load(workspaceFS, projectRoot)
+	if err != nil {
+		return newValidationError("%w", err)
+	}
+	if scaffoldPolicy == nil {
+		return nil
+	}
+
+	changed, err := scaffoldPolicy.Apply(workspaceFS, projectRoot, servicePath)
+	if err != nil {
+		return fmt.Errorf("failed to apply %s: %w", policy.FileName, err)
+	}
+	if len(changed) > 0 {
+		fmt.Printf("📐 Applied %s to %d file(s)\n", filepath.ToSlash(policy.FileName), len(changed))
+	}
 	return nil
 }
 
+// resolveServicePath returns the directory of a new service relative to the
+// project root, with forward slashes: the --path flag when set, otherwise the
+// location the project's layout gives the service name
+func resolveServicePath(cmd *cobra.Command, manifest *manifestPkg.WorkbenchManifest, serviceName string) (string, error) {
+	pathFlag, _ := cmd.Flags().GetString("path")
+	if pathFlag == "" {
+		return manifest.ServicePath(serviceName), nil
+	}
+
+	cleanPath, err := ValidateAndSanitizePath(pathFlag, nil)
+	if err != nil {
+		return "", err
+	}
+	if !manifestPkg.IsProjectPath(cleanPath) {
+		return "", newValidationError("service path '%s' must be inside the project", pathFlag)
+	}
+	serviceDir := filepath.ToSlash(cleanPath)
+	for _, segment := range strings.Split(serviceDir, "/") {
+		if _, err := ValidateAndSanitizeName(segment, nil); err != nil {
+			return "", newValidationError("invalid service path '%s': %v", pathFlag, err)
+		}
+	}
+	return serviceDir, nil
+}
+
+// ownershipFlags returns the owner and team given with --owner and --team
+// for the entry at field, such as services.api
+func ownershipFlags(cmd *cobra.Command, field string) (manifestPkg.Ownership, error) {
+	owner, _ := cmd.Flags().GetString("owner")
+	team, _ := cmd.Flags().GetString("team")
+	ownership := manifestPkg.Ownership{Owner: owner, Team: team}
+	if err := ownership.Validate(field); err != nil {
+		return manifestPkg.Ownership{}, err
+	}
+	return ownership, nil
+}
+
 // performSafetyChecks performs critical safety checks before adding the service
-func performSafetyChecks(manifest *manifestPkg.WorkbenchManifest, projectRoot, serviceName string) error {
+func performSafetyChecks(manifest *manifestPkg.WorkbenchManifest, projectRoot, serviceName, serviceDir string) error {
 	// Check if service already exists in manifest
 	if _, exists := manifest.Services[serviceName]; exists {
-		return fmt.Errorf("error: a service named '%s' already exists in your project", serviceName)
+		return newValidationError("error: a service named '%s' already exists in your project", serviceName)
 	}
 
 	// Check if directory already exists on filesystem
-	servicePath := filepath.Join(projectRoot, serviceName)
-	if _, err := os.Stat(servicePath); err == nil {
-		return fmt.Errorf("error: a directory named '%s' already exists", serviceName)
+	servicePath := filepath.Join(projectRoot, filepath.FromSlash(serviceDir))
+	if _, err := workspaceFS.Stat(servicePath); err == nil {
+		return newValidationError("error: a directory named '%s' already exists", serviceDir)
 	}
 
-	return nil
+	// Services cannot live inside another service's or component's directory,
+	// where they would end up in its build context
+	owners := make(map[string]string)
+	for name, service := range manifest.Services {
+		owners[service.Path] = fmt.Sprintf("service '%s'", name)
+	}
+	for name, component := range manifest.Components {
+		owners[component.Path] = fmt.Sprintf("component '%s'", name)
+	}
+	for ownerPath, owner := range owners {
+		if ownerPath == "" {
+			continue
+		}
+		ownerDir := path.Clean(filepath.ToSlash(ownerPath))
+		if ownerDir != "." && strings.HasPrefix(serviceDir+"/", ownerDir+"/") {
+			return newValidationError("error: '%s' is inside the directory of %s", serviceDir, owner)
+		}
+	}
+
+	return checkScaffoldTarget(projectRoot, serviceDir)
 }
 
-// updateWorkbenchManifest updates the workbench.yaml file with the new service
-func updateWorkbenchManifest(manifest *manifestPkg.WorkbenchManifest, serviceName, templateName, projectRoot string) error {
-	// Add the new service to the manifest
-	manifest.Services[serviceName] = manifestPkg.Service{
-		Template: templateName,
-		Path:     filepath.Join(".", serviceName),
-		Port:     defaultServicePort(templateName),
+// checkScaffoldTarget refuses to scaffold into dir, relative to the project
+// root, when it is a directory om generates, whose contents the next om
+// compose replaces, or inside another project nested in this one
+func checkScaffoldTarget(projectRoot, dir string) error {
+	dir = path.Clean(filepath.ToSlash(dir))
+	top, _, _ := strings.Cut(dir, "/")
+	for _, artifact := range generatedArtifacts {
+		if top == artifact {
+			return newValidationError("error: '%s' is inside %s, which om generates and replaces; choose a directory outside it", dir, artifact)
+		}
 	}
 
-	// Marshal to YAML
-	data, err := yaml.Marshal(manifest)
-	if err != nil {
-		return fmt.Errorf("failed to marshal manifest: %w", err)
+	for parent := path.Dir(dir); parent != "."; parent = path.Dir(parent) {
+		if filesystem.Exists(workspaceFS, filepath.Join(projectRoot, filepath.FromSlash(parent), "workbench.yaml")) {
+			return newValidationError("error: '%s' is inside the project in %s, which has its own workbench.yaml; run om add from there, or choose a directory outside it", dir, parent)
+		}
 	}
+	return nil
+}
 
-	// Write to file
-	manifestPath := filepath.Join(projectRoot, "workbench.yaml")
-	err = os.WriteFile(manifestPath, data, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write workbench.yaml: %w", err)
+// updateWorkbenchManifest updates the workbench.yaml file with the new service
+func updateWorkbenchManifest(manifest *manifestPkg.WorkbenchManifest, serviceName, templateName, serviceDir, projectRoot string, ownership manifestPkg.Ownership) error {
+	// Add the new service to the manifest
+	manifest.Services[serviceName] = manifestPkg.Service{
+		Ownership: ownership,
+		Template:  templateName,
+		Path:      serviceDir,
+		Port:      defaultServicePort(templateName),
+		Protocol:  defaultServiceProtocol(templateName),
+		Kind:      defaultServiceKind(templateName),
+		Dev:       defaultDevCommand(templateName),
 	}
 
-	return nil
+	return saveWorkbenchManifest(manifest, projectRoot)
 }
 
 // defaultServicePort returns a sensible external/internal port for well-known templates
@@ -640,25 +927,72 @@ func defaultServicePort(templateName string) int {
 		return 8000
 	case "vue-nuxt":
 		return 3000
+	case "node-grpc":
+		return 50051
+	case "go-api":
+		return 8080
+	case "expo-app":
+		return 8081
 	default:
 		return 0
 	}
 }
 
+// defaultServiceKind returns the kind of well-known templates that do not run in a container
+func defaultServiceKind(templateName string) string {
+	switch strings.ToLower(templateName) {
+	case "expo-app":
+		return manifestPkg.ServiceKindLocal
+	default:
+		return ""
+	}
+}
+
+// defaultDevCommand returns the command that starts a host-run service from a well-known template
+func defaultDevCommand(templateName string) string {
+	switch strings.ToLower(templateName) {
+	case "expo-app":
+		return "npx expo start"
+	default:
+		return ""
+	}
+}
+
+// defaultServiceProtocol returns the protocol of well-known templates that do not speak HTTP
+func defaultServiceProtocol(templateName string) string {
+	switch strings.ToLower(templateName) {
+	case "node-grpc":
+		return manifestPkg.ProtocolGRPC
+	default:
+		return ""
+	}
+}
+
 // printAddServiceSuccessMessage prints a success message for adding a service
-func printAddServiceSuccessMessage(serviceName, templateName string) {
+func printAddServiceSuccessMessage(serviceName, templateName, serviceDir string) {
 	fmt.Println("------------------------------------")
 	fmt.Printf("✅ Success! Service '%s' has been added to your project.\n", serviceName)
 	fmt.Println()
 	fmt.Printf("📁 Service details:\n")
 	fmt.Printf("  Template: %s\n", templateName)
-	fmt.Printf("  Path: ./%s\n", serviceName)
+	fmt.Printf("  Path: ./%s\n", serviceDir)
 	fmt.Println()
 	fmt.Println("🚀 Next steps:")
-	fmt.Printf("  cd %s\n", serviceName)
+	fmt.Printf("  cd %s\n", serviceDir)
 	fmt.Println("  om add service  # Add more services to your project")
 }
 
+// printTemplateDocsLink points to the documentation of the template a
+// service or component was scaffolded from, when the template has any
+func printTemplateDocsLink(catalog *templating.TemplateCatalog, templateName string) {
+	manifest, err := catalog.Manifest(templateName)
+	if err != nil || manifest.DocsURL == "" {
+		return
+	}
+	fmt.Println()
+	fmt.Printf("📖 Learn more about %s: %s\n", templateName, manifest.DocsURL)
+}
+
 // runAddComponent executes the add component command logic - smart mode detection
 func runAddComponent(cmd *cobra.Command, args []string) error {
 	// Check if we're in direct mode (parameters provided)
@@ -679,14 +1013,20 @@ func runAddComponent(cmd *cobra.Command, args []string) error {
 
 // runAddComponentInteractive executes the add component command in interactive mode
 func runAddComponentInteractive(cmd *cobra.Command, args []string) error {
+	// Every step of the command shares one view of the templates
+	catalog := templating.NewTemplateCatalog(templatesFS)
+
 	// Step 1: Find project root and load manifest
 	projectRoot, manifest, err := findProjectRootAndLoadManifest()
 	if err != nil {
 		return err
 	}
+	if err := checkWritable(projectRoot); err != nil {
+		return err
+	}
 
 	// Step 2: Prompt for new component details
-	componentName, templateName, err := promptForNewComponent()
+	componentName, templateName, err := promptForNewComponent(catalog, manifest)
 	if err != nil {
 		return err
 	}
@@ -695,26 +1035,51 @@ func runAddComponentInteractive(cmd *cobra.Command, args []string) error {
 	if err := performComponentSafetyChecks(manifest, projectRoot, componentName); err != nil {
 		return err
 	}
+	ownership, err := ownershipFlags(cmd, "components."+componentName)
+	if err != nil {
+		return err
+	}
 
 	// Step 4: Collect template parameters
-	params, err := collectTemplateParameters(templateName, false, manifest.Metadata.Name, "Open Workbench")
+	params, err := collectTemplateParameters(catalog, templateName, filepath.Join(projectRoot, componentName), false, manifest.Metadata.Name, "Open Workbench")
 	if err != nil {
 		return err
 	}
 
-	// Step 5: Scaffold the component
+	// Step 5: Check disk space and path lengths, then scaffold the component
 	componentPath := filepath.Join(projectRoot, componentName)
-	if err := scaffoldComponentDirect(templateName, componentPath, params); err != nil {
+	if err := runScaffoldPreflight(cmd, catalog, templateName, componentPath); err != nil {
+		return err
+	}
+	componentHookData := map[string]string{"component": componentName, "template": templateName, "path": componentName}
+	if err := runLifecycleHook(cmd, projectRoot, manifest, manifestPkg.HookPreAddComponent, componentHookData); err != nil {
+		return err
+	}
+	if err := scaffoldComponentDirect(catalog, templateName, componentPath, params); err != nil {
+		// Clean up the partially scaffolded component if scaffolding fails
+		workspaceFS.RemoveAll(componentPath)
 		return err
 	}
 
 	// Step 6: Update workbench.yaml (atomic update)
-	if err := updateWorkbenchManifestForComponent(manifest, componentName, templateName, projectRoot); err != nil {
+	if err := updateWorkbenchManifestForComponent(manifest, componentName, templateName, projectRoot, ownership); err != nil {
+		// Clean up the created directory if manifest update fails
+		workspaceFS.RemoveAll(componentPath)
 		return fmt.Errorf("failed to update workbench.yaml: %w", err)
 	}
 
 	// Step 7: Print success message
 	printAddComponentSuccessMessage(componentName, templateName)
+	printTemplateDocsLink(catalog, templateName)
+	refreshCodeOwners(projectRoot, manifest)
+	fireHook(cmd, hooks.EventScaffoldComplete, manifest.Metadata.Name, projectRoot, map[string]string{
+		"kind": "component", "name": componentName, "template": templateName, "path": componentName,
+	})
+	if err := runLifecycleHook(cmd, projectRoot, manifest, manifestPkg.HookPostAddComponent, componentHookData); err != nil {
+		return err
+	}
+	offerDecisionRecord(projectRoot, fmt.Sprintf("Add %s component", componentName),
+		fmt.Sprintf("Added the '%s' component from the %s template.", componentName, templateName))
 
 	return nil
 }
@@ -726,6 +1091,16 @@ func runAddComponentDirect(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if err := checkWritable(projectRoot); err != nil {
+		return err
+	}
+
+	// Every step of the command shares one view of the templates, fetched
+	// first when --template names a git repository
+	catalog, err := templateCatalog(cmd)
+	if err != nil {
+		return err
+	}
 
 	// Step 2: Get parameters from command line
 	componentName, templateName, params, err := getDirectComponentParameters(cmd)
@@ -737,36 +1112,71 @@ func runAddComponentDirect(cmd *cobra.Command, args []string) error {
 	if err := performComponentSafetyChecks(manifest, projectRoot, componentName); err != nil {
 		return err
 	}
-
-	// Step 4: Validate template and parameters
-	if err := validateTemplateAndParameters(templateName, params); err != nil {
+	ownership, err := ownershipFlags(cmd, "components."+componentName)
+	if err != nil {
 		return err
 	}
 
-	// Step 5: Scaffold the component
+	// Step 4: Fill in team preset answers and the project defaults, then
+	// validate template and parameters
 	componentPath := filepath.Join(projectRoot, componentName)
-	if err := scaffoldComponentDirect(templateName, componentPath, params); err != nil {
+	params, err = applyTeamPreset(catalog, templateName, params)
+	if err != nil {
+		return err
+	}
+	templateManifest, err := catalog.Manifest(templateName)
+	if err != nil {
+		return fmt.Errorf("failed to load template manifest: %w", err)
+	}
+	params = seedProjectParameters(templateManifest, params, componentPath, manifest.Metadata.Name)
+	if err := validateTemplateAndParameters(catalog, templateName, params); err != nil {
+		return err
+	}
+
+	// Step 5: Check disk space and path lengths, then scaffold the component
+	if err := runScaffoldPreflight(cmd, catalog, templateName, componentPath); err != nil {
+		return err
+	}
+	componentHookData := map[string]string{"component": componentName, "template": templateName, "path": componentName}
+	if err := runLifecycleHook(cmd, projectRoot, manifest, manifestPkg.HookPreAddComponent, componentHookData); err != nil {
+		return err
+	}
+	if err := scaffoldComponentDirect(catalog, templateName, componentPath, params); err != nil {
+		// Clean up the partially scaffolded component if scaffolding fails
+		workspaceFS.RemoveAll(componentPath)
 		return err
 	}
 
 	// Step 6: Update workbench.yaml (atomic update)
-	if err := updateWorkbenchManifestForComponent(manifest, componentName, templateName, projectRoot); err != nil {
+	if err := updateWorkbenchManifestForComponent(manifest, componentName, templateName, projectRoot, ownership); err != nil {
+		// Clean up the created directory if manifest update fails
+		workspaceFS.RemoveAll(componentPath)
 		return fmt.Errorf("failed to update workbench.yaml: %w", err)
 	}
 
 	// Step 7: Print success message
 	printAddComponentSuccessMessage(componentName, templateName)
+	printTemplateDocsLink(catalog, templateName)
+	refreshCodeOwners(projectRoot, manifest)
+	fireHook(cmd, hooks.EventScaffoldComplete, manifest.Metadata.Name, projectRoot, map[string]string{
+		"kind": "component", "name": componentName, "template": templateName, "path": componentName,
+	})
+	if err := runLifecycleHook(cmd, projectRoot, manifest, manifestPkg.HookPostAddComponent, componentHookData); err != nil {
+		return err
+	}
+	offerDecisionRecord(projectRoot, fmt.Sprintf("Add %s component", componentName),
+		fmt.Sprintf("Added the '%s' component from the %s template.", componentName, templateName))
 
 	return nil
 }
 
 // promptForNewComponent prompts for component details
-func promptForNewComponent() (string, string, error) {
+func promptForNewComponent(catalog *templating.TemplateCatalog, manifest *manifestPkg.WorkbenchManifest) (string, string, error) {
 	var componentName string
 	var templateName string
 
 	// Step 1: Discover and select component template first
-	templates, err := templating.DiscoverTemplates(templatesFS)
+	templates, err := catalog.Templates()
 	if err != nil {
 		return "", "", fmt.Errorf("could not discover templates: %w", err)
 	}
@@ -797,43 +1207,42 @@ func promptForNewComponent() (string, string, error) {
 	var templateOptions []string
 	templateMap := make(map[string]string)
 	for _, template := range componentTemplates {
-		templateOptions = append(templateOptions, fmt.Sprintf("%s - %s", template.Name, template.Description))
-		templateMap[fmt.Sprintf("%s - %s", template.Name, template.Description)] = template.Name
+		templateOptions = append(templateOptions, templateOptionLabel(template))
+		templateMap[templateOptionLabel(template)] = template.Name
 	}
 
 	// Prompt for template selection first
-	var selectedTemplateOption string
-	templateQuestion := &survey.Select{
+	selectedTemplateOption, err := prompter.Select(prompt.Question{
+		Name:    "template",
 		Message: "Choose a component template:",
 		Options: templateOptions,
 		Help:    "Select a template that matches your component type",
-	}
-	err = survey.AskOne(templateQuestion, &selectedTemplateOption)
+	})
 	if err != nil {
-		if errors.Is(err, terminal.InterruptErr) {
-			fmt.Println("\nOperation cancelled.")
-			os.Exit(0)
-		}
 		return "", "", fmt.Errorf("could not select template: %w", err)
 	}
 
 	templateName = templateMap[selectedTemplateOption]
+	if err := checkTemplateCompatibility(catalog, templateName); err != nil {
+		return "", "", err
+	}
 
 	// Step 2: Prompt for component name after template selection
-	err = survey.AskOne(&survey.Input{
-		Message: "What is your component name?",
-		Help:    "This will be used as the directory name and in the workbench.yaml manifest",
-	}, &componentName, survey.WithValidator(func(val interface{}) error {
-		if str, ok := val.(string); ok {
-			if str == "" {
-				return errors.New("component name cannot be empty")
-			}
-			if !isValidProjectName(str) {
-				return errors.New("component name must contain only lowercase letters, numbers, and hyphens")
-			}
-		}
-		return nil
-	}))
+	componentName, err = prompter.Input(prompt.Question{
+		Name:     "componentName",
+		Message:  "What is your component name?",
+		Help:     "This will be used as the directory name and in the workbench.yaml manifest",
+		Required: true,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	// Turn the answer into a valid component name
+	componentName, err = nameFromAnswer("component", componentName, func(name string) bool {
+		_, exists := manifest.Components[name]
+		return exists
+	})
 	if err != nil {
 		return "", "", err
 	}
@@ -868,50 +1277,39 @@ func getDirectComponentParameters(cmd *cobra.Command) (string, string, map[strin
 func performComponentSafetyChecks(manifest *manifestPkg.WorkbenchManifest, projectRoot, componentName string) error {
 	// Check if component already exists
 	if _, exists := manifest.Components[componentName]; exists {
-		return fmt.Errorf("component '%s' already exists in workbench.yaml", componentName)
+		return newValidationError("component '%s' already exists in workbench.yaml", componentName)
 	}
 
 	// Check if directory already exists
 	componentPath := filepath.Join(projectRoot, componentName)
-	if _, err := os.Stat(componentPath); err == nil {
-		return fmt.Errorf("directory '%s' already exists", componentName)
+	if _, err := workspaceFS.Stat(componentPath); err == nil {
+		return newValidationError("directory '%s' already exists", componentName)
 	}
 
-	return nil
+	return checkScaffoldTarget(projectRoot, componentName)
 }
 
 // scaffoldComponent scaffolds a component using the template system
-func scaffoldComponent(templateName, componentPath string, isAddComponent bool, existingProjectName string, existingOwner string) error {
-	// Discover available templates
-	templates, err := templating.DiscoverTemplates(templatesFS)
+func scaffoldComponent(catalog *templating.TemplateCatalog, templateName, componentPath string, isAddComponent bool, existingProjectName string, existingOwner string) error {
+	// Look up the template
+	templateInfo, err := catalog.Info(templateName)
 	if err != nil {
-		return fmt.Errorf("could not discover templates: %w", err)
-	}
-
-	// Find the specific template
-	var templateInfo *templating.TemplateInfo
-	for _, template := range templates {
-		if template.Name == templateName {
-			templateInfo = &template
-			break
-		}
-	}
-
-	if templateInfo == nil {
-		return fmt.Errorf("template '%s' not found", templateName)
+		return newNotFoundError("template '%s' not found", templateName)
 	}
 
 	// Collect parameters
-	parameterValues, err := collectTemplateParameters(templateName, isAddComponent, existingProjectName, existingOwner)
+	parameterValues, err := collectTemplateParameters(catalog, templateName, componentPath, isAddComponent, existingProjectName, existingOwner)
 	if err != nil {
 		return fmt.Errorf("failed to collect parameters: %w", err)
 	}
 
 	// Create a template processor
 	processor := templating.NewTemplateProcessor(templateInfo.Manifest, parameterValues, false)
+	processor.SetFileSystem(workspaceFS)
+	processor.SetCommandEnvironment(packageCacheEnvironment())
 
 	// Execute the scaffolding process
-	err = processor.ScaffoldProject(templatesFS, templateName, componentPath)
+	err = processor.ScaffoldProject(catalog.FS(), templateName, componentPath)
 	if err != nil {
 		return fmt.Errorf("failed to scaffold component: %w", err)
 	}
@@ -926,31 +1324,20 @@ func scaffoldComponent(templateName, componentPath string, isAddComponent bool,
 }
 
 // scaffoldComponentDirect scaffolds a component with direct parameters
-func scaffoldComponentDirect(templateName, componentPath string, params map[string]interface{}) error {
-	// Discover available templates
-	templates, err := templating.DiscoverTemplates(templatesFS)
+func scaffoldComponentDirect(catalog *templating.TemplateCatalog, templateName, componentPath string, params map[string]interface{}) error {
+	// Look up the template
+	templateInfo, err := catalog.Info(templateName)
 	if err != nil {
-		return fmt.Errorf("could not discover templates: %w", err)
-	}
-
-	// Find the specific template
-	var templateInfo *templating.TemplateInfo
-	for _, template := range templates {
-		if template.Name == templateName {
-			templateInfo = &template
-			break
-		}
-	}
-
-	if templateInfo == nil {
-		return fmt.Errorf("template '%s' not found", templateName)
+		return newNotFoundError("template '%s' not found", templateName)
 	}
 
 	// Create a template processor
 	processor := templating.NewTemplateProcessor(templateInfo.Manifest, params, false)
+	processor.SetFileSystem(workspaceFS)
+	processor.SetCommandEnvironment(packageCacheEnvironment())
 
 	// Execute the scaffolding process
-	err = processor.ScaffoldProject(templatesFS, templateName, componentPath)
+	err = processor.ScaffoldProject(catalog.FS(), templateName, componentPath)
 	if err != nil {
 		return fmt.Errorf("failed to scaffold component: %w", err)
 	}
@@ -965,7 +1352,7 @@ func scaffoldComponentDirect(templateName, componentPath string, params map[stri
 }
 
 // updateWorkbenchManifestForComponent updates the workbench.yaml file with the new component
-func updateWorkbenchManifestForComponent(manifest *manifestPkg.WorkbenchManifest, componentName, templateName, projectRoot string) error {
+func updateWorkbenchManifestForComponent(manifest *manifestPkg.WorkbenchManifest, componentName, templateName, projectRoot string, ownership manifestPkg.Ownership) error {
 	// Initialize Components map if it doesn't exist
 	if manifest.Components == nil {
 		manifest.Components = make(map[string]manifestPkg.Component)
@@ -973,24 +1360,12 @@ func updateWorkbenchManifestForComponent(manifest *manifestPkg.WorkbenchManifest
 
 	// Add the new component
 	manifest.Components[componentName] = manifestPkg.Component{
-		Template: templateName,
-		Path:     filepath.Join(".", componentName),
-	}
-
-	// Marshal to YAML
-	data, err := yaml.Marshal(manifest)
-	if err != nil {
-		return fmt.Errorf("failed to marshal manifest: %w", err)
+		Ownership: ownership,
+		Template:  templateName,
+		Path:      filepath.Join(".", componentName),
 	}
 
-	// Write to file
-	manifestPath := filepath.Join(projectRoot, "workbench.yaml")
-	err = os.WriteFile(manifestPath, data, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write workbench.yaml: %w", err)
-	}
-
-	return nil
+	return saveWorkbenchManifest(manifest, projectRoot)
 }
 
 // printAddComponentSuccessMessage prints a success message for component addition
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/hooks"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+	"github.com/jashkahar/open-workbench-platform/internal/sharedlib"
+	"github.com/spf13/cobra"
+)
+
+var addSharedCmd = &cobra.Command{
+	Use:   "shared <name>",
+	Short: "Add a shared library package used by several services.",
+	Long: `Add a shared library package, such as common models, that services build in
+instead of copying the code between them.
+
+The library is created under shared/<name> and registered in workbench.yaml
+as a service of kind library. Libraries never run on their own: 'om compose'
+passes them to the builds of the services that list them under libraries, as
+extra build contexts their Dockerfiles can COPY --from.
+
+Each service named with --service gets the library in its libraries list and
+as a local dependency: a file: entry in package.json for TypeScript, or an
+editable install in requirements.txt for Python.
+
+Examples:
+  # A TypeScript library used by two services
+  om add shared models --language typescript --service web --service api
+
+  # A Python library, placed elsewhere
+  om add shared common --language python --path libs/common --service worker`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAddShared,
+}
+
+// initAddSharedCommand initializes the add shared command
+func initAddSharedCommand() {
+	addCmd.AddCommand(addSharedCmd)
+
+	addSharedCmd.Flags().String("language", "", "Library language: typescript or python (optional - will prompt if not provided)")
+	addSharedCmd.Flags().StringSlice("service", nil, "Service that uses the library (repeatable)")
+	addSharedCmd.Flags().String("path", "", "Library directory relative to the project root (default: shared/<name>)")
+}
+
+func runAddShared(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+	if err := checkWritable(projectRoot); err != nil {
+		return err
+	}
+
+	name, err := ValidateAndSanitizeName(args[0], nil)
+	if err != nil {
+		return newValidationError("invalid library name '%s': %v", args[0], err)
+	}
+	libraryDir, err := resolveLibraryPath(cmd, name)
+	if err != nil {
+		return err
+	}
+	if err := performSafetyChecks(manifest, projectRoot, name, libraryDir); err != nil {
+		return err
+	}
+
+	consumers, _ := cmd.Flags().GetStringSlice("service")
+	for _, consumer := range consumers {
+		service, exists := manifest.Services[consumer]
+		if !exists {
+			return newNotFoundError("service '%s' not found in workbench.yaml", consumer)
+		}
+		if service.IsLibrary() {
+			return newValidationError("'%s' is a library; only services can use libraries", consumer)
+		}
+	}
+
+	language, err := getLibraryLanguage(cmd)
+	if err != nil {
+		return err
+	}
+	files, err := sharedlib.Files(manifest.Metadata.Name, name, language)
+	if err != nil {
+		return newValidationError("%v", err)
+	}
+
+	// Write the library, removing it again if anything fails
+	libraryPath := filepath.Join(projectRoot, filepath.FromSlash(libraryDir))
+	for file, content := range files {
+		target := filepath.Join(libraryPath, filepath.FromSlash(file))
+		if err := workspaceFS.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			workspaceFS.RemoveAll(libraryPath)
+			return fmt.Errorf("failed to create library directory: %w", err)
+		}
+		if err := workspaceFS.WriteFile(target, content, 0644); err != nil {
+			workspaceFS.RemoveAll(libraryPath)
+			return fmt.Errorf("failed to write %s: %w", path.Join(libraryDir, file), err)
+		}
+	}
+
+	manifest.Services[name] = manifestPkg.Service{
+		Template: sharedlib.Template(language),
+		Kind:     manifestPkg.ServiceKindLibrary,
+		Path:     libraryDir,
+	}
+	pkg := sharedlib.PackageName(manifest.Metadata.Name, name, language)
+	var unlinked []string
+	for _, consumer := range consumers {
+		service := manifest.Services[consumer]
+		service.Libraries = appendUnique(service.Libraries, name)
+		manifest.Services[consumer] = service
+
+		changed, err := sharedlib.Link(workspaceFS, projectRoot, service.Path, libraryDir, pkg, language)
+		if err != nil {
+			workspaceFS.RemoveAll(libraryPath)
+			return fmt.Errorf("failed to add %s to %s: %w", pkg, consumer, err)
+		}
+		if changed == "" {
+			unlinked = append(unlinked, consumer)
+		} else {
+			fmt.Printf("🔗 Added %s to %s\n", pkg, changed)
+		}
+	}
+
+	if err := saveWorkbenchManifest(manifest, projectRoot); err != nil {
+		workspaceFS.RemoveAll(libraryPath)
+		return fmt.Errorf("failed to update workbench.yaml: %w", err)
+	}
+
+	printAddSharedSuccessMessage(name, pkg, libraryDir, consumers, unlinked)
+	fireHook(cmd, hooks.EventScaffoldComplete, manifest.Metadata.Name, projectRoot, map[string]string{
+		"kind": "library", "name": name, "template": sharedlib.Template(language), "path": libraryDir,
+	})
+	return nil
+}
+
+// resolveLibraryPath returns the directory of a new library relative to the
+// project root, with forward slashes: the --path flag when set, otherwise
+// shared/<name>
+func resolveLibraryPath(cmd *cobra.Command, name string) (string, error) {
+	pathFlag, _ := cmd.Flags().GetString("path")
+	if pathFlag == "" {
+		return path.Join(sharedlib.Dir, name), nil
+	}
+	cleanPath, err := ValidateAndSanitizePath(pathFlag, nil)
+	if err != nil {
+		return "", err
+	}
+	if !manifestPkg.IsProjectPath(cleanPath) {
+		return "", newValidationError("library path '%s' must be inside the project", pathFlag)
+	}
+	libraryDir := filepath.ToSlash(cleanPath)
+	for _, segment := range strings.Split(libraryDir, "/") {
+		if _, err := ValidateAndSanitizeName(segment, nil); err != nil {
+			return "", newValidationError("invalid library path '%s': %v", pathFlag, err)
+		}
+	}
+	return libraryDir, nil
+}
+
+// getLibraryLanguage returns the --language flag, or asks for the language
+func getLibraryLanguage(cmd *cobra.Command) (string, error) {
+	language, _ := cmd.Flags().GetString("language")
+	if language != "" {
+		return language, nil
+	}
+	language, err := prompter.Select(prompt.Question{
+		Name:    "language",
+		Message: "Which language is the library written in?",
+		Options: sharedlib.Languages,
+		Help:    "Services using the library should be written in the same language",
+		Default: sharedlib.LanguageTypeScript,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get library language: %w", err)
+	}
+	return language, nil
+}
+
+// appendUnique appends value to values unless it is already there
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// printAddSharedSuccessMessage explains how services pick up the new library
+func printAddSharedSuccessMessage(name, pkg, libraryDir string, consumers, unlinked []string) {
+	fmt.Printf("\n✅ Added shared library '%s' (%s) in %s\n", name, pkg, libraryDir)
+	if len(consumers) > 0 {
+		fmt.Printf("\n'om compose' passes it to the builds of %s as the build context '%s'.\n", strings.Join(consumers, ", "), name)
+		fmt.Println("Copy it in from each Dockerfile, before installing dependencies:")
+		fmt.Printf("   COPY --from=%s . /%s/%s\n", name, sharedlib.Dir, name)
+	} else {
+		fmt.Printf("\nUse it from a service by adding '%s' to the service's libraries in workbench.yaml.\n", name)
+	}
+	for _, consumer := range unlinked {
+		fmt.Printf("⚠️  Add %s to the dependencies of %s yourself; om found no dependency file to update\n", pkg, consumer)
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/adr"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+// adrClock dates new decision records. Tests replace it.
+var adrClock = time.Now
+
+var adrCmd = &cobra.Command{
+	Use:   "adr",
+	Short: "Record architecture decisions alongside the manifest",
+}
+
+var adrNewCmd = &cobra.Command{
+	Use:   "new <title>",
+	Short: "Create a numbered architecture decision record",
+	Long: `Create an architecture decision record (ADR) under docs/adr/.
+
+Records are numbered in order (0001-use-postgres.md, 0002-...) and follow
+Michael Nygard's format: status, context, decision, and consequences. New
+records start as Proposed; edit the status as the decision is accepted or
+superseded.
+
+Once a project has a docs/adr/ directory, commands that change its structure,
+such as adding a component, deleting a service, or generating for another
+deployment target, offer to record a decision about the change.
+
+Examples:
+  # Record a decision
+  om adr new "Use PostgreSQL for the orders service"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runADRNew,
+}
+
+// initADRCommand registers the adr command and its subcommands
+func initADRCommand() {
+	adrCmd.AddCommand(adrNewCmd)
+	if rootCmd != nil {
+		rootCmd.AddCommand(adrCmd)
+	}
+}
+
+// runADRNew creates a decision record with the given title
+func runADRNew(cmd *cobra.Command, args []string) error {
+	projectRoot, _, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+
+	title := strings.TrimSpace(strings.Join(args, " "))
+	path, err := adr.Create(workspaceFS, projectRoot, adr.Record{Title: title, Date: adrClock()})
+	if err != nil {
+		return newValidationError("failed to create the decision record: %w", err)
+	}
+	fmt.Printf("📝 Created %s\n", filepath.ToSlash(path))
+	return nil
+}
+
+// offerDecisionRecord asks whether to record an architecture decision about
+// a structural change, in projects that keep decision records. The change
+// has already been made, so neither declining nor failing to write the
+// record fails the command.
+func offerDecisionRecord(projectRoot, title, context string) {
+	if !adr.Enabled(workspaceFS, projectRoot) {
+		return
+	}
+
+	record, err := prompter.Confirm(prompt.Question{
+		Name:    "recordDecision",
+		Message: fmt.Sprintf("Record an architecture decision for this change (%s)?", title),
+		Help:    "Creates a numbered record under docs/adr/ with the change as its context",
+		Default: false,
+	})
+	if err != nil || !record {
+		return
+	}
+
+	path, err := adr.Create(workspaceFS, projectRoot, adr.Record{Title: title, Context: context, Date: adrClock()})
+	if err != nil {
+		fmt.Printf("⚠️  Could not record the decision: %v\n", err)
+		return
+	}
+	fmt.Printf("📝 Created %s; fill in the decision and its consequences\n", filepath.ToSlash(path))
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/bundle"
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/spf13/cobra"
+)
+
+// bundleRunner runs the docker commands of om bundle. Tests replace it.
+var bundleRunner deps.Runner = deps.ExecRunner
+
+// bundleClock stamps exported bundles. Tests replace it.
+var bundleClock = time.Now
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Move the project runtime to machines without internet access",
+	Long: `Move the runtime of the project to machines without internet or registry
+access, such as air-gapped networks or demo laptops.`,
+}
+
+var bundleExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Pack the generated compose file and its images into a tarball",
+	Long: `Pack the runtime of the project into one tarball: the docker-compose.yml
+generated by 'om compose --target docker', the images it runs, the
+.env.example template, and the files the containers mount.
+
+Services built from source are built and tagged <project>-<service>:<revision>,
+where the revision is the one of workbench.yaml recorded in
+docker-compose.yml, and the bundled compose file runs them by that tag.
+Other images are pulled unless they are already present. All of them are
+saved with docker save. The .env file is left out since it holds
+credentials; set them on the target machine.
+
+Examples:
+  # Write demo-bundle.tar.gz
+  om bundle export
+
+  # Choose where the bundle is written
+  om bundle export --output /media/usb/demo.tar.gz`,
+	Args: cobra.NoArgs,
+	RunE: runBundleExport,
+}
+
+var bundleImportCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: "Unpack a bundle and load its images",
+	Long: `Unpack a bundle written by 'om bundle export' into a directory, load its
+images with docker load, and create .env from the bundled .env.example.
+Neither om nor a workbench.yaml is needed to run it afterwards, and nothing
+is pulled from a registry.
+
+The bundle is unpacked into a directory named after it, such as
+demo-bundle for demo-bundle.tar.gz, unless --dir is given. The directory
+must not exist or be empty.
+
+Examples:
+  # Unpack and load demo-bundle.tar.gz, then start it
+  om bundle import demo-bundle.tar.gz
+  cd demo-bundle && docker compose up -d`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBundleImport,
+}
+
+// initBundleCommand registers the bundle command and its subcommands
+func initBundleCommand() {
+	bundleCmd.AddCommand(bundleExportCmd)
+	bundleCmd.AddCommand(bundleImportCmd)
+	if rootCmd != nil {
+		rootCmd.AddCommand(bundleCmd)
+	}
+
+	bundleExportCmd.Flags().StringP("output", "o", "", "Path of the bundle (default <project>-bundle.tar.gz)")
+	bundleImportCmd.Flags().String("dir", "", "Directory to unpack the bundle into")
+}
+
+// runBundleExport packs the generated compose file and its images
+func runBundleExport(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		output = manifest.Metadata.Name + "-bundle.tar.gz"
+	}
+	output = displayPath(output)
+
+	if !filesystem.Exists(workspaceFS, filepath.Join(projectRoot, bundle.ComposeFile)) {
+		return newValidationError("there is no %s to bundle; run 'om compose --target docker' first", bundle.ComposeFile)
+	}
+	if err := confirmCurrentOutputs(projectRoot, manifest, "run 'om compose --target docker' to regenerate it", bundle.ComposeFile); err != nil {
+		return err
+	}
+	plan, err := bundle.Plan(workspaceFS, projectRoot, manifest.Metadata.Name)
+	if err != nil {
+		return err
+	}
+	plan.Manifest.OMVersion = Version
+	plan.Manifest.CreatedAt = bundleClock().UTC()
+
+	fmt.Printf("📦 Bundling %d image(s) of project '%s'...\n", len(plan.Manifest.Images), manifest.Metadata.Name)
+	for _, build := range plan.Builds {
+		fmt.Printf("  🔨 %s from %s\n", build.Image, build.Config.Context)
+	}
+	if err := plan.BuildImages(bundleRunner, projectRoot); err != nil {
+		return &exitCodeError{code: ExitCodeExternalTool, err: err}
+	}
+	if err := plan.PullImages(bundleRunner, projectRoot); err != nil {
+		return &exitCodeError{code: ExitCodeExternalTool, err: err}
+	}
+
+	// docker save writes the images to disk, from where they are packed
+	scratch, err := os.MkdirTemp("", "om-bundle-")
+	if err != nil {
+		return fmt.Errorf("failed to create a scratch directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+	imagesPath := filepath.Join(scratch, bundle.ImagesFile)
+	if err := plan.SaveImages(bundleRunner, projectRoot, imagesPath); err != nil {
+		return &exitCodeError{code: ExitCodeExternalTool, err: err}
+	}
+
+	if err := workspaceFS.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+	file, err := bundle.Create(workspaceFS, output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	err = plan.Write(file, workspaceFS, projectRoot, imagesPath)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+
+	fmt.Printf("✅ Wrote %s\n", output)
+	for _, image := range plan.Manifest.Images {
+		fmt.Printf("  • %s\n", image)
+	}
+	fmt.Println()
+	fmt.Println("🚀 On the target machine:")
+	fmt.Printf("  om bundle import %s\n", filepath.Base(output))
+	return nil
+}
+
+// runBundleImport unpacks a bundle and loads its images
+func runBundleImport(cmd *cobra.Command, args []string) error {
+	source := displayPath(args[0])
+	dir, _ := cmd.Flags().GetString("dir")
+	if dir == "" {
+		dir = strings.TrimSuffix(strings.TrimSuffix(filepath.Base(source), ".gz"), ".tar")
+		dir = strings.TrimSuffix(dir, ".tgz")
+	}
+	dir = displayPath(dir)
+
+	if entries, err := workspaceFS.ReadDir(dir); err == nil && len(entries) > 0 {
+		return newValidationError("'%s' is not empty; choose another directory with --dir", dir)
+	}
+	file, err := bundle.Open(workspaceFS, source)
+	if errors.Is(err, fs.ErrNotExist) {
+		return newNotFoundError("bundle '%s' not found", args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", args[0], err)
+	}
+	defer file.Close()
+
+	fmt.Printf("📦 Unpacking %s into %s...\n", args[0], dir)
+	manifest, err := bundle.Extract(workspaceFS, file, dir)
+	if err != nil {
+		return newValidationError("%v", err)
+	}
+	fmt.Printf("🐳 Loading %d image(s)...\n", len(manifest.Images))
+	if err := bundle.LoadImages(bundleRunner, workspaceFS, dir); err != nil {
+		return &exitCodeError{code: ExitCodeExternalTool, err: err}
+	}
+
+	// The services read ./.env; start from the template and leave the
+	// values to the operator
+	envPath := filepath.Join(dir, ".env")
+	template, err := workspaceFS.ReadFile(filepath.Join(dir, bundle.EnvTemplate))
+	if err != nil {
+		return err
+	}
+	if err := workspaceFS.WriteFile(envPath, template, 0644); err != nil {
+		return fmt.Errorf("failed to write .env: %w", err)
+	}
+
+	fmt.Printf("✅ Imported project '%s' (om %s, created %s)\n", manifest.Project, manifest.OMVersion, manifest.CreatedAt.Format("2006-01-02 15:04 MST"))
+	if unset := unsetVariables(template); len(unset) > 0 {
+		fmt.Printf("🔐 Set %s in %s before starting\n", strings.Join(unset, ", "), envPath)
+	}
+	fmt.Println()
+	fmt.Println("🚀 Next steps:")
+	fmt.Printf("  cd %s\n", dir)
+	fmt.Println("  docker compose up -d")
+	return nil
+}
+
+// unsetVariables returns the variables of an env file that have no value
+func unsetVariables(data []byte) []string {
+	var unset []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if name, value, _ := strings.Cut(line, "="); value == "" {
+			unset = append(unset, name)
+		}
+	}
+	return unset
+}
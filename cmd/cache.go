@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/pkgcache"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+	"github.com/spf13/cobra"
+)
+
+// packageCacheRunner runs the package managers that warm the cache. Tests
+// replace it.
+var packageCacheRunner deps.Runner = deps.ExecRunner
+
+// packageCacheDir returns where om cache warm keeps packages: the
+// OM_PACKAGE_CACHE directory, or open-workbench/packages in the user's
+// cache directory
+func packageCacheDir() string {
+	if dir := os.Getenv("OM_PACKAGE_CACHE"); dir != "" {
+		return dir
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "open-workbench", "packages")
+}
+
+// packageCacheEnvironment returns the variables that make post-scaffold
+// installs use the package cache, or nil when it was never warmed
+func packageCacheEnvironment() []string {
+	dir := packageCacheDir()
+	if dir == "" {
+		return nil
+	}
+	return pkgcache.Environment(filesystem.NewOSFS(), dir)
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local package cache",
+	Long: `Manage the local cache of the npm and pip packages templates depend on.
+
+Once the cache is warmed, om scaffolds install dependencies from it instead
+of fetching them from the public registries, which makes workshops and
+offline environments fast and reliable.`,
+}
+
+var cacheWarmCmd = &cobra.Command{
+	Use:   "warm [template...]",
+	Short: "Download the packages templates depend on",
+	Long: `Download the npm and pip packages the given templates, or all templates,
+depend on into the local package cache.
+
+The cache is kept in OM_PACKAGE_CACHE, or open-workbench/packages in the
+user's cache directory, unless --dir is given. From then on, the dependency
+installs of om init, om add service, and om examples create prefer cached
+packages.
+
+Packages can also be fetched through registry mirrors. --compose writes a
+Docker Compose file that runs Verdaccio for npm and devpi for pip; start it,
+then warm the cache through the mirrors so they keep every package for
+other machines too. The mirrors are remembered for later installs.
+
+Examples:
+  # Cache the packages of every template
+  om cache warm
+
+  # Cache the packages of two templates
+  om cache warm express-api fastapi-basic
+
+  # Run registry mirrors and warm them
+  om cache warm --compose om-cache.yml
+  docker compose -f om-cache.yml up -d
+  om cache warm --npm-registry http://localhost:4873/ --pip-index http://localhost:3141/root/pypi/+simple/`,
+	ValidArgsFunction: completeTemplateNames,
+	RunE:              runCacheWarm,
+}
+
+// initCacheCommand registers the cache command and its subcommands
+func initCacheCommand() {
+	cacheCmd.AddCommand(cacheWarmCmd)
+	if rootCmd != nil {
+		rootCmd.AddCommand(cacheCmd)
+	}
+
+	cacheWarmCmd.Flags().String("dir", "", "Directory to keep the packages in")
+	cacheWarmCmd.Flags().String("npm-registry", "", "npm registry mirror to download packages through")
+	cacheWarmCmd.Flags().String("pip-index", "", "Python package index mirror to download packages through")
+	cacheWarmCmd.Flags().String("compose", "", "Write a Docker Compose file that runs registry mirrors instead of warming")
+}
+
+// runCacheWarm downloads the packages of the selected templates into the
+// package cache
+func runCacheWarm(cmd *cobra.Command, args []string) error {
+	if composePath, _ := cmd.Flags().GetString("compose"); composePath != "" {
+		return writeCacheComposeFile(composePath)
+	}
+
+	dir, _ := cmd.Flags().GetString("dir")
+	if dir == "" {
+		dir = packageCacheDir()
+	}
+	if dir == "" {
+		return newValidationError("could not find a cache directory; set OM_PACKAGE_CACHE or pass --dir")
+	}
+
+	osFS := filesystem.NewOSFS()
+	registries, err := pkgcache.LoadRegistries(osFS, dir)
+	if err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("npm-registry") {
+		registries.NPM, _ = cmd.Flags().GetString("npm-registry")
+	}
+	if cmd.Flags().Changed("pip-index") {
+		registries.Pip, _ = cmd.Flags().GetString("pip-index")
+	}
+	if err := pkgcache.SaveRegistries(osFS, dir, registries); err != nil {
+		return fmt.Errorf("failed to create the package cache: %w", err)
+	}
+
+	templates, err := cacheWarmTemplates(args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📦 Warming the package cache in %s...\n", dir)
+	warmed := make(map[string]bool)
+	failed := 0
+	for _, template := range templates {
+		servicePath := filepath.Join("sample-project", template.Name)
+		seed := projectParameterDefaults(servicePath, "", "")
+		values := templating.ParameterMatrix(template.Manifest, seed)[0].Values
+		output, err := templating.RenderTemplate(templatesFS, template.Name, values, servicePath)
+		if err != nil {
+			fmt.Printf("  ❌ %-20s %v\n", template.Name, err)
+			failed++
+			continue
+		}
+		manifests, err := pkgcache.Find(output, servicePath)
+		if err != nil {
+			fmt.Printf("  ❌ %-20s %v\n", template.Name, err)
+			failed++
+			continue
+		}
+		if len(manifests) == 0 {
+			fmt.Printf("  ➖ %-20s no dependencies\n", template.Name)
+			continue
+		}
+		for _, manifest := range manifests {
+			data, err := output.ReadFile(manifest.Path)
+			if err != nil {
+				return err
+			}
+			// Templates sharing a manifest download it once
+			key := manifest.Warmer.Ecosystem() + "\x00" + string(data)
+			if warmed[key] {
+				continue
+			}
+			warmed[key] = true
+			if err := pkgcache.Warm(packageCacheRunner, manifest, data, dir, registries); err != nil {
+				fmt.Printf("  ❌ %-20s %s: %v\n", template.Name, manifest.Warmer.Ecosystem(), err)
+				failed++
+				continue
+			}
+			fmt.Printf("  ✅ %-20s %s packages cached\n", template.Name, manifest.Warmer.Ecosystem())
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d download(s) failed; the packages that did download stay cached", failed)
+	}
+	fmt.Println("✅ New services now install their dependencies from the cache")
+	return nil
+}
+
+// cacheWarmTemplates returns the named templates, or every template
+func cacheWarmTemplates(names []string) ([]templating.TemplateInfo, error) {
+	templates, err := templating.DiscoverTemplates(templatesFS)
+	if err != nil {
+		return nil, fmt.Errorf("could not discover templates: %w", err)
+	}
+	if len(names) == 0 {
+		return templates, nil
+	}
+	byName := make(map[string]templating.TemplateInfo, len(templates))
+	for _, template := range templates {
+		byName[template.Name] = template
+	}
+	var selected []templating.TemplateInfo
+	for _, name := range names {
+		template, found := byName[name]
+		if !found {
+			return nil, newNotFoundError("template '%s' not found", name)
+		}
+		selected = append(selected, template)
+	}
+	return selected, nil
+}
+
+// writeCacheComposeFile writes the registry mirror compose file and
+// explains how to warm the mirrors
+func writeCacheComposeFile(path string) error {
+	if !filepath.IsAbs(path) {
+		cwd, err := workingDir()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		path = filepath.Join(cwd, path)
+	}
+	if filesystem.Exists(workspaceFS, path) {
+		return newValidationError("'%s' already exists", path)
+	}
+	if err := workspaceFS.WriteFile(path, []byte(pkgcache.ComposeFile()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Printf("✅ Wrote the registry mirrors to %s\n", displayPath(path))
+	fmt.Println()
+	fmt.Println("🚀 Next steps:")
+	fmt.Printf("  docker compose -f %s up -d\n", displayPath(path))
+	fmt.Printf("  om cache warm --npm-registry %s --pip-index %s\n", pkgcache.ComposeNPMRegistry, pkgcache.ComposePipIndex)
+	return nil
+}
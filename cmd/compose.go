@@ -1,18 +1,31 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
-	"github.com/AlecAivazis/survey/v2"
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/gencache"
 	"github.com/jashkahar/open-workbench-platform/internal/generator"
 	"github.com/jashkahar/open-workbench-platform/internal/generator/docker"
+	"github.com/jashkahar/open-workbench-platform/internal/generator/kubernetes"
+	"github.com/jashkahar/open-workbench-platform/internal/hooks"
+	"github.com/jashkahar/open-workbench-platform/internal/labels"
+	"github.com/jashkahar/open-workbench-platform/internal/ports"
 
 	// "github.com/jashkahar/open-workbench-platform/internal/generator/terraform" // Temporarily disabled
 	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+	"github.com/jashkahar/open-workbench-platform/internal/report"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var composeCmd = &cobra.Command{
@@ -22,6 +35,7 @@ var composeCmd = &cobra.Command{
 
 This command supports multiple deployment targets:
 - docker: Generate Docker Compose configuration for local development
+- kubernetes: Generate Kubernetes manifests in k8s/ (experimental; enable with OM_EXPERIMENTS=kubernetes)
 - terraform: Generate Terraform configuration for cloud infrastructure (temporarily disabled)
 
 Interactive Mode (no target specified):
@@ -29,6 +43,7 @@ Interactive Mode (no target specified):
 
 Direct Mode (with target specified):
   om compose --target docker
+  om compose --target kubernetes
   # om compose --target terraform (temporarily disabled)
 
 Examples:
@@ -38,12 +53,25 @@ Examples:
   # Direct mode with Docker target
   om compose --target docker
 
+  # Deployments, Services, ConfigMaps, and claims for a k3s cluster
+  OM_EXPERIMENTS=kubernetes om compose --target kubernetes
+  kubectl apply -k k8s
+
   # Direct mode with Terraform target (temporarily disabled)
   # om compose --target terraform
 
+  # Only the services in the "data" group, plus anything they reference
+  om compose --target docker --group data
+
+  # Regenerate even when nothing changed since the last run
+  om compose --target docker --force
+
 The generated configuration will be based on your workbench.yaml file and
-the selected target.`,
-	RunE: runCompose,
+the selected target. When neither the manifest, the files the generator
+reads, nor the generated files changed since the last run, generation is
+skipped and the configuration is reported as up to date.`,
+	RunE:        runCompose,
+	Annotations: map[string]string{dryRunAnnotation: "true"},
 }
 
 // initComposeCommand registers the compose command with the root command
@@ -53,19 +81,53 @@ func initComposeCommand() {
 	}
 
 	// Add target flag
-	composeCmd.Flags().String("target", "", "Deployment target (docker)")
+	composeCmd.Flags().String("target", "", "Deployment target (docker, kubernetes)")
 	// Add environment flag for Terraform
-	composeCmd.Flags().String("env", "", "Environment name (dev, staging, prod)")
+	composeCmd.Flags().String("env", "", "Environment to generate for, leaving out the services it excludes")
+	// Add group flag to generate configuration for part of the project
+	composeCmd.Flags().String("group", "", "Only include the services of this group from workbench.yaml")
+	// Add force flag to regenerate even when the outputs are up to date
+	composeCmd.Flags().Bool("force", false, "Regenerate even when nothing changed since the last run")
 }
 
+// dockerPrerequisites overrides the Docker tooling check when set. Tests use
+// it to run compose on machines without Docker installed.
+var dockerPrerequisites docker.PrerequisiteChecker
+
+// portAvailable reports whether a host port is free when new ports are
+// published. Tests replace it so generated ports do not depend on the machine.
+var portAvailable = ports.Available
+
+// hostEnvironment returns the environment om runs in, such as WSL or Git
+// Bash, which decides how paths in generated files are written. Tests pin it.
+var hostEnvironment = templating.NewPlatformUtils().Environment
+
+// containerEngine returns the container engine that runs the project, such
+// as Podman or Colima, detected once per run. Tests pin it.
+var containerEngine = sync.OnceValue(func() compose.Engine {
+	return compose.DetectEngine(compose.HostProbe())
+})
+
 func runCompose(cmd *cobra.Command, args []string) error {
+	return composeProject(cmd, getTarget)
+}
+
+// composeProject generates the configuration of the project in the current
+// directory for the target chooseTarget picks, honoring the group, env, and
+// force flags of cmd. om dev reuses it to bring docker-compose.yml up to date.
+func composeProject(cmd *cobra.Command, chooseTarget func(*cobra.Command) (string, error)) error {
 	// Find workbench.yaml
-	workbenchPath := "workbench.yaml"
-	if _, err := os.Stat(workbenchPath); os.IsNotExist(err) {
-		return fmt.Errorf("workbench.yaml not found in current directory. Please run this command from your project root")
+	projectDir, err := workingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	workbenchPath := filepath.Join(projectDir, "workbench.yaml")
+	if _, err := workspaceFS.Stat(workbenchPath); errors.Is(err, fs.ErrNotExist) {
+		return manifestPkg.NewNotFoundError("current directory", err)
 	}
 
 	fmt.Println("📖 Loading workbench.yaml...")
+	timer := report.NewTimer(reportClock)
 
 	// Load and parse workbench.yaml
 	manifest, err := loadWorkbenchManifest(workbenchPath)
@@ -75,8 +137,28 @@ func runCompose(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("✅ Loaded project: %s\n", manifest.Metadata.Name)
 
+	// Record which revision of the manifest the files are generated from
+	manifestRev, err := manifestRevision(projectDir, manifest)
+	if err != nil {
+		return err
+	}
+
+	// Narrow the manifest down to a group if requested
+	manifest, err = selectGroup(cmd, manifest)
+	if err != nil {
+		return err
+	}
+
+	// Leave out what the selected environment does not deploy
+	manifest, err = selectEnvironment(cmd, manifest)
+	if err != nil {
+		return err
+	}
+
+	timer.Phase("load")
+
 	// Get target from flag or prompt user
-	target, err := getTarget(cmd)
+	target, err := chooseTarget(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to get target: %w", err)
 	}
@@ -88,15 +170,133 @@ func runCompose(cmd *cobra.Command, args []string) error {
 	// 	}
 	// }
 
+	// Run the project's preCompose hook, e.g. to regenerate API clients
+	group, _ := cmd.Flags().GetString("group")
+	env, _ := cmd.Flags().GetString("env")
+	hookData := map[string]string{"target": target, "group": group, "env": env}
+	if err := runLifecycleHook(cmd, projectDir, manifest, manifestPkg.HookPreCompose, hookData); err != nil {
+		return err
+	}
+
+	// Skip generation when nothing it depends on changed since the last run.
+	// Secrets read from an external store may have changed without the cache
+	// seeing it, so those projects are always regenerated.
+	cacheTarget, key, err := composeCacheKey(cmd, target, manifest)
+	if err != nil {
+		return err
+	}
+	cache := gencache.Load(workspaceFS, projectDir)
+	previousTargets := cache.Targets()
+	force, _ := cmd.Flags().GetBool("force")
+	if !force && manifest.Secrets.ProviderName() == manifestPkg.SecretsGenerate {
+		if entry, ok := cache.Lookup(cacheTarget, key); ok {
+			fmt.Printf("✅ %s configuration is up to date (use --force to regenerate)\n", target)
+			printRegistryLoginHints(manifest)
+			return reportWarnings(cmd, entry.Warnings)
+		}
+	}
+
+	tracker := gencache.NewTrackingFS(workspaceFS)
+	secrets, err := newSecretsProvider(tracker, projectDir, manifest)
+	if err != nil {
+		return err
+	}
+	gen, err := newComposeGenerator(target, tracker, projectDir, labels.Info{
+		Project: manifest.Metadata.Name, Version: Version, Environment: env, Revision: manifestRev,
+	}, secrets)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔧 Using %s generator: %s\n", target, gen.Description())
+
+	// Generate configuration
+	timer.Phase("prepare")
+	if err := gen.Generate(manifest); err != nil {
+		return fmt.Errorf("failed to generate %s configuration: %w", target, err)
+	}
+	timer.Phase("generate")
+	printRegistryLoginHints(manifest)
+
+	var found []warnings.Warning
+	if source, ok := gen.(warnings.Source); ok {
+		found = source.Warnings()
+	}
+	cache.Record(cacheTarget, key, tracker, found)
+	if err := cache.Save(); err != nil {
+		fmt.Printf("⚠️  Could not update the generator cache: %v\n", err)
+	}
+	writeGenerationReport(projectDir, manifest.Metadata.Name, timer, tracker, report.Inputs{
+		Target: target, Group: group, Env: env,
+	}, found)
+	fireHook(cmd, hooks.EventComposeGenerated, manifest.Metadata.Name, projectDir, hookData)
+	if err := runLifecycleHook(cmd, projectDir, manifest, manifestPkg.HookPostCompose, hookData); err != nil {
+		return err
+	}
+
+	// Generating for a new target is a change of deployment strategy
+	switched := len(previousTargets) > 0
+	for _, previous := range previousTargets {
+		if previous == target {
+			switched = false
+		}
+	}
+	if switched {
+		offerDecisionRecord(projectDir, fmt.Sprintf("Deploy with %s", target),
+			fmt.Sprintf("Generated %s configuration for a project previously generated for %s.", target, strings.Join(previousTargets, ", ")))
+	}
+	return reportWarnings(cmd, found)
+}
+
+// composeCacheKey returns the cache entry name of a compose run, one per
+// target, group, and environment, and the key of the inputs it depends on
+func composeCacheKey(cmd *cobra.Command, target string, manifest *manifestPkg.WorkbenchManifest) (string, string, error) {
+	group, _ := cmd.Flags().GetString("group")
+	env, _ := cmd.Flags().GetString("env")
+
+	name := target
+	if group != "" {
+		name += "/group=" + group
+	}
+	if env != "" {
+		name += "/env=" + env
+	}
+	key, err := gencache.Key(Version, target, env, hostEnvironment(), containerEngine().Name, manifest)
+	if err != nil {
+		return "", "", err
+	}
+	return name, key, nil
+}
+
+// newComposeGenerator returns the generator for a deployment target that
+// writes its files through fsys into projectDir, with passwords from secrets
+func newComposeGenerator(target string, fsys filesystem.FS, projectDir string, info labels.Info, secrets compose.SecretsProvider) (generator.Generator, error) {
 	// Create generator registry
 	registry := generator.NewRegistry()
 
 	// Register generators
-	dockerGen := docker.NewGenerator()
+	engine := containerEngine()
+	dockerGen := docker.NewGeneratorWithFS(fsys, projectDir)
+	dockerGen.SetPrerequisiteChecker(compose.NewPrerequisiteCheckerFor(engine))
+	if dockerPrerequisites != nil {
+		dockerGen.SetPrerequisiteChecker(dockerPrerequisites)
+	}
+	dockerGen.SetEngine(engine)
+	dockerGen.SetSecretsProvider(secrets)
+	dockerGen.SetLabels(info)
+	dockerGen.SetPortProbe(portAvailable)
+	dockerGen.SetHost(hostEnvironment())
+	kubernetesGen := kubernetes.NewGeneratorWithFS(fsys, projectDir)
+	kubernetesGen.SetLabels(info)
+	kubernetesGen.SetSecretsProvider(secrets)
 	// terraformGen := terraform.NewGenerator() // Temporarily disabled
 
 	if err := registry.Register(dockerGen); err != nil {
-		return fmt.Errorf("failed to register Docker generator: %w", err)
+		return nil, fmt.Errorf("failed to register Docker generator: %w", err)
+	}
+
+	if err := registry.Register(kubernetesGen); err != nil {
+		return nil, fmt.Errorf("failed to register Kubernetes generator: %w", err)
 	}
 
 	// if err := registry.Register(terraformGen); err != nil {
@@ -106,17 +306,67 @@ func runCompose(cmd *cobra.Command, args []string) error {
 	// Get the selected generator
 	gen, err := registry.Get(target)
 	if err != nil {
-		return fmt.Errorf("failed to get generator '%s': %w", target, err)
+		return nil, fmt.Errorf("failed to get generator '%s': %w", target, err)
 	}
+	return gen, nil
+}
 
-	fmt.Printf("🔧 Using %s generator: %s\n", target, gen.Description())
+// selectGroup returns the part of the manifest selected by the --group flag,
+// or the whole manifest when the flag is not set
+func selectGroup(cmd *cobra.Command, manifest *manifestPkg.WorkbenchManifest) (*manifestPkg.WorkbenchManifest, error) {
+	group, err := cmd.Flags().GetString("group")
+	if err != nil || group == "" {
+		return manifest, err
+	}
 
-	// Generate configuration
-	if err := gen.Generate(manifest); err != nil {
-		return fmt.Errorf("failed to generate %s configuration: %w", target, err)
+	members, err := manifest.GroupMembers(group)
+	if err != nil {
+		return nil, err
+	}
+	subset, err := manifest.Subset(members)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select group '%s': %w", group, err)
 	}
 
-	return nil
+	names := getServiceNames(subset.Services)
+	for name := range subset.Components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Printf("🎯 Using group '%s': %s\n", group, strings.Join(names, ", "))
+	return subset, nil
+}
+
+// selectEnvironment returns the part of the manifest deployed to the
+// environment selected by the --env flag, or the whole manifest when the
+// flag is not set
+func selectEnvironment(cmd *cobra.Command, manifest *manifestPkg.WorkbenchManifest) (*manifestPkg.WorkbenchManifest, error) {
+	env, err := cmd.Flags().GetString("env")
+	if err != nil || env == "" {
+		return manifest, err
+	}
+
+	subset, err := manifest.ForEnvironment(env)
+	if err != nil {
+		return nil, err
+	}
+
+	var left []string
+	for name := range manifest.Services {
+		if _, exists := subset.Services[name]; !exists {
+			left = append(left, name)
+		}
+	}
+	for name := range manifest.Components {
+		if _, exists := subset.Components[name]; !exists {
+			left = append(left, name)
+		}
+	}
+	if len(left) > 0 {
+		sort.Strings(left)
+		fmt.Printf("🌍 Environment '%s' leaves out: %s\n", env, strings.Join(left, ", "))
+	}
+	return subset, nil
 }
 
 // handleTerraformEnvironment handles environment configuration for Terraform generation
@@ -133,11 +383,16 @@ func handleTerraformEnvironment(cmd *cobra.Command, manifest *manifestPkg.Workbe
 		return fmt.Errorf("failed to get environment: %w", err)
 	}
 
-	// Create default environment with all services
+	// Create default environment with all services, in the cloud of the
+	// user's profile
+	provider, region := "aws", "us-east-1"
+	if cloud := userProfile().Cloud; cloud.Provider != "" && cloud.Provider != "none" {
+		provider, region = cloud.Provider, cloud.Region
+	}
 	manifest.Environments = map[string]manifestPkg.Environment{
 		envName: {
-			Provider: "aws",       // Default provider
-			Region:   "us-east-1", // Default region
+			Provider: provider,
+			Region:   region,
 			Config: map[string]string{
 				"services": strings.Join(getServiceNames(manifest.Services), ","),
 			},
@@ -169,12 +424,12 @@ func getEnvironment(cmd *cobra.Command) (string, error) {
 				return envName, nil
 			}
 		}
-		return "", fmt.Errorf("invalid environment '%s'. Valid environments are: %s", envName, strings.Join(validEnvs, ", "))
+		return "", newValidationError("invalid environment '%s'. Valid environments are: %s", envName, strings.Join(validEnvs, ", "))
 	}
 
 	// Interactive mode - prompt user for environment
-	var envChoice string
-	prompt := &survey.Select{
+	envChoice, err := prompter.Select(prompt.Question{
+		Name:    "environment",
 		Message: "Which environment would you like to configure?",
 		Options: []string{
 			"dev - Development environment",
@@ -182,9 +437,8 @@ func getEnvironment(cmd *cobra.Command) (string, error) {
 			"prod - Production environment",
 		},
 		Help: "Select the environment for Terraform configuration",
-	}
-
-	if err := survey.AskOne(prompt, &envChoice); err != nil {
+	})
+	if err != nil {
 		return "", fmt.Errorf("failed to get environment selection: %w", err)
 	}
 
@@ -219,33 +473,38 @@ func getTarget(cmd *cobra.Command) (string, error) {
 
 	// If target is provided, validate it
 	if target != "" {
-		validTargets := []string{"docker"} // Temporarily disabled terraform
+		validTargets := []string{"docker", "kubernetes"} // Temporarily disabled terraform
 		for _, valid := range validTargets {
 			if target == valid {
-				return target, nil
+				return target, requireTargetExperiment(target)
 			}
 		}
-		return "", fmt.Errorf("invalid target '%s'. Valid targets are: %s", target, strings.Join(validTargets, ", "))
+		return "", newValidationError("invalid target '%s'. Valid targets are: %s", target, strings.Join(validTargets, ", "))
 	}
 
 	// Interactive mode - prompt user for target
-	var targetChoice string
-	prompt := &survey.Select{
-		Message: "Which target would you like to compose for?",
-		Options: []string{
-			"docker - Generate Docker Compose configuration for local development",
-			// "terraform - Generate Terraform configuration for cloud infrastructure", // Temporarily disabled
-		},
-		Help: "Select the deployment target for your configuration",
+	options := []string{
+		"docker - Generate Docker Compose configuration for local development",
+		// "terraform - Generate Terraform configuration for cloud infrastructure", // Temporarily disabled
 	}
-
-	if err := survey.AskOne(prompt, &targetChoice); err != nil {
+	if settings, err := loadExperiments(); err == nil && settings.Enabled("kubernetes") {
+		options = append(options, "kubernetes - Generate Kubernetes manifests for a cluster such as k3s")
+	}
+	targetChoice, err := prompter.Select(prompt.Question{
+		Name:    "target",
+		Message: "Which target would you like to compose for?",
+		Options: options,
+		Help:    "Select the deployment target for your configuration",
+	})
+	if err != nil {
 		return "", fmt.Errorf("failed to get target selection: %w", err)
 	}
 
 	// Extract target from choice
 	if strings.Contains(targetChoice, "docker") {
 		return "docker", nil
+	} else if strings.Contains(targetChoice, "kubernetes") {
+		return "kubernetes", requireTargetExperiment("kubernetes")
 	} else if strings.Contains(targetChoice, "terraform") {
 		return "terraform", nil
 	}
@@ -253,17 +512,16 @@ func getTarget(cmd *cobra.Command) (string, error) {
 	return "", fmt.Errorf("invalid target selection")
 }
 
-// loadWorkbenchManifest loads and parses the workbench.yaml file
-func loadWorkbenchManifest(path string) (*manifestPkg.WorkbenchManifest, error) {
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read workbench.yaml: %w", err)
-	}
-
-	var manifest manifestPkg.WorkbenchManifest
-	if err := yaml.Unmarshal(content, &manifest); err != nil {
-		return nil, fmt.Errorf("failed to parse workbench.yaml: %w", err)
+// requireTargetExperiment fails for targets behind an experiment the user
+// has not enabled
+func requireTargetExperiment(target string) error {
+	if target == "kubernetes" {
+		return requireExperiment(target)
 	}
+	return nil
+}
 
-	return &manifest, nil
+// loadWorkbenchManifest loads and parses the workbench.yaml file
+func loadWorkbenchManifest(path string) (*manifestPkg.WorkbenchManifest, error) {
+	return loadManifest(path)
 }
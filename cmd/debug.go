@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/debuglog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// debugEnabled is set by the --debug flag
+var debugEnabled bool
+
+// debugClock timestamps debug log files and messages. Tests replace it.
+var debugClock = time.Now
+
+// startDebugLog starts a debug log under the project root, or the current
+// directory outside a project, when --debug is set
+func startDebugLog(cmd *cobra.Command, args []string) error {
+	if !debugEnabled {
+		return nil
+	}
+
+	dir, err := workingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if projectRoot, err := findWorkbenchYaml(dir); err == nil {
+		dir = projectRoot
+	}
+
+	logger, err := debuglog.Start(workspaceFS, dir, debugClock)
+	if err != nil {
+		return fmt.Errorf("failed to start debug log: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "🐞 Writing debug log to %s\n", logger.Path())
+	debuglog.Printf("running %s (version %s)", strings.Join(commandLine(cmd, args), " "), Version)
+	return nil
+}
+
+// commandLine returns the command path, the flags that were set, and the
+// arguments of a command
+func commandLine(cmd *cobra.Command, args []string) []string {
+	line := []string{cmd.CommandPath()}
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			line = append(line, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+		}
+	})
+	return append(line, args...)
+}
+
+// stopDebugLog records how the command ended and closes the debug log
+func stopDebugLog(err error) {
+	if err != nil {
+		debuglog.Printf("command failed: %v", err)
+	} else {
+		debuglog.Printf("command succeeded")
+	}
+	debuglog.Stop()
+}
@@ -2,15 +2,21 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/AlecAivazis/survey/v2"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
 	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+	"github.com/jashkahar/open-workbench-platform/internal/trash"
 	"github.com/spf13/cobra"
 )
 
+// trashClock names and expires deletions in the trash. Tests replace it.
+var trashClock = time.Now
+
 var deleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete services, components, or resources from your project",
@@ -25,6 +31,9 @@ Safety Features:
   • --files flag requires explicit confirmation
   • Confirmation prompts for destructive operations
   • Validates dependencies before deletion
+  • Only deletes directories inside the project that hold no other service or component
+  • Deleted files are moved to .om/trash/ and kept for 7 days
+  • --all-generated only removes files 'om compose' can write again
 
 Examples:
   # Delete a service (manifest only)
@@ -40,8 +49,17 @@ Examples:
   om delete resource backend.database
 
   # Interactive mode
-  om delete service`,
-	RunE: runDelete,
+  om delete service
+
+  # Pick several services, components, and resources from a checkbox list
+  om delete
+  om delete --files
+
+  # Delete the generated files but keep workbench.yaml and all source
+  om delete --all-generated`,
+	Args:        cobra.NoArgs,
+	RunE:        runDelete,
+	Annotations: map[string]string{dryRunAnnotation: "true"},
 }
 
 var deleteServiceCmd = &cobra.Command{
@@ -98,15 +116,12 @@ func initDeleteCommand() {
 	deleteCmd.AddCommand(deleteResourceCmd)
 
 	// Add flags
+	deleteCmd.Flags().Bool("files", false, "Also delete the directories of the selected services and components")
+	deleteCmd.Flags().Bool("all-generated", false, "Delete the generated files (docker-compose.yml, .env, terraform/) and keep the source")
 	deleteServiceCmd.Flags().Bool("files", false, "Also delete the service directory and files")
 	deleteComponentCmd.Flags().Bool("files", false, "Also delete the component directory and files")
 }
 
-func runDelete(cmd *cobra.Command, args []string) error {
-	// Show help if no subcommand is provided
-	return cmd.Help()
-}
-
 func runDeleteService(cmd *cobra.Command, args []string) error {
 	// Find project root and load manifest
 	projectRoot, manifest, err := findProjectRootAndLoadManifest()
@@ -128,7 +143,7 @@ func runDeleteService(cmd *cobra.Command, args []string) error {
 
 	// Validate service exists
 	if _, exists := manifest.Services[serviceName]; !exists {
-		return fmt.Errorf("service '%s' not found in workbench.yaml", serviceName)
+		return newNotFoundError("service '%s' not found in workbench.yaml", serviceName)
 	}
 
 	// Confirm deletion
@@ -137,11 +152,14 @@ func runDeleteService(cmd *cobra.Command, args []string) error {
 	}
 
 	// Delete service
-	if err := deleteService(manifest, serviceName, projectRoot, deleteFiles); err != nil {
+	trashed, err := deleteService(manifest, serviceName, projectRoot, deleteFiles)
+	if err != nil {
 		return fmt.Errorf("failed to delete service: %w", err)
 	}
 
-	printDeleteSuccessMessage("service", serviceName, deleteFiles)
+	printDeleteSuccessMessage("service", serviceName, trashed)
+	offerDecisionRecord(projectRoot, fmt.Sprintf("Remove %s service", serviceName),
+		fmt.Sprintf("Removed the '%s' service from workbench.yaml.", serviceName))
 	return nil
 }
 
@@ -166,7 +184,7 @@ func runDeleteComponent(cmd *cobra.Command, args []string) error {
 
 	// Validate component exists
 	if _, exists := manifest.Components[componentName]; !exists {
-		return fmt.Errorf("component '%s' not found in workbench.yaml", componentName)
+		return newNotFoundError("component '%s' not found in workbench.yaml", componentName)
 	}
 
 	// Confirm deletion
@@ -175,11 +193,14 @@ func runDeleteComponent(cmd *cobra.Command, args []string) error {
 	}
 
 	// Delete component
-	if err := deleteComponent(manifest, componentName, projectRoot, deleteFiles); err != nil {
+	trashed, err := deleteComponent(manifest, componentName, projectRoot, deleteFiles)
+	if err != nil {
 		return fmt.Errorf("failed to delete component: %w", err)
 	}
 
-	printDeleteSuccessMessage("component", componentName, deleteFiles)
+	printDeleteSuccessMessage("component", componentName, trashed)
+	offerDecisionRecord(projectRoot, fmt.Sprintf("Remove %s component", componentName),
+		fmt.Sprintf("Removed the '%s' component from workbench.yaml.", componentName))
 	return nil
 }
 
@@ -199,7 +220,7 @@ func runDeleteResource(cmd *cobra.Command, args []string) error {
 	// Parse service.resource format
 	parts := strings.Split(resourceName, ".")
 	if len(parts) != 2 {
-		return fmt.Errorf("resource name must be in format 'service.resource' (e.g., 'backend.database')")
+		return newValidationError("resource name must be in format 'service.resource' (e.g., 'backend.database')")
 	}
 
 	serviceName := parts[0]
@@ -207,11 +228,11 @@ func runDeleteResource(cmd *cobra.Command, args []string) error {
 
 	// Validate service and resource exist
 	if _, exists := manifest.Services[serviceName]; !exists {
-		return fmt.Errorf("service '%s' not found in workbench.yaml", serviceName)
+		return newNotFoundError("service '%s' not found in workbench.yaml", serviceName)
 	}
 
 	if _, exists := manifest.Services[serviceName].Resources[resourceNameOnly]; !exists {
-		return fmt.Errorf("resource '%s' not found in service '%s'", resourceNameOnly, serviceName)
+		return newNotFoundError("resource '%s' not found in service '%s'", resourceNameOnly, serviceName)
 	}
 
 	// Confirm deletion
@@ -224,7 +245,7 @@ func runDeleteResource(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to delete resource: %w", err)
 	}
 
-	printDeleteSuccessMessage("resource", resourceName, false)
+	printDeleteSuccessMessage("resource", resourceName, "")
 	return nil
 }
 
@@ -243,14 +264,13 @@ func getServiceNameFromArgs(args []string, manifest *manifestPkg.WorkbenchManife
 		return "", fmt.Errorf("no services found in workbench.yaml")
 	}
 
-	var selectedService string
-	prompt := &survey.Select{
+	selectedService, err := prompter.Select(prompt.Question{
+		Name:    "service",
 		Message: "Which service would you like to delete?",
 		Options: serviceNames,
 		Help:    "Select the service to delete from your project",
-	}
-
-	if err := survey.AskOne(prompt, &selectedService); err != nil {
+	})
+	if err != nil {
 		return "", fmt.Errorf("failed to get service selection: %w", err)
 	}
 
@@ -272,14 +292,13 @@ func getComponentNameFromArgs(args []string, manifest *manifestPkg.WorkbenchMani
 		return "", fmt.Errorf("no components found in workbench.yaml")
 	}
 
-	var selectedComponent string
-	prompt := &survey.Select{
+	selectedComponent, err := prompter.Select(prompt.Question{
+		Name:    "component",
 		Message: "Which component would you like to delete?",
 		Options: componentNames,
 		Help:    "Select the component to delete from your project",
-	}
-
-	if err := survey.AskOne(prompt, &selectedComponent); err != nil {
+	})
+	if err != nil {
 		return "", fmt.Errorf("failed to get component selection: %w", err)
 	}
 
@@ -303,14 +322,13 @@ func getResourceNameFromArgs(args []string, manifest *manifestPkg.WorkbenchManif
 		return "", fmt.Errorf("no resources found in workbench.yaml")
 	}
 
-	var selectedResource string
-	prompt := &survey.Select{
+	selectedResource, err := prompter.Select(prompt.Question{
+		Name:    "resource",
 		Message: "Which resource would you like to delete?",
 		Options: resourceOptions,
 		Help:    "Select the resource to delete from your project",
-	}
-
-	if err := survey.AskOne(prompt, &selectedResource); err != nil {
+	})
+	if err != nil {
 		return "", fmt.Errorf("failed to get resource selection: %w", err)
 	}
 
@@ -320,93 +338,150 @@ func getResourceNameFromArgs(args []string, manifest *manifestPkg.WorkbenchManif
 func confirmDeletion(entityType, name string, deleteFiles bool) error {
 	var message string
 	if deleteFiles {
-		message = fmt.Sprintf("Are you sure you want to delete %s '%s' and ALL its files? This action cannot be undone.", entityType, name)
+		message = fmt.Sprintf("Are you sure you want to delete %s '%s' and ALL its files?", entityType, name)
 	} else {
 		message = fmt.Sprintf("Are you sure you want to delete %s '%s' from workbench.yaml? (This will not delete any files)", entityType, name)
 	}
 
-	var confirmed bool
-	prompt := &survey.Confirm{
+	confirmed, err := prompter.Confirm(prompt.Question{
+		Name:    "confirm",
 		Message: message,
 		Help:    "This action will remove the entry from workbench.yaml",
-	}
-
-	if err := survey.AskOne(prompt, &confirmed); err != nil {
+	})
+	if err != nil {
 		return fmt.Errorf("failed to get confirmation: %w", err)
 	}
 
 	if !confirmed {
-		return fmt.Errorf("deletion cancelled")
+		return newCancelledError("deletion cancelled")
 	}
 
 	// Additional confirmation for file deletion
 	if deleteFiles {
-		var finalConfirmed bool
-		finalPrompt := &survey.Confirm{
-			Message: fmt.Sprintf("⚠️  FINAL WARNING: This will permanently delete the %s directory and ALL files. Are you absolutely sure?", entityType),
-			Help:    "This action is irreversible and will delete all files in the directory",
-		}
-
-		if err := survey.AskOne(finalPrompt, &finalConfirmed); err != nil {
+		finalConfirmed, err := prompter.Confirm(prompt.Question{
+			Name:    "confirmDeleteFiles",
+			Message: fmt.Sprintf("⚠️  FINAL WARNING: This will delete the %s directory and ALL files. Are you absolutely sure?", entityType),
+			Help:    fmt.Sprintf("The directory is moved to %s and permanently removed after %d days", filepath.ToSlash(trash.Dir), int(trash.Retention.Hours()/24)),
+		})
+		if err != nil {
 			return fmt.Errorf("failed to get final confirmation: %w", err)
 		}
 
 		if !finalConfirmed {
-			return fmt.Errorf("file deletion cancelled")
+			return newCancelledError("file deletion cancelled")
 		}
 	}
 
 	return nil
 }
 
-func deleteService(manifest *manifestPkg.WorkbenchManifest, serviceName, projectRoot string, deleteFiles bool) error {
+// deleteService removes a service from the manifest and, with deleteFiles,
+// moves its directory to the trash. It returns where the directory went.
+func deleteService(manifest *manifestPkg.WorkbenchManifest, serviceName, projectRoot string, deleteFiles bool) (string, error) {
 	// Get service path before deletion
-	service := manifest.Services[serviceName]
-	servicePath := service.Path
+	servicePath := manifest.Services[serviceName].Path
+	if deleteFiles && servicePath != "" {
+		if err := checkDeletablePath(manifest, projectRoot, serviceName, servicePath); err != nil {
+			return "", err
+		}
+	}
 
 	// Remove from manifest
 	delete(manifest.Services, serviceName)
+	manifest.RemoveFromGroups(serviceName)
+	manifest.RemoveFromEnvironments(serviceName)
+	manifest.RemoveFromDependencies(serviceName)
 
 	// Save updated manifest
 	if err := saveWorkbenchManifest(manifest, projectRoot); err != nil {
-		return fmt.Errorf("failed to save workbench.yaml: %w", err)
+		return "", fmt.Errorf("failed to save workbench.yaml: %w", err)
 	}
 
 	// Delete files if requested
 	if deleteFiles && servicePath != "" {
-		fullPath := filepath.Join(projectRoot, servicePath)
-		if err := os.RemoveAll(fullPath); err != nil {
-			return fmt.Errorf("failed to delete service directory: %w", err)
-		}
+		return trashDirectory(projectRoot, servicePath, "service")
 	}
 
-	return nil
+	return "", nil
 }
 
-func deleteComponent(manifest *manifestPkg.WorkbenchManifest, componentName, projectRoot string, deleteFiles bool) error {
+// deleteComponent removes a component from the manifest and, with
+// deleteFiles, moves its directory to the trash. It returns where the
+// directory went.
+func deleteComponent(manifest *manifestPkg.WorkbenchManifest, componentName, projectRoot string, deleteFiles bool) (string, error) {
 	// Get component path before deletion
-	component := manifest.Components[componentName]
-	componentPath := component.Path
+	componentPath := manifest.Components[componentName].Path
+	if deleteFiles && componentPath != "" {
+		if err := checkDeletablePath(manifest, projectRoot, componentName, componentPath); err != nil {
+			return "", err
+		}
+	}
 
 	// Remove from manifest
 	delete(manifest.Components, componentName)
+	manifest.RemoveFromGroups(componentName)
+	manifest.RemoveFromEnvironments(componentName)
+	manifest.RemoveFromDependencies(componentName)
 
 	// Save updated manifest
 	if err := saveWorkbenchManifest(manifest, projectRoot); err != nil {
-		return fmt.Errorf("failed to save workbench.yaml: %w", err)
+		return "", fmt.Errorf("failed to save workbench.yaml: %w", err)
 	}
 
 	// Delete files if requested
 	if deleteFiles && componentPath != "" {
-		fullPath := filepath.Join(projectRoot, componentPath)
-		if err := os.RemoveAll(fullPath); err != nil {
-			return fmt.Errorf("failed to delete component directory: %w", err)
-		}
+		return trashDirectory(projectRoot, componentPath, "component")
+	}
+
+	return "", nil
+}
+
+// checkDeletablePath refuses to delete the directory of the named service or
+// component unless it lies inside the project and holds no other service or
+// component. It runs before workbench.yaml is changed, so a refused deletion
+// leaves the project as it was.
+func checkDeletablePath(manifest *manifestPkg.WorkbenchManifest, projectRoot, name, dir string) error {
+	if err := trash.CheckContained(workspaceFS, projectRoot, dir); err != nil {
+		return newValidationError("refusing to delete the files of '%s': %v", name, err)
 	}
 
+	clean := path.Clean(filepath.ToSlash(dir))
+	paths := make(map[string]string)
+	for other, service := range manifest.Services {
+		paths[other] = service.Path
+	}
+	for other, component := range manifest.Components {
+		paths[other] = component.Path
+	}
+	for other, otherPath := range paths {
+		if other == name || otherPath == "" {
+			continue
+		}
+		if strings.HasPrefix(path.Clean(filepath.ToSlash(otherPath))+"/", clean+"/") {
+			return newValidationError("refusing to delete the files of '%s': '%s' also holds '%s'", name, dir, other)
+		}
+	}
 	return nil
 }
 
+// trashDirectory moves a deleted directory, when it exists, to the trash and
+// purges expired deletions. The manifest entry is already gone, so purging
+// failures are only reported.
+func trashDirectory(projectRoot, dir, entityType string) (string, error) {
+	if !filesystem.Exists(workspaceFS, filepath.Join(projectRoot, dir)) {
+		return "", nil
+	}
+	now := trashClock()
+	moved, err := trash.Move(workspaceFS, projectRoot, dir, now)
+	if err != nil {
+		return "", fmt.Errorf("failed to delete %s directory: %w", entityType, err)
+	}
+	if _, err := trash.Purge(workspaceFS, projectRoot, now); err != nil {
+		fmt.Printf("⚠️  Could not purge old deletions: %v\n", err)
+	}
+	return moved, nil
+}
+
 func deleteResource(manifest *manifestPkg.WorkbenchManifest, serviceName, resourceName, projectRoot string) error {
 	// Remove from manifest
 	delete(manifest.Services[serviceName].Resources, resourceName)
@@ -419,7 +494,7 @@ func deleteResource(manifest *manifestPkg.WorkbenchManifest, serviceName, resour
 	return nil
 }
 
-func printDeleteSuccessMessage(entityType, name string, deletedFiles bool) {
+func printDeleteSuccessMessage(entityType, name, trashed string) {
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Printf("✅ Successfully deleted %s '%s'!\n", entityType, name)
 	fmt.Println(strings.Repeat("=", 60))
@@ -427,8 +502,8 @@ func printDeleteSuccessMessage(entityType, name string, deletedFiles bool) {
 	fmt.Println("\n📁 Updated files:")
 	fmt.Println("  • workbench.yaml - Removed entry")
 
-	if deletedFiles {
-		fmt.Println("  • Deleted directory and all files")
+	if trashed != "" {
+		fmt.Printf("  • Moved the directory to %s; it is removed for good after %d days\n", filepath.ToSlash(trashed), int(trash.Retention.Hours()/24))
 	} else {
 		fmt.Println("  • Files were preserved (use --files to delete them)")
 	}
@@ -436,6 +511,7 @@ func printDeleteSuccessMessage(entityType, name string, deletedFiles bool) {
 	fmt.Println("\n💡 Tips:")
 	fmt.Println("  • Run 'om ls' to see the updated project structure")
 	fmt.Println("  • Run 'om compose' to regenerate configuration files")
+	fmt.Println("  • Run 'om prune' to remove the containers and volumes of deleted entries")
 	fmt.Println("  • The deletion only affects workbench.yaml by default")
 
 	fmt.Println("\n🎉 Deletion completed successfully!")
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/gencache"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+	"github.com/jashkahar/open-workbench-platform/internal/trash"
+	"github.com/spf13/cobra"
+)
+
+// generatedArtifacts are the files and directories the generators write at
+// the project root, whether or not the generation cache recorded them
+var generatedArtifacts = []string{"docker-compose.yml", ".env", ".env.example", "terraform", "k8s"}
+
+// deleteEntry is a service, component, or resource picked for deletion
+type deleteEntry struct {
+	kind string // service, component, or resource
+	name string // resources are named service.resource
+}
+
+// label returns how the entry is listed in the selection
+func (e deleteEntry) label() string {
+	return e.kind + " " + e.name
+}
+
+// runDelete deletes the generated files with --all-generated, and otherwise
+// asks which entries of workbench.yaml to delete
+func runDelete(cmd *cobra.Command, args []string) error {
+	allGenerated, err := cmd.Flags().GetBool("all-generated")
+	if err != nil {
+		return fmt.Errorf("failed to get all-generated flag: %w", err)
+	}
+	if allGenerated {
+		return runDeleteGenerated()
+	}
+
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+
+	deleteFiles, err := cmd.Flags().GetBool("files")
+	if err != nil {
+		return fmt.Errorf("failed to get files flag: %w", err)
+	}
+
+	entries, err := selectDeleteEntries(manifest)
+	if err != nil {
+		return err
+	}
+
+	if err := confirmBulkDeletion(entries, deleteFiles); err != nil {
+		return err
+	}
+
+	trashed, err := deleteEntries(manifest, entries, projectRoot, deleteFiles)
+	if err != nil {
+		return err
+	}
+
+	printBulkDeleteSuccessMessage(entries, trashed)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.name)
+	}
+	offerDecisionRecord(projectRoot, fmt.Sprintf("Remove %s", strings.Join(names, ", ")),
+		fmt.Sprintf("Removed %s from workbench.yaml.", strings.Join(names, ", ")))
+	return nil
+}
+
+// selectDeleteEntries lists every service, component, and resource of the
+// manifest in a checkbox list and returns the ones picked
+func selectDeleteEntries(manifest *manifestPkg.WorkbenchManifest) ([]deleteEntry, error) {
+	var entries []deleteEntry
+	for _, name := range slices.Sorted(maps.Keys(manifest.Services)) {
+		entries = append(entries, deleteEntry{kind: "service", name: name})
+	}
+	for _, name := range slices.Sorted(maps.Keys(manifest.Components)) {
+		entries = append(entries, deleteEntry{kind: "component", name: name})
+	}
+	for _, serviceName := range slices.Sorted(maps.Keys(manifest.Services)) {
+		for _, resourceName := range slices.Sorted(maps.Keys(manifest.Services[serviceName].Resources)) {
+			entries = append(entries, deleteEntry{kind: "resource", name: serviceName + "." + resourceName})
+		}
+	}
+	if len(entries) == 0 {
+		return nil, newNotFoundError("no services, components, or resources found in workbench.yaml")
+	}
+
+	options := make([]string, 0, len(entries))
+	byLabel := make(map[string]deleteEntry, len(entries))
+	for _, entry := range entries {
+		options = append(options, entry.label())
+		byLabel[entry.label()] = entry
+	}
+
+	selected, err := prompter.MultiSelect(prompt.Question{
+		Name:    "entries",
+		Message: "Which entries would you like to delete?",
+		Options: options,
+		Help:    "Select any number of services, components, and resources; resources of deleted services go with them",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get selection: %w", err)
+	}
+	if len(selected) == 0 {
+		return nil, newCancelledError("nothing selected to delete")
+	}
+
+	picked := make([]deleteEntry, 0, len(selected))
+	for _, label := range selected {
+		picked = append(picked, byLabel[label])
+	}
+	return picked, nil
+}
+
+// confirmBulkDeletion asks once for all the entries picked, with the same
+// questions as deleting a single entry
+func confirmBulkDeletion(entries []deleteEntry, deleteFiles bool) error {
+	fmt.Println("\n🗑️  Selected for deletion:")
+	for _, entry := range entries {
+		fmt.Printf("  • %s\n", entry.label())
+	}
+
+	message := fmt.Sprintf("Are you sure you want to delete these %d entries from workbench.yaml? (This will not delete any files)", len(entries))
+	if deleteFiles {
+		message = fmt.Sprintf("Are you sure you want to delete these %d entries and ALL their files?", len(entries))
+	}
+	confirmed, err := prompter.Confirm(prompt.Question{
+		Name:    "confirm",
+		Message: message,
+		Help:    "This action will remove the entries from workbench.yaml",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get confirmation: %w", err)
+	}
+	if !confirmed {
+		return newCancelledError("deletion cancelled")
+	}
+
+	if deleteFiles {
+		finalConfirmed, err := prompter.Confirm(prompt.Question{
+			Name:    "confirmDeleteFiles",
+			Message: "⚠️  FINAL WARNING: This will delete the directories of the selected services and components and ALL their files. Are you absolutely sure?",
+			Help:    fmt.Sprintf("The directories are moved to %s and permanently removed after %d days", filepath.ToSlash(trash.Dir), int(trash.Retention.Hours()/24)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get final confirmation: %w", err)
+		}
+		if !finalConfirmed {
+			return newCancelledError("file deletion cancelled")
+		}
+	}
+	return nil
+}
+
+// deleteEntries removes the entries from the manifest in a single save and,
+// with deleteFiles, moves the directories of the deleted services and
+// components to the trash. Every directory is checked before workbench.yaml
+// changes; a directory may hold other entries only when they are deleted
+// too. It returns where the directories went.
+func deleteEntries(manifest *manifestPkg.WorkbenchManifest, entries []deleteEntry, projectRoot string, deleteFiles bool) ([]string, error) {
+	remaining := manifest.Clone()
+	var dirs []string
+	dirNames := make(map[string]string)
+	for _, entry := range entries {
+		var dir string
+		switch entry.kind {
+		case "service":
+			dir = manifest.Services[entry.name].Path
+			delete(remaining.Services, entry.name)
+		case "component":
+			dir = manifest.Components[entry.name].Path
+			delete(remaining.Components, entry.name)
+		}
+		if deleteFiles && dir != "" {
+			dirs = append(dirs, dir)
+			dirNames[dir] = entry.name
+		}
+	}
+	for _, dir := range dirs {
+		if err := checkDeletablePath(remaining, projectRoot, dirNames[dir], dir); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, entry := range entries {
+		switch entry.kind {
+		case "service":
+			delete(manifest.Services, entry.name)
+			manifest.RemoveFromGroups(entry.name)
+			manifest.RemoveFromEnvironments(entry.name)
+			manifest.RemoveFromDependencies(entry.name)
+		case "component":
+			delete(manifest.Components, entry.name)
+			manifest.RemoveFromGroups(entry.name)
+			manifest.RemoveFromEnvironments(entry.name)
+			manifest.RemoveFromDependencies(entry.name)
+		case "resource":
+			serviceName, resourceName, _ := strings.Cut(entry.name, ".")
+			if service, exists := manifest.Services[serviceName]; exists {
+				delete(service.Resources, resourceName)
+			}
+		}
+	}
+	if err := saveWorkbenchManifest(manifest, projectRoot); err != nil {
+		return nil, fmt.Errorf("failed to save workbench.yaml: %w", err)
+	}
+
+	// Parents sort first, so nested directories go with them
+	sort.Slice(dirs, func(i, j int) bool {
+		return path.Clean(filepath.ToSlash(dirs[i])) < path.Clean(filepath.ToSlash(dirs[j]))
+	})
+	var trashed []string
+	for _, dir := range dirs {
+		moved, err := trashDirectory(projectRoot, dir, "entry")
+		if err != nil {
+			return trashed, err
+		}
+		if moved != "" {
+			trashed = append(trashed, moved)
+		}
+	}
+	return trashed, nil
+}
+
+// printBulkDeleteSuccessMessage reports the entries deleted and where their
+// directories went
+func printBulkDeleteSuccessMessage(entries []deleteEntry, trashed []string) {
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Printf("✅ Successfully deleted %d entries!\n", len(entries))
+	fmt.Println(strings.Repeat("=", 60))
+
+	fmt.Println("\n📁 Updated files:")
+	fmt.Println("  • workbench.yaml - Removed entries:")
+	for _, entry := range entries {
+		fmt.Printf("    - %s\n", entry.label())
+	}
+
+	if len(trashed) > 0 {
+		for _, moved := range trashed {
+			fmt.Printf("  • Moved a directory to %s\n", filepath.ToSlash(moved))
+		}
+		fmt.Printf("  • Moved directories are removed for good after %d days\n", int(trash.Retention.Hours()/24))
+	} else {
+		fmt.Println("  • Files were preserved (use --files to delete them)")
+	}
+
+	fmt.Println("\n💡 Tips:")
+	fmt.Println("  • Run 'om ls' to see the updated project structure")
+	fmt.Println("  • Run 'om compose' to regenerate configuration files")
+	fmt.Println("  • Run 'om prune' to remove the containers and volumes of deleted entries")
+
+	fmt.Println("\n🎉 Deletion completed successfully!")
+}
+
+// runDeleteGenerated removes the files the generators wrote, leaving
+// workbench.yaml and the source of every service and component untouched
+func runDeleteGenerated() error {
+	projectRoot, _, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+
+	artifacts := generatedFiles(projectRoot)
+	if len(artifacts) == 0 {
+		fmt.Println("✨ No generated files to delete")
+		return nil
+	}
+
+	fmt.Println("\n🗑️  Generated files:")
+	for _, artifact := range artifacts {
+		fmt.Printf("  • %s\n", artifact)
+	}
+	confirmed, err := prompter.Confirm(prompt.Question{
+		Name:    "confirm",
+		Message: fmt.Sprintf("Are you sure you want to delete these %d generated files?", len(artifacts)),
+		Help:    "Run 'om compose' to generate them again; workbench.yaml and source files are kept",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get confirmation: %w", err)
+	}
+	if !confirmed {
+		return newCancelledError("deletion cancelled")
+	}
+
+	for _, artifact := range artifacts {
+		if err := workspaceFS.RemoveAll(filepath.Join(projectRoot, filepath.FromSlash(artifact))); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", artifact, err)
+		}
+	}
+	if err := workspaceFS.RemoveAll(filepath.Join(projectRoot, gencache.FileName)); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", filepath.ToSlash(gencache.FileName), err)
+	}
+
+	fmt.Printf("\n✅ Deleted %d generated files\n", len(artifacts))
+	fmt.Println("💡 Run 'om compose' to generate them again")
+	return nil
+}
+
+// generatedFiles returns the existing generated files of the project,
+// relative to its root: the generators' fixed outputs plus every file the
+// generation cache recorded. .gitignore is kept, since om only adds lines to
+// it, and so is anything outside the project or under .om/.
+func generatedFiles(projectRoot string) []string {
+	seen := make(map[string]bool)
+	var artifacts []string
+	for _, path := range append(append([]string(nil), generatedArtifacts...), gencache.Load(workspaceFS, projectRoot).Outputs()...) {
+		if seen[path] || path == ".gitignore" || trash.CheckContained(workspaceFS, projectRoot, path) != nil {
+			continue
+		}
+		seen[path] = true
+		if filesystem.Exists(workspaceFS, filepath.Join(projectRoot, filepath.FromSlash(path))) {
+			artifacts = append(artifacts, path)
+		}
+	}
+	sort.Strings(artifacts)
+	return artifacts
+}
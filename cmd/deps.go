@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+	"github.com/spf13/cobra"
+)
+
+// depsRunner runs the package managers and git. Tests replace it to check
+// dependencies without npm, pip, or a git repository.
+var depsRunner deps.Runner = deps.ExecRunner
+
+var depsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "Manage the dependencies of the project's services",
+}
+
+var depsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "List outdated dependencies across every service",
+	Long: `List the outdated dependencies of every service in workbench.yaml.
+
+Each service is checked with its ecosystem's own tooling: 'npm outdated' for
+services with a package.json and 'pip list --outdated' for services with a
+requirements.txt. Run it after installing dependencies, since both tools
+compare against what is installed. Results are grouped by how far behind each
+dependency is: major, minor, or patch releases.
+
+With --apply, each service with outdated dependencies gets its own git
+branch, om/deps-<service>, with its package.json or requirements.txt bumped
+to the latest versions. The current branch is left unchanged. Lock files are
+not updated; run npm install on the branch to refresh them.
+
+Examples:
+  # Check every service
+  om deps check
+
+  # Check only the frontend and backend
+  om deps check --service frontend --service backend
+
+  # Open a branch per service with the bumps
+  om deps check --apply`,
+	Args: cobra.NoArgs,
+	RunE: runDepsCheck,
+}
+
+// initDepsCommand registers the deps command and its subcommands
+func initDepsCommand() {
+	depsCmd.AddCommand(depsCheckCmd)
+	if rootCmd != nil {
+		rootCmd.AddCommand(depsCmd)
+	}
+
+	depsCheckCmd.Flags().StringSlice("service", nil, "Only check these services")
+	depsCheckCmd.Flags().Bool("apply", false, "Open a git branch per service with its dependencies bumped")
+}
+
+// runDepsCheck checks the selected services and optionally applies the bumps
+func runDepsCheck(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+
+	names, err := cmd.Flags().GetStringSlice("service")
+	if err != nil {
+		return fmt.Errorf("failed to get service flag: %w", err)
+	}
+	if len(names) == 0 {
+		for name := range manifest.Services {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var found []warnings.Warning
+	outdated := make(map[string][]deps.Outdated)
+	var all []deps.Outdated
+	for _, name := range names {
+		service, exists := manifest.Services[name]
+		if !exists {
+			return newNotFoundError("service '%s' not found in workbench.yaml", name)
+		}
+		if service.UsesImage() || service.Path == "" {
+			continue
+		}
+
+		dir := filepath.Join(projectRoot, service.Path)
+		for _, ecosystem := range deps.Detect(workspaceFS, dir) {
+			fmt.Printf("🔍 Checking %s (%s)...\n", name, ecosystem)
+			results, err := deps.Check(workspaceFS, depsRunner, name, dir, ecosystem)
+			if err != nil {
+				found = append(found, warnings.New("services."+name, "could not check %s dependencies: %s", ecosystem, err))
+				continue
+			}
+			outdated[name] = append(outdated[name], results...)
+			all = append(all, results...)
+		}
+	}
+
+	fmt.Println()
+	if len(all) == 0 {
+		fmt.Println("✅ All dependencies are up to date")
+		return reportWarnings(cmd, found)
+	}
+	printOutdatedTable(all)
+
+	apply, _ := cmd.Flags().GetBool("apply")
+	if !apply {
+		fmt.Println()
+		fmt.Println("💡 Run 'om deps check --apply' to open a branch per service with the bumps")
+		return reportWarnings(cmd, found)
+	}
+
+	if err := openDependencyBranches(projectRoot, manifest.Services, outdated); err != nil {
+		return err
+	}
+	return reportWarnings(cmd, found)
+}
+
+// printOutdatedTable prints outdated dependencies grouped by severity
+func printOutdatedTable(all []deps.Outdated) {
+	services := make(map[string]bool)
+	for _, dependency := range all {
+		services[dependency.Service] = true
+	}
+	fmt.Printf("📦 %d outdated dependencies across %d service(s)\n", len(all), len(services))
+
+	headings := map[deps.Severity]string{
+		deps.SeverityMajor:   "🔴 Major updates (may break)",
+		deps.SeverityMinor:   "🟡 Minor updates",
+		deps.SeverityPatch:   "🟢 Patch updates",
+		deps.SeverityUnknown: "⚪ Other updates",
+	}
+	for _, severity := range deps.Severities {
+		var group []deps.Outdated
+		for _, dependency := range all {
+			if dependency.Severity == severity {
+				group = append(group, dependency)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].Service != group[j].Service {
+				return group[i].Service < group[j].Service
+			}
+			return group[i].Package < group[j].Package
+		})
+
+		fmt.Println()
+		fmt.Println(headings[severity])
+		table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "  SERVICE\tECOSYSTEM\tPACKAGE\tCURRENT\tLATEST")
+		for _, dependency := range group {
+			fmt.Fprintf(table, "  %s\t%s\t%s\t%s\t%s\n",
+				dependency.Service, dependency.Ecosystem, dependency.Package, dependency.Current, dependency.Latest)
+		}
+		table.Flush()
+	}
+}
+
+// openDependencyBranches commits the bumps of each service on its own
+// branch and returns to the current branch
+func openDependencyBranches(projectRoot string, services map[string]manifestPkg.Service, outdated map[string][]deps.Outdated) error {
+	status, err := depsRunner(projectRoot, "git", "status", "--porcelain")
+	if err != nil {
+		return newValidationError("--apply needs the project to be a git repository: %w", err)
+	}
+	if strings.TrimSpace(string(status)) != "" {
+		return newValidationError("--apply needs a clean working tree; commit or stash your changes first")
+	}
+	head, err := depsRunner(projectRoot, "git", "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to find the current branch: %w", err)
+	}
+	original := strings.TrimSpace(string(head))
+
+	names := make([]string, 0, len(outdated))
+	for name := range outdated {
+		if len(outdated[name]) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	fmt.Println()
+	for _, name := range names {
+		branch := "om/deps-" + name
+		if _, err := depsRunner(projectRoot, "git", "checkout", "-b", branch); err != nil {
+			return fmt.Errorf("failed to create branch %s: %w", branch, err)
+		}
+
+		committed, err := commitDependencyBumps(projectRoot, name, services[name].Path, outdated[name])
+		if _, checkoutErr := depsRunner(projectRoot, "git", "checkout", original); checkoutErr != nil && err == nil {
+			err = fmt.Errorf("failed to return to %s: %w", original, checkoutErr)
+		}
+		if err != nil {
+			return err
+		}
+		if !committed {
+			// Nothing in the manifests pins the outdated versions
+			if _, err := depsRunner(projectRoot, "git", "branch", "-D", branch); err != nil {
+				return fmt.Errorf("failed to delete empty branch %s: %w", branch, err)
+			}
+			fmt.Printf("ℹ️  %s: no pinned versions to bump in its manifests\n", name)
+			continue
+		}
+		fmt.Printf("🌿 Opened %s with %d bumped dependencies\n", branch, len(outdated[name]))
+	}
+	fmt.Printf("✅ Back on %s. Review each branch, refresh lock files, and open a pull request.\n", original)
+	return nil
+}
+
+// commitDependencyBumps bumps a service's manifests and commits them on the
+// current branch. It reports whether there was anything to commit.
+func commitDependencyBumps(projectRoot, name, servicePath string, outdated []deps.Outdated) (bool, error) {
+	changed, err := deps.Apply(workspaceFS, filepath.Join(projectRoot, servicePath), outdated)
+	if err != nil {
+		return false, fmt.Errorf("failed to bump %s dependencies: %w", name, err)
+	}
+	if len(changed) == 0 {
+		return false, nil
+	}
+
+	addArgs := []string{"add"}
+	for _, file := range changed {
+		addArgs = append(addArgs, filepath.ToSlash(filepath.Join(servicePath, file)))
+	}
+	if _, err := depsRunner(projectRoot, "git", addArgs...); err != nil {
+		return false, fmt.Errorf("failed to stage %s dependency bumps: %w", name, err)
+	}
+	message := fmt.Sprintf("Bump %s dependencies", name)
+	if _, err := depsRunner(projectRoot, "git", "commit", "-m", message); err != nil {
+		return false, fmt.Errorf("failed to commit %s dependency bumps: %w", name, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/devrun"
+	"github.com/spf13/cobra"
+)
+
+// devRunner runs the docker compose commands of om dev that finish, and
+// devStreamer the ones that follow logs. Tests replace them.
+var (
+	devRunner   deps.Runner     = deps.ExecRunner
+	devStreamer devrun.Streamer = devrun.ExecStreamer
+)
+
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Run the whole project locally and follow its logs",
+	Long: `Run the whole project locally with Docker Compose and follow its logs.
+
+The command brings docker-compose.yml up to date like 'om compose --target
+docker' (skipping generation when nothing changed), builds and starts every
+service with 'docker compose up', and then follows the logs of all services
+in one stream. Each line is prefixed with the name of its service, in a color
+of its own when the output is a terminal.
+
+Press Ctrl+C to stop following the logs; the services are then stopped and
+removed with 'docker compose down'. Named volumes, such as database data, are
+kept. With --keep, the services are left running instead.
+
+Examples:
+  # Start everything and follow the logs
+  om dev
+
+  # Only the services of the "backend" group
+  om dev --group backend
+
+  # Leave the services running after Ctrl+C
+  om dev --keep`,
+	Args: cobra.NoArgs,
+	RunE: runDev,
+}
+
+// initDevCommand registers the dev command with the root command
+func initDevCommand() {
+	if rootCmd != nil {
+		rootCmd.AddCommand(devCmd)
+	}
+
+	devCmd.Flags().String("env", "", "Environment to generate for, leaving out the services it excludes")
+	devCmd.Flags().String("group", "", "Only run the services of this group from workbench.yaml")
+	devCmd.Flags().Bool("force", false, "Regenerate docker-compose.yml even when nothing changed since the last run")
+	devCmd.Flags().Bool("keep", false, "Leave the services running when om dev stops")
+}
+
+// runDev generates docker-compose.yml, starts the project, and follows its
+// logs until interrupted
+func runDev(cmd *cobra.Command, args []string) error {
+	if err := composeProject(cmd, func(*cobra.Command) (string, error) { return "docker", nil }); err != nil {
+		return err
+	}
+	projectDir, err := workingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	var checker interface{ GetDockerComposeCommand() string } = compose.NewPrerequisiteCheckerFor(containerEngine())
+	if dockerPrerequisites != nil {
+		checker = dockerPrerequisites
+	}
+	project := devrun.Compose{
+		Command: checker.GetDockerComposeCommand(),
+		Dir:     projectDir,
+		File:    "docker-compose.yml",
+		Run:     devRunner,
+		Stream:  devStreamer,
+	}
+
+	fmt.Println("\n🚀 Building and starting services...")
+	if err := project.Up(); err != nil {
+		return &exitCodeError{code: ExitCodeExternalTool, err: fmt.Errorf("failed to start services: %w", err)}
+	}
+	services, err := project.Services()
+	if err != nil {
+		return &exitCodeError{code: ExitCodeExternalTool, err: fmt.Errorf("failed to list services: %w", err)}
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("📜 Following the logs of %d service(s) (press Ctrl+C to stop)\n\n", len(services))
+	followErr := project.Follow(ctx, devrun.NewOutput(os.Stdout, services, useColor()), services)
+	stop()
+
+	keep, _ := cmd.Flags().GetBool("keep")
+	if keep {
+		fmt.Printf("\n✋ Services are still running; stop them with: %s -f %s down\n", project.Command, project.File)
+	} else {
+		fmt.Println("\n🛑 Stopping services...")
+		if err := project.Down(); err != nil {
+			return &exitCodeError{code: ExitCodeExternalTool, err: fmt.Errorf("failed to stop services: %w", err)}
+		}
+		fmt.Println("✅ Services stopped; volumes keep their data")
+	}
+	if followErr != nil {
+		return &exitCodeError{code: ExitCodeExternalTool, err: fmt.Errorf("failed to follow logs: %w", followErr)}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/generator/docker"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the container engine and tooling om relies on",
+	Long: `Check the container engine and tooling the project runs on, and list the
+known limitations of the engine that was detected.
+
+om runs on Docker, on Docker in a Colima VM, and on Podman. The engine is
+told apart by DOCKER_HOST, a docker command that is Podman in disguise, the
+active docker context, and the binaries on the PATH. 'om compose' and
+'om dev' adjust to it: Podman projects are run with podman-compose or
+'podman compose', and Traefik gateways mount the Podman socket.
+
+Examples:
+  # Show the detected engine and check that it works
+  om doctor`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+// initDoctorCommand registers the doctor command
+func initDoctorCommand() {
+	if rootCmd != nil {
+		rootCmd.AddCommand(doctorCmd)
+	}
+}
+
+// runDoctor prints the detected container engine, checks its tooling, and
+// lists its known limitations
+func runDoctor(cmd *cobra.Command, args []string) error {
+	engine := containerEngine()
+	var checker docker.PrerequisiteChecker = compose.NewPrerequisiteCheckerFor(engine)
+	if dockerPrerequisites != nil {
+		checker = dockerPrerequisites
+	}
+
+	fmt.Println("🩺 Checking the container engine...")
+	fmt.Printf("🐳 Engine:  %s\n", engine.Name)
+	fmt.Printf("   Compose: %s\n", engine.Compose)
+	fmt.Printf("   Socket:  %s\n", engine.Socket)
+	if env := hostEnvironment(); env != "" {
+		fmt.Printf("   Host:    %s\n", env)
+	}
+
+	if len(engine.Limitations) > 0 {
+		fmt.Printf("\n⚠️  Known limitations of %s:\n", engine.Name)
+		for _, limitation := range engine.Limitations {
+			fmt.Printf("  • %s\n", limitation)
+		}
+	}
+
+	fmt.Println()
+	if err := checker.CheckAllPrerequisites(); err != nil {
+		return &exitCodeError{code: ExitCodeExternalTool, err: err}
+	}
+	fmt.Printf("✅ %s and %s are ready\n", engine.Name, engine.Compose)
+	return nil
+}
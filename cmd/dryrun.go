@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/textdiff"
+	"github.com/spf13/cobra"
+)
+
+// dryRunAnnotation marks a command, and every command below it, as
+// supporting --dry-run: Annotations: map[string]string{dryRunAnnotation: "true"}.
+// Every write of such a command must go through workspaceFS.
+const dryRunAnnotation = "dryRun"
+
+// dryRun records the changes of a command run with --dry-run instead of
+// making them. It is nil when the command writes for real.
+var dryRun *filesystem.DryRunFS
+
+// dryRunBase is the workspace dryRun records changes against
+var dryRunBase filesystem.FS
+
+// startDryRun routes the writes of a command run with --dry-run into a
+// recorder. Commands that are not marked with dryRunAnnotation refuse the
+// flag rather than run for real.
+func startDryRun(cmd *cobra.Command) error {
+	enabled, _ := cmd.Flags().GetBool("dry-run")
+	if !enabled {
+		return nil
+	}
+	supported := false
+	for c := cmd; c != nil; c = c.Parent() {
+		if _, marked := c.Annotations[dryRunAnnotation]; marked {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return newValidationError("'%s' does not support --dry-run", cmd.CommandPath())
+	}
+
+	dryRunBase = workspaceFS
+	dryRun = filesystem.NewDryRunFS(workspaceFS)
+	workspaceFS = dryRun
+	fmt.Println("🔍 Dry run: nothing is written to disk")
+	return nil
+}
+
+// finishDryRun prints what a successful dry run would have changed and
+// restores the workspace
+func finishDryRun(err error) {
+	if dryRun == nil {
+		return
+	}
+	recorded := dryRun
+	workspaceFS = dryRunBase
+	dryRun, dryRunBase = nil, nil
+	if err == nil {
+		printDryRunPreview(recorded, workspaceFS)
+	}
+}
+
+// printDryRunPreview prints the files and directories a dry run created,
+// modified, and deleted compared to base, with a diff for every modified
+// file. A new directory is shown once with the number of files in it.
+func printDryRunPreview(recorded *filesystem.DryRunFS, base filesystem.FS) {
+	var lines, diffs []string
+	seen := make(map[string]bool)
+	newDirs := make(map[string]int)
+	var newDirOrder []string
+
+	for _, op := range recorded.Operations() {
+		if seen[op.Path] {
+			continue
+		}
+		seen[op.Path] = true
+		existed := filesystem.Exists(base, op.Path)
+		exists := filesystem.Exists(recorded, op.Path)
+
+		switch {
+		case op.Type == filesystem.OpRemove && existed && !exists:
+			info, err := base.Stat(op.Path)
+			lines = append(lines, fmt.Sprintf("  - delete  %s", previewPath(op.Path, err == nil && info.IsDir())))
+		case op.Type == filesystem.OpMkdir && !existed && exists:
+			top := newAncestor(base, op.Path)
+			if _, found := newDirs[top]; !found {
+				newDirs[top] = 0
+				newDirOrder = append(newDirOrder, top)
+			}
+		case op.Type == filesystem.OpWrite && !existed && exists:
+			if top := newAncestor(base, filepath.Dir(op.Path)); top != "" {
+				if _, found := newDirs[top]; !found {
+					newDirOrder = append(newDirOrder, top)
+				}
+				newDirs[top]++
+			} else {
+				lines = append(lines, fmt.Sprintf("  + create  %s", previewPath(op.Path, false)))
+			}
+		case op.Type == filesystem.OpWrite && existed && exists:
+			oldData, _ := base.ReadFile(op.Path)
+			newData, _ := recorded.ReadFile(op.Path)
+			if string(oldData) == string(newData) {
+				continue
+			}
+			name := previewPath(op.Path, false)
+			lines = append(lines, fmt.Sprintf("  ~ modify  %s", name))
+			diffs = append(diffs, textdiff.Unified("a/"+name, "b/"+name, string(oldData), string(newData)))
+		}
+	}
+
+	var created []string
+	for _, dir := range newDirOrder {
+		created = append(created, fmt.Sprintf("  + create  %s (%d file(s))", previewPath(dir, true), newDirs[dir]))
+	}
+	lines = append(created, lines...)
+
+	if len(lines) == 0 {
+		fmt.Println("🔍 Dry run: no files would change")
+		return
+	}
+	fmt.Println("🔍 Dry run: without --dry-run, om would")
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	for _, diff := range diffs {
+		fmt.Println()
+		fmt.Print(diff)
+		if !strings.HasSuffix(diff, "\n") {
+			fmt.Println()
+		}
+	}
+}
+
+// newAncestor returns the outermost directory of path, path included, that
+// does not exist in base, or "" when path exists
+func newAncestor(base filesystem.FS, path string) string {
+	top := ""
+	for current := filepath.Clean(path); current != "." && current != filepath.Dir(current); current = filepath.Dir(current) {
+		if filesystem.Exists(base, current) {
+			break
+		}
+		top = current
+	}
+	return top
+}
+
+// previewPath returns path relative to the current directory for the dry
+// run preview, with a trailing slash for directories
+func previewPath(path string, dir bool) string {
+	if cwd, err := workingDir(); err == nil && filepath.IsAbs(path) {
+		path = relativePath(cwd, path)
+	}
+	path = filepath.ToSlash(path)
+	if dir {
+		path += "/"
+	}
+	return path
+}
@@ -0,0 +1,2992 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"maps"
+	mathrand "math/rand"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/experiments"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/generator/docker"
+	"github.com/jashkahar/open-workbench-platform/internal/hooks"
+	"github.com/jashkahar/open-workbench-platform/internal/infrastate"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/onboarding"
+	"github.com/jashkahar/open-workbench-platform/internal/ports"
+	"github.com/jashkahar/open-workbench-platform/internal/report"
+	"github.com/jashkahar/open-workbench-platform/internal/smoke"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+	"github.com/jashkahar/open-workbench-platform/internal/testutil"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// fakeDockerPrerequisites satisfies the Docker tooling check without Docker installed
+type fakeDockerPrerequisites struct{}
+
+func (fakeDockerPrerequisites) CheckAllPrerequisites() error    { return nil }
+func (fakeDockerPrerequisites) GetDockerComposeCommand() string { return "docker compose" }
+
+// e2eWorkspace sets up an in-memory workspace, the default fake template
+// catalog, and a fake Docker check for the duration of a test
+func e2eWorkspace(t *testing.T) *filesystem.MemFS {
+	t.Helper()
+	memFS := filesystem.NewMemFS()
+	withWorkspaceFS(t, memFS, ".")
+	withTemplatesFS(t, testutil.DefaultCatalog())
+
+	original := dockerPrerequisites
+	dockerPrerequisites = fakeDockerPrerequisites{}
+	t.Cleanup(func() { dockerPrerequisites = original })
+
+	originalPortAvailable := portAvailable
+	portAvailable = func(int) bool { return true }
+	t.Cleanup(func() { portAvailable = originalPortAvailable })
+
+	originalHost := hostEnvironment
+	hostEnvironment = func() string { return templating.EnvironmentNative }
+	t.Cleanup(func() { hostEnvironment = originalHost })
+
+	originalEngine := containerEngine
+	containerEngine = compose.DockerEngine
+	t.Cleanup(func() { containerEngine = originalEngine })
+
+	// Generated passwords end up in .env and the golden snapshots of it
+	originalSecretsRand := secretsRand
+	secretsRand = mathrand.New(mathrand.NewSource(1))
+	t.Cleanup(func() { secretsRand = originalSecretsRand })
+
+	originalFreeSpace, originalOS := diskFreeSpace, targetOS
+	diskFreeSpace = func(string) (uint64, error) { return 10 << 30, nil }
+	targetOS = "linux"
+	t.Cleanup(func() { diskFreeSpace, targetOS = originalFreeSpace, originalOS })
+
+	originalConfigPath := dockerConfigPath
+	dockerConfigPath = func() (string, error) { return filepath.Join("home", ".docker", "config.json"), nil }
+	t.Cleanup(func() { dockerConfigPath = originalConfigPath })
+
+	originalUserConfig := userConfigPath
+	userConfigPath = func() (string, error) { return filepath.Join("home", ".om", "config.yaml"), nil }
+	t.Cleanup(func() { userConfigPath = originalUserConfig })
+
+	// Generation reports and the trash carry the time; a fixed clock keeps
+	// them, and the golden snapshots that include them, stable
+	originalReportClock, originalTrashClock := reportClock, trashClock
+	reportClock = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	trashClock = reportClock
+	t.Cleanup(func() { reportClock, trashClock = originalReportClock, originalTrashClock })
+	return memFS
+}
+
+// runOM runs the om command tree in-process with the given arguments and answers
+func runOM(t *testing.T, answers map[string]interface{}, args ...string) error {
+	t.Helper()
+	withPrompter(t, testutil.NewPrompter(t, answers))
+
+	root := setupRootCommand()
+	resetFlags(root)
+	t.Cleanup(func() { resetFlags(root) })
+	root.SetArgs(args)
+	_, err := root.ExecuteC()
+	finishDryRun(err)
+	stopDebugLog(err)
+	return err
+}
+
+// resetFlags restores every flag in the command tree to its default, since
+// the commands are package-level values shared between runs
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if f.Changed {
+			if err := f.Value.Set(f.DefValue); err != nil && f.Value.Type() == "stringToString" {
+				// Maps cannot be emptied through Set, so the flag gets a fresh value
+				fresh := pflag.NewFlagSet(f.Name, pflag.ContinueOnError)
+				fresh.StringToString(f.Name, nil, f.Usage)
+				f.Value = fresh.Lookup(f.Name).Value
+			}
+			f.Changed = false
+		}
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, child := range cmd.Commands() {
+		resetFlags(child)
+	}
+}
+
+// chdir points the in-memory workspace's working directory at dir
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	original := workingDir
+	workingDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { workingDir = original })
+}
+
+func TestIntegrationInitCommand(t *testing.T) {
+	memFS := e2eWorkspace(t)
+
+	err := runOM(t, map[string]interface{}{
+		"projectName": "demo",
+		"template":    "demo-service - A demo service",
+		"serviceName": "web",
+		"ServiceName": "web",
+		"IncludeDocs": true,
+	}, "init")
+	if err != nil {
+		t.Fatalf("om init failed: %v", err)
+	}
+
+	testutil.AssertGolden(t, "e2e/init", testutil.Snapshot(memFS, "demo"))
+}
+
+func TestEndToEndProjectLifecycle(t *testing.T) {
+	memFS := e2eWorkspace(t)
+
+	// om init
+	err := runOM(t, map[string]interface{}{
+		"projectName": "demo",
+		"template":    "demo-service - A demo service",
+		"serviceName": "web",
+		"ServiceName": "web",
+		"IncludeDocs": true,
+	}, "init")
+	if err != nil {
+		t.Fatalf("om init failed: %v", err)
+	}
+	chdir(t, "demo")
+
+	// om add service
+	err = runOM(t, map[string]interface{}{
+		"serviceName": "api",
+		"template":    "demo-service - A demo service",
+		"ServiceName": "api",
+		"IncludeDocs": false,
+	}, "add", "service")
+	if err != nil {
+		t.Fatalf("om add service failed: %v", err)
+	}
+
+	// om add resource
+	err = runOM(t, map[string]interface{}{
+		"version":      "15",
+		"databaseName": "app",
+		"username":     "postgres",
+		"password":     "secret",
+	}, "add", "resource", "--service", "api", "--type", "postgres-db", "--name", "db")
+	if err != nil {
+		t.Fatalf("om add resource failed: %v", err)
+	}
+
+	// om compose
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("om compose failed: %v", err)
+	}
+	testutil.AssertGolden(t, "e2e/compose", testutil.Snapshot(memFS, "demo"))
+
+	// om delete service
+	err = runOM(t, map[string]interface{}{
+		"confirm":            true,
+		"confirmDeleteFiles": true,
+	}, "delete", "service", "web", "--files")
+	if err != nil {
+		t.Fatalf("om delete service failed: %v", err)
+	}
+
+	if filesystem.Exists(memFS, filepath.Join("demo", "web")) {
+		t.Error("expected web/ to be deleted")
+	}
+	testutil.AssertGolden(t, "e2e/delete", testutil.Snapshot(memFS, "demo"))
+}
+
+func TestEndToEndNestedScaffoldGuards(t *testing.T) {
+	memFS := e2eWorkspace(t)
+
+	err := runOM(t, map[string]interface{}{
+		"projectName": "demo",
+		"template":    "demo-service - A demo service",
+		"serviceName": "web",
+		"ServiceName": "web",
+		"IncludeDocs": true,
+	}, "init")
+	if err != nil {
+		t.Fatalf("om init failed: %v", err)
+	}
+
+	// om init inside a service of the project
+	chdir(t, filepath.Join("demo", "web"))
+	err = runOM(t, nil, "init")
+	if exitCodeForError(err) != ExitCodeValidation || !strings.Contains(err.Error(), "inside the project in demo") {
+		t.Fatalf("expected om init inside a project to fail, got %d (%v)", exitCodeForError(err), err)
+	}
+	if filesystem.Exists(memFS, filepath.Join("demo", "web", "nested")) {
+		t.Error("expected no nested project to be created")
+	}
+
+	// om add service into a directory om generates
+	chdir(t, "demo")
+	err = runOM(t, nil, "add", "service", "--name", "api", "--template", "demo-service", "--path", "k8s/api")
+	if exitCodeForError(err) != ExitCodeValidation || !strings.Contains(err.Error(), "which om generates") {
+		t.Fatalf("expected a service in k8s/ to be refused, got %d (%v)", exitCodeForError(err), err)
+	}
+
+	// om add service into a project nested in this one
+	if err := memFS.MkdirAll(filepath.Join("demo", "tools", "legacy"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "tools", "legacy", "workbench.yaml"), []byte("apiVersion: openworkbench.io/v1alpha1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = runOM(t, nil, "add", "service", "--name", "api", "--template", "demo-service", "--path", "tools/legacy/api")
+	if exitCodeForError(err) != ExitCodeValidation || !strings.Contains(err.Error(), "own workbench.yaml") {
+		t.Fatalf("expected a service in a nested project to be refused, got %d (%v)", exitCodeForError(err), err)
+	}
+	if filesystem.Exists(memFS, filepath.Join("demo", "tools", "legacy", "api")) {
+		t.Error("expected no service to be scaffolded into the nested project")
+	}
+}
+
+func TestEndToEndAddDirectProjectDefaults(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	err := runOM(t, map[string]interface{}{
+		"projectName": "demo",
+		"template":    "demo-service - A demo service",
+		"serviceName": "web",
+		"ServiceName": "web",
+		"IncludeDocs": false,
+	}, "init")
+	if err != nil {
+		t.Fatalf("om init failed: %v", err)
+	}
+	chdir(t, "demo")
+
+	// Templates that require the project name and owner get them from the
+	// project without --params
+	owned := testutil.Template{
+		Manifest: `{"name": "owned-service", "description": "Needs a project", "parameters": [
+    {"name": "ProjectName", "prompt": "Project?", "type": "string", "required": true},
+    {"name": "Owner", "prompt": "Owner?", "type": "string", "required": true}
+  ]}`,
+		Files: map[string]string{"OWNERS": "{{ .ProjectName }} by {{ .Owner }}\n"},
+	}
+	withTemplatesFS(t, testutil.NewCatalog(map[string]testutil.Template{"owned-service": owned}))
+
+	if err := runOM(t, nil, "add", "service", "--name", "api", "--template", "owned-service"); err != nil {
+		t.Fatalf("om add service failed: %v", err)
+	}
+	if err := runOM(t, nil, "add", "component", "--name", "proxy", "--template", "owned-service"); err != nil {
+		t.Fatalf("om add component failed: %v", err)
+	}
+	for _, dir := range []string{"api", "proxy"} {
+		data, err := memFS.ReadFile(filepath.Join("demo", dir, "OWNERS"))
+		if err != nil {
+			t.Fatalf("expected %s to be scaffolded: %v", dir, err)
+		}
+		if string(data) != "demo by Open Workbench\n" {
+			t.Errorf("expected the project name and default owner in %s, got %q", dir, data)
+		}
+	}
+}
+
+func TestEndToEndInitNonInteractive(t *testing.T) {
+	memFS := e2eWorkspace(t)
+
+	// A missing detail is an error with --yes instead of a prompt
+	err := runOM(t, nil, "init", "--service", "api", "--template", "demo-service", "--yes")
+	if exitCodeForError(err) != ExitCodeValidation || !strings.Contains(err.Error(), "--name is required") {
+		t.Fatalf("expected --yes without --name to fail, got %d (%v)", exitCodeForError(err), err)
+	}
+	err = runOM(t, nil, "init", "--name", "demo", "--service", "api", "--template", "demo-service", "--yes")
+	if exitCodeForError(err) != ExitCodeValidation || !strings.Contains(err.Error(), "required parameter missing: ServiceName") {
+		t.Fatalf("expected a missing required parameter to fail, got %d (%v)", exitCodeForError(err), err)
+	}
+	if filesystem.Exists(memFS, "demo") {
+		t.Fatal("expected nothing to be created by a failed om init")
+	}
+
+	// IncludeDocs takes its default
+	err = runOM(t, nil, "init", "--name", "demo", "--service", "api", "--template", "demo-service", "--params", "ServiceName=api", "--yes")
+	if err != nil {
+		t.Fatalf("om init --yes failed: %v", err)
+	}
+	manifest, err := manifestPkg.Load(memFS, filepath.Join("demo", "workbench.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load workbench.yaml: %v", err)
+	}
+	if manifest.Metadata.Name != "demo" || manifest.Services["api"].Template != "demo-service" {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+	if !filesystem.Exists(memFS, filepath.Join("demo", "api", "docs", "index.md")) {
+		t.Error("expected IncludeDocs to default to true")
+	}
+}
+
+func TestEndToEndDryRun(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	initAnswers := map[string]interface{}{
+		"projectName": "demo",
+		"template":    "demo-service - A demo service",
+		"serviceName": "web",
+		"ServiceName": "web",
+		"IncludeDocs": true,
+	}
+
+	if err := runOM(t, initAnswers, "init", "--dry-run"); err != nil {
+		t.Fatalf("om init --dry-run failed: %v", err)
+	}
+	if filesystem.Exists(memFS, "demo") {
+		t.Fatal("expected om init --dry-run to create nothing")
+	}
+	if err := runOM(t, initAnswers, "init"); err != nil {
+		t.Fatalf("om init failed: %v", err)
+	}
+	chdir(t, "demo")
+	snapshot := testutil.Snapshot(memFS, "demo")
+
+	runs := []struct {
+		answers map[string]interface{}
+		args    []string
+	}{
+		{nil, []string{"add", "service", "--name", "api", "--template", "demo-service", "--params", "ServiceName=api", "--dry-run"}},
+		{map[string]interface{}{"version": "7.2", "password": "secret"}, []string{"add", "resource", "--service", "web", "--type", "redis-cache", "--name", "cache", "--dry-run"}},
+		{nil, []string{"compose", "--target", "docker", "--dry-run"}},
+		{map[string]interface{}{"confirm": true, "confirmDeleteFiles": true}, []string{"delete", "service", "web", "--files", "--dry-run"}},
+	}
+	for _, run := range runs {
+		if err := runOM(t, run.answers, run.args...); err != nil {
+			t.Fatalf("om %s failed: %v", strings.Join(run.args, " "), err)
+		}
+		if got := testutil.Snapshot(memFS, "demo"); !bytes.Equal(got, snapshot) {
+			t.Fatalf("expected om %s to change nothing", strings.Join(run.args, " "))
+		}
+	}
+
+	// Commands that cannot preview their changes refuse the flag
+	err := runOM(t, nil, "dev", "--dry-run")
+	if exitCodeForError(err) != ExitCodeValidation || !strings.Contains(err.Error(), "does not support --dry-run") {
+		t.Fatalf("expected om dev --dry-run to be refused, got %d (%v)", exitCodeForError(err), err)
+	}
+}
+
+func TestEndToEndDeleteCancelled(t *testing.T) {
+	memFS := e2eWorkspace(t)
+
+	err := runOM(t, map[string]interface{}{
+		"projectName": "demo",
+		"template":    "demo-service - A demo service",
+		"serviceName": "web",
+		"ServiceName": "web",
+		"IncludeDocs": true,
+	}, "init")
+	if err != nil {
+		t.Fatalf("om init failed: %v", err)
+	}
+	chdir(t, "demo")
+	before := testutil.Snapshot(memFS, "demo")
+
+	err = runOM(t, map[string]interface{}{"confirm": false}, "delete", "service", "web")
+	if exitCodeForError(err) != ExitCodeCancelled {
+		t.Fatalf("expected cancellation exit code, got %d (%v)", exitCodeForError(err), err)
+	}
+	if after := testutil.Snapshot(memFS, "demo"); string(after) != string(before) {
+		t.Errorf("cancelled delete modified the project:\n%s", after)
+	}
+}
+
+func TestEndToEndDeleteFilesGuardrails(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  rogue:\n    path: ../outside\n  apps:\n    path: ./apps\n  api:\n    path: ./apps/api\n"
+	for _, dir := range []string{"outside", filepath.Join("demo", "apps", "api")} {
+		if err := memFS.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path, content := range map[string]string{
+		filepath.Join("demo", "workbench.yaml"):         manifest,
+		filepath.Join("outside", "keep.txt"):            "precious",
+		filepath.Join("demo", "apps", "api", "main.go"): "package main\n",
+	} {
+		if err := memFS.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdir(t, "demo")
+	confirm := map[string]interface{}{"confirm": true, "confirmDeleteFiles": true}
+
+	// Manifests with paths outside the project are refused on load
+	for _, args := range [][]string{{"compose"}, {"delete", "service", "rogue", "--files"}} {
+		err := runOM(t, nil, args...)
+		if exitCodeForError(err) != ExitCodeValidation || !strings.Contains(err.Error(), "services.rogue.path") {
+			t.Errorf("expected om %s to refuse services.rogue.path, got %v", strings.Join(args, " "), err)
+		}
+	}
+	if !filesystem.Exists(memFS, filepath.Join("outside", "keep.txt")) {
+		t.Fatal("expected files outside the project to survive")
+	}
+
+	// Directories holding other services are refused
+	manifest = strings.Replace(manifest, "  rogue:\n    path: ../outside\n", "", 1)
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runOM(t, confirm, "delete", "service", "apps", "--files"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected deleting the files of apps to be refused, got %v", err)
+	}
+	kept, _ := manifestPkg.NewLoader(memFS).Load(filepath.Join("demo", "workbench.yaml"))
+	if _, exists := kept.Services["apps"]; !exists {
+		t.Error("expected a refused deletion to leave workbench.yaml unchanged")
+	}
+
+	// Deleted directories are moved to the trash
+	if err := runOM(t, confirm, "delete", "service", "api", "--files"); err != nil {
+		t.Fatalf("om delete service failed: %v", err)
+	}
+	if filesystem.Exists(memFS, filepath.Join("demo", "apps", "api")) {
+		t.Error("expected apps/api to be deleted")
+	}
+	data, err := memFS.ReadFile(filepath.Join("demo", ".om", "trash", "20260102-030405", "apps", "api", "main.go"))
+	if err != nil || string(data) != "package main\n" {
+		t.Errorf("expected apps/api in the trash, got %q, %v", data, err)
+	}
+}
+
+func TestEndToEndDeleteBulk(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  apps:\n    path: ./apps\n  api:\n    path: ./apps/api\n    resources:\n      db:\n        type: postgres-db\n  web:\n    path: ./web\n    resources:\n      cache:\n        type: redis-cache\n"
+	for _, dir := range []string{filepath.Join("demo", "apps", "api"), filepath.Join("demo", "web"), filepath.Join("demo", "terraform")} {
+		if err := memFS.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path, content := range map[string]string{
+		filepath.Join("demo", "workbench.yaml"):         manifest,
+		filepath.Join("demo", "apps", "api", "main.go"): "package main\n",
+		filepath.Join("demo", "terraform", "main.tf"):   "# generated\n",
+		filepath.Join("demo", ".gitignore"):             ".env\n",
+	} {
+		if err := memFS.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdir(t, "demo")
+
+	// Several entries are deleted at once, with directories that hold only deleted entries
+	err := runOM(t, map[string]interface{}{
+		"entries":            []string{"service apps", "service api", "resource web.cache"},
+		"confirm":            true,
+		"confirmDeleteFiles": true,
+	}, "delete", "--files")
+	if err != nil {
+		t.Fatalf("om delete failed: %v", err)
+	}
+	kept, err := manifestPkg.NewLoader(memFS).Load(filepath.Join("demo", "workbench.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kept.Services) != 1 || len(kept.Services["web"].Resources) != 0 {
+		t.Errorf("expected only web without resources to remain, got %+v", kept.Services)
+	}
+	if filesystem.Exists(memFS, filepath.Join("demo", "apps")) || !filesystem.Exists(memFS, filepath.Join("demo", "web")) {
+		t.Error("expected apps/ to be deleted and web/ to be kept")
+	}
+	if !filesystem.Exists(memFS, filepath.Join("demo", ".om", "trash", "20260102-030405", "apps", "api", "main.go")) {
+		t.Error("expected apps/ in the trash")
+	}
+
+	// Generated files go, source and .gitignore stay
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("om compose failed: %v", err)
+	}
+	if err := runOM(t, map[string]interface{}{"confirm": true}, "delete", "--all-generated"); err != nil {
+		t.Fatalf("om delete --all-generated failed: %v", err)
+	}
+	for _, gone := range []string{"docker-compose.yml", ".env", ".env.example", "terraform", filepath.Join(".om", "cache", "generate.yaml")} {
+		if filesystem.Exists(memFS, filepath.Join("demo", gone)) {
+			t.Errorf("expected %s to be deleted", gone)
+		}
+	}
+	for _, kept := range []string{"workbench.yaml", ".gitignore", "web"} {
+		if !filesystem.Exists(memFS, filepath.Join("demo", kept)) {
+			t.Errorf("expected %s to be kept", kept)
+		}
+	}
+}
+
+func TestEndToEndADR(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n  worker:\n    path: ./worker\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+	original := adrClock
+	adrClock = func() time.Time { return time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { adrClock = original })
+
+	// Projects without records are never asked
+	if err := runOM(t, map[string]interface{}{"confirm": true}, "delete", "service", "worker"); err != nil {
+		t.Fatalf("om delete service failed: %v", err)
+	}
+	if filesystem.Exists(memFS, filepath.Join("demo", "docs", "adr")) {
+		t.Error("expected no records in a project that does not keep them")
+	}
+
+	if err := runOM(t, nil, "adr", "new", "Use", "PostgreSQL"); err != nil {
+		t.Fatalf("om adr new failed: %v", err)
+	}
+	if !filesystem.Exists(memFS, filepath.Join("demo", "docs", "adr", "0001-use-postgresql.md")) {
+		t.Fatal("expected the first record to be created")
+	}
+
+	// Structural changes now offer to record a decision
+	err := runOM(t, map[string]interface{}{"confirm": true, "recordDecision": true}, "delete", "service", "api")
+	if err != nil {
+		t.Fatalf("om delete service failed: %v", err)
+	}
+	record, err := memFS.ReadFile(filepath.Join("demo", "docs", "adr", "0002-remove-api-service.md"))
+	if err != nil {
+		t.Fatalf("expected a record of the deletion: %v", err)
+	}
+	if !strings.Contains(string(record), "Removed the 'api' service from workbench.yaml.") {
+		t.Errorf("expected the change as the record's context, got:\n%s", record)
+	}
+}
+
+func TestEndToEndHooks(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	var events []hooks.Event
+	var commands, projectCommands, posted []string
+	original := hookDispatcher
+	hookDispatcher = &hooks.Dispatcher{
+		Run: func(dir, command string, env []string, stdin []byte) error {
+			var event hooks.Event
+			if err := json.Unmarshal(stdin, &event); err != nil {
+				t.Errorf("expected the event as JSON on stdin: %v", err)
+			}
+			events = append(events, event)
+			commands = append(commands, command)
+			return nil
+		},
+		RunProject: func(dir, command string, env []string, stdin []byte) error {
+			var event hooks.Event
+			if err := json.Unmarshal(stdin, &event); err != nil {
+				t.Errorf("expected the event as JSON on stdin: %v", err)
+			}
+			events = append(events, event)
+			projectCommands = append(projectCommands, command)
+			return nil
+		},
+		Post: func(url string, body []byte) error {
+			posted = append(posted, url)
+			return errors.New("unreachable")
+		},
+	}
+	t.Cleanup(func() { hookDispatcher = original })
+
+	// om init only runs in a directory without visible files
+	userConfigDir := filepath.Join(".home", ".om")
+	userConfigPath = func() (string, error) { return filepath.Join(userConfigDir, "config.yaml"), nil }
+	userConfig := "hooks:\n  - events: [scaffold-complete]\n    command: ./notify.sh\n  - events: [compose-generated]\n    webhook: https://hooks.example.com/om\n"
+	if err := memFS.MkdirAll(userConfigDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join(userConfigDir, "config.yaml"), []byte(userConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runOM(t, map[string]interface{}{
+		"projectName": "demo",
+		"template":    "demo-service - A demo service",
+		"serviceName": "web",
+		"ServiceName": "web",
+		"IncludeDocs": false,
+	}, "init")
+	if err != nil {
+		t.Fatalf("om init failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Name != hooks.EventScaffoldComplete || events[0].Project != "demo" ||
+		events[0].Data["kind"] != "project" || events[0].Data["service"] != "web" {
+		t.Fatalf("expected a scaffold-complete event for the new project, got %+v", events)
+	}
+	chdir(t, "demo")
+
+	// A failing webhook is reported without failing the command, and the
+	// project's commands and webhooks are skipped until the user approves them
+	projectHooks := "hooks:\n  - events: [compose-generated]\n    command: make notify\n  - events: [compose-generated]\n    webhook: https://collector.example.com/om\n"
+	if err := memFS.MkdirAll(filepath.Join("demo", ".om"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", ".om", "hooks.yaml"), []byte(projectHooks), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("om compose failed: %v", err)
+	}
+	if len(projectCommands) != 0 || len(commands) != 1 {
+		t.Fatalf("expected the unapproved project hook to be skipped, ran %v and %v", commands, projectCommands)
+	}
+	if len(posted) != 1 || posted[0] != "https://hooks.example.com/om" {
+		t.Fatalf("expected only the user's webhook to be called, posted to %v", posted)
+	}
+
+	// Approved project commands run sandboxed, never in the user's environment
+	if err := runOM(t, map[string]interface{}{"trustHook": true}, "compose", "--target", "docker", "--force"); err != nil {
+		t.Fatalf("om compose failed: %v", err)
+	}
+	if err := runOM(t, nil, "compose", "--target", "docker", "--force"); err != nil {
+		t.Fatalf("om compose failed: %v", err)
+	}
+	if len(projectCommands) != 2 || projectCommands[0] != "make notify" || len(commands) != 1 {
+		t.Errorf("expected the approved project hook to run sandboxed twice, got %v and %v", commands, projectCommands)
+	}
+	if len(posted) != 5 || posted[2] != "https://collector.example.com/om" {
+		t.Errorf("expected the approved project webhook to be called with the user's, posted to %v", posted)
+	}
+	last := events[len(events)-1]
+	if last.Name != hooks.EventComposeGenerated || last.Data["target"] != "docker" {
+		t.Errorf("expected the project's compose-generated hook to get the event, got %+v", last)
+	}
+}
+
+func TestEndToEndLifecycleHooks(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    image: nginx:1.27\n    port: 80\nhooks:\n  preCompose:\n    - ./scripts/generate-clients.sh\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	var ran []string
+	var failure error
+	original := lifecycleRunner
+	lifecycleRunner = func(dir, command string, env []string, stdin []byte) error {
+		ran = append(ran, command)
+		if !strings.Contains(strings.Join(env, "\n"), "OM_TARGET=docker") {
+			t.Errorf("expected the compose target in the hook environment, got %v", env)
+		}
+		return failure
+	}
+	t.Cleanup(func() { lifecycleRunner = original })
+
+	// Unapproved hooks are skipped
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("om compose failed: %v", err)
+	}
+	if len(ran) != 0 {
+		t.Fatalf("expected the unapproved hook to be skipped, ran %v", ran)
+	}
+
+	// An approval is remembered until the commands change
+	if err := runOM(t, map[string]interface{}{"trustHook": true}, "compose", "--target", "docker", "--force"); err != nil {
+		t.Fatalf("om compose failed: %v", err)
+	}
+	if err := runOM(t, nil, "compose", "--target", "docker", "--force"); err != nil {
+		t.Fatalf("om compose failed: %v", err)
+	}
+	if len(ran) != 2 || ran[0] != "./scripts/generate-clients.sh" {
+		t.Fatalf("expected the approved hook to run twice, ran %v", ran)
+	}
+
+	// A failing pre hook stops the command
+	failure = errors.New("exit status 1")
+	err := runOM(t, nil, "compose", "--target", "docker", "--force")
+	if err == nil || exitCodeForError(err) != ExitCodeExternalTool || !strings.Contains(err.Error(), "preCompose hook") {
+		t.Fatalf("expected the failing hook to stop om compose, got %v", err)
+	}
+}
+
+func TestEndToEndIncludedServices(t *testing.T) {
+	memFS := e2eWorkspace(t)
+
+	err := runOM(t, map[string]interface{}{
+		"projectName": "demo",
+		"template":    "demo-service - A demo service",
+		"serviceName": "web",
+		"ServiceName": "web",
+		"IncludeDocs": false,
+	}, "init")
+	if err != nil {
+		t.Fatalf("om init failed: %v", err)
+	}
+	chdir(t, "demo")
+
+	// Move a service definition into workbench.d/
+	fragmentPath := filepath.Join("demo", "workbench.d", "billing.yaml")
+	if err := memFS.MkdirAll(filepath.Dir(fragmentPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	fragment := "# Billing team services\nservices:\n    billing:\n        template: demo-service\n        path: billing\n"
+	if err := memFS.WriteFile(fragmentPath, []byte(fragment), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err = runOM(t, map[string]interface{}{
+		"version":  "7.2",
+		"password": "secret",
+	}, "add", "resource", "--service", "billing", "--type", "redis-cache", "--name", "cache")
+	if err != nil {
+		t.Fatalf("om add resource failed: %v", err)
+	}
+
+	testutil.AssertGolden(t, "e2e/includes", testutil.Snapshot(memFS, "demo"))
+}
+
+func TestEndToEndValidate(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    api:\n      spec: openapi.yaml\n  auth:\n    image: registry.internal/auth:1.4.2\n"
+	if err := memFS.MkdirAll(filepath.Join("demo", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	// The Dockerfile and the referenced spec are missing
+	err := runOM(t, nil, "validate")
+	if exitCodeForError(err) != ExitCodeValidation {
+		t.Fatalf("expected validation exit code, got %d (%v)", exitCodeForError(err), err)
+	}
+	if !strings.Contains(err.Error(), "2 problem(s)") {
+		t.Errorf("expected the Dockerfile and spec problems, got %v", err)
+	}
+
+	dockerfile := "FROM node:20\nHEALTHCHECK CMD wget -qO- http://localhost:3000/health\n"
+	if err := memFS.WriteFile(filepath.Join("demo", "api", "Dockerfile"), []byte(dockerfile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := "openapi: 3.0.3\ninfo:\n  title: API\n  version: 1.0.0\npaths: {}\n"
+	if err := memFS.WriteFile(filepath.Join("demo", "api", "openapi.yaml"), []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runOM(t, nil, "validate"); err != nil {
+		t.Fatalf("om validate failed: %v", err)
+	}
+}
+
+func TestEndToEndEnvironmentExclude(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    image: registry.internal/api:1.0\n    port: 8000\n    environment:\n      PAYMENTS_URL: ${services.payments.url}\n  payments:\n    image: stoplight/prism:5\n    port: 4010\n  admin:\n    image: registry.internal/admin:1.0\n    port: 9000\nenvironments:\n  staging:\n    provider: aws\n    include: [admin]\n  prod:\n    provider: aws\n    exclude: [payments]\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	// api depends on the payments service that prod leaves out
+	if err := runOM(t, nil, "validate"); err != nil {
+		t.Fatalf("om validate failed: %v", err)
+	}
+	err := runOM(t, nil, "validate", "--strict")
+	if exitCodeForError(err) != ExitCodeValidation {
+		t.Fatalf("expected the excluded dependency to be a warning, got %d (%v)", exitCodeForError(err), err)
+	}
+
+	if err := runOM(t, nil, "compose", "--target", "docker", "--env", "prod"); err != nil {
+		t.Fatalf("om compose --env prod failed: %v", err)
+	}
+	compose, err := memFS.ReadFile(filepath.Join("demo", "docker-compose.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]bool{"api:": true, "payments:": false, "admin:": false} {
+		if strings.Contains(string(compose), "    "+name+"\n") != want {
+			t.Errorf("expected %s in the prod compose file: %t", name, want)
+		}
+	}
+
+	err = runOM(t, nil, "compose", "--target", "docker", "--env", "qa")
+	if exitCodeForError(err) != ExitCodeValidation {
+		t.Fatalf("expected validation exit code for an unknown environment, got %d (%v)", exitCodeForError(err), err)
+	}
+}
+
+func TestEndToEndValidateStrict(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    resources:\n      db:\n        type: postgres-db\n"
+	if err := memFS.MkdirAll(filepath.Join("demo", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "api", "Dockerfile"), []byte("FROM node:20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	// The unpinned database version is only a warning
+	if err := runOM(t, nil, "validate"); err != nil {
+		t.Fatalf("om validate failed: %v", err)
+	}
+
+	err := runOM(t, nil, "validate", "--strict")
+	if exitCodeForError(err) != ExitCodeValidation {
+		t.Fatalf("expected validation exit code with --strict, got %d (%v)", exitCodeForError(err), err)
+	}
+}
+
+func TestEndToEndGenerateLoadTest(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    port: 8000\n  orders:\n    path: ./orders\n    port: 50051\n    protocol: grpc\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	if err := runOM(t, nil, "generate", "loadtest"); err != nil {
+		t.Fatalf("om generate loadtest failed: %v", err)
+	}
+	for _, path := range []string{"docker-compose.loadtest.yml", "loadtest/api.js", "loadtest/grafana/dashboards/k6.json"} {
+		if !filesystem.Exists(memFS, filepath.Join("demo", filepath.FromSlash(path))) {
+			t.Errorf("expected %s to be generated", path)
+		}
+	}
+	if filesystem.Exists(memFS, filepath.Join("demo", "loadtest", "orders.js")) {
+		t.Error("gRPC services should not get a load test")
+	}
+
+	err := runOM(t, nil, "generate", "loadtest", "--service", "orders")
+	if exitCodeForError(err) != ExitCodeValidation {
+		t.Fatalf("expected validation exit code, got %d (%v)", exitCodeForError(err), err)
+	}
+}
+
+func TestEndToEndGenerateDocs(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    template: fastapi-basic\n    path: ./api\n    port: 8000\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range map[string]string{
+		filepath.Join("demo", "workbench.yaml"): manifest,
+		filepath.Join("demo", "README.md"):      "# Demo\n\nWritten by hand.\n",
+	} {
+		if err := memFS.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdir(t, "demo")
+
+	if err := runOM(t, nil, "generate", "docs"); err != nil {
+		t.Fatalf("om generate docs failed: %v", err)
+	}
+
+	// Regenerating after a manifest change replaces only the managed block
+	manifest += "  web:\n    template: react-typescript\n    path: ./web\n    port: 4173\n"
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runOM(t, nil, "generate", "docs"); err != nil {
+		t.Fatalf("om generate docs failed: %v", err)
+	}
+
+	readme, _ := memFS.ReadFile(filepath.Join("demo", "README.md"))
+	if !strings.HasPrefix(string(readme), "# Demo\n\nWritten by hand.\n") || strings.Count(string(readme), "om:docs:begin") != 1 {
+		t.Errorf("expected one managed block after the hand-written text, got:\n%s", readme)
+	}
+	architecture, _ := memFS.ReadFile(filepath.Join("demo", "ARCHITECTURE.md"))
+	if !strings.Contains(string(architecture), "| web | react-typescript | 4173 | `./web` |") {
+		t.Errorf("expected ARCHITECTURE.md to list the new service, got:\n%s", architecture)
+	}
+}
+
+func TestEndToEndGenerateClients(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    template: fastapi-basic\n    path: ./api\n    port: 8000\n    api:\n      spec: openapi.yaml\n  web:\n    template: react-typescript\n    path: ./web\n    port: 4173\n    consumes: [api]\n"
+	for _, dir := range []string{filepath.Join("demo", "api"), filepath.Join("demo", "web")} {
+		if err := memFS.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path, content := range map[string]string{
+		filepath.Join("demo", "workbench.yaml"):      manifest,
+		filepath.Join("demo", "api", "openapi.yaml"): "openapi: 3.0.3\ninfo:\n  title: API\n  version: 1.0.0\npaths: {}\n",
+		filepath.Join("demo", "web", "package.json"): "{\"name\": \"web\"}\n",
+	} {
+		if err := memFS.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdir(t, "demo")
+
+	var ran []string
+	original := clientRunner
+	clientRunner = func(dir, name string, args ...string) ([]byte, error) {
+		ran = append(ran, filepath.ToSlash(dir)+": "+name+" "+strings.Join(args, " "))
+		return nil, nil
+	}
+	t.Cleanup(func() { clientRunner = original })
+
+	if err := runOM(t, nil, "generate", "clients"); err != nil {
+		t.Fatalf("om generate clients failed: %v", err)
+	}
+	if len(ran) != 1 || ran[0] != "demo/web: npx --yes openapi-typescript ../api/openapi.yaml --output src/clients/api.ts" {
+		t.Errorf("expected openapi-typescript to run in web, ran %v", ran)
+	}
+
+	// The base URL is wired into the consumer and resolved by om compose
+	updated, err := manifestPkg.NewLoader(memFS).Load(filepath.Join("demo", "workbench.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load workbench.yaml: %v", err)
+	}
+	if got := updated.Services["web"].Environment["API_API_URL"]; got != "${services.api.url}" {
+		t.Errorf("expected API_API_URL to reference the api service, got %q", got)
+	}
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("om compose failed: %v", err)
+	}
+	compose, _ := memFS.ReadFile(filepath.Join("demo", "docker-compose.yml"))
+	if !strings.Contains(string(compose), "API_API_URL=http://api:8000") {
+		t.Errorf("expected the api URL in the compose environment, got:\n%s", compose)
+	}
+}
+
+func TestEndToEndAddShared(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  web:\n    template: express-api\n    path: ./web\n    port: 3001\n"
+	if err := memFS.MkdirAll(filepath.Join("demo", "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range map[string]string{
+		filepath.Join("demo", "workbench.yaml"):      manifest,
+		filepath.Join("demo", "web", "package.json"): "{\n  \"name\": \"web\",\n  \"dependencies\": {\n    \"express\": \"^4.18.0\"\n  }\n}\n",
+	} {
+		if err := memFS.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdir(t, "demo")
+
+	if err := runOM(t, nil, "add", "shared", "models", "--language", "typescript", "--service", "web"); err != nil {
+		t.Fatalf("om add shared failed: %v", err)
+	}
+	if _, err := memFS.Stat(filepath.Join("demo", "shared", "models", "src", "index.ts")); err != nil {
+		t.Errorf("expected the library to be scaffolded: %v", err)
+	}
+	pkg, _ := memFS.ReadFile(filepath.Join("demo", "web", "package.json"))
+	if !strings.Contains(string(pkg), `"@demo/models": "file:../shared/models"`) {
+		t.Errorf("expected web to depend on the library, got:\n%s", pkg)
+	}
+
+	updated, err := manifestPkg.NewLoader(memFS).Load(filepath.Join("demo", "workbench.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load workbench.yaml: %v", err)
+	}
+	if library := updated.Services["models"]; !library.IsLibrary() || library.Path != "shared/models" {
+		t.Errorf("expected models to be registered as a library, got %+v", library)
+	}
+	if libraries := updated.Services["web"].Libraries; len(libraries) != 1 || libraries[0] != "models" {
+		t.Errorf("expected web to list the library, got %v", libraries)
+	}
+
+	// The library is a build context of web, not a container of its own
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("om compose failed: %v", err)
+	}
+	compose, _ := memFS.ReadFile(filepath.Join("demo", "docker-compose.yml"))
+	if !strings.Contains(string(compose), "additional_contexts:\n                models: shared/models") {
+		t.Errorf("expected the library as an additional build context, got:\n%s", compose)
+	}
+	if strings.Contains(string(compose), "\n    models:") {
+		t.Errorf("expected no models container, got:\n%s", compose)
+	}
+
+	if err := runOM(t, nil, "add", "shared", "models", "--language", "python"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected a second models library to be rejected, got %v", err)
+	}
+}
+
+func TestEndToEndExperiments(t *testing.T) {
+	e2eWorkspace(t)
+	t.Setenv(experiments.EnvVar, "")
+
+	ran := false
+	gated := &cobra.Command{
+		Use:         "preview",
+		Annotations: map[string]string{experimentAnnotation: "tui"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ran = true
+			return nil
+		},
+	}
+	root := setupRootCommand()
+	root.AddCommand(gated)
+	t.Cleanup(func() { root.RemoveCommand(gated) })
+
+	err := runOM(t, nil, "preview")
+	if exitCodeForError(err) != ExitCodeValidation || ran {
+		t.Fatalf("expected a disabled experiment to stop the command, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "OM_EXPERIMENTS=tui") {
+		t.Errorf("expected the error to explain how to enable the experiment, got %v", err)
+	}
+
+	t.Setenv(experiments.EnvVar, "tui")
+	if err := runOM(t, nil, "preview"); err != nil || !ran {
+		t.Fatalf("expected an enabled experiment to run, got %v", err)
+	}
+}
+
+func TestEndToEndFirstRunSetup(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	t.Setenv(experiments.EnvVar, "")
+	originalInteractive, originalLookPath := firstRunInteractive, toolLookPath
+	firstRunInteractive = func() bool { return true }
+	toolLookPath = func(name string) (string, error) { return "/usr/bin/" + name, nil }
+	t.Cleanup(func() { firstRunInteractive, toolLookPath = originalInteractive, originalLookPath })
+
+	answers := map[string]interface{}{
+		"owner":          "jane@example.com",
+		"packageManager": "pnpm",
+		"telemetry":      false,
+		"cloudProvider":  "gcp",
+		"cloudRegion":    "europe-west1",
+	}
+	if err := runOM(t, answers, "experiments"); err != nil {
+		t.Fatalf("om experiments failed on the first run: %v", err)
+	}
+	configPath := filepath.Join("home", ".om", "config.yaml")
+	profile, found, err := onboarding.Load(memFS, configPath)
+	if err != nil || !found {
+		t.Fatalf("expected the wizard to save a profile, got %v, %v", found, err)
+	}
+	want := onboarding.Profile{Owner: "jane@example.com", PackageManager: "pnpm", Cloud: onboarding.Cloud{Provider: "gcp", Region: "europe-west1"}}
+	if profile != want {
+		t.Errorf("saved profile = %+v, want %+v", profile, want)
+	}
+
+	// Later runs do not ask again
+	if err := runOM(t, nil, "experiments"); err != nil {
+		t.Fatalf("expected no questions once a profile exists, got %v", err)
+	}
+	if answers := profilePreset().Answers("go-api"); answers["Owner"] != "jane@example.com" || answers["PackageManager"] != "pnpm" {
+		t.Errorf("expected the profile to pre-fill template answers, got %v", answers)
+	}
+
+	answers["cloudProvider"] = "none"
+	delete(answers, "cloudRegion")
+	if err := runOM(t, answers, "setup"); err != nil {
+		t.Fatalf("om setup failed: %v", err)
+	}
+	if profile, _, _ := onboarding.Load(memFS, configPath); profile.Cloud != (onboarding.Cloud{Provider: "none"}) {
+		t.Errorf("expected om setup to update the profile, got %+v", profile)
+	}
+}
+
+func TestEndToEndExplain(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    port: 8000\n"
+	if err := memFS.MkdirAll(filepath.Join("demo", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("om compose failed: %v", err)
+	}
+	for _, args := range [][]string{
+		{"explain", "docker-compose.yml"},
+		{"explain", "docker-compose.yml:6"},
+		{"explain", "docker-compose.yml", "--line", "1"},
+	} {
+		if err := runOM(t, nil, args...); err != nil {
+			t.Errorf("om %v failed: %v", args, err)
+		}
+	}
+
+	err := runOM(t, nil, "explain", "docker-compose.yml:999")
+	if exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected validation exit code for a line out of range, got %d (%v)", exitCodeForError(err), err)
+	}
+	err = runOM(t, nil, "explain", "missing.yml:1")
+	if exitCodeForError(err) != ExitCodeNotFound {
+		t.Errorf("expected not-found exit code for a missing file, got %d (%v)", exitCodeForError(err), err)
+	}
+}
+
+func TestEndToEndTemplateTestAll(t *testing.T) {
+	e2eWorkspace(t)
+
+	// The embedded catalog lives in the repository's templates directory
+	withTemplatesFS(t, os.DirFS(".."))
+	if err := runOM(t, nil, "template", "test-all"); err != nil {
+		t.Fatalf("the template catalog has failing combinations: %v", err)
+	}
+
+	withTemplatesFS(t, testutil.NewCatalog(map[string]testutil.Template{
+		"broken-service": {
+			Manifest: `{"name": "broken-service", "description": "Breaks without docs", "parameters": [{"name": "IncludeDocs", "prompt": "Docs?", "type": "boolean", "default": true}]}`,
+			Files:    map[string]string{"package.json": `{"name": "broken"{{ if not .IncludeDocs }},{{ end }}}`},
+		},
+	}))
+	err := runOM(t, nil, "template", "test-all")
+	if exitCodeForError(err) != ExitCodeValidation {
+		t.Fatalf("expected validation exit code, got %d (%v)", exitCodeForError(err), err)
+	}
+
+	withTemplatesFS(t, testutil.NewCatalog(map[string]testutil.Template{
+		"bad-condition": {
+			Manifest: `{"name": "bad-condition", "description": "Condition does not parse", "parameters": [{"name": "IncludeDocs", "prompt": "Docs?", "type": "boolean"}, {"name": "DocsTool", "prompt": "Tool?", "type": "string", "condition": "IncludeDocs == true &&"}]}`,
+			Files:    map[string]string{"README.md": "docs"},
+		},
+	}))
+	err = runOM(t, nil, "template", "test-all")
+	if exitCodeForError(err) != ExitCodeValidation {
+		t.Fatalf("expected validation exit code for a condition that does not parse, got %d (%v)", exitCodeForError(err), err)
+	}
+
+	err = runOM(t, nil, "template", "test-all", "--template", "missing")
+	if exitCodeForError(err) != ExitCodeNotFound {
+		t.Errorf("expected not-found exit code for an unknown template, got %d (%v)", exitCodeForError(err), err)
+	}
+}
+
+func TestEndToEndTemplateDescribe(t *testing.T) {
+	e2eWorkspace(t)
+	withTemplatesFS(t, os.DirFS(".."))
+
+	if err := runOM(t, nil, "template", "describe", "go-api"); err != nil {
+		t.Fatalf("template describe failed: %v", err)
+	}
+	err := runOM(t, nil, "template", "describe", "missing")
+	if exitCodeForError(err) != ExitCodeNotFound {
+		t.Errorf("expected not-found exit code for an unknown template, got %d (%v)", exitCodeForError(err), err)
+	}
+}
+
+func TestRenderParameterHelp(t *testing.T) {
+	got := renderParameterHelp("Set `PORT`, see [docs](https://example.com).\n\n```\nPORT=8080\n```", "  ")
+	want := "  Set PORT, see docs (https://example.com).\n\n      PORT=8080\n"
+	if got != want {
+		t.Errorf("renderParameterHelp() = %q, want %q", got, want)
+	}
+}
+
+func TestEndToEndExamples(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	// Examples are scaffolded from the real templates they are written for
+	withTemplatesFS(t, os.DirFS(".."))
+
+	if err := runOM(t, nil, "examples", "list"); err != nil {
+		t.Fatalf("om examples list failed: %v", err)
+	}
+	if err := runOM(t, nil, "examples", "create", "todo-app"); err != nil {
+		t.Fatalf("om examples create failed: %v", err)
+	}
+	loaded, err := manifestPkg.NewLoader(memFS).Load(filepath.Join("todo-app", "workbench.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, web := loaded.Services["api"], loaded.Services["web"]
+	if api.Template != "express-api" || api.Port != 3001 || api.Resources["db"].Type != "postgres-db" || api.Environment["DB_HOST"] != "api-db" {
+		t.Errorf("unexpected api service: %+v", api)
+	}
+	if web.Template != "react-typescript" || web.Path != "web" {
+		t.Errorf("unexpected web service: %+v", web)
+	}
+	todos, err := memFS.ReadFile(filepath.Join("todo-app", "api", "src", "todos.ts"))
+	if err != nil || !strings.Contains(string(todos), "CREATE TABLE IF NOT EXISTS todos") {
+		t.Errorf("expected the example's API code over the template, got %v", err)
+	}
+	if !filesystem.Exists(memFS, filepath.Join("todo-app", "api", "src", "database", "postgres", "index.ts")) {
+		t.Error("expected the template to be scaffolded with PostgreSQL support")
+	}
+
+	// The project is an ordinary one that om can generate for
+	chdir(t, "todo-app")
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("om compose failed for the example: %v", err)
+	}
+	compose, err := memFS.ReadFile(filepath.Join("todo-app", "docker-compose.yml"))
+	if err != nil || !strings.Contains(string(compose), "api-db:") {
+		t.Errorf("expected the database in docker-compose.yml, got %v", err)
+	}
+	chdir(t, ".")
+
+	err = runOM(t, nil, "examples", "create", "todo-app")
+	if exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected validation exit code for a directory that is not empty, got %d (%v)", exitCodeForError(err), err)
+	}
+	err = runOM(t, nil, "examples", "create", "chat-app")
+	if exitCodeForError(err) != ExitCodeNotFound {
+		t.Errorf("expected not-found exit code for an unknown example, got %d (%v)", exitCodeForError(err), err)
+	}
+
+	originalTempDir := exampleTempDir
+	exampleTempDir = func() (string, error) { return "tmp", nil }
+	t.Cleanup(func() { exampleTempDir = originalTempDir })
+	if err := runOM(t, nil, "examples", "create", "hello-api", "--temp"); err != nil {
+		t.Fatalf("om examples create --temp failed: %v", err)
+	}
+	if !filesystem.Exists(memFS, filepath.Join("tmp", "hello-api", "api", "main.go")) {
+		t.Error("expected the example in the temporary directory")
+	}
+}
+
+func TestEndToEndTeamPreset(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices: {}\n"
+	files := map[string]string{
+		filepath.Join("demo", "workbench.yaml"): manifest,
+		"om-defaults.yaml":                      "templates:\n  demo-service:\n    IncludeDocs: false\n",
+		filepath.Join("demo", "docs-on.yaml"):   "templates:\n  demo-service:\n    IncludeDocs: true\n",
+	}
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range files {
+		if err := memFS.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdir(t, "demo")
+
+	// The preset in the parent directory answers IncludeDocs interactively
+	err := runOM(t, map[string]interface{}{
+		"serviceName": "api",
+		"template":    "demo-service - A demo service",
+		"ServiceName": "api",
+	}, "add", "service")
+	if err != nil {
+		t.Fatalf("om add service failed: %v", err)
+	}
+	if filesystem.Exists(memFS, filepath.Join("demo", "api", "docs")) {
+		t.Error("expected the preset to turn docs off")
+	}
+
+	// --defaults takes precedence over discovered presets in direct mode
+	err = runOM(t, nil, "add", "service", "--name", "web", "--template", "demo-service", "--params", "ServiceName=web", "--defaults", "docs-on.yaml")
+	if err != nil {
+		t.Fatalf("om add service --defaults failed: %v", err)
+	}
+	if !filesystem.Exists(memFS, filepath.Join("demo", "web", "docs")) {
+		t.Error("expected --defaults to turn docs back on")
+	}
+}
+
+func TestEndToEndLogin(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	if err := memFS.MkdirAll(filepath.Join("home", ".docker"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("home", ".docker", "config.json"), []byte(`{"credsStore": "desktop"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var commands []string
+	original := registryRunner
+	registryRunner = func(stdin []byte, name string, args ...string) ([]byte, error) {
+		commands = append(commands, strings.Join(append([]string{name}, args...), " "))
+		return []byte("ecr-token\n"), nil
+	}
+	t.Cleanup(func() { registryRunner = original })
+
+	// ECR tokens are fetched with the AWS CLI and handed to the credential helper
+	ecr := "123456789012.dkr.ecr.eu-west-1.amazonaws.com"
+	if err := runOM(t, nil, "login", ecr); err != nil {
+		t.Fatalf("om login failed: %v", err)
+	}
+	expected := []string{"aws ecr get-login-password --region eu-west-1", "docker-credential-desktop store"}
+	if strings.Join(commands, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected %v, got %v", expected, commands)
+	}
+
+	// Other registries need the password on stdin
+	err := runOM(t, nil, "login", "registry.internal", "--username", "ci")
+	if exitCodeForError(err) != ExitCodeValidation {
+		t.Fatalf("expected validation exit code without --password-stdin, got %d (%v)", exitCodeForError(err), err)
+	}
+
+	root := setupRootCommand()
+	root.SetIn(strings.NewReader("token\n"))
+	t.Cleanup(func() { root.SetIn(nil) })
+	if err := runOM(t, nil, "login", "registry.internal", "--username", "ci", "--password-stdin"); err != nil {
+		t.Fatalf("om login --password-stdin failed: %v", err)
+	}
+	config, err := memFS.ReadFile(filepath.Join("home", ".docker", "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range []string{ecr, "registry.internal"} {
+		if !strings.Contains(string(config), `"`+host+`"`) {
+			t.Errorf("expected %s in the docker config, got:\n%s", host, config)
+		}
+	}
+}
+
+func TestEndToEndDepsCheck(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  web:\n    path: ./web\n  api:\n    path: ./api\n  auth:\n    image: registry.internal/auth:1.4.2\n"
+	files := map[string]string{
+		filepath.Join("demo", "workbench.yaml"):          manifest,
+		filepath.Join("demo", "web", "package.json"):     "{\n  \"dependencies\": {\n    \"react\": \"^18.2.0\"\n  }\n}\n",
+		filepath.Join("demo", "api", "requirements.txt"): "fastapi==0.109.0\n",
+	}
+	for _, dir := range []string{"web", "api"} {
+		if err := memFS.MkdirAll(filepath.Join("demo", dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path, content := range files {
+		if err := memFS.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdir(t, "demo")
+
+	outputs := map[string]string{
+		"npm outdated --json":               `{"react": {"current": "18.2.0", "wanted": "18.3.1", "latest": "19.0.0"}}`,
+		"pip list --outdated --format=json": `[{"name": "fastapi", "version": "0.109.0", "latest_version": "0.111.0"}]`,
+		"git rev-parse --abbrev-ref HEAD":   "main\n",
+	}
+	var commands []string
+	original := depsRunner
+	depsRunner = func(dir, name string, args ...string) ([]byte, error) {
+		command := strings.Join(append([]string{name}, args...), " ")
+		commands = append(commands, command)
+		return []byte(outputs[command]), nil
+	}
+	t.Cleanup(func() { depsRunner = original })
+
+	// Checking only reports
+	if err := runOM(t, nil, "deps", "check"); err != nil {
+		t.Fatalf("om deps check failed: %v", err)
+	}
+	if content, _ := memFS.ReadFile(filepath.Join("demo", "web", "package.json")); strings.Contains(string(content), "19.0.0") {
+		t.Error("expected check without --apply to leave package.json alone")
+	}
+
+	// --apply commits the bumps of each service on its own branch
+	commands = nil
+	if err := runOM(t, nil, "deps", "check", "--apply"); err != nil {
+		t.Fatalf("om deps check --apply failed: %v", err)
+	}
+	expected := []string{
+		"pip list --outdated --format=json",
+		"npm outdated --json",
+		"git status --porcelain",
+		"git rev-parse --abbrev-ref HEAD",
+		"git checkout -b om/deps-api",
+		"git add api/requirements.txt",
+		"git commit -m Bump api dependencies",
+		"git checkout main",
+		"git checkout -b om/deps-web",
+		"git add web/package.json",
+		"git commit -m Bump web dependencies",
+		"git checkout main",
+	}
+	if strings.Join(commands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected commands:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(commands, "\n"))
+	}
+	if content, _ := memFS.ReadFile(filepath.Join("demo", "web", "package.json")); !strings.Contains(string(content), `"react": "^19.0.0"`) {
+		t.Errorf("expected react to be bumped, got:\n%s", content)
+	}
+
+	// Dirty working trees are refused
+	outputs["git status --porcelain"] = " M web/package.json\n"
+	err := runOM(t, nil, "deps", "check", "--apply")
+	if exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected validation exit code for a dirty tree, got %d (%v)", exitCodeForError(err), err)
+	}
+}
+
+func TestEndToEndLintDockerfiles(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  web:\n    path: ./web\n  api:\n    path: ./api\n  auth:\n    image: registry.internal/auth:1.4.2\n"
+	files := map[string]string{
+		filepath.Join("demo", "workbench.yaml"):    manifest,
+		filepath.Join("demo", "web", "Dockerfile"): "FROM node:20\nRUN apt-get install -y curl\n",
+		filepath.Join("demo", "api", "Dockerfile"): "FROM python:3.12\n",
+	}
+	for _, dir := range []string{"web", "api"} {
+		if err := memFS.MkdirAll(filepath.Join("demo", dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path, content := range files {
+		if err := memFS.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdir(t, "demo")
+
+	reports := map[string]string{
+		filepath.Join("demo", "web"): `[{"line": 2, "code": "DL3008", "message": "Pin versions in apt get install", "column": 1, "file": "Dockerfile", "level": "warning"}]`,
+		filepath.Join("demo", "api"): `[]`,
+	}
+	var linted []string
+	original := lintRunner
+	lintRunner = func(dir, name string, args ...string) ([]byte, error) {
+		linted = append(linted, dir)
+		return []byte(reports[dir]), nil
+	}
+	t.Cleanup(func() { lintRunner = original })
+
+	// New findings fail the command
+	err := runOM(t, nil, "lint", "dockerfiles")
+	if exitCodeForError(err) != ExitCodeValidation {
+		t.Fatalf("expected validation exit code for new findings, got %d (%v)", exitCodeForError(err), err)
+	}
+	if len(linted) != 2 {
+		t.Errorf("expected the two services with Dockerfiles to be linted, got %v", linted)
+	}
+
+	// Accepted findings pass until another one appears
+	if err := runOM(t, nil, "lint", "dockerfiles", "--update-baseline"); err != nil {
+		t.Fatalf("om lint dockerfiles --update-baseline failed: %v", err)
+	}
+	if !filesystem.Exists(memFS, filepath.Join("demo", ".om", "dockerlint-baseline.yaml")) {
+		t.Error("expected the baseline to be written")
+	}
+	if err := runOM(t, nil, "lint", "dockerfiles"); err != nil {
+		t.Fatalf("expected baselined findings to pass, got %v", err)
+	}
+	reports[filepath.Join("demo", "api")] = `[{"line": 1, "code": "DL3007", "message": "Using latest is prone to errors", "column": 1, "file": "Dockerfile", "level": "warning"}]`
+	if err := runOM(t, nil, "lint", "dockerfiles"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected a new finding to fail the command, got %v", err)
+	}
+
+	// A missing hadolint is a warning, not a failure
+	lintRunner = func(dir, name string, args ...string) ([]byte, error) {
+		return nil, &exec.Error{Name: name, Err: exec.ErrNotFound}
+	}
+	if err := runOM(t, nil, "lint", "dockerfiles"); err != nil {
+		t.Errorf("expected a missing hadolint to only warn, got %v", err)
+	}
+	if err := runOM(t, nil, "lint", "dockerfiles", "--strict"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected --strict to fail without hadolint, got %v", err)
+	}
+}
+
+func TestEndToEndScaffoldPolicy(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	withTemplatesFS(t, testutil.NewCatalog(map[string]testutil.Template{
+		"go-service": {
+			Manifest: `{"name": "go-service", "description": "A Go service", "parameters": [{"name": "ServiceName", "prompt": "Service name?", "type": "string", "required": true}]}`,
+			Files:    map[string]string{"main.go": "package main\n\nfunc main() {}\n", "README.md": "# {{ .ServiceName }}\n"},
+		},
+	}))
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices: {}\n"
+	policy := "license:\n  header: \"Copyright (c) Acme Corp.\"\nfiles:\n  - path: CODEOWNERS\n    content: \"* @acme/platform\\n\"\n"
+	if err := memFS.MkdirAll(filepath.Join("demo", ".om"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range map[string]string{
+		filepath.Join("demo", "workbench.yaml"):     manifest,
+		filepath.Join("demo", ".om", "policy.yaml"): policy,
+	} {
+		if err := memFS.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdir(t, "demo")
+
+	if err := runOM(t, nil, "add", "service", "--name", "api", "--template", "go-service", "--params", "ServiceName=api"); err != nil {
+		t.Fatalf("om add service failed: %v", err)
+	}
+	main, err := memFS.ReadFile(filepath.Join("demo", "api", "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(main), "// Copyright (c) Acme Corp.\n\npackage main") {
+		t.Errorf("expected the license header on main.go, got:\n%s", main)
+	}
+	if !filesystem.Exists(memFS, filepath.Join("demo", "api", "CODEOWNERS")) {
+		t.Error("expected the policy's CODEOWNERS file")
+	}
+
+	// An invalid policy stops the scaffold and leaves nothing behind
+	if err := memFS.WriteFile(filepath.Join("demo", ".om", "policy.yaml"), []byte("formatters:\n  black: x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = runOM(t, nil, "add", "service", "--name", "web", "--template", "go-service", "--params", "ServiceName=web")
+	if exitCodeForError(err) != ExitCodeValidation {
+		t.Fatalf("expected validation exit code for an invalid policy, got %d (%v)", exitCodeForError(err), err)
+	}
+	if filesystem.Exists(memFS, filepath.Join("demo", "web")) {
+		t.Error("expected the service directory to be removed")
+	}
+}
+
+func TestEndToEndAddServiceRemoteTemplate(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	t.Setenv("OM_TEMPLATE_CACHE", t.TempDir())
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices: {}\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	var cloned []string
+	originalRunner := templateRunner
+	templateRunner = func(dir, name string, args ...string) ([]byte, error) {
+		cloned = append(cloned, strings.Join(args, " "))
+		target := args[len(args)-1]
+		for file, content := range map[string]string{"template.json": testutil.DemoServiceTemplate.Manifest, "README.md": "# {{ .ServiceName }} from the company catalog\n"} {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(filepath.Join(target, file), []byte(content), 0644); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { templateRunner = originalRunner })
+
+	template := "github.com/acme/service-template@v1.2.0"
+	if err := runOM(t, nil, "add", "service", "--name", "api", "--template", template, "--params", "ServiceName=api"); err != nil {
+		t.Fatalf("om add service failed: %v", err)
+	}
+	readme, err := memFS.ReadFile(filepath.Join("demo", "api", "README.md"))
+	if err != nil || !strings.HasPrefix(string(readme), "# api from the company catalog\n") {
+		t.Errorf("expected the remote template to be scaffolded, got %q, %v", readme, err)
+	}
+	if len(cloned) != 1 || !strings.Contains(cloned[0], "--branch v1.2.0 https://github.com/acme/service-template") {
+		t.Errorf("expected the tag to be cloned once, got %v", cloned)
+	}
+	loaded, err := manifestPkg.NewLoader(memFS).Load(filepath.Join("demo", "workbench.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Services["api"].Template != template {
+		t.Errorf("expected the service to record where its template came from, got %q", loaded.Services["api"].Template)
+	}
+
+	// The pinned version is served from the cache
+	if err := runOM(t, nil, "add", "service", "--name", "web", "--template", template, "--params", "ServiceName=web"); err != nil {
+		t.Fatalf("om add service failed: %v", err)
+	}
+	if len(cloned) != 1 {
+		t.Errorf("expected the cached template to be reused, got %v", cloned)
+	}
+	if err := runOM(t, nil, "add", "service", "--name", "worker", "--template", "github.com/acme"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected a path without a repository to be a validation error, got %v", err)
+	}
+}
+
+func TestEndToEndAddServiceLocalTemplate(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices: {}\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	// OM_TEMPLATES_PATH overrides the built-in demo-service; the user's
+	// templates directory adds a template of its own
+	pathDir, userDir := t.TempDir(), t.TempDir()
+	writeTemplate := func(dir, name, readme string) {
+		t.Helper()
+		for file, content := range map[string]string{"template.json": testutil.DemoServiceTemplate.Manifest, "README.md": readme} {
+			if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, name, file), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	writeTemplate(pathDir, "demo-service", "# {{ .ServiceName }} from my checkout\n")
+	writeTemplate(userDir, "demo-service", "# {{ .ServiceName }} from my home\n")
+	writeTemplate(userDir, "worker", "# {{ .ServiceName }} worker\n")
+	t.Setenv("OM_TEMPLATES_PATH", pathDir)
+	originalUserTemplates := userTemplatesDir
+	userTemplatesDir = func() (string, error) { return userDir, nil }
+	t.Cleanup(func() { userTemplatesDir = originalUserTemplates })
+	withTemplatesFS(t, withLocalTemplates(testutil.DefaultCatalog()))
+
+	for name, template := range map[string]string{"api": "demo-service", "jobs": "worker"} {
+		if err := runOM(t, nil, "add", "service", "--name", name, "--template", template, "--params", "ServiceName="+name); err != nil {
+			t.Fatalf("om add service --template %s failed: %v", template, err)
+		}
+	}
+	for file, want := range map[string]string{"api": "# api from my checkout\n", "jobs": "# jobs worker\n"} {
+		readme, err := memFS.ReadFile(filepath.Join("demo", file, "README.md"))
+		if err != nil || !strings.HasPrefix(string(readme), want) {
+			t.Errorf("expected %s to be scaffolded from the local template, got %q, %v", file, readme, err)
+		}
+	}
+
+	// Edits to a local template apply to the next run
+	writeTemplate(pathDir, "demo-service", "# {{ .ServiceName }} edited\n")
+	if err := runOM(t, nil, "add", "service", "--name", "web", "--template", "demo-service", "--params", "ServiceName=web"); err != nil {
+		t.Fatalf("om add service failed: %v", err)
+	}
+	if readme, _ := memFS.ReadFile(filepath.Join("demo", "web", "README.md")); !strings.HasPrefix(string(readme), "# web edited\n") {
+		t.Errorf("expected the edited template to be used, got %q", readme)
+	}
+}
+
+func TestEndToEndAddServiceReadsManifestOnce(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	catalog := testutil.NewCountingFS(testutil.DefaultCatalog())
+	withTemplatesFS(t, catalog)
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices: {}\n"
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	if err := runOM(t, nil, "add", "service", "--name", "api", "--template", "demo-service", "--params", "ServiceName=api,IncludeDocs=false"); err != nil {
+		t.Fatalf("om add service failed: %v", err)
+	}
+	if opens := catalog.Opens("templates/demo-service/template.json"); opens != 1 {
+		t.Errorf("expected template.json to be read once, got %d", opens)
+	}
+}
+
+func TestEndToEndDebugLog(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	original := debugClock
+	debugClock = func() time.Time { return time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC) }
+	t.Cleanup(func() { debugClock = original })
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices: {}\n"
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	if err := runOM(t, nil, "add", "service", "--debug", "--name", "api", "--template", "demo-service", "--params", "ServiceName=api,IncludeDocs=false"); err != nil {
+		t.Fatalf("om add service failed: %v", err)
+	}
+	log, err := memFS.ReadFile(filepath.Join("demo", ".om", "logs", "om-20260304-050607.log"))
+	if err != nil {
+		t.Fatalf("expected a debug log in the project: %v", err)
+	}
+	for _, want := range []string{"running om add service --debug=true --name=api --params=", "loading template manifest templates/demo-service/template.json", "command succeeded"} {
+		if !strings.Contains(string(log), want) {
+			t.Errorf("expected the debug log to contain %q, got:\n%s", want, log)
+		}
+	}
+
+	// Without --debug nothing more is written
+	if err := runOM(t, nil, "add", "service", "--name", "web", "--template", "demo-service", "--params", "ServiceName=web,IncludeDocs=false"); err != nil {
+		t.Fatalf("om add service failed: %v", err)
+	}
+	after, err := memFS.ReadFile(filepath.Join("demo", ".om", "logs", "om-20260304-050607.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(log) {
+		t.Error("expected no debug output without --debug")
+	}
+}
+
+// readOnlyFS rejects every write below dir, like a read-only mount
+type readOnlyFS struct {
+	filesystem.FS
+	dir string
+}
+
+func (r readOnlyFS) check(path string) error {
+	if rel, err := filepath.Rel(r.dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return &fs.PathError{Op: "write", Path: path, Err: fs.ErrPermission}
+	}
+	return nil
+}
+
+func (r readOnlyFS) WriteFile(path string, data []byte, perm os.FileMode) error {
+	if err := r.check(path); err != nil {
+		return err
+	}
+	return r.FS.WriteFile(path, data, perm)
+}
+
+func (r readOnlyFS) MkdirAll(path string, perm os.FileMode) error {
+	if err := r.check(path); err != nil {
+		return err
+	}
+	return r.FS.MkdirAll(path, perm)
+}
+
+func TestEndToEndReadOnlyProject(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices: {}\n"
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	withWorkspaceFS(t, readOnlyFS{FS: memFS, dir: "demo"}, "demo")
+
+	err := runOM(t, nil, "add", "service", "--name", "api", "--template", "demo-service", "--params", "ServiceName=api,IncludeDocs=false")
+	if exitCodeForError(err) != ExitCodeFileSystem {
+		t.Fatalf("expected file system exit code for a read-only project, got %d (%v)", exitCodeForError(err), err)
+	}
+	if !strings.Contains(err.Error(), "Resource: demo") {
+		t.Errorf("expected the error to name the project directory, got: %v", err)
+	}
+	if filesystem.Exists(memFS, filepath.Join("demo", "api")) {
+		t.Error("expected nothing to be scaffolded")
+	}
+}
+
+func TestEndToEndInitReadOnlyDirectory(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	withWorkspaceFS(t, readOnlyFS{FS: memFS, dir: "."}, "/shared")
+	original := fallbackProjectsDir
+	fallbackProjectsDir = func() (string, error) { return "/home/dev/om-projects", nil }
+	t.Cleanup(func() { fallbackProjectsDir = original })
+	answers := map[string]interface{}{
+		"projectName": "demo",
+		"template":    "demo-service - A demo service",
+		"serviceName": "web",
+		"ServiceName": "web",
+		"IncludeDocs": false,
+	}
+
+	// Declining the alternative fails before any question about the project
+	err := runOM(t, map[string]interface{}{"useWritableDir": false}, "init")
+	if exitCodeForError(err) != ExitCodeFileSystem {
+		t.Fatalf("expected file system exit code, got %d (%v)", exitCodeForError(err), err)
+	}
+	if !strings.Contains(err.Error(), "Resource: /shared") {
+		t.Errorf("expected the error to name the current directory, got: %v", err)
+	}
+
+	answers["useWritableDir"] = true
+	if err := runOM(t, answers, "init"); err != nil {
+		t.Fatalf("om init failed: %v", err)
+	}
+	if !filesystem.Exists(memFS, filepath.Join("/home/dev/om-projects", "demo", "workbench.yaml")) {
+		t.Error("expected the project in the writable alternative directory")
+	}
+}
+
+func TestEndToEndScaffoldPreflight(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	diskFreeSpace = func(string) (uint64, error) { return 5 << 20, nil }
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices: {}\n"
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	err := runOM(t, nil, "add", "service", "--name", "api", "--template", "demo-service", "--params", "ServiceName=api,IncludeDocs=false")
+	if exitCodeForError(err) != ExitCodeFileSystem {
+		t.Fatalf("expected file system exit code on a full disk, got %d (%v)", exitCodeForError(err), err)
+	}
+	if !strings.Contains(err.Error(), "only 5 MB free") {
+		t.Errorf("expected the free space in the error, got: %v", err)
+	}
+	if filesystem.Exists(memFS, filepath.Join("demo", "api")) {
+		t.Error("expected nothing to be scaffolded")
+	}
+}
+
+// fakeWatcher reports one change, made when om watch starts waiting for
+// changes, and then stops
+type fakeWatcher struct {
+	dirs   []string
+	change func() string
+	events chan string
+}
+
+func (w *fakeWatcher) Add(dir string) error {
+	w.dirs = append(w.dirs, dir)
+	return nil
+}
+
+func (w *fakeWatcher) Events() <-chan string {
+	if w.events == nil {
+		w.events = make(chan string, 1)
+		w.events <- w.change()
+		close(w.events)
+	}
+	return w.events
+}
+
+func (w *fakeWatcher) Errors() <-chan error { return nil }
+func (w *fakeWatcher) Close() error         { return nil }
+
+func TestEndToEndWatch(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    port: 8000\n"
+	if err := memFS.MkdirAll(filepath.Join("demo", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "api", "Dockerfile"), []byte("FROM node:20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	workbenchPath := filepath.Join("demo", "workbench.yaml")
+	if err := memFS.WriteFile(workbenchPath, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	watcher := &fakeWatcher{change: func() string {
+		compose, err := memFS.ReadFile(filepath.Join("demo", "docker-compose.yml"))
+		if err != nil {
+			t.Fatalf("expected docker-compose.yml after the first run: %v", err)
+		}
+		if strings.Contains(string(compose), "9000") {
+			t.Fatal("docker-compose.yml already uses the new port")
+		}
+		edited := strings.Replace(manifest, "port: 8000", "port: 9000", 1)
+		if err := memFS.WriteFile(workbenchPath, []byte(edited), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return filepath.Join("demo", "workbench.yaml")
+	}}
+	originalWatcher := newFileWatcher
+	newFileWatcher = func() (fileWatcher, error) { return watcher, nil }
+	t.Cleanup(func() { newFileWatcher = originalWatcher })
+
+	if err := runOM(t, nil, "watch"); err != nil {
+		t.Fatalf("om watch failed: %v", err)
+	}
+	if len(watcher.dirs) == 0 || watcher.dirs[0] != "demo" {
+		t.Errorf("expected the project directory to be watched, got %v", watcher.dirs)
+	}
+	compose, err := memFS.ReadFile(filepath.Join("demo", "docker-compose.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(compose), "9000") {
+		t.Errorf("expected docker-compose.yml to be regenerated with the new port, got:\n%s", compose)
+	}
+}
+
+// countingDockerPrerequisites counts the Docker tooling checks, one per
+// generator run
+type countingDockerPrerequisites struct {
+	runs *int
+}
+
+func (c countingDockerPrerequisites) CheckAllPrerequisites() error {
+	*c.runs++
+	return nil
+}
+
+func (countingDockerPrerequisites) GetDockerComposeCommand() string { return "docker compose" }
+
+func TestEndToEndComposeUpToDate(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	runs := 0
+	dockerPrerequisites = countingDockerPrerequisites{runs: &runs}
+
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    port: 8000\ngroups:\n  backend: [api]\n"
+	if err := memFS.MkdirAll(filepath.Join("demo", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "api", "Dockerfile"), []byte("FROM node:20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	steps := []struct {
+		name   string
+		args   []string
+		change func() error
+		runs   int
+	}{
+		{name: "first run", runs: 1},
+		{name: "unchanged", runs: 1},
+		{name: "forced", args: []string{"--force"}, runs: 2},
+		{name: "other group", args: []string{"--group", "backend"}, runs: 3},
+		{name: "Dockerfile changed", runs: 4, change: func() error {
+			return memFS.WriteFile(filepath.Join("demo", "api", "Dockerfile"), []byte("FROM node:22\n"), 0644)
+		}},
+		{name: "manifest changed", runs: 5, change: func() error {
+			edited := strings.Replace(manifest, "port: 8000", "port: 9000", 1)
+			return memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(edited), 0644)
+		}},
+		{name: "output deleted", runs: 6, change: func() error {
+			return memFS.Remove(filepath.Join("demo", "docker-compose.yml"))
+		}},
+	}
+	for _, step := range steps {
+		if step.change != nil {
+			if err := step.change(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		args := append([]string{"compose", "--target", "docker"}, step.args...)
+		if err := runOM(t, nil, args...); err != nil {
+			t.Fatalf("%s: om compose failed: %v", step.name, err)
+		}
+		if runs != step.runs {
+			t.Errorf("%s: expected %d generator runs, got %d", step.name, step.runs, runs)
+		}
+	}
+	if !filesystem.Exists(memFS, filepath.Join("demo", "docker-compose.yml")) {
+		t.Error("expected docker-compose.yml to be regenerated")
+	}
+}
+
+func TestEndToEndDev(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    port: 8000\n"
+	if err := memFS.MkdirAll(filepath.Join("demo", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	var ran []string
+	originalRunner, originalStreamer := devRunner, devStreamer
+	devRunner = func(dir, name string, args ...string) ([]byte, error) {
+		ran = append(ran, name+" "+strings.Join(args, " "))
+		if args[len(args)-1] == "--services" {
+			return []byte("api\n"), nil
+		}
+		return nil, nil
+	}
+	devStreamer = func(ctx context.Context, dir string, out io.Writer, name string, args ...string) error {
+		ran = append(ran, name+" "+strings.Join(args, " "))
+		_, err := io.WriteString(out, "listening on :8000\n")
+		return err
+	}
+	t.Cleanup(func() { devRunner, devStreamer = originalRunner, originalStreamer })
+
+	if err := runOM(t, nil, "dev"); err != nil {
+		t.Fatalf("om dev failed: %v", err)
+	}
+	if !filesystem.Exists(memFS, filepath.Join("demo", "docker-compose.yml")) {
+		t.Error("expected om dev to generate docker-compose.yml")
+	}
+	want := []string{
+		"docker compose -f docker-compose.yml up --build --detach --remove-orphans",
+		"docker compose -f docker-compose.yml config --services",
+		"docker compose -f docker-compose.yml logs --follow --no-color --no-log-prefix api",
+		"docker compose -f docker-compose.yml down",
+	}
+	if strings.Join(ran, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ran:\n%s\nwant:\n%s", strings.Join(ran, "\n"), strings.Join(want, "\n"))
+	}
+
+	ran = nil
+	if err := runOM(t, nil, "dev", "--keep"); err != nil {
+		t.Fatalf("om dev --keep failed: %v", err)
+	}
+	if len(ran) != 3 {
+		t.Errorf("expected --keep to leave the services running, ran:\n%s", strings.Join(ran, "\n"))
+	}
+
+	devRunner = func(dir, name string, args ...string) ([]byte, error) {
+		return nil, errors.New("Cannot connect to the Docker daemon")
+	}
+	if err := runOM(t, nil, "dev"); exitCodeForError(err) != ExitCodeExternalTool {
+		t.Errorf("expected a failed docker compose up to be an external tool error, got %v", err)
+	}
+}
+
+func TestEndToEndComposeKubernetes(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	t.Setenv(experiments.EnvVar, "")
+
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    port: 8000\n    resources:\n      db:\n        type: postgres-db\n"
+	if err := memFS.MkdirAll(filepath.Join("demo", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	err := runOM(t, nil, "compose", "--target", "kubernetes")
+	if exitCodeForError(err) != ExitCodeValidation || !strings.Contains(err.Error(), "OM_EXPERIMENTS=kubernetes") {
+		t.Fatalf("expected the kubernetes target to require its experiment, got %v", err)
+	}
+
+	t.Setenv(experiments.EnvVar, "kubernetes")
+	if err := runOM(t, nil, "compose", "--target", "kubernetes"); err != nil {
+		t.Fatalf("om compose --target kubernetes failed: %v", err)
+	}
+	api, err := memFS.ReadFile(filepath.Join("demo", "k8s", "api.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"kind: Deployment", "image: demo-api:latest", "kind: Service", "name: demo-env"} {
+		if !strings.Contains(string(api), want) {
+			t.Errorf("expected api.yaml to contain %q, got:\n%s", want, api)
+		}
+	}
+	db, err := memFS.ReadFile(filepath.Join("demo", "k8s", "api-db.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(db), "kind: PersistentVolumeClaim") {
+		t.Errorf("expected the database to get a claim for its data, got:\n%s", db)
+	}
+	if filesystem.Exists(memFS, filepath.Join("demo", "docker-compose.yml")) {
+		t.Error("expected the kubernetes target not to write docker-compose.yml")
+	}
+}
+
+func TestEndToEndGenerationReport(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    port: 8000\n"
+	if err := memFS.MkdirAll(filepath.Join("demo", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "api", "Dockerfile"), []byte("FROM node:20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	if err := runOM(t, nil, "report", "last"); exitCodeForError(err) != ExitCodeNotFound {
+		t.Errorf("expected no report before the first run, got %v", err)
+	}
+
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("om compose failed: %v", err)
+	}
+	data, err := memFS.ReadFile(filepath.Join("demo", ".om", "reports", "compose-20260102-030405.json"))
+	if err != nil {
+		t.Fatalf("expected a generation report: %v", err)
+	}
+	var generated report.Report
+	if err := json.Unmarshal(data, &generated); err != nil {
+		t.Fatalf("invalid report: %v", err)
+	}
+	if generated.Inputs.Target != "docker" || len(generated.Inputs.ManifestSHA256) != 64 {
+		t.Errorf("expected the target and manifest hash as inputs, got %+v", generated.Inputs)
+	}
+	var wroteCompose bool
+	for _, file := range generated.Outputs {
+		wroteCompose = wroteCompose || file.Path == "docker-compose.yml" && file.Size > 0
+	}
+	if !wroteCompose {
+		t.Errorf("expected docker-compose.yml among the outputs, got %+v", generated.Outputs)
+	}
+	if len(generated.Durations) == 0 {
+		t.Error("expected phase durations")
+	}
+
+	if err := runOM(t, nil, "report", "last", "--json"); err != nil {
+		t.Errorf("om report last failed: %v", err)
+	}
+}
+
+func TestEndToEndAddServiceNestedPath(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nlayout:\n  services: apps\nservices: {}\n"
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	// The project layout places services under apps/
+	if err := runOM(t, nil, "add", "service", "--name", "api", "--template", "demo-service", "--params", "ServiceName=api,IncludeDocs=false"); err != nil {
+		t.Fatalf("om add service failed: %v", err)
+	}
+	// --path overrides the layout and names the service after its directory
+	if err := runOM(t, nil, "add", "service", "--path", "services/worker", "--template", "demo-service", "--params", "ServiceName=worker,IncludeDocs=false"); err != nil {
+		t.Fatalf("om add service --path failed: %v", err)
+	}
+	for _, dir := range []string{filepath.Join("demo", "apps", "api"), filepath.Join("demo", "services", "worker")} {
+		if !filesystem.Exists(memFS, dir) {
+			t.Errorf("expected %s to be scaffolded", dir)
+		}
+	}
+
+	loaded, err := manifestPkg.Load(memFS, filepath.Join("demo", "workbench.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Services["api"].Path; got != "apps/api" {
+		t.Errorf("expected api at apps/api, got %q", got)
+	}
+	if got := loaded.Services["worker"].Path; got != "services/worker" {
+		t.Errorf("expected worker at services/worker, got %q", got)
+	}
+
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("om compose failed: %v", err)
+	}
+	compose, err := memFS.ReadFile(filepath.Join("demo", "docker-compose.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, context := range []string{"context: apps/api", "context: services/worker"} {
+		if !strings.Contains(string(compose), context) {
+			t.Errorf("expected build %s in docker-compose.yml, got:\n%s", context, compose)
+		}
+	}
+
+	for _, path := range []string{"apps/api/jobs", "../outside", "/srv/api", "Apps/Web"} {
+		err := runOM(t, nil, "add", "service", "--path", path, "--template", "demo-service", "--params", "ServiceName=x,IncludeDocs=false")
+		if exitCodeForError(err) != ExitCodeValidation {
+			t.Errorf("--path %s: expected validation exit code, got %d (%v)", path, exitCodeForError(err), err)
+		}
+	}
+}
+
+func TestEndToEndPrune(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	var removed []string
+	original := pruneRunner
+	pruneRunner = func(dir, name string, args ...string) ([]byte, error) {
+		switch args[0] {
+		case "ps":
+			return []byte("c1\tdemo-api-1\tservices.api\nc2\tdemo-worker-1\tservices.worker\n"), nil
+		case "volume":
+			if args[1] == "ls" {
+				return []byte("demo_worker_db_data\tservices.worker.resources.db\n"), nil
+			}
+		}
+		if args[0] != "image" {
+			removed = append(removed, strings.Join(args, " "))
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { pruneRunner = original })
+
+	if err := runOM(t, nil, "prune", "--dry-run"); err != nil || len(removed) != 0 {
+		t.Fatalf("expected a dry run to remove nothing, got %v (%v)", removed, err)
+	}
+	if err := runOM(t, map[string]interface{}{"confirm": false}, "prune"); exitCodeForError(err) != ExitCodeCancelled {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+	if err := runOM(t, map[string]interface{}{"confirm": true}, "prune"); err != nil {
+		t.Fatalf("om prune failed: %v", err)
+	}
+	if want := "rm --force c2|volume rm demo_worker_db_data"; strings.Join(removed, "|") != want {
+		t.Errorf("expected %s, got %v", want, removed)
+	}
+}
+
+// smokeResponder answers smoke test requests without a network
+type smokeResponder func(req *http.Request) (*http.Response, error)
+
+func (f smokeResponder) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestEndToEndSmoke(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"services:\n  api:\n    path: ./api\n    port: 8000\n    health:\n      path: /health\n  web:\n    path: ./web\n    port: 3000\n" +
+		"environments:\n  dev:\n    provider: aws\n    exclude: [web]\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	var requested []string
+	healthy := map[string]bool{"http://localhost:8000/health": true, "https://demo-alb.elb.example/health": true}
+	originalClient, originalRunner := smokeClient, smokeRunner
+	smokeClient = func(time.Duration) smoke.Doer {
+		return smokeResponder(func(req *http.Request) (*http.Response, error) {
+			requested = append(requested, req.URL.String())
+			status := http.StatusServiceUnavailable
+			if healthy[req.URL.String()] {
+				status = http.StatusOK
+			}
+			return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}, nil
+		})
+	}
+	smokeRunner = func(dir, name string, args ...string) ([]byte, error) {
+		return []byte("demo-alb.elb.example"), nil
+	}
+	t.Cleanup(func() { smokeClient, smokeRunner = originalClient, originalRunner })
+
+	if err := runOM(t, nil, "smoke"); err == nil || !strings.Contains(err.Error(), "1 of 2") {
+		t.Errorf("expected web to fail locally, got %v", err)
+	}
+	if want := "http://localhost:8000/health http://localhost:3000/"; strings.Join(requested, " ") != want {
+		t.Errorf("expected requests to %s, got %v", want, requested)
+	}
+
+	requested = nil
+	if err := runOM(t, nil, "smoke", "--env", "dev"); err != nil {
+		t.Fatalf("expected dev to pass without web, got %v", err)
+	}
+	if want := "https://demo-alb.elb.example/health"; strings.Join(requested, " ") != want {
+		t.Errorf("expected requests to %s, got %v", want, requested)
+	}
+
+	if err := runOM(t, nil, "smoke", "--env", "prod"); exitCodeForError(err) != ExitCodeNotFound {
+		t.Errorf("expected an unknown environment to be not found, got %v", err)
+	}
+	if err := runOM(t, nil, "smoke", "--url", "https://example.com"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected --url without --env to be a validation error, got %v", err)
+	}
+}
+
+func TestEndToEndStaleOutputs(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"services:\n  api:\n    path: ./api\n    port: 8000\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	originalClient := smokeClient
+	smokeClient = func(time.Duration) smoke.Doer {
+		return smokeResponder(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+		})
+	}
+	t.Cleanup(func() { smokeClient = originalClient })
+
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("compose failed: %v", err)
+	}
+	data, err := memFS.ReadFile(filepath.Join("demo", "docker-compose.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# om-manifest-revision: ") {
+		t.Errorf("expected docker-compose.yml to record the manifest revision, got:\n%s", data)
+	}
+
+	// Outputs generated from the current manifest are used without asking
+	if err := runOM(t, nil, "smoke"); err != nil {
+		t.Fatalf("expected smoke to pass, got %v", err)
+	}
+
+	manifest += "  web:\n    path: ./web\n    port: 3000\n"
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = runOM(t, map[string]interface{}{"useStaleOutputs": false}, "smoke")
+	if exitCodeForError(err) != ExitCodeValidation || !strings.Contains(err.Error(), "docker-compose.yml is out of date") {
+		t.Errorf("expected declining stale outputs to be a validation error, got %v", err)
+	}
+	if err := runOM(t, map[string]interface{}{"useStaleOutputs": true}, "smoke"); err != nil {
+		t.Errorf("expected smoke to continue with confirmed stale outputs, got %v", err)
+	}
+
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("compose failed: %v", err)
+	}
+	if err := runOM(t, nil, "smoke"); err != nil {
+		t.Errorf("expected smoke to pass after regenerating, got %v", err)
+	}
+}
+
+func TestEndToEndBundle(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"services:\n  api:\n    path: ./api\n    port: 8000\n    resources:\n      cache:\n        type: redis\n        version: \"7\"\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	var ran []string
+	originalRunner, originalClock := bundleRunner, bundleClock
+	bundleRunner = func(dir, name string, args ...string) ([]byte, error) {
+		ran = append(ran, strings.Join(append([]string{name}, args...), " "))
+		if args[0] == "save" {
+			return nil, os.WriteFile(args[2], []byte("image layers"), 0644)
+		}
+		return nil, nil
+	}
+	bundleClock = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	t.Cleanup(func() { bundleRunner, bundleClock = originalRunner, originalClock })
+
+	if err := runOM(t, nil, "bundle", "export"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected exporting before om compose to be a validation error, got %v", err)
+	}
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("compose failed: %v", err)
+	}
+	if err := runOM(t, nil, "bundle", "export"); err != nil {
+		t.Fatalf("bundle export failed: %v", err)
+	}
+	if len(ran) != 3 || !strings.HasPrefix(ran[0], "docker build --tag demo-api:") || !strings.HasSuffix(ran[0], " ./api") {
+		t.Errorf("expected api to be built and the images saved, ran %v", ran)
+	}
+	if !filesystem.Exists(memFS, filepath.Join("demo", "demo-bundle.tar.gz")) {
+		t.Fatal("expected demo-bundle.tar.gz to be written")
+	}
+
+	ran = nil
+	if err := runOM(t, nil, "bundle", "import", "demo-bundle.tar.gz", "--dir", "../site"); err != nil {
+		t.Fatalf("bundle import failed: %v", err)
+	}
+	if strings.Join(ran, " ") != "docker load --input images.tar" {
+		t.Errorf("expected the images to be loaded, ran %v", ran)
+	}
+	composeFile, err := memFS.ReadFile(filepath.Join("site", "docker-compose.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(composeFile), "build:") || !strings.Contains(string(composeFile), "image: demo-api:") {
+		t.Errorf("expected the imported compose file to run images only, got:\n%s", composeFile)
+	}
+	env, err := memFS.ReadFile(filepath.Join("site", ".env"))
+	if err != nil || !strings.Contains(string(env), "api_cache_password=\n") {
+		t.Errorf("expected .env to be created from the template without values, got %q, %v", env, err)
+	}
+
+	if err := runOM(t, nil, "bundle", "import", "demo-bundle.tar.gz", "--dir", "../site"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected importing into a non-empty directory to be a validation error, got %v", err)
+	}
+	if err := runOM(t, nil, "bundle", "import", "missing.tar.gz"); exitCodeForError(err) != ExitCodeNotFound {
+		t.Errorf("expected a missing bundle to be not found, got %v", err)
+	}
+}
+
+// missingDockerPrerequisites fails the tooling check as on a machine
+// without the engine installed
+type missingDockerPrerequisites struct{}
+
+func (missingDockerPrerequisites) CheckAllPrerequisites() error {
+	return errors.New("Podman is not installed or not available in PATH")
+}
+
+func (missingDockerPrerequisites) GetDockerComposeCommand() string { return "podman-compose" }
+
+func TestEndToEndPodman(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	containerEngine = func() compose.Engine {
+		return compose.Engine{Name: compose.EnginePodman, Compose: "podman-compose", Socket: "/run/user/1000/podman/podman.sock"}
+	}
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"services:\n  api:\n    path: ./api\n    port: 8000\n" +
+		"components:\n  gateway:\n    template: traefik-gateway\n    path: ./gateway\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("compose failed: %v", err)
+	}
+	data, err := memFS.ReadFile(filepath.Join("demo", "docker-compose.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	composeFile := string(data)
+	if !strings.Contains(composeFile, "- /run/user/1000/podman/podman.sock:/var/run/docker.sock:ro") || !strings.Contains(composeFile, "- label=disable") {
+		t.Errorf("expected the gateway to mount the Podman socket with SELinux labeling disabled, got:\n%s", composeFile)
+	}
+	if strings.Contains(composeFile, "\nversion:") {
+		t.Errorf("expected no version key for podman-compose, got:\n%s", composeFile)
+	}
+
+	if err := runOM(t, nil, "doctor"); err != nil {
+		t.Errorf("doctor failed: %v", err)
+	}
+	dockerPrerequisites = missingDockerPrerequisites{}
+	if err := runOM(t, nil, "doctor"); exitCodeForError(err) != ExitCodeExternalTool {
+		t.Errorf("expected a missing engine to fail doctor as an external tool error, got %v", err)
+	}
+}
+
+func TestEndToEndPorts(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"services:\n  api:\n    path: ./api\n    port: 8000\n  web:\n    path: ./web\n    port: 3000\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	if err := runOM(t, nil, "ports"); err != nil {
+		t.Fatalf("expected ports to succeed before anything is published, got %v", err)
+	}
+
+	// Another project already runs on 3000
+	portAvailable = func(port int) bool { return port != 3000 }
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatal(err)
+	}
+	composeFile, err := memFS.ReadFile(filepath.Join("demo", "docker-compose.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(composeFile), "3001:3000") || !strings.Contains(string(composeFile), "8000:8000") {
+		t.Errorf("expected web to move to 3001 and api to keep 8000, got:\n%s", composeFile)
+	}
+
+	// Once recorded, ports stay where they are even when the project itself
+	// holds them
+	portAvailable = func(int) bool { return false }
+	if err := runOM(t, nil, "compose", "--target", "docker", "--force"); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := ports.Load(memFS, "demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.HostPort("api", 8000) != 8000 || lock.HostPort("web", 3000) != 3001 {
+		t.Errorf("expected the recorded ports to be kept, got %+v", lock.Ports)
+	}
+	if err := runOM(t, nil, "ports"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEndToEndOpen(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"services:\n  web:\n    path: ./web\n    port: 3000\n  mobile:\n    path: ./mobile\n    kind: local\n    dev: npx expo start\n    port: 8081\n" +
+		"  orders:\n    path: ./orders\n    port: 50051\n    protocol: grpc\n" +
+		"environments:\n  dev:\n    provider: aws\n    exclude: [mobile]\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lock := &ports.Lock{Ports: []ports.Allocation{{Service: "web", Container: 3000, Host: 3001, Requested: 3000}}}
+	if err := ports.Save(memFS, "demo", lock); err != nil {
+		t.Fatal(err)
+	}
+	state := &infrastate.State{Environment: "dev", Outputs: map[string]string{smoke.LoadBalancerOutput: "demo-alb.elb.example"}}
+	if err := infrastate.Save(memFS, "demo", state); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	var opened []string
+	original := browserOpener
+	browserOpener = func(url string) error {
+		opened = append(opened, url)
+		return nil
+	}
+	t.Cleanup(func() { browserOpener = original })
+
+	for _, args := range [][]string{{"open", "web"}, {"open", "mobile"}, {"open", "web", "--env", "dev"}, {"open", "web", "--print"}} {
+		if err := runOM(t, nil, args...); err != nil {
+			t.Fatalf("om %v failed: %v", args, err)
+		}
+	}
+	if want := "http://localhost:3001 http://localhost:8081 https://demo-alb.elb.example"; strings.Join(opened, " ") != want {
+		t.Errorf("expected %s to be opened, got %v", want, opened)
+	}
+
+	tests := []struct {
+		args []string
+		code int
+	}{
+		{[]string{"open", "billing"}, ExitCodeNotFound},
+		{[]string{"open", "orders"}, ExitCodeValidation},
+		{[]string{"open", "mobile", "--env", "dev"}, ExitCodeValidation},
+		{[]string{"open", "web", "--env", "prod"}, ExitCodeNotFound},
+	}
+	for _, tt := range tests {
+		if err := runOM(t, nil, tt.args...); exitCodeForError(err) != tt.code {
+			t.Errorf("om %v: expected exit code %d, got %v", tt.args, tt.code, err)
+		}
+	}
+}
+
+func TestEndToEndInspectService(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"services:\n  api:\n    path: ./api\n    port: 8000\n    resources:\n      db:\n        type: postgres\n        version: \"15\"\n" +
+		"  web:\n    path: ./web\n    port: 3000\n" +
+		"    environment:\n      API_URL: ${services.api.url}\n      DB_PASSWORD: ${services.api.resources.db.password}\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	// Before and after generating, the view resolves the same way
+	if err := runOM(t, nil, "inspect", "service", "web"); err != nil {
+		t.Fatalf("expected inspect to succeed before compose, got %v", err)
+	}
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatal(err)
+	}
+	if err := runOM(t, nil, "inspect", "service", "web"); err != nil {
+		t.Fatal(err)
+	}
+	if err := runOM(t, nil, "inspect", "service", "billing"); exitCodeForError(err) != ExitCodeNotFound {
+		t.Errorf("expected an unknown service to be not found, got %v", err)
+	}
+
+	parsed, err := loadManifest(filepath.Join("demo", "workbench.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := inspectResources(parsed, "web", parsed.Services["web"]); len(got) != 1 || !strings.Contains(got[0], "api.db: postgres 15, container api-db") || !strings.Contains(got[0], ".env (api_db_dbname, api_db_name, api_db_password, api_db_user)") {
+		t.Errorf("expected web to see the database of api, got %v", got)
+	}
+
+	// The generated file matches the resolved view until it is edited by hand
+	config, err := docker.NewGeneratorWithFS(memFS, "demo").Config(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	web := config.Services["web"]
+	if drift := composeDrift("demo", "web", web); len(drift) != 0 {
+		t.Errorf("expected docker-compose.yml to match, got drift in %v", drift)
+	}
+	web.Ports = []string{"3001:3000"}
+	if drift := composeDrift("demo", "web", web); strings.Join(drift, ",") != "ports" {
+		t.Errorf("expected the ports to drift, got %v", drift)
+	}
+}
+
+func TestEndToEndRefs(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"services:\n  api:\n    path: ./api\n    port: 8000\n  web:\n    path: ./web\n    port: 3000\n" +
+		"    environment:\n      API_URL: ${services.api.url}\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"refs", "api"}, {"grep", "web", "--manifest-only"}} {
+		if err := runOM(t, nil, args...); err != nil {
+			t.Errorf("om %v failed: %v", args, err)
+		}
+	}
+	if err := runOM(t, nil, "refs", "billing"); exitCodeForError(err) != ExitCodeNotFound {
+		t.Errorf("expected an unknown service to be not found, got %v", err)
+	}
+	if err := runOM(t, nil, "refs", "api.db.name"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected a path that names nothing to be a validation error, got %v", err)
+	}
+}
+
+func TestEndToEndLsGraph(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"services:\n  api:\n    template: express-api\n    path: ./api\n    port: 8000\n    resources:\n      db:\n        type: postgres\n        version: \"15\"\n        expose: host\n" +
+		"  web:\n    path: ./web\n    port: 3000\n" +
+		"components:\n  gateway:\n    template: nginx\n    path: ./gateway\n    ports: [\"8080:80\"]\n" +
+		"environments:\n  dev:\n    provider: aws\n    region: us-east-1\n    exclude: [web]\n  prod:\n    provider: aws\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lock := &ports.Lock{Ports: []ports.Allocation{
+		{Service: "api", Container: 8000, Host: 8001, Requested: 8000},
+		{Service: "api-db", Container: 5432, Host: 5432, Requested: 5432},
+	}}
+	if err := ports.Save(memFS, "demo", lock); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	for _, args := range [][]string{{"ls"}, {"ls", "--detailed"}, {"ls", "--wide"}, {"ls", "--json"}} {
+		if err := runOM(t, nil, args...); err != nil {
+			t.Errorf("om %v failed: %v", args, err)
+		}
+	}
+	if err := runOM(t, nil, "ls", "--json", "--wide"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected --json with --wide to be a validation error, got %v", err)
+	}
+
+	_, loaded, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	project := newLsProject(loaded, lock)
+	data, err := json.Marshal(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`{"kind":"service","name":"api","template":"express-api","ports":["8001:8000"],"environments":["dev","prod"]`,
+		`{"kind":"resource","name":"db","parent":"api","type":"postgres","version":"15","ports":["5432:5432"]}`,
+		`{"kind":"service","name":"web","ports":["3000:3000"],"environments":["prod"]}`,
+		`{"kind":"component","name":"gateway","template":"nginx","ports":["8080:80"],"environments":["dev","prod"]}`,
+		`"environments":[{"name":"dev","provider":"aws","region":"us-east-1"},{"name":"prod","provider":"aws"}]`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected the project graph to contain %s, got %s", want, data)
+		}
+	}
+}
+
+func TestEndToEndLsOwnership(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"services:\n  api:\n    path: ./api\n    port: 8000\n    team: payments\n    tags: [pci]\n" +
+		"  web:\n    path: ./web\n    port: 3000\n    owner: jane@example.com\n    description: Storefront\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	for _, args := range [][]string{{"ls", "--detailed"}, {"ls", "--team", "payments"}, {"ls", "--tag", "pci", "--owner", "nobody"}} {
+		if err := runOM(t, nil, args...); err != nil {
+			t.Errorf("om %v failed: %v", args, err)
+		}
+	}
+
+	_, loaded, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(slices.Sorted(maps.Keys(filterByOwnership(loaded, "payments", "", "pci").Services)), ","); got != "api" {
+		t.Errorf("expected only the payments service, got %v", got)
+	}
+	if got := slices.Sorted(maps.Keys(filterByOwnership(loaded, "", "jane@example.com", "pci").Services)); len(got) != 0 {
+		t.Errorf("expected filters to be combined, got %v", got)
+	}
+	if len(loaded.Services) != 2 {
+		t.Errorf("expected filtering to leave the manifest alone, got %v", slices.Sorted(maps.Keys(loaded.Services)))
+	}
+}
+
+func TestEndToEndGenerateCodeOwners(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"codeowners:\n  org: acme\n  default: [\"@acme/platform\"]\n" +
+		"services:\n  api:\n    path: ./api\n    port: 8000\n    team: payments\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	if err := runOM(t, nil, "generate", "codeowners", "--path", "CODEOWNERS.txt"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected a location GitHub does not read to be a validation error, got %v", err)
+	}
+	if err := runOM(t, nil, "generate", "codeowners", "--path", ".github/CODEOWNERS"); err != nil {
+		t.Fatalf("om generate codeowners failed: %v", err)
+	}
+
+	// Adding a service refreshes the generated file
+	if err := runOM(t, nil, "add", "service", "--name", "web", "--template", "demo-service", "--params", "ServiceName=web", "--team", "storefront"); err != nil {
+		t.Fatalf("om add service failed: %v", err)
+	}
+	data, err := memFS.ReadFile(filepath.Join("demo", ".github", "CODEOWNERS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"/api/ @acme/payments\n", "/web/ @acme/storefront\n", "/terraform/ @acme/platform\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in CODEOWNERS, got:\n%s", want, data)
+		}
+	}
+	if filesystem.Exists(memFS, filepath.Join("demo", "CODEOWNERS")) {
+		t.Error("expected the generated file to be refreshed where it is")
+	}
+
+	if err := runOM(t, nil, "add", "service", "--name", "docs", "--template", "demo-service", "--params", "ServiceName=docs", "--team", "docs, and more"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected an invalid team to be a validation error, got %v", err)
+	}
+	if filesystem.Exists(memFS, filepath.Join("demo", "docs")) {
+		t.Error("expected nothing to be scaffolded for an invalid team")
+	}
+}
+
+func TestEndToEndInfraPull(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"services:\n  api:\n    path: ./api\n    port: 8000\nenvironments:\n  dev:\n    provider: aws\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	originalRunner, originalClock := infraRunner, infraClock
+	infraRunner = func(dir, name string, args ...string) ([]byte, error) {
+		return []byte(`{"alb_dns_name": {"sensitive": false, "value": "demo-alb.elb.example"}, "db_password": {"sensitive": true, "value": "secret"}}`), nil
+	}
+	infraClock = func() time.Time { return time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC) }
+	t.Cleanup(func() { infraRunner, infraClock = originalRunner, originalClock })
+
+	if err := runOM(t, nil, "infra", "pull"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected --env to be required, got %v", err)
+	}
+	if err := runOM(t, nil, "infra", "pull", "--env", "prod"); exitCodeForError(err) != ExitCodeNotFound {
+		t.Errorf("expected an unknown environment to be not found, got %v", err)
+	}
+	if err := runOM(t, nil, "infra", "pull", "--env", "dev"); err != nil {
+		t.Fatalf("om infra pull failed: %v", err)
+	}
+	testutil.AssertGolden(t, "infra-pull", testutil.Snapshot(memFS, filepath.Join("demo", ".om", "state")))
+
+	// om smoke finds the recorded load balancer without running terraform
+	var requested []string
+	originalClient, originalSmokeRunner := smokeClient, smokeRunner
+	smokeClient = func(time.Duration) smoke.Doer {
+		return smokeResponder(func(req *http.Request) (*http.Response, error) {
+			requested = append(requested, req.URL.String())
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+		})
+	}
+	smokeRunner = func(dir, name string, args ...string) ([]byte, error) {
+		t.Errorf("unexpected %s %v with a recorded state", name, args)
+		return nil, nil
+	}
+	t.Cleanup(func() { smokeClient, smokeRunner = originalClient, originalSmokeRunner })
+
+	if err := runOM(t, nil, "smoke", "--env", "dev"); err != nil {
+		t.Fatalf("om smoke failed: %v", err)
+	}
+	if want := "https://demo-alb.elb.example/"; strings.Join(requested, " ") != want {
+		t.Errorf("expected requests to %s, got %v", want, requested)
+	}
+}
+
+func TestEndToEndCacheWarm(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	cacheDir := t.TempDir()
+	t.Setenv("OM_PACKAGE_CACHE", cacheDir)
+	withTemplatesFS(t, testutil.NewCatalog(map[string]testutil.Template{
+		"web": {
+			Manifest: `{"name": "web", "description": "Web app", "parameters": [{"name": "ServiceName", "prompt": "Name?", "type": "string", "required": true}]}`,
+			Files:    map[string]string{"package.json": `{"dependencies": {"react": "^18.2.0"}}`},
+		},
+		"admin": {
+			Manifest: `{"name": "admin", "description": "Admin app", "parameters": [{"name": "ServiceName", "prompt": "Name?", "type": "string", "required": true}]}`,
+			Files:    map[string]string{"package.json": `{"dependencies": {"react": "^18.2.0"}}`},
+		},
+		"api": {
+			Manifest: `{"name": "api", "description": "Python API", "parameters": [{"name": "ServiceName", "prompt": "Name?", "type": "string", "required": true}]}`,
+			Files:    map[string]string{"requirements.txt": "fastapi==0.109.0\n"},
+		},
+	}))
+
+	var commands []string
+	original := packageCacheRunner
+	packageCacheRunner = func(dir, name string, args ...string) ([]byte, error) {
+		commands = append(commands, name+" "+args[0])
+		return nil, nil
+	}
+	t.Cleanup(func() { packageCacheRunner = original })
+
+	// Templates with the same dependencies download them once
+	if err := runOM(t, nil, "cache", "warm", "--npm-registry", "http://localhost:4873/"); err != nil {
+		t.Fatalf("om cache warm failed: %v", err)
+	}
+	if strings.Join(commands, ", ") != "npm install, pip download" {
+		t.Errorf("expected one npm install and one pip download, got %v", commands)
+	}
+
+	// Scaffolds install through the cache and the recorded mirror
+	env := strings.Join(packageCacheEnvironment(), " ")
+	for _, variable := range []string{"npm_config_cache=" + filepath.Join(cacheDir, "npm"), "npm_config_registry=http://localhost:4873/", "PIP_FIND_LINKS=" + filepath.Join(cacheDir, "pip")} {
+		if !strings.Contains(env, variable) {
+			t.Errorf("expected the install environment to contain %s, got %s", variable, env)
+		}
+	}
+
+	err := runOM(t, nil, "cache", "warm", "missing")
+	if exitCodeForError(err) != ExitCodeNotFound {
+		t.Errorf("expected not-found exit code for an unknown template, got %d (%v)", exitCodeForError(err), err)
+	}
+
+	if err := runOM(t, nil, "cache", "warm", "--compose", "om-cache.yml"); err != nil {
+		t.Fatalf("om cache warm --compose failed: %v", err)
+	}
+	content, err := memFS.ReadFile("om-cache.yml")
+	if err != nil || !strings.Contains(string(content), "verdaccio/verdaccio") || !strings.Contains(string(content), "devpi") {
+		t.Errorf("expected a compose file with Verdaccio and devpi, got %q (%v)", content, err)
+	}
+}
+
+func TestEndToEndSecrets(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"services:\n  api:\n    path: ./api\n    port: 8000\n    resources:\n      db:\n        type: postgres-db\n" +
+		"      cache:\n        type: redis-cache\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+	readEnv := func() map[string]string {
+		t.Helper()
+		values, err := compose.ReadEnvFile(memFS, filepath.Join("demo", ".env"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return values
+	}
+
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("compose failed: %v", err)
+	}
+	generated := readEnv()
+	if len(generated["api_db_password"]) != 32 || generated["api_db_password"] == generated["api_cache_password"] {
+		t.Fatalf("expected distinct generated passwords, got %v", generated)
+	}
+
+	// Regenerating keeps the passwords the database was created with
+	if err := runOM(t, nil, "compose", "--target", "docker", "--force"); err != nil {
+		t.Fatal(err)
+	}
+	if kept := readEnv(); kept["api_db_password"] != generated["api_db_password"] {
+		t.Errorf("expected compose to keep %q, got %q", generated["api_db_password"], kept["api_db_password"])
+	}
+
+	if err := runOM(t, nil, "secrets", "rotate", "api_web_password"); exitCodeForError(err) != ExitCodeNotFound {
+		t.Errorf("expected an unknown secret to be not found, got %v", err)
+	}
+	if err := runOM(t, map[string]interface{}{"rotateSecrets": false}, "secrets", "rotate"); exitCodeForError(err) != ExitCodeCancelled {
+		t.Errorf("expected declining to cancel the rotation, got %v", err)
+	}
+	if err := runOM(t, map[string]interface{}{"rotateSecrets": true}, "secrets", "rotate", "api_db_password"); err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+	rotated := readEnv()
+	if rotated["api_db_password"] == generated["api_db_password"] || len(rotated["api_db_password"]) != 32 {
+		t.Errorf("expected a new api_db_password, got %q", rotated["api_db_password"])
+	}
+	if rotated["api_cache_password"] != generated["api_cache_password"] || rotated["api_db_user"] != generated["api_db_user"] {
+		t.Errorf("expected the other variables to be kept, got %v", rotated)
+	}
+
+	// Secrets of external stores are read on every run and rotated there
+	external := manifest + "secrets:\n  provider: vault\n  source: secret/demo\n"
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(external), 0644); err != nil {
+		t.Fatal(err)
+	}
+	originalRunner := secretsRunner
+	secretsRunner = func(dir, name string, args ...string) ([]byte, error) {
+		return []byte(`{"data": {"data": {"api_db_password": "from-vault", "api_cache_password": "cache-vault"}}}`), nil
+	}
+	t.Cleanup(func() { secretsRunner = originalRunner })
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("compose with vault secrets failed: %v", err)
+	}
+	if values := readEnv(); values["api_db_password"] != "from-vault" || values["api_cache_password"] != "cache-vault" {
+		t.Errorf("expected the passwords from vault, got %v", values)
+	}
+	if err := runOM(t, nil, "secrets", "rotate", "--yes"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected rotating vault secrets to be a validation error, got %v", err)
+	}
+}
+
+func TestEndToEndEnvList(t *testing.T) {
+	memFS := e2eWorkspace(t)
+	manifest := "apiVersion: openworkbench.io/v1alpha1\nkind: Project\nmetadata:\n  name: demo\n" +
+		"services:\n  api:\n    path: ./api\n    port: 8000\n" +
+		"    environment:\n      LOG_LEVEL: info\n    envDocs:\n      LOG_LEVEL: How much the API logs\n" +
+		"    resources:\n      db:\n        type: postgres-db\n        version: \"15\"\n        envDocs:\n          password: Also used by the backup job\n"
+	if err := memFS.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, "demo")
+
+	if err := runOM(t, nil, "env", "list"); err != nil {
+		t.Fatalf("env list failed before compose: %v", err)
+	}
+	if err := runOM(t, nil, "compose", "--target", "docker"); err != nil {
+		t.Fatalf("compose failed: %v", err)
+	}
+	data, err := memFS.ReadFile(filepath.Join("demo", ".env.example"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	example := string(data)
+	for _, expected := range []string{
+		"# Required by api.db (postgres 15)\n",
+		"# Also used by the backup job\napi_db_password=\n",
+		"# User the service connects to the database as\napi_db_user=\n",
+	} {
+		if !strings.Contains(example, expected) {
+			t.Errorf("expected .env.example to contain %q, got:\n%s", expected, example)
+		}
+	}
+	if err := runOM(t, nil, "env", "list"); err != nil {
+		t.Errorf("env list failed: %v", err)
+	}
+
+	undocumented := strings.Replace(manifest, "LOG_LEVEL: How", "LOG_FORMAT: How", 1)
+	if err := memFS.WriteFile(filepath.Join("demo", "workbench.yaml"), []byte(undocumented), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runOM(t, nil, "env", "list"); exitCodeForError(err) != ExitCodeValidation {
+		t.Errorf("expected envDocs for an unset variable to be a validation error, got %v", err)
+	}
+}
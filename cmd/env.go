@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/spf13/cobra"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Show the environment variables of the project",
+}
+
+var envListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the environment variables of the project and what they are for",
+	Long: `List the variables 'om compose' writes to .env, grouped by the resource
+that needs them as in .env.example, and the environment of each service, with
+their descriptions.
+
+Resource variables are described by their blueprint; the envDocs of a
+resource or service in workbench.yaml describe them in the project's own
+words:
+
+  services:
+    api:
+      environment:
+        LOG_LEVEL: info
+      envDocs:
+        LOG_LEVEL: How much the API logs (debug, info, warn)
+      resources:
+        db:
+          type: postgres
+          envDocs:
+            password: Also used by the nightly backup job
+
+Examples:
+  # Show every variable and whether .env sets it
+  om env list`,
+	Args: cobra.NoArgs,
+	RunE: runEnvList,
+}
+
+// initEnvCommand registers the env command and its subcommands
+func initEnvCommand() {
+	envCmd.AddCommand(envListCmd)
+	if rootCmd != nil {
+		rootCmd.AddCommand(envCmd)
+	}
+}
+
+// runEnvList prints the documented variables of .env and of each service's
+// environment
+func runEnvList(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+	current, err := compose.ReadEnvFile(workspaceFS, filepath.Join(projectRoot, ".env"))
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	printed := false
+	heading := func(title string) {
+		if printed {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "# %s\n", title)
+		printed = true
+	}
+
+	// The variables of .env, as .env.example groups them
+	for _, section := range compose.EnvSections(manifest) {
+		heading(section.Title)
+		for _, variable := range section.Variables {
+			state := "set in .env"
+			if _, exists := current[variable.Name]; !exists {
+				state = "missing, run 'om compose'"
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", variable.Name, state, variable.Description)
+		}
+	}
+
+	// The environment workbench.yaml gives each service
+	for _, name := range slices.Sorted(maps.Keys(manifest.Services)) {
+		service := manifest.Services[name]
+		if len(service.Environment) == 0 {
+			continue
+		}
+		heading("Environment of " + name)
+		for _, key := range slices.Sorted(maps.Keys(service.Environment)) {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", key, service.Environment[key], service.EnvDocs[key])
+		}
+	}
+
+	if !printed {
+		fmt.Println("✨ No environment variables in workbench.yaml")
+		return nil
+	}
+	return w.Flush()
+}
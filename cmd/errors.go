@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/jashkahar/open-workbench-platform/internal/generator"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by the om binary. Scripts can rely on these values
+// to branch on the category of failure instead of parsing output.
+const (
+	// ExitCodeOK indicates the command completed successfully
+	ExitCodeOK = 0
+	// ExitCodeError indicates an unexpected or uncategorized failure
+	ExitCodeError = 1
+	// ExitCodeValidation indicates invalid input, flags, templates, or manifest contents
+	ExitCodeValidation = 2
+	// ExitCodeCancelled indicates the user cancelled an interactive prompt
+	ExitCodeCancelled = 3
+	// ExitCodeExternalTool indicates a required external tool is missing or failed
+	ExitCodeExternalTool = 4
+	// ExitCodeGeneration indicates scaffolding or configuration generation failed
+	ExitCodeGeneration = 5
+	// ExitCodeNotFound indicates the project, template, or requested item does not exist
+	ExitCodeNotFound = 6
+	// ExitCodeFileSystem indicates a file system or permission failure
+	ExitCodeFileSystem = 7
+)
+
+// exitCodeError attaches an exit code to an error raised by the command layer
+type exitCodeError struct {
+	code  int
+	err   error
+	usage bool // whether the error came from invalid flags or arguments
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// newValidationError creates an error for invalid user input that maps to ExitCodeValidation
+func newValidationError(format string, args ...interface{}) error {
+	return &exitCodeError{code: ExitCodeValidation, err: fmt.Errorf(format, args...)}
+}
+
+// newNotFoundError creates an error for a missing service, resource, or template that maps to ExitCodeNotFound
+func newNotFoundError(format string, args ...interface{}) error {
+	return &exitCodeError{code: ExitCodeNotFound, err: fmt.Errorf(format, args...)}
+}
+
+// newFileSystemError creates an error for a directory that cannot hold a scaffold that maps to ExitCodeFileSystem
+func newFileSystemError(format string, args ...interface{}) error {
+	return &exitCodeError{code: ExitCodeFileSystem, err: fmt.Errorf(format, args...)}
+}
+
+// newUsageError wraps a flag or argument parsing error reported by Cobra
+func newUsageError(err error) error {
+	return &exitCodeError{code: ExitCodeValidation, err: err, usage: true}
+}
+
+// newCancelledError creates an error for an operation the user declined that maps to ExitCodeCancelled
+func newCancelledError(format string, args ...interface{}) error {
+	return &exitCodeError{code: ExitCodeCancelled, err: fmt.Errorf(format, args...)}
+}
+
+// exitCodeForError maps an error returned by a command to its exit code
+func exitCodeForError(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+
+	if errors.Is(err, terminal.InterruptErr) {
+		return ExitCodeCancelled
+	}
+
+	var codeErr *exitCodeError
+	if errors.As(err, &codeErr) {
+		return codeErr.code
+	}
+
+	var templateErr *templating.TemplateError
+	if errors.As(err, &templateErr) {
+		switch templateErr.Type {
+		case templating.ErrorTypeTemplateNotFound:
+			return ExitCodeNotFound
+		case templating.ErrorTypeInvalidManifest, templating.ErrorTypeParameterValidation:
+			return ExitCodeValidation
+		case templating.ErrorTypeCommandExecution, templating.ErrorTypeNetwork:
+			return ExitCodeExternalTool
+		case templating.ErrorTypeFileSystem, templating.ErrorTypePermission:
+			return ExitCodeFileSystem
+		default:
+			return ExitCodeGeneration
+		}
+	}
+
+	var manifestErr *manifestPkg.ManifestError
+	if errors.As(err, &manifestErr) {
+		if manifestErr.Type == manifestPkg.ErrorTypeNotFound {
+			return ExitCodeNotFound
+		}
+		return ExitCodeValidation
+	}
+
+	var generatorErr *generator.GeneratorError
+	if errors.As(err, &generatorErr) {
+		switch generatorErr.Type {
+		case generator.ErrorTypeValidation:
+			return ExitCodeValidation
+		case generator.ErrorTypePrerequisite:
+			return ExitCodeExternalTool
+		default:
+			return ExitCodeGeneration
+		}
+	}
+
+	return ExitCodeError
+}
+
+// isCancellation reports whether err was caused by the user cancelling the operation
+func isCancellation(err error) bool {
+	return exitCodeForError(err) == ExitCodeCancelled
+}
+
+// presentError writes a user-facing description of err. Template errors are
+// shown with their suggestions, cancellations are reported without noise, and
+// usage errors point at the failing command's help.
+func presentError(w io.Writer, err error, failedCmd *cobra.Command) {
+	if err == nil {
+		return
+	}
+
+	if isCancellation(err) {
+		fmt.Fprintln(w, "\nOperation cancelled.")
+		return
+	}
+
+	if templating.IsTemplateError(err) {
+		fmt.Fprintf(w, "❌ %s\n", strings.TrimRight(templating.FormatErrorForUser(err), "\n"))
+		return
+	}
+
+	fmt.Fprintf(w, "❌ Error: %s\n", err)
+
+	var codeErr *exitCodeError
+	if errors.As(err, &codeErr) && codeErr.usage && failedCmd != nil {
+		fmt.Fprintf(w, "Run '%s --help' for usage.\n", failedCmd.CommandPath())
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/jashkahar/open-workbench-platform/internal/generator"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+	"github.com/spf13/cobra"
+)
+
+func TestExitCodeForError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"nil error", nil, ExitCodeOK},
+		{"generic error", errors.New("boom"), ExitCodeError},
+		{"interrupt", fmt.Errorf("failed to get project name: %w", terminal.InterruptErr), ExitCodeCancelled},
+		{"cancelled", newCancelledError("deletion cancelled"), ExitCodeCancelled},
+		{"validation", newValidationError("invalid target '%s'", "k8s"), ExitCodeValidation},
+		{"not found", newNotFoundError("service '%s' not found", "api"), ExitCodeNotFound},
+		{"template not found", templating.NewTemplateNotFoundError("missing", nil), ExitCodeNotFound},
+		{"template parameter", fmt.Errorf("invalid value: %w", templating.NewParameterValidationError("Port", "x", "bad", nil)), ExitCodeValidation},
+		{"template command", templating.NewCommandExecutionError("npm install", "Installing", nil), ExitCodeExternalTool},
+		{"template filesystem", templating.NewFileSystemError("write", "a.txt", errors.New("disk full")), ExitCodeFileSystem},
+		{"template processing", templating.NewTemplateProcessingError("t", "bad", nil), ExitCodeGeneration},
+		{"manifest not found", manifestPkg.NewNotFoundError("current directory", nil), ExitCodeNotFound},
+		{"manifest parse", fmt.Errorf("failed to load: %w", manifestPkg.NewParseError("workbench.yaml", errors.New("bad yaml"))), ExitCodeValidation},
+		{"generator validation", generator.NewValidationError("docker", errors.New("no services")), ExitCodeValidation},
+		{"generator prerequisite", fmt.Errorf("failed: %w", generator.NewPrerequisiteError("docker", errors.New("docker not found"))), ExitCodeExternalTool},
+		{"generator generation", generator.NewGenerationError("docker", "failed to save", errors.New("denied")), ExitCodeGeneration},
+		{"validation name", func() error { _, err := ValidateAndSanitizeName("", nil); return err }(), ExitCodeValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCodeForError(tt.err); got != tt.expected {
+				t.Errorf("exitCodeForError(%v) = %d, want %d", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPresentError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		contains string
+		excludes string
+	}{
+		{"cancelled", fmt.Errorf("could not select template: %w", terminal.InterruptErr), "Operation cancelled.", "❌"},
+		{"template error keeps suggestions", fmt.Errorf("failed to scaffold service: %w", templating.NewTemplateNotFoundError("missing", nil)), "Possible solutions:", "unexpected error"},
+		{"usage error", newUsageError(errors.New("unknown flag: --bogus")), "Run 'om --help' for usage.", ""},
+		{"generic error", errors.New("boom"), "❌ Error: boom", "--help"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			presentError(&buf, tt.err, &cobra.Command{Use: "om"})
+			output := buf.String()
+
+			if !strings.Contains(output, tt.contains) {
+				t.Errorf("expected output to contain %q, got %q", tt.contains, output)
+			}
+			if tt.excludes != "" && strings.Contains(output, tt.excludes) {
+				t.Errorf("expected output not to contain %q, got %q", tt.excludes, output)
+			}
+		})
+	}
+}
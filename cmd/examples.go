@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/examples"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+	"github.com/spf13/cobra"
+)
+
+// exampleTempDir creates the directory om examples create --temp scaffolds
+// into. Tests replace it.
+var exampleTempDir = func() (string, error) {
+	return os.MkdirTemp("", "om-example-")
+}
+
+var examplesCmd = &cobra.Command{
+	Use:   "examples",
+	Short: "Scaffold runnable example projects",
+	Long: `Scaffold complete example projects to try the whole workflow in one command.
+
+Examples are built into om. Each is a small but working project of several
+services and resources, scaffolded from the built-in templates with the
+answers and code the demo needs.
+
+Examples:
+  # See the available examples
+  om examples list
+
+  # Scaffold the todo app (React, Express, PostgreSQL) and run it
+  om examples create todo-app
+  cd todo-app && om dev`,
+}
+
+var examplesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the example projects",
+	Args:  cobra.NoArgs,
+	RunE:  runExamplesList,
+}
+
+var examplesCreateCmd = &cobra.Command{
+	Use:   "create <example> [directory]",
+	Short: "Scaffold an example project",
+	Long: `Scaffold an example project into a new directory.
+
+The project is created in the given directory, which must not exist or be
+empty, or in a directory named after the example in the current directory.
+With --temp, it is created in a new temporary directory instead, which keeps
+the current directory clean while evaluating om.
+
+The result is an ordinary project: run it with om dev, and change it with om
+add service, om add resource, and the other commands.
+
+Examples:
+  om examples create todo-app
+  om examples create todo-app ~/demos/todo
+  om examples create hello-api --temp`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeExampleNames,
+	RunE:              runExamplesCreate,
+}
+
+// initExamplesCommand registers the examples command and its subcommands
+func initExamplesCommand() {
+	examplesCmd.AddCommand(examplesListCmd)
+	examplesCmd.AddCommand(examplesCreateCmd)
+	if rootCmd != nil {
+		rootCmd.AddCommand(examplesCmd)
+	}
+
+	examplesCreateCmd.Flags().Bool("temp", false, "Create the project in a new temporary directory")
+}
+
+// runExamplesList prints every example with its services
+func runExamplesList(cmd *cobra.Command, args []string) error {
+	list, err := examples.List()
+	if err != nil {
+		return fmt.Errorf("failed to read the examples: %w", err)
+	}
+	fmt.Println("📚 Example projects:")
+	for _, example := range list {
+		fmt.Printf("\n  %s — %s\n", example.Name, example.Description)
+		for _, service := range example.Services {
+			line := fmt.Sprintf("    • %s (%s)", service.Name, service.Template)
+			for _, resource := range service.Resources {
+				line += fmt.Sprintf(" + %s", resource.Type)
+			}
+			fmt.Println(line)
+		}
+	}
+	fmt.Println("\nCreate one with: om examples create <example>")
+	return nil
+}
+
+// runExamplesCreate scaffolds an example project
+func runExamplesCreate(cmd *cobra.Command, args []string) error {
+	example, err := examples.Get(args[0])
+	if errors.Is(err, fs.ErrNotExist) {
+		return newNotFoundError("example '%s' not found; available examples: %s", args[0], strings.Join(examples.Names(), ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	projectDir, err := exampleProjectDir(cmd, example, args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📦 Creating example '%s' in %s\n", example.Name, displayPath(projectDir))
+	created := !filesystem.Exists(workspaceFS, projectDir)
+	if err := scaffoldExample(example, projectDir); err != nil {
+		if created {
+			workspaceFS.RemoveAll(projectDir)
+		}
+		return err
+	}
+	printExampleSuccessMessage(example, projectDir)
+	return nil
+}
+
+// exampleProjectDir returns the directory to scaffold an example into and
+// checks that it is empty
+func exampleProjectDir(cmd *cobra.Command, example *examples.Example, args []string) (string, error) {
+	temp, _ := cmd.Flags().GetBool("temp")
+	if temp {
+		if len(args) > 1 {
+			return "", newValidationError("--temp cannot be combined with a directory")
+		}
+		dir, err := exampleTempDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to create a temporary directory: %w", err)
+		}
+		return filepath.Join(dir, example.Name), nil
+	}
+
+	target := example.Name
+	if len(args) > 1 {
+		target = args[1]
+	}
+	if !filepath.IsAbs(target) {
+		cwd, err := workingDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+		target = filepath.Join(cwd, target)
+	}
+	if entries, err := workspaceFS.ReadDir(target); err == nil && len(entries) > 0 {
+		return "", newValidationError("directory '%s' already exists and is not empty", displayPath(target))
+	}
+	if err := checkNotInsideProject(filepath.Dir(target)); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// scaffoldExample scaffolds every service of an example, writes the
+// example's own files over them, and creates workbench.yaml
+func scaffoldExample(example *examples.Example, projectDir string) error {
+	catalog := templating.NewTemplateCatalog(templatesFS)
+	manifest := &manifestPkg.WorkbenchManifest{
+		APIVersion: "openworkbench.io/v1alpha1",
+		Kind:       "Project",
+		Metadata:   manifestPkg.ProjectMetadata{Name: example.Name},
+		Services:   make(map[string]manifestPkg.Service),
+	}
+	owner := userProfile().Owner
+
+	// Every service is scaffolded before any installs dependencies, so the
+	// installs run in parallel and see the example's own files
+	var jobs []postScaffoldJob
+	for _, service := range example.Services {
+		fmt.Printf("🏗️  Scaffolding %s from %s...\n", service.Name, service.Template)
+		servicePath := filepath.Join(projectDir, service.Name)
+		params := seedParameterDefaults(service.Params(), projectParameterDefaults(servicePath, "", owner))
+		if err := validateTemplateAndParameters(catalog, service.Template, params); err != nil {
+			return fmt.Errorf("example '%s' does not match template '%s': %w", example.Name, service.Template, err)
+		}
+		if err := workspaceFS.MkdirAll(servicePath, 0755); err != nil {
+			return fmt.Errorf("failed to create service directory: %w", err)
+		}
+		processor, err := scaffoldServiceFiles(catalog, service.Template, servicePath, params)
+		if err != nil {
+			return fmt.Errorf("failed to scaffold service '%s': %w", service.Name, err)
+		}
+		jobs = append(jobs, postScaffoldJob{name: service.Name, dir: servicePath, processor: processor})
+
+		files, err := example.Files(service.Name)
+		if err != nil {
+			return err
+		}
+		for name, data := range files {
+			target := filepath.Join(servicePath, filepath.FromSlash(name))
+			if err := workspaceFS.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+			}
+			if err := workspaceFS.WriteFile(target, data, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path.Join(service.Name, name), err)
+			}
+		}
+
+		entry := manifestPkg.Service{
+			Template:    service.Template,
+			Path:        service.Name,
+			Port:        defaultServicePort(service.Template),
+			Protocol:    defaultServiceProtocol(service.Template),
+			Kind:        defaultServiceKind(service.Template),
+			Dev:         defaultDevCommand(service.Template),
+			Environment: service.Environment,
+		}
+		for _, resource := range service.Resources {
+			if entry.Resources == nil {
+				entry.Resources = make(map[string]manifestPkg.Resource)
+			}
+			entry.Resources[resource.Name] = manifestPkg.Resource{Type: resource.Type, Version: resource.Version, Config: resource.Config}
+		}
+		manifest.Services[service.Name] = entry
+	}
+
+	runPostScaffoldCommands(jobs)
+
+	if err := saveWorkbenchManifest(manifest, projectDir); err != nil {
+		return fmt.Errorf("failed to write workbench.yaml: %w", err)
+	}
+	return nil
+}
+
+// printExampleSuccessMessage prints how to run the new example project
+func printExampleSuccessMessage(example *examples.Example, projectDir string) {
+	fmt.Println("------------------------------------")
+	fmt.Printf("✅ Example '%s' is ready: %s\n", example.Name, example.Description)
+	fmt.Println()
+	fmt.Println("📁 Project structure:")
+	fmt.Printf("  %s/\n", filepath.Base(projectDir))
+	fmt.Println("  ├── workbench.yaml")
+	for i, service := range example.Services {
+		branch := "├──"
+		if i == len(example.Services)-1 {
+			branch = "└──"
+		}
+		fmt.Printf("  %s %s/\n", branch, service.Name)
+	}
+	fmt.Println()
+	fmt.Println("🚀 Next steps:")
+	fmt.Printf("  cd %s\n", displayPath(projectDir))
+	fmt.Println("  om dev          # Build, start, and follow the logs of every service")
+	fmt.Println("  om ls           # See what the project is made of")
+}
+
+// completeExampleNames completes the example argument of om examples create
+func completeExampleNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+	list, err := examples.List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, example := range list {
+		names = append(names, example.Name+"\t"+example.Description)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
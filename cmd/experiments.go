@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/experiments"
+	"github.com/spf13/cobra"
+)
+
+// experimentAnnotation marks a command, and every command below it, as part
+// of an experiment: Annotations: map[string]string{experimentAnnotation: "tui"}
+const experimentAnnotation = "experiment"
+
+var experimentsCmd = &cobra.Command{
+	Use:   "experiments",
+	Short: "List experimental features and whether they are enabled",
+	Long: `List experimental features and whether they are enabled.
+
+New subsystems ship behind experiments until they are ready for everyone.
+Enable them with the OM_EXPERIMENTS environment variable or the experiments
+list of ~/.om/config.yaml; the variable wins:
+
+  OM_EXPERIMENTS=kubernetes,tui om compose --target kubernetes
+
+  # ~/.om/config.yaml
+  experiments: [kubernetes]
+
+"all" enables every experiment and "beta" every beta one; "-name" disables
+one, including stable experiments, which are on by default.`,
+	Args: cobra.NoArgs,
+	RunE: runExperiments,
+}
+
+// initExperimentsCommand registers the experiments command
+func initExperimentsCommand() {
+	if rootCmd != nil {
+		rootCmd.AddCommand(experimentsCmd)
+	}
+}
+
+// runExperiments prints every experiment with its stage and state
+func runExperiments(cmd *cobra.Command, args []string) error {
+	settings, err := loadExperiments()
+	if err != nil {
+		return err
+	}
+	for _, experiment := range experiments.Known {
+		state := "disabled"
+		if settings.Enabled(experiment.Name) {
+			state = "enabled"
+		}
+		fmt.Printf("%-12s %-7s %-9s %s\n", experiment.Name, experiment.Stage, state, experiment.Description)
+	}
+	return nil
+}
+
+// loadExperiments reads the user's experiment settings, warning about names
+// this version of om does not know
+func loadExperiments() (*experiments.Settings, error) {
+	configPath, err := userConfigPath()
+	if err != nil {
+		configPath = ""
+	}
+	settings, unknown, err := experiments.Load(workspaceFS, configPath, os.Getenv(experiments.EnvVar))
+	if err != nil {
+		return nil, newValidationError("invalid experiment settings: %v", err)
+	}
+	if len(unknown) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  Ignoring unknown experiments: %s\n", strings.Join(unknown, ", "))
+	}
+	return settings, nil
+}
+
+// requireExperiment fails unless the named experiment is enabled, and
+// prints its banner when it is. Subsystems that are not commands of their
+// own, such as a deployment target, call it before doing any work.
+func requireExperiment(name string) error {
+	experiment, exists := experiments.Lookup(name)
+	if !exists {
+		return fmt.Errorf("unknown experiment '%s'", name)
+	}
+	settings, err := loadExperiments()
+	if err != nil {
+		return err
+	}
+	if !settings.Enabled(name) {
+		return newValidationError("%v", experiments.DisabledError(experiment))
+	}
+	fmt.Fprintln(os.Stderr, experiments.Banner(experiment))
+	return nil
+}
+
+// checkExperimentalCommand applies requireExperiment to commands marked with
+// experimentAnnotation, directly or through a parent
+func checkExperimentalCommand(cmd *cobra.Command) error {
+	for c := cmd; c != nil; c = c.Parent() {
+		if name, marked := c.Annotations[experimentAnnotation]; marked {
+			return requireExperiment(name)
+		}
+	}
+	return nil
+}
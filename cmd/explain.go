@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/explain"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <file>:<line>",
+	Short: "Explain which workbench.yaml entry produced a generated line",
+	Long: `Explain where a line of a generated file comes from.
+
+Files generated by 'om compose' carry marker comments naming the workbench.yaml
+entry behind each block. Given a file and line, this command finds the entry,
+describes the rule that generated the line, and tells you what to edit in
+workbench.yaml instead of the generated file.
+
+Examples:
+  # Explain line 12 of docker-compose.yml
+  om explain docker-compose.yml:12
+
+  # The same, with the line as a flag
+  om explain terraform/main.tf --line 40
+
+  # List every marked block of a file
+  om explain docker-compose.yml`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExplain,
+}
+
+// initExplainCommand registers the explain command with the root command
+func initExplainCommand() {
+	if rootCmd != nil {
+		rootCmd.AddCommand(explainCmd)
+	}
+
+	explainCmd.Flags().Int("line", 0, "Line of the file to explain")
+}
+
+// runExplain finds the manifest entry behind a line of a generated file
+func runExplain(cmd *cobra.Command, args []string) error {
+	file, line, err := parseFileAndLine(args[0])
+	if err != nil {
+		return err
+	}
+	if flagLine, _ := cmd.Flags().GetInt("line"); flagLine > 0 {
+		line = flagLine
+	}
+
+	dir, err := workingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	path := file
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, file)
+	}
+	content, err := workspaceFS.ReadFile(path)
+	if err != nil {
+		return newNotFoundError("failed to read %s: %w", file, err)
+	}
+
+	if line == 0 {
+		return listMarkers(file, content)
+	}
+
+	origin, markerLine, found, err := explain.Find(content, line)
+	if err != nil {
+		return newValidationError("%s: %w", file, err)
+	}
+	if !found {
+		fmt.Printf("ℹ️  %s:%d is not derived from a workbench.yaml entry.\n", file, line)
+		fmt.Println("   It is a fixed part of the generator output; edits to it are lost the next time the file is generated.")
+		return nil
+	}
+
+	fmt.Printf("🔎 %s:%d was generated from %s (marker on line %d)\n", file, line, origin.Entry, markerLine)
+	if rule, ok := origin.Describe(); ok {
+		fmt.Printf("   Rule: %s — %s\n", origin.Rule, rule.Description)
+		fmt.Printf("   To change it: %s\n", rule.Change)
+	}
+
+	// Point at the manifest file that defines the entry
+	if _, manifest, err := findProjectRootAndLoadManifest(); err == nil {
+		describeManifestEntry(manifest, origin.Entry)
+	}
+	return nil
+}
+
+// parseFileAndLine splits "file:line" into its parts. The line is 0 when
+// the argument has none.
+func parseFileAndLine(arg string) (string, int, error) {
+	separator := strings.LastIndex(arg, ":")
+	if separator <= 0 {
+		return arg, 0, nil
+	}
+	line, err := strconv.Atoi(arg[separator+1:])
+	if err != nil {
+		// Not a line number, e.g. a Windows drive letter
+		return arg, 0, nil
+	}
+	if line < 1 {
+		return "", 0, newValidationError("invalid line number %d", line)
+	}
+	return arg[:separator], line, nil
+}
+
+// listMarkers prints every marked block of a generated file
+func listMarkers(file string, content []byte) error {
+	markers := explain.Markers(content)
+	if len(markers) == 0 {
+		fmt.Printf("ℹ️  %s has no om markers. Regenerate it with 'om compose' to trace its lines.\n", file)
+		return nil
+	}
+
+	fmt.Printf("🔎 Blocks of %s:\n", file)
+	for _, marker := range markers {
+		fmt.Printf("  %4d  %s (%s)\n", marker.Line, marker.Origin.Entry, marker.Origin.Rule)
+	}
+	return nil
+}
+
+// describeManifestEntry reports where a services.<name> or components.<name>
+// entry is defined, or that it no longer exists
+func describeManifestEntry(manifest *manifestPkg.WorkbenchManifest, entry string) {
+	parts := strings.Split(entry, ".")
+	if len(parts) < 2 || parts[1] == "*" {
+		return
+	}
+
+	var exists bool
+	switch parts[0] {
+	case "services":
+		_, exists = manifest.Services[parts[1]]
+	case "components":
+		_, exists = manifest.Components[parts[1]]
+	case "external":
+		_, exists = manifest.External[parts[1]]
+	default:
+		return
+	}
+
+	if !exists {
+		fmt.Printf("⚠️  %s.%s is no longer in workbench.yaml; regenerate the file to remove it.\n", parts[0], parts[1])
+		return
+	}
+	source := manifest.Source(parts[0], parts[1])
+	if source == "" {
+		source = "workbench.yaml"
+	}
+	fmt.Printf("   Defined in: %s\n", source)
+}
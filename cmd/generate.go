@@ -0,0 +1,431 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/clientgen"
+	"github.com/jashkahar/open-workbench-platform/internal/codeowners"
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/loadtest"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/projectdocs"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+	"github.com/spf13/cobra"
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate supporting files for the project",
+	Long: `Generate supporting files from workbench.yaml that are not part of any service,
+such as load tests and project documentation.`,
+}
+
+var generateLoadTestCmd = &cobra.Command{
+	Use:   "loadtest",
+	Short: "Generate k6 load tests for the project's HTTP services",
+	Long: `Generate a k6 smoke test for every HTTP service in workbench.yaml.
+
+This command writes one script per service into loadtest/, targeting the
+service's address inside the Docker Compose network, and a
+docker-compose.loadtest.yml overlay with a k6 runner per service, InfluxDB,
+and a Grafana dashboard. Every container is in the "loadtest" profile.
+
+Examples:
+  # Load test every HTTP service
+  om generate loadtest
+
+  # Only the backend service
+  om generate loadtest --service backend
+
+  # Run the tests against the stack generated by 'om compose'
+  docker compose -f docker-compose.yml -f docker-compose.loadtest.yml --profile loadtest up
+
+Results are charted at http://localhost:3030.`,
+	Args: cobra.NoArgs,
+	RunE: runGenerateLoadTest,
+}
+
+var generateDocsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate the project's README section and ARCHITECTURE.md",
+	Long: `Generate project documentation from workbench.yaml.
+
+This command writes a services table and getting-started commands into
+README.md, and an ARCHITECTURE.md with the services, components, resource
+inventory, environment matrix, and a Mermaid diagram of the dependencies
+between them.
+
+Generated content sits between <!-- om:docs:begin --> and <!-- om:docs:end -->
+markers. Running the command again replaces only that block, so text written
+around it is kept. Files without a block get one appended.
+
+The README of every service scaffolded by 'om init' or 'om add service' has
+such a block too, with the template parameters it was created with, how to
+run it with the project and on its own, and its port, variables, resources,
+and dependencies. This command refreshes those blocks as well.
+
+Examples:
+  # Generate or refresh the docs
+  om generate docs`,
+	Args: cobra.NoArgs,
+	RunE: runGenerateDocs,
+}
+
+var generateClientsCmd = &cobra.Command{
+	Use:   "clients",
+	Short: "Generate typed API clients for the services each service consumes",
+	Long: `Generate typed API clients between services from their OpenAPI documents.
+
+A service lists the services it calls under consumes in workbench.yaml; each
+consumed service needs an api.spec. For every entry this command generates a
+client into the consuming service with the generator for its language:
+
+  - Node services (package.json): openapi-typescript, into src/clients/<service>.ts
+  - Python services (pyproject.toml or requirements.txt): openapi-python-client,
+    into clients/<service>/
+
+The consumer also gets a <SERVICE>_API_URL variable set to
+${services.<service>.url}, which 'om compose' resolves to the service's
+address, unless the variable is already defined.
+
+Examples:
+  # Generate every client
+  om generate clients
+
+  # Only the clients used by the web service
+  om generate clients --service web`,
+	Args: cobra.NoArgs,
+	RunE: runGenerateClients,
+}
+
+var generateCodeOwnersCmd = &cobra.Command{
+	Use:   "codeowners",
+	Short: "Generate a CODEOWNERS file from the owners and teams of services",
+	Long: `Generate a CODEOWNERS file for GitHub or GitLab from workbench.yaml.
+
+Every service and component with a team or owner gets a rule for its path.
+Teams are written as @<codeowners.org>/<team>, owners as @<owner>; values
+that already are handles or email addresses are kept as written. The
+manifest and the generated docker-compose.yml and terraform/ are owned by
+codeowners.default, or by every owner in the project when it is not set:
+
+  codeowners:
+    org: acme
+    default: ["@acme/platform"]
+
+Generated rules sit between "# om:codeowners:begin" and "# om:codeowners:end".
+Rules written around the block are kept, and 'om add service' and
+'om add component' refresh the block once it exists.
+
+Examples:
+  # Generate CODEOWNERS at the project root
+  om generate codeowners
+
+  # Or where GitHub also looks for it
+  om generate codeowners --path .github/CODEOWNERS`,
+	Args: cobra.NoArgs,
+	RunE: runGenerateCodeOwners,
+}
+
+// clientRunner runs the client generators. Tests replace it to generate
+// clients without npx or openapi-python-client.
+var clientRunner deps.Runner = deps.ExecRunner
+
+// initGenerateCommand registers the generate command and its subcommands
+func initGenerateCommand() {
+	generateCmd.AddCommand(generateLoadTestCmd)
+	generateCmd.AddCommand(generateDocsCmd)
+	generateCmd.AddCommand(generateClientsCmd)
+	generateCmd.AddCommand(generateCodeOwnersCmd)
+	if rootCmd != nil {
+		rootCmd.AddCommand(generateCmd)
+	}
+
+	generateLoadTestCmd.Flags().StringSlice("service", nil, "Only generate load tests for these services")
+	generateClientsCmd.Flags().StringSlice("service", nil, "Only generate the clients these services consume")
+	generateCodeOwnersCmd.Flags().String("path", "", "Where to write the file: CODEOWNERS, .github/CODEOWNERS, .gitlab/CODEOWNERS, or docs/CODEOWNERS (default: the generated file, or CODEOWNERS)")
+}
+
+// runGenerateLoadTest writes the k6 scripts and compose overlay for the
+// selected services
+func runGenerateLoadTest(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+
+	names, err := cmd.Flags().GetStringSlice("service")
+	if err != nil {
+		return fmt.Errorf("failed to get service flag: %w", err)
+	}
+	if len(names) == 0 {
+		names = loadtest.Services(manifest)
+		if len(names) == 0 {
+			return newValidationError("project '%s' has no HTTP services with a port to load test", manifest.Metadata.Name)
+		}
+	}
+
+	files, err := loadtest.Generate(manifest, names)
+	if err != nil {
+		return newValidationError("%w", err)
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		target := filepath.Join(projectRoot, filepath.FromSlash(path))
+		if err := workspaceFS.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		if err := workspaceFS.WriteFile(target, files[path], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	fmt.Printf("✅ Generated load tests for: %s\n", strings.Join(names, ", "))
+	fmt.Println()
+	fmt.Println("🚀 Next steps:")
+	fmt.Println("  1. Generate the stack: om compose --target docker")
+	fmt.Printf("  2. Run the tests: docker compose -f docker-compose.yml -f %s --profile %s up\n", loadtest.ComposeFile, loadtest.Profile)
+	fmt.Println("  3. Watch the results: http://localhost:3030")
+	return nil
+}
+
+// runGenerateDocs refreshes the managed blocks of the README and
+// ARCHITECTURE.md
+func runGenerateDocs(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+
+	files, err := projectdocs.Generate(workspaceFS, projectRoot, manifest)
+	if err != nil {
+		return newValidationError("%w", err)
+	}
+	for _, path := range []string{projectdocs.ReadmeFile, projectdocs.ArchitectureFile} {
+		if err := workspaceFS.WriteFile(filepath.Join(projectRoot, path), files[path], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	fmt.Printf("✅ Updated %s and %s\n", projectdocs.ReadmeFile, projectdocs.ArchitectureFile)
+	refreshServiceReadmes(projectRoot, manifest)
+	fmt.Println("💡 Re-run 'om generate docs' after changing workbench.yaml to keep them current")
+	return nil
+}
+
+// runGenerateCodeOwners writes the managed block of the CODEOWNERS file
+func runGenerateCodeOwners(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+	location, err := cmd.Flags().GetString("path")
+	if err != nil {
+		return fmt.Errorf("failed to get path flag: %w", err)
+	}
+	if location == "" {
+		if found, ok := codeowners.Find(workspaceFS, projectRoot); ok {
+			location = found
+		} else {
+			location = codeowners.File
+		}
+	}
+	location = filepath.ToSlash(filepath.Clean(location))
+	supported := false
+	for _, candidate := range codeowners.Locations {
+		supported = supported || candidate == location
+	}
+	if !supported {
+		return newValidationError("GitHub and GitLab do not read CODEOWNERS from %s; use one of %s", location, strings.Join(codeowners.Locations, ", "))
+	}
+
+	if err := writeCodeOwners(projectRoot, location, manifest); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Updated %s\n", location)
+	return nil
+}
+
+// writeCodeOwners brings the managed block of the CODEOWNERS file at
+// location up to date
+func writeCodeOwners(projectRoot, location string, manifest *manifestPkg.WorkbenchManifest) error {
+	data, err := codeowners.Generate(workspaceFS, projectRoot, location, manifest)
+	if err != nil {
+		return newValidationError("%w", err)
+	}
+	target := filepath.Join(projectRoot, filepath.FromSlash(location))
+	if err := workspaceFS.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(location), err)
+	}
+	if err := workspaceFS.WriteFile(target, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", location, err)
+	}
+	return nil
+}
+
+// refreshCodeOwners regenerates the CODEOWNERS file after the manifest
+// changed, when om generated one. Failures are reported without failing
+// the change.
+func refreshCodeOwners(projectRoot string, manifest *manifestPkg.WorkbenchManifest) {
+	location, ok := codeowners.Find(workspaceFS, projectRoot)
+	if !ok {
+		return
+	}
+	if err := writeCodeOwners(projectRoot, location, manifest); err != nil {
+		fmt.Printf("⚠️  Could not update %s: %v\n", location, err)
+		return
+	}
+	fmt.Printf("👥 Updated %s\n", location)
+}
+
+// writeNewServiceReadme documents a newly scaffolded service in the managed
+// block of its README, with the template parameters it was scaffolded with.
+// Failures are reported without failing the scaffold.
+func writeNewServiceReadme(catalog *templating.TemplateCatalog, projectRoot string, manifest *manifestPkg.WorkbenchManifest, serviceName, templateName string, values map[string]interface{}) {
+	var parameters []projectdocs.Parameter
+	if templateManifest, err := catalog.Manifest(templateName); err == nil {
+		parameters = readmeParameters(templateManifest, values)
+	}
+	if err := writeServiceReadme(projectRoot, manifest, serviceName, parameters); err != nil {
+		fmt.Printf("⚠️  Could not write the README of %s: %v\n", serviceName, err)
+	}
+}
+
+// writeServiceReadme brings the managed block of a service's README up to
+// date. Nil parameters keep the ones the README already lists.
+func writeServiceReadme(projectRoot string, manifest *manifestPkg.WorkbenchManifest, serviceName string, parameters []projectdocs.Parameter) error {
+	file, data, err := projectdocs.GenerateServiceReadme(workspaceFS, projectRoot, manifest, serviceName, parameters)
+	if err != nil {
+		return err
+	}
+	if err := workspaceFS.WriteFile(filepath.Join(projectRoot, filepath.FromSlash(file)), data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	fmt.Printf("📝 Updated %s\n", file)
+	return nil
+}
+
+// refreshServiceReadmes regenerates the README block of the given services,
+// or of every service, for the services whose README has one
+func refreshServiceReadmes(projectRoot string, manifest *manifestPkg.WorkbenchManifest, serviceNames ...string) {
+	if len(serviceNames) == 0 {
+		serviceNames = slices.Sorted(maps.Keys(manifest.Services))
+	}
+	for _, name := range serviceNames {
+		service, exists := manifest.Services[name]
+		if !exists || service.Path == "" {
+			continue
+		}
+		data, err := workspaceFS.ReadFile(filepath.Join(projectRoot, filepath.FromSlash(service.Path), projectdocs.ServiceReadmeFile))
+		if err != nil || !projectdocs.HasBlock(data) {
+			continue
+		}
+		if err := writeServiceReadme(projectRoot, manifest, name, nil); err != nil {
+			fmt.Printf("⚠️  Could not update the README of %s: %v\n", name, err)
+		}
+	}
+}
+
+// readmeParameters returns the template parameters a service was scaffolded
+// with, in the order the template asks for them. Values of parameters named
+// like passwords, secrets, or tokens are left out of the README.
+func readmeParameters(templateManifest *templating.TemplateManifest, values map[string]interface{}) []projectdocs.Parameter {
+	values = templating.DefaultParameterValues(templateManifest, values)
+	parameters := []projectdocs.Parameter{}
+	for _, param := range templateManifest.Parameters {
+		value, exists := values[param.Name]
+		if !exists {
+			continue
+		}
+		text := fmt.Sprint(value)
+		if list, ok := value.([]string); ok {
+			text = strings.Join(list, ", ")
+		}
+		name := strings.ToLower(param.Name)
+		if strings.Contains(name, "password") || strings.Contains(name, "secret") || strings.Contains(name, "token") {
+			text = "(hidden)"
+		}
+		parameters = append(parameters, projectdocs.Parameter{Name: param.Name, Value: text})
+	}
+	return parameters
+}
+
+// runGenerateClients generates the clients of the selected consumers and
+// wires the providers' base URLs into their environment
+func runGenerateClients(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+
+	names, err := cmd.Flags().GetStringSlice("service")
+	if err != nil {
+		return fmt.Errorf("failed to get service flag: %w", err)
+	}
+	clients, skipped, err := clientgen.Plan(workspaceFS, projectRoot, manifest, names)
+	if err != nil {
+		return newNotFoundError("%s", err)
+	}
+
+	var found []warnings.Warning
+	for _, name := range skipped {
+		found = append(found, warnings.New("services."+name+".consumes",
+			"no clients generated: add a package.json, pyproject.toml, or requirements.txt so its language is known"))
+	}
+	if len(clients) == 0 {
+		if len(skipped) == 0 {
+			return newValidationError("no service consumes another service's API; list providers under consumes in workbench.yaml")
+		}
+		return reportWarnings(cmd, found)
+	}
+
+	generated := 0
+	wired := false
+	for _, client := range clients {
+		consumer := manifest.Services[client.Consumer]
+		fmt.Printf("🔧 Generating %s client for %s (%s)...\n", client.Provider, client.Consumer, client.Language)
+		if err := clientgen.Generate(clientRunner, projectRoot, filepath.Join(projectRoot, consumer.Path), client); err != nil {
+			found = append(found, warnings.New("services."+client.Consumer+".consumes",
+				"could not generate the %s client: %s", client.Provider, err))
+			continue
+		}
+		generated++
+		fmt.Printf("   ✅ %s\n", filepath.ToSlash(filepath.Join(consumer.Path, client.Output)))
+
+		if _, exists := consumer.Environment[client.EnvVar]; !exists {
+			if consumer.Environment == nil {
+				consumer.Environment = make(map[string]string)
+			}
+			consumer.Environment[client.EnvVar] = "${services." + client.Provider + ".url}"
+			manifest.Services[client.Consumer] = consumer
+			wired = true
+			fmt.Printf("   🔗 Set %s in %s's environment\n", client.EnvVar, client.Consumer)
+		}
+	}
+
+	if wired {
+		if err := saveWorkbenchManifest(manifest, projectRoot); err != nil {
+			return err
+		}
+	}
+	if generated == 0 {
+		_ = reportWarnings(cmd, found)
+		return &exitCodeError{code: ExitCodeExternalTool, err: fmt.Errorf("no clients could be generated")}
+	}
+
+	fmt.Printf("\n✅ Generated %d client(s)\n", generated)
+	fmt.Println("💡 Re-run 'om generate clients' when an API spec changes, or add it to a preCompose hook")
+	return reportWarnings(cmd, found)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/help"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var helpCmd = &cobra.Command{
+	Use:   "help [topic|command]",
+	Short: "Help about any command or topic",
+	Long: `Show help for a command, or read one of the long-form topic guides.
+
+Topic guides explain the concepts behind Open Workbench without leaving the terminal.
+Long output is shown through your pager ($PAGER, or less) when running in a terminal.
+
+Examples:
+  # Learn the workbench.yaml format
+  om help manifest
+
+  # Learn how templates and template.json work
+  om help templates
+
+  # Help for a specific command
+  om help add service`,
+	RunE:              runHelp,
+	ValidArgsFunction: completeHelpTopics,
+}
+
+// initHelpCommand replaces Cobra's default help command with the topic-aware one
+func initHelpCommand() {
+	helpCmd.Flags().Bool("no-pager", false, "Print help directly instead of using a pager")
+	rootCmd.SetHelpCommand(helpCmd)
+	rootCmd.Long += helpTopicsSection()
+}
+
+func runHelp(cmd *cobra.Command, args []string) error {
+	noPager, err := cmd.Flags().GetBool("no-pager")
+	if err != nil {
+		return fmt.Errorf("failed to get no-pager flag: %w", err)
+	}
+
+	// Topic guides take precedence over commands with the same name
+	if len(args) == 1 {
+		if topic, ok := help.Lookup(args[0]); ok {
+			return showHelpText(help.Render(topic.Body, useColor()), noPager)
+		}
+	}
+
+	// Fall back to command help
+	target, _, err := rootCmd.Find(args)
+	if err != nil || target == nil {
+		return fmt.Errorf("unknown help topic or command %q. Run 'om help' to see available topics", strings.Join(args, " "))
+	}
+	target.InitDefaultHelpFlag()
+	return target.Help()
+}
+
+// helpTopicsSection lists the embedded topic guides for the root command's long help
+func helpTopicsSection() string {
+	topics := help.Topics()
+	if len(topics) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\nHelp Topics:\n")
+	for _, topic := range topics {
+		b.WriteString(fmt.Sprintf("  %-12s %s\n", topic.Name, topic.Title))
+	}
+	b.WriteString("\nRun 'om help <topic>' to read a guide.")
+	return b.String()
+}
+
+// completeHelpTopics provides shell completion for topic names and commands
+func completeHelpTopics(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, topic := range help.Topics() {
+		names = append(names, topic.Name+"\t"+topic.Title)
+	}
+	for _, sub := range rootCmd.Commands() {
+		if sub.IsAvailableCommand() {
+			names = append(names, sub.Name()+"\t"+sub.Short)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// useColor reports whether help output should be colorized
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+}
+
+// showHelpText prints text, paging it when it does not fit on the terminal
+func showHelpText(text string, noPager bool) error {
+	fd := int(os.Stdout.Fd())
+	if noPager || !term.IsTerminal(fd) {
+		_, err := io.WriteString(os.Stdout, text)
+		return err
+	}
+
+	_, height, err := term.GetSize(fd)
+	if err != nil || strings.Count(text, "\n") < height {
+		_, err := io.WriteString(os.Stdout, text)
+		return err
+	}
+
+	pager := exec.Command(pagerCommand())
+	if pagerEnv := os.Getenv("PAGER"); pagerEnv != "" {
+		shell, shellArgs := templating.NewPlatformUtils().GetShellCommand(pagerEnv)
+		pager = exec.Command(shell, shellArgs...)
+	}
+	pager.Stdin = strings.NewReader(text)
+	pager.Stdout = os.Stdout
+	pager.Stderr = os.Stderr
+	pager.Env = append(os.Environ(), "LESS=FRX")
+
+	if err := pager.Run(); err != nil {
+		// The pager is a convenience; never lose the help text because of it
+		_, err := io.WriteString(os.Stdout, text)
+		return err
+	}
+	return nil
+}
+
+// pagerCommand returns the default pager for the current platform
+func pagerCommand() string {
+	if runtime.GOOS == "windows" {
+		return "more"
+	}
+	return "less"
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/hooks"
+	"github.com/spf13/cobra"
+)
+
+// userConfigPath returns the user's om configuration, whose hooks run for
+// every project. Tests replace it.
+var userConfigPath = func() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".om", "config.yaml"), nil
+}
+
+// hookDispatcher runs hooks. Tests replace it to observe events without
+// running commands or calling webhooks.
+var hookDispatcher = hooks.NewDispatcher()
+
+// hookClock stamps events. Tests replace it.
+var hookClock = time.Now
+
+// fireHook runs the user's and the project's hooks for an event. Hooks only
+// report: a missing or broken hook configuration, or a failing hook, is
+// printed as a warning and never fails the command that finished. The
+// commands and webhooks of the project's .om/hooks.yaml only run once the user
+// approved them, like the lifecycle hooks of workbench.yaml.
+func fireHook(cmd *cobra.Command, event, projectName, projectRoot string, data map[string]string) {
+	// A dry run changed nothing for hooks to react to
+	if dryRun != nil {
+		return
+	}
+	configPath, err := userConfigPath()
+	if err != nil {
+		configPath = ""
+	}
+	config, err := hooks.Load(workspaceFS, configPath, projectRoot)
+	if err != nil {
+		fmt.Printf("⚠️  Hooks were not run: %v\n", err)
+		return
+	}
+	if len(config.Hooks) == 0 {
+		return
+	}
+
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		root = projectRoot
+	}
+	if actions := config.ProjectActions(event); len(actions) > 0 {
+		approved, err := approveHookCommands(cmd, root, filepath.ToSlash(hooks.ProjectFileName), event, actions)
+		if err != nil {
+			fmt.Printf("⚠️  Could not ask to approve the project's hooks: %v\n", err)
+		}
+		if !approved {
+			fmt.Printf("⚠️  Skipped the %s hooks of %s: they were not approved\n", event, filepath.ToSlash(hooks.ProjectFileName))
+			config = config.WithoutProjectHooks()
+		}
+	}
+	failures := hookDispatcher.Dispatch(config, hooks.Event{
+		Name:        event,
+		Project:     projectName,
+		ProjectRoot: root,
+		Time:        hookClock().UTC(),
+		Data:        data,
+	})
+	for _, failure := range failures {
+		fmt.Printf("⚠️  %v\n", failure)
+	}
+}
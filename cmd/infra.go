@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/infrastate"
+	"github.com/spf13/cobra"
+)
+
+// infraRunner runs terraform output. Tests replace it.
+var infraRunner deps.Runner = deps.ExecRunner
+
+// infraClock stamps recorded states. Tests replace it.
+var infraClock = time.Now
+
+var infraCmd = &cobra.Command{
+	Use:   "infra",
+	Short: "Work with the deployed infrastructure of an environment",
+}
+
+var infraPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Record the Terraform outputs of a deployed environment",
+	Long: `Record the Terraform outputs of a deployed environment.
+
+Run after 'terraform apply' in terraform/. The outputs of the applied
+configuration (the load balancer's DNS name, database endpoints, bucket
+names) are read with 'terraform output -json' and written to
+.om/state/<env>.json, replacing what was recorded before. Outputs Terraform
+marks as sensitive are never written.
+
+Other commands use the recorded outputs instead of running Terraform:
+'om smoke --env' checks the recorded load balancer, and generating Terraform
+for the environment resolves ${infra.<output>} references in service
+environment variables.
+
+Examples:
+  # Record the outputs of dev
+  om infra pull --env dev`,
+	Args: cobra.NoArgs,
+	RunE: runInfraPull,
+}
+
+// initInfraCommand registers the infra command and its subcommands
+func initInfraCommand() {
+	infraCmd.AddCommand(infraPullCmd)
+	if rootCmd != nil {
+		rootCmd.AddCommand(infraCmd)
+	}
+
+	infraPullCmd.Flags().String("env", "", "Environment the applied Terraform deploys (required)")
+}
+
+// runInfraPull records the Terraform outputs of an environment
+func runInfraPull(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+	env, err := cmd.Flags().GetString("env")
+	if err != nil {
+		return fmt.Errorf("failed to get env flag: %w", err)
+	}
+	if env == "" {
+		return newValidationError("--env is required: outputs are recorded per environment")
+	}
+	if _, exists := manifest.Environments[env]; !exists {
+		return newNotFoundError("environment '%s' is not defined in workbench.yaml", env)
+	}
+
+	if err := confirmCurrentOutputs(projectRoot, manifest, terraformRemedy, "terraform/main.tf"); err != nil {
+		return err
+	}
+
+	outputs, sensitive, err := infrastate.Outputs(infraRunner, filepath.Join(projectRoot, "terraform"))
+	if err != nil {
+		return &exitCodeError{code: ExitCodeExternalTool, err: err}
+	}
+	state := &infrastate.State{Environment: env, UpdatedAt: infraClock().UTC(), Outputs: outputs, Sensitive: sensitive}
+	if err := infrastate.Save(workspaceFS, projectRoot, state); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Recorded %d output(s) of environment '%s' in %s\n", len(outputs), env, infrastate.Path(env))
+	for _, name := range slices.Sorted(maps.Keys(outputs)) {
+		fmt.Printf("  • %s = %s\n", name, outputs[name])
+	}
+	if len(sensitive) > 0 {
+		fmt.Printf("🔒 Left out sensitive output(s): %s\n", strings.Join(sensitive, ", "))
+	}
+	return nil
+}
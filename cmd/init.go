@@ -1,17 +1,15 @@
 package cmd
 
 import (
-	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/AlecAivazis/survey/v2"
-	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/hooks"
 	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 
 	"github.com/jashkahar/open-workbench-platform/internal/templating"
 )
@@ -27,56 +25,286 @@ This command will:
   3. Create the project structure
   4. Generate a workbench.yaml manifest file
 
-Example:
-  om init`,
-	RunE: runInit,
+Project details can also be given as flags, like om add service takes them.
+Anything not given is still asked for, unless --yes is set: then template
+parameters take their defaults and a missing --name, --service, or
+--template is an error, so om init runs without a terminal, e.g. in CI.
+
+Examples:
+  # Interactive mode
+  om init
+
+  # Non-interactive mode
+  om init --name shop --service api --template express-api --yes
+  om init --name shop --service web --template react-typescript --params IncludeTesting=false --yes`,
+	RunE:        runInit,
+	Annotations: map[string]string{dryRunAnnotation: "true"},
 }
 
 func init() {
 	// Command registration will be done in Execute function
 }
 
-// runInit executes the init command logic
+// runInit executes the init command logic - smart mode detection
 func runInit(cmd *cobra.Command, args []string) error {
-	// Step 1: Safety check - verify the current directory is empty or contains only hidden files
-	if err := checkDirectorySafety(); err != nil {
+	// Check if we're in direct mode (any project detail given as a flag)
+	for _, name := range []string{"name", "service", "template", "params", "yes"} {
+		if cmd.Flags().Changed(name) {
+			return runInitDirect(cmd, args)
+		}
+	}
+	return runInitInteractive(cmd, args)
+}
+
+// runInitInteractive executes the init command, prompting for every detail
+func runInitInteractive(cmd *cobra.Command, args []string) error {
+	// Every step of the command shares one view of the templates
+	catalog := templating.NewTemplateCatalog(templatesFS)
+
+	// Step 1: Probe the current directory, offering a writable alternative
+	// before anything is scaffolded when it is read-only
+	baseDir, err := chooseInitDir(false)
+	if err != nil {
+		return err
+	}
+
+	// Step 2: Safety check - verify the new project does not end up nested in an
+	// existing one, and that the current directory is empty or contains only hidden files
+	if err := checkNotInsideProject(baseDir); err != nil {
 		return err
 	}
+	if baseDir == "" {
+		if err := checkDirectorySafety(); err != nil {
+			return err
+		}
+	}
 
-	// Step 2: Prompt for project name
-	projectName, err := promptForProjectName()
+	// Step 3: Prompt for project name
+	projectName, err := promptForProjectName(func(name string) bool {
+		return filesystem.Exists(workspaceFS, filepath.Join(baseDir, name))
+	})
 	if err != nil {
 		return err
 	}
+	projectDir := filepath.Join(baseDir, projectName)
+	if baseDir != "" && filesystem.Exists(workspaceFS, projectDir) {
+		return newValidationError("directory '%s' already exists", projectDir)
+	}
 
-	// Step 3: Prompt for first service details
-	serviceName, templateName, err := promptForFirstService()
+	// Step 4: Prompt for first service details
+	serviceName, templateName, err := promptForFirstService(catalog)
 	if err != nil {
 		return err
 	}
 
-	// Step 4: Create directories
-	if err := createProjectDirectories(projectName, serviceName); err != nil {
+	// Step 5: Check disk space and path lengths, then create directories
+	servicePath := filepath.Join(projectDir, serviceName)
+	if err := runScaffoldPreflight(cmd, catalog, templateName, servicePath); err != nil {
+		return err
+	}
+	if err := createProjectDirectories(baseDir, projectName, serviceName); err != nil {
 		return err
 	}
 
-	// Step 5: Run the scaffolder
-	servicePath := filepath.Join(projectName, serviceName)
-	if err := scaffoldService(templateName, servicePath, false, projectName, "Open Workbench"); err != nil {
+	// Step 6: Run the scaffolder
+	params, err := scaffoldService(catalog, templateName, servicePath, false, projectName, "Open Workbench")
+	if err != nil {
+		// Clean up the project directory created in step 5
+		workspaceFS.RemoveAll(projectDir)
 		return err
 	}
 
-	// Step 6: Create and write workbench.yaml
-	if err := createWorkbenchManifest(projectName, serviceName, templateName); err != nil {
+	// Step 7: Create and write workbench.yaml, then document the service
+	if err := createWorkbenchManifest(projectDir, projectName, serviceName, templateName); err != nil {
+		// Clean up the project directory created in step 5
+		workspaceFS.RemoveAll(projectDir)
 		return err
 	}
+	if manifest, err := loadManifest(filepath.Join(projectDir, "workbench.yaml")); err == nil {
+		writeNewServiceReadme(catalog, projectDir, manifest, serviceName, templateName, params)
+	}
 
-	// Step 7: Print success message
-	printSuccessMessage(projectName, serviceName)
+	// Step 8: Print success message
+	printSuccessMessage(projectDir, projectName, serviceName)
+	printTemplateDocsLink(catalog, templateName)
+	fireHook(cmd, hooks.EventScaffoldComplete, projectName, projectDir, map[string]string{
+		"kind": "project", "name": projectName, "service": serviceName, "template": templateName,
+	})
 
 	return nil
 }
 
+// runInitDirect executes the init command with the project details given as
+// flags, prompting only for the ones missing unless --yes is set
+func runInitDirect(cmd *cobra.Command, args []string) error {
+	assumeYes, _ := cmd.Flags().GetBool("yes")
+
+	// Every step of the command shares one view of the templates, fetched
+	// first when --template names a git repository
+	catalog, err := templateCatalog(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Step 1: Probe the current directory, moving to a writable alternative
+	// before anything is scaffolded when it is read-only
+	baseDir, err := chooseInitDir(assumeYes)
+	if err != nil {
+		return err
+	}
+
+	// Step 2: Safety check - the same as in interactive mode
+	if err := checkNotInsideProject(baseDir); err != nil {
+		return err
+	}
+	if baseDir == "" {
+		if err := checkDirectorySafety(); err != nil {
+			return err
+		}
+	}
+
+	// Step 3: Get the project details from the flags, prompting for the rest
+	projectName, serviceName, templateName, err := getDirectInitParameters(cmd, catalog, baseDir, assumeYes)
+	if err != nil {
+		return err
+	}
+	projectDir := filepath.Join(baseDir, projectName)
+	if baseDir != "" && filesystem.Exists(workspaceFS, projectDir) {
+		return newValidationError("directory '%s' already exists", projectDir)
+	}
+
+	// Step 4: Fill in team preset answers and defaults, then validate the
+	// template and parameters
+	paramStrings, err := cmd.Flags().GetStringToString("params")
+	if err != nil {
+		return fmt.Errorf("failed to get parameters: %w", err)
+	}
+	params, err := applyTeamPreset(catalog, templateName, parseParamsFlag(paramStrings))
+	if err != nil {
+		return err
+	}
+	templateManifest, err := catalog.Manifest(templateName)
+	if err != nil {
+		return fmt.Errorf("failed to load template manifest: %w", err)
+	}
+	servicePath := filepath.Join(projectDir, serviceName)
+	params = seedProjectParameters(templateManifest, params, servicePath, projectName)
+	params = templating.DefaultParameterValues(templateManifest, params)
+	if err := validateTemplateAndParameters(catalog, templateName, params); err != nil {
+		return err
+	}
+
+	// Step 5: Check disk space and path lengths, then create directories
+	if err := runScaffoldPreflight(cmd, catalog, templateName, servicePath); err != nil {
+		return err
+	}
+	if err := createProjectDirectories(baseDir, projectName, serviceName); err != nil {
+		return err
+	}
+
+	// Step 6: Run the scaffolder with direct parameters
+	if err := scaffoldServiceDirect(catalog, templateName, servicePath, params); err != nil {
+		// Clean up the project directory created in step 5
+		workspaceFS.RemoveAll(projectDir)
+		return fmt.Errorf("failed to scaffold service: %w", err)
+	}
+
+	// Step 7: Create and write workbench.yaml, then document the service
+	if err := createWorkbenchManifest(projectDir, projectName, serviceName, templateName); err != nil {
+		// Clean up the project directory created in step 5
+		workspaceFS.RemoveAll(projectDir)
+		return err
+	}
+	if manifest, err := loadManifest(filepath.Join(projectDir, "workbench.yaml")); err == nil {
+		writeNewServiceReadme(catalog, projectDir, manifest, serviceName, templateName, params)
+	}
+
+	// Step 8: Print success message
+	printSuccessMessage(projectDir, projectName, serviceName)
+	printTemplateDocsLink(catalog, templateName)
+	fireHook(cmd, hooks.EventScaffoldComplete, projectName, projectDir, map[string]string{
+		"kind": "project", "name": projectName, "service": serviceName, "template": templateName,
+	})
+
+	return nil
+}
+
+// getDirectInitParameters returns the project name, first service name, and
+// template of om init from its flags. Names given as flags must already be
+// valid; missing details are prompted for, or reported when assumeYes is set.
+func getDirectInitParameters(cmd *cobra.Command, catalog *templating.TemplateCatalog, baseDir string, assumeYes bool) (string, string, string, error) {
+	projectName, _ := cmd.Flags().GetString("name")
+	serviceName, _ := cmd.Flags().GetString("service")
+	templateName, _ := cmd.Flags().GetString("template")
+
+	var err error
+	if projectName == "" {
+		if assumeYes {
+			return "", "", "", newValidationError("--name is required with --yes")
+		}
+		projectName, err = promptForProjectName(func(name string) bool {
+			return filesystem.Exists(workspaceFS, filepath.Join(baseDir, name))
+		})
+		if err != nil {
+			return "", "", "", err
+		}
+	} else if projectName, err = validateNameFlag(projectName); err != nil {
+		return "", "", "", err
+	}
+
+	if templateName == "" {
+		if assumeYes {
+			return "", "", "", newValidationError("--template is required with --yes; see om list-templates")
+		}
+		if templateName, err = promptForFirstTemplate(catalog); err != nil {
+			return "", "", "", err
+		}
+	} else {
+		if err := ValidateTemplateName(templateName); err != nil {
+			return "", "", "", fmt.Errorf("invalid template name: %w", err)
+		}
+		if err := checkTemplateCompatibility(catalog, templateName); err != nil {
+			return "", "", "", err
+		}
+	}
+
+	if serviceName == "" {
+		if assumeYes {
+			return "", "", "", newValidationError("--service is required with --yes")
+		}
+		if serviceName, err = promptForFirstServiceName(); err != nil {
+			return "", "", "", err
+		}
+	} else if serviceName, err = validateNameFlag(serviceName); err != nil {
+		return "", "", "", err
+	}
+
+	return projectName, serviceName, templateName, nil
+}
+
+// hasParameter reports whether the template asks for the named parameter
+func hasParameter(manifest *templating.TemplateManifest, name string) bool {
+	for _, param := range manifest.Parameters {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateNameFlag validates a name given as a flag. Unlike names typed at
+// a prompt, it is used as given or rejected.
+func validateNameFlag(name string) (string, error) {
+	sanitized, err := ValidateAndSanitizeName(name, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := CheckForSuspiciousPatterns(sanitized); err != nil {
+		return "", err
+	}
+	return sanitized, nil
+}
+
 // checkDirectorySafety verifies that the current directory is safe to initialize
 func checkDirectorySafety() error {
 	// Validate current directory safety
@@ -84,7 +312,7 @@ func checkDirectorySafety() error {
 		return fmt.Errorf("directory safety check failed: %w", err)
 	}
 
-	entries, err := os.ReadDir(".")
+	entries, err := workspaceFS.ReadDir(".")
 	if err != nil {
 		return fmt.Errorf("failed to read current directory: %w", err)
 	}
@@ -92,118 +320,124 @@ func checkDirectorySafety() error {
 	// Check if directory is empty or contains only hidden files
 	for _, entry := range entries {
 		if !strings.HasPrefix(entry.Name(), ".") {
-			return fmt.Errorf("directory is not empty. Please run 'om init' in an empty directory or a directory containing only hidden files (like .git)")
+			return newValidationError("directory is not empty. Please run 'om init' in an empty directory or a directory containing only hidden files (like .git)")
 		}
 	}
 
 	return nil
 }
 
-// promptForProjectName prompts the user for a project name
-func promptForProjectName() (string, error) {
-	var projectName string
-	prompt := &survey.Input{
-		Message: "What is your project name?",
-		Help:    "This will be used as the directory name and in the workbench.yaml manifest",
-	}
-	err := survey.AskOne(prompt, &projectName, survey.WithValidator(survey.Required))
-	if err != nil {
-		if errors.Is(err, terminal.InterruptErr) {
-			fmt.Println("\nOperation cancelled.")
-			os.Exit(0)
+// checkNotInsideProject refuses to create a project in dir, or the current
+// directory when dir is empty, when it is inside an existing project. A
+// nested project would be picked up by neither om nor its own services.
+func checkNotInsideProject(dir string) error {
+	if dir == "" {
+		cwd, err := workingDir()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
 		}
-		return "", fmt.Errorf("failed to get project name: %w", err)
+		dir = cwd
 	}
-
-	// Validate and sanitize project name
-	sanitizedName, err := ValidateAndSanitizeName(projectName, nil)
+	projectRoot, err := findWorkbenchYaml(dir)
 	if err != nil {
-		return "", err
+		return nil
 	}
+	return newValidationError("%s is inside the project in %s. Add services to that project with 'om add service', or run om init outside it", displayPath(dir), displayPath(projectRoot))
+}
 
-	// Check for suspicious patterns
-	if err := CheckForSuspiciousPatterns(sanitizedName); err != nil {
-		return "", err
+// promptForProjectName prompts the user for a project name, turning what
+// they type into a valid name; taken reports names already in use
+func promptForProjectName(taken func(string) bool) (string, error) {
+	projectName, err := prompter.Input(prompt.Question{
+		Name:     "projectName",
+		Message:  "What is your project name?",
+		Help:     "This will be used as the directory name and in the workbench.yaml manifest",
+		Required: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get project name: %w", err)
 	}
 
-	return sanitizedName, nil
+	return nameFromAnswer("project", projectName, taken)
 }
 
 // promptForFirstService prompts the user for the first service details
-func promptForFirstService() (string, string, error) {
+func promptForFirstService(catalog *templating.TemplateCatalog) (string, string, error) {
+	templateName, err := promptForFirstTemplate(catalog)
+	if err != nil {
+		return "", "", err
+	}
+	serviceName, err := promptForFirstServiceName()
+	if err != nil {
+		return "", "", err
+	}
+	return serviceName, templateName, nil
+}
+
+// promptForFirstTemplate prompts the user for the template of the first service
+func promptForFirstTemplate(catalog *templating.TemplateCatalog) (string, error) {
 	// Discover available templates
-	templates, err := templating.DiscoverTemplates(templatesFS)
+	templates, err := catalog.Templates()
 	if err != nil {
-		return "", "", fmt.Errorf("could not discover templates: %w", err)
+		return "", fmt.Errorf("could not discover templates: %w", err)
 	}
 
 	if len(templates) == 0 {
-		return "", "", fmt.Errorf("no templates found")
+		return "", fmt.Errorf("no templates found")
 	}
 
 	// Create template options for selection
 	var templateOptions []string
 	templateMap := make(map[string]string)
 	for _, template := range templates {
-		templateOptions = append(templateOptions, fmt.Sprintf("%s - %s", template.Name, template.Description))
-		templateMap[fmt.Sprintf("%s - %s", template.Name, template.Description)] = template.Name
+		templateOptions = append(templateOptions, templateOptionLabel(template))
+		templateMap[templateOptionLabel(template)] = template.Name
 	}
 
 	// Prompt for template selection
-	var selectedTemplateOption string
-	templateQuestion := &survey.Select{
+	selectedTemplateOption, err := prompter.Select(prompt.Question{
+		Name:    "template",
 		Message: "Choose a template for your first service:",
 		Options: templateOptions,
 		Help:    "This will be used to scaffold your first service",
-	}
-	err = survey.AskOne(templateQuestion, &selectedTemplateOption)
+	})
 	if err != nil {
-		if errors.Is(err, terminal.InterruptErr) {
-			fmt.Println("\nOperation cancelled.")
-			os.Exit(0)
-		}
-		return "", "", fmt.Errorf("could not select template: %w", err)
+		return "", fmt.Errorf("could not select template: %w", err)
 	}
 
 	selectedTemplate := templateMap[selectedTemplateOption]
 
 	// Validate template name for security
 	if err := ValidateTemplateName(selectedTemplate); err != nil {
-		return "", "", fmt.Errorf("invalid template name: %w", err)
+		return "", fmt.Errorf("invalid template name: %w", err)
 	}
-
-	// Prompt for service name
-	var serviceName string
-	servicePrompt := &survey.Input{
-		Message: "What is your service name?",
-		Default: "frontend",
-		Help:    "This will be used as the service directory name",
-	}
-	err = survey.AskOne(servicePrompt, &serviceName, survey.WithValidator(survey.Required))
-	if err != nil {
-		if errors.Is(err, terminal.InterruptErr) {
-			fmt.Println("\nOperation cancelled.")
-			os.Exit(0)
-		}
-		return "", "", fmt.Errorf("could not get service name: %w", err)
+	if err := checkTemplateCompatibility(catalog, selectedTemplate); err != nil {
+		return "", err
 	}
 
-	// Validate and sanitize service name
-	sanitizedServiceName, err := ValidateAndSanitizeName(serviceName, nil)
-	if err != nil {
-		return "", "", err
-	}
+	return selectedTemplate, nil
+}
 
-	// Check for suspicious patterns
-	if err := CheckForSuspiciousPatterns(sanitizedServiceName); err != nil {
-		return "", "", err
+// promptForFirstServiceName prompts the user for the name of the first service
+func promptForFirstServiceName() (string, error) {
+	serviceName, err := prompter.Input(prompt.Question{
+		Name:     "serviceName",
+		Message:  "What is your service name?",
+		Default:  "frontend",
+		Help:     "This will be used as the service directory name",
+		Required: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not get service name: %w", err)
 	}
 
-	return sanitizedServiceName, selectedTemplate, nil
+	// Turn the answer into a valid service name
+	return nameFromAnswer("service", serviceName, nil)
 }
 
-// createProjectDirectories creates the project and service directories
-func createProjectDirectories(projectName, serviceName string) error {
+// createProjectDirectories creates the project and service directories in
+// baseDir, or the current directory when baseDir is empty
+func createProjectDirectories(baseDir, projectName, serviceName string) error {
 	// Validate and sanitize paths
 	sanitizedProjectName, err := ValidateAndSanitizePath(projectName, nil)
 	if err != nil {
@@ -216,23 +450,73 @@ func createProjectDirectories(projectName, serviceName string) error {
 	}
 
 	// Create project directory
-	if err := os.MkdirAll(sanitizedProjectName, 0755); err != nil {
+	projectDir := filepath.Join(baseDir, sanitizedProjectName)
+	if err := workspaceFS.MkdirAll(projectDir, 0755); err != nil {
 		return fmt.Errorf("failed to create project directory: %w", err)
 	}
 
 	// Create service directory
-	servicePath := filepath.Join(sanitizedProjectName, sanitizedServiceName)
-	if err := os.MkdirAll(servicePath, 0755); err != nil {
+	servicePath := filepath.Join(projectDir, sanitizedServiceName)
+	if err := workspaceFS.MkdirAll(servicePath, 0755); err != nil {
 		return fmt.Errorf("failed to create service directory: %w", err)
 	}
 
 	return nil
 }
 
+// projectParameterDefaults returns the ProjectName and Owner values templates
+// receive when the user does not supply them
+func projectParameterDefaults(servicePath, existingProjectName, existingOwner string) map[string]interface{} {
+	defaults := map[string]interface{}{
+		"ProjectName": filepath.Base(servicePath),
+		"Owner":       "Open Workbench",
+	}
+	if existingProjectName != "" {
+		defaults["ProjectName"] = existingProjectName
+	}
+	if existingOwner != "" {
+		defaults["Owner"] = existingOwner
+	}
+	return defaults
+}
+
+// seedProjectParameters adds the ProjectName and Owner defaults to params,
+// before they are validated, when the template asks for them and they were
+// not given
+func seedProjectParameters(templateManifest *templating.TemplateManifest, params map[string]interface{}, servicePath, projectName string) map[string]interface{} {
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+	for name, value := range projectParameterDefaults(servicePath, projectName, "") {
+		// Only templates that ask for the project name or owner receive them
+		if _, exists := params[name]; !exists && hasParameter(templateManifest, name) {
+			params[name] = value
+		}
+	}
+	return params
+}
+
+// seedParameterDefaults adds the defaults that are missing from values
+func seedParameterDefaults(values, defaults map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	for name, value := range defaults {
+		if _, exists := values[name]; !exists {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// advancedPrompts asks advanced template parameters without the "Configure
+// advanced options?" gate. It is set by the --advanced flag.
+var advancedPrompts bool
+
 // collectTemplateParameters prompts the user for template-specific parameters
-func collectTemplateParameters(templateName string, isAddService bool, existingProjectName string, existingOwner string) (map[string]interface{}, error) {
+func collectTemplateParameters(catalog *templating.TemplateCatalog, templateName, servicePath string, isAddService bool, existingProjectName string, existingOwner string) (map[string]interface{}, error) {
 	// Load the template manifest
-	templateInfo, err := templating.GetTemplateInfo(templatesFS, templateName)
+	templateInfo, err := catalog.Info(templateName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load template: %w", err)
 	}
@@ -241,14 +525,28 @@ func collectTemplateParameters(templateName string, isAddService bool, existingP
 	processor := templating.NewParameterProcessor(templateInfo.Manifest)
 	parameterValues := make(map[string]interface{})
 
+	// Team preset answers become the defaults of their questions
+	presetAnswers, err := teamPresetAnswers(templateName, templateInfo.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	// Seed the processor with the project-level defaults before prompting, so
+	// conditions that depend on them see the values the templates will get
+	defaults := projectParameterDefaults(servicePath, existingProjectName, existingOwner)
+	if owner, ok := presetAnswers["Owner"]; ok && existingOwner == "" {
+		defaults["Owner"] = owner
+	}
+	for name, value := range defaults {
+		processor.SetValue(name, value)
+	}
+
 	// Pre-populate project-level parameters if provided
 	if existingProjectName != "" {
 		parameterValues["ProjectName"] = existingProjectName
-		processor.SetValue("ProjectName", existingProjectName)
 	}
 	if existingOwner != "" {
 		parameterValues["Owner"] = existingOwner
-		processor.SetValue("Owner", existingOwner)
 	}
 
 	// Get visible parameters organized by groups
@@ -260,6 +558,34 @@ func collectTemplateParameters(templateName string, isAddService bool, existingP
 		name  string
 		value interface{}
 	}
+	ask := func(groupName string, param templating.Parameter) error {
+		value, err := promptForParameter(param)
+		if err != nil {
+			return err
+		}
+
+		// Store the value in both the processor and our result map
+		processor.SetValue(param.Name, value)
+		parameterValues[param.Name] = value
+
+		// Store for summary
+		collectedParams = append(collectedParams, struct {
+			group string
+			name  string
+			value interface{}
+		}{
+			group: groupName,
+			name:  param.Prompt,
+			value: value,
+		})
+		return nil
+	}
+
+	// Advanced parameters are asked after the basic ones, behind a gate
+	var advanced []struct {
+		group string
+		param templating.Parameter
+	}
 
 	// Collect parameters from each group
 	for groupName, params := range parameterGroups {
@@ -282,25 +608,47 @@ func collectTemplateParameters(templateName string, isAddService bool, existingP
 					}
 				}
 
-				value, err := promptForParameter(param)
-				if err != nil {
+				if answer, ok := presetAnswers[param.Name]; ok {
+					param.Default = answer
+				}
+				if param.IsAdvanced() {
+					advanced = append(advanced, struct {
+						group string
+						param templating.Parameter
+					}{groupName, param})
+					continue
+				}
+				if err := ask(groupName, param); err != nil {
 					return nil, err
 				}
+			}
+		}
+	}
 
-				// Store the value in both the processor and our result map
-				processor.SetValue(param.Name, value)
-				parameterValues[param.Name] = value
-
-				// Store for summary
-				collectedParams = append(collectedParams, struct {
-					group string
-					name  string
-					value interface{}
-				}{
-					group: groupName,
-					name:  param.Prompt,
-					value: value,
-				})
+	if len(advanced) > 0 {
+		configureAdvanced := advancedPrompts
+		if !configureAdvanced {
+			configureAdvanced, err = prompter.Confirm(prompt.Question{
+				Name:    "ConfigureAdvanced",
+				Message: fmt.Sprintf("Configure advanced options? (%d more)", len(advanced)),
+				Help:    "Answer no to use the template defaults. Pass --advanced to always see these questions.",
+				Default: false,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get advanced options choice: %w", err)
+			}
+		}
+
+		for _, entry := range advanced {
+			if configureAdvanced {
+				if err := ask(entry.group, entry.param); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if value := entry.param.DefaultValue(); value != nil {
+				processor.SetValue(entry.param.Name, value)
+				parameterValues[entry.param.Name] = value
 			}
 		}
 	}
@@ -339,7 +687,8 @@ func collectTemplateParameters(templateName string, isAddService bool, existingP
 		fmt.Println()
 	}
 
-	return parameterValues, nil
+	// Parameters the user was not asked for fall back to the project defaults
+	return seedParameterDefaults(parameterValues, defaults), nil
 }
 
 // promptForParameter prompts the user for a single parameter value
@@ -360,7 +709,6 @@ func promptForParameter(param templating.Parameter) (interface{}, error) {
 
 // promptForStringParameter prompts for a string parameter
 func promptForStringParameter(param templating.Parameter) (string, error) {
-	var value string
 	var defaultValue string
 	if param.Default != nil {
 		if str, ok := param.Default.(string); ok {
@@ -368,28 +716,14 @@ func promptForStringParameter(param templating.Parameter) (string, error) {
 		}
 	}
 
-	prompt := &survey.Input{
-		Message: param.Prompt,
-		Help:    param.HelpText,
-		Default: defaultValue,
-	}
-
-	var validators []survey.Validator
-	if param.Required {
-		validators = append(validators, survey.Required)
-	}
-
-	var err error
-	if len(validators) > 0 {
-		err = survey.AskOne(prompt, &value, survey.WithValidator(validators[0]))
-	} else {
-		err = survey.AskOne(prompt, &value)
-	}
+	value, err := prompter.Input(prompt.Question{
+		Name:     param.Name,
+		Message:  param.Prompt,
+		Help:     param.HelpText,
+		Default:  defaultValue,
+		Required: param.Required,
+	})
 	if err != nil {
-		if errors.Is(err, terminal.InterruptErr) {
-			fmt.Println("\nOperation cancelled.")
-			os.Exit(0)
-		}
 		return "", fmt.Errorf("failed to get %s: %w", param.Name, err)
 	}
 
@@ -398,7 +732,6 @@ func promptForStringParameter(param templating.Parameter) (string, error) {
 
 // promptForBooleanParameter prompts for a boolean parameter
 func promptForBooleanParameter(param templating.Parameter) (bool, error) {
-	var value bool
 	var defaultValue bool
 	if param.Default != nil {
 		if b, ok := param.Default.(bool); ok {
@@ -406,18 +739,13 @@ func promptForBooleanParameter(param templating.Parameter) (bool, error) {
 		}
 	}
 
-	prompt := &survey.Confirm{
+	value, err := prompter.Confirm(prompt.Question{
+		Name:    param.Name,
 		Message: param.Prompt,
 		Help:    param.HelpText,
 		Default: defaultValue,
-	}
-
-	err := survey.AskOne(prompt, &value)
+	})
 	if err != nil {
-		if errors.Is(err, terminal.InterruptErr) {
-			fmt.Println("\nOperation cancelled.")
-			os.Exit(0)
-		}
 		return false, fmt.Errorf("failed to get %s: %w", param.Name, err)
 	}
 
@@ -426,7 +754,6 @@ func promptForBooleanParameter(param templating.Parameter) (bool, error) {
 
 // promptForSelectParameter prompts for a select parameter
 func promptForSelectParameter(param templating.Parameter) (string, error) {
-	var value string
 	var defaultValue string
 	if param.Default != nil {
 		if str, ok := param.Default.(string); ok {
@@ -434,19 +761,14 @@ func promptForSelectParameter(param templating.Parameter) (string, error) {
 		}
 	}
 
-	prompt := &survey.Select{
+	value, err := prompter.Select(prompt.Question{
+		Name:    param.Name,
 		Message: param.Prompt,
 		Options: param.Options,
 		Help:    param.HelpText,
 		Default: defaultValue,
-	}
-
-	err := survey.AskOne(prompt, &value)
+	})
 	if err != nil {
-		if errors.Is(err, terminal.InterruptErr) {
-			fmt.Println("\nOperation cancelled.")
-			os.Exit(0)
-		}
 		return "", fmt.Errorf("failed to get %s: %w", param.Name, err)
 	}
 
@@ -455,7 +777,6 @@ func promptForSelectParameter(param templating.Parameter) (string, error) {
 
 // promptForMultiSelectParameter prompts for a multiselect parameter
 func promptForMultiSelectParameter(param templating.Parameter) ([]string, error) {
-	var value []string
 	var defaultValue []string
 	if param.Default != nil {
 		if strs, ok := param.Default.([]string); ok {
@@ -463,67 +784,57 @@ func promptForMultiSelectParameter(param templating.Parameter) ([]string, error)
 		}
 	}
 
-	prompt := &survey.MultiSelect{
+	value, err := prompter.MultiSelect(prompt.Question{
+		Name:    param.Name,
 		Message: param.Prompt,
 		Options: param.Options,
 		Help:    param.HelpText,
 		Default: defaultValue,
-	}
-
-	err := survey.AskOne(prompt, &value)
+	})
 	if err != nil {
-		if errors.Is(err, terminal.InterruptErr) {
-			fmt.Println("\nOperation cancelled.")
-			os.Exit(0)
-		}
 		return nil, fmt.Errorf("failed to get %s: %w", param.Name, err)
 	}
 
 	return value, nil
 }
 
-// scaffoldService runs the scaffolding process for the service
-func scaffoldService(templateName, servicePath string, isAddService bool, existingProjectName string, existingOwner string) error {
+// scaffoldService runs the scaffolding process for the service and returns
+// the parameter values the user chose
+func scaffoldService(catalog *templating.TemplateCatalog, templateName, servicePath string, isAddService bool, existingProjectName string, existingOwner string) (map[string]interface{}, error) {
 	// Load the template manifest
-	templateInfo, err := templating.GetTemplateInfo(templatesFS, templateName)
+	templateInfo, err := catalog.Info(templateName)
 	if err != nil {
-		return fmt.Errorf("failed to load template: %w", err)
+		return nil, fmt.Errorf("failed to load template: %w", err)
 	}
 
 	// Collect template parameters from the user
-	parameterValues, err := collectTemplateParameters(templateName, isAddService, existingProjectName, existingOwner)
+	parameterValues, err := collectTemplateParameters(catalog, templateName, servicePath, isAddService, existingProjectName, existingOwner)
 	if err != nil {
-		return fmt.Errorf("failed to collect template parameters: %w", err)
-	}
-
-	// Add some default values for backward compatibility
-	if _, exists := parameterValues["ProjectName"]; !exists {
-		parameterValues["ProjectName"] = filepath.Base(servicePath)
-	}
-	if _, exists := parameterValues["Owner"]; !exists {
-		parameterValues["Owner"] = "Open Workbench"
+		return nil, fmt.Errorf("failed to collect template parameters: %w", err)
 	}
 
 	// Create a template processor
 	processor := templating.NewTemplateProcessor(templateInfo.Manifest, parameterValues, false)
+	processor.SetFileSystem(workspaceFS)
+	processor.SetCommandEnvironment(packageCacheEnvironment())
 
 	// Execute the scaffolding process
-	err = processor.ScaffoldProject(templatesFS, templateName, servicePath)
+	err = processor.ScaffoldProject(catalog.FS(), templateName, servicePath)
 	if err != nil {
-		return fmt.Errorf("failed to scaffold service: %w", err)
+		return nil, fmt.Errorf("failed to scaffold service: %w", err)
 	}
 
 	// Execute post-scaffolding actions
 	err = processor.ExecutePostScaffoldActions(servicePath)
 	if err != nil {
-		return fmt.Errorf("failed to execute post-scaffold actions: %w", err)
+		return nil, fmt.Errorf("failed to execute post-scaffold actions: %w", err)
 	}
 
-	return nil
+	return parameterValues, nil
 }
 
-// createWorkbenchManifest creates and writes the workbench.yaml file
-func createWorkbenchManifest(projectName, serviceName, templateName string) error {
+// createWorkbenchManifest creates and writes the workbench.yaml file in projectDir
+func createWorkbenchManifest(projectDir, projectName, serviceName, templateName string) error {
 	manifest := manifestPkg.WorkbenchManifest{
 		APIVersion: "openworkbench.io/v1alpha1",
 		Kind:       "Project",
@@ -535,28 +846,19 @@ func createWorkbenchManifest(projectName, serviceName, templateName string) erro
 				Template: templateName,
 				Path:     filepath.Join(".", serviceName),
 				Port:     defaultServicePort(templateName),
+				Protocol: defaultServiceProtocol(templateName),
+				Kind:     defaultServiceKind(templateName),
+				Dev:      defaultDevCommand(templateName),
 			},
 		},
 	}
 
-	// Marshal to YAML
-	data, err := yaml.Marshal(&manifest)
-	if err != nil {
-		return fmt.Errorf("failed to marshal manifest: %w", err)
-	}
-
 	// Write to file
-	manifestPath := filepath.Join(projectName, "workbench.yaml")
-	err = os.WriteFile(manifestPath, data, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write workbench.yaml: %w", err)
-	}
-
-	return nil
+	return saveWorkbenchManifest(&manifest, projectDir)
 }
 
 // printSuccessMessage prints a success message with next steps
-func printSuccessMessage(projectName, serviceName string) {
+func printSuccessMessage(projectDir, projectName, serviceName string) {
 	fmt.Println("------------------------------------")
 	fmt.Printf("✅ Success! Your new project '%s' is ready.\n", projectName)
 	fmt.Println()
@@ -566,7 +868,7 @@ func printSuccessMessage(projectName, serviceName string) {
 	fmt.Printf("  └── %s/\n", serviceName)
 	fmt.Println()
 	fmt.Println("🚀 Next steps:")
-	fmt.Printf("  cd %s\n", projectName)
+	fmt.Printf("  cd %s\n", projectDir)
 	fmt.Println("  om add service  # Add more services to your project")
 }
 
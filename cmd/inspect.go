@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/generator/docker"
+	"github.com/jashkahar/open-workbench-platform/internal/labels"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/ports"
+	"github.com/jashkahar/open-workbench-platform/internal/refs"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Show the resolved runtime view of part of the project",
+	Long:  `Show how om resolves part of the project when it generates configuration.`,
+}
+
+var inspectServiceCmd = &cobra.Command{
+	Use:   "service <name>",
+	Short: "Show what a service sees at runtime",
+	Long: `Show what a service sees at runtime: its environment variables after
+interpolation, the resources it can reach and where their credentials come
+from, its published ports, networks, and dependencies, and the generated
+files that refer to it.
+
+The view is built by the same code 'om compose --target docker' generates
+docker-compose.yml with, so it matches what the generator would write. When
+the docker-compose.yml on disk disagrees with it, the fields that differ are
+listed; run 'om compose' to bring the file up to date.
+
+Examples:
+  # What the api service sees locally
+  om inspect service api
+
+  # With the ${env.*} values of the staging environment
+  om inspect service api --env staging`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runInspectService,
+	ValidArgsFunction: completeServiceNames,
+}
+
+// initInspectCommand registers the inspect command and its subcommands
+func initInspectCommand() {
+	inspectCmd.AddCommand(inspectServiceCmd)
+	if rootCmd != nil {
+		rootCmd.AddCommand(inspectCmd)
+	}
+
+	inspectServiceCmd.Flags().String("env", "", "Environment to resolve ${env.*} references for")
+}
+
+// resourceReferencePattern matches the ${services.<service>.resources.<resource>.*}
+// references through which a service reaches a resource
+var resourceReferencePattern = regexp.MustCompile(`\$\{services\.([^.}]+)\.resources\.([^.}]+)\.`)
+
+// runInspectService prints the resolved runtime view of a service
+func runInspectService(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+	if _, exists := manifest.Services[name]; !exists {
+		return newNotFoundError("service '%s' not found in workbench.yaml", name)
+	}
+
+	manifest, err = selectEnvironment(cmd, manifest)
+	if err != nil {
+		return err
+	}
+	env, _ := cmd.Flags().GetString("env")
+	service, exists := manifest.Services[name]
+	if !exists {
+		return newNotFoundError("service '%s' is not deployed to environment '%s'", name, env)
+	}
+
+	gen := docker.NewGeneratorWithFS(workspaceFS, projectRoot)
+	gen.SetLabels(labels.Info{Project: manifest.Metadata.Name, Version: Version, Environment: env})
+	config, err := gen.Config(manifest)
+	if err != nil {
+		return err
+	}
+	resolved := config.Services[name]
+	lock, err := ports.Load(workspaceFS, projectRoot)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔍 Service %s (services.%s)\n", name, name)
+
+	fmt.Println("\nEnvironment:")
+	printInspectList(resolved.Environment)
+	if len(resolved.EnvFile) > 0 {
+		fmt.Printf("  (also loads %s)\n", strings.Join(resolved.EnvFile, ", "))
+	}
+
+	fmt.Println("\nResources:")
+	printInspectList(inspectResources(manifest, name, service))
+
+	fmt.Println("\nPorts:")
+	var published []string
+	for _, mapping := range resolved.Ports {
+		line := mapping
+		for _, allocation := range lock.Ports {
+			if allocation.Service == name && allocation.Moved() && strings.HasPrefix(mapping, fmt.Sprintf("%d:", allocation.Host)) {
+				line += fmt.Sprintf(" (moved from %d, which was in use)", allocation.Requested)
+			}
+		}
+		published = append(published, line)
+	}
+	printInspectList(published)
+
+	fmt.Println("\nNetworks:")
+	printInspectList(resolved.Networks)
+
+	fmt.Println("\nDepends on:")
+	var dependencies []string
+	for _, dependency := range resolved.DependsOn {
+		if condition := resolved.Conditions[dependency]; condition != "" {
+			dependency += " (" + condition + ")"
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	printInspectList(dependencies)
+
+	fmt.Println("\nGenerated files:")
+	artifacts, err := inspectArtifacts(projectRoot, manifest, name)
+	if err != nil {
+		return err
+	}
+	printInspectList(artifacts)
+
+	if drift := composeDrift(projectRoot, name, resolved); len(drift) > 0 {
+		fmt.Printf("\n⚠️  docker-compose.yml disagrees on the %s of %s; run 'om compose' to regenerate it\n", strings.Join(drift, ", "), name)
+	}
+	return nil
+}
+
+// printInspectList prints the lines of a section, or a dash when it is empty
+func printInspectList(lines []string) {
+	if len(lines) == 0 {
+		fmt.Println("  -")
+		return
+	}
+	for _, line := range lines {
+		fmt.Printf("  %s\n", line)
+	}
+}
+
+// inspectResources describes the resources a service can reach: its own
+// and those of other services its environment refers to, with the
+// container that runs each and where its credentials come from
+func inspectResources(manifest *manifestPkg.WorkbenchManifest, name string, service manifestPkg.Service) []string {
+	owners := make(map[string]string)
+	for resourceName := range service.Resources {
+		owners[name+"."+resourceName] = name
+	}
+	for _, value := range service.Environment {
+		for _, match := range resourceReferencePattern.FindAllStringSubmatch(value, -1) {
+			if _, exists := manifest.Services[match[1]].Resources[match[2]]; exists {
+				owners[match[1]+"."+match[2]] = match[1]
+			}
+		}
+	}
+
+	var lines []string
+	for _, key := range slices.Sorted(maps.Keys(owners)) {
+		owner := owners[key]
+		resourceName := strings.TrimPrefix(key, owner+".")
+		resource := manifest.Services[owner].Resources[resourceName]
+
+		kind := resource.Type
+		if resource.Version != "" {
+			kind += " " + resource.Version
+		}
+		line := fmt.Sprintf("%s: %s, container %s-%s", key, kind, owner, resourceName)
+		if owner != name {
+			line += ", referenced from the environment"
+		}
+		lines = append(lines, line+", credentials from "+credentialsSource(owner, resourceName, resource))
+	}
+	return lines
+}
+
+// credentialsSource names where the credentials of a resource come from:
+// the .env variables generated for it and the credential settings of its
+// config in workbench.yaml
+func credentialsSource(serviceName, resourceName string, resource manifestPkg.Resource) string {
+	var sources []string
+	if credentials := compose.ResourceCredentials(serviceName, resourceName, resource); len(credentials) > 0 {
+		sources = append(sources, fmt.Sprintf(".env (%s)", strings.Join(slices.Sorted(maps.Keys(credentials)), ", ")))
+	}
+	var configured []string
+	for _, key := range slices.Sorted(maps.Keys(resource.Config)) {
+		lower := strings.ToLower(key)
+		if strings.Contains(lower, "password") || strings.Contains(lower, "user") || strings.Contains(lower, "secret") {
+			configured = append(configured, key)
+		}
+	}
+	if len(configured) > 0 {
+		sources = append(sources, fmt.Sprintf("workbench.yaml config (%s)", strings.Join(configured, ", ")))
+	}
+	if len(sources) == 0 {
+		return "none needed"
+	}
+	return strings.Join(sources, " and ")
+}
+
+// inspectArtifacts lists the generated files that mention a service, with
+// the number of lines that do
+func inspectArtifacts(projectRoot string, manifest *manifestPkg.WorkbenchManifest, name string) ([]string, error) {
+	target, err := refs.ParseTarget(manifest, "services."+name)
+	if err != nil {
+		return nil, err
+	}
+	generated := append(generatedFiles(projectRoot), refs.EnvFiles(manifest)...)
+	found, err := refs.Find(workspaceFS, projectRoot, nil, generated, target)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, ref := range found {
+		counts[ref.File]++
+	}
+	var lines []string
+	for _, file := range slices.Sorted(maps.Keys(counts)) {
+		lines = append(lines, fmt.Sprintf("%s (%d line(s))", file, counts[file]))
+	}
+	return lines, nil
+}
+
+// composeDrift compares a service's resolved configuration with the one in
+// the project's docker-compose.yml and returns the fields that differ. It
+// returns nil when there is no docker-compose.yml to compare with.
+func composeDrift(projectRoot, name string, resolved compose.DockerComposeService) []string {
+	data, err := workspaceFS.ReadFile(filepath.Join(projectRoot, "docker-compose.yml"))
+	if err != nil {
+		return nil
+	}
+	var written compose.DockerComposeConfig
+	if err := yaml.Unmarshal(data, &written); err != nil {
+		return []string{"contents (it could not be parsed)"}
+	}
+	current, exists := written.Services[name]
+	if !exists {
+		return []string{"presence"}
+	}
+
+	var drift []string
+	for _, field := range []struct {
+		name           string
+		want, existing []string
+	}{
+		{"environment", resolved.Environment, current.Environment},
+		{"ports", resolved.Ports, current.Ports},
+		{"networks", resolved.Networks, current.Networks},
+		{"dependencies", resolved.DependsOn, current.DependsOn},
+	} {
+		want, existing := slices.Clone(field.want), slices.Clone(field.existing)
+		sort.Strings(want)
+		sort.Strings(existing)
+		if !slices.Equal(want, existing) {
+			drift = append(drift, field.name)
+		}
+	}
+	return drift
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/jashkahar/open-workbench-platform/internal/hooks"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+// lifecycleRunner runs the project's lifecycle hooks. Tests replace it.
+var lifecycleRunner hooks.Runner = hooks.SandboxRunner
+
+// trustFilePath returns the file that remembers which hooks of projects the
+// user approved, next to the user's om configuration
+func trustFilePath() (string, error) {
+	configPath, err := userConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "trusted-hooks.yaml"), nil
+}
+
+// runLifecycleHook runs the commands of a lifecycle hook declared in
+// workbench.yaml, in order, from the project root. Commands a user has not
+// approved are shown first and only run after confirmation, or with
+// --trust-hooks; a declined hook is skipped. The first failing command stops
+// the hook and is returned, so a failing pre hook stops the command.
+func runLifecycleHook(cmd *cobra.Command, projectRoot string, manifest *manifestPkg.WorkbenchManifest, name string, data map[string]string) error {
+	commands := manifest.Hooks[name]
+	if len(commands) == 0 {
+		return nil
+	}
+	if dryRun != nil {
+		fmt.Printf("🔍 Would run the %s hook\n", name)
+		return nil
+	}
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		root = projectRoot
+	}
+
+	approved, err := approveHookCommands(cmd, root, "workbench.yaml", name, commands)
+	if err != nil {
+		return err
+	}
+	if !approved {
+		fmt.Printf("⚠️  Skipped the %s hook: it was not approved\n", name)
+		return nil
+	}
+
+	event := hooks.Event{Name: name, Project: manifest.Metadata.Name, ProjectRoot: root, Time: hookClock().UTC(), Data: data}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode the %s hook input: %w", name, err)
+	}
+	env := hooks.Environment(event, body)
+	for _, command := range commands {
+		fmt.Printf("🪝 Running %s hook: %s\n", name, command)
+		if err := lifecycleRunner(root, command, env, body); err != nil {
+			return &exitCodeError{code: ExitCodeExternalTool, err: fmt.Errorf("%s hook '%s' failed: %w", name, command, err)}
+		}
+	}
+	return nil
+}
+
+// approveHookCommands reports whether the commands of a project's hook may
+// run, asking the user when they were not approved before and remembering a
+// yes. source is the file declaring the hook: workbench.yaml for lifecycle
+// hooks, .om/hooks.yaml for the commands run on an event.
+func approveHookCommands(cmd *cobra.Command, projectRoot, source, name string, commands []string) (bool, error) {
+	if trusted, _ := cmd.Flags().GetBool("trust-hooks"); trusted {
+		return true, nil
+	}
+	path, err := trustFilePath()
+	if err != nil {
+		return false, fmt.Errorf("failed to find the trusted hooks file: %w", err)
+	}
+	trust, err := hooks.LoadTrust(workspaceFS, path)
+	if err != nil {
+		return false, err
+	}
+	if trust.Trusted(projectRoot, name, commands) {
+		return true, nil
+	}
+
+	fmt.Printf("\n🪝 %s declares a %s hook that runs:\n", source, name)
+	for _, command := range commands {
+		fmt.Printf("   %s\n", command)
+	}
+	approve, err := prompter.Confirm(prompt.Question{
+		Name:    "trustHook",
+		Message: fmt.Sprintf("Allow the %s hook to run these commands?", name),
+		Help:    "Hooks run in the project root with a reduced environment. Your answer is remembered until the commands change.",
+		Default: false,
+	})
+	if err != nil || !approve {
+		return false, err
+	}
+	trust.Approve(projectRoot, name, commands)
+	if err := trust.Save(workspaceFS, path); err != nil {
+		fmt.Printf("⚠️  Could not remember the approval: %v\n", err)
+	}
+	return true, nil
+}
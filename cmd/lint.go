@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/dockerlint"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+	"github.com/spf13/cobra"
+)
+
+// lintRunner runs hadolint. Tests replace it to lint without hadolint.
+var lintRunner deps.Runner = deps.ExecRunner
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the project's services for quality issues",
+}
+
+var lintDockerfilesCmd = &cobra.Command{
+	Use:   "dockerfiles",
+	Short: "Lint every service's Dockerfile with hadolint",
+	Long: `Lint the Dockerfile of every service in workbench.yaml with hadolint.
+
+Findings are listed per service. Findings recorded in the project's baseline,
+.om/dockerlint-baseline.yaml, are accepted and not reported again, so the
+command can gate CI on new issues while old ones are fixed over time. Any
+finding outside the baseline fails the command.
+
+hadolint must be installed and on the PATH. When it is missing the command
+prints a warning and succeeds, unless --strict is set.
+
+Examples:
+  # Lint every Dockerfile
+  om lint dockerfiles
+
+  # Lint only the backend
+  om lint dockerfiles --service backend
+
+  # Accept the current findings as the baseline
+  om lint dockerfiles --update-baseline`,
+	Args: cobra.NoArgs,
+	RunE: runLintDockerfiles,
+}
+
+// initLintCommand registers the lint command and its subcommands
+func initLintCommand() {
+	lintCmd.AddCommand(lintDockerfilesCmd)
+	if rootCmd != nil {
+		rootCmd.AddCommand(lintCmd)
+	}
+
+	lintDockerfilesCmd.Flags().StringSlice("service", nil, "Only lint these services")
+	lintDockerfilesCmd.Flags().Bool("update-baseline", false, "Record the current findings as accepted in the baseline")
+}
+
+// runLintDockerfiles lints the selected services' Dockerfiles
+func runLintDockerfiles(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+
+	names, err := cmd.Flags().GetStringSlice("service")
+	if err != nil {
+		return fmt.Errorf("failed to get service flag: %w", err)
+	}
+	if len(names) == 0 {
+		for name := range manifest.Services {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	update, _ := cmd.Flags().GetBool("update-baseline")
+
+	var found []warnings.Warning
+	var all []dockerlint.Finding
+	linted := 0
+	for _, name := range names {
+		service, exists := manifest.Services[name]
+		if !exists {
+			return newNotFoundError("service '%s' not found in workbench.yaml", name)
+		}
+		if !service.IsContainer() || service.UsesImage() || service.Path == "" {
+			continue
+		}
+		file := filepath.ToSlash(filepath.Join(service.Path, "Dockerfile"))
+		if !filesystem.Exists(workspaceFS, filepath.Join(projectRoot, file)) {
+			continue
+		}
+
+		fmt.Printf("🔍 Linting %s...\n", file)
+		findings, err := dockerlint.Lint(lintRunner, name, filepath.Join(projectRoot, service.Path), file)
+		if errors.Is(err, dockerlint.ErrNotInstalled) {
+			if update {
+				return newValidationError("cannot update the baseline: %s", err)
+			}
+			found = append(found, warnings.New("", "Dockerfiles were not linted: %s", err))
+			break
+		}
+		if err != nil {
+			found = append(found, warnings.New("services."+name, "could not lint %s: %s", file, err))
+			continue
+		}
+		linted++
+		all = append(all, findings...)
+	}
+
+	if update {
+		if err := dockerlint.NewBaseline(all).Save(workspaceFS, projectRoot); err != nil {
+			return err
+		}
+		fmt.Printf("\n✅ Recorded %d finding(s) in %s\n", len(all), filepath.ToSlash(dockerlint.BaselineFileName))
+		return reportWarnings(cmd, found)
+	}
+
+	baseline, err := dockerlint.LoadBaseline(workspaceFS, projectRoot)
+	if err != nil {
+		return err
+	}
+	fresh := baseline.Filter(all)
+
+	fmt.Println()
+	if len(fresh) == 0 {
+		if linted > 0 {
+			fmt.Printf("✅ No new Dockerfile issues in %d service(s)\n", linted)
+		}
+		return reportWarnings(cmd, found)
+	}
+	printLintFindings(fresh, len(all)-len(fresh))
+	if err := reportWarnings(cmd, found); err != nil {
+		return err
+	}
+	return newValidationError("%d new Dockerfile issue(s); fix them or run 'om lint dockerfiles --update-baseline' to accept them", len(fresh))
+}
+
+// printLintFindings prints findings grouped by service
+func printLintFindings(findings []dockerlint.Finding, accepted int) {
+	fmt.Printf("🐳 %d Dockerfile issue(s)", len(findings))
+	if accepted > 0 {
+		fmt.Printf(" (%d more accepted by the baseline)", accepted)
+	}
+	fmt.Println()
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "  SERVICE\tLOCATION\tLEVEL\tRULE\tMESSAGE")
+	for _, finding := range findings {
+		fmt.Fprintf(table, "  %s\t%s:%d\t%s\t%s\t%s\n",
+			finding.Service, finding.File, finding.Line, finding.Level, finding.Code, finding.Message)
+	}
+	table.Flush()
+}
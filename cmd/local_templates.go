@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+)
+
+// userTemplatesDir returns the directory of the user's own templates. Tests
+// replace it.
+var userTemplatesDir = func() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".openworkbench", "templates"), nil
+}
+
+// localTemplateDirs returns the directories local templates are read from,
+// in order of precedence: those listed in OM_TEMPLATES_PATH, then the
+// user's templates directory
+func localTemplateDirs() []string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(os.Getenv("OM_TEMPLATES_PATH")) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	if dir, err := userTemplatesDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// withLocalTemplates adds the local templates to the built-in ones, taking
+// precedence over built-in templates of the same name
+func withLocalTemplates(builtin fs.FS) fs.FS {
+	return templating.WithLocalTemplates(builtin, localTemplateDirs()...)
+}
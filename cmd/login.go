@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+	"github.com/jashkahar/open-workbench-platform/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+// registryRunner runs the credential helpers and the AWS CLI. Tests replace
+// it to log in without either installed.
+var registryRunner registry.Runner = registry.ExecRunner
+
+// dockerConfigPath returns the docker CLI config credentials are stored in
+var dockerConfigPath = registry.DefaultConfigPath
+
+var loginCmd = &cobra.Command{
+	Use:   "login <registry>",
+	Short: "Store credentials for a container registry",
+	Long: `Store credentials for a container registry in the docker CLI config.
+
+Services with an image in workbench.yaml are pulled from its registry when
+'docker compose' starts them. Private registries answer those pulls with
+401 Unauthorized until you log in.
+
+Credentials are handed to the docker credential helper configured in
+~/.docker/config.json (credHelpers or credsStore), so 'docker' and 'docker
+compose' use them. Without a helper they are written to the config file,
+as 'docker login' does.
+
+For Amazon ECR registries (<account>.dkr.ecr.<region>.amazonaws.com) the
+login token is fetched with 'aws ecr get-login-password'; tokens expire
+after 12 hours, so run the command again when pulls start failing.
+
+Examples:
+  # Log in to an internal registry with a token
+  echo "$REGISTRY_TOKEN" | om login registry.example.com --username ci --password-stdin
+
+  # Log in to Amazon ECR with the current AWS credentials
+  om login 123456789012.dkr.ecr.eu-west-1.amazonaws.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogin,
+}
+
+// initLoginCommand registers the login command with the root command
+func initLoginCommand() {
+	if rootCmd != nil {
+		rootCmd.AddCommand(loginCmd)
+	}
+
+	loginCmd.Flags().StringP("username", "u", "", "Registry username")
+	loginCmd.Flags().Bool("password-stdin", false, "Read the password or token from stdin")
+}
+
+// runLogin stores credentials for a registry
+func runLogin(cmd *cobra.Command, args []string) error {
+	host := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(args[0], "https://"), "http://"), "/")
+	username, _ := cmd.Flags().GetString("username")
+	passwordStdin, _ := cmd.Flags().GetBool("password-stdin")
+
+	var password string
+	region, isECR := registry.ECRRegion(host)
+	switch {
+	case passwordStdin:
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("failed to read password from stdin: %w", err)
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+		if password == "" {
+			return newValidationError("no password was given on stdin")
+		}
+		if isECR && username == "" {
+			username = registry.ECRUsername
+		}
+	case isECR:
+		fmt.Printf("🔑 Fetching an ECR login token for %s...\n", region)
+		token, err := registry.ECRPassword(registryRunner, region)
+		if err != nil {
+			return err
+		}
+		username, password = registry.ECRUsername, token
+	default:
+		return newValidationError("pass the password or token on stdin with --password-stdin, e.g. echo \"$TOKEN\" | om login %s -u <user> --password-stdin", host)
+	}
+
+	if username == "" {
+		answer, err := prompter.Input(prompt.Question{
+			Name:     "username",
+			Message:  fmt.Sprintf("Username for %s:", host),
+			Required: true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get username: %w", err)
+		}
+		username = answer
+	}
+
+	configPath, err := dockerConfigPath()
+	if err != nil {
+		return err
+	}
+	helper, err := registry.NewStore(workspaceFS, configPath, registryRunner).Login(host, username, password)
+	if err != nil {
+		return err
+	}
+
+	if helper != "" {
+		fmt.Printf("✅ Logged in to %s (credentials stored with docker-credential-%s)\n", host, helper)
+	} else {
+		fmt.Printf("✅ Logged in to %s (credentials stored in %s)\n", host, configPath)
+		fmt.Println("⚠️  The credentials are stored unencrypted. Configure a credential helper to keep them in your keychain:")
+		fmt.Println("   https://docs.docker.com/reference/cli/docker/login/#credential-stores")
+	}
+	return nil
+}
+
+// printRegistryLoginHints lists the registries that services pull images
+// from without stored credentials, with the command to log in to each
+func printRegistryLoginHints(manifest *manifestPkg.WorkbenchManifest) {
+	configPath, err := dockerConfigPath()
+	if err != nil {
+		return
+	}
+	store := registry.NewStore(workspaceFS, configPath, registryRunner)
+
+	pullers := make(map[string][]string)
+	for name, service := range manifest.Services {
+		if !service.IsContainer() || !service.UsesImage() {
+			continue
+		}
+		host := registry.Host(service.Image)
+		if host == registry.DockerHub || store.HasCredentials(host) {
+			continue
+		}
+		pullers[host] = append(pullers[host], "services."+name)
+	}
+	if len(pullers) == 0 {
+		return
+	}
+
+	hosts := make([]string, 0, len(pullers))
+	for host := range pullers {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	fmt.Println()
+	fmt.Println("🔐 No credentials stored for these registries; private images fail to pull with 401 Unauthorized:")
+	for _, host := range hosts {
+		sort.Strings(pullers[host])
+		fmt.Printf("   %s (%s) → om login %s\n", host, strings.Join(pullers[host], ", "), host)
+	}
+}
@@ -1,10 +1,18 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/ports"
 	"github.com/jashkahar/open-workbench-platform/internal/resources"
 	"github.com/spf13/cobra"
 )
@@ -14,8 +22,10 @@ var lsCmd = &cobra.Command{
 	Short: "List project structure and components",
 	Long: `Display a high-level, human-readable overview of your project's architecture.
 
-This command reads your workbench.yaml file and displays a formatted tree structure
-showing all services, components, and resources in your project.
+This command reads your workbench.yaml file and prints the project graph as a
+tree: services with their templates, ports, and environments, the resources
+each service owns, components, external dependencies, and environments.
+Ports show the host port 'om compose' published them on.
 
 Examples:
   # List project structure
@@ -24,12 +34,14 @@ Examples:
   # List with detailed information
   om ls --detailed
 
-The output includes:
-  • Project name and metadata
-  • Services with their templates and resources
-  • Components with their templates
-  • Resource types and configurations
-  • Environment configurations (if any)`,
+  # One row per service, resource, component, and external dependency
+  om ls --wide
+
+  # The project graph for scripts and dashboards
+  om ls --json
+
+  # List what the payments team owns
+  om ls --team payments`,
 	RunE: runLs,
 }
 
@@ -41,145 +53,465 @@ func initLsCommand() {
 
 	// Add detailed flag
 	lsCmd.Flags().Bool("detailed", false, "Show detailed information including resource configurations")
+
+	// Add output modes for scripting
+	lsCmd.Flags().Bool("json", false, "Print the project graph as JSON")
+	lsCmd.Flags().Bool("wide", false, "Print a table with a row per service, resource, component, and external dependency")
+
+	// Add ownership filters
+	lsCmd.Flags().String("team", "", "Only list services and components of this team")
+	lsCmd.Flags().String("owner", "", "Only list services and components with this owner")
+	lsCmd.Flags().String("tag", "", "Only list services and components with this tag")
 }
 
 func runLs(cmd *cobra.Command, args []string) error {
 	// Find project root and load manifest
-	_, manifest, err := findProjectRootAndLoadManifest()
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
 	if err != nil {
 		return fmt.Errorf("failed to load project: %w", err)
 	}
 
-	// Get detailed flag
+	// Get output flags
 	detailed, err := cmd.Flags().GetBool("detailed")
 	if err != nil {
 		return fmt.Errorf("failed to get detailed flag: %w", err)
 	}
+	asJSON, _ := cmd.Flags().GetBool("json")
+	wide, _ := cmd.Flags().GetBool("wide")
+	if asJSON && wide {
+		return newValidationError("--json and --wide cannot be used together")
+	}
 
-	// Print project header
-	printProjectHeader(manifest)
+	// Keep only what the ownership filters select
+	team, _ := cmd.Flags().GetString("team")
+	owner, _ := cmd.Flags().GetString("owner")
+	tag, _ := cmd.Flags().GetString("tag")
+	manifest = filterByOwnership(manifest, team, owner, tag)
 
-	// Print environments if any
-	if len(manifest.Environments) > 0 {
-		printEnvironments(manifest.Environments)
+	// Published host ports are recorded by om compose
+	lock, err := ports.Load(workspaceFS, projectRoot)
+	if err != nil {
+		return err
+	}
+	project := newLsProject(manifest, lock)
+
+	switch {
+	case asJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(project)
+	case wide:
+		return printLsTable(project)
 	}
 
-	// Print components
-	if len(manifest.Components) > 0 {
-		printComponents(manifest.Components, detailed)
+	fmt.Printf("📁 %s\n", project.Name)
+	printTree(lsTree(manifest, project, detailed), "")
+	fmt.Println()
+	printSummary(manifest)
+	return nil
+}
+
+// lsProject is the project graph om ls prints, and its --json output
+type lsProject struct {
+	Name         string          `json:"name"`
+	Services     []lsEntry       `json:"services"`
+	Components   []lsEntry       `json:"components"`
+	External     []lsEntry       `json:"external"`
+	Environments []lsEnvironment `json:"environments"`
+}
+
+// lsEntry is a service, component, resource, or external dependency of the
+// project graph
+type lsEntry struct {
+	Kind         string    `json:"kind"` // service, component, resource, or external
+	Name         string    `json:"name"`
+	Parent       string    `json:"parent,omitempty"`   // service that owns a resource
+	Template     string    `json:"template,omitempty"` // template of a service or component
+	Image        string    `json:"image,omitempty"`
+	Type         string    `json:"type,omitempty"` // type of a resource
+	Version      string    `json:"version,omitempty"`
+	URL          string    `json:"url,omitempty"`   // address of an external dependency
+	Ports        []string  `json:"ports,omitempty"` // host:container, with the host port om compose published on
+	Environments []string  `json:"environments,omitempty"`
+	Team         string    `json:"team,omitempty"`
+	Owner        string    `json:"owner,omitempty"`
+	Resources    []lsEntry `json:"resources,omitempty"`
+}
+
+// lsEnvironment is a deployment environment of the project graph
+type lsEnvironment struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	Region   string `json:"region,omitempty"`
+}
+
+// newLsProject builds the project graph of a manifest, in name order, with
+// the host ports recorded in lock
+func newLsProject(manifest *manifestPkg.WorkbenchManifest, lock *ports.Lock) lsProject {
+	project := lsProject{
+		Name:         manifest.Metadata.Name,
+		Services:     []lsEntry{},
+		Components:   []lsEntry{},
+		External:     []lsEntry{},
+		Environments: []lsEnvironment{},
+	}
+	environments := slices.Sorted(maps.Keys(manifest.Environments))
+	deployedTo := func(name string) []string {
+		var names []string
+		for _, env := range environments {
+			if manifest.InEnvironment(env, name) {
+				names = append(names, env)
+			}
+		}
+		return names
 	}
 
-	// Print services
-	if len(manifest.Services) > 0 {
-		printServices(manifest.Services, detailed)
+	for _, name := range slices.Sorted(maps.Keys(manifest.Services)) {
+		service := manifest.Services[name]
+		entry := lsEntry{
+			Kind: "service", Name: name, Template: service.Template, Environments: deployedTo(name),
+			Team: service.Team, Owner: service.Owner,
+		}
+		if service.UsesImage() {
+			entry.Image = service.Image
+		}
+		if service.Port != 0 {
+			if service.IsContainer() {
+				entry.Ports = publishedPorts(lock, name, []string{fmt.Sprintf("%d:%d", service.Port, service.Port)})
+			} else {
+				entry.Ports = []string{strconv.Itoa(service.Port)}
+			}
+		}
+		for _, resourceName := range slices.Sorted(maps.Keys(service.Resources)) {
+			resource := service.Resources[resourceName]
+			entry.Resources = append(entry.Resources, lsEntry{
+				Kind: "resource", Name: resourceName, Parent: name, Type: resource.Type, Version: resource.Version,
+				Ports: publishedPorts(lock, name+"-"+resourceName, nil),
+			})
+		}
+		project.Services = append(project.Services, entry)
 	}
 
-	// Print summary
-	printSummary(manifest)
+	for _, name := range slices.Sorted(maps.Keys(manifest.Components)) {
+		component := manifest.Components[name]
+		project.Components = append(project.Components, lsEntry{
+			Kind: "component", Name: name, Template: component.Template, Environments: deployedTo(name),
+			Ports: publishedPorts(lock, name, component.Ports), Team: component.Team, Owner: component.Owner,
+		})
+	}
 
-	return nil
-}
+	for _, name := range slices.Sorted(maps.Keys(manifest.External)) {
+		project.External = append(project.External, lsEntry{Kind: "external", Name: name, URL: manifest.External[name].URL})
+	}
 
-func printProjectHeader(manifest *manifestPkg.WorkbenchManifest) {
-	fmt.Println("📁 Project Structure")
-	fmt.Println("===================")
-	fmt.Printf("Project: %s\n", manifest.Metadata.Name)
-	fmt.Printf("API Version: %s\n", manifest.APIVersion)
-	fmt.Printf("Kind: %s\n", manifest.Kind)
-	fmt.Println()
+	for _, name := range environments {
+		environment := manifest.Environments[name]
+		project.Environments = append(project.Environments, lsEnvironment{Name: name, Provider: environment.Provider, Region: environment.Region})
+	}
+	return project
 }
 
-func printEnvironments(environments map[string]manifestPkg.Environment) {
-	fmt.Println("🌍 Environments")
-	fmt.Println("---------------")
-	for name, env := range environments {
-		fmt.Printf("  %s:\n", name)
-		fmt.Printf("    Provider: %s\n", env.Provider)
-		if env.Region != "" {
-			fmt.Printf("    Region: %s\n", env.Region)
+// publishedPorts returns the host:container mappings of a compose service,
+// with the host port om compose recorded for it where one was. Recorded
+// ports the mappings do not list, such as those of resources exposed to
+// the host, are added.
+func publishedPorts(lock *ports.Lock, service string, mappings []string) []string {
+	recorded := make(map[int]int)
+	for _, allocation := range lock.Ports {
+		if allocation.Service == service {
+			recorded[allocation.Container] = allocation.Host
 		}
-		if len(env.Config) > 0 {
-			fmt.Printf("    Config: %v\n", env.Config)
+	}
+
+	var published []string
+	for _, mapping := range mappings {
+		parts := strings.Split(mapping, ":")
+		container, err := strconv.Atoi(parts[len(parts)-1])
+		if host, exists := recorded[container]; err == nil && exists {
+			mapping = fmt.Sprintf("%d:%d", host, container)
+			delete(recorded, container)
 		}
+		published = append(published, mapping)
 	}
-	fmt.Println()
+	var unlisted []int
+	for container := range recorded {
+		unlisted = append(unlisted, container)
+	}
+	sort.Ints(unlisted)
+	for _, container := range unlisted {
+		published = append(published, fmt.Sprintf("%d:%d", recorded[container], container))
+	}
+	return published
 }
 
-func printComponents(components map[string]manifestPkg.Component, detailed bool) {
-	fmt.Println("📦 Components")
-	fmt.Println("--------------")
-	for name, component := range components {
-		fmt.Printf("  📦 %s (%s)\n", name, component.Template)
-		if detailed {
-			fmt.Printf("    Path: %s\n", component.Path)
-			if len(component.Ports) > 0 {
-				fmt.Printf("    Ports: %s\n", strings.Join(component.Ports, ", "))
-			}
+// lsNode is a line of the om ls tree and the lines nested under it
+type lsNode struct {
+	label    string
+	children []lsNode
+}
+
+// printTree prints nodes as the branches of a tree, each line starting with
+// prefix
+func printTree(nodes []lsNode, prefix string) {
+	for i, node := range nodes {
+		branch, indent := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, indent = "└── ", "    "
 		}
+		fmt.Printf("%s%s%s\n", prefix, branch, node.label)
+		printTree(node.children, prefix+indent)
 	}
-	fmt.Println()
 }
 
-func printServices(services map[string]manifestPkg.Service, detailed bool) {
-	fmt.Println("🚀 Services")
-	fmt.Println("------------")
-	for name, service := range services {
-		fmt.Printf("  💻 %s (%s)\n", name, service.Template)
-		if detailed {
-			fmt.Printf("    Path: %s\n", service.Path)
-			if service.Port != 0 {
-				fmt.Printf("    Port: %d\n", service.Port)
+// lsTree returns the tree of the project graph: the project, with its
+// services and their resources, components, external dependencies, and
+// environments. Detailed trees add the settings of each entry.
+func lsTree(manifest *manifestPkg.WorkbenchManifest, project lsProject, detailed bool) []lsNode {
+	var sections []lsNode
+
+	if len(project.Services) > 0 {
+		section := lsNode{label: "🚀 Services"}
+		for _, entry := range project.Services {
+			service := manifest.Services[entry.Name]
+			node := lsNode{label: "💻 " + entry.Name + lsLabel(entry) + ownershipSummary(service.Ownership)}
+			if detailed {
+				node.children = serviceDetails(manifest, entry.Name, service)
 			}
-			if len(service.Environment) > 0 {
-				fmt.Printf("    Environment Variables: %d\n", len(service.Environment))
+			for _, resource := range entry.Resources {
+				node.children = append(node.children, resourceNode(resource, service.Resources[resource.Name], detailed))
 			}
+			section.children = append(section.children, node)
 		}
+		sections = append(sections, section)
+	}
 
-		// Print resources for this service
-		if len(service.Resources) > 0 {
-			printServiceResources(name, service.Resources, detailed)
+	if len(project.Components) > 0 {
+		section := lsNode{label: "📦 Components"}
+		for _, entry := range project.Components {
+			component := manifest.Components[entry.Name]
+			node := lsNode{label: "📦 " + entry.Name + lsLabel(entry) + ownershipSummary(component.Ownership)}
+			if detailed {
+				node.children = ownershipDetails(component.Ownership)
+				node.children = append(node.children, lsNode{label: "Path: " + component.Path})
+				if source := manifest.Source("components", entry.Name); source != "" {
+					node.children = append(node.children, lsNode{label: "Defined in: " + source})
+				}
+			}
+			section.children = append(section.children, node)
 		}
+		sections = append(sections, section)
 	}
-	fmt.Println()
-}
 
-func printServiceResources(serviceName string, serviceResources map[string]manifestPkg.Resource, detailed bool) {
-	resourceRegistry := resources.NewRegistry()
-
-	for resourceName, resource := range serviceResources {
-		// Get resource blueprint for description
-		blueprint, err := resourceRegistry.Get(resource.Type)
-		description := resource.Type
-		if err == nil {
-			description = blueprint.Description
-		}
-
-		// Choose appropriate emoji based on resource category
-		emoji := "🔧"
-		if err == nil {
-			switch blueprint.Category {
-			case "database":
-				emoji = "🐘"
-			case "cache":
-				emoji = "⚡"
-			case "storage":
-				emoji = "📦"
-			case "message-queue":
-				emoji = "📨"
+	if len(project.External) > 0 {
+		section := lsNode{label: "🔗 External Dependencies"}
+		for _, entry := range project.External {
+			dependency := manifest.External[entry.Name]
+			node := lsNode{label: fmt.Sprintf("🔗 %s (%s)", entry.Name, entry.URL)}
+			if detailed {
+				if dependency.Description != "" {
+					node.children = append(node.children, lsNode{label: "Description: " + dependency.Description})
+				}
+				for _, env := range slices.Sorted(maps.Keys(dependency.Environments)) {
+					node.children = append(node.children, lsNode{label: fmt.Sprintf("%s: %s", env, dependency.Environments[env])})
+				}
 			}
+			section.children = append(section.children, node)
 		}
+		sections = append(sections, section)
+	}
 
-		fmt.Printf("    %s %s (%s)\n", emoji, resourceName, description)
-
-		if detailed {
-			if resource.Version != "" {
-				fmt.Printf("      Version: %s\n", resource.Version)
+	if len(project.Environments) > 0 {
+		section := lsNode{label: "🌍 Environments"}
+		for _, env := range project.Environments {
+			label := fmt.Sprintf("%s (%s", env.Name, env.Provider)
+			if env.Region != "" {
+				label += ", " + env.Region
 			}
-			if len(resource.Config) > 0 {
-				fmt.Printf("      Config: %v\n", resource.Config)
+			node := lsNode{label: label + ")"}
+			if config := manifest.Environments[env.Name].Config; detailed && len(config) > 0 {
+				for _, key := range slices.Sorted(maps.Keys(config)) {
+					node.children = append(node.children, lsNode{label: fmt.Sprintf("%s: %s", key, config[key])})
+				}
 			}
+			section.children = append(section.children, node)
 		}
+		sections = append(sections, section)
+	}
+
+	return sections
+}
+
+// lsLabel returns what follows the name of a service or component in the
+// tree: its template or image, ports, and environments
+func lsLabel(entry lsEntry) string {
+	label := ""
+	switch {
+	case entry.Template != "":
+		label += " (" + entry.Template + ")"
+	case entry.Image != "":
+		label += " (image " + entry.Image + ")"
+	}
+	if len(entry.Ports) > 0 {
+		label += " ports " + strings.Join(entry.Ports, ", ")
+	}
+	if len(entry.Environments) > 0 {
+		label += " in " + strings.Join(entry.Environments, ", ")
+	}
+	return label
+}
+
+// serviceDetails returns the settings of a service shown by om ls --detailed
+func serviceDetails(manifest *manifestPkg.WorkbenchManifest, name string, service manifestPkg.Service) []lsNode {
+	details := ownershipDetails(service.Ownership)
+	if service.UsesImage() {
+		details = append(details, lsNode{label: "Image: " + service.Image})
+	} else {
+		details = append(details, lsNode{label: "Path: " + service.Path})
+	}
+	if source := manifest.Source("services", name); source != "" {
+		details = append(details, lsNode{label: "Defined in: " + source})
+	}
+	if service.RunsOnHost() {
+		details = append(details, lsNode{label: "Runs on host: " + service.Dev})
+	}
+	if service.IsLibrary() {
+		details = append(details, lsNode{label: "Kind: shared library"})
+	}
+	if len(service.Environment) > 0 {
+		details = append(details, lsNode{label: fmt.Sprintf("Environment Variables: %d", len(service.Environment))})
+	}
+	return details
+}
+
+// ownershipDetails returns the description and tags of an entry
+func ownershipDetails(ownership manifestPkg.Ownership) []lsNode {
+	var details []lsNode
+	if ownership.Description != "" {
+		details = append(details, lsNode{label: "Description: " + ownership.Description})
+	}
+	if len(ownership.Tags) > 0 {
+		details = append(details, lsNode{label: "Tags: " + strings.Join(ownership.Tags, ", ")})
+	}
+	return details
+}
+
+// resourceNode returns the tree node of a service's resource, described by
+// its blueprint when it has one
+func resourceNode(entry lsEntry, resource manifestPkg.Resource, detailed bool) lsNode {
+	blueprint, err := resources.NewRegistry().Get(resources.BlueprintName(resource.Type))
+	description := resource.Type
+	if err == nil {
+		description = blueprint.Description
+	}
+
+	// Choose appropriate emoji based on resource category
+	emoji := "🔧"
+	if err == nil {
+		switch blueprint.Category {
+		case "database":
+			emoji = "🐘"
+		case "cache":
+			emoji = "⚡"
+		case "storage":
+			emoji = "📦"
+		case "message-queue":
+			emoji = "📨"
+		}
+	}
+
+	label := fmt.Sprintf("%s %s (%s)", emoji, entry.Name, description)
+	if entry.Version != "" {
+		label += " " + entry.Version
+	}
+	if len(entry.Ports) > 0 {
+		label += " ports " + strings.Join(entry.Ports, ", ")
+	}
+	node := lsNode{label: label}
+	if detailed {
+		for _, key := range slices.Sorted(maps.Keys(resource.Config)) {
+			node.children = append(node.children, lsNode{label: fmt.Sprintf("%s: %s", key, resource.Config[key])})
+		}
+	}
+	return node
+}
+
+// printLsTable prints the project graph as a table with a row per service,
+// resource, component, and external dependency
+func printLsTable(project lsProject) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAME\tSERVICE\tTEMPLATE\tTYPE\tPORTS\tENVIRONMENTS\tTEAM\tOWNER")
+	row := func(entry lsEntry) {
+		source := entry.Template
+		if source == "" {
+			source = entry.Image
+		}
+		kind := entry.Type
+		if entry.Version != "" {
+			kind += ":" + entry.Version
+		}
+		if entry.URL != "" {
+			kind = entry.URL
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", entry.Kind, entry.Name,
+			orDash(entry.Parent), orDash(source), orDash(kind), orDash(strings.Join(entry.Ports, ",")),
+			orDash(strings.Join(entry.Environments, ",")), orDash(entry.Team), orDash(entry.Owner))
+	}
+	for _, service := range project.Services {
+		row(service)
+		for _, resource := range service.Resources {
+			row(resource)
+		}
+	}
+	for _, entries := range [][]lsEntry{project.Components, project.External} {
+		for _, entry := range entries {
+			row(entry)
+		}
+	}
+	return w.Flush()
+}
+
+// orDash returns value, or a dash when it is empty
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// filterByOwnership returns a copy of the manifest without the services and
+// components that do not match the team, owner, and tag filters
+func filterByOwnership(manifest *manifestPkg.WorkbenchManifest, team, owner, tag string) *manifestPkg.WorkbenchManifest {
+	if team == "" && owner == "" && tag == "" {
+		return manifest
+	}
+	filtered := manifest.Clone()
+	for name, service := range filtered.Services {
+		if !service.Matches(team, owner, tag) {
+			delete(filtered.Services, name)
+		}
+	}
+	for name, component := range filtered.Components {
+		if !component.Matches(team, owner, tag) {
+			delete(filtered.Components, name)
+		}
+	}
+	return filtered
+}
+
+// ownershipSummary returns the team and owner of an entry as shown next to
+// its name, or nothing when neither is set
+func ownershipSummary(ownership manifestPkg.Ownership) string {
+	var parts []string
+	if ownership.Team != "" {
+		parts = append(parts, "team: "+ownership.Team)
+	}
+	if ownership.Owner != "" {
+		parts = append(parts, "owner: "+ownership.Owner)
+	}
+	if len(parts) == 0 {
+		return ""
 	}
+	return " [" + strings.Join(parts, ", ") + "]"
 }
 
 func printSummary(manifest *manifestPkg.WorkbenchManifest) {
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os/exec"
+	"runtime"
+	"slices"
+
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/ports"
+	"github.com/jashkahar/open-workbench-platform/internal/smoke"
+	"github.com/spf13/cobra"
+)
+
+// browserOpener opens a URL in the default browser. Tests replace it.
+var browserOpener = openInBrowser
+
+var openCmd = &cobra.Command{
+	Use:   "open <service>",
+	Short: "Open a service in the browser",
+	Long: `Open a service in the browser.
+
+Without --env the service is opened on localhost, at the host port
+'om compose' publishes it on (see 'om ports'), or at its own port for
+services that run on your machine. With --env it is opened at the load
+balancer of the deployed environment, recorded by 'om infra pull' or read
+from terraform output; the generated load balancer has no per-service
+routing, so every service opens at the same address.
+
+Examples:
+  # Open the web service of the local stack
+  om open web
+
+  # Open the production deployment
+  om open web --env prod
+
+  # Print the URL instead, e.g. over SSH
+  om open api --print`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runOpen,
+	ValidArgsFunction: completeServiceNames,
+}
+
+// initOpenCommand registers the open command
+func initOpenCommand() {
+	if rootCmd != nil {
+		rootCmd.AddCommand(openCmd)
+	}
+
+	openCmd.Flags().String("env", "", "Open the service deployed to this environment")
+	openCmd.Flags().Bool("print", false, "Print the URL instead of opening it")
+}
+
+// runOpen opens the URL of a service
+func runOpen(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+	env, err := cmd.Flags().GetString("env")
+	if err != nil {
+		return fmt.Errorf("failed to get env flag: %w", err)
+	}
+	printOnly, err := cmd.Flags().GetBool("print")
+	if err != nil {
+		return fmt.Errorf("failed to get print flag: %w", err)
+	}
+
+	name := args[0]
+	service, exists := manifest.Services[name]
+	if !exists {
+		return newNotFoundError("service '%s' is not defined in workbench.yaml", name)
+	}
+	switch {
+	case service.IsLibrary():
+		return newValidationError("service '%s' is a library and has nothing to open", name)
+	case service.Port == 0:
+		return newValidationError("service '%s' has no port to open", name)
+	case service.ProtocolOrDefault() != manifestPkg.ProtocolHTTP:
+		return newValidationError("service '%s' speaks %s, not HTTP, and cannot be opened in a browser", name, service.ProtocolOrDefault())
+	}
+
+	var url string
+	if env == "" {
+		port := service.Port
+		if service.IsContainer() {
+			lock, err := ports.Load(workspaceFS, projectRoot)
+			if err != nil {
+				return err
+			}
+			port = lock.HostPort(name, service.Port)
+		}
+		url = fmt.Sprintf("http://%s:%d", smoke.LocalHost, port)
+	} else {
+		environment, exists := manifest.Environments[env]
+		if !exists {
+			return newNotFoundError("environment '%s' is not defined in workbench.yaml", env)
+		}
+		if !environment.DeploysService(name, service) {
+			return newValidationError("service '%s' is not deployed to environment '%s'", name, env)
+		}
+		if url, err = environmentURL(projectRoot, env); err != nil {
+			return err
+		}
+	}
+
+	if printOnly {
+		fmt.Println(url)
+		return nil
+	}
+	fmt.Printf("🌐 Opening %s at %s\n", name, url)
+	if err := browserOpener(url); err != nil {
+		return &exitCodeError{code: ExitCodeExternalTool, err: fmt.Errorf("failed to open a browser (visit %s yourself): %w", url, err)}
+	}
+	return nil
+}
+
+// openInBrowser opens url with the platform's default handler
+func openInBrowser(url string) error {
+	var command *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		command = exec.Command("open", url)
+	case "windows":
+		command = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		command = exec.Command("xdg-open", url)
+	}
+	return command.Start()
+}
+
+// completeServiceNames completes the first argument with the services of
+// the project in the current directory
+func completeServiceNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	_, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return slices.Sorted(maps.Keys(manifest.Services)), cobra.ShellCompDirectiveNoFileComp
+}
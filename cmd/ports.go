@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/jashkahar/open-workbench-platform/internal/ports"
+	"github.com/spf13/cobra"
+)
+
+var portsCmd = &cobra.Command{
+	Use:   "ports",
+	Short: "Show the host ports the project publishes",
+	Long: `Show the host ports the project publishes.
+
+'om compose' publishes every port on the host port it asks for unless
+another process already uses it, in which case it moves to the next free
+port, so several projects can run side by side. The choice is recorded in
+.om/ports.lock and reused on every later run; delete the file to pick ports
+again.
+
+Examples:
+  # Show which host port reaches each container
+  om ports`,
+	Args: cobra.NoArgs,
+	RunE: runPorts,
+}
+
+// initPortsCommand registers the ports command
+func initPortsCommand() {
+	if rootCmd != nil {
+		rootCmd.AddCommand(portsCmd)
+	}
+}
+
+// runPorts prints the recorded host ports of the project
+func runPorts(cmd *cobra.Command, args []string) error {
+	projectRoot, _, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+	lock, err := ports.Load(workspaceFS, projectRoot)
+	if err != nil {
+		return err
+	}
+	if len(lock.Ports) == 0 {
+		fmt.Printf("✨ No published ports recorded in %s; run 'om compose' to publish them\n", filepath.ToSlash(ports.LockFile))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tCONTAINER\tHOST\tNOTE")
+	for _, allocation := range lock.Ports {
+		note := ""
+		if allocation.Moved() {
+			note = fmt.Sprintf("moved from %d, which was in use", allocation.Requested)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", allocation.Service, allocation.Container, allocation.Host, note)
+	}
+	return w.Flush()
+}
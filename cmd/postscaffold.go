@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+)
+
+// maxParallelSetups caps how many services run their post-scaffold commands
+// at once when several are scaffolded together
+const maxParallelSetups = 4
+
+// sharedInstallEnvironment is added to post-scaffold commands run in
+// parallel. The installs already share npm's and pip's download caches;
+// preferring cached packages lets a package several services depend on be
+// fetched once instead of revalidated by every install.
+var sharedInstallEnvironment = []string{
+	"npm_config_prefer_offline=true",
+	"PIP_DISABLE_PIP_VERSION_CHECK=1",
+}
+
+// postScaffoldJob is a scaffolded service whose post-scaffold commands,
+// such as dependency installs, have not run yet
+type postScaffoldJob struct {
+	name      string
+	dir       string
+	processor *templating.TemplateProcessor
+}
+
+// runPostScaffoldCommands runs the post-scaffold commands of several
+// services concurrently, with one progress line per service and a summary
+// at the end. Failing commands are reported, as when a single service is
+// scaffolded, and never fail the command.
+func runPostScaffoldCommands(jobs []postScaffoldJob) {
+	var pending []postScaffoldJob
+	for _, job := range jobs {
+		if job.processor.HasPostScaffoldCommands() {
+			pending = append(pending, job)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	// Commands need real files on disk; the processors explain the skip
+	if !filesystem.IsOS(workspaceFS) || len(pending) == 1 {
+		for _, job := range pending {
+			if err := job.processor.ExecutePostScaffoldCommands(job.dir); err != nil {
+				fmt.Printf("⚠️  Setup of %s failed: %v\n", job.name, err)
+			}
+		}
+		return
+	}
+
+	fmt.Printf("📦 Setting up %d services in parallel...\n", len(pending))
+	multi := templating.NewMultiProgress(os.Stdout, useColor())
+	slots := make(chan struct{}, maxParallelSetups)
+	var wg sync.WaitGroup
+	for _, job := range pending {
+		wg.Add(1)
+		go func(job postScaffoldJob) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			reporter := multi.Task(job.name)
+			job.processor.SetProgressReporter(reporter)
+			job.processor.SetCommandEnvironment(sharedInstallEnvironment)
+			reporter.StartOperation("Running post-scaffold commands")
+			err := job.processor.ExecutePostScaffoldCommands(job.dir)
+			failed := job.processor.FailedCommands()
+			switch {
+			case err != nil:
+				reporter.CompleteOperation(false, err.Error())
+			case len(failed) > 0:
+				reporter.CompleteOperation(false, "failed: "+strings.Join(failed, ", "))
+			default:
+				reporter.CompleteOperation(true, "ready")
+			}
+		}(job)
+	}
+	wg.Wait()
+
+	if summary := multi.Close(); summary.Failed > 0 {
+		fmt.Println("⚠️  Some setup commands failed; run them in the service directories above, or see the log with --debug")
+	}
+}
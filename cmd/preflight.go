@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"errors"
+	"runtime"
+
+	"github.com/jashkahar/open-workbench-platform/internal/preflight"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+	"github.com/spf13/cobra"
+)
+
+// diskFreeSpace and targetOS feed the scaffold preflight checks. Tests
+// replace them.
+var diskFreeSpace = preflight.FreeSpace
+var targetOS = runtime.GOOS
+
+// runScaffoldPreflight checks free disk space and, on Windows, path lengths
+// before a template is scaffolded into servicePath. Problems fail the
+// command before anything is written; likely problems are reported as
+// warnings.
+func runScaffoldPreflight(cmd *cobra.Command, catalog *templating.TemplateCatalog, templateName, servicePath string) error {
+	manifest, err := catalog.Manifest(templateName)
+	if err != nil {
+		return err
+	}
+	files, size, err := templating.TemplateFiles(catalog.FS(), templateName)
+	if err != nil {
+		return err
+	}
+
+	var commands []string
+	if manifest.PostScaffold != nil {
+		for _, command := range manifest.PostScaffold.Commands {
+			commands = append(commands, command.Command)
+		}
+	}
+
+	result := preflight.Run(preflight.Options{
+		Dir:       displayPath(servicePath),
+		Files:     files,
+		Commands:  commands,
+		TotalSize: size,
+		GOOS:      targetOS,
+		FreeSpace: diskFreeSpace,
+	})
+	if len(result.Errors) > 0 {
+		return newFileSystemError("preflight check failed: %w", errors.Join(result.Errors...))
+	}
+	return reportWarnings(cmd, result.Warnings)
+}
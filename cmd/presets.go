@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/presets"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+)
+
+// defaultsSource is a preset file or URL applied over the discovered
+// om-defaults.yaml files. It is set by --defaults or OM_DEFAULTS.
+var defaultsSource string
+
+// loadTeamPreset merges the defaults of the user's profile, the
+// om-defaults.yaml files from the working directory upwards, and the
+// --defaults source, each taking precedence over the ones before it
+func loadTeamPreset() (*presets.Preset, error) {
+	dir, err := workingDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	preset, err := presets.Discover(workspaceFS, dir)
+	if err != nil {
+		return nil, newValidationError("failed to load team preset: %w", err)
+	}
+	preset = profilePreset().Merge(preset)
+
+	source := defaultsSource
+	if source == "" {
+		source = os.Getenv("OM_DEFAULTS")
+	}
+	if source == "" {
+		return preset, nil
+	}
+	if !presets.IsURL(source) && !filepath.IsAbs(source) {
+		source = filepath.Join(dir, source)
+	}
+	explicit, err := presets.Load(workspaceFS, source)
+	if err != nil {
+		return nil, newValidationError("failed to load team preset: %w", err)
+	}
+	return preset.Merge(explicit), nil
+}
+
+// teamPresetAnswers returns the team preset answers for a template's
+// declared parameters and prints where they come from
+func teamPresetAnswers(templateName string, manifest *templating.TemplateManifest) (map[string]interface{}, error) {
+	preset, err := loadTeamPreset()
+	if err != nil {
+		return nil, err
+	}
+
+	answers := make(map[string]interface{})
+	for name, value := range preset.Answers(templateName) {
+		for _, param := range manifest.Parameters {
+			if param.Name == name {
+				answers[name] = value
+			}
+		}
+	}
+	if len(answers) > 0 {
+		fmt.Printf("📋 Using team preset answers from %s\n", strings.Join(preset.Sources, ", "))
+	}
+	return answers, nil
+}
+
+// applyTeamPreset fills the parameters missing from params with the team
+// preset answers for the template
+func applyTeamPreset(catalog *templating.TemplateCatalog, templateName string, params map[string]interface{}) (map[string]interface{}, error) {
+	manifest, err := catalog.Manifest(templateName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template manifest: %w", err)
+	}
+	answers, err := teamPresetAnswers(templateName, manifest)
+	if err != nil {
+		return nil, err
+	}
+	return seedParameterDefaults(params, answers), nil
+}
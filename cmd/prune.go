@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+	"github.com/jashkahar/open-workbench-platform/internal/prune"
+	"github.com/spf13/cobra"
+)
+
+// pruneRunner runs the docker commands of om prune. Tests replace it.
+var pruneRunner deps.Runner = deps.ExecRunner
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove Docker containers, volumes, and images of deleted entries",
+	Long: `Remove Docker containers, volumes, and images of deleted entries.
+
+'om compose' labels every container, volume, and built image with the project
+name (com.openworkbench.project) and the workbench.yaml entry it came from
+(com.openworkbench.entry). After services, components, or resources are
+deleted from workbench.yaml, their containers, database volumes, and images
+stay behind on your machine; prune lists the ones whose entry is gone and
+removes them after confirmation. Artifacts of other projects, and images
+pulled from registries, are never touched.
+
+Examples:
+  # Review and remove orphaned artifacts
+  om prune
+
+  # Only list them
+  om prune --dry-run`,
+	Args:        cobra.NoArgs,
+	RunE:        runPrune,
+	Annotations: map[string]string{dryRunAnnotation: "true"},
+}
+
+// initPruneCommand registers the prune command
+func initPruneCommand() {
+	if rootCmd != nil {
+		rootCmd.AddCommand(pruneCmd)
+	}
+}
+
+// runPrune removes the Docker artifacts of entries no longer in the manifest
+func runPrune(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return fmt.Errorf("failed to get dry-run flag: %w", err)
+	}
+
+	artifacts, err := prune.List(pruneRunner, projectRoot, manifest.Metadata.Name)
+	if err != nil {
+		return &exitCodeError{code: ExitCodeExternalTool, err: err}
+	}
+	orphaned := prune.Orphaned(artifacts, manifest)
+	if len(orphaned) == 0 {
+		fmt.Printf("✨ No orphaned Docker artifacts for project '%s'\n", manifest.Metadata.Name)
+		return nil
+	}
+
+	fmt.Printf("🧹 %d orphaned Docker artifact(s) of project '%s':\n", len(orphaned), manifest.Metadata.Name)
+	for _, artifact := range orphaned {
+		fmt.Printf("  • %s\n", artifact)
+	}
+	if dryRun {
+		fmt.Println("\n💡 Run 'om prune' without --dry-run to remove them")
+		return nil
+	}
+
+	confirmed, err := prompter.Confirm(prompt.Question{
+		Name:    "confirm",
+		Message: fmt.Sprintf("Remove these %d artifact(s)? Volumes lose their data", len(orphaned)),
+		Help:    "Containers are stopped and removed first, then volumes, then images",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get confirmation: %w", err)
+	}
+	if !confirmed {
+		return newCancelledError("prune cancelled")
+	}
+
+	removed, err := prune.Remove(pruneRunner, projectRoot, orphaned)
+	fmt.Printf("\n✅ Removed %d of %d artifact(s)\n", len(removed), len(orphaned))
+	if err != nil {
+		return &exitCodeError{code: ExitCodeExternalTool, err: err}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/refs"
+	"github.com/spf13/cobra"
+)
+
+var refsCmd = &cobra.Command{
+	Use:     "refs <target>",
+	Aliases: []string{"grep"},
+	Short:   "Find where a service, resource, or other manifest entry is used",
+	Long: `Find where a service, resource, or other manifest entry is used.
+
+Lists every place that would break if the target were renamed or deleted:
+${...} references to it in workbench.yaml and its included files, the
+consumes, libraries, groups, and environment include and exclude lists that
+name it, and the lines of generated files (docker-compose.yml, .env,
+terraform/, and the .env files of services and components) that mention
+it, such as the host name other services reach a database on.
+
+The target is a service or component by name, a resource as
+<service>.<resource>, or a manifest path: services.<name>,
+services.<name>.resources.<resource>, components.<name>, external.<name>,
+infra.<output>, or env.<key>.
+
+Examples:
+  # Before renaming a database
+  om refs backend.database
+
+  # Which services use an infra output
+  om refs infra.assets_bucket`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runRefs,
+	ValidArgsFunction: completeServiceNames,
+}
+
+// initRefsCommand registers the refs command
+func initRefsCommand() {
+	if rootCmd != nil {
+		rootCmd.AddCommand(refsCmd)
+	}
+
+	refsCmd.Flags().Bool("manifest-only", false, "Search workbench.yaml and its included files only")
+}
+
+// runRefs prints every reference to the target
+func runRefs(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+	manifestOnly, err := cmd.Flags().GetBool("manifest-only")
+	if err != nil {
+		return fmt.Errorf("failed to get manifest-only flag: %w", err)
+	}
+
+	target, err := refs.ParseTarget(manifest, args[0])
+	if errors.Is(err, refs.ErrNotFound) {
+		return newNotFoundError("%v", err)
+	}
+	if err != nil {
+		return newValidationError("%v", err)
+	}
+
+	workbenchPath := filepath.Join(projectRoot, "workbench.yaml")
+	manifestFiles := []string{"workbench.yaml"}
+	included, err := manifestPkg.IncludedFiles(workspaceFS, workbenchPath, manifest)
+	if err != nil {
+		return err
+	}
+	for _, file := range included {
+		relative, err := filepath.Rel(projectRoot, file)
+		if err != nil {
+			return fmt.Errorf("failed to locate %s: %w", file, err)
+		}
+		manifestFiles = append(manifestFiles, filepath.ToSlash(relative))
+	}
+	var generated []string
+	if !manifestOnly {
+		generated = append(generatedFiles(projectRoot), refs.EnvFiles(manifest)...)
+	}
+
+	found, err := refs.Find(workspaceFS, projectRoot, manifestFiles, generated, target)
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		fmt.Printf("✨ Nothing refers to %s\n", target.Entry)
+		return nil
+	}
+
+	fmt.Printf("🔎 %d reference(s) to %s\n", len(found), target.Entry)
+	file := ""
+	for _, ref := range found {
+		if ref.File != file {
+			file = ref.File
+			fmt.Printf("\n%s\n", file)
+		}
+		if ref.Field != "" {
+			fmt.Printf("  %d: %s: %s\n", ref.Line, ref.Field, ref.Text)
+		} else {
+			fmt.Printf("  %d: %s\n", ref.Line, ref.Text)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+	"github.com/spf13/cobra"
+)
+
+// templateRunner runs git to fetch remote templates. Tests replace it.
+var templateRunner deps.Runner = deps.ExecRunner
+
+// templateCacheDir returns the directory remote templates are cloned into:
+// OM_TEMPLATE_CACHE, or open-workbench/templates in the user's cache
+func templateCacheDir() string {
+	if dir := os.Getenv("OM_TEMPLATE_CACHE"); dir != "" {
+		return dir
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "open-workbench", "templates")
+}
+
+// templateCatalog returns the templates a command chooses from. When its
+// --template flag names a git repository, such as
+// github.com/org/tpl@v1.2.0, the template is fetched first and offered
+// alongside the built-in ones under that name.
+func templateCatalog(cmd *cobra.Command) (*templating.TemplateCatalog, error) {
+	name, _ := cmd.Flags().GetString("template")
+	if !templating.IsRemoteTemplate(name) {
+		return templating.NewTemplateCatalog(templatesFS), nil
+	}
+	if _, err := templating.ParseRemoteSource(name); err != nil {
+		return nil, newValidationError("%v", err)
+	}
+
+	fmt.Printf("📥 Fetching template %s\n", name)
+	templateFS, err := templating.ResolveTemplate(templatesFS, name, templateCacheDir(), templateRunner)
+	if err != nil {
+		return nil, err
+	}
+	return templating.NewTemplateCatalog(templateFS), nil
+}
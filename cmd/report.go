@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/gencache"
+	"github.com/jashkahar/open-workbench-platform/internal/report"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+	"github.com/spf13/cobra"
+)
+
+// reportClock times generation runs and stamps their reports. Tests replace it.
+var reportClock = time.Now
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show reports of past generation runs",
+}
+
+var reportLastCmd = &cobra.Command{
+	Use:   "last",
+	Short: "Show the report of the latest generation run",
+	Long: `Show the report of the latest generation run.
+
+Every 'om compose' run that writes configuration leaves a JSON report under
+.om/reports/, named after the command and the time it started
+(compose-20260102-150405.json). A report records the hash of workbench.yaml,
+the target, group, and environment, the other files the generator read, the
+files it wrote with their sizes and hashes, its warnings, and how long each
+phase took, so platform teams can tell what was generated when and from what.
+
+Examples:
+  # Summarize the latest run
+  om report last
+
+  # Print the report itself, e.g. to archive it in CI
+  om report last --json`,
+	Args: cobra.NoArgs,
+	RunE: runReportLast,
+}
+
+// initReportCommand registers the report command and its subcommands
+func initReportCommand() {
+	reportCmd.AddCommand(reportLastCmd)
+	if rootCmd != nil {
+		rootCmd.AddCommand(reportCmd)
+	}
+
+	reportLastCmd.Flags().Bool("json", false, "Print the report as JSON")
+}
+
+// runReportLast prints the newest report of the project
+func runReportLast(cmd *cobra.Command, args []string) error {
+	projectRoot, _, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+
+	path, latest, err := report.Latest(workspaceFS, projectRoot)
+	if errors.Is(err, report.ErrNoReports) {
+		return newNotFoundError("no generation reports in %s; run 'om compose' first", filepath.ToSlash(report.Dir))
+	}
+	if err != nil {
+		return err
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		data, err := workspaceFS.ReadFile(filepath.Join(projectRoot, path))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	fmt.Printf("📄 %s\n", filepath.ToSlash(path))
+	fmt.Printf("   om %s, %s by om %s\n", latest.Command, latest.StartedAt.Local().Format("2006-01-02 15:04:05"), latest.OMVersion)
+	fmt.Printf("   Target: %s", latest.Inputs.Target)
+	if latest.Inputs.Group != "" {
+		fmt.Printf(", group %s", latest.Inputs.Group)
+	}
+	if latest.Inputs.Env != "" {
+		fmt.Printf(", environment %s", latest.Inputs.Env)
+	}
+	fmt.Println()
+	fmt.Printf("   Manifest: %s (sha256 %s)\n", latest.Inputs.Manifest, shortHash(latest.Inputs.ManifestSHA256))
+	if len(latest.Inputs.Files) > 0 {
+		fmt.Printf("   Read %d other file(s)\n", len(latest.Inputs.Files))
+	}
+
+	fmt.Printf("\n📦 Wrote %d file(s):\n", len(latest.Outputs))
+	for _, file := range latest.Outputs {
+		fmt.Printf("  • %s (%d bytes)\n", file.Path, file.Size)
+	}
+
+	fmt.Printf("\n⏱️  %d ms:", latest.DurationMS)
+	for _, phase := range latest.Durations {
+		fmt.Printf(" %s %d ms", phase.Name, phase.DurationMS)
+	}
+	fmt.Println()
+
+	if len(latest.Warnings) > 0 {
+		fmt.Printf("\n⚠️  %d warning(s):\n", len(latest.Warnings))
+		for _, warning := range latest.Warnings {
+			fmt.Printf("  • %s\n", warnings.Warning{Entry: warning.Entry, Message: warning.Message})
+		}
+	}
+	return nil
+}
+
+// shortHash abbreviates a hash for display
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+// writeGenerationReport records a generation run under .om/reports/. The
+// configuration has already been written, so a report that cannot be saved
+// is only a warning.
+func writeGenerationReport(projectRoot, projectName string, timer *report.Timer, tracker *gencache.TrackingFS, inputs report.Inputs, found []warnings.Warning) {
+	inputs.Manifest = "workbench.yaml"
+	inputs.ManifestSHA256 = report.Describe(workspaceFS, projectRoot, inputs.Manifest).SHA256
+	for _, path := range tracker.Read() {
+		if rel := projectRelative(projectRoot, path); rel != inputs.Manifest {
+			inputs.Files = append(inputs.Files, report.Describe(workspaceFS, projectRoot, rel))
+		}
+	}
+	var outputs []report.File
+	for _, path := range tracker.Written() {
+		outputs = append(outputs, report.Describe(workspaceFS, projectRoot, projectRelative(projectRoot, path)))
+	}
+
+	path, err := report.Write(workspaceFS, projectRoot, &report.Report{
+		Command:    "compose",
+		OMVersion:  Version,
+		Project:    projectName,
+		StartedAt:  timer.Started().UTC(),
+		Inputs:     inputs,
+		Outputs:    outputs,
+		Warnings:   report.Warnings(found),
+		Durations:  timer.Phases,
+		DurationMS: timer.Total(),
+	})
+	if err != nil {
+		fmt.Printf("⚠️  Could not write the generation report: %v\n", err)
+		return
+	}
+	fmt.Printf("🧾 Wrote report %s\n", filepath.ToSlash(path))
+}
+
+// projectRelative returns path relative to the project root with forward slashes
+func projectRelative(projectRoot, path string) string {
+	rel, err := filepath.Rel(projectRoot, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
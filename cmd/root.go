@@ -2,18 +2,59 @@ package cmd
 
 import (
 	"embed"
+	"fmt"
+	"io/fs"
 	"os"
+	"sync"
 
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
 	"github.com/spf13/cobra"
 )
 
 var rootCmd *cobra.Command
-var templatesFS embed.FS
+var templatesFS fs.FS
+
+// Version is the version of om, set at build time with
+// -ldflags "-X github.com/jashkahar/open-workbench-platform/cmd.Version=<version>"
+var Version = templating.DevelopmentVersion
+
+// prompter asks all interactive questions. Tests replace it with a scripted
+// prompter; frontends select the JSON protocol with --prompts=json.
+var prompter prompt.Prompter = prompt.NewSurveyPrompter()
+
+// workspaceFS performs every write into the user's project. Tests replace it
+// with an in-memory file system; workingDir is overridden alongside it.
+var workspaceFS filesystem.FS = filesystem.NewOSFS()
+var workingDir = os.Getwd
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute(fs embed.FS) {
-	templatesFS = fs
+	templatesFS = withLocalTemplates(fs)
+
+	failedCmd, err := setupRootCommand().ExecuteC()
+	finishDryRun(err)
+	stopDebugLog(err)
+	if err != nil {
+		presentError(os.Stderr, err, failedCmd)
+		os.Exit(exitCodeForError(err))
+	}
+}
+
+// rootOnce guards construction of the command tree. Subcommands are package
+// level values, so their flags can only be registered once per process.
+var rootOnce sync.Once
+
+// setupRootCommand builds the command tree on first use and returns the root command
+func setupRootCommand() *cobra.Command {
+	rootOnce.Do(buildRootCommand)
+	return rootCmd
+}
+
+// buildRootCommand creates the root command and registers all subcommands
+func buildRootCommand() {
 	rootCmd = &cobra.Command{
 		Use:   "om",
 		Short: "Open Workbench - A modern CLI for scaffolding web applications",
@@ -32,10 +73,37 @@ Features:
 		CompletionOptions: cobra.CompletionOptions{
 			DisableDefaultCmd: true,
 		},
+		PersistentPreRunE: prepareCommand,
+		Version:           Version,
 	}
 
+	// Prompt frontend selection for API/TUI integrations
+	rootCmd.PersistentFlags().String("prompts", "terminal", "How to ask interactive questions (terminal, json)")
+	_ = rootCmd.PersistentFlags().MarkHidden("prompts")
+
+	// Team preset applied over the discovered om-defaults.yaml files
+	rootCmd.PersistentFlags().StringVar(&defaultsSource, "defaults", "", "Team preset file or URL with default template answers (or set OM_DEFAULTS)")
+
+	// Fail on warnings, e.g. in CI
+	rootCmd.PersistentFlags().Bool("strict", false, "Treat warnings as errors")
+
+	// Run the hooks a project declares without asking, e.g. in CI
+	rootCmd.PersistentFlags().Bool("trust-hooks", false, "Run the hooks of workbench.yaml and .om/hooks.yaml without asking for approval")
+
+	// Preview the changes of init, add, delete, compose, and prune
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Show what the command would create, change, or delete without writing anything")
+
+	// Capture diagnostics for bug reports
+	rootCmd.PersistentFlags().BoolVar(&debugEnabled, "debug", false, "Write a debug log under .om/logs/")
+
 	// Add subcommands
 	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().BoolVar(&advancedPrompts, "advanced", false, "Ask advanced template questions too")
+	initCmd.Flags().String("name", "", "Project name (optional - will prompt if not provided)")
+	initCmd.Flags().String("service", "", "Name of the first service (optional - will prompt if not provided)")
+	initCmd.Flags().String("template", "", "Template of the first service, a name or git repository (optional - will prompt if not provided)")
+	initCmd.Flags().StringToString("params", nil, "Template parameters as key=value pairs (e.g., --params IncludeTesting=true,Framework=React)")
+	initCmd.Flags().Bool("yes", false, "Never prompt: use template defaults and fail when a required detail is missing")
 	rootCmd.AddCommand(listTemplatesCmd) // Top-level command
 
 	// Force inclusion of add_service.go by calling functions from it
@@ -51,21 +119,145 @@ Features:
 	// Initialize add resource command
 	initAddResourceCommand()
 
+	// Initialize add shared command
+	initAddSharedCommand()
+
 	// Initialize compose command
 	initComposeCommand()
 
+	// Initialize local development run command
+	initDevCommand()
+
 	// Initialize ls command
 	initLsCommand()
 
 	// Initialize delete command
 	initDeleteCommand()
 
-	// Removed validate command
+	// Initialize topic-aware help command
+	initHelpCommand()
+
+	// Initialize validate command
+	initValidateCommand()
+
+	// Initialize generate command
+	initGenerateCommand()
+
+	// Initialize explain command
+	initExplainCommand()
+
+	// Initialize registry login command
+	initLoginCommand()
+
+	// Initialize dependency update command
+	initDepsCommand()
+
+	// Initialize Dockerfile lint command
+	initLintCommand()
+
+	// Initialize architecture decision record command
+	initADRCommand()
+
+	// Initialize watch command
+	initWatchCommand()
+
+	// Initialize generation report command
+	initReportCommand()
 
-	err := rootCmd.Execute()
+	// Initialize Docker cleanup command
+	initPruneCommand()
+
+	// Initialize health check smoke test command
+	initSmokeCommand()
+
+	// Initialize deployed infrastructure commands
+	initInfraCommand()
+
+	// Initialize published host ports command
+	initPortsCommand()
+
+	// Initialize service browser launch command
+	initOpenCommand()
+
+	// Initialize manifest reference search command
+	initRefsCommand()
+
+	// Initialize resolved service view command
+	initInspectCommand()
+
+	// Initialize bundle command
+	initBundleCommand()
+
+	// Initialize container engine check command
+	initDoctorCommand()
+
+	// Initialize secrets rotation command
+	initSecretsCommand()
+
+	// Initialize environment variable listing command
+	initEnvCommand()
+
+	// Initialize experiments listing command
+	initExperimentsCommand()
+
+	// Initialize personal defaults wizard command
+	initSetupCommand()
+
+	// Initialize package cache command
+	initCacheCommand()
+
+	// Initialize example projects command
+	initExamplesCommand()
+
+	// Initialize hidden template maintenance command
+	initTemplateCommand()
+
+	// Flag parsing errors are usage errors
+	rootCmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return newUsageError(err)
+	})
+
+	// Errors are reported once, by presentError, instead of by Cobra
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+}
+
+// prepareCommand configures the prompt frontend and the debug log before
+// any command runs, stops experimental commands that are not enabled, and
+// runs the setup wizard on the first run
+func prepareCommand(cmd *cobra.Command, args []string) error {
+	if err := configurePrompter(cmd, args); err != nil {
+		return err
+	}
+	if err := startDebugLog(cmd, args); err != nil {
+		return err
+	}
+	if err := checkExperimentalCommand(cmd); err != nil {
+		return err
+	}
+	if err := startDryRun(cmd); err != nil {
+		return err
+	}
+	offerFirstRunSetup(cmd)
+	return nil
+}
+
+// configurePrompter selects the prompt frontend from the --prompts flag
+func configurePrompter(cmd *cobra.Command, args []string) error {
+	mode, err := cmd.Flags().GetString("prompts")
 	if err != nil {
-		os.Exit(1)
+		return fmt.Errorf("failed to get prompts flag: %w", err)
+	}
+
+	switch mode {
+	case "", "terminal":
+		// Keep the current prompter (terminal by default, scripted in tests)
+	case "json":
+		prompter = prompt.NewJSONPrompter(os.Stdin, os.Stdout)
+	default:
+		return newValidationError("invalid prompts mode '%s'. Valid modes are: terminal, json", mode)
 	}
+	return nil
 }
 
 func init() {
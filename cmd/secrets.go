@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+// secretsRunner runs the CLIs of external secret stores, and secretsRand is
+// where generated secrets come from. Tests replace them.
+var (
+	secretsRunner deps.Runner = deps.ExecRunner
+	secretsRand   io.Reader   = rand.Reader
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage the passwords in the generated .env file",
+	Long: `Manage the passwords of resources, such as databases, that 'om compose'
+writes to .env.
+
+By default they are generated at random the first time and kept in .env on
+later runs. The secrets section of workbench.yaml reads them from elsewhere
+instead:
+
+  secrets:
+    provider: env      # API_DB_PASSWORD and the like, e.g. set by CI
+  secrets:
+    provider: sops     # a file encrypted with SOPS
+    source: secrets.enc.yaml
+  secrets:
+    provider: vault    # a Vault KV secret, read with the vault CLI
+    source: secret/shop/dev
+  secrets:
+    provider: aws      # an AWS Secrets Manager secret holding a JSON object
+    source: shop/dev`,
+}
+
+var secretsRotateCmd = &cobra.Command{
+	Use:   "rotate [variable...]",
+	Short: "Replace generated passwords in .env with new random ones",
+	Long: `Replace the generated passwords in .env with new random ones: all of them,
+or only the variables given.
+
+Databases keep the password they were created with, so after rotating
+either change it inside the database or recreate its volume, then restart
+the services. Passwords read from an external provider are rotated in that
+store instead.
+
+Examples:
+  # Rotate every generated password
+  om secrets rotate
+
+  # Rotate only the password of the api's database
+  om secrets rotate api_db_password`,
+	RunE: runSecretsRotate,
+}
+
+// initSecretsCommand registers the secrets command and its subcommands
+func initSecretsCommand() {
+	secretsCmd.AddCommand(secretsRotateCmd)
+	if rootCmd != nil {
+		rootCmd.AddCommand(secretsCmd)
+	}
+
+	secretsRotateCmd.Flags().BoolP("yes", "y", false, "Rotate without asking for confirmation")
+}
+
+// newSecretsProvider returns the secrets provider workbench.yaml configures,
+// keeping the generated secrets of the .env file in projectRoot on fsys
+func newSecretsProvider(fsys filesystem.FS, projectRoot string, manifest *manifestPkg.WorkbenchManifest) (compose.SecretsProvider, error) {
+	existing, err := compose.ReadEnvFile(fsys, filepath.Join(projectRoot, ".env"))
+	if err != nil {
+		return nil, err
+	}
+	return compose.NewSecretsProvider(manifest.Secrets, projectRoot, compose.SecretsSources{
+		Existing: existing,
+		Rand:     secretsRand,
+		Getenv:   os.Getenv,
+		Run:      secretsRunner,
+	}), nil
+}
+
+// runSecretsRotate generates new values for the selected secrets in .env
+func runSecretsRotate(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+	if provider := manifest.Secrets.ProviderName(); provider != manifestPkg.SecretsGenerate {
+		return newValidationError("secrets come from the %s provider; rotate them there and run 'om compose' to pick them up", provider)
+	}
+
+	keys := compose.SecretKeys(manifest)
+	if len(args) > 0 {
+		for _, key := range args {
+			if !slices.Contains(keys, key) {
+				return newNotFoundError("'%s' is not a generated secret (have: %s)", key, strings.Join(keys, ", "))
+			}
+		}
+		keys = args
+	}
+	if len(keys) == 0 {
+		fmt.Println("✨ No resources with passwords in workbench.yaml; nothing to rotate")
+		return nil
+	}
+
+	fmt.Println("🔑 Secrets to rotate:")
+	for _, key := range keys {
+		fmt.Printf("  • %s\n", key)
+	}
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+		confirmed, err := prompter.Confirm(prompt.Question{
+			Name:    "rotateSecrets",
+			Message: fmt.Sprintf("Replace these %d password(s) in .env?", len(keys)),
+			Help:    "Databases keep the password they were created with; change it inside them or recreate their volumes afterwards",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			return newCancelledError("rotation cancelled")
+		}
+	}
+
+	envPath := filepath.Join(projectRoot, ".env")
+	values, err := compose.ReadEnvFile(workspaceFS, envPath)
+	if err != nil {
+		return err
+	}
+	rotated, err := compose.GeneratedSecrets{Rand: secretsRand}.Secrets(keys)
+	if err != nil {
+		return err
+	}
+	for key, value := range rotated {
+		values[key] = value
+	}
+	if err := compose.WriteEnvFile(workspaceFS, values, envPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Rotated %d secret(s) in %s\n", len(keys), envPath)
+	fmt.Println()
+	fmt.Println("🚀 Next steps:")
+	fmt.Println("  1. Change the passwords inside the databases, or, if their data can go, recreate their volumes with 'docker compose down --volumes'")
+	fmt.Println("  2. Restart the services so they read the new .env")
+	return nil
+}
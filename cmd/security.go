@@ -7,6 +7,9 @@ import (
 	"regexp"
 	"strings"
 	"unicode"
+
+	"github.com/jashkahar/open-workbench-platform/internal/slug"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
 )
 
 // SecurityConfig holds security-related configuration
@@ -38,28 +41,28 @@ func ValidateAndSanitizePath(path string, config *SecurityConfig) (string, error
 
 	// Check for empty path
 	if strings.TrimSpace(path) == "" {
-		return "", fmt.Errorf("path cannot be empty")
+		return "", newValidationError("path cannot be empty")
 	}
 
 	// Check path length
 	if len(path) > config.MaxPathLength {
-		return "", fmt.Errorf("path too long (max %d characters)", config.MaxPathLength)
+		return "", newValidationError("path too long (max %d characters)", config.MaxPathLength)
 	}
 
 	// Check for path traversal attempts
 	if strings.Contains(path, "..") {
-		return "", fmt.Errorf("path traversal not allowed")
+		return "", newValidationError("path traversal not allowed")
 	}
 
 	// Check for absolute paths
 	if filepath.IsAbs(path) {
-		return "", fmt.Errorf("absolute paths not allowed")
+		return "", newValidationError("absolute paths not allowed")
 	}
 
 	// Check for forbidden patterns
 	for _, pattern := range config.ForbiddenPatterns {
 		if pattern.MatchString(path) {
-			return "", fmt.Errorf("path contains forbidden pattern: %s", pattern.String())
+			return "", newValidationError("path contains forbidden pattern: %s", pattern.String())
 		}
 	}
 
@@ -68,7 +71,7 @@ func ValidateAndSanitizePath(path string, config *SecurityConfig) (string, error
 
 	// Ensure the cleaned path doesn't start with ../
 	if strings.HasPrefix(cleanPath, "..") {
-		return "", fmt.Errorf("path traversal not allowed")
+		return "", newValidationError("path traversal not allowed")
 	}
 
 	return cleanPath, nil
@@ -82,42 +85,67 @@ func ValidateAndSanitizeName(name string, config *SecurityConfig) (string, error
 
 	// Check for empty name
 	if strings.TrimSpace(name) == "" {
-		return "", fmt.Errorf("name cannot be empty")
+		return "", newValidationError("name cannot be empty")
 	}
 
 	// Check name length
 	if len(name) > config.MaxNameLength {
-		return "", fmt.Errorf("name too long (max %d characters)", config.MaxNameLength)
+		return "", newValidationError("name too long (max %d characters)", config.MaxNameLength)
 	}
 
 	// Check for forbidden patterns
 	for _, pattern := range config.ForbiddenPatterns {
 		if pattern.MatchString(name) {
-			return "", fmt.Errorf("name contains forbidden pattern: %s", pattern.String())
+			return "", newValidationError("name contains forbidden pattern: %s", pattern.String())
 		}
 	}
 
 	// Validate character set
 	if !config.AllowedCharacters.MatchString(name) {
-		return "", fmt.Errorf("name can only contain lowercase letters, numbers, and hyphens")
+		return "", newValidationError("name can only contain lowercase letters, numbers, and hyphens")
 	}
 
 	// Must start with a letter
 	if len(name) > 0 && !unicode.IsLetter(rune(name[0])) {
-		return "", fmt.Errorf("name must start with a letter")
+		return "", newValidationError("name must start with a letter")
 	}
 
 	// Must end with a letter or number
 	if len(name) > 0 {
 		lastChar := rune(name[len(name)-1])
 		if !unicode.IsLetter(lastChar) && !unicode.IsNumber(lastChar) {
-			return "", fmt.Errorf("name must end with a letter or number")
+			return "", newValidationError("name must end with a letter or number")
 		}
 	}
 
 	return strings.ToLower(strings.TrimSpace(name)), nil
 }
 
+// nameFromAnswer turns a name typed at a prompt, such as "Café Orders", into
+// one om accepts by slugging it, and says so when the name had to change.
+// A changed name that taken reports in use gets a numeric suffix instead of
+// failing later; a name typed exactly as it should be is left to the usual
+// duplicate checks. kind names what is being named in messages.
+func nameFromAnswer(kind, answer string, taken func(string) bool) (string, error) {
+	name := slug.Make(answer)
+	if name == "" {
+		return "", newValidationError("%s name '%s' has no letters or digits to build a name from", kind, strings.TrimSpace(answer))
+	}
+	if name != strings.TrimSpace(answer) {
+		name = slug.Unique(name, taken)
+		fmt.Printf("ℹ️  Using '%s' as the %s name\n", name, kind)
+	}
+
+	sanitized, err := ValidateAndSanitizeName(name, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := CheckForSuspiciousPatterns(sanitized); err != nil {
+		return "", err
+	}
+	return sanitized, nil
+}
+
 // ValidateDirectorySafety performs comprehensive directory safety checks
 func ValidateDirectorySafety(dirPath string) error {
 	// Check if directory exists and is accessible
@@ -146,23 +174,32 @@ func ValidateDirectorySafety(dirPath string) error {
 // ValidateTemplateName validates a template name for security
 func ValidateTemplateName(templateName string) error {
 	if strings.TrimSpace(templateName) == "" {
-		return fmt.Errorf("template name cannot be empty")
+		return newValidationError("template name cannot be empty")
+	}
+
+	// Templates in git repositories are named by their path, such as
+	// github.com/org/tpl@v1.2.0
+	if templating.IsRemoteTemplate(templateName) {
+		if _, err := templating.ParseRemoteSource(templateName); err != nil {
+			return newValidationError("%v", err)
+		}
+		return nil
 	}
 
 	// Check for path traversal
 	if strings.Contains(templateName, "..") || strings.Contains(templateName, "/") || strings.Contains(templateName, "\\") {
-		return fmt.Errorf("template name contains invalid characters")
+		return newValidationError("template name contains invalid characters")
 	}
 
 	// Check length
 	if len(templateName) > 100 {
-		return fmt.Errorf("template name too long")
+		return newValidationError("template name too long")
 	}
 
 	// Check for allowed characters only
 	allowedPattern := regexp.MustCompile(`^[a-zA-Z0-9-_]+$`)
 	if !allowedPattern.MatchString(templateName) {
-		return fmt.Errorf("template name contains invalid characters")
+		return newValidationError("template name contains invalid characters")
 	}
 
 	return nil
@@ -213,7 +250,7 @@ func CheckForSuspiciousPatterns(input string) error {
 	inputLower := strings.ToLower(input)
 	for _, pattern := range suspiciousPatterns {
 		if strings.Contains(inputLower, pattern) {
-			return fmt.Errorf("input contains suspicious pattern: %s", pattern)
+			return newValidationError("input contains suspicious pattern: %s", pattern)
 		}
 	}
 
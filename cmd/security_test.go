@@ -159,6 +159,9 @@ func TestValidateTemplateName(t *testing.T) {
 		{"suspicious patterns", "javascript:alert(1)", true},
 		{"suspicious patterns 2", "data:text/html,<script>alert(1)</script>", true},
 		{"suspicious patterns 3", "eval(alert(1))", true},
+		{"remote template", "github.com/org/tpl@v1.2.0", false},
+		{"remote template with traversal", "github.com/org/../tpl", true},
+		{"remote template with option as version", "github.com/org/tpl@--upload-pack=x", true},
 	}
 
 	for _, tt := range tests {
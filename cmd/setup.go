@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/onboarding"
+	"github.com/jashkahar/open-workbench-platform/internal/presets"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// firstRunInteractive reports whether the first-run wizard may ask its
+// questions: only in a terminal, and never in CI. Tests replace it.
+var firstRunInteractive = func() bool {
+	return os.Getenv("CI") == "" && (isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd()))
+}
+
+// toolLookPath finds the tools the getting-started checklist depends on.
+// Tests replace it.
+var toolLookPath = exec.LookPath
+
+var setupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Set your default owner, package manager, and cloud",
+	Long: `Set your personal defaults and show a getting-started checklist.
+
+om asks these questions once, on the first run in a terminal, and stores the
+answers in the profile section of ~/.om/config.yaml:
+
+  - Default owner: pre-fills the Owner parameter of templates
+  - Preferred package manager: pre-fills templates that ask for one
+  - Telemetry: whether you opt in to anonymous usage statistics (off by
+    default; om does not send any today, and will respect your answer)
+  - Cloud provider and region: the defaults of new deployment environments
+
+Team presets (om-defaults.yaml and --defaults) take precedence over these
+defaults. Run om setup again at any time to change them; the checklist that
+follows is tailored to whether Docker and Terraform are installed.`,
+	Args: cobra.NoArgs,
+	RunE: runSetup,
+}
+
+// initSetupCommand registers the setup command with the root command
+func initSetupCommand() {
+	if rootCmd != nil {
+		rootCmd.AddCommand(setupCmd)
+	}
+}
+
+// runSetup asks for the user's defaults, starting from the current ones
+func runSetup(cmd *cobra.Command, args []string) error {
+	configPath, err := userConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to locate the user config: %w", err)
+	}
+	profile, _, err := onboarding.Load(workspaceFS, configPath)
+	if err != nil {
+		return newValidationError("%v", err)
+	}
+	return runOnboardingWizard(configPath, profile)
+}
+
+// offerFirstRunSetup runs the wizard before the first command run in a
+// terminal without a profile. The wizard never stops the command: a
+// cancelled wizard stores the defaults so it is not asked again, and a
+// failing one is reported as a warning.
+func offerFirstRunSetup(cmd *cobra.Command) {
+	switch cmd.Name() {
+	case "setup", "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return
+	}
+	if mode, _ := cmd.Flags().GetString("prompts"); mode == "json" || !firstRunInteractive() {
+		return
+	}
+	if assumeYes, _ := cmd.Flags().GetBool("yes"); assumeYes || dryRun != nil {
+		return
+	}
+	configPath, err := userConfigPath()
+	if err != nil {
+		return
+	}
+	if _, found, err := onboarding.Load(workspaceFS, configPath); err != nil || found {
+		return
+	}
+
+	fmt.Println("👋 Welcome to Open Workbench! A few questions to set your defaults (change them later with om setup).")
+	err = runOnboardingWizard(configPath, onboarding.Profile{})
+	if errors.Is(err, prompt.ErrCancelled) {
+		if err := onboarding.Save(workspaceFS, configPath, onboarding.Profile{}); err != nil {
+			fmt.Printf("⚠️  Could not save your defaults: %v\n", err)
+		}
+		fmt.Println("⏭️  Skipped; run om setup at any time")
+	} else if err != nil {
+		fmt.Printf("⚠️  Setup did not finish: %v\n", err)
+	}
+	fmt.Println()
+}
+
+// runOnboardingWizard asks the setup questions, saves the answers, and
+// prints the getting-started checklist
+func runOnboardingWizard(configPath string, current onboarding.Profile) error {
+	owner, err := prompter.Input(prompt.Question{
+		Name:    "owner",
+		Message: "Default owner of the services you create (name, email, or @handle):",
+		Default: current.Owner,
+		Help:    "Pre-fills the Owner parameter of templates; leave empty to be asked each time",
+		Validate: func(value string) error {
+			return manifest.Ownership{Owner: value}.Validate("owner")
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	packageManager, err := prompter.Select(prompt.Question{
+		Name:    "packageManager",
+		Message: "Preferred JavaScript package manager:",
+		Options: onboarding.PackageManagers,
+		Default: defaultOption(current.PackageManager, "npm"),
+	})
+	if err != nil {
+		return err
+	}
+
+	telemetry, err := prompter.Confirm(prompt.Question{
+		Name:    "telemetry",
+		Message: "Share anonymous usage statistics to help improve om?",
+		Default: current.Telemetry,
+		Help:    "om does not send any data today; your answer is stored and respected once it does",
+	})
+	if err != nil {
+		return err
+	}
+
+	provider, err := prompter.Select(prompt.Question{
+		Name:    "cloudProvider",
+		Message: "Cloud provider you deploy to:",
+		Options: onboarding.Providers,
+		Default: defaultOption(current.Cloud.Provider, "none"),
+	})
+	if err != nil {
+		return err
+	}
+	cloud := onboarding.Cloud{Provider: provider}
+	if provider != "none" {
+		region := current.Cloud.Region
+		if current.Cloud.Provider != provider || region == "" {
+			region = onboarding.DefaultRegions[provider]
+		}
+		cloud.Region, err = prompter.Input(prompt.Question{
+			Name:     "cloudRegion",
+			Message:  fmt.Sprintf("Default %s region:", provider),
+			Default:  region,
+			Required: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	profile := onboarding.Profile{Owner: owner, PackageManager: packageManager, Telemetry: telemetry, Cloud: cloud}
+	if err := onboarding.Save(workspaceFS, configPath, profile); err != nil {
+		return fmt.Errorf("failed to save your defaults: %w", err)
+	}
+	fmt.Printf("✅ Saved your defaults to %s\n", configPath)
+
+	fmt.Println("\n📋 Getting started:")
+	for _, step := range onboarding.Checklist(profile, onboarding.DetectTools(toolLookPath)) {
+		mark := "⬜"
+		if step.Done {
+			mark = "✅"
+		}
+		fmt.Printf("  %s %s\n", mark, step.Text)
+	}
+	return nil
+}
+
+// defaultOption returns value, or fallback when value is empty
+func defaultOption(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// userProfile returns the user's saved defaults, or an empty profile when
+// there are none or they cannot be read
+func userProfile() onboarding.Profile {
+	configPath, err := userConfigPath()
+	if err != nil {
+		return onboarding.Profile{}
+	}
+	profile, _, err := onboarding.Load(workspaceFS, configPath)
+	if err != nil {
+		return onboarding.Profile{}
+	}
+	return profile
+}
+
+// profilePreset returns the owner and package manager of the user's
+// profile as a preset, the layer every team preset overrides
+func profilePreset() *presets.Preset {
+	profile := userProfile()
+	preset := &presets.Preset{Owner: profile.Owner}
+	if profile.PackageManager != "" {
+		preset.Parameters = map[string]interface{}{"PackageManager": profile.PackageManager}
+	}
+	if preset.Owner != "" || preset.Parameters != nil {
+		if configPath, err := userConfigPath(); err == nil {
+			preset.Sources = []string{configPath}
+		}
+	}
+	return preset
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/infrastate"
+	"github.com/jashkahar/open-workbench-platform/internal/ports"
+	"github.com/jashkahar/open-workbench-platform/internal/smoke"
+	"github.com/spf13/cobra"
+)
+
+// smokeRunner runs terraform output to find a deployed load balancer. Tests
+// replace it.
+var smokeRunner deps.Runner = deps.ExecRunner
+
+// smokeClient returns the HTTP client of the checks. Tests replace it to
+// check services without a network.
+var smokeClient = func(timeout time.Duration) smoke.Doer {
+	return &http.Client{Timeout: timeout}
+}
+
+var smokeCmd = &cobra.Command{
+	Use:   "smoke",
+	Short: "Check that every service answers its health check",
+	Long: `Check that every HTTP service answers its health check.
+
+Each service is sent a GET request at its health path and must answer with
+its expected status code. Both are set by the service's health entry in
+workbench.yaml and default to / and 200:
+
+  services:
+    api:
+      port: 8000
+      health:
+        path: /health
+        status: 200
+
+Without --env the services are checked locally, through the ports
+'om compose' publishes on localhost. With --env the services deployed to
+that environment are checked through its load balancer, whose address is
+given with --url, recorded by 'om infra pull', or else read from the
+alb_dns_name output of the generated Terraform in terraform/. Services without a port, and gRPC and TCP services, are
+listed as skipped.
+
+The results are printed as a pass/fail matrix, and the command fails when any
+check fails, so it can gate CI after the stack is started or deployed.
+
+Examples:
+  # Check the services started by docker compose
+  om smoke
+
+  # Check the services deployed to dev
+  om smoke --env dev
+
+  # Check an environment served from a custom domain
+  om smoke --env staging --url https://staging.example.com`,
+	Args: cobra.NoArgs,
+	RunE: runSmoke,
+}
+
+// initSmokeCommand registers the smoke command
+func initSmokeCommand() {
+	if rootCmd != nil {
+		rootCmd.AddCommand(smokeCmd)
+	}
+
+	smokeCmd.Flags().String("env", "", "Check the services deployed to this environment instead of the local stack")
+	smokeCmd.Flags().String("url", "", "Base URL of the environment (default: the load balancer from terraform output)")
+	smokeCmd.Flags().Duration("timeout", 5*time.Second, "How long to wait for each service to answer")
+}
+
+// runSmoke checks the health endpoint of every service
+func runSmoke(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+	env, err := cmd.Flags().GetString("env")
+	if err != nil {
+		return fmt.Errorf("failed to get env flag: %w", err)
+	}
+	baseURL, err := cmd.Flags().GetString("url")
+	if err != nil {
+		return fmt.Errorf("failed to get url flag: %w", err)
+	}
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil {
+		return fmt.Errorf("failed to get timeout flag: %w", err)
+	}
+	if baseURL != "" && env == "" {
+		return newValidationError("--url needs --env: local checks go through the ports on localhost")
+	}
+
+	var targets []smoke.Target
+	if env == "" {
+		if err := confirmCurrentOutputs(projectRoot, manifest, "run 'om compose --target docker' to regenerate it", "docker-compose.yml"); err != nil {
+			return err
+		}
+		lock, err := ports.Load(workspaceFS, projectRoot)
+		if err != nil {
+			return err
+		}
+		targets = smoke.LocalTargets(manifest, lock)
+		fmt.Println("🔥 Smoke testing the local stack")
+	} else {
+		if _, exists := manifest.Environments[env]; !exists {
+			return newNotFoundError("environment '%s' is not defined in workbench.yaml", env)
+		}
+		if err := confirmCurrentOutputs(projectRoot, manifest, terraformRemedy, "terraform/main.tf"); err != nil {
+			return err
+		}
+		deployed, err := manifest.ForEnvironment(env)
+		if err != nil {
+			return err
+		}
+		if baseURL == "" {
+			if baseURL, err = environmentURL(projectRoot, env); err != nil {
+				return err
+			}
+		}
+		targets = smoke.RemoteTargets(deployed, env, baseURL)
+		fmt.Printf("🔥 Smoke testing environment '%s' at %s\n", env, baseURL)
+	}
+	if len(targets) == 0 {
+		fmt.Println("✨ No services to check")
+		return nil
+	}
+
+	results := smoke.Check(smokeClient(timeout), targets)
+	printSmokeResults(results)
+
+	if failed := smoke.Failed(results); failed > 0 {
+		return fmt.Errorf("%d of %d service(s) failed their health check", failed, len(results))
+	}
+	fmt.Println("\n✅ Every checked service is healthy")
+	return nil
+}
+
+// environmentURL returns the load balancer of a deployed environment: the
+// one recorded by 'om infra pull', or else the one terraform output reports
+func environmentURL(projectRoot, env string) (string, error) {
+	state, err := infrastate.Load(workspaceFS, projectRoot, env)
+	if err != nil && !errors.Is(err, infrastate.ErrNoState) {
+		return "", err
+	}
+	if state != nil && state.Outputs[smoke.LoadBalancerOutput] != "" {
+		return smoke.BaseURL(state.Outputs[smoke.LoadBalancerOutput]), nil
+	}
+	baseURL, err := smoke.LoadBalancerURL(smokeRunner, filepath.Join(projectRoot, "terraform"))
+	if err != nil {
+		return "", &exitCodeError{code: ExitCodeExternalTool, err: err}
+	}
+	return baseURL, nil
+}
+
+// printSmokeResults prints one row per service: what was expected, what
+// came back, and whether it passed
+func printSmokeResults(results []smoke.Result) {
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "  SERVICE\tURL\tEXPECTED\tGOT\tRESULT")
+	for _, result := range results {
+		url, got, outcome := result.URL, "-", "✅ pass"
+		switch {
+		case result.Skip != "":
+			url, outcome = "-", "⏭️  skipped: "+result.Skip
+		case result.Err != nil:
+			outcome = "❌ fail: " + result.Err.Error()
+		case !result.Passed():
+			got, outcome = fmt.Sprint(result.Status), "❌ fail"
+		default:
+			got = fmt.Sprint(result.Status)
+		}
+		fmt.Fprintf(table, "  %s\t%s\t%d\t%s\t%s\n", result.Service, url, result.Expect, got, outcome)
+	}
+	table.Flush()
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+	"github.com/jashkahar/open-workbench-platform/internal/revision"
+)
+
+// manifestRevision returns the revision of the project's workbench.yaml and
+// the files it includes, which generators record in the files they write
+func manifestRevision(projectRoot string, manifest *manifestPkg.WorkbenchManifest) (string, error) {
+	workbenchPath := filepath.Join(projectRoot, "workbench.yaml")
+	included, err := manifestPkg.IncludedFiles(workspaceFS, workbenchPath, manifest)
+	if err != nil {
+		return "", err
+	}
+	return revision.Compute(workspaceFS, projectRoot, append([]string{workbenchPath}, included...))
+}
+
+// terraformRemedy says how to bring the Terraform configuration up to date
+const terraformRemedy = "regenerate the Terraform configuration and apply it again"
+
+// confirmCurrentOutputs warns when generated files a command is about to use
+// were generated from another revision of workbench.yaml, such as before a
+// git pull, and asks whether to go on with them. Declining fails with
+// remedy, which says how to regenerate them.
+func confirmCurrentOutputs(projectRoot string, manifest *manifestPkg.WorkbenchManifest, remedy string, files ...string) error {
+	current, err := manifestRevision(projectRoot, manifest)
+	if err != nil {
+		return err
+	}
+	stale := revision.Check(workspaceFS, projectRoot, files, current)
+	if len(stale) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(stale))
+	fmt.Printf("⚠️  Generated from a different revision of workbench.yaml (now %s):\n", current)
+	for _, file := range stale {
+		fmt.Printf("   %s (revision %s)\n", file.File, file.Revision)
+		names = append(names, file.File)
+	}
+	proceed, err := prompter.Confirm(prompt.Question{
+		Name:    "useStaleOutputs",
+		Message: "Continue with the out-of-date configuration?",
+		Help:    fmt.Sprintf("workbench.yaml changed since %s was generated, for example in a git pull, so it may not match the manifest. To fix it, %s.", strings.Join(names, ", "), remedy),
+		Default: false,
+	})
+	if err != nil {
+		return err
+	}
+	if !proceed {
+		return newValidationError("%s is out of date with workbench.yaml; %s", strings.Join(names, ", "), remedy)
+	}
+	return nil
+}
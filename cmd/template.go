@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/help"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+	"github.com/spf13/cobra"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Inspect the available templates",
+}
+
+var templateDescribeCmd = &cobra.Command{
+	Use:   "describe <template>",
+	Short: "Show a template's parameters and their help",
+	Long: `Show everything about a template: its description, status, documentation
+link, and every parameter with its prompt, help, options, default, and the
+condition under which it is asked.
+
+Parameter help is written in markdown and rendered for the terminal, with
+links and code blocks kept readable.
+
+Examples:
+  om template describe go-api`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTemplateNames,
+	RunE:              runTemplateDescribe,
+}
+
+var templateTestAllCmd = &cobra.Command{
+	Use:   "test-all",
+	Short: "Render every embedded template across its parameter matrix",
+	Long: `Render every embedded template with each of its parameter combinations and
+check that the output parses.
+
+Each manifest is validated first, including the syntax of its conditions.
+Each template is then rendered with its defaults, then once per flipped boolean
+parameter and once per select option. Rendered package.json and other JSON
+files, YAML files, Go sources, and Dockerfiles are checked. Post-scaffold
+commands are not run and nothing is written to disk.
+
+Examples:
+  # Test the whole catalog before a release
+  om template test-all
+
+  # Test a single template
+  om template test-all --template go-api
+
+The command exits with a non-zero status when any combination fails.`,
+	Args:   cobra.NoArgs,
+	RunE:   runTemplateTestAll,
+	Hidden: true,
+}
+
+// initTemplateCommand registers the template command and its subcommands
+func initTemplateCommand() {
+	templateCmd.AddCommand(templateDescribeCmd)
+	templateCmd.AddCommand(templateTestAllCmd)
+	if rootCmd != nil {
+		rootCmd.AddCommand(templateCmd)
+	}
+
+	templateTestAllCmd.Flags().StringSlice("template", nil, "Only test these templates")
+}
+
+// runTemplateDescribe prints a template and its parameters
+func runTemplateDescribe(cmd *cobra.Command, args []string) error {
+	info, err := templating.NewTemplateCatalog(templatesFS).Info(args[0])
+	if err != nil {
+		return err
+	}
+	manifest := info.Manifest
+
+	fmt.Printf("📦 %s — %s\n", info.Name, manifest.Description)
+	if notice := manifest.DeprecationNotice(); notice != "" {
+		fmt.Printf("   ⚠️  Status: %s\n", notice)
+	}
+	if manifest.MinimumCLIVersion != "" {
+		fmt.Printf("   Requires: om %s or newer\n", manifest.MinimumCLIVersion)
+	}
+	if manifest.DocsURL != "" {
+		fmt.Printf("   📖 Docs: %s\n", manifest.DocsURL)
+	}
+
+	fmt.Println()
+	fmt.Println("Parameters:")
+	for _, param := range manifest.Parameters {
+		var traits []string
+		traits = append(traits, param.Type)
+		if param.Required {
+			traits = append(traits, "required")
+		}
+		if param.IsAdvanced() {
+			traits = append(traits, "advanced")
+		}
+		fmt.Printf("\n  %s (%s)\n", param.Name, strings.Join(traits, ", "))
+		fmt.Printf("    %s\n", param.Prompt)
+		if param.HelpText != "" {
+			fmt.Print(renderParameterHelp(param.HelpText, "    "))
+		}
+		if len(param.Options) > 0 {
+			fmt.Printf("    Options: %s\n", strings.Join(param.Options, ", "))
+		}
+		if param.Default != nil {
+			fmt.Printf("    Default: %v\n", param.Default)
+		}
+		if param.Condition != "" {
+			fmt.Printf("    Asked when: %s\n", param.Condition)
+		}
+		if param.Group != "" {
+			fmt.Printf("    Group: %s\n", param.Group)
+		}
+	}
+	return nil
+}
+
+// renderParameterHelp renders the markdown help of a parameter for the
+// terminal, each line indented by indent
+func renderParameterHelp(markdown, indent string) string {
+	var out strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(help.Render(markdown, useColor()), "\n"), "\n") {
+		if line == "" {
+			out.WriteString("\n")
+			continue
+		}
+		out.WriteString(indent + line + "\n")
+	}
+	return out.String()
+}
+
+// runTemplateTestAll renders the parameter matrix of every selected template
+// and prints a summary
+func runTemplateTestAll(cmd *cobra.Command, args []string) error {
+	templates, err := templating.DiscoverTemplates(templatesFS)
+	if err != nil {
+		return fmt.Errorf("could not discover templates: %w", err)
+	}
+
+	only, err := cmd.Flags().GetStringSlice("template")
+	if err != nil {
+		return fmt.Errorf("failed to get template flag: %w", err)
+	}
+	if len(only) > 0 {
+		selected := make(map[string]bool, len(only))
+		for _, name := range only {
+			selected[name] = true
+		}
+		var filtered []templating.TemplateInfo
+		for _, template := range templates {
+			if selected[template.Name] {
+				filtered = append(filtered, template)
+				delete(selected, template.Name)
+			}
+		}
+		for name := range selected {
+			return newNotFoundError("template '%s' not found", name)
+		}
+		templates = filtered
+	}
+
+	fmt.Printf("🧪 Testing %d template(s)...\n", len(templates))
+	fmt.Println()
+
+	var totalCases, failedCases, failedTemplates int
+	for _, template := range templates {
+		cases, failed := testTemplateMatrix(templatesFS, template)
+		totalCases += cases
+		failedCases += failed
+		if failed > 0 {
+			failedTemplates++
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("📊 %d/%d templates passed, %d/%d combinations passed\n",
+		len(templates)-failedTemplates, len(templates), totalCases-failedCases, totalCases)
+	if failedCases > 0 {
+		return newValidationError("%d template combination(s) failed", failedCases)
+	}
+	return nil
+}
+
+// testTemplateMatrix renders every parameter combination of a template,
+// prints a result line with the failures, and returns the number of
+// combinations and how many of them failed
+func testTemplateMatrix(templateFS fs.FS, template templating.TemplateInfo) (int, int) {
+	// A manifest with invalid settings, such as a condition that does not
+	// parse, fails as a whole before anything is rendered
+	if err := templating.ValidateTemplate(templateFS, template.Name); err != nil {
+		fmt.Printf("  ❌ %-20s invalid template.json\n", template.Name)
+		fmt.Printf("       • %s\n", manifestProblem(err))
+		return 1, 1
+	}
+
+	servicePath := filepath.Join("sample-project", templating.SampleStringValue)
+	seed := projectParameterDefaults(servicePath, "", "")
+	matrix := templating.ParameterMatrix(template.Manifest, seed)
+
+	var failures []string
+	failed := 0
+	for _, matrixCase := range matrix {
+		problems := testTemplateCase(templateFS, template.Name, matrixCase, servicePath)
+		if len(problems) > 0 {
+			failed++
+		}
+		for _, problem := range problems {
+			failures = append(failures, fmt.Sprintf("%s: %v", matrixCase.Name, problem))
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("  ❌ %-20s %d/%d combinations passed\n", template.Name, len(matrix)-failed, len(matrix))
+		for _, failure := range failures {
+			fmt.Printf("       • %s\n", failure)
+		}
+	} else {
+		fmt.Printf("  ✅ %-20s %d/%d combinations passed\n", template.Name, len(matrix), len(matrix))
+	}
+	return len(matrix), failed
+}
+
+// testTemplateCase renders one parameter combination of a template and
+// returns what is wrong with the output
+func testTemplateCase(templateFS fs.FS, templateName string, matrixCase templating.MatrixCase, servicePath string) []error {
+	output, err := templating.RenderTemplate(templateFS, templateName, matrixCase.Values, servicePath)
+	if err != nil {
+		return []error{err}
+	}
+	return templating.CheckRenderedFiles(output, servicePath)
+}
+
+// manifestProblem describes what ValidateTemplate found wrong with a
+// manifest in one line
+func manifestProblem(err error) string {
+	var templateErr *templating.TemplateError
+	if !errors.As(err, &templateErr) || templateErr.Details == "" {
+		return err.Error()
+	}
+	if templateErr.OriginalErr != nil {
+		return fmt.Sprintf("%s: %v", templateErr.Details, templateErr.OriginalErr)
+	}
+	return templateErr.Details
+}
+
+// completeTemplateNames completes the template argument of om template describe
+func completeTemplateNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	templates, err := templating.DiscoverTemplates(templatesFS)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, template := range templates {
+		names = append(names, template.Name+"\t"+template.Description)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check workbench.yaml and the files it references",
+	Long: `Check that workbench.yaml is valid and that the files it points at exist.
+
+This command loads the manifest, including any included files, and reports
+structural problems such as missing resource types or unknown group members.
+It then checks that every container service has a Dockerfile in its path or
+runs a prebuilt image, and that every API spec referenced by a service's
+api.spec field exists and parses as an OpenAPI document.
+
+Likely mistakes that do not make the manifest invalid, such as resources
+without a pinned version, Dockerfiles without a HEALTHCHECK, or services
+that depend on a service their environment excludes, are reported as
+warnings after the result.
+
+Examples:
+  # Validate the project in the current directory
+  om validate
+
+  # Fail on warnings too, e.g. in CI
+  om validate --strict
+
+The command exits with a non-zero status when any problem is found, so it can
+be used in CI.`,
+	Args: cobra.NoArgs,
+	RunE: runValidate,
+}
+
+// initValidateCommand registers the validate command with the root command
+func initValidateCommand() {
+	if rootCmd != nil {
+		rootCmd.AddCommand(validateCmd)
+	}
+}
+
+// runValidate loads the manifest and checks the files it references
+func runValidate(cmd *cobra.Command, args []string) error {
+	projectRoot, manifest, err := findProjectRootAndLoadManifest()
+	if err != nil {
+		return err
+	}
+
+	problems := manifest.ValidateBuildSources(workspaceFS, projectRoot)
+	problems = append(problems, manifest.ValidateAPISpecs(workspaceFS, projectRoot)...)
+	found := warnings.Sort(append(manifest.Lint(workspaceFS, projectRoot), manifest.EnvironmentWarnings()...))
+	if len(problems) > 0 {
+		fmt.Println("❌ workbench.yaml references invalid files:")
+		for _, problem := range problems {
+			fmt.Printf("  • %s\n", problem)
+		}
+		_ = reportWarnings(cmd, found)
+		return newValidationError("found %d problem(s) in %s", len(problems), manifest.Metadata.Name)
+	}
+
+	fmt.Printf("✅ Project '%s' is valid (%d services, %d components)\n",
+		manifest.Metadata.Name, len(manifest.Services), len(manifest.Components))
+	return reportWarnings(cmd, found)
+}
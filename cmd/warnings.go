@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+	"github.com/spf13/cobra"
+)
+
+// reportWarnings prints the warnings of a command in a final summary block.
+// With --strict, any warning fails the command.
+func reportWarnings(cmd *cobra.Command, list []warnings.Warning) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("⚠️  %d warning(s):\n", len(list))
+	for _, warning := range list {
+		fmt.Printf("  • %s\n", warning)
+	}
+
+	if strict, _ := cmd.Flags().GetBool("strict"); strict {
+		return newValidationError("%d warning(s) treated as errors because of --strict", len(list))
+	}
+	return nil
+}
@@ -0,0 +1,436 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/labels"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+	"github.com/jashkahar/open-workbench-platform/internal/textdiff"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Regenerate deployment configuration whenever workbench.yaml changes",
+	Long: `Watch workbench.yaml and regenerate the deployment configuration on every change.
+
+The command validates the project and runs the generator of the selected
+target once at start-up, then again whenever workbench.yaml, a file it
+includes, or a file in workbench.d/ is saved. Each run prints a diff of the
+generated files against the previous output before writing them, so edits to
+the manifest get immediate feedback. Validation problems are printed and the
+previous output is kept until the manifest is fixed.
+
+With --templates, the templates of a local catalog directory are watched as
+well, and a template's parameter combinations are rendered and checked again,
+like om template test, whenever one of its files changes.
+
+Press Ctrl+C to stop watching.
+
+Examples:
+  # Keep docker-compose.yml in sync with workbench.yaml
+  om watch
+
+  # Also re-test templates while developing them
+  om watch --templates ./my-catalog`,
+	Args: cobra.NoArgs,
+	RunE: runWatch,
+}
+
+// initWatchCommand registers the watch command with the root command
+func initWatchCommand() {
+	if rootCmd != nil {
+		rootCmd.AddCommand(watchCmd)
+	}
+
+	watchCmd.Flags().String("target", "docker", "Deployment target to regenerate (docker)")
+	watchCmd.Flags().String("templates", "", "Catalog directory whose templates/ are re-tested on change")
+}
+
+// fileWatcher reports changes to the files of watched directories
+type fileWatcher interface {
+	Add(dir string) error
+	Events() <-chan string
+	Errors() <-chan error
+	Close() error
+}
+
+// newFileWatcher creates the watcher used by om watch. Tests replace it.
+var newFileWatcher = func() (fileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	return newNotifyWatcher(watcher), nil
+}
+
+// watchDebounce is how long om watch waits for more changes before
+// regenerating, since editors often save a file in several steps
+var watchDebounce = 200 * time.Millisecond
+
+// notifyWatcher adapts an fsnotify watcher to fileWatcher
+type notifyWatcher struct {
+	watcher *fsnotify.Watcher
+	events  chan string
+}
+
+// newNotifyWatcher forwards the paths of content changes from an fsnotify
+// watcher, dropping permission-only changes
+func newNotifyWatcher(watcher *fsnotify.Watcher) *notifyWatcher {
+	w := &notifyWatcher{watcher: watcher, events: make(chan string)}
+	go func() {
+		defer close(w.events)
+		for event := range watcher.Events {
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			w.events <- event.Name
+		}
+	}()
+	return w
+}
+
+func (w *notifyWatcher) Add(dir string) error  { return w.watcher.Add(dir) }
+func (w *notifyWatcher) Events() <-chan string { return w.events }
+func (w *notifyWatcher) Errors() <-chan error  { return w.watcher.Errors }
+func (w *notifyWatcher) Close() error          { return w.watcher.Close() }
+
+// projectWatch tracks what om watch regenerates and which files trigger it
+type projectWatch struct {
+	cmd         *cobra.Command
+	projectRoot string
+	target      string
+	watcher     fileWatcher
+	watchedDirs map[string]bool
+	inputs      map[string]bool // manifest files that trigger regeneration
+	templateDir string          // catalog directory containing templates/, if any
+}
+
+// runWatch regenerates the project's configuration on every manifest change
+func runWatch(cmd *cobra.Command, args []string) error {
+	projectDir, err := workingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	projectRoot, err := findWorkbenchYaml(projectDir)
+	if err != nil {
+		return manifestPkg.NewNotFoundError("current directory or its parents", fs.ErrNotExist)
+	}
+
+	target, _ := cmd.Flags().GetString("target")
+	templateDir, _ := cmd.Flags().GetString("templates")
+	if templateDir != "" {
+		if _, err := fs.Stat(os.DirFS(templateDir), "templates"); err != nil {
+			return newNotFoundError("templates directory not found in %s", templateDir)
+		}
+	}
+
+	watcher, err := newFileWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	w := &projectWatch{
+		cmd:         cmd,
+		projectRoot: projectRoot,
+		target:      target,
+		watcher:     watcher,
+		watchedDirs: make(map[string]bool),
+		inputs:      make(map[string]bool),
+		templateDir: templateDir,
+	}
+	if err := w.watchTemplates(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("👀 Watching %s (press Ctrl+C to stop)\n", filepath.Join(projectRoot, "workbench.yaml"))
+	if err := w.regenerate(); err != nil {
+		return err
+	}
+	return w.loop(ctx)
+}
+
+// loop waits for changes and handles them once no more arrive within
+// watchDebounce. It returns when the context is cancelled or the watcher
+// stops.
+func (w *projectWatch) loop(ctx context.Context) error {
+	pending := make(map[string]bool)
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println("\n👋 Stopped watching")
+			return nil
+		case err, ok := <-w.watcher.Errors():
+			if ok {
+				fmt.Printf("⚠️  File watcher error: %v\n", err)
+			}
+		case path, ok := <-w.watcher.Events():
+			if !ok {
+				if len(pending) > 0 {
+					if err := w.handle(pending); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			pending[filepath.Clean(path)] = true
+			timer = time.After(watchDebounce)
+		case <-timer:
+			timer = nil
+			if err := w.handle(pending); err != nil {
+				return err
+			}
+			pending = make(map[string]bool)
+		}
+	}
+}
+
+// handle re-tests the templates and regenerates the project for a batch of
+// changed paths
+func (w *projectWatch) handle(changed map[string]bool) error {
+	regenerate := false
+	templates := make(map[string]bool)
+	for path := range changed {
+		if name := w.templateOf(path); name != "" {
+			templates[name] = true
+			continue
+		}
+		if w.isInput(path) {
+			regenerate = true
+		}
+	}
+
+	for name := range templates {
+		w.retestTemplate(name)
+	}
+	if regenerate {
+		return w.regenerate()
+	}
+	return nil
+}
+
+// isInput reports whether a changed path is part of the manifest
+func (w *projectWatch) isInput(path string) bool {
+	if w.inputs[path] {
+		return true
+	}
+	if filepath.Dir(path) == filepath.Join(w.projectRoot, manifestPkg.IncludeDir) {
+		ext := filepath.Ext(path)
+		return ext == ".yaml" || ext == ".yml"
+	}
+	return false
+}
+
+// regenerate validates the manifest and runs the generator, printing a diff
+// of every file it changes. Problems with the manifest are printed rather
+// than returned so that watching continues until they are fixed.
+func (w *projectWatch) regenerate() error {
+	fmt.Printf("\n🔄 [%s] Regenerating %s configuration...\n", time.Now().Format("15:04:05"), w.target)
+
+	workbenchPath := filepath.Join(w.projectRoot, "workbench.yaml")
+	w.watchInput(workbenchPath)
+	w.watchDir(filepath.Join(w.projectRoot, manifestPkg.IncludeDir))
+
+	manifest, err := loadManifest(workbenchPath)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return nil
+	}
+	if files, err := manifestPkg.IncludedFiles(workspaceFS, workbenchPath, manifest); err == nil {
+		for _, file := range files {
+			w.watchInput(file)
+		}
+	}
+
+	problems := manifest.ValidateBuildSources(workspaceFS, w.projectRoot)
+	problems = append(problems, manifest.ValidateAPISpecs(workspaceFS, w.projectRoot)...)
+	if len(problems) > 0 {
+		fmt.Println("❌ workbench.yaml references invalid files:")
+		for _, problem := range problems {
+			fmt.Printf("  • %s\n", problem)
+		}
+		return nil
+	}
+
+	// Generate into a dry run first, so the changes can be shown as diffs
+	// against the files on disk before they are written
+	dryRun := filesystem.NewDryRunFS(workspaceFS)
+	secrets, err := newSecretsProvider(dryRun, w.projectRoot, manifest)
+	if err != nil {
+		return err
+	}
+	gen, err := newComposeGenerator(w.target, dryRun, w.projectRoot, labels.Info{Project: manifest.Metadata.Name, Version: Version}, secrets)
+	if err != nil {
+		return err
+	}
+	if err := gen.Generate(manifest); err != nil {
+		fmt.Printf("❌ Failed to generate %s configuration: %v\n", w.target, err)
+		return nil
+	}
+
+	changed, err := applyDryRun(dryRun, w.projectRoot)
+	if err != nil {
+		return err
+	}
+	if changed == 0 {
+		fmt.Println("✅ Generated files are up to date")
+	} else {
+		fmt.Printf("✅ Updated %d file(s)\n", changed)
+	}
+
+	if source, ok := gen.(warnings.Source); ok {
+		// Warnings never stop watching, even under --strict
+		_ = reportWarnings(w.cmd, source.Warnings())
+	}
+	return nil
+}
+
+// applyDryRun writes the changes recorded by a dry run to the workspace,
+// printing a diff for every file whose content changes, and returns the
+// number of changed files
+func applyDryRun(dryRun *filesystem.DryRunFS, projectRoot string) (int, error) {
+	changed := 0
+	for _, op := range dryRun.Operations() {
+		switch op.Type {
+		case filesystem.OpMkdir:
+			if err := workspaceFS.MkdirAll(op.Path, 0755); err != nil {
+				return changed, fmt.Errorf("failed to create %s: %w", op.Path, err)
+			}
+		case filesystem.OpRemove:
+			if err := workspaceFS.RemoveAll(op.Path); err != nil {
+				return changed, fmt.Errorf("failed to remove %s: %w", op.Path, err)
+			}
+		case filesystem.OpWrite:
+			newData, err := dryRun.ReadFile(op.Path)
+			if err != nil {
+				continue // written and removed again within the run
+			}
+			oldData, err := workspaceFS.ReadFile(op.Path)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return changed, fmt.Errorf("failed to read %s: %w", op.Path, err)
+			}
+			if string(oldData) == string(newData) {
+				continue
+			}
+
+			name := relativePath(projectRoot, op.Path)
+			diff := textdiff.Unified("a/"+name, "b/"+name, string(oldData), string(newData))
+			fmt.Print(diff)
+			if !strings.HasSuffix(diff, "\n") {
+				fmt.Println()
+			}
+
+			if err := workspaceFS.MkdirAll(filepath.Dir(op.Path), 0755); err != nil {
+				return changed, fmt.Errorf("failed to create %s: %w", filepath.Dir(op.Path), err)
+			}
+			if err := workspaceFS.WriteFile(op.Path, newData, 0644); err != nil {
+				return changed, fmt.Errorf("failed to write %s: %w", op.Path, err)
+			}
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// relativePath returns path relative to root for display, or path itself
+// when it is outside root
+func relativePath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// watchInput marks a manifest file as triggering regeneration and watches
+// its directory
+func (w *projectWatch) watchInput(path string) {
+	w.inputs[filepath.Clean(path)] = true
+	w.watchDir(filepath.Dir(path))
+}
+
+// watchDir adds a directory to the watcher once. Directories that do not
+// exist yet, like an empty workbench.d, are skipped.
+func (w *projectWatch) watchDir(dir string) {
+	dir = filepath.Clean(dir)
+	if w.watchedDirs[dir] {
+		return
+	}
+	if info, err := workspaceFS.Stat(dir); err != nil || !info.IsDir() {
+		return
+	}
+	if err := w.watcher.Add(dir); err != nil {
+		fmt.Printf("⚠️  Cannot watch %s: %v\n", dir, err)
+		return
+	}
+	w.watchedDirs[dir] = true
+}
+
+// watchTemplates watches every directory of the templates in the catalog
+// directory given with --templates
+func (w *projectWatch) watchTemplates() error {
+	if w.templateDir == "" {
+		return nil
+	}
+	root := filepath.Join(w.templateDir, "templates")
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if err := w.watcher.Add(path); err != nil {
+				return fmt.Errorf("failed to watch %s: %w", path, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch templates in %s: %w", w.templateDir, err)
+	}
+	fmt.Printf("👀 Watching templates in %s\n", root)
+	return nil
+}
+
+// templateOf returns the name of the watched template a changed path
+// belongs to, or "" when it is not in a template
+func (w *projectWatch) templateOf(path string) string {
+	if w.templateDir == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(filepath.Join(w.templateDir, "templates"), path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return strings.Split(filepath.ToSlash(rel), "/")[0]
+}
+
+// retestTemplate renders and checks every parameter combination of a
+// changed template
+func (w *projectWatch) retestTemplate(name string) {
+	fmt.Printf("\n🧪 [%s] Testing template %s...\n", time.Now().Format("15:04:05"), name)
+
+	templateFS := os.DirFS(w.templateDir)
+	info, err := templating.NewTemplateCatalog(templateFS).Info(name)
+	if err != nil {
+		fmt.Printf("  ❌ %-20s %v\n", name, err)
+		return
+	}
+	testTemplateMatrix(templateFS, *info)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+)
+
+// writeProbeName is the file written, and removed again, to check that a
+// directory is writable before anything is scaffolded into it
+const writeProbeName = ".om-write-probe"
+
+// fallbackProjectsDir returns the user-writable directory om init offers
+// when the current directory is read-only. Tests replace it.
+var fallbackProjectsDir = func() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "om-projects"), nil
+}
+
+// checkWritable writes and removes a probe file in dir, so that a read-only
+// file system or a directory owned by another user is reported up front
+// instead of halfway through scaffolding
+func checkWritable(dir string) error {
+	probe := filepath.Join(dir, writeProbeName)
+	if err := workspaceFS.WriteFile(probe, nil, 0644); err != nil {
+		return templating.NewPermissionError("write to directory", displayPath(dir), err)
+	}
+	if err := workspaceFS.Remove(probe); err != nil {
+		return templating.NewPermissionError("remove files in directory", displayPath(dir), err)
+	}
+	return nil
+}
+
+// displayPath returns the absolute form of a workspace path for messages
+func displayPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	dir, err := workingDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// chooseInitDir returns the directory om init creates the project in: ""
+// for the current directory, or a user-writable alternative the user
+// accepted when the current directory cannot be written. With assumeYes the
+// alternative is used without asking.
+func chooseInitDir(assumeYes bool) (string, error) {
+	probeErr := checkWritable(".")
+	if probeErr == nil {
+		return "", nil
+	}
+
+	fallback, err := fallbackProjectsDir()
+	if err != nil {
+		return "", probeErr
+	}
+	if err := workspaceFS.MkdirAll(fallback, 0755); err != nil {
+		return "", probeErr
+	}
+	if err := checkWritable(fallback); err != nil {
+		return "", probeErr
+	}
+
+	fmt.Printf("⚠️  %s is not writable.\n", displayPath("."))
+	if assumeYes {
+		fmt.Printf("📁 Creating the project in %s instead\n", fallback)
+		return fallback, nil
+	}
+	useFallback, err := prompter.Confirm(prompt.Question{
+		Name:    "useWritableDir",
+		Message: fmt.Sprintf("Create the project in %s instead?", fallback),
+		Help:    "Nothing has been written yet. Answer no to stop and fix the permissions of the current directory.",
+		Default: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get directory choice: %w", err)
+	}
+	if !useFallback {
+		return "", probeErr
+	}
+	return fallback, nil
+}
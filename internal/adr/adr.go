@@ -0,0 +1,111 @@
+// Package adr keeps a project's architecture decision records: short,
+// numbered markdown files under docs/adr/ that record why the project is
+// shaped the way it is, next to the workbench.yaml that describes it.
+package adr
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/slug"
+)
+
+// Dir is the location of the records relative to the project root
+var Dir = filepath.Join("docs", "adr")
+
+// recordPattern matches record file names such as 0007-use-postgres.md
+var recordPattern = regexp.MustCompile(`^(\d{4})-.*\.md$`)
+
+// Record describes a new decision record
+type Record struct {
+	Title   string
+	Context string // prefilled context, e.g. the command that prompted the record
+	Date    time.Time
+}
+
+// Enabled reports whether the project at projectRoot keeps decision records.
+// Commands only offer to record decisions in projects that do.
+func Enabled(fsys filesystem.FS, projectRoot string) bool {
+	return filesystem.Exists(fsys, filepath.Join(projectRoot, Dir))
+}
+
+// Create writes record as the next numbered file in the project's records
+// directory, creating the directory if needed. It returns the path of the
+// new file relative to the project root.
+func Create(fsys filesystem.FS, projectRoot string, record Record) (string, error) {
+	titleSlug := Slug(record.Title)
+	if titleSlug == "" {
+		return "", fmt.Errorf("the title needs at least one letter or digit")
+	}
+
+	dir := filepath.Join(projectRoot, Dir)
+	if err := fsys.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", Dir, err)
+	}
+	number, err := nextNumber(fsys, dir)
+	if err != nil {
+		return "", err
+	}
+
+	name := filepath.Join(Dir, fmt.Sprintf("%04d-%s.md", number, titleSlug))
+	if err := fsys.WriteFile(filepath.Join(projectRoot, name), []byte(render(number, record)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return name, nil
+}
+
+// nextNumber returns one more than the highest record number in dir
+func nextNumber(fsys filesystem.FS, dir string) (int, error) {
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", Dir, err)
+	}
+	highest := 0
+	for _, entry := range entries {
+		match := recordPattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		if number, _ := strconv.Atoi(match[1]); number > highest {
+			highest = number
+		}
+	}
+	return highest + 1, nil
+}
+
+// Slug turns a title into the lowercase, dash-separated part of a file name
+func Slug(title string) string {
+	return slug.Make(title)
+}
+
+// render returns the markdown of a new record, in Michael Nygard's format
+func render(number int, record Record) string {
+	context := record.Context
+	if context == "" {
+		context = "What is the issue that motivates this decision?"
+	}
+	return fmt.Sprintf(`# %d. %s
+
+Date: %s
+
+## Status
+
+Proposed
+
+## Context
+
+%s
+
+## Decision
+
+What is the change that we are proposing or have agreed to?
+
+## Consequences
+
+What becomes easier or harder because of this change?
+`, number, record.Title, record.Date.Format("2006-01-02"), context)
+}
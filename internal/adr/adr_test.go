@@ -0,0 +1,72 @@
+package adr
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"Use PostgreSQL for orders":  "use-postgresql-for-orders",
+		"  Switch to ECS (Fargate)!": "switch-to-ecs-fargate",
+		"gRPC/HTTP gateway":          "grpc-http-gateway",
+		"???":                        "",
+	}
+	for title, want := range tests {
+		if got := Slug(title); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestCreate(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	if Enabled(fsys, "demo") {
+		t.Error("expected a project without docs/adr not to keep records")
+	}
+
+	date := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	first, err := Create(fsys, "demo", Record{Title: "Use PostgreSQL", Date: date})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if first != filepath.Join("docs", "adr", "0001-use-postgresql.md") {
+		t.Errorf("unexpected path %s", first)
+	}
+	if !Enabled(fsys, "demo") {
+		t.Error("expected the records directory to be created")
+	}
+
+	// Numbers continue from the highest existing record, skipping other files
+	if err := fsys.WriteFile(filepath.Join("demo", Dir, "0007-older.md"), []byte("# 7. Older\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(filepath.Join("demo", Dir, "README.md"), []byte("# Decisions\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	second, err := Create(fsys, "demo", Record{Title: "Add gateway component", Context: "Added the 'gateway' component.", Date: date})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if second != filepath.Join("docs", "adr", "0008-add-gateway-component.md") {
+		t.Errorf("unexpected path %s", second)
+	}
+
+	content, err := fsys.ReadFile(filepath.Join("demo", second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# 8. Add gateway component\n", "Date: 2026-03-14", "## Status\n\nProposed", "## Context\n\nAdded the 'gateway' component."} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("expected %q in:\n%s", want, content)
+		}
+	}
+
+	if _, err := Create(fsys, "demo", Record{Title: "!!!", Date: date}); err == nil {
+		t.Error("expected a title without letters or digits to be rejected")
+	}
+}
@@ -0,0 +1,371 @@
+// Package bundle packs the runtime of a project into one tarball that runs
+// on a machine without internet or registry access: the generated
+// docker-compose.yml, rewritten to run images instead of building them, the
+// images it runs as saved by docker save, the .env template, and the files
+// the containers mount. Importing the bundle unpacks it and loads the
+// images, after which docker compose up starts the project as usual.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/revision"
+	"gopkg.in/yaml.v3"
+)
+
+// Names of the files of a bundle
+const (
+	ManifestFile = "bundle.json"
+	ComposeFile  = "docker-compose.yml"
+	EnvTemplate  = ".env.example"
+	ImagesFile   = "images.tar"
+)
+
+// Manifest describes a bundle. It is the first file of the tarball.
+type Manifest struct {
+	Project   string    `json:"project"`
+	OMVersion string    `json:"omVersion"`
+	Revision  string    `json:"manifestRevision,omitempty"` // revision of workbench.yaml the compose file was generated from
+	CreatedAt time.Time `json:"createdAt"`
+	Images    []string  `json:"images"`
+}
+
+// Build is a service whose image is built from its source before bundling
+type Build struct {
+	Service string
+	Image   string // tag the image is built with and run by in the bundle
+	Config  compose.BuildConfig
+}
+
+// Bundle is the plan of a bundle: the compose file it runs, the images to
+// build, and the files to pack next to it
+type Bundle struct {
+	Manifest Manifest
+	Compose  *compose.DockerComposeConfig
+	Builds   []Build
+	Mounts   []string // files and directories the containers mount, relative to the project
+}
+
+// Plan reads the docker-compose.yml generated in projectRoot and plans its
+// bundle. Built services are tagged <project>-<service>:<revision> and run
+// by that tag, since their source does not travel with the bundle.
+func Plan(fsys filesystem.FS, projectRoot, project string) (*Bundle, error) {
+	data, err := fsys.ReadFile(filepath.Join(projectRoot, ComposeFile))
+	if err != nil {
+		return nil, err
+	}
+	var config compose.DockerComposeConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ComposeFile, err)
+	}
+	config.Revision = revision.Read(data)
+
+	tag := config.Revision
+	if tag == "" {
+		tag = "latest"
+	}
+	b := &Bundle{Manifest: Manifest{Project: project, Revision: config.Revision}, Compose: &config}
+	images := make(map[string]bool)
+	mounts := make(map[string]bool)
+	for _, name := range slices.Sorted(maps.Keys(config.Services)) {
+		service := config.Services[name]
+		if service.Build != nil {
+			image := fmt.Sprintf("%s-%s:%s", strings.ToLower(project), name, tag)
+			b.Builds = append(b.Builds, Build{Service: name, Image: image, Config: *service.Build})
+			service.Build = nil
+			service.Image = image
+			config.Services[name] = service
+		}
+		if service.Image != "" {
+			images[service.Image] = true
+		}
+		for _, volume := range service.Volumes {
+			source := strings.SplitN(volume, ":", 2)[0]
+			if !strings.HasPrefix(source, ".") {
+				continue
+			}
+			rel := path.Clean(filepath.ToSlash(source))
+			if rel == ".." || strings.HasPrefix(rel, "../") {
+				return nil, fmt.Errorf("service '%s' mounts %s from outside the project, which cannot be bundled", name, source)
+			}
+			mounts[rel] = true
+		}
+	}
+	b.Manifest.Images = slices.Sorted(maps.Keys(images))
+	b.Mounts = slices.Sorted(maps.Keys(mounts))
+	return b, nil
+}
+
+// BuildImages builds the images of the built services from their source in
+// projectRoot
+func (b *Bundle) BuildImages(run deps.Runner, projectRoot string) error {
+	for _, build := range b.Builds {
+		args := []string{"build", "--tag", build.Image}
+		for _, key := range slices.Sorted(maps.Keys(build.Config.Labels)) {
+			args = append(args, "--label", key+"="+build.Config.Labels[key])
+		}
+		for _, name := range slices.Sorted(maps.Keys(build.Config.AdditionalContexts)) {
+			args = append(args, "--build-context", name+"="+build.Config.AdditionalContexts[name])
+		}
+		args = append(args, build.Config.Context)
+		if _, err := run(projectRoot, "docker", args...); err != nil {
+			return fmt.Errorf("failed to build the image of %s: %w", build.Service, err)
+		}
+	}
+	return nil
+}
+
+// PullImages pulls the images the bundle runs that are neither built nor
+// already present locally
+func (b *Bundle) PullImages(run deps.Runner, projectRoot string) error {
+	built := make(map[string]bool, len(b.Builds))
+	for _, build := range b.Builds {
+		built[build.Image] = true
+	}
+	for _, image := range b.Manifest.Images {
+		if built[image] {
+			continue
+		}
+		if _, err := run(projectRoot, "docker", "image", "inspect", "--format", "{{.Id}}", image); err == nil {
+			continue
+		}
+		if _, err := run(projectRoot, "docker", "pull", image); err != nil {
+			return fmt.Errorf("failed to pull %s: %w", image, err)
+		}
+	}
+	return nil
+}
+
+// SaveImages saves every image the bundle runs into one archive at path
+func (b *Bundle) SaveImages(run deps.Runner, projectRoot, path string) error {
+	args := append([]string{"save", "--output", path}, b.Manifest.Images...)
+	if _, err := run(projectRoot, "docker", args...); err != nil {
+		return fmt.Errorf("failed to save the images: %w", err)
+	}
+	return nil
+}
+
+// Write writes the bundle to w as a gzipped tarball: the manifest, the
+// rewritten compose file, the .env template of projectRoot, the mounted
+// files, and the image archive at imagesPath
+func (b *Bundle) Write(w io.Writer, fsys filesystem.FS, projectRoot, imagesPath string) error {
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeEntry(archive, ManifestFile, append(manifest, '\n')); err != nil {
+		return err
+	}
+
+	scratch := filesystem.NewMemFS()
+	if err := compose.WriteDockerCompose(scratch, b.Compose, ComposeFile); err != nil {
+		return err
+	}
+	data, err := scratch.ReadFile(ComposeFile)
+	if err != nil {
+		return err
+	}
+	if err := writeEntry(archive, ComposeFile, data); err != nil {
+		return err
+	}
+
+	// The template lists the variables the services read without their
+	// values, which are set on the target machine
+	template, err := fsys.ReadFile(filepath.Join(projectRoot, EnvTemplate))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := writeEntry(archive, EnvTemplate, template); err != nil {
+		return err
+	}
+
+	for _, mount := range b.Mounts {
+		if err := writeTree(archive, fsys, projectRoot, mount); err != nil {
+			return err
+		}
+	}
+
+	images, err := os.Open(imagesPath)
+	if err != nil {
+		return fmt.Errorf("failed to read the saved images: %w", err)
+	}
+	defer images.Close()
+	info, err := images.Stat()
+	if err != nil {
+		return err
+	}
+	if err := archive.WriteHeader(&tar.Header{Name: ImagesFile, Mode: 0644, Size: info.Size(), Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	if _, err := io.Copy(archive, images); err != nil {
+		return fmt.Errorf("failed to pack the images: %w", err)
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// writeEntry adds a file to the tarball
+func writeEntry(archive *tar.Writer, name string, data []byte) error {
+	if err := archive.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	_, err := archive.Write(data)
+	return err
+}
+
+// writeTree adds the file or the files under the directory rel of
+// projectRoot to the tarball
+func writeTree(archive *tar.Writer, fsys filesystem.FS, projectRoot, rel string) error {
+	name := filepath.Join(projectRoot, filepath.FromSlash(rel))
+	info, err := fsys.Stat(name)
+	if err != nil {
+		return fmt.Errorf("failed to read mounted %s: %w", rel, err)
+	}
+	if !info.IsDir() {
+		data, err := fsys.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		return writeEntry(archive, rel, data)
+	}
+	entries, err := fsys.ReadDir(name)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := writeTree(archive, fsys, projectRoot, path.Join(rel, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Extract unpacks the bundle read from r into dir and returns its manifest
+func Extract(fsys filesystem.FS, r io.Reader, dir string) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a bundle: %w", err)
+	}
+	defer gz.Close()
+	archive := tar.NewReader(gz)
+
+	var manifest *Manifest
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		rel := path.Clean(header.Name)
+		if path.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, "../") {
+			return nil, fmt.Errorf("bundle entry %s points outside the bundle", header.Name)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := fsys.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+
+		if rel == ImagesFile {
+			file, err := Create(fsys, target)
+			if err != nil {
+				return nil, err
+			}
+			_, err = io.Copy(file, archive)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to unpack the images: %w", err)
+			}
+			continue
+		}
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from the bundle: %w", rel, err)
+		}
+		if rel == ManifestFile {
+			manifest = &Manifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", ManifestFile, err)
+			}
+		}
+		if err := fsys.WriteFile(target, data, 0644); err != nil {
+			return nil, err
+		}
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("not a bundle: %s is missing", ManifestFile)
+	}
+	return manifest, nil
+}
+
+// Create opens name on fsys for writing. Files on the OS filesystem are
+// streamed to disk so that image archives are never held in memory; on
+// other filesystems the file is written when the writer is closed.
+func Create(fsys filesystem.FS, name string) (io.WriteCloser, error) {
+	if filesystem.IsOS(fsys) {
+		return os.Create(name)
+	}
+	return &bufferedFile{fsys: fsys, name: name}, nil
+}
+
+// Open opens name on fsys for reading, streaming it from the OS filesystem
+func Open(fsys filesystem.FS, name string) (io.ReadCloser, error) {
+	if filesystem.IsOS(fsys) {
+		return os.Open(name)
+	}
+	data, err := fsys.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// bufferedFile collects a file written through Create in memory
+type bufferedFile struct {
+	bytes.Buffer
+	fsys filesystem.FS
+	name string
+}
+
+// Close writes the collected file
+func (f *bufferedFile) Close() error {
+	return f.fsys.WriteFile(f.name, f.Bytes(), 0644)
+}
+
+// LoadImages loads the images of a bundle unpacked into dir and removes
+// their archive
+func LoadImages(run deps.Runner, fsys filesystem.FS, dir string) error {
+	if _, err := run(dir, "docker", "load", "--input", ImagesFile); err != nil {
+		return fmt.Errorf("failed to load the images: %w", err)
+	}
+	return fsys.Remove(filepath.Join(dir, ImagesFile))
+}
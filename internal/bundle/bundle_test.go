@@ -0,0 +1,194 @@
+package bundle
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+const testCompose = `# THIS FILE IS AUTO-GENERATED BY 'om compose'.
+# om-manifest-revision: 0123456789ab
+
+services:
+    api:
+        build:
+            context: api
+            additional_contexts:
+                shared: libs/shared
+            labels:
+                com.openworkbench.entry: services.api
+        env_file:
+            - ./.env
+    api-db:
+        image: postgres:15
+        volumes:
+            - api_db_data:/var/lib/postgresql/data
+    mock-stripe:
+        image: stoplight/prism:5
+        volumes:
+            - ./specs/stripe.yaml:/tmp/stripe.yaml:ro
+volumes:
+    api_db_data: {}
+`
+
+// fakeDocker records docker commands and writes the archive docker save
+// would
+type fakeDocker struct {
+	ran     []string
+	missing map[string]bool // images docker image inspect does not find
+}
+
+func (f *fakeDocker) run(dir, name string, args ...string) ([]byte, error) {
+	f.ran = append(f.ran, strings.Join(append([]string{name}, args...), " "))
+	switch args[0] {
+	case "image":
+		if f.missing[args[len(args)-1]] {
+			return nil, os.ErrNotExist
+		}
+	case "save":
+		return nil, os.WriteFile(args[2], []byte("image layers"), 0644)
+	}
+	return nil, nil
+}
+
+func testProject(t *testing.T) *filesystem.MemFS {
+	t.Helper()
+	fsys := filesystem.NewMemFS()
+	files := map[string]string{
+		"demo/docker-compose.yml": testCompose,
+		"demo/.env.example":       "api_db_password=\n",
+		"demo/.env":               "api_db_password=password123\n",
+		"demo/specs/stripe.yaml":  "openapi: 3.0.0\n",
+	}
+	for name, content := range files {
+		if err := fsys.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsys.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fsys
+}
+
+func TestPlan(t *testing.T) {
+	b, err := Plan(testProject(t), "demo", "Demo")
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	if len(b.Builds) != 1 || b.Builds[0].Image != "demo-api:0123456789ab" {
+		t.Fatalf("expected api to be built as demo-api:0123456789ab, got %+v", b.Builds)
+	}
+	if api := b.Compose.Services["api"]; api.Build != nil || api.Image != "demo-api:0123456789ab" {
+		t.Errorf("expected api to run its built image, got %+v", api)
+	}
+	if got := strings.Join(b.Manifest.Images, " "); got != "demo-api:0123456789ab postgres:15 stoplight/prism:5" {
+		t.Errorf("Images = %s", got)
+	}
+	if got := strings.Join(b.Mounts, " "); got != "specs/stripe.yaml" {
+		t.Errorf("Mounts = %s", got)
+	}
+}
+
+func TestPlan_MountOutsideProject(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	if err := fsys.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	compose := "services:\n    mock:\n        image: prism\n        volumes:\n            - ../spec.yaml:/tmp/spec.yaml\n"
+	if err := fsys.WriteFile("demo/docker-compose.yml", []byte(compose), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Plan(fsys, "demo", "demo"); err == nil || !strings.Contains(err.Error(), "outside the project") {
+		t.Errorf("expected a mount outside the project to fail, got %v", err)
+	}
+}
+
+func TestExportAndImport(t *testing.T) {
+	fsys := testProject(t)
+	b, err := Plan(fsys, "demo", "demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docker := &fakeDocker{missing: map[string]bool{"stoplight/prism:5": true}}
+	imagesPath := filepath.Join(t.TempDir(), ImagesFile)
+	if err := b.BuildImages(docker.run, "demo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.PullImages(docker.run, "demo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SaveImages(docker.run, "demo", imagesPath); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"docker build --tag demo-api:0123456789ab --label com.openworkbench.entry=services.api --build-context shared=libs/shared api",
+		"docker image inspect --format {{.Id}} postgres:15",
+		"docker image inspect --format {{.Id}} stoplight/prism:5",
+		"docker pull stoplight/prism:5",
+		"docker save --output " + imagesPath + " demo-api:0123456789ab postgres:15 stoplight/prism:5",
+	}
+	if got := strings.Join(docker.ran, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("ran\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+
+	var archive bytes.Buffer
+	if err := b.Write(&archive, fsys, "demo", imagesPath); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	target := filesystem.NewMemFS()
+	manifest, err := Extract(target, &archive, "site")
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if manifest.Project != "demo" || manifest.Revision != "0123456789ab" || len(manifest.Images) != 3 {
+		t.Errorf("unexpected manifest %+v", manifest)
+	}
+
+	composeFile, err := target.ReadFile("site/docker-compose.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(composeFile), "build:") || !strings.Contains(string(composeFile), "image: demo-api:0123456789ab") {
+		t.Errorf("expected the bundled compose file to run images only, got:\n%s", composeFile)
+	}
+	if !strings.Contains(string(composeFile), "# om-manifest-revision: 0123456789ab") {
+		t.Errorf("expected the bundled compose file to keep the manifest revision, got:\n%s", composeFile)
+	}
+	for name, content := range map[string]string{
+		"site/.env.example":      "api_db_password=\n",
+		"site/specs/stripe.yaml": "openapi: 3.0.0\n",
+		"site/images.tar":        "image layers",
+	} {
+		if data, err := target.ReadFile(name); err != nil || string(data) != content {
+			t.Errorf("%s = %q, %v; want %q", name, data, err, content)
+		}
+	}
+	if filesystem.Exists(target, "site/.env") {
+		t.Error("expected the .env with credentials to stay out of the bundle")
+	}
+
+	docker.ran = nil
+	if err := LoadImages(docker.run, target, "site"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(docker.ran, "\n"); got != "docker load --input images.tar" {
+		t.Errorf("ran %s", got)
+	}
+	if filesystem.Exists(target, "site/images.tar") {
+		t.Error("expected the image archive to be removed once loaded")
+	}
+}
+
+func TestExtract_NotABundle(t *testing.T) {
+	if _, err := Extract(filesystem.NewMemFS(), strings.NewReader("plain text"), "site"); err == nil || !strings.Contains(err.Error(), "not a bundle") {
+		t.Errorf("expected plain text to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+// Package clientgen generates typed API clients for the services a service
+// consumes, from the providers' OpenAPI documents, with each language's own
+// generator: openapi-typescript for Node services and openapi-python-client
+// for Python services.
+package clientgen
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+// Supported client languages
+const (
+	LanguageTypeScript = "typescript"
+	LanguagePython     = "python"
+)
+
+// Client is one generated client: the API of Provider, for use in Consumer
+type Client struct {
+	Consumer string
+	Provider string
+	Language string
+	Spec     string // provider's OpenAPI document, relative to the project root
+	Output   string // generated file or package, relative to the consumer's path
+	EnvVar   string // consumer variable holding the provider's base URL
+}
+
+// Plan returns the clients to generate for every consumes entry of the named
+// services, or of every service when names is empty. Consumers whose
+// language cannot be told from their files are returned in skipped.
+func Plan(fsys filesystem.FS, projectRoot string, m *manifest.WorkbenchManifest, names []string) (clients []Client, skipped []string, err error) {
+	if len(names) == 0 {
+		for name := range m.Services {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		consumer, exists := m.Services[name]
+		if !exists {
+			return nil, nil, fmt.Errorf("service '%s' not found in workbench.yaml", name)
+		}
+		if len(consumer.Consumes) == 0 {
+			continue
+		}
+		language := Detect(fsys, filepath.Join(projectRoot, consumer.Path))
+		if language == "" {
+			skipped = append(skipped, name)
+			continue
+		}
+		for _, provider := range consumer.Consumes {
+			clients = append(clients, Client{
+				Consumer: name,
+				Provider: provider,
+				Language: language,
+				Spec:     m.Services[provider].APISpecPath(),
+				Output:   outputPath(language, provider),
+				EnvVar:   EnvVar(provider),
+			})
+		}
+	}
+	return clients, skipped, nil
+}
+
+// Detect returns the client language of the service in dir, judged by its
+// dependency manifests, or "" when there is none
+func Detect(fsys filesystem.FS, dir string) string {
+	if filesystem.Exists(fsys, filepath.Join(dir, "package.json")) {
+		return LanguageTypeScript
+	}
+	for _, file := range []string{"pyproject.toml", "requirements.txt"} {
+		if filesystem.Exists(fsys, filepath.Join(dir, file)) {
+			return LanguagePython
+		}
+	}
+	return ""
+}
+
+// EnvVar returns the variable holding the base URL of a provider's API,
+// e.g. BILLING_API_URL for billing
+func EnvVar(provider string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(provider)) + "_API_URL"
+}
+
+// outputPath returns where a provider's client goes inside a consumer
+func outputPath(language, provider string) string {
+	if language == LanguagePython {
+		return filepath.Join("clients", strings.ReplaceAll(provider, "-", "_"))
+	}
+	return filepath.Join("src", "clients", provider+".ts")
+}
+
+// Generate writes the client into the consumer at consumerDir, an absolute
+// or working-directory-relative path. The generator is run from the
+// consumer's directory.
+func Generate(run deps.Runner, projectRoot, consumerDir string, client Client) error {
+	spec, err := filepath.Rel(consumerDir, filepath.Join(projectRoot, client.Spec))
+	if err != nil {
+		return fmt.Errorf("failed to locate %s from %s: %w", client.Spec, client.Consumer, err)
+	}
+	spec = filepath.ToSlash(spec)
+	output := filepath.ToSlash(client.Output)
+
+	var name string
+	var args []string
+	switch client.Language {
+	case LanguageTypeScript:
+		name, args = "npx", []string{"--yes", "openapi-typescript", spec, "--output", output}
+	case LanguagePython:
+		name, args = "openapi-python-client", []string{"generate", "--path", spec, "--output-path", output, "--overwrite"}
+	default:
+		return fmt.Errorf("unsupported client language '%s'", client.Language)
+	}
+
+	if _, err := run(consumerDir, name, args...); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return fmt.Errorf("%s is not installed: %w", name, err)
+		}
+		return err
+	}
+	return nil
+}
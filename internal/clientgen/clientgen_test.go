@@ -0,0 +1,95 @@
+package clientgen
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+func clientsManifest() *manifest.WorkbenchManifest {
+	return &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "demo"},
+		Services: map[string]manifest.Service{
+			"billing-api": {Path: "billing", API: &manifest.API{Spec: "openapi.yaml"}},
+			"users":       {Path: "users", API: &manifest.API{Spec: "docs/openapi.json"}},
+			"web":         {Path: "web", Consumes: []string{"billing-api", "users"}},
+			"reports":     {Path: "reports", Consumes: []string{"users"}},
+			"cli":         {Path: "cli", Consumes: []string{"users"}},
+		},
+	}
+}
+
+func writeFile(t *testing.T, fsys *filesystem.MemFS, path string) {
+	t.Helper()
+	if err := fsys.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	writeFile(t, fsys, filepath.Join("demo", "web", "package.json"))
+	writeFile(t, fsys, filepath.Join("demo", "reports", "pyproject.toml"))
+
+	clients, skipped, err := Plan(fsys, "demo", clientsManifest(), nil)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if !reflect.DeepEqual(skipped, []string{"cli"}) {
+		t.Errorf("expected cli to be skipped for lack of a known language, got %v", skipped)
+	}
+	want := []Client{
+		{Consumer: "reports", Provider: "users", Language: LanguagePython, Spec: filepath.Join("users", "docs", "openapi.json"), Output: filepath.Join("clients", "users"), EnvVar: "USERS_API_URL"},
+		{Consumer: "web", Provider: "billing-api", Language: LanguageTypeScript, Spec: filepath.Join("billing", "openapi.yaml"), Output: filepath.Join("src", "clients", "billing-api.ts"), EnvVar: "BILLING_API_API_URL"},
+		{Consumer: "web", Provider: "users", Language: LanguageTypeScript, Spec: filepath.Join("users", "docs", "openapi.json"), Output: filepath.Join("src", "clients", "users.ts"), EnvVar: "USERS_API_URL"},
+	}
+	if !reflect.DeepEqual(clients, want) {
+		t.Errorf("unexpected clients:\n got %+v\nwant %+v", clients, want)
+	}
+
+	if _, _, err := Plan(fsys, "demo", clientsManifest(), []string{"mobile"}); err == nil {
+		t.Error("expected an unknown service to be rejected")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	var commands []string
+	run := func(dir, name string, args ...string) ([]byte, error) {
+		commands = append(commands, fmt.Sprintf("%s: %s %s", filepath.ToSlash(dir), name, strings.Join(args, " ")))
+		return nil, nil
+	}
+
+	clients := []Client{
+		{Consumer: "web", Provider: "users", Language: LanguageTypeScript, Spec: filepath.Join("users", "openapi.yaml"), Output: filepath.Join("src", "clients", "users.ts")},
+		{Consumer: "reports", Provider: "users", Language: LanguagePython, Spec: filepath.Join("users", "openapi.yaml"), Output: filepath.Join("clients", "users")},
+	}
+	for _, client := range clients {
+		if err := Generate(run, "demo", filepath.Join("demo", client.Consumer), client); err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+	}
+	want := []string{
+		"demo/web: npx --yes openapi-typescript ../users/openapi.yaml --output src/clients/users.ts",
+		"demo/reports: openapi-python-client generate --path ../users/openapi.yaml --output-path clients/users --overwrite",
+	}
+	if !reflect.DeepEqual(commands, want) {
+		t.Errorf("unexpected commands:\n got %v\nwant %v", commands, want)
+	}
+
+	missing := func(dir, name string, args ...string) ([]byte, error) {
+		return nil, fmt.Errorf("%s: %w", name, exec.ErrNotFound)
+	}
+	err := Generate(missing, "demo", filepath.Join("demo", "reports"), clients[1])
+	if err == nil || !strings.Contains(err.Error(), "openapi-python-client is not installed") {
+		t.Errorf("expected a missing generator to be reported, got %v", err)
+	}
+}
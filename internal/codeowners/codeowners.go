@@ -0,0 +1,189 @@
+// Package codeowners generates a CODEOWNERS file from the owners and teams
+// of a project's services and components. Generated rules live in a managed
+// block delimited by marker comments, so regenerating replaces only that
+// block and keeps the rules the team wrote around it. GitHub and GitLab read
+// the same syntax, and the last matching rule wins in both, so rules for
+// nested paths follow the rules for their parents.
+package codeowners
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+// File is where a new CODEOWNERS file is written, relative to the project
+// root
+const File = "CODEOWNERS"
+
+// Locations are the places GitHub and GitLab look for CODEOWNERS, in the
+// order Find searches them
+var Locations = []string{File, ".github/CODEOWNERS", ".gitlab/CODEOWNERS", "docs/CODEOWNERS"}
+
+// Markers delimiting the managed block
+const (
+	BeginMarker = "# om:codeowners:begin"
+	EndMarker   = "# om:codeowners:end"
+)
+
+// notice opens every managed block
+const notice = "# Generated by 'om generate codeowners' from workbench.yaml. Edits inside this block are overwritten."
+
+// Outputs are the files generated from the whole manifest, owned by
+// codeowners.default or, without it, by every owner in the project
+var Outputs = []string{"/workbench.yaml", "/docker-compose.yml", "/terraform/"}
+
+// Rule gives the owners of a path
+type Rule struct {
+	Path   string
+	Owners []string
+}
+
+// Handles returns the CODEOWNERS handles of an entry: its team as
+// @<org>/<team> and its owner as @<owner>, each kept as written when it is
+// already a handle or an email address
+func Handles(ownership manifest.Ownership, org string) ([]string, error) {
+	var handles []string
+	if team := strings.TrimSpace(ownership.Team); team != "" {
+		switch {
+		case manifest.IsHandle(team):
+			handles = append(handles, team)
+		case org != "":
+			handles = append(handles, "@"+org+"/"+strings.ToLower(strings.Join(strings.Fields(team), "-")))
+		default:
+			return nil, fmt.Errorf("team '%s' is not a handle; set codeowners.org or write it as @<org>/%s", team, team)
+		}
+	}
+	if owner := strings.TrimSpace(ownership.Owner); owner != "" {
+		switch {
+		case manifest.IsHandle(owner):
+			handles = append(handles, owner)
+		case !strings.Contains(owner, " "):
+			handles = append(handles, "@"+owner)
+		default:
+			return nil, fmt.Errorf("owner '%s' is not a handle; write it as @<user> or an email address", owner)
+		}
+	}
+	return handles, nil
+}
+
+// Rules returns the rules of the managed block: one per service and
+// component with an owner or team, and one per generated output. Entries
+// without owners get no rule and fall back to the rules around the block.
+func Rules(m *manifest.WorkbenchManifest) ([]Rule, error) {
+	entries := make(map[string]manifest.Ownership)
+	paths := make(map[string]string)
+	for name, service := range m.Services {
+		entries["services."+name], paths["services."+name] = service.Ownership, service.Path
+	}
+	for name, component := range m.Components {
+		entries["components."+name], paths["components."+name] = component.Ownership, component.Path
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rules []Rule
+	everyone := make(map[string]bool)
+	for _, name := range names {
+		handles, err := Handles(entries[name], m.CodeOwners.Org)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for _, handle := range handles {
+			everyone[handle] = true
+		}
+		dir := path.Clean(filepath.ToSlash(paths[name]))
+		if len(handles) == 0 || dir == "." || dir == "" {
+			continue
+		}
+		rules = append(rules, Rule{Path: "/" + dir + "/", Owners: handles})
+	}
+	// Parents before the paths nested in them, since the last match wins
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].Path < rules[j].Path })
+
+	owners := append([]string(nil), m.CodeOwners.Default...)
+	if len(owners) == 0 {
+		for handle := range everyone {
+			owners = append(owners, handle)
+		}
+		sort.Strings(owners)
+	}
+	if len(owners) > 0 {
+		for _, output := range Outputs {
+			rules = append(rules, Rule{Path: output, Owners: owners})
+		}
+	}
+	return rules, nil
+}
+
+// Render returns the managed block for the rules
+func Render(rules []Rule) string {
+	var b strings.Builder
+	b.WriteString(BeginMarker + "\n" + notice + "\n")
+	for _, rule := range rules {
+		fmt.Fprintf(&b, "%s %s\n", rule.Path, strings.Join(rule.Owners, " "))
+	}
+	b.WriteString(EndMarker + "\n")
+	return b.String()
+}
+
+// UpdateBlock replaces the managed block of a CODEOWNERS file with block,
+// appending it to files without one
+func UpdateBlock(document []byte, block string) ([]byte, error) {
+	text := string(document)
+	begin := strings.Index(text, BeginMarker)
+	if begin < 0 {
+		if strings.TrimSpace(text) == "" {
+			return []byte(block), nil
+		}
+		return []byte(strings.TrimRight(text, "\n") + "\n\n" + block), nil
+	}
+	end := strings.Index(text[begin:], EndMarker)
+	if end < 0 {
+		return nil, fmt.Errorf("the generated block is missing its end marker %s", EndMarker)
+	}
+	end += begin + len(EndMarker)
+	rest := strings.TrimPrefix(text[end:], "\n")
+	return []byte(text[:begin] + block + rest), nil
+}
+
+// Find returns the location of the project's CODEOWNERS file with a managed
+// block, relative to its root, or false when om has not generated one
+func Find(fsys filesystem.FS, projectRoot string) (string, bool) {
+	for _, location := range Locations {
+		data, err := fsys.ReadFile(filepath.Join(projectRoot, filepath.FromSlash(location)))
+		if err == nil && strings.Contains(string(data), BeginMarker) {
+			return location, true
+		}
+	}
+	return "", false
+}
+
+// Generate returns the CODEOWNERS file at location, relative to projectRoot,
+// with its managed block brought up to date
+func Generate(fsys filesystem.FS, projectRoot, location string, m *manifest.WorkbenchManifest) ([]byte, error) {
+	rules, err := Rules(m)
+	if err != nil {
+		return nil, err
+	}
+	file := filepath.Join(projectRoot, filepath.FromSlash(location))
+	var existing []byte
+	if filesystem.Exists(fsys, file) {
+		if existing, err = fsys.ReadFile(file); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", location, err)
+		}
+	}
+	updated, err := UpdateBlock(existing, Render(rules))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", location, err)
+	}
+	return updated, nil
+}
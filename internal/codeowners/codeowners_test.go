@@ -0,0 +1,103 @@
+package codeowners
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+func ownersManifest() *manifest.WorkbenchManifest {
+	return &manifest.WorkbenchManifest{
+		Metadata:   manifest.ProjectMetadata{Name: "shop"},
+		CodeOwners: manifest.CodeOwners{Org: "acme"},
+		Components: map[string]manifest.Component{
+			"gateway": {Ownership: manifest.Ownership{Team: "@acme/platform"}, Path: "./gateway"},
+		},
+		Services: map[string]manifest.Service{
+			"api":      {Ownership: manifest.Ownership{Team: "Payments Core", Owner: "jane"}, Path: "./apps"},
+			"checkout": {Ownership: manifest.Ownership{Owner: "sam@example.com"}, Path: "apps/checkout"},
+			"web":      {Path: "./web"},
+		},
+	}
+}
+
+func TestRules(t *testing.T) {
+	rules, err := Rules(ownersManifest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# om:codeowners:begin
+# Generated by 'om generate codeowners' from workbench.yaml. Edits inside this block are overwritten.
+/apps/ @acme/payments-core @jane
+/apps/checkout/ sam@example.com
+/gateway/ @acme/platform
+/workbench.yaml @acme/payments-core @acme/platform @jane sam@example.com
+/docker-compose.yml @acme/payments-core @acme/platform @jane sam@example.com
+/terraform/ @acme/payments-core @acme/platform @jane sam@example.com
+# om:codeowners:end
+`
+	if got := Render(rules); got != want {
+		t.Errorf("Render() =\n%s\nwant:\n%s", got, want)
+	}
+
+	m := ownersManifest()
+	m.CodeOwners.Default = []string{"@acme/platform"}
+	rules, err = Rules(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := rules[len(rules)-1]; last.Path != "/terraform/" || strings.Join(last.Owners, " ") != "@acme/platform" {
+		t.Errorf("expected the default owners to own the outputs, got %+v", last)
+	}
+
+	m.CodeOwners.Org = ""
+	if _, err := Rules(m); err == nil || !strings.Contains(err.Error(), "services.api") {
+		t.Errorf("expected a team without an organization to be rejected, got %v", err)
+	}
+}
+
+func TestGenerate_KeepsRulesAroundTheBlock(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	if err := fsys.MkdirAll(filepath.Join("shop", ".github"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	location := ".github/CODEOWNERS"
+	if _, found := Find(fsys, "shop"); found {
+		t.Fatal("expected no generated CODEOWNERS before generating one")
+	}
+
+	handwritten := "* @acme/leads\n"
+	if err := fsys.WriteFile(filepath.Join("shop", ".github", "CODEOWNERS"), []byte(handwritten), 0644); err != nil {
+		t.Fatal(err)
+	}
+	data, err := Generate(fsys, "shop", location, ownersManifest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), handwritten+"\n"+BeginMarker) {
+		t.Errorf("expected the block after the handwritten rules, got:\n%s", data)
+	}
+	if err := fsys.WriteFile(filepath.Join("shop", ".github", "CODEOWNERS"), append(data, []byte("/docs/ @acme/writers\n")...), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if found, ok := Find(fsys, "shop"); !ok || found != location {
+		t.Errorf("Find() = %q, %v, want %q", found, ok, location)
+	}
+	m := ownersManifest()
+	delete(m.Services, "checkout")
+	data, err = Generate(fsys, "shop", location, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	if strings.Contains(text, "/apps/checkout/") || strings.Count(text, BeginMarker) != 1 {
+		t.Errorf("expected the block to be replaced, got:\n%s", text)
+	}
+	if !strings.HasPrefix(text, handwritten) || !strings.HasSuffix(text, EndMarker+"\n/docs/ @acme/writers\n") {
+		t.Errorf("expected the rules around the block to be kept, got:\n%s", text)
+	}
+}
@@ -0,0 +1,62 @@
+package compose
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// APIDocsServiceName is the name of the docs portal added to docker-compose.yml
+// when any service declares an OpenAPI document
+const APIDocsServiceName = "api-docs"
+
+// apiDocsImage serves every spec from a single Swagger UI instance
+const apiDocsImage = "swaggerapi/swagger-ui:v5.17.14"
+
+// apiDocsPort is the host port of the docs portal
+const apiDocsPort = 8088
+
+// apiDocsSpecDir is where specs are mounted inside the Swagger UI container
+const apiDocsSpecDir = "/usr/share/nginx/html/specs"
+
+// createAPIDocsService creates a Swagger UI service that lists the OpenAPI
+// document of every service with an api.spec. It returns false when no
+// service declares one.
+func (g *Generator) createAPIDocsService() (DockerComposeService, bool) {
+	var names []string
+	for name, service := range g.project.Services {
+		if service.API != nil && service.API.Spec != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return DockerComposeService{}, false
+	}
+	sort.Strings(names)
+
+	type specURL struct {
+		URL  string `json:"url"`
+		Name string `json:"name"`
+	}
+	var urls []specURL
+	var volumes []string
+	for _, name := range names {
+		service := g.project.Services[name]
+		specFile := name + filepath.Ext(service.API.Spec)
+		source := "./" + strings.TrimPrefix(filepath.ToSlash(filepath.Join(service.Path, service.API.Spec)), "./")
+		volumes = append(volumes, fmt.Sprintf("%s:%s:ro", source, path.Join(apiDocsSpecDir, specFile)))
+		urls = append(urls, specURL{URL: "specs/" + specFile, Name: name})
+	}
+	encoded, _ := json.Marshal(urls)
+
+	return DockerComposeService{
+		Image:       apiDocsImage,
+		Ports:       []string{fmt.Sprintf("%d:8080", apiDocsPort)},
+		Environment: []string{"URLS=" + string(encoded)},
+		Volumes:     volumes,
+		Networks:    []string{"workbench_net"},
+	}, true
+}
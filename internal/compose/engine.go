@@ -0,0 +1,183 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+)
+
+// Container engines the generated compose file can run on
+const (
+	EngineDocker = "docker" // Docker Engine or Docker Desktop
+	EngineColima = "colima" // Docker Engine in a Colima VM
+	EnginePodman = "podman" // Podman, through podman-compose or 'podman compose'
+)
+
+// DockerSocket is where containers, such as Traefik gateways, mount the
+// Docker API from
+const DockerSocket = "/var/run/docker.sock"
+
+// Engine is the container engine that runs the project, as detected from the
+// tooling on the host
+type Engine struct {
+	Name        string   // EngineDocker, EngineColima, or EnginePodman
+	Compose     string   // command that runs compose files, e.g. "docker compose"
+	Socket      string   // the engine's API socket as containers mount it
+	Shares      []string // host directories its VM mounts, or nil when it reads the host's files directly
+	Limitations []string // known differences from Docker worth telling the user about
+}
+
+// DockerEngine returns the engine of a plain Docker installation
+func DockerEngine() Engine {
+	return Engine{Name: EngineDocker, Compose: "docker compose", Socket: DockerSocket}
+}
+
+// EngineProbe is what DetectEngine learns about the host from
+type EngineProbe struct {
+	GOOS     string
+	Getenv   func(string) string
+	LookPath func(string) (string, error)
+	Run      deps.Runner
+}
+
+// HostProbe returns the probe of the machine om runs on
+func HostProbe() EngineProbe {
+	return EngineProbe{GOOS: runtime.GOOS, Getenv: os.Getenv, LookPath: exec.LookPath, Run: deps.ExecRunner}
+}
+
+// DetectEngine tells the container engine apart by DOCKER_HOST, which
+// points at the socket of Podman or Colima when set by them, by a docker
+// command that is Podman in disguise (podman-docker), by the active docker
+// context, which Colima names after itself, and otherwise by the binaries
+// on the PATH.
+func DetectEngine(probe EngineProbe) Engine {
+	has := func(name string) bool {
+		_, err := probe.LookPath(name)
+		return err == nil
+	}
+	output := func(args ...string) string {
+		out, err := probe.Run("", "docker", args...)
+		if err != nil {
+			return ""
+		}
+		return strings.ToLower(strings.TrimSpace(string(out)))
+	}
+
+	name := EngineDocker
+	host := probe.Getenv("DOCKER_HOST")
+	switch {
+	case strings.Contains(host, "podman"):
+		name = EnginePodman
+	case strings.Contains(host, ".colima"):
+		name = EngineColima
+	case host != "":
+	case has("docker"):
+		if strings.Contains(output("--version"), "podman") {
+			name = EnginePodman
+		} else if strings.HasPrefix(output("context", "show"), "colima") {
+			name = EngineColima
+		}
+	case has("podman"):
+		name = EnginePodman
+	}
+
+	engine := DockerEngine()
+	engine.Name = name
+	if has("docker-compose") {
+		engine.Compose = "docker-compose"
+	}
+	home := probe.Getenv("HOME")
+	switch name {
+	case EngineColima:
+		engine.Shares = []string{home, "/tmp/colima"}
+		engine.Limitations = []string{
+			fmt.Sprintf("Only %s and /tmp/colima are mounted into the Colima VM by default; bind mounts of projects elsewhere come up empty until they are added to the mounts in colima.yaml", home),
+			"Published ports are forwarded to 127.0.0.1 only, so other machines cannot reach them",
+			"File changes reach containers only with the virtiofs mount type (colima start --vm-type vz --mount-type virtiofs)",
+		}
+	case EnginePodman:
+		engine.Compose = "podman compose"
+		if has("podman-compose") {
+			engine.Compose = "podman-compose"
+		}
+		engine.Limitations = []string{
+			"Rootless Podman cannot publish host ports below 1024",
+			"podman-compose before 1.1 ignores build.additional_contexts, which services with shared libraries are built with",
+		}
+		if probe.GOOS == "linux" {
+			engine.Socket = podmanSocket(probe.Getenv)
+			engine.Limitations = append(engine.Limitations,
+				fmt.Sprintf("Traefik gateways read the Docker API from %s; enable it with 'systemctl --user enable --now podman.socket'", engine.Socket))
+		} else {
+			engine.Socket = "/run/podman/podman.sock"
+			engine.Shares = []string{home}
+			engine.Limitations = append(engine.Limitations,
+				fmt.Sprintf("Only %s is mounted into the Podman machine by default; bind mounts of projects elsewhere come up empty", home),
+				"Traefik gateways read the Docker API of rootful machines; run 'podman machine set --rootful' for them to see containers")
+		}
+	}
+	return engine
+}
+
+// podmanSocket returns the API socket of Podman on Linux: the one DOCKER_HOST
+// names, the socket of the user's rootless Podman, or the system one
+func podmanSocket(getenv func(string) string) string {
+	if host := getenv("DOCKER_HOST"); strings.HasPrefix(host, "unix://") && strings.Contains(host, "podman") {
+		return strings.TrimPrefix(host, "unix://")
+	}
+	if runtimeDir := getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		return runtimeDir + "/podman/podman.sock"
+	}
+	return "/run/podman/podman.sock"
+}
+
+// SetEngine sets the container engine the generated file runs on. It
+// decides which socket Traefik gateways mount and which project directories
+// are warned about. Without it the file is generated for Docker.
+func (g *Generator) SetEngine(engine Engine) {
+	g.engine = engine
+}
+
+// applyEngine mounts the engine's API socket where services mount the Docker
+// socket. Podman labels containers for SELinux, which keeps them from
+// reading the socket unless labeling is disabled for them.
+func (g *Generator) applyEngine(config *DockerComposeConfig) {
+	if g.engine.Name != EnginePodman {
+		return
+	}
+	for name, service := range config.Services {
+		mounted := false
+		for i, volume := range service.Volumes {
+			if source, rest := splitVolume(volume); source == DockerSocket {
+				service.Volumes[i] = g.engine.Socket + rest
+				mounted = true
+			}
+		}
+		if mounted {
+			service.SecurityOpt = append(service.SecurityOpt, "label=disable")
+		}
+		config.Services[name] = service
+	}
+}
+
+// engineWarnings returns a warning when the project mounts files from a
+// directory the engine's VM does not share
+func (g *Generator) engineWarnings(config *DockerComposeConfig) []warnings.Warning {
+	if len(g.engine.Shares) == 0 || g.projectDir == "" || !hasBindMounts(config) {
+		return nil
+	}
+	dir := hostPath(g.host, g.projectDir)
+	for _, share := range g.engine.Shares {
+		if share != "" && (dir == share || strings.HasPrefix(dir, strings.TrimSuffix(share, "/")+"/")) {
+			return nil
+		}
+	}
+	return []warnings.Warning{warnings.New("",
+		"the project (%s) is outside the directories %s mounts into its VM (%s), so its bind mounts will be empty; move it under one of them or add a mount",
+		dir, g.engine.Name, strings.Join(g.engine.Shares, ", "))}
+}
@@ -0,0 +1,113 @@
+package compose
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/resources"
+)
+
+// EnvSection is a group of documented variables of the generated .env file,
+// such as the credentials of one resource
+type EnvSection struct {
+	Title     string // e.g. "Required by api.db (postgres 15)"
+	Service   string // the service the variables are for
+	Resource  string // the resource of the service they belong to
+	Variables []EnvVariable
+}
+
+// EnvVariable is a variable of the generated .env file and what it is for
+type EnvVariable struct {
+	Name        string
+	Description string // empty when neither the resource nor its blueprint documents it
+}
+
+// EnvSections returns the variables GenerateEnvFile writes, one section per
+// resource in the order of its service and name. Descriptions come from the
+// resource's envDocs in workbench.yaml, or else from its blueprint.
+func EnvSections(m *manifest.WorkbenchManifest) []EnvSection {
+	registry := resources.NewRegistry()
+	var sections []EnvSection
+	for _, serviceName := range slices.Sorted(maps.Keys(m.Services)) {
+		service := m.Services[serviceName]
+		for _, resourceName := range slices.Sorted(maps.Keys(service.Resources)) {
+			resource := service.Resources[resourceName]
+			credentials := ResourceCredentials(serviceName, resourceName, resource)
+			if len(credentials) == 0 {
+				continue
+			}
+			blueprint, _ := registry.Get(resources.BlueprintName(resource.Type))
+
+			kind := normalizeResourceType(resource.Type)
+			if resource.Version != "" {
+				kind += " " + resource.Version
+			}
+			section := EnvSection{
+				Title:    fmt.Sprintf("Required by %s.%s (%s)", serviceName, resourceName, kind),
+				Service:  serviceName,
+				Resource: resourceName,
+			}
+			prefix := fmt.Sprintf("%s_%s_", serviceName, resourceName)
+			for _, key := range slices.Sorted(maps.Keys(credentials)) {
+				property := strings.TrimPrefix(key, prefix)
+				description := resource.EnvDocs[property]
+				if description == "" {
+					description = blueprint.EnvDocs[property]
+				}
+				section.Variables = append(section.Variables, EnvVariable{Name: key, Description: description})
+			}
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+// SaveEnvExampleFile saves the .env.example file
+func SaveEnvExampleFile(sections []EnvSection, envVars map[string]string, filePath string) error {
+	return WriteEnvExampleFile(filesystem.NewOSFS(), sections, envVars, filePath)
+}
+
+// WriteEnvExampleFile writes the .env.example file to the given file system:
+// the keys of envVars without their values, grouped and commented by
+// sections. Keys no section documents come last.
+func WriteEnvExampleFile(fsys filesystem.FS, sections []EnvSection, envVars map[string]string, filePath string) error {
+	var blocks []string
+	documented := make(map[string]bool)
+	for _, section := range sections {
+		lines := []string{"# " + section.Title}
+		for _, variable := range section.Variables {
+			if _, exists := envVars[variable.Name]; !exists {
+				continue
+			}
+			documented[variable.Name] = true
+			if variable.Description != "" {
+				lines = append(lines, "# "+variable.Description)
+			}
+			lines = append(lines, variable.Name+"=")
+		}
+		if len(lines) > 1 {
+			blocks = append(blocks, strings.Join(lines, "\n"))
+		}
+	}
+
+	var rest []string
+	for _, key := range slices.Sorted(maps.Keys(envVars)) {
+		if !documented[key] {
+			rest = append(rest, key+"=")
+		}
+	}
+	if len(rest) > 0 {
+		blocks = append(blocks, strings.Join(rest, "\n"))
+	}
+
+	data := strings.Join(blocks, "\n\n") + "\n"
+	if err := fsys.WriteFile(filePath, []byte(data), 0644); err != nil {
+		return fmt.Errorf("failed to write .env.example file: %w", err)
+	}
+
+	return nil
+}
@@ -3,27 +3,62 @@ package compose
 import (
 	"bytes"
 	"fmt"
+	"maps"
 	"os"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
-	"text/template"
 
+	"github.com/jashkahar/open-workbench-platform/internal/explain"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/labels"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
 	"github.com/jashkahar/open-workbench-platform/internal/resources"
+	"github.com/jashkahar/open-workbench-platform/internal/revision"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
 	"gopkg.in/yaml.v3"
 )
 
 // Generator handles the translation of workbench.yaml to docker-compose.yml
 type Generator struct {
-	project *WorkbenchProject
+	project    *manifest.WorkbenchManifest // the manifest with only its container services
+	libraries  map[string]string           // path of every shared library, by name
+	labels     labels.Info
+	host       string // environment om runs in, see SetHost
+	engine     Engine
+	secrets    SecretsProvider // fills the secrets of GenerateEnvFile; random when nil
+	projectDir string
+	warnings   []warnings.Warning
 }
 
-// NewGenerator creates a new generator instance
-func NewGenerator(project *WorkbenchProject) *Generator {
+// NewGenerator creates a new generator instance. Services that run on the
+// host, and shared libraries, are not part of the compose project.
+func NewGenerator(m *manifest.WorkbenchManifest) *Generator {
+	project := *m
+	project.Services = make(map[string]manifest.Service, len(m.Services))
+	libraries := make(map[string]string)
+	for name, service := range m.Services {
+		if service.IsContainer() {
+			project.Services[name] = service
+		}
+		if service.IsLibrary() {
+			libraries[name] = service.Path
+		}
+	}
 	return &Generator{
-		project: project,
+		project:   &project,
+		libraries: libraries,
 	}
 }
 
+// SetLabels sets the om version and environment the generated containers,
+// volumes, and images are labeled with. The project defaults to the
+// project's name.
+func (g *Generator) SetLabels(info labels.Info) {
+	g.labels = info
+}
+
 // Generate creates the docker-compose.yml configuration
 func (g *Generator) Generate() (*DockerComposeConfig, error) {
 	config := &DockerComposeConfig{
@@ -34,80 +69,254 @@ func (g *Generator) Generate() (*DockerComposeConfig, error) {
 				"driver": "bridge",
 			},
 		},
+		Origins:  make(map[string]explain.Origin),
+		Revision: g.labels.Revision,
 	}
+	g.warnings = nil
 
 	// Process components first
 	for name, component := range g.project.Components {
 		service := g.createComponentService(name, component)
 		config.Services[name] = service
+		config.Origins[name] = explain.Origin{Entry: "components." + name, Rule: componentRule(component)}
 	}
 
 	// Process services
 	for name, service := range g.project.Services {
 		dockerService := g.createService(name, service)
 		config.Services[name] = dockerService
+		config.Origins[name] = explain.Origin{Entry: "services." + name, Rule: explain.RuleService}
 
 		// Add service-owned resources
 		for resourceName, resource := range service.Resources {
 			resourceService := g.createResourceService(name, resourceName, resource)
 			config.Services[resourceServiceName(name, resourceName)] = resourceService
+			config.Origins[resourceServiceName(name, resourceName)] = explain.Origin{
+				Entry: fmt.Sprintf("services.%s.resources.%s", name, resourceName),
+				Rule:  explain.RuleResource,
+			}
 
 			// Add volume for the resource
 			volumeName := fmt.Sprintf("%s_%s_data", name, resourceName)
-			config.Volumes[volumeName] = nil
+			config.Volumes[volumeName] = g.volumeConfig(config.Origins[resourceServiceName(name, resourceName)].Entry)
+		}
+	}
+
+	// Mock external dependencies that ship an OpenAPI document
+	for name, external := range g.project.External {
+		if external.Spec == "" {
+			continue
+		}
+		if _, exists := config.Services[MockServiceName(name)]; !exists {
+			config.Services[MockServiceName(name)] = createMockService(name, external)
+			config.Origins[MockServiceName(name)] = explain.Origin{Entry: "external." + name, Rule: explain.RuleMock}
+		}
+	}
+
+	// Serve the API specs of all services from one docs portal
+	if _, exists := config.Services[APIDocsServiceName]; !exists {
+		if docs, ok := g.createAPIDocsService(); ok {
+			config.Services[APIDocsServiceName] = docs
+			config.Origins[APIDocsServiceName] = explain.Origin{Entry: "services.*.api.spec", Rule: explain.RuleAPIDocs}
 		}
 	}
 
 	// Resolve dependencies and environment variables
 	g.resolveDependencies(config)
 	g.resolveEnvironmentVariables(config)
+	g.applyProjectLabels(config)
+	g.applyEngine(config)
+	g.translateHostPaths(config)
+	g.warnings = append(g.warnings, g.hostWarnings(config)...)
+	g.warnings = append(g.warnings, g.engineWarnings(config)...)
 
 	return config, nil
 }
 
+// projectLabels returns the labels tying an artifact to the project and the
+// workbench.yaml entry behind it, or nil when the project has no name
+func (g *Generator) projectLabels(entry string) map[string]string {
+	info := g.labels
+	if info.Project == "" {
+		info.Project = g.project.Metadata.Name
+	}
+	service, template := "", ""
+	var ownership manifest.Ownership
+	parts := strings.Split(entry, ".")
+	switch {
+	case len(parts) >= 2 && parts[0] == "services" && parts[1] != "*":
+		service, ownership = parts[1], g.project.Services[parts[1]].Ownership
+		if len(parts) == 2 {
+			template = g.project.Services[service].Template
+		}
+	case len(parts) == 2 && parts[0] == "components":
+		service, template = parts[1], g.project.Components[parts[1]].Template
+		ownership = g.project.Components[parts[1]].Ownership
+	}
+	entryLabels := info.For(entry, service, template)
+	labels.AddOwnership(entryLabels, ownership.Owner, ownership.Team, ownership.Tags)
+	return entryLabels
+}
+
+// volumeConfig returns the top-level definition of a volume owned by entry
+func (g *Generator) volumeConfig(entry string) interface{} {
+	volumeLabels := g.projectLabels(entry)
+	if volumeLabels == nil {
+		return nil
+	}
+	return map[string]interface{}{"labels": volumeLabels}
+}
+
+// applyProjectLabels labels every container, and every image built from the
+// project, with the project, the entry it was generated from, and the run
+func (g *Generator) applyProjectLabels(config *DockerComposeConfig) {
+	for name, service := range config.Services {
+		serviceLabels := g.projectLabels(config.Origins[name].Entry)
+		if serviceLabels == nil {
+			return
+		}
+		service.Labels = append(service.Labels, labels.List(serviceLabels)...)
+		if service.Build != nil {
+			service.Build.Labels = serviceLabels
+		}
+		config.Services[name] = service
+	}
+}
+
+// Warnings returns the warnings found by the last call to Generate
+func (g *Generator) Warnings() []warnings.Warning {
+	return warnings.Sort(g.warnings)
+}
+
+// componentRule returns the explain rule of the service generated for a component
+func componentRule(component manifest.Component) string {
+	switch component.Template {
+	case TraefikTemplate:
+		return explain.RuleTraefikGateway
+	case GraphQLGatewayTemplate:
+		return explain.RuleGraphQLGateway
+	}
+	return explain.RuleComponent
+}
+
 // createComponentService creates a Docker Compose service for a component
-func (g *Generator) createComponentService(name string, component Component) DockerComposeService {
-	service := DockerComposeService{
-		Build: &BuildConfig{
-			Context: component.Path,
-		},
-		Networks: []string{"workbench_net"},
+func (g *Generator) createComponentService(name string, component manifest.Component) DockerComposeService {
+	var service DockerComposeService
+	switch component.Template {
+	case TraefikTemplate:
+		service = createTraefikService(component)
+	case GraphQLGatewayTemplate:
+		service = g.createGraphQLGatewayService(component)
+	default:
+		service = DockerComposeService{
+			Build: &BuildConfig{
+				Context: component.Path,
+			},
+			EnvFile:  []string{"./.env"},
+			Networks: []string{"workbench_net"},
+		}
+
+		if len(component.Ports) > 0 {
+			service.Ports = component.Ports
+		}
 	}
 
-	if len(component.Ports) > 0 {
-		service.Ports = component.Ports
+	// Add the component's own environment variables, command overrides,
+	// process settings, and hardening
+	service.Environment = mergeEnvironment(service.Environment, g.interpolate("components."+name, component.Environment))
+	if len(component.Command) > 0 {
+		service.Command = component.Command
+	}
+	if len(component.Entrypoint) > 0 {
+		service.Entrypoint = component.Entrypoint
 	}
+	service.Restart = component.Restart
+	service.Init = component.Init
+	applySecurity(&service, component.Security)
 
 	return service
 }
 
-// createService creates a Docker Compose service for a regular service
-func (g *Generator) createService(name string, service Service) DockerComposeService {
+// interpolate resolves the ${infra.*} and ${env.*} references of an entry's
+// environment variables: infra outputs become the local stand-ins declared
+// under infra, and env keys the values of the environment generated for.
+// Without an environment, variables that refer to one are left out with a
+// warning.
+func (g *Generator) interpolate(entry string, environment map[string]string) map[string]string {
+	if len(environment) == 0 {
+		return environment
+	}
+	resolved := make(map[string]string, len(environment))
+	for key, value := range environment {
+		value = g.project.ResolveEnvReferences(g.project.ResolveLocalInfraReferences(value), g.labels.Environment)
+		if manifest.HasEnvReferences(value) {
+			g.warnings = append(g.warnings, warnings.New(entry,
+				"environment variable %s refers to ${env.*} and is left out; pass --env to resolve it", key))
+			continue
+		}
+		resolved[key] = value
+	}
+	return resolved
+}
+
+// applySecurity adds the hardening settings of a service or component to its
+// container
+func applySecurity(dockerService *DockerComposeService, security *manifest.Security) {
+	if security == nil {
+		return
+	}
+	dockerService.User = security.User
+	dockerService.ReadOnly = security.ReadOnlyRootFilesystem
+	dockerService.CapDrop = security.CapDrop
+	if security.NoNewPrivileges {
+		dockerService.SecurityOpt = []string{"no-new-privileges:true"}
+	}
+}
+
+// createService creates a Docker Compose service for a regular service. It
+// builds the service's path, or runs its image when one is set.
+func (g *Generator) createService(name string, service manifest.Service) DockerComposeService {
 	dockerService := DockerComposeService{
-		Build: &BuildConfig{
-			Context: service.Path,
-		},
 		EnvFile:  []string{"./.env"},
 		Networks: []string{"workbench_net"},
 	}
+	if service.Image != "" {
+		dockerService.Image = service.Image
+	} else {
+		dockerService.Build = &BuildConfig{Context: service.Path}
+		// Shared libraries live outside the service's context; the Dockerfile
+		// copies them in with COPY --from=<library>
+		if len(service.Libraries) > 0 {
+			dockerService.Build.AdditionalContexts = make(map[string]string, len(service.Libraries))
+			for _, name := range service.Libraries {
+				dockerService.Build.AdditionalContexts[name] = g.libraries[name]
+			}
+		}
+	}
 
 	// Add port mapping if specified
 	if service.Port > 0 {
 		dockerService.Ports = []string{fmt.Sprintf("%d:%d", service.Port, service.Port)}
 	}
 
-	// Add environment variables
-	if len(service.Environment) > 0 {
-		for key, value := range service.Environment {
-			dockerService.Environment = append(dockerService.Environment, fmt.Sprintf("%s=%s", key, value))
-		}
+	// Add environment variables, command overrides, and process settings
+	dockerService.Environment = mergeEnvironment(nil, g.interpolate("services."+name, service.Environment))
+	dockerService.Command = service.Command
+	dockerService.Entrypoint = service.Entrypoint
+	dockerService.Restart = service.Restart
+	dockerService.Init = service.Init
+	applySecurity(&dockerService, service.Security)
+
+	// Route the service through a Traefik gateway if the project has one
+	if g.hasTraefikGateway() && service.Port > 0 {
+		dockerService.Labels = traefikLabels(name, service)
 	}
 
 	return dockerService
 }
 
 // createResourceService creates a Docker Compose service for a resource (like a database)
-func (g *Generator) createResourceService(serviceName, resourceName string, resource Resource) DockerComposeService {
+func (g *Generator) createResourceService(serviceName, resourceName string, resource manifest.Resource) DockerComposeService {
 	// Start with base defaults
 	dockerService := DockerComposeService{
 		EnvFile:  []string{"./.env"},
@@ -115,14 +324,29 @@ func (g *Generator) createResourceService(serviceName, resourceName string, reso
 	}
 
 	// Try to apply a resource blueprint if available
-	if applied := g.applyBlueprintIfAvailable(resource, &dockerService); !applied {
+	entry := fmt.Sprintf("services.%s.resources.%s", serviceName, resourceName)
+	if found, err := g.applyBlueprint(resource, &dockerService); !found || err != nil {
 		// Fallback: map known types to canonical images
 		baseImage := resolveBaseImage(resource.Type)
 		version := strings.TrimSpace(resource.Version)
 		if version == "" {
 			version = "latest"
 		}
+		dockerService = DockerComposeService{EnvFile: dockerService.EnvFile, Networks: dockerService.Networks}
 		dockerService.Image = fmt.Sprintf("%s:%s", baseImage, version)
+		if err != nil {
+			g.warnings = append(g.warnings, warnings.New(entry,
+				"blueprint for resource type '%s' could not be applied (%v); using image %s", resource.Type, err, dockerService.Image))
+		} else {
+			g.warnings = append(g.warnings, warnings.New(entry,
+				"no blueprint for resource type '%s'; using image %s", resource.Type, dockerService.Image))
+		}
+	}
+
+	// Resources are reached by name on the compose network; only those
+	// exposed to the host keep the ports their blueprint publishes
+	if !resource.PublishesPort() {
+		dockerService.Ports = nil
 	}
 
 	// Ensure we have a volume mapping for known types if none was provided
@@ -131,18 +355,98 @@ func (g *Generator) createResourceService(serviceName, resourceName string, reso
 	// Normalize any blueprint-provided volume names to the computed top-level volume name
 	g.rewriteResourceVolumeNames(serviceName, resourceName, &dockerService)
 
+	// Layer the user's extra variables over the blueprint's
+	dockerService.Environment = mergeEnvironment(dockerService.Environment, g.interpolate(entry, resource.Environment))
+
 	return dockerService
 }
 
-// resolveDependencies analyzes environment variables to determine service dependencies
+// mergeEnvironment returns the KEY=value entries of base with the variables of
+// extra added in key order. Variables in extra replace entries of base with
+// the same key.
+func mergeEnvironment(base []string, extra map[string]string) []string {
+	if len(extra) == 0 {
+		return base
+	}
+
+	var merged []string
+	for _, entry := range base {
+		key := strings.SplitN(entry, "=", 2)[0]
+		if _, overridden := extra[key]; !overridden {
+			merged = append(merged, entry)
+		}
+	}
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		merged = append(merged, fmt.Sprintf("%s=%s", key, extra[key]))
+	}
+	return merged
+}
+
+// resolveDependencies sets the services each service waits for: those its
+// environment variables refer to, and those it depends on in workbench.yaml
+// with the condition to wait for. Services waited for until healthy get a
+// health check.
 func (g *Generator) resolveDependencies(config *DockerComposeConfig) {
 	for serviceName, service := range config.Services {
 		dependencies := g.extractDependencies(serviceName, service)
+		for _, dependency := range g.project.Services[serviceName].DependsOn {
+			// Dependencies left out of the environment or group are not waited for
+			if _, exists := config.Services[dependency.Service]; !exists {
+				continue
+			}
+			if !slices.Contains(dependencies, dependency.Service) {
+				dependencies = append(dependencies, dependency.Service)
+			}
+			if service.Conditions == nil {
+				service.Conditions = make(map[string]string)
+			}
+			service.Conditions[dependency.Service] = composeCondition(g.project.DependencyCondition(dependency))
+		}
 		if len(dependencies) > 0 {
 			service.DependsOn = dependencies
 			config.Services[serviceName] = service
 		}
 	}
+
+	for _, serviceName := range slices.Sorted(maps.Keys(config.Services)) {
+		for dependency, condition := range config.Services[serviceName].Conditions {
+			target := config.Services[dependency]
+			if condition == ConditionHealthy && target.Healthcheck == nil {
+				target.Healthcheck = healthcheck(g.project.Services[dependency])
+				config.Services[dependency] = target
+			}
+		}
+	}
+}
+
+// composeCondition returns the depends_on condition of a workbench.yaml
+// dependency condition
+func composeCondition(condition string) string {
+	switch condition {
+	case manifest.DependencyHealthy:
+		return ConditionHealthy
+	case manifest.DependencyCompleted:
+		return ConditionCompleted
+	}
+	return ConditionStarted
+}
+
+// healthcheck checks a service's health endpoint from inside its container
+// with wget or curl, whichever its image has
+func healthcheck(service manifest.Service) *Healthcheck {
+	url := fmt.Sprintf("http://localhost:%d%s", service.Port, service.HealthPath())
+	return &Healthcheck{
+		Test:        []string{"CMD-SHELL", fmt.Sprintf("wget -q --spider %s || curl -fsS -o /dev/null %s || exit 1", url, url)},
+		Interval:    "10s",
+		Timeout:     "5s",
+		Retries:     5,
+		StartPeriod: "10s",
+	}
 }
 
 // extractDependencies extracts service dependencies from environment variables
@@ -171,6 +475,12 @@ func (g *Generator) extractDependencies(serviceName string, service DockerCompos
 					referencedComponent := parts[1]
 					dependencies = append(dependencies, referencedComponent)
 				}
+				// Check if it's a mocked external dependency
+				if parts[0] == "external" {
+					if external, exists := g.project.External[parts[1]]; exists && external.Spec != "" {
+						dependencies = append(dependencies, MockServiceName(parts[1]))
+					}
+				}
 			}
 		}
 	}
@@ -248,70 +558,29 @@ func normalizeResourceType(resourceType string) string {
 	}
 }
 
-// resolveBlueprintKey maps resource type to a registry blueprint key
-func resolveBlueprintKey(resourceType string) string {
-	switch strings.ToLower(resourceType) {
-	case "postgres", "postgres-db":
-		return "postgres-db"
-	case "mysql", "mysql-db":
-		return "mysql-db"
-	case "mongodb", "mongo":
-		return "mongodb"
-	case "redis", "redis-cache":
-		return "redis-cache"
-	case "memcached":
-		return "memcached"
-	case "rabbitmq":
-		return "rabbitmq"
-	default:
-		return resourceType
-	}
-}
-
-// applyBlueprintIfAvailable tries to render and merge a resource blueprint into dockerService
-func (g *Generator) applyBlueprintIfAvailable(resource Resource, dockerService *DockerComposeService) bool {
+// applyBlueprint renders and merges the resource's blueprint into
+// dockerService. It reports whether the resource type has a blueprint, and
+// the error when the blueprint could not be rendered with the resource's
+// version and config.
+func (g *Generator) applyBlueprint(resource manifest.Resource, dockerService *DockerComposeService) (bool, error) {
 	registry := resources.NewRegistry()
-	key := resolveBlueprintKey(resource.Type)
+	key := resources.BlueprintName(resource.Type)
 	blueprint, err := registry.Get(key)
 	if err != nil || strings.TrimSpace(blueprint.DockerComposeSnippet) == "" {
-		return false
+		return false, nil
 	}
 
-	// Build template data combining version and config (both original and Title-cased keys)
-	data := map[string]interface{}{}
-	if resource.Version != "" {
-		data["Version"] = resource.Version
-		data["version"] = resource.Version
-	}
-	for k, v := range resource.Config {
-		data[k] = v
-		if len(k) > 0 {
-			// Title-case first rune only, keep rest as-is
-			r := []rune(k)
-			r[0] = []rune(strings.ToUpper(string(r[0])))[0]
-			data[string(r)] = v
-		}
-	}
-
-	// Render snippet
-	rendered, err := template.New("snippet").Parse(blueprint.DockerComposeSnippet)
+	snippet, err := blueprint.RenderSnippet(resources.TemplateData(resource.Version, resource.Config))
 	if err != nil {
-		return false
-	}
-	var buf bytes.Buffer
-	if err := rendered.Execute(&buf, data); err != nil {
-		return false
+		return true, err
 	}
 
 	// Wrap into a minimal YAML document for unmarshalling
-	snippet := strings.TrimLeft(buf.String(), "\n")
-	wrapped := "service:\n" + snippet
-
 	var tmp struct {
 		Service DockerComposeService `yaml:"service"`
 	}
-	if err := yaml.Unmarshal([]byte(wrapped), &tmp); err != nil {
-		return false
+	if err := yaml.Unmarshal([]byte("service:\n"+snippet), &tmp); err != nil {
+		return true, err
 	}
 
 	// Merge fields conservatively
@@ -328,11 +597,11 @@ func (g *Generator) applyBlueprintIfAvailable(resource Resource, dockerService *
 		dockerService.Environment = append(dockerService.Environment, tmp.Service.Environment...)
 	}
 
-	return true
+	return true, nil
 }
 
 // ensureDefaultVolumeForKnownTypes ensures a data volume exists for common stateful services if blueprint didn't specify one
-func (g *Generator) ensureDefaultVolumeForKnownTypes(serviceName, resourceName string, resource Resource, dockerService *DockerComposeService) {
+func (g *Generator) ensureDefaultVolumeForKnownTypes(serviceName, resourceName string, resource manifest.Resource, dockerService *DockerComposeService) {
 	if len(dockerService.Volumes) > 0 {
 		return
 	}
@@ -375,6 +644,21 @@ func (g *Generator) resolveEnvironmentVariable(envVar, serviceName string) strin
 		return match
 	})
 
+	// Replace ${services.service.url} patterns with the service's address
+	// inside the compose network
+	re = regexp.MustCompile(`\$\{services\.([^.}]+)\.url\}`)
+	envVar = re.ReplaceAllStringFunc(envVar, func(match string) string {
+		name := re.FindStringSubmatch(match)[1]
+		service, exists := g.project.Services[name]
+		if !exists || service.Port == 0 {
+			return match
+		}
+		if service.Protocol == "" || service.Protocol == "http" {
+			return fmt.Sprintf("http://%s:%d", name, service.Port)
+		}
+		return fmt.Sprintf("%s:%d", name, service.Port)
+	})
+
 	// Replace ${components.component.property} patterns
 	re = regexp.MustCompile(`\$\{components\.([^.]+)\.([^}]+)\}`)
 	envVar = re.ReplaceAllStringFunc(envVar, func(match string) string {
@@ -400,6 +684,21 @@ func (g *Generator) resolveEnvironmentVariable(envVar, serviceName string) strin
 		return match
 	})
 
+	// Replace ${external.name.url} patterns, pointing mocked dependencies at
+	// their mock server
+	re = regexp.MustCompile(`\$\{external\.([^.}]+)\.url\}`)
+	envVar = re.ReplaceAllStringFunc(envVar, func(match string) string {
+		name := re.FindStringSubmatch(match)[1]
+		external, exists := g.project.External[name]
+		if !exists {
+			return match
+		}
+		if external.Spec != "" {
+			return mockURL(name)
+		}
+		return external.URL
+	})
+
 	return envVar
 }
 
@@ -412,40 +711,66 @@ func resourceServiceName(serviceName, resourceName string) string {
 func (g *Generator) GenerateEnvFile() (map[string]string, error) {
 	envVars := make(map[string]string)
 
-	// Generate default credentials for each service's resources
+	// Derive the names of each service's resources, and leave their
+	// passwords to the secrets provider
+	var secrets []string
 	for serviceName, service := range g.project.Services {
 		for resourceName, resource := range service.Resources {
-			prefix := fmt.Sprintf("%s_%s", serviceName, resourceName)
-
-			// Generate default credentials based on resource type
-			switch normalizeResourceType(resource.Type) {
-			case "postgres":
-				envVars[fmt.Sprintf("%s_user", prefix)] = fmt.Sprintf("%s_user", serviceName)
-				envVars[fmt.Sprintf("%s_password", prefix)] = "password123"
-				envVars[fmt.Sprintf("%s_name", prefix)] = fmt.Sprintf("%s_%s", serviceName, resourceName)
-				envVars[fmt.Sprintf("%s_dbname", prefix)] = fmt.Sprintf("%s_%s_db", serviceName, resourceName)
-			case "mysql":
-				envVars[fmt.Sprintf("%s_user", prefix)] = fmt.Sprintf("%s_user", serviceName)
-				envVars[fmt.Sprintf("%s_password", prefix)] = "password123"
-				envVars[fmt.Sprintf("%s_name", prefix)] = fmt.Sprintf("%s_%s", serviceName, resourceName)
-				envVars[fmt.Sprintf("%s_dbname", prefix)] = fmt.Sprintf("%s_%s_db", serviceName, resourceName)
-			case "redis":
-				envVars[fmt.Sprintf("%s_password", prefix)] = "password123"
+			for key, value := range ResourceCredentials(serviceName, resourceName, resource) {
+				envVars[key] = value
+				if IsSecret(key) {
+					secrets = append(secrets, key)
+				}
 			}
 		}
 	}
+	sort.Strings(secrets)
+
+	provider := g.secrets
+	if provider == nil {
+		provider = GeneratedSecrets{}
+	}
+	values, err := provider.Secrets(secrets)
+	if err != nil {
+		return nil, fmt.Errorf("%s secrets: %w", provider.Name(), err)
+	}
+	for _, key := range secrets {
+		envVars[key] = values[key]
+	}
 
 	return envVars, nil
 }
 
-// LoadWorkbenchProject loads a workbench.yaml file
-func LoadWorkbenchProject(filePath string) (*WorkbenchProject, error) {
+// ResourceCredentials returns the credentials of a service's resource as
+// the .env variables GenerateEnvFile writes them to. Secrets, for which
+// IsSecret holds, are left empty for the secrets provider to fill.
+// Resource types without credentials return nil.
+func ResourceCredentials(serviceName, resourceName string, resource manifest.Resource) map[string]string {
+	prefix := fmt.Sprintf("%s_%s", serviceName, resourceName)
+
+	// Generate default credentials based on resource type
+	switch normalizeResourceType(resource.Type) {
+	case "postgres", "mysql":
+		return map[string]string{
+			fmt.Sprintf("%s_user", prefix):     fmt.Sprintf("%s_user", serviceName),
+			fmt.Sprintf("%s_password", prefix): "",
+			fmt.Sprintf("%s_name", prefix):     fmt.Sprintf("%s_%s", serviceName, resourceName),
+			fmt.Sprintf("%s_dbname", prefix):   fmt.Sprintf("%s_%s_db", serviceName, resourceName),
+		}
+	case "redis":
+		return map[string]string{fmt.Sprintf("%s_password", prefix): ""}
+	}
+	return nil
+}
+
+// LoadWorkbenchProject loads a workbench.yaml file without validating it
+func LoadWorkbenchProject(filePath string) (*manifest.WorkbenchManifest, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read workbench.yaml: %w", err)
 	}
 
-	var project WorkbenchProject
+	var project manifest.WorkbenchManifest
 	if err := yaml.Unmarshal(data, &project); err != nil {
 		return nil, fmt.Errorf("failed to parse workbench.yaml: %w", err)
 	}
@@ -455,47 +780,75 @@ func LoadWorkbenchProject(filePath string) (*WorkbenchProject, error) {
 
 // SaveDockerCompose saves the docker-compose.yml file
 func SaveDockerCompose(config *DockerComposeConfig, filePath string) error {
-	data, err := yaml.Marshal(config)
+	return WriteDockerCompose(filesystem.NewOSFS(), config, filePath)
+}
+
+// WriteDockerCompose writes the docker-compose.yml file to the given file system
+func WriteDockerCompose(fsys filesystem.FS, config *DockerComposeConfig, filePath string) error {
+	data, err := marshalWithOrigins(config)
 	if err != nil {
 		return fmt.Errorf("failed to marshal docker-compose config: %w", err)
 	}
 
 	// Add header comment
-	header := "# THIS FILE IS AUTO-GENERATED BY 'om compose'.\n# For permanent changes, modify your workbench.yaml and re-run the command.\n\n"
+	header := "# THIS FILE IS AUTO-GENERATED BY 'om compose'.\n# For permanent changes, modify your workbench.yaml and re-run the command.\n" +
+		revision.Comment(config.Revision) + "\n"
 	data = append([]byte(header), data...)
 
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
+	if err := fsys.WriteFile(filePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write docker-compose.yml: %w", err)
 	}
 
 	return nil
 }
 
-// SaveEnvFile saves the .env file
-func SaveEnvFile(envVars map[string]string, filePath string) error {
-	var lines []string
-	for key, value := range envVars {
-		lines = append(lines, fmt.Sprintf("%s=%s", key, value))
+// marshalWithOrigins marshals the configuration with a marker comment above
+// every service that names the workbench.yaml entry it was generated from
+func marshalWithOrigins(config *DockerComposeConfig) ([]byte, error) {
+	var document yaml.Node
+	if err := document.Encode(config); err != nil {
+		return nil, err
 	}
 
-	data := strings.Join(lines, "\n") + "\n"
-	if err := os.WriteFile(filePath, []byte(data), 0644); err != nil {
-		return fmt.Errorf("failed to write .env file: %w", err)
+	for i := 0; i+1 < len(document.Content); i += 2 {
+		if document.Content[i].Value != "services" {
+			continue
+		}
+		services := document.Content[i+1]
+		for j := 0; j+1 < len(services.Content); j += 2 {
+			if origin, ok := config.Origins[services.Content[j].Value]; ok {
+				services.Content[j].HeadComment = origin.Marker()
+			}
+		}
 	}
 
-	return nil
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(4)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
-// SaveEnvExampleFile saves the .env.example file
-func SaveEnvExampleFile(envVars map[string]string, filePath string) error {
+// SaveEnvFile saves the .env file
+func SaveEnvFile(envVars map[string]string, filePath string) error {
+	return WriteEnvFile(filesystem.NewOSFS(), envVars, filePath)
+}
+
+// WriteEnvFile writes the .env file to the given file system
+func WriteEnvFile(fsys filesystem.FS, envVars map[string]string, filePath string) error {
 	var lines []string
-	for key := range envVars {
-		lines = append(lines, fmt.Sprintf("%s=", key))
+	for _, key := range slices.Sorted(maps.Keys(envVars)) {
+		lines = append(lines, fmt.Sprintf("%s=%s", key, envVars[key]))
 	}
 
 	data := strings.Join(lines, "\n") + "\n"
-	if err := os.WriteFile(filePath, []byte(data), 0644); err != nil {
-		return fmt.Errorf("failed to write .env.example file: %w", err)
+	if err := fsys.WriteFile(filePath, []byte(data), 0644); err != nil {
+		return fmt.Errorf("failed to write .env file: %w", err)
 	}
 
 	return nil
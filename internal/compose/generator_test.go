@@ -2,10 +2,19 @@ package compose
 
 import (
 	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/labels"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestGenerator_Generate(t *testing.T) {
@@ -86,17 +95,17 @@ services:
 
 func TestGenerator_GenerateEnvFile(t *testing.T) {
 	// Create a test project with resources
-	project := &WorkbenchProject{
+	project := &manifest.WorkbenchManifest{
 		APIVersion: "openworkbench.io/v1alpha1",
 		Kind:       "Project",
-		Metadata: ProjectMetadata{
+		Metadata: manifest.ProjectMetadata{
 			Name: "test-project",
 		},
-		Services: map[string]Service{
+		Services: map[string]manifest.Service{
 			"backend": {
 				Template: "fastapi-basic",
 				Path:     "./backend",
-				Resources: map[string]Resource{
+				Resources: map[string]manifest.Resource{
 					"database": {
 						Type:    "postgres",
 						Version: "15",
@@ -106,7 +115,7 @@ func TestGenerator_GenerateEnvFile(t *testing.T) {
 			"frontend": {
 				Template: "nextjs-golden-path",
 				Path:     "./frontend",
-				Resources: map[string]Resource{
+				Resources: map[string]manifest.Resource{
 					"cache": {
 						Type:    "redis",
 						Version: "7",
@@ -125,12 +134,511 @@ func TestGenerator_GenerateEnvFile(t *testing.T) {
 
 	// Verify backend database credentials
 	assert.Equal(t, "backend_user", envVars["backend_database_user"])
-	assert.Equal(t, "password123", envVars["backend_database_password"])
+	assert.Regexp(t, "^[A-Za-z0-9]{32}$", envVars["backend_database_password"])
 	assert.Equal(t, "backend_database", envVars["backend_database_name"])
 	assert.Equal(t, "backend_database_db", envVars["backend_database_dbname"])
 
 	// Verify frontend cache credentials
-	assert.Equal(t, "password123", envVars["frontend_cache_password"])
+	assert.Regexp(t, "^[A-Za-z0-9]{32}$", envVars["frontend_cache_password"])
+	assert.NotEqual(t, envVars["backend_database_password"], envVars["frontend_cache_password"])
+
+	// Passwords already in .env are kept, since the database was created with them
+	generator.SetSecretsProvider(GeneratedSecrets{Existing: map[string]string{"backend_database_password": "kept"}})
+	envVars, err = generator.GenerateEnvFile()
+	require.NoError(t, err)
+	assert.Equal(t, "kept", envVars["backend_database_password"])
+	assert.Regexp(t, "^[A-Za-z0-9]{32}$", envVars["frontend_cache_password"])
+}
+
+func TestSecretsProviders(t *testing.T) {
+	keys := []string{"api_db_password", "api_cache_password"}
+	environment := map[string]string{"API_DB_PASSWORD": "from-ci", "api_cache_password": "lower"}
+	values, err := EnvSecrets{Getenv: func(key string) string { return environment[key] }}.Secrets(keys)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"api_db_password": "from-ci", "api_cache_password": "lower"}, values)
+	_, err = EnvSecrets{Getenv: func(string) string { return "" }}.Secrets(keys)
+	assert.ErrorContains(t, err, "API_DB_PASSWORD, API_CACHE_PASSWORD are not set")
+
+	outputs := map[string]string{
+		"sops --decrypt --output-type json secrets.enc.yaml":                                          `{"api_db_password": "sops-db", "api_cache_password": "sops-cache"}`,
+		"vault kv get -format=json secret/shop":                                                       `{"data": {"data": {"api_db_password": "vault-db", "API_CACHE_PASSWORD": "vault-cache"}, "metadata": {"version": 3}}}`,
+		"aws secretsmanager get-secret-value --secret-id shop/dev --query SecretString --output text": `{"api_db_password": "aws-db"}`,
+	}
+	var dirs []string
+	run := func(dir, name string, args ...string) ([]byte, error) {
+		dirs = append(dirs, dir)
+		return []byte(outputs[strings.Join(append([]string{name}, args...), " ")]), nil
+	}
+	for _, tt := range []struct {
+		config   manifest.Secrets
+		expected map[string]string
+		err      string
+	}{
+		{config: manifest.Secrets{Provider: manifest.SecretsSOPS, Source: "secrets.enc.yaml"},
+			expected: map[string]string{"api_db_password": "sops-db", "api_cache_password": "sops-cache"}},
+		{config: manifest.Secrets{Provider: manifest.SecretsVault, Source: "secret/shop"},
+			expected: map[string]string{"api_db_password": "vault-db", "api_cache_password": "vault-cache"}},
+		{config: manifest.Secrets{Provider: manifest.SecretsAWS, Source: "shop/dev"},
+			err: "aws shop/dev has no api_cache_password"},
+	} {
+		t.Run(tt.config.Provider, func(t *testing.T) {
+			provider := NewSecretsProvider(tt.config, "project", SecretsSources{Run: run})
+			assert.Equal(t, tt.config.Provider, provider.Name())
+			values, err := provider.Secrets(keys)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, values)
+		})
+	}
+	assert.Equal(t, []string{"project", "project", "project"}, dirs)
+
+	provider := NewSecretsProvider(manifest.Secrets{}, "project", SecretsSources{Existing: map[string]string{"api_db_password": "kept"}})
+	values, err = provider.Secrets(keys)
+	require.NoError(t, err)
+	assert.Equal(t, "kept", values["api_db_password"])
+	assert.Len(t, values["api_cache_password"], 32)
+}
+
+func TestGenerator_TraefikGateway(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Components: map[string]manifest.Component{
+			"gateway": {Template: TraefikTemplate, Path: "./gateway"},
+		},
+		Services: map[string]manifest.Service{
+			"frontend": {Template: "react-typescript", Path: "./frontend", Port: 3000},
+			"backend":  {Template: "fastapi-basic", Path: "./backend", Port: 8000, Subdomain: "api"},
+			"worker":   {Template: "fastapi-basic", Path: "./worker"},
+			"orders":   {Template: "node-grpc", Path: "./orders", Port: 50051, Protocol: "grpc"},
+			"broker":   {Template: "fastapi-basic", Path: "./broker", Port: 1883, Protocol: "tcp"},
+		},
+	}
+
+	config, err := NewGenerator(project).Generate()
+	require.NoError(t, err)
+
+	// The gateway runs the Traefik image with the Docker provider
+	gateway := config.Services["gateway"]
+	assert.Nil(t, gateway.Build)
+	assert.Equal(t, traefikImage, gateway.Image)
+	assert.Equal(t, []string{"80:80", "8080:8080"}, gateway.Ports)
+	assert.Contains(t, gateway.Command, "--providers.docker=true")
+	assert.Contains(t, gateway.Volumes, "/var/run/docker.sock:/var/run/docker.sock:ro")
+
+	// Services with a port are routed by labels
+	assert.Contains(t, config.Services["frontend"].Labels, "traefik.http.routers.frontend.rule=Host(`frontend.localhost`)")
+	assert.Contains(t, config.Services["backend"].Labels, "traefik.http.routers.backend.rule=Host(`api.localhost`)")
+	assert.Contains(t, config.Services["backend"].Labels, "traefik.http.services.backend.loadbalancer.server.port=8000")
+	assert.Empty(t, traefikLabelsOf(config.Services["worker"]))
+	assert.Contains(t, config.Services["orders"].Labels, "traefik.http.services.orders.loadbalancer.server.scheme=h2c")
+	assert.Empty(t, traefikLabelsOf(config.Services["broker"]))
+
+	// Without a Traefik gateway no labels are added
+	delete(project.Components, "gateway")
+	config, err = NewGenerator(project).Generate()
+	require.NoError(t, err)
+	assert.Empty(t, traefikLabelsOf(config.Services["frontend"]))
+}
+
+// traefikLabelsOf returns the Traefik routing labels of a service
+func traefikLabelsOf(service DockerComposeService) []string {
+	var labels []string
+	for _, label := range service.Labels {
+		if strings.HasPrefix(label, "traefik.") {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+func TestGenerator_ProjectLabels(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "demo"},
+		Components: map[string]manifest.Component{
+			"gateway": {Template: "nginx-gateway", Path: "./gateway", Ownership: manifest.Ownership{Owner: "platform@example.com"}},
+		},
+		Services: map[string]manifest.Service{
+			"api": {Template: "fastapi-basic", Path: "./api", Resources: map[string]manifest.Resource{"db": {Type: "postgres-db", Version: "15"}},
+				Ownership: manifest.Ownership{Team: "payments", Tags: []string{"pci"}, Description: "Charges cards"}},
+		},
+	}
+
+	generator := NewGenerator(project)
+	generator.SetLabels(labels.Info{Version: "1.2.0", Environment: "staging"})
+	config, err := generator.Generate()
+	require.NoError(t, err)
+
+	// Containers and built images are tied to the project, their entry, and the run
+	api := map[string]string{
+		labels.Project:     "demo",
+		labels.Entry:       "services.api",
+		labels.Service:     "api",
+		labels.Template:    "fastapi-basic",
+		labels.Version:     "1.2.0",
+		labels.Environment: "staging",
+		labels.Team:        "payments",
+		labels.Tags:        "pci",
+	}
+	assert.Equal(t, api, config.Services["api"].Build.Labels)
+	assert.Subset(t, config.Services["api"].Labels, labels.List(api))
+	assert.Contains(t, config.Services["gateway"].Labels, labels.Entry+"=components.gateway")
+	assert.Contains(t, config.Services["gateway"].Labels, labels.Template+"=nginx-gateway")
+	assert.Contains(t, config.Services["api-db"].Labels, labels.Entry+"=services.api.resources.db")
+	assert.Contains(t, config.Services["api-db"].Labels, labels.Service+"=api")
+	assert.Contains(t, config.Services["gateway"].Labels, labels.Owner+"=platform@example.com")
+	assert.Contains(t, config.Services["api-db"].Labels, labels.Team+"=payments", "resources belong to the team of their service")
+
+	// So are the volumes of resources
+	volume := config.Volumes["api_db_data"].(map[string]interface{})["labels"].(map[string]string)
+	assert.Equal(t, "services.api.resources.db", volume[labels.Entry])
+	assert.Equal(t, "demo", volume[labels.Project])
+}
+
+func TestGenerator_GraphQLGateway(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Components: map[string]manifest.Component{
+			"graphql": {Template: GraphQLGatewayTemplate, Path: "./graphql"},
+		},
+		Services: map[string]manifest.Service{
+			"users":    {Template: "fastapi-basic", Path: "./users", Port: 8000, GraphQL: &manifest.GraphQL{Path: "/graphql"}},
+			"products": {Template: "express-api", Path: "./products", Port: 3001, GraphQL: &manifest.GraphQL{Path: "/api/graphql"}},
+			"frontend": {Template: "react-typescript", Path: "./frontend", Port: 4173},
+		},
+	}
+	generator := NewGenerator(project)
+
+	config, err := generator.Generate()
+	require.NoError(t, err)
+	gateway := config.Services["graphql"]
+	assert.Equal(t, []string{"4000:4000"}, gateway.Ports)
+	assert.Equal(t, []string{"products", "users"}, gateway.DependsOn)
+
+	meshPath := filepath.Join("graphql", MeshConfigFile)
+	meshConfigs := generator.GenerateMeshConfigs()
+	require.Contains(t, meshConfigs, meshPath)
+	meshConfig := meshConfigs[meshPath]
+	require.Len(t, meshConfig.Sources, 2)
+	assert.Equal(t, "products", meshConfig.Sources[0].Name)
+	assert.Equal(t, "http://products:3001/api/graphql", meshConfig.Sources[0].Handler.GraphQL.Endpoint)
+	assert.Equal(t, "http://users:8000/graphql", meshConfig.Sources[1].Handler.GraphQL.Endpoint)
+
+	memFS := filesystem.NewMemFS()
+	require.NoError(t, WriteMeshConfig(memFS, meshConfig, meshPath))
+	data, err := memFS.ReadFile(meshPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "endpoint: http://users:8000/graphql")
+}
+
+func TestGenerator_APIDocs(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Services: map[string]manifest.Service{
+			"users":    {Template: "fastapi-basic", Path: "./users", Port: 8000, API: &manifest.API{Spec: "openapi.yaml"}},
+			"orders":   {Template: "express-api", Path: "./orders", Port: 3001, API: &manifest.API{Spec: "docs/openapi.json"}},
+			"frontend": {Template: "react-typescript", Path: "./frontend", Port: 4173},
+		},
+	}
+
+	config, err := NewGenerator(project).Generate()
+	require.NoError(t, err)
+
+	docs, exists := config.Services[APIDocsServiceName]
+	require.True(t, exists)
+	assert.Equal(t, apiDocsImage, docs.Image)
+	assert.Equal(t, []string{"8088:8080"}, docs.Ports)
+	assert.Equal(t, []string{
+		"./orders/docs/openapi.json:/usr/share/nginx/html/specs/orders.json:ro",
+		"./users/openapi.yaml:/usr/share/nginx/html/specs/users.yaml:ro",
+	}, docs.Volumes)
+	assert.Equal(t, []string{`URLS=[{"url":"specs/orders.json","name":"orders"},{"url":"specs/users.yaml","name":"users"}]`}, docs.Environment)
+
+	// No specs, no portal
+	delete(project.Services, "users")
+	delete(project.Services, "orders")
+	config, err = NewGenerator(project).Generate()
+	require.NoError(t, err)
+	assert.NotContains(t, config.Services, APIDocsServiceName)
+}
+
+func TestGenerator_WarnsAboutResourcesWithoutBlueprint(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Services: map[string]manifest.Service{
+			"api": {Template: "express-api", Path: "./api", Port: 3001, Resources: map[string]manifest.Resource{
+				"db":     {Type: "postgres-db", Version: "16"},
+				"search": {Type: "elasticsearch"},
+			}},
+		},
+	}
+
+	generator := NewGenerator(project)
+	config, err := generator.Generate()
+	require.NoError(t, err)
+	assert.Equal(t, "elasticsearch:latest", config.Services["api-search"].Image)
+
+	warnings := generator.Warnings()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "services.api.resources.search", warnings[0].Entry)
+	assert.Contains(t, warnings[0].Message, "elasticsearch:latest")
+}
+
+func TestGenerator_ComponentAndResourceEnvironment(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Components: map[string]manifest.Component{
+			"gateway": {Template: "nginx-gateway", Path: "./gateway", Ports: []string{"8080:80"}, Environment: map[string]string{
+				"UPSTREAM":    "http://${services.api.name}:3001",
+				"WORKER_PROC": "2",
+			}},
+		},
+		Services: map[string]manifest.Service{
+			"api": {Template: "express-api", Path: "./api", Port: 3001, Resources: map[string]manifest.Resource{
+				"db": {Type: "postgres-db", Version: "16", Config: map[string]string{"databaseName": "app", "username": "app", "password": "secret"},
+					Environment: map[string]string{
+						"POSTGRES_INITDB_ARGS": "--data-checksums",
+						"POSTGRES_DB":          "override",
+					}},
+			}},
+		},
+	}
+
+	generator := NewGenerator(project)
+	config, err := generator.Generate()
+	require.NoError(t, err)
+
+	gateway := config.Services["gateway"]
+	assert.Equal(t, []string{"UPSTREAM=http://${services.api.name}:3001", "WORKER_PROC=2"}, gateway.Environment)
+	assert.Equal(t, []string{"./.env"}, gateway.EnvFile)
+	assert.Equal(t, []string{"api"}, gateway.DependsOn)
+
+	db := config.Services["api-db"]
+	assert.Contains(t, db.Environment, "POSTGRES_INITDB_ARGS=--data-checksums")
+	assert.Contains(t, db.Environment, "POSTGRES_DB=override")
+	assert.NotContains(t, db.Environment, "POSTGRES_DB=app")
+	assert.Contains(t, db.Environment, "POSTGRES_USER=app")
+}
+
+func TestGenerator_ResourceExpose(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Services: map[string]manifest.Service{
+			"api": {Template: "express-api", Path: "./api", Port: 3001, Resources: map[string]manifest.Resource{
+				"db":        {Type: "postgres-db", Version: "16"},
+				"reporting": {Type: "postgres-db", Version: "16", Expose: manifest.ExposeHost},
+			}},
+		},
+	}
+
+	config, err := NewGenerator(project).Generate()
+	require.NoError(t, err)
+
+	assert.Empty(t, config.Services["api-db"].Ports, "internal resources are reached over the compose network only")
+	assert.Equal(t, []string{"5432:5432"}, config.Services["api-reporting"].Ports, "host resources keep the ports of their blueprint")
+}
+
+func TestGenerator_CommandOverrides(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Components: map[string]manifest.Component{
+			"gateway": {Template: "nginx-gateway", Path: "./gateway", Entrypoint: []string{"/docker-entrypoint.sh"}},
+		},
+		Services: map[string]manifest.Service{
+			"api": {Template: "fastapi-basic", Path: "./api", Port: 8000, Command: []string{"uvicorn", "app:app", "--reload", "--port", "8000"}},
+			"web": {Template: "react-typescript", Path: "./web", Port: 4173},
+		},
+	}
+
+	config, err := NewGenerator(project).Generate()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"uvicorn", "app:app", "--reload", "--port", "8000"}, config.Services["api"].Command)
+	assert.Empty(t, config.Services["web"].Command)
+	assert.Equal(t, []string{"/docker-entrypoint.sh"}, config.Services["gateway"].Entrypoint)
+}
+
+func TestGenerator_RestartAndInit(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Components: map[string]manifest.Component{
+			"gateway": {Template: "nginx-gateway", Path: "./gateway", Restart: "unless-stopped"},
+		},
+		Services: map[string]manifest.Service{
+			"api":    {Template: "fastapi-basic", Path: "./api", Port: 8000, Restart: "on-failure", Init: true},
+			"worker": {Template: "express-api", Path: "./worker", Restart: "no"},
+		},
+	}
+
+	config, err := NewGenerator(project).Generate()
+	require.NoError(t, err)
+	assert.Equal(t, "on-failure", config.Services["api"].Restart)
+	assert.True(t, config.Services["api"].Init)
+	assert.False(t, config.Services["worker"].Init)
+	assert.Equal(t, "unless-stopped", config.Services["gateway"].Restart)
+
+	// "no" must stay a string rather than become a YAML 1.1 boolean
+	fsys := filesystem.NewMemFS()
+	require.NoError(t, WriteDockerCompose(fsys, config, "docker-compose.yml"))
+	data, err := fsys.ReadFile("docker-compose.yml")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `restart: "no"`)
+	assert.Contains(t, string(data), "init: true")
+}
+
+func TestGenerator_ServiceURLs(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Services: map[string]manifest.Service{
+			"api":   {Template: "fastapi-basic", Path: "./api", Port: 8000},
+			"users": {Template: "node-grpc", Path: "./users", Port: 50051, Protocol: "grpc"},
+			"web": {Template: "react-typescript", Path: "./web", Port: 4173, Environment: map[string]string{
+				"API_API_URL":   "${services.api.url}",
+				"USERS_API_URL": "${services.users.url}",
+			}},
+		},
+	}
+
+	config, err := NewGenerator(project).Generate()
+	require.NoError(t, err)
+	assert.Contains(t, config.Services["web"].Environment, "API_API_URL=http://api:8000")
+	assert.Contains(t, config.Services["web"].Environment, "USERS_API_URL=users:50051")
+	assert.ElementsMatch(t, []string{"api", "users"}, config.Services["web"].DependsOn)
+}
+
+func TestGenerator_Libraries(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Services: map[string]manifest.Service{
+			"api":    {Template: "express-api", Path: "./api", Port: 3001, Libraries: []string{"models"}},
+			"models": {Path: "shared/models", Kind: manifest.ServiceKindLibrary},
+			"web":    {Template: "react-typescript", Path: "./web", Port: 4173},
+		},
+	}
+
+	config, err := NewGenerator(project).Generate()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"models": "shared/models"}, config.Services["api"].Build.AdditionalContexts)
+	assert.Nil(t, config.Services["web"].Build.AdditionalContexts)
+	assert.NotContains(t, config.Services, "models", "shared libraries never run on their own")
+}
+
+func TestGenerator_Security(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Services: map[string]manifest.Service{
+			"api": {Template: "fastapi-basic", Path: "./api", Port: 8000, Security: &manifest.Security{
+				ReadOnlyRootFilesystem: true,
+				CapDrop:                []string{"ALL"},
+				User:                   "1000:1000",
+				NoNewPrivileges:        true,
+			}},
+			"web": {Template: "react-typescript", Path: "./web", Port: 4173},
+		},
+		Components: map[string]manifest.Component{
+			"gateway": {Template: "nginx-gateway", Path: "./gateway", Security: &manifest.Security{
+				CapDrop:         []string{"ALL"},
+				NoNewPrivileges: true,
+			}},
+		},
+	}
+
+	config, err := NewGenerator(project).Generate()
+	require.NoError(t, err)
+	api := config.Services["api"]
+	assert.True(t, api.ReadOnly)
+	assert.Equal(t, []string{"ALL"}, api.CapDrop)
+	assert.Equal(t, "1000:1000", api.User)
+	assert.Equal(t, []string{"no-new-privileges:true"}, api.SecurityOpt)
+
+	web := config.Services["web"]
+	assert.False(t, web.ReadOnly)
+	assert.Empty(t, web.SecurityOpt)
+
+	gateway := config.Services["gateway"]
+	assert.Equal(t, []string{"ALL"}, gateway.CapDrop)
+	assert.Equal(t, []string{"no-new-privileges:true"}, gateway.SecurityOpt)
+}
+
+func TestGenerator_PrebuiltImage(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Services: map[string]manifest.Service{
+			"api":  {Template: "express-api", Path: "./api", Port: 3001},
+			"auth": {Image: "registry.internal/auth:1.4.2", Port: 9000},
+		},
+	}
+
+	generator := NewGenerator(project)
+	config, err := generator.Generate()
+	require.NoError(t, err)
+
+	auth := config.Services["auth"]
+	assert.Equal(t, "registry.internal/auth:1.4.2", auth.Image)
+	assert.Nil(t, auth.Build)
+	assert.Equal(t, []string{"9000:9000"}, auth.Ports)
+
+	api := config.Services["api"]
+	assert.Empty(t, api.Image)
+	require.NotNil(t, api.Build)
+	assert.Equal(t, "./api", api.Build.Context)
+}
+
+func TestGenerator_MockExternal(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Services: map[string]manifest.Service{
+			"api": {Template: "express-api", Path: "./api", Port: 3001, Environment: map[string]string{
+				"STRIPE_URL":   "${external.stripe.url}/v1",
+				"SENDGRID_URL": "${external.sendgrid.url}",
+			}},
+		},
+		External: map[string]manifest.External{
+			"stripe":   {URL: "https://api.stripe.com", Spec: "mocks/stripe.yaml"},
+			"sendgrid": {URL: "https://api.sendgrid.com"},
+		},
+	}
+
+	config, err := NewGenerator(project).Generate()
+	require.NoError(t, err)
+
+	mock, exists := config.Services["mock-stripe"]
+	require.True(t, exists)
+	assert.Equal(t, prismImage, mock.Image)
+	assert.Equal(t, []string{"./mocks/stripe.yaml:/tmp/stripe.yaml:ro"}, mock.Volumes)
+	assert.Equal(t, []string{"mock", "-h", "0.0.0.0", "-p", "4010", "/tmp/stripe.yaml"}, mock.Command)
+	assert.NotContains(t, config.Services, "mock-sendgrid")
+
+	api := config.Services["api"]
+	assert.Contains(t, api.Environment, "STRIPE_URL=http://mock-stripe:4010/v1")
+	assert.Contains(t, api.Environment, "SENDGRID_URL=https://api.sendgrid.com")
+	assert.Equal(t, []string{"mock-stripe"}, api.DependsOn)
+}
+
+func TestWriteDockerCompose_Markers(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata:   manifest.ProjectMetadata{Name: "test-project"},
+		Components: map[string]manifest.Component{"gateway": {Template: TraefikTemplate, Path: "./gateway"}},
+		Services: map[string]manifest.Service{
+			"api": {Template: "express-api", Path: "./api", Port: 3001, Resources: map[string]manifest.Resource{
+				"db": {Type: "postgres-db"},
+			}},
+		},
+	}
+
+	config, err := NewGenerator(project).Generate()
+	require.NoError(t, err)
+
+	fsys := filesystem.NewMemFS()
+	require.NoError(t, WriteDockerCompose(fsys, config, "docker-compose.yml"))
+	data, err := fsys.ReadFile("docker-compose.yml")
+	require.NoError(t, err)
+
+	content := string(data)
+	assert.Contains(t, content, "    # om: services.api (service)\n    api:\n")
+	assert.Contains(t, content, "    # om: services.api.resources.db (resource)\n    api-db:\n")
+	assert.Contains(t, content, "    # om: components.gateway (traefik-gateway)\n    gateway:\n")
 }
 
 func TestPrerequisiteChecker_CheckDockerCompose(t *testing.T) {
@@ -182,7 +690,7 @@ func TestSaveAndLoadFiles(t *testing.T) {
 	defer os.Remove("test.env")
 
 	// Test saving .env.example file
-	err = SaveEnvExampleFile(envVars, "test.env.example")
+	err = SaveEnvExampleFile(nil, envVars, "test.env.example")
 	require.NoError(t, err)
 	defer os.Remove("test.env.example")
 
@@ -196,3 +704,315 @@ func TestSaveAndLoadFiles(t *testing.T) {
 	_, err = os.Stat("test.env.example")
 	assert.NoError(t, err)
 }
+
+func TestWriteEnvExampleFile(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Services: map[string]manifest.Service{
+			"web": {Path: "./web", Resources: map[string]manifest.Resource{
+				"cache": {Type: "redis-cache", EnvDocs: map[string]string{"password": "Shared with the worker"}},
+			}},
+			"api": {Path: "./api", Resources: map[string]manifest.Resource{
+				"db":    {Type: "postgres-db", Version: "15"},
+				"queue": {Type: "rabbitmq"},
+			}},
+		},
+	}
+	sections := EnvSections(project)
+	require.Len(t, sections, 2)
+	assert.Equal(t, "Required by api.db (postgres 15)", sections[0].Title)
+	assert.Equal(t, "Required by web.cache (redis)", sections[1].Title)
+
+	generator := NewGenerator(project)
+	envVars, err := generator.GenerateEnvFile()
+	require.NoError(t, err)
+	envVars["EXTRA"] = "value"
+
+	fsys := filesystem.NewMemFS()
+	require.NoError(t, WriteEnvExampleFile(fsys, sections, envVars, ".env.example"))
+	data, err := fsys.ReadFile(".env.example")
+	require.NoError(t, err)
+	assert.Equal(t, `# Required by api.db (postgres 15)
+# Database the service's tables are created in
+api_db_dbname=
+# Name of the database resource
+api_db_name=
+# Password of the user
+api_db_password=
+# User the service connects to the database as
+api_db_user=
+
+# Required by web.cache (redis)
+# Shared with the worker
+web_cache_password=
+
+EXTRA=
+`, string(data))
+}
+
+func TestGenerator_Interpolation(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Environments: map[string]manifest.Environment{
+			"dev": {Provider: "aws", Config: map[string]string{"logLevel": "debug"}},
+		},
+		Infra: map[string]string{"api_url": "${services.api.url}"},
+		Services: map[string]manifest.Service{
+			"api": {Template: "express-api", Path: "./api", Port: 3001},
+			"worker": {Template: "express-api", Path: "./worker", Environment: map[string]string{
+				"API_URL":   "${infra.api_url}",
+				"LOG_LEVEL": "${env.logLevel}",
+			}, Resources: map[string]manifest.Resource{
+				"db": {Type: "postgres-db", Config: map[string]string{"databaseName": "app", "username": "app", "password": "secret"}, Environment: map[string]string{
+					"API_URL":      "${infra.api_url}",
+					"CALLBACK_URL": "${services.api.url}/callbacks",
+					"PGAPPNAME":    "worker-${env.logLevel}",
+				}},
+			}},
+		},
+	}
+
+	generator := NewGenerator(project)
+	generator.SetLabels(labels.Info{Environment: "dev"})
+	config, err := generator.Generate()
+	require.NoError(t, err)
+	worker := config.Services["worker"]
+	assert.Equal(t, []string{"API_URL=http://api:3001", "LOG_LEVEL=debug"}, worker.Environment)
+	assert.Equal(t, []string{"api"}, worker.DependsOn)
+	db := config.Services["worker-db"]
+	assert.Contains(t, db.Environment, "API_URL=http://api:3001")
+	assert.Contains(t, db.Environment, "CALLBACK_URL=http://api:3001/callbacks")
+	assert.Contains(t, db.Environment, "PGAPPNAME=worker-debug")
+	assert.Empty(t, generator.Warnings())
+
+	generator = NewGenerator(project)
+	config, err = generator.Generate()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"API_URL=http://api:3001"}, config.Services["worker"].Environment)
+	assert.NotContains(t, strings.Join(config.Services["worker-db"].Environment, "\n"), "PGAPPNAME")
+	warnings := generator.Warnings()
+	require.Len(t, warnings, 2)
+	assert.Equal(t, "services.worker", warnings[0].Entry)
+	assert.Contains(t, warnings[0].Message, "LOG_LEVEL")
+	assert.Equal(t, "services.worker.resources.db", warnings[1].Entry)
+	assert.Contains(t, warnings[1].Message, "PGAPPNAME")
+}
+
+func TestGenerator_WarnsAboutBlueprintsThatFailToRender(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Services: map[string]manifest.Service{
+			"api": {Template: "express-api", Path: "./api", Port: 3001, Resources: map[string]manifest.Resource{
+				"db": {Type: "postgres-db", Version: "16", Config: map[string]string{"password": "secret\n  ports: ["}},
+			}},
+		},
+	}
+
+	generator := NewGenerator(project)
+	config, err := generator.Generate()
+	require.NoError(t, err)
+	assert.Equal(t, "postgres:16", config.Services["api-db"].Image)
+
+	warnings := generator.Warnings()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "services.api.resources.db", warnings[0].Entry)
+	assert.Contains(t, warnings[0].Message, "could not be applied")
+}
+
+func TestGenerator_DependsOn(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Services: map[string]manifest.Service{
+			"api":     {Template: "express-api", Path: "./api", Port: 3001, Health: &manifest.Health{Path: "/health"}},
+			"migrate": {Template: "express-api", Path: "./migrate"},
+			"web": {Template: "express-api", Path: "./web", Port: 3000,
+				Environment: map[string]string{"API_URL": "${services.api.url}"},
+				DependsOn: []manifest.Dependency{
+					{Service: "api"},
+					{Service: "migrate", Condition: manifest.DependencyCompleted},
+				}},
+		},
+	}
+
+	config, err := NewGenerator(project).Generate()
+	require.NoError(t, err)
+	web := config.Services["web"]
+	assert.Equal(t, []string{"api", "migrate"}, web.DependsOn)
+	assert.Equal(t, map[string]string{"api": ConditionHealthy, "migrate": ConditionCompleted}, web.Conditions)
+	require.NotNil(t, config.Services["api"].Healthcheck)
+	assert.Contains(t, config.Services["api"].Healthcheck.Test[1], "http://localhost:3001/health")
+	assert.Nil(t, config.Services["migrate"].Healthcheck)
+
+	fsys := filesystem.NewMemFS()
+	require.NoError(t, WriteDockerCompose(fsys, config, "docker-compose.yml"))
+	data, err := fsys.ReadFile("docker-compose.yml")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "        depends_on:\n            api:\n                condition: service_healthy\n            migrate:\n                condition: service_completed_successfully\n")
+
+	// The written mapping reads back as the dependencies and their conditions
+	var written DockerComposeConfig
+	require.NoError(t, yaml.Unmarshal(data, &written))
+	assert.Equal(t, web.DependsOn, written.Services["web"].DependsOn)
+	assert.Equal(t, web.Conditions, written.Services["web"].Conditions)
+	assert.Equal(t, config.Services["api"].Healthcheck, written.Services["api"].Healthcheck)
+}
+
+func TestHostPath(t *testing.T) {
+	tests := []struct {
+		environment string
+		path        string
+		expected    string
+	}{
+		{templating.EnvironmentNative, "./api", "./api"},
+		{templating.EnvironmentNative, `services\api`, "services/api"},
+		{templating.EnvironmentWindows, `C:\work\shop\api`, "C:/work/shop/api"},
+		{templating.EnvironmentWSL, `C:\work\shop\api`, "/mnt/c/work/shop/api"},
+		{templating.EnvironmentWSL, "/home/dev/shop/api", "/home/dev/shop/api"},
+		{templating.EnvironmentGitBash, "/c/work/shop/api", "C:/work/shop/api"},
+		{templating.EnvironmentGitBash, "/cygdrive/d", "D:/"},
+		{templating.EnvironmentGitBash, "/var/run/docker.sock", "/var/run/docker.sock"},
+	}
+	for _, tt := range tests {
+		if got := hostPath(tt.environment, tt.path); got != tt.expected {
+			t.Errorf("hostPath(%q, %q) = %q, want %q", tt.environment, tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestGenerator_HostPaths(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Services: map[string]manifest.Service{
+			"api":    {Template: "express-api", Path: `C:\work\shop\api`, Port: 3001, Libraries: []string{"shared"}},
+			"shared": {Path: `libs\shared`, Kind: manifest.ServiceKindLibrary},
+		},
+		External: map[string]manifest.External{
+			"stripe": {URL: "https://api.stripe.com", Spec: `specs\stripe.yaml`},
+		},
+	}
+
+	gen := NewGenerator(project)
+	gen.SetHost(templating.EnvironmentWSL, "/home/dev/shop")
+	config, err := gen.Generate()
+	require.NoError(t, err)
+	api := config.Services["api"]
+	assert.Equal(t, "/mnt/c/work/shop/api", api.Build.Context)
+	assert.Equal(t, map[string]string{"shared": "libs/shared"}, api.Build.AdditionalContexts)
+	assert.Equal(t, []string{"./specs/stripe.yaml:/tmp/stripe.yaml:ro"}, config.Services[MockServiceName("stripe")].Volumes)
+	assert.Empty(t, gen.Warnings())
+
+	// A project on a Windows drive is slow to read from WSL
+	gen.SetHost(templating.EnvironmentWSL, "/mnt/c/work/shop")
+	_, err = gen.Generate()
+	require.NoError(t, err)
+	require.Len(t, gen.Warnings(), 1)
+	assert.Contains(t, gen.Warnings()[0].Message, "Move it into the Linux filesystem")
+
+	// Docker Desktop shares only C:\Users by default, and no network shares
+	gen.SetHost(templating.EnvironmentGitBash, "/d/work/shop")
+	_, err = gen.Generate()
+	require.NoError(t, err)
+	require.Len(t, gen.Warnings(), 1)
+	assert.Contains(t, gen.Warnings()[0].Message, "D:/work/shop")
+	assert.Contains(t, gen.Warnings()[0].Message, "File sharing")
+
+	gen.SetHost(templating.EnvironmentWindows, `C:\Users\dev\shop`)
+	_, err = gen.Generate()
+	require.NoError(t, err)
+	assert.Empty(t, gen.Warnings())
+
+	gen.SetHost(templating.EnvironmentWindows, `\\fileserver\projects\shop`)
+	_, err = gen.Generate()
+	require.NoError(t, err)
+	require.Len(t, gen.Warnings(), 1)
+	assert.Contains(t, gen.Warnings()[0].Message, "network share")
+}
+
+func TestDetectEngine(t *testing.T) {
+	tests := []struct {
+		name     string
+		goos     string
+		env      map[string]string
+		binaries []string
+		output   map[string]string // output of docker commands
+		engine   string
+		compose  string
+		socket   string
+	}{
+		{name: "docker", goos: "linux", binaries: []string{"docker"}, output: map[string]string{"context show": "default"},
+			engine: EngineDocker, compose: "docker compose", socket: DockerSocket},
+		{name: "colima context", goos: "darwin", env: map[string]string{"HOME": "/Users/dev"}, binaries: []string{"docker", "docker-compose"}, output: map[string]string{"context show": "colima"},
+			engine: EngineColima, compose: "docker-compose", socket: DockerSocket},
+		{name: "colima host", goos: "darwin", env: map[string]string{"DOCKER_HOST": "unix:///Users/dev/.colima/default/docker.sock"},
+			engine: EngineColima, compose: "docker compose", socket: DockerSocket},
+		{name: "podman-docker", goos: "linux", env: map[string]string{"XDG_RUNTIME_DIR": "/run/user/1000"}, binaries: []string{"docker", "podman", "podman-compose"},
+			output: map[string]string{"--version": "podman version 5.2.2"}, engine: EnginePodman, compose: "podman-compose", socket: "/run/user/1000/podman/podman.sock"},
+		{name: "podman only", goos: "linux", binaries: []string{"podman"},
+			engine: EnginePodman, compose: "podman compose", socket: "/run/podman/podman.sock"},
+		{name: "podman host", goos: "linux", env: map[string]string{"DOCKER_HOST": "unix:///run/user/1000/podman/podman.sock"},
+			engine: EnginePodman, compose: "podman compose", socket: "/run/user/1000/podman/podman.sock"},
+		{name: "podman machine", goos: "darwin", env: map[string]string{"HOME": "/Users/dev"}, binaries: []string{"podman"},
+			engine: EnginePodman, compose: "podman compose", socket: "/run/podman/podman.sock"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := DetectEngine(EngineProbe{
+				GOOS:   tt.goos,
+				Getenv: func(key string) string { return tt.env[key] },
+				LookPath: func(name string) (string, error) {
+					if slices.Contains(tt.binaries, name) {
+						return "/usr/bin/" + name, nil
+					}
+					return "", exec.ErrNotFound
+				},
+				Run: func(dir, name string, args ...string) ([]byte, error) {
+					return []byte(tt.output[strings.Join(args, " ")]), nil
+				},
+			})
+			assert.Equal(t, tt.engine, engine.Name)
+			assert.Equal(t, tt.compose, engine.Compose)
+			assert.Equal(t, tt.socket, engine.Socket)
+			if tt.engine == EngineDocker {
+				assert.Empty(t, engine.Limitations)
+			} else {
+				assert.NotEmpty(t, engine.Limitations)
+			}
+		})
+	}
+}
+
+func TestGenerator_Engine(t *testing.T) {
+	project := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "test-project"},
+		Components: map[string]manifest.Component{
+			"gateway": {Template: TraefikTemplate, Path: "./gateway"},
+		},
+		Services: map[string]manifest.Service{
+			"api": {Template: "express-api", Path: "./api", Port: 3001},
+		},
+		External: map[string]manifest.External{
+			"stripe": {URL: "https://api.stripe.com", Spec: "specs/stripe.yaml"},
+		},
+	}
+
+	gen := NewGenerator(project)
+	config, err := gen.Generate()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/var/run/docker.sock:/var/run/docker.sock:ro"}, config.Services["gateway"].Volumes)
+
+	gen.SetEngine(Engine{Name: EnginePodman, Socket: "/run/user/1000/podman/podman.sock", Shares: []string{"/Users/dev"}})
+	gen.SetHost(templating.EnvironmentNative, "/Users/dev/shop")
+	config, err = gen.Generate()
+	require.NoError(t, err)
+	gateway := config.Services["gateway"]
+	assert.Equal(t, []string{"/run/user/1000/podman/podman.sock:/var/run/docker.sock:ro"}, gateway.Volumes)
+	assert.Equal(t, []string{"label=disable"}, gateway.SecurityOpt)
+	assert.Empty(t, gen.Warnings())
+
+	// Bind mounts of a project the VM does not mount come up empty
+	gen.SetHost(templating.EnvironmentNative, "/opt/shop")
+	_, err = gen.Generate()
+	require.NoError(t, err)
+	require.Len(t, gen.Warnings(), 1)
+	assert.Contains(t, gen.Warnings()[0].Message, "outside the directories podman mounts")
+}
@@ -0,0 +1,134 @@
+package compose
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"gopkg.in/yaml.v3"
+)
+
+// GraphQLGatewayTemplate is the component template that stitches the schemas
+// of every service with a graphql section into one endpoint using GraphQL Mesh
+const GraphQLGatewayTemplate = "graphql-gateway"
+
+// MeshConfigFile is the GraphQL Mesh configuration written into the
+// directory of each graphql-gateway component
+const MeshConfigFile = ".meshrc.yaml"
+
+// graphQLGatewayPort is the port GraphQL Mesh serves the combined schema on
+const graphQLGatewayPort = 4000
+
+// MeshConfig represents the generated .meshrc.yaml
+type MeshConfig struct {
+	Sources []MeshSource `yaml:"sources"`
+	Serve   MeshServe    `yaml:"serve"`
+}
+
+// MeshSource is one stitched GraphQL API
+type MeshSource struct {
+	Name    string      `yaml:"name"`
+	Handler MeshHandler `yaml:"handler"`
+}
+
+// MeshHandler tells GraphQL Mesh how to reach a source
+type MeshHandler struct {
+	GraphQL MeshGraphQLHandler `yaml:"graphql"`
+}
+
+// MeshGraphQLHandler points at a GraphQL endpoint
+type MeshGraphQLHandler struct {
+	Endpoint string `yaml:"endpoint"`
+}
+
+// MeshServe configures the gateway's own server
+type MeshServe struct {
+	Port int `yaml:"port"`
+}
+
+// GenerateMeshConfigs returns the GraphQL Mesh configuration for every
+// graphql-gateway component, keyed by the path of its config file relative to
+// the project root
+func (g *Generator) GenerateMeshConfigs() map[string]*MeshConfig {
+	configs := make(map[string]*MeshConfig)
+	for _, component := range g.project.Components {
+		if component.Template != GraphQLGatewayTemplate {
+			continue
+		}
+		configs[filepath.Join(component.Path, MeshConfigFile)] = &MeshConfig{
+			Sources: g.graphQLSources(),
+			Serve:   MeshServe{Port: graphQLGatewayPort},
+		}
+	}
+	return configs
+}
+
+// graphQLSources lists the services with a graphql section, sorted by name
+func (g *Generator) graphQLSources() []MeshSource {
+	sources := []MeshSource{}
+	for _, name := range g.graphQLServiceNames() {
+		service := g.project.Services[name]
+		host := name
+		if service.Port > 0 {
+			host = fmt.Sprintf("%s:%d", name, service.Port)
+		}
+		sources = append(sources, MeshSource{
+			Name: name,
+			Handler: MeshHandler{GraphQL: MeshGraphQLHandler{
+				Endpoint: fmt.Sprintf("http://%s%s", host, service.GraphQL.Path),
+			}},
+		})
+	}
+	return sources
+}
+
+// graphQLServiceNames returns the sorted names of services with a graphql section
+func (g *Generator) graphQLServiceNames() []string {
+	var names []string
+	for name, service := range g.project.Services {
+		if service.GraphQL != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// createGraphQLGatewayService creates the Docker Compose service for a
+// graphql-gateway component, which starts after the services it stitches
+func (g *Generator) createGraphQLGatewayService(component manifest.Component) DockerComposeService {
+	ports := component.Ports
+	if len(ports) == 0 {
+		ports = []string{fmt.Sprintf("%d:%d", graphQLGatewayPort, graphQLGatewayPort)}
+	}
+
+	return DockerComposeService{
+		Build: &BuildConfig{
+			Context: component.Path,
+		},
+		Ports:     append([]string(nil), ports...),
+		Networks:  []string{"workbench_net"},
+		DependsOn: g.graphQLServiceNames(),
+	}
+}
+
+// WriteMeshConfig writes a GraphQL Mesh configuration file
+func WriteMeshConfig(fsys filesystem.FS, config *MeshConfig, filePath string) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal GraphQL Mesh config: %w", err)
+	}
+
+	header := "# THIS FILE IS AUTO-GENERATED BY 'om compose' from the graphql sections of workbench.yaml.\n\n"
+	data = append([]byte(header), data...)
+
+	if err := fsys.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(filePath), err)
+	}
+	if err := fsys.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", MeshConfigFile, err)
+	}
+	return nil
+}
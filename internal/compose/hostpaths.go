@@ -0,0 +1,154 @@
+package compose
+
+import (
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/templating"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+)
+
+// SetHost sets the environment om runs in, one of the templating.Environment
+// constants, and the absolute directory of the project. Paths in the
+// generated file are written the way Docker reads them from that
+// environment, and known file sharing problems of the directory are warned
+// about.
+func (g *Generator) SetHost(environment, projectDir string) {
+	g.host = environment
+	g.projectDir = projectDir
+}
+
+// translateHostPaths rewrites the build contexts, bind mount sources, and
+// env files of the configuration for the host environment
+func (g *Generator) translateHostPaths(config *DockerComposeConfig) {
+	for name, service := range config.Services {
+		if service.Build != nil {
+			service.Build.Context = hostPath(g.host, service.Build.Context)
+			for context, path := range service.Build.AdditionalContexts {
+				service.Build.AdditionalContexts[context] = hostPath(g.host, path)
+			}
+		}
+		for i, volume := range service.Volumes {
+			if source, rest := splitVolume(volume); isBindSource(source) {
+				service.Volumes[i] = hostPath(g.host, source) + rest
+			}
+		}
+		for i, envFile := range service.EnvFile {
+			service.EnvFile[i] = hostPath(g.host, envFile)
+		}
+		config.Services[name] = service
+	}
+}
+
+// hostWarnings returns the known Docker Desktop file sharing problems of the
+// project directory in the host environment
+func (g *Generator) hostWarnings(config *DockerComposeConfig) []warnings.Warning {
+	dir := hostPath(g.host, g.projectDir)
+	switch g.host {
+	case templating.EnvironmentWSL:
+		if _, _, ok := wslDrive(dir); ok {
+			return []warnings.Warning{warnings.New("",
+				"the project is on a Windows drive (%s); Docker reads it from WSL through a slow file share, so builds and bind mounts are slow and file changes do not reach containers. Move it into the Linux filesystem, such as ~/projects", dir)}
+		}
+	case templating.EnvironmentWindows, templating.EnvironmentGitBash:
+		if strings.HasPrefix(dir, "//") {
+			return []warnings.Warning{warnings.New("",
+				"the project is on a network share (%s), which Docker Desktop cannot mount into containers; move it to a local drive", dir)}
+		}
+		if hasBindMounts(config) && !strings.HasPrefix(strings.ToLower(dir), "c:/users/") {
+			return []warnings.Warning{warnings.New("",
+				"the project (%s) is outside C:\\Users, the only directory Docker Desktop shares by default with the Hyper-V backend; if bind mounts come up empty, add it under Settings > Resources > File sharing or switch to the WSL 2 backend", dir)}
+		}
+	}
+	return nil
+}
+
+// hostPath returns a path the way Docker reads it from the host environment:
+// with forward slashes, Windows drives as /mnt/c under WSL, and MSYS drives
+// such as /c as C: under Git Bash
+func hostPath(environment, path string) string {
+	path = strings.ReplaceAll(path, `\`, "/")
+	switch environment {
+	case templating.EnvironmentWSL:
+		if drive, rest, ok := windowsDrive(path); ok {
+			return "/mnt/" + strings.ToLower(drive) + rest
+		}
+	case templating.EnvironmentGitBash:
+		if drive, rest, ok := msysDrive(path); ok {
+			if rest == "" {
+				rest = "/"
+			}
+			return strings.ToUpper(drive) + ":" + rest
+		}
+	}
+	return path
+}
+
+// windowsDrive splits a path such as C:/work into its drive letter and the
+// rest of the path
+func windowsDrive(path string) (string, string, bool) {
+	if len(path) < 2 || !isDriveLetter(path[0]) || path[1] != ':' || (len(path) > 2 && path[2] != '/') {
+		return "", "", false
+	}
+	return path[:1], path[2:], true
+}
+
+// msysDrive splits a path such as /c/work or /cygdrive/c/work into its
+// drive letter and the rest of the path
+func msysDrive(path string) (string, string, bool) {
+	path = strings.TrimPrefix(path, "/cygdrive")
+	if len(path) < 2 || path[0] != '/' || !isDriveLetter(path[1]) || (len(path) > 2 && path[2] != '/') {
+		return "", "", false
+	}
+	return path[1:2], path[2:], true
+}
+
+// wslDrive splits a path such as /mnt/c/work, a Windows drive mounted into
+// WSL, into its drive letter and the rest of the path
+func wslDrive(path string) (string, string, bool) {
+	if !strings.HasPrefix(path, "/mnt/") {
+		return "", "", false
+	}
+	return msysDrive(strings.TrimPrefix(path, "/mnt"))
+}
+
+func isDriveLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// splitVolume splits a volume into its source and the rest, starting with
+// the colon before the container path. A Windows drive letter is part of
+// the source.
+func splitVolume(volume string) (string, string) {
+	start := 0
+	if _, _, ok := windowsDrive(strings.ReplaceAll(volume, `\`, "/")); ok {
+		start = 2
+	}
+	i := strings.Index(volume[start:], ":")
+	if i < 0 {
+		return volume, ""
+	}
+	return volume[:start+i], volume[start+i:]
+}
+
+// isBindSource reports whether the source of a volume is a host path rather
+// than the name of a volume
+func isBindSource(source string) bool {
+	if _, _, ok := windowsDrive(strings.ReplaceAll(source, `\`, "/")); ok {
+		return true
+	}
+	return strings.HasPrefix(source, ".") || strings.HasPrefix(source, "/") ||
+		strings.HasPrefix(source, "~") || strings.HasPrefix(source, `\`)
+}
+
+// hasBindMounts reports whether a service mounts a path of the project,
+// which Docker Desktop has to share with its VM
+func hasBindMounts(config *DockerComposeConfig) bool {
+	for _, service := range config.Services {
+		for _, volume := range service.Volumes {
+			if source, _ := splitVolume(volume); isBindSource(source) && strings.HasPrefix(source, ".") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package compose
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+// mockServicePrefix prefixes the compose service that mocks an external
+// dependency
+const mockServicePrefix = "mock-"
+
+// prismImage serves a mock API from an OpenAPI document
+const prismImage = "stoplight/prism:5"
+
+// mockPort is the port Prism listens on inside the compose network
+const mockPort = 4010
+
+// MockServiceName returns the compose service name of an external
+// dependency's mock server
+func MockServiceName(name string) string {
+	return mockServicePrefix + name
+}
+
+// mockURL returns the base URL services use to reach a mock server
+func mockURL(name string) string {
+	return fmt.Sprintf("http://%s:%d", MockServiceName(name), mockPort)
+}
+
+// createMockService creates a Prism container that answers requests to an
+// external dependency with examples from its OpenAPI document, so dependent
+// services can be developed offline
+func createMockService(name string, external manifest.External) DockerComposeService {
+	specFile := path.Join("/tmp", name+filepath.Ext(external.Spec))
+	source := "./" + strings.TrimPrefix(filepath.ToSlash(external.Spec), "./")
+
+	return DockerComposeService{
+		Image:    prismImage,
+		Command:  []string{"mock", "-h", "0.0.0.0", "-p", fmt.Sprintf("%d", mockPort), specFile},
+		Volumes:  []string{fmt.Sprintf("%s:%s:ro", source, specFile)},
+		Networks: []string{"workbench_net"},
+	}
+}
@@ -4,16 +4,25 @@ import (
 	"fmt"
 	"os/exec"
 	"runtime"
+	"strings"
 )
 
 // PrerequisiteChecker handles validation of required external tools
-type PrerequisiteChecker struct{}
+type PrerequisiteChecker struct {
+	engine *Engine // the engine whose tooling is checked; Docker when nil
+}
 
 // NewPrerequisiteChecker creates a new prerequisite checker
 func NewPrerequisiteChecker() *PrerequisiteChecker {
 	return &PrerequisiteChecker{}
 }
 
+// NewPrerequisiteCheckerFor creates a prerequisite checker for the tooling
+// of a container engine, such as podman and podman-compose
+func NewPrerequisiteCheckerFor(engine Engine) *PrerequisiteChecker {
+	return &PrerequisiteChecker{engine: &engine}
+}
+
 // CheckDocker checks if Docker is installed and available
 func (pc *PrerequisiteChecker) CheckDocker() error {
 	_, err := exec.LookPath("docker")
@@ -46,8 +55,25 @@ func (pc *PrerequisiteChecker) CheckDockerCompose() error {
 	return nil
 }
 
+// checkPodman checks that Podman and the compose command it runs with are
+// available
+func (pc *PrerequisiteChecker) checkPodman() error {
+	if _, err := exec.LookPath("podman"); err != nil {
+		return fmt.Errorf("Podman is not installed or not available in PATH. Please install Podman from https://podman.io/docs/installation")
+	}
+	fields := strings.Fields(pc.engine.Compose)
+	if err := exec.Command(fields[0], append(fields[1:], "version")...).Run(); err != nil {
+		return fmt.Errorf("%s is not available. Please install podman-compose (pip install podman-compose)", pc.engine.Compose)
+	}
+	return nil
+}
+
 // CheckAllPrerequisites checks all required prerequisites
 func (pc *PrerequisiteChecker) CheckAllPrerequisites() error {
+	if pc.engine != nil && pc.engine.Name == EnginePodman {
+		return pc.checkPodman()
+	}
+
 	if err := pc.CheckDocker(); err != nil {
 		return err
 	}
@@ -61,6 +87,10 @@ func (pc *PrerequisiteChecker) CheckAllPrerequisites() error {
 
 // GetDockerComposeCommand returns the appropriate docker-compose command for the system
 func (pc *PrerequisiteChecker) GetDockerComposeCommand() string {
+	if pc.engine != nil {
+		return pc.engine.Compose
+	}
+
 	// Check if docker-compose is available
 	_, err := exec.LookPath("docker-compose")
 	if err == nil {
@@ -0,0 +1,121 @@
+package compose
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RemoteDockerHost describes a Docker daemon running on another machine, as
+// configured through DOCKER_HOST. Ports published by containers on such a
+// host are not reachable on localhost without forwarding them.
+type RemoteDockerHost struct {
+	Host    string // host name or address of the remote machine
+	User    string // SSH user, if given in DOCKER_HOST
+	SSHPort string // SSH port, if given in DOCKER_HOST
+}
+
+// ParseDockerHost returns the remote machine named by a DOCKER_HOST value, or
+// nil when the daemon is local. Unix sockets and named pipes are local; VMs
+// reached through them (Colima, Lima, Docker Desktop) forward published
+// ports to localhost on their own.
+func ParseDockerHost(value string) *RemoteDockerHost {
+	if value == "" {
+		return nil
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Hostname() == "" {
+		return nil
+	}
+
+	switch parsed.Scheme {
+	case "ssh":
+		remote := &RemoteDockerHost{Host: parsed.Hostname(), SSHPort: parsed.Port()}
+		if parsed.User != nil {
+			remote.User = parsed.User.Username()
+		}
+		if isLoopback(remote.Host) {
+			return nil
+		}
+		return remote
+	case "tcp", "http", "https":
+		if isLoopback(parsed.Hostname()) {
+			return nil
+		}
+		return &RemoteDockerHost{Host: parsed.Hostname()}
+	}
+	return nil
+}
+
+// isLoopback reports whether host refers to the local machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
+
+// Target returns the SSH destination for the remote host
+func (r *RemoteDockerHost) Target() string {
+	if r.User != "" {
+		return r.User + "@" + r.Host
+	}
+	return r.Host
+}
+
+// ForwardCommand returns an ssh command that forwards each of the given ports
+// from localhost to the remote host
+func (r *RemoteDockerHost) ForwardCommand(ports []string) string {
+	args := []string{"ssh", "-N"}
+	for _, port := range ports {
+		args = append(args, "-L", fmt.Sprintf("%s:localhost:%s", port, port))
+	}
+	if r.SSHPort != "" {
+		args = append(args, "-p", r.SSHPort)
+	}
+	args = append(args, r.Target())
+	return strings.Join(args, " ")
+}
+
+// PublishedPorts returns the host ports published by the services of config,
+// sorted numerically and without duplicates
+func PublishedPorts(config *DockerComposeConfig) []string {
+	seen := make(map[int]bool)
+	var ports []int
+	for _, service := range config.Services {
+		for _, mapping := range service.Ports {
+			port, ok := hostPort(mapping)
+			if ok && !seen[port] {
+				seen[port] = true
+				ports = append(ports, port)
+			}
+		}
+	}
+	sort.Ints(ports)
+
+	result := make([]string, len(ports))
+	for i, port := range ports {
+		result[i] = strconv.Itoa(port)
+	}
+	return result
+}
+
+// hostPort extracts the host port from a "host:container" or
+// "ip:host:container" mapping. Mappings without a host port publish on a
+// random port and are skipped.
+func hostPort(mapping string) (int, bool) {
+	mapping = strings.SplitN(mapping, "/", 2)[0]
+	parts := strings.Split(mapping, ":")
+	if len(parts) < 2 {
+		return 0, false
+	}
+	port, err := strconv.Atoi(parts[len(parts)-2])
+	if err != nil || port <= 0 {
+		return 0, false
+	}
+	return port, true
+}
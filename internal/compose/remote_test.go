@@ -0,0 +1,54 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDockerHost(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected *RemoteDockerHost
+	}{
+		{value: "", expected: nil},
+		{value: "unix:///var/run/docker.sock", expected: nil},
+		{value: "unix:///Users/dev/.colima/default/docker.sock", expected: nil},
+		{value: "npipe:////./pipe/docker_engine", expected: nil},
+		{value: "tcp://127.0.0.1:2375", expected: nil},
+		{value: "tcp://localhost:2376", expected: nil},
+		{value: "ssh://localhost", expected: nil},
+		{value: "ssh://dev@build-box", expected: &RemoteDockerHost{Host: "build-box", User: "dev"}},
+		{value: "ssh://dev@10.0.0.5:2222", expected: &RemoteDockerHost{Host: "10.0.0.5", User: "dev", SSHPort: "2222"}},
+		{value: "tcp://192.168.106.2:2375", expected: &RemoteDockerHost{Host: "192.168.106.2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseDockerHost(tt.value))
+		})
+	}
+}
+
+func TestRemoteDockerHost_ForwardCommand(t *testing.T) {
+	remote := ParseDockerHost("ssh://dev@build-box:2222")
+	require.NotNil(t, remote)
+
+	assert.Equal(t, "ssh -N -L 3000:localhost:3000 -L 8080:localhost:8080 -p 2222 dev@build-box",
+		remote.ForwardCommand([]string{"3000", "8080"}))
+}
+
+func TestPublishedPorts(t *testing.T) {
+	config := &DockerComposeConfig{
+		Services: map[string]DockerComposeService{
+			"gateway":  {Ports: []string{"8080:80"}},
+			"frontend": {Ports: []string{"3000:3000", "127.0.0.1:9229:9229"}},
+			"backend":  {Ports: []string{"8080:8000", "5353:53/udp"}},
+			"worker":   {Ports: []string{"9000"}},
+			"db":       {},
+		},
+	}
+
+	assert.Equal(t, []string{"3000", "5353", "8080", "9229"}, PublishedPorts(config))
+}
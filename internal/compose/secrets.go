@@ -0,0 +1,279 @@
+package compose
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+// SecretsProvider supplies the secret values of the generated .env file,
+// such as the passwords of databases
+type SecretsProvider interface {
+	// Name is the provider as workbench.yaml names it, e.g. vault
+	Name() string
+	// Secrets returns the value of every key, or an error naming the keys
+	// it has no value for
+	Secrets(keys []string) (map[string]string, error)
+}
+
+// secretLength is the length of generated secrets, drawn from
+// secretAlphabet. Letters and digits only keep them safe to embed in
+// connection URLs and shell commands.
+const (
+	secretLength   = 32
+	secretAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// IsSecret reports whether a variable of the generated .env file holds a
+// secret rather than a name derived from the manifest
+func IsSecret(key string) bool {
+	return strings.HasSuffix(key, "_password")
+}
+
+// SecretKeys returns the sorted secret variables the resources of the
+// manifest need in .env
+func SecretKeys(m *manifest.WorkbenchManifest) []string {
+	var keys []string
+	for serviceName, service := range m.Services {
+		for resourceName, resource := range service.Resources {
+			for key := range ResourceCredentials(serviceName, resourceName, resource) {
+				if IsSecret(key) {
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GeneratedSecrets generates strong random secrets. Values already in .env
+// are kept, since databases only read their password when their volume is
+// first created.
+type GeneratedSecrets struct {
+	Existing map[string]string // the current .env
+	Rand     io.Reader         // source of randomness; crypto/rand when nil
+}
+
+// Name returns generate
+func (s GeneratedSecrets) Name() string {
+	return manifest.SecretsGenerate
+}
+
+// Secrets returns the existing value of each key, or a new random one
+func (s GeneratedSecrets) Secrets(keys []string) (map[string]string, error) {
+	source := s.Rand
+	if source == nil {
+		source = rand.Reader
+	}
+	values := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if existing := s.Existing[key]; existing != "" {
+			values[key] = existing
+			continue
+		}
+		secret, err := RandomSecret(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s: %w", key, err)
+		}
+		values[key] = secret
+	}
+	return values, nil
+}
+
+// RandomSecret returns a random string of letters and digits, long enough to
+// serve as a password
+func RandomSecret(source io.Reader) (string, error) {
+	secret := make([]byte, secretLength)
+	limit := big.NewInt(int64(len(secretAlphabet)))
+	for i := range secret {
+		n, err := rand.Int(source, limit)
+		if err != nil {
+			return "", err
+		}
+		secret[i] = secretAlphabet[n.Int64()]
+	}
+	return string(secret), nil
+}
+
+// EnvSecrets reads secrets from environment variables, named like the .env
+// variable in upper or lower case, e.g. API_DB_PASSWORD. CI pipelines set
+// them from their own secret store.
+type EnvSecrets struct {
+	Getenv func(string) string
+}
+
+// Name returns env
+func (s EnvSecrets) Name() string {
+	return manifest.SecretsEnv
+}
+
+// Secrets returns the value of every key from the environment
+func (s EnvSecrets) Secrets(keys []string) (map[string]string, error) {
+	values := make(map[string]string, len(keys))
+	var missing []string
+	for _, key := range keys {
+		value := s.Getenv(strings.ToUpper(key))
+		if value == "" {
+			value = s.Getenv(key)
+		}
+		if value == "" {
+			missing = append(missing, strings.ToUpper(key))
+			continue
+		}
+		values[key] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("environment variables %s are not set", strings.Join(missing, ", "))
+	}
+	return values, nil
+}
+
+// CommandSecrets reads a flat set of secrets with the CLI of an external
+// store: sops for an encrypted file, vault for a KV secret, or aws for a
+// Secrets Manager secret holding a JSON object
+type CommandSecrets struct {
+	Provider string // manifest.SecretsSOPS, SecretsVault, or SecretsAWS
+	Source   string // the file, KV path, or secret id
+	Dir      string // directory the CLI runs in, the project root
+	Run      deps.Runner
+}
+
+// Name returns the provider
+func (s CommandSecrets) Name() string {
+	return s.Provider
+}
+
+// Secrets reads the whole secret once and returns the values of the keys
+func (s CommandSecrets) Secrets(keys []string) (map[string]string, error) {
+	if len(keys) == 0 {
+		return map[string]string{}, nil
+	}
+	stored, err := s.read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets from %s %s: %w", s.Provider, s.Source, err)
+	}
+	values := make(map[string]string, len(keys))
+	var missing []string
+	for _, key := range keys {
+		value, ok := stored[key]
+		if !ok {
+			value, ok = stored[strings.ToUpper(key)]
+		}
+		if !ok || value == "" {
+			missing = append(missing, key)
+			continue
+		}
+		values[key] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%s %s has no %s", s.Provider, s.Source, strings.Join(missing, ", "))
+	}
+	return values, nil
+}
+
+// read runs the CLI of the store and decodes the secret it prints
+func (s CommandSecrets) read() (map[string]string, error) {
+	var name string
+	var args []string
+	switch s.Provider {
+	case manifest.SecretsSOPS:
+		name, args = "sops", []string{"--decrypt", "--output-type", "json", s.Source}
+	case manifest.SecretsVault:
+		name, args = "vault", []string{"kv", "get", "-format=json", s.Source}
+	case manifest.SecretsAWS:
+		name, args = "aws", []string{"secretsmanager", "get-secret-value", "--secret-id", s.Source, "--query", "SecretString", "--output", "text"}
+	default:
+		return nil, fmt.Errorf("unsupported secrets provider '%s'", s.Provider)
+	}
+	output, err := s.Run(s.Dir, name, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(output, &decoded); err != nil {
+		return nil, fmt.Errorf("%s printed no JSON object: %w", name, err)
+	}
+	if s.Provider == manifest.SecretsVault {
+		// vault kv get wraps the secret in data, and KV version 2 wraps it
+		// in data again next to its metadata
+		data, _ := decoded["data"].(map[string]interface{})
+		if inner, ok := data["data"].(map[string]interface{}); ok {
+			data = inner
+		}
+		decoded = data
+	}
+	values := make(map[string]string, len(decoded))
+	for key, value := range decoded {
+		if text, ok := value.(string); ok {
+			values[key] = text
+		} else if value != nil {
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return values, nil
+}
+
+// SecretsSources is what secrets providers read from
+type SecretsSources struct {
+	Existing map[string]string   // the current .env, whose generated secrets are kept
+	Rand     io.Reader           // source of generated secrets; crypto/rand when nil
+	Getenv   func(string) string // environment of the env provider
+	Run      deps.Runner         // runs the CLI of external stores
+}
+
+// NewSecretsProvider returns the provider the secrets section of
+// workbench.yaml configures. The CLIs of external stores run in
+// projectRoot, so SOPS files are found relative to it.
+func NewSecretsProvider(config manifest.Secrets, projectRoot string, sources SecretsSources) SecretsProvider {
+	switch config.ProviderName() {
+	case manifest.SecretsEnv:
+		return EnvSecrets{Getenv: sources.Getenv}
+	case manifest.SecretsSOPS, manifest.SecretsVault, manifest.SecretsAWS:
+		return CommandSecrets{Provider: config.Provider, Source: config.Source, Dir: projectRoot, Run: sources.Run}
+	}
+	return GeneratedSecrets{Existing: sources.Existing, Rand: sources.Rand}
+}
+
+// SetSecretsProvider sets where the secrets of the generated .env file come
+// from. Without it they are generated at random on every run.
+func (g *Generator) SetSecretsProvider(provider SecretsProvider) {
+	g.secrets = provider
+}
+
+// ReadEnvFile returns the variables of a .env file, or none when it does not
+// exist
+func ReadEnvFile(fsys filesystem.FS, filePath string) (map[string]string, error) {
+	data, err := fsys.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			values[strings.TrimSpace(key)] = value
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,79 @@
+package compose
+
+import (
+	"fmt"
+
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+// TraefikTemplate is the component template that turns a component into a
+// Traefik gateway. Services are then routed through it by Docker labels
+// instead of a hand-maintained proxy configuration.
+const TraefikTemplate = "traefik-gateway"
+
+// traefikImage is the Traefik release used for gateway components
+const traefikImage = "traefik:v3.1"
+
+// traefikDefaultPorts publishes the web entrypoint and the dashboard when the
+// component does not list its own ports
+var traefikDefaultPorts = []string{"80:80", "8080:8080"}
+
+// hasTraefikGateway reports whether the project contains a Traefik gateway
+func (g *Generator) hasTraefikGateway() bool {
+	for _, component := range g.project.Components {
+		if component.Template == TraefikTemplate {
+			return true
+		}
+	}
+	return false
+}
+
+// createTraefikService creates the Docker Compose service for a Traefik
+// gateway component. Traefik watches the Docker socket, so services are
+// discovered from their labels as containers start and stop.
+func createTraefikService(component manifest.Component) DockerComposeService {
+	ports := component.Ports
+	if len(ports) == 0 {
+		ports = traefikDefaultPorts
+	}
+
+	return DockerComposeService{
+		Image: traefikImage,
+		Command: []string{
+			"--providers.docker=true",
+			"--providers.docker.exposedbydefault=false",
+			"--entrypoints.web.address=:80",
+			"--api.dashboard=true",
+			"--api.insecure=true",
+		},
+		Ports:    append([]string(nil), ports...),
+		Volumes:  []string{DockerSocket + ":" + DockerSocket + ":ro"},
+		Networks: []string{"workbench_net"},
+	}
+}
+
+// traefikLabels returns the labels that route <subdomain>.localhost to the
+// service's port. The subdomain defaults to the service name. gRPC services
+// are proxied over cleartext HTTP/2; plain TCP services cannot be routed by
+// host name and get no labels.
+func traefikLabels(name string, service manifest.Service) []string {
+	if service.Protocol == "tcp" {
+		return nil
+	}
+
+	subdomain := service.Subdomain
+	if subdomain == "" {
+		subdomain = name
+	}
+
+	labels := []string{
+		"traefik.enable=true",
+		fmt.Sprintf("traefik.http.routers.%s.rule=Host(`%s.localhost`)", name, subdomain),
+		fmt.Sprintf("traefik.http.routers.%s.entrypoints=web", name),
+		fmt.Sprintf("traefik.http.services.%s.loadbalancer.server.port=%d", name, service.Port),
+	}
+	if service.Protocol == "grpc" {
+		labels = append(labels, fmt.Sprintf("traefik.http.services.%s.loadbalancer.server.scheme=h2c", name))
+	}
+	return labels
+}
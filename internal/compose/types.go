@@ -1,58 +1,131 @@
+// Package compose translates a workbench manifest into docker-compose.yml,
+// the .env files, and the configs of generated gateways. It reads the
+// manifest's own types; the types here model the generated files.
 package compose
 
-// WorkbenchProject represents the evolved workbench.yaml structure
-// that supports components, services with resources, and environment variables.
-type WorkbenchProject struct {
-	APIVersion string               `yaml:"apiVersion"`
-	Kind       string               `yaml:"kind"`
-	Metadata   ProjectMetadata      `yaml:"metadata"`
-	Components map[string]Component `yaml:"components,omitempty"`
-	Services   map[string]Service   `yaml:"services"`
-}
+import (
+	"maps"
+	"slices"
 
-// ProjectMetadata contains project-level metadata
-type ProjectMetadata struct {
-	Name string `yaml:"name"`
-}
-
-// Component represents a shared project component (like a gateway)
-type Component struct {
-	Template string   `yaml:"template"`
-	Path     string   `yaml:"path"`
-	Ports    []string `yaml:"ports,omitempty"`
-}
-
-// Service represents a service in the project with its configuration
-type Service struct {
-	Template    string              `yaml:"template"`
-	Path        string              `yaml:"path"`
-	Port        int                 `yaml:"port,omitempty"`
-	Resources   map[string]Resource `yaml:"resources,omitempty"`
-	Environment map[string]string   `yaml:"environment,omitempty"`
-}
-
-// Resource represents a service-owned resource (like a database)
-type Resource struct {
-	Type    string            `yaml:"type"`
-	Version string            `yaml:"version,omitempty"`
-	Config  map[string]string `yaml:"config,omitempty"`
-}
+	"github.com/jashkahar/open-workbench-platform/internal/explain"
+	"gopkg.in/yaml.v3"
+)
 
 // DockerComposeService represents a service in the generated docker-compose.yml
 type DockerComposeService struct {
 	Build       *BuildConfig `yaml:"build,omitempty"`
 	Image       string       `yaml:"image,omitempty"`
+	Entrypoint  []string     `yaml:"entrypoint,omitempty"`
+	Command     []string     `yaml:"command,omitempty"`
+	Restart     string       `yaml:"restart,omitempty"`
+	Init        bool         `yaml:"init,omitempty"`
+	User        string       `yaml:"user,omitempty"`
+	ReadOnly    bool         `yaml:"read_only,omitempty"`
+	CapDrop     []string     `yaml:"cap_drop,omitempty"`
+	SecurityOpt []string     `yaml:"security_opt,omitempty"`
 	Ports       []string     `yaml:"ports,omitempty"`
 	Environment []string     `yaml:"environment,omitempty"`
+	Labels      []string     `yaml:"labels,omitempty"`
 	EnvFile     []string     `yaml:"env_file,omitempty"`
 	Networks    []string     `yaml:"networks,omitempty"`
 	DependsOn   []string     `yaml:"depends_on,omitempty"`
+	Healthcheck *Healthcheck `yaml:"healthcheck,omitempty"`
 	Volumes     []string     `yaml:"volumes,omitempty"`
+	Profiles    []string     `yaml:"profiles,omitempty"`
+
+	// Conditions sets what services in DependsOn must reach before this one
+	// starts, e.g. service_healthy. With any set, depends_on is written as a
+	// mapping; dependencies without one wait for service_started.
+	Conditions map[string]string `yaml:"-"`
+}
+
+// Healthcheck tells Docker how to check that a container is healthy
+type Healthcheck struct {
+	Test        []string `yaml:"test"`
+	Interval    string   `yaml:"interval,omitempty"`
+	Timeout     string   `yaml:"timeout,omitempty"`
+	Retries     int      `yaml:"retries,omitempty"`
+	StartPeriod string   `yaml:"start_period,omitempty"`
+}
+
+// Conditions of depends_on entries
+const (
+	ConditionStarted   = "service_started"
+	ConditionHealthy   = "service_healthy"
+	ConditionCompleted = "service_completed_successfully"
+)
+
+// MarshalYAML writes depends_on as a mapping of services to conditions
+// when any dependency has a condition, and as a list of names otherwise
+func (s DockerComposeService) MarshalYAML() (interface{}, error) {
+	type plain DockerComposeService
+	var node yaml.Node
+	if err := node.Encode(plain(s)); err != nil {
+		return nil, err
+	}
+	if len(s.Conditions) == 0 {
+		return &node, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "depends_on" {
+			continue
+		}
+		dependencies := make(map[string]map[string]string, len(s.DependsOn))
+		for _, name := range s.DependsOn {
+			condition := s.Conditions[name]
+			if condition == "" {
+				condition = ConditionStarted
+			}
+			dependencies[name] = map[string]string{"condition": condition}
+		}
+		var mapping yaml.Node
+		if err := mapping.Encode(dependencies); err != nil {
+			return nil, err
+		}
+		node.Content[i+1] = &mapping
+	}
+	return &node, nil
+}
+
+// UnmarshalYAML reads depends_on written as a list of names or as a
+// mapping of services to conditions
+func (s *DockerComposeService) UnmarshalYAML(node *yaml.Node) error {
+	type plain DockerComposeService
+	var conditions map[string]string
+	if node.Kind == yaml.MappingNode {
+		content := append([]*yaml.Node(nil), node.Content...)
+		for i := 0; i+1 < len(content); i += 2 {
+			if content[i].Value != "depends_on" || content[i+1].Kind != yaml.MappingNode {
+				continue
+			}
+			var dependencies map[string]struct {
+				Condition string `yaml:"condition"`
+			}
+			if err := content[i+1].Decode(&dependencies); err != nil {
+				return err
+			}
+			names := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			conditions = make(map[string]string, len(dependencies))
+			for _, name := range slices.Sorted(maps.Keys(dependencies)) {
+				names.Content = append(names.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name})
+				conditions[name] = dependencies[name].Condition
+			}
+			content[i+1] = names
+		}
+		node = &yaml.Node{Kind: node.Kind, Tag: node.Tag, Content: content}
+	}
+	if err := node.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	s.Conditions = conditions
+	return nil
 }
 
 // BuildConfig represents the build configuration for a service
 type BuildConfig struct {
-	Context string `yaml:"context"`
+	Context            string            `yaml:"context"`
+	AdditionalContexts map[string]string `yaml:"additional_contexts,omitempty"` // named contexts the Dockerfile can COPY --from
+	Labels             map[string]string `yaml:"labels,omitempty"`              // labels of the built image
 }
 
 // DockerComposeConfig represents the complete docker-compose.yml structure
@@ -61,4 +134,12 @@ type DockerComposeConfig struct {
 	Services map[string]DockerComposeService `yaml:"services"`
 	Volumes  map[string]interface{}          `yaml:"volumes,omitempty"`
 	Networks map[string]interface{}          `yaml:"networks,omitempty"`
+
+	// Origins records the workbench.yaml entry behind each service. It is
+	// written as marker comments rather than as part of the file.
+	Origins map[string]explain.Origin `yaml:"-"`
+
+	// Revision is the revision of workbench.yaml the configuration was
+	// generated from, written in the header of the file
+	Revision string `yaml:"-"`
 }
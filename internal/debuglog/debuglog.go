@@ -0,0 +1,99 @@
+// Package debuglog captures diagnostic output. Debug messages never reach
+// stdout or stderr, where they would mix with command output; while a log
+// is started with --debug they are written to a timestamped file under
+// .om/logs/ that can be attached to bug reports.
+package debuglog
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// Dir is the directory, relative to the project root, that holds debug logs
+var Dir = filepath.Join(".om", "logs")
+
+// Logger writes debug messages to a log file
+type Logger struct {
+	mu      sync.Mutex
+	fsys    filesystem.FS
+	path    string
+	now     func() time.Time
+	content bytes.Buffer
+}
+
+var (
+	currentMu sync.Mutex
+	current   *Logger
+)
+
+// Start creates a log file under dir/.om/logs and sends every later Printf
+// to it, until Stop is called. The file name carries the start time.
+func Start(fsys filesystem.FS, dir string, now func() time.Time) (*Logger, error) {
+	logDir := filepath.Join(dir, Dir)
+	if err := fsys.MkdirAll(logDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", logDir, err)
+	}
+
+	logger := &Logger{
+		fsys: fsys,
+		path: filepath.Join(logDir, fmt.Sprintf("om-%s.log", now().Format("20060102-150405"))),
+		now:  now,
+	}
+	if err := logger.flush(); err != nil {
+		return nil, err
+	}
+
+	currentMu.Lock()
+	current = logger
+	currentMu.Unlock()
+	return logger, nil
+}
+
+// Stop detaches the current log file. Later messages are discarded.
+func Stop() {
+	currentMu.Lock()
+	current = nil
+	currentMu.Unlock()
+}
+
+// Printf records a debug message when a log is started and discards it
+// otherwise
+func Printf(format string, args ...interface{}) {
+	currentMu.Lock()
+	logger := current
+	currentMu.Unlock()
+	if logger != nil {
+		logger.Printf(format, args...)
+	}
+}
+
+// Path returns the path of the log file
+func (l *Logger) Path() string {
+	return l.path
+}
+
+// Printf appends a timestamped message to the log file. Write failures are
+// ignored so that diagnostics never fail a command.
+func (l *Logger) Printf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	message := strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+	fmt.Fprintf(&l.content, "%s %s\n", l.now().Format("15:04:05.000"), message)
+	_ = l.flush()
+}
+
+// flush writes the messages so far, so the log is complete even when om
+// exits abruptly
+func (l *Logger) flush() error {
+	if err := l.fsys.WriteFile(l.path, l.content.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", l.path, err)
+	}
+	return nil
+}
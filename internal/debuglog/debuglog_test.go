@@ -0,0 +1,42 @@
+package debuglog
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestStartPrintfStop(t *testing.T) {
+	memFS := filesystem.NewMemFS()
+	clock := func() time.Time { return time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC) }
+
+	Printf("before start")
+	logger, err := Start(memFS, "demo", clock)
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(Stop)
+
+	wantPath := filepath.Join("demo", ".om", "logs", "om-20260304-050607.log")
+	if logger.Path() != wantPath {
+		t.Errorf("expected log at %s, got %s", wantPath, logger.Path())
+	}
+
+	Printf("loaded %s\n", "template.json")
+	Stop()
+	Printf("after stop")
+
+	content, err := memFS.ReadFile(wantPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "05:06:07.000 loaded template.json\n" {
+		t.Errorf("unexpected log content:\n%s", content)
+	}
+	if strings.Contains(string(content), "before start") || strings.Contains(string(content), "after stop") {
+		t.Error("expected messages outside a started log to be discarded")
+	}
+}
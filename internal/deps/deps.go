@@ -0,0 +1,139 @@
+// Package deps finds the outdated dependencies of a project's services with
+// each ecosystem's own tooling and bumps them in the services' manifests.
+package deps
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/debuglog"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// Supported ecosystems
+const (
+	EcosystemNPM = "npm"
+	EcosystemPip = "pip"
+)
+
+// manifestFiles are the dependency manifests that identify each ecosystem
+var manifestFiles = map[string]string{
+	EcosystemNPM: "package.json",
+	EcosystemPip: "requirements.txt",
+}
+
+// Runner runs a command in dir and returns its standard output. Output is
+// returned together with the error when the command fails, since some
+// tools, like npm outdated, exit non-zero whenever they find something.
+type Runner func(dir, name string, args ...string) ([]byte, error)
+
+// ExecRunner runs commands on the host
+func ExecRunner(dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	debuglog.Printf("running in %s: %s %s", dir, name, strings.Join(args, " "))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.TrimSpace(stderr.String()) != "" {
+			return output, fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+		}
+		return output, fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return output, nil
+}
+
+// Outdated is a dependency with a newer release than the one in use
+type Outdated struct {
+	Service   string
+	Ecosystem string
+	Package   string
+	Current   string
+	Latest    string
+	Severity  Severity
+}
+
+// Detect returns the ecosystems of the service in dir, judged by the
+// dependency manifests it contains
+func Detect(fsys filesystem.FS, dir string) []string {
+	var ecosystems []string
+	for ecosystem, file := range manifestFiles {
+		if filesystem.Exists(fsys, filepath.Join(dir, file)) {
+			ecosystems = append(ecosystems, ecosystem)
+		}
+	}
+	sort.Strings(ecosystems)
+	return ecosystems
+}
+
+// Check runs the outdated check of an ecosystem for the service in dir
+func Check(fsys filesystem.FS, run Runner, service, dir, ecosystem string) ([]Outdated, error) {
+	var found []Outdated
+	var err error
+	switch ecosystem {
+	case EcosystemNPM:
+		found, err = checkNPM(fsys, run, dir)
+	case EcosystemPip:
+		found, err = checkPip(fsys, run, dir)
+	default:
+		return nil, fmt.Errorf("unsupported ecosystem '%s'", ecosystem)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range found {
+		found[i].Service = service
+		found[i].Ecosystem = ecosystem
+		found[i].Severity = Classify(found[i].Current, found[i].Latest)
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Package < found[j].Package })
+	return found, nil
+}
+
+// Apply bumps the outdated dependencies of the service in dir to their
+// latest versions in its dependency manifests. It returns the files it
+// changed, relative to dir. Lock files are not updated.
+func Apply(fsys filesystem.FS, dir string, outdated []Outdated) ([]string, error) {
+	updates := make(map[string]map[string]string)
+	for _, dependency := range outdated {
+		if updates[dependency.Ecosystem] == nil {
+			updates[dependency.Ecosystem] = make(map[string]string)
+		}
+		updates[dependency.Ecosystem][dependency.Package] = dependency.Latest
+	}
+
+	var changed []string
+	for _, ecosystem := range []string{EcosystemNPM, EcosystemPip} {
+		if len(updates[ecosystem]) == 0 {
+			continue
+		}
+		file := manifestFiles[ecosystem]
+		path := filepath.Join(dir, file)
+		data, err := fsys.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		var bumped []byte
+		if ecosystem == EcosystemNPM {
+			bumped = bumpPackageJSON(data, updates[ecosystem])
+		} else {
+			bumped = bumpRequirements(data, updates[ecosystem])
+		}
+		if bytes.Equal(bumped, data) {
+			continue
+		}
+		if err := fsys.WriteFile(path, bumped, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		changed = append(changed, file)
+	}
+	return changed, nil
+}
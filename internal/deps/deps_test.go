@@ -0,0 +1,179 @@
+package deps
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+const packageJSON = `{
+  "name": "web",
+  "dependencies": {
+    "react": "^18.2.0",
+    "axios": "~1.6.0"
+  },
+  "devDependencies": {
+    "vite": "^5.0.0"
+  }
+}
+`
+
+const requirements = `# API dependencies
+fastapi==0.109.0
+uvicorn[standard]>=0.27.0
+pydantic>=2.0,<3
+requests
+`
+
+// fakeRunner answers commands with canned output
+func fakeRunner(outputs map[string]string, failures map[string]error) Runner {
+	return func(dir, name string, args ...string) ([]byte, error) {
+		command := strings.Join(append([]string{name}, args...), " ")
+		return []byte(outputs[command]), failures[command]
+	}
+}
+
+func writeService(t *testing.T, fsys *filesystem.MemFS, files map[string]string) {
+	t.Helper()
+	if err := fsys.MkdirAll("svc", 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := fsys.WriteFile(filepath.Join("svc", name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	writeService(t, fsys, map[string]string{"package.json": packageJSON, "requirements.txt": requirements})
+
+	ecosystems := Detect(fsys, "svc")
+	if strings.Join(ecosystems, ",") != "npm,pip" {
+		t.Errorf("expected npm and pip, got %v", ecosystems)
+	}
+	if len(Detect(fsys, "missing")) != 0 {
+		t.Error("expected no ecosystems for a directory without manifests")
+	}
+}
+
+func TestCheck_NPM(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	writeService(t, fsys, map[string]string{"package.json": packageJSON})
+
+	// npm outdated exits with status 1 when it finds outdated packages
+	run := fakeRunner(map[string]string{
+		"npm outdated --json": `{
+  "react": {"current": "18.2.0", "wanted": "18.3.1", "latest": "19.0.0"},
+  "axios": {"current": "1.6.0", "wanted": "1.6.8", "latest": "1.7.2"},
+  "vite": {"wanted": "5.0.12", "latest": "5.0.12"}
+}`,
+	}, map[string]error{"npm outdated --json": errors.New("exit status 1")})
+
+	found, err := Check(fsys, run, "web", "svc", EcosystemNPM)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if len(found) != 3 {
+		t.Fatalf("expected 3 outdated packages, got %v", found)
+	}
+	expected := []Outdated{
+		{Service: "web", Ecosystem: EcosystemNPM, Package: "axios", Current: "1.6.0", Latest: "1.7.2", Severity: SeverityMinor},
+		{Service: "web", Ecosystem: EcosystemNPM, Package: "react", Current: "18.2.0", Latest: "19.0.0", Severity: SeverityMajor},
+		{Service: "web", Ecosystem: EcosystemNPM, Package: "vite", Current: "5.0.0", Latest: "5.0.12", Severity: SeverityPatch},
+	}
+	for i, want := range expected {
+		if found[i] != want {
+			t.Errorf("entry %d: expected %+v, got %+v", i, want, found[i])
+		}
+	}
+}
+
+func TestCheck_NPMFailure(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	writeService(t, fsys, map[string]string{"package.json": packageJSON})
+	run := fakeRunner(nil, map[string]error{"npm outdated --json": errors.New("executable file not found")})
+
+	if _, err := Check(fsys, run, "web", "svc", EcosystemNPM); err == nil {
+		t.Error("expected an error when npm cannot run")
+	}
+}
+
+func TestCheck_Pip(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	writeService(t, fsys, map[string]string{"requirements.txt": requirements})
+	run := fakeRunner(map[string]string{
+		"pip list --outdated --format=json": `[
+  {"name": "fastapi", "version": "0.109.0", "latest_version": "0.111.0", "latest_filetype": "wheel"},
+  {"name": "setuptools", "version": "69.0.0", "latest_version": "70.0.0", "latest_filetype": "wheel"},
+  {"name": "Uvicorn", "version": "0.27.0", "latest_version": "0.30.1", "latest_filetype": "wheel"}
+]`,
+	}, nil)
+
+	found, err := Check(fsys, run, "api", "svc", EcosystemPip)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if len(found) != 2 || found[0].Package != "Uvicorn" || found[1].Package != "fastapi" {
+		t.Fatalf("expected only the declared packages, got %+v", found)
+	}
+	if found[1].Severity != SeverityMinor {
+		t.Errorf("expected a minor update for fastapi, got %s", found[1].Severity)
+	}
+}
+
+func TestApply(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	writeService(t, fsys, map[string]string{"package.json": packageJSON, "requirements.txt": requirements})
+
+	changed, err := Apply(fsys, "svc", []Outdated{
+		{Ecosystem: EcosystemNPM, Package: "react", Latest: "19.0.0"},
+		{Ecosystem: EcosystemNPM, Package: "axios", Latest: "1.7.2"},
+		{Ecosystem: EcosystemPip, Package: "fastapi", Latest: "0.111.0"},
+		{Ecosystem: EcosystemPip, Package: "uvicorn", Latest: "0.30.1"},
+		{Ecosystem: EcosystemPip, Package: "pydantic", Latest: "3.1.0"},
+		{Ecosystem: EcosystemPip, Package: "requests", Latest: "2.32.0"},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if strings.Join(changed, ",") != "package.json,requirements.txt" {
+		t.Errorf("expected both manifests to change, got %v", changed)
+	}
+
+	pkg, _ := fsys.ReadFile(filepath.Join("svc", "package.json"))
+	for _, want := range []string{`"react": "^19.0.0"`, `"axios": "~1.7.2"`, `"vite": "^5.0.0"`} {
+		if !strings.Contains(string(pkg), want) {
+			t.Errorf("package.json missing %s:\n%s", want, pkg)
+		}
+	}
+
+	reqs, _ := fsys.ReadFile(filepath.Join("svc", "requirements.txt"))
+	want := "# API dependencies\nfastapi==0.111.0\nuvicorn[standard]>=0.30.1\npydantic>=2.0,<3\nrequests\n"
+	if string(reqs) != want {
+		t.Errorf("expected requirements.txt:\n%s\ngot:\n%s", want, reqs)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            Severity
+	}{
+		{"1.2.3", "2.0.0", SeverityMajor},
+		{"1.2.3", "1.3.0", SeverityMinor},
+		{"1.2.3", "1.2.9", SeverityPatch},
+		{"v0.9", "0.9.1", SeverityPatch},
+		{"2.0.0rc1", "2.1.0", SeverityMinor},
+		{"git+https://example.com/pkg", "1.0.0", SeverityUnknown},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.current, tt.latest); got != tt.want {
+			t.Errorf("Classify(%q, %q) = %s, want %s", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
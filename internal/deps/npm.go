@@ -0,0 +1,108 @@
+package deps
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// npmOutdated is one entry of 'npm outdated --json'
+type npmOutdated struct {
+	Current string `json:"current"`
+	Wanted  string `json:"wanted"`
+	Latest  string `json:"latest"`
+}
+
+// checkNPM runs 'npm outdated' for the service in dir
+func checkNPM(fsys filesystem.FS, run Runner, dir string) ([]Outdated, error) {
+	output, runErr := run(dir, "npm", "outdated", "--json")
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, runErr
+	}
+
+	var report map[string]json.RawMessage
+	if err := json.Unmarshal(output, &report); err != nil {
+		if runErr != nil {
+			return nil, runErr
+		}
+		return nil, fmt.Errorf("failed to parse npm outdated output: %w", err)
+	}
+
+	declared, err := packageJSONVersions(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Outdated
+	for name, raw := range report {
+		entry, err := parseNPMEntry(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse npm outdated entry for %s: %w", name, err)
+		}
+		current := entry.Current
+		if current == "" {
+			// Not installed yet; compare against the declared range
+			current = strings.TrimLeft(declared[name], "^~>=<v ")
+		}
+		if entry.Latest == "" || current == entry.Latest {
+			continue
+		}
+		found = append(found, Outdated{Package: name, Current: current, Latest: entry.Latest})
+	}
+	return found, nil
+}
+
+// parseNPMEntry decodes an outdated entry. Packages installed in several
+// workspaces are reported as a list; the first one is used.
+func parseNPMEntry(raw json.RawMessage) (npmOutdated, error) {
+	var entry npmOutdated
+	if err := json.Unmarshal(raw, &entry); err == nil {
+		return entry, nil
+	}
+	var entries []npmOutdated
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return entry, err
+	}
+	if len(entries) > 0 {
+		entry = entries[0]
+	}
+	return entry, nil
+}
+
+// packageJSONVersions returns the declared version range of every dependency
+func packageJSONVersions(fsys filesystem.FS, dir string) (map[string]string, error) {
+	data, err := fsys.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package.json: %w", err)
+	}
+	var manifest struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+	versions := make(map[string]string, len(manifest.Dependencies)+len(manifest.DevDependencies))
+	for name, version := range manifest.DevDependencies {
+		versions[name] = version
+	}
+	for name, version := range manifest.Dependencies {
+		versions[name] = version
+	}
+	return versions, nil
+}
+
+// bumpPackageJSON sets dependencies to their latest versions, keeping the
+// ^ or ~ range of each and the formatting of the file
+func bumpPackageJSON(data []byte, updates map[string]string) []byte {
+	for name, latest := range updates {
+		pattern := regexp.MustCompile(`("` + regexp.QuoteMeta(name) + `"\s*:\s*")([\^~]?)[^"]*(")`)
+		data = pattern.ReplaceAll(data, []byte("${1}${2}"+latest+"${3}"))
+	}
+	return data
+}
@@ -0,0 +1,102 @@
+package deps
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// pipOutdated is one entry of 'pip list --outdated --format=json'
+type pipOutdated struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	LatestVersion string `json:"latest_version"`
+}
+
+// requirementLine matches a single-specifier requirement such as
+// fastapi==0.110.0 or uvicorn[standard]>=0.29
+var requirementLine = regexp.MustCompile(`^(\s*([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?\s*)(==|>=|~=)\s*([^\s,;#]+)(.*)$`)
+
+// checkPip runs 'pip list --outdated' for the service in dir. pip reports
+// every package of the active environment, so only the packages listed in
+// requirements.txt are kept.
+func checkPip(fsys filesystem.FS, run Runner, dir string) ([]Outdated, error) {
+	data, err := fsys.ReadFile(filepath.Join(dir, "requirements.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read requirements.txt: %w", err)
+	}
+	declared := requirementNames(data)
+
+	output, err := run(dir, "pip", "list", "--outdated", "--format=json")
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+	var report []pipOutdated
+	if err := json.Unmarshal(output, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse pip list output: %w", err)
+	}
+
+	var found []Outdated
+	for _, entry := range report {
+		if !declared[normalizePipName(entry.Name)] {
+			continue
+		}
+		found = append(found, Outdated{Package: entry.Name, Current: entry.Version, Latest: entry.LatestVersion})
+	}
+	return found, nil
+}
+
+// requirementNames returns the normalized names of the packages listed in a
+// requirements file
+func requirementNames(data []byte) map[string]bool {
+	names := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		end := strings.IndexAny(line, "[=<>~!;@ \t#")
+		if end == -1 {
+			end = len(line)
+		}
+		names[normalizePipName(line[:end])] = true
+	}
+	return names
+}
+
+// normalizePipName normalizes a package name the way pip compares them
+func normalizePipName(name string) string {
+	return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+}
+
+// bumpRequirements sets ==, >=, and ~= requirements to their latest
+// versions. Unpinned requirements and those with several specifiers are
+// left alone.
+func bumpRequirements(data []byte, updates map[string]string) []byte {
+	latest := make(map[string]string, len(updates))
+	for name, version := range updates {
+		latest[normalizePipName(name)] = version
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		match := requirementLine.FindStringSubmatch(line)
+		if match == nil || strings.HasPrefix(strings.TrimSpace(match[6]), ",") {
+			continue
+		}
+		version, ok := latest[normalizePipName(match[2])]
+		if !ok {
+			continue
+		}
+		lines[i] = match[1] + match[4] + version + match[6]
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
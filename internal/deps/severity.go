@@ -0,0 +1,63 @@
+package deps
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Severity tells how far behind its latest release a dependency is
+type Severity string
+
+// Severities, from the most to the least disruptive update
+const (
+	SeverityMajor   Severity = "major"
+	SeverityMinor   Severity = "minor"
+	SeverityPatch   Severity = "patch"
+	SeverityUnknown Severity = "unknown" // versions that are not dotted numbers
+)
+
+// Severities lists every severity, most disruptive first
+var Severities = []Severity{SeverityMajor, SeverityMinor, SeverityPatch, SeverityUnknown}
+
+// Classify compares the major, minor, and patch numbers of two versions
+func Classify(current, latest string) Severity {
+	currentParts, ok := versionNumbers(current)
+	if !ok {
+		return SeverityUnknown
+	}
+	latestParts, ok := versionNumbers(latest)
+	if !ok {
+		return SeverityUnknown
+	}
+	switch {
+	case currentParts[0] != latestParts[0]:
+		return SeverityMajor
+	case currentParts[1] != latestParts[1]:
+		return SeverityMinor
+	default:
+		return SeverityPatch
+	}
+}
+
+// versionNumbers parses the first three numbers of a version such as 1.2.3,
+// v1.2, or 2.0.0rc1. Missing numbers are zero.
+func versionNumbers(version string) ([3]int, bool) {
+	var numbers [3]int
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	parts := strings.SplitN(version, ".", 3)
+	for i, part := range parts {
+		end := 0
+		for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			return numbers, false
+		}
+		number, err := strconv.Atoi(part[:end])
+		if err != nil {
+			return numbers, false
+		}
+		numbers[i] = number
+	}
+	return numbers, true
+}
@@ -0,0 +1,180 @@
+// Package devrun runs a project's docker-compose.yml for om dev. It starts
+// the services in the background, follows the logs of each service as a
+// stream of its own, and merges the streams into one output where every
+// line carries the name of its service, colored per service, so the logs
+// of the whole project can be read in one terminal.
+package devrun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+)
+
+// Streamer runs a command in dir, writing its output to out, until the
+// command exits or ctx is cancelled
+type Streamer func(ctx context.Context, dir string, out io.Writer, name string, args ...string) error
+
+// ExecStreamer runs the command as a process. A command stopped because ctx
+// was cancelled is not an error.
+func ExecStreamer(ctx context.Context, dir string, out io.Writer, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Run(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// Compose runs docker compose commands against one compose file
+type Compose struct {
+	Command string // "docker compose" or "docker-compose"
+	Dir     string // project root holding the compose file
+	File    string
+	Run     deps.Runner
+	Stream  Streamer
+}
+
+// command returns the program and arguments of a compose subcommand
+func (c Compose) command(args ...string) (string, []string) {
+	fields := strings.Fields(c.Command)
+	return fields[0], append(append(fields[1:], "-f", c.File), args...)
+}
+
+// Services returns the services docker compose starts by default, leaving
+// out those behind a profile
+func (c Compose) Services() ([]string, error) {
+	name, args := c.command("config", "--services")
+	output, err := c.Run(c.Dir, name, args...)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// Up builds the images and starts the services in the background
+func (c Compose) Up() error {
+	name, args := c.command("up", "--build", "--detach", "--remove-orphans")
+	_, err := c.Run(c.Dir, name, args...)
+	return err
+}
+
+// Down stops and removes the containers and network of the project. Named
+// volumes, and the data in them, are kept.
+func (c Compose) Down() error {
+	name, args := c.command("down")
+	_, err := c.Run(c.Dir, name, args...)
+	return err
+}
+
+// Follow streams the logs of every service into out until ctx is cancelled
+// or every service has stopped. It returns the first error of a stream
+// that failed for another reason.
+func (c Compose) Follow(ctx context.Context, out *Output, services []string) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(services))
+	for i, service := range services {
+		wg.Add(1)
+		go func(i int, service string) {
+			defer wg.Done()
+			writer := out.Writer(service)
+			name, args := c.command("logs", "--follow", "--no-color", "--no-log-prefix", service)
+			errs[i] = c.Stream(ctx, c.Dir, writer, name, args...)
+			writer.Flush()
+			if ctx.Err() == nil {
+				out.Notice(service, "stopped")
+			}
+		}(i, service)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// palette holds the ANSI colors of service prefixes, assigned in order
+var palette = []string{"36", "33", "32", "35", "34", "96", "93", "92", "95", "94"}
+
+// Output merges lines written by several services into one writer, one
+// whole line at a time
+type Output struct {
+	mu       sync.Mutex
+	out      io.Writer
+	prefixes map[string]string
+}
+
+// NewOutput returns an output for the services, padding their names to the
+// same width and, with color, giving each a color of its own
+func NewOutput(out io.Writer, services []string, color bool) *Output {
+	width := 0
+	for _, service := range services {
+		if len(service) > width {
+			width = len(service)
+		}
+	}
+	o := &Output{out: out, prefixes: make(map[string]string, len(services))}
+	for i, service := range services {
+		prefix := fmt.Sprintf("%-*s | ", width, service)
+		if color {
+			prefix = "\033[" + palette[i%len(palette)] + "m" + prefix + "\033[0m"
+		}
+		o.prefixes[service] = prefix
+	}
+	return o
+}
+
+// Writer returns the writer of a service's lines
+func (o *Output) Writer(service string) *LineWriter {
+	return &LineWriter{output: o, prefix: o.prefixes[service]}
+}
+
+// Notice writes a line about a service rather than from it
+func (o *Output) Notice(service, message string) {
+	o.writeLine(o.prefixes[service], "--- "+message)
+}
+
+func (o *Output) writeLine(prefix, line string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	fmt.Fprintf(o.out, "%s%s\n", prefix, line)
+}
+
+// LineWriter prefixes the lines of one service. Output is held back until a
+// line is complete, so lines of different services never mix.
+type LineWriter struct {
+	output  *Output
+	prefix  string
+	pending []byte
+}
+
+// Write writes the complete lines of p and keeps the rest for the next write
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		end := strings.IndexByte(string(w.pending), '\n')
+		if end < 0 {
+			break
+		}
+		w.output.writeLine(w.prefix, strings.TrimSuffix(string(w.pending[:end]), "\r"))
+		w.pending = w.pending[end+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a last line that did not end in a newline
+func (w *LineWriter) Flush() {
+	if len(w.pending) > 0 {
+		w.output.writeLine(w.prefix, string(w.pending))
+		w.pending = nil
+	}
+}
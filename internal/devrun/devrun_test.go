@@ -0,0 +1,80 @@
+package devrun
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestOutput_PrefixesWholeLines(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewOutput(&buf, []string{"api", "api-db"}, false)
+	api, db := out.Writer("api"), out.Writer("api-db")
+
+	api.Write([]byte("listening on "))
+	db.Write([]byte("ready to accept connections\r\n"))
+	api.Write([]byte(":8000\nGET /health"))
+	api.Flush()
+
+	want := "api-db | ready to accept connections\napi    | listening on :8000\napi    | GET /health\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	colored := NewOutput(&buf, []string{"api", "web"}, true)
+	colored.Writer("web").Write([]byte("compiled\n"))
+	if buf.String() != "\033[33mweb | \033[0mcompiled\n" {
+		t.Errorf("expected the second service to get the second color, got %q", buf.String())
+	}
+}
+
+func TestCompose(t *testing.T) {
+	var ran []string
+	c := Compose{
+		Command: "docker compose",
+		Dir:     "demo",
+		File:    "docker-compose.yml",
+		Run: func(dir, name string, args ...string) ([]byte, error) {
+			ran = append(ran, name+" "+strings.Join(args, " "))
+			return []byte("api\nworker\n"), nil
+		},
+		Stream: func(ctx context.Context, dir string, out io.Writer, name string, args ...string) error {
+			service := args[len(args)-1]
+			io.WriteString(out, "hello from "+service+"\n")
+			return nil
+		},
+	}
+
+	if err := c.Up(); err != nil {
+		t.Fatal(err)
+	}
+	services, err := c.Services()
+	if err != nil || strings.Join(services, ",") != "api,worker" {
+		t.Fatalf("Services() = %v, %v", services, err)
+	}
+	var buf bytes.Buffer
+	if err := c.Follow(context.Background(), NewOutput(&buf, services, false), services); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"api    | hello from api\n", "worker | hello from worker\n", "worker | --- stopped\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in the merged logs, got:\n%s", want, buf.String())
+		}
+	}
+
+	legacy := Compose{Command: "docker-compose", File: "docker-compose.yml", Run: c.Run}
+	if err := legacy.Down(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"docker compose -f docker-compose.yml up --build --detach --remove-orphans",
+		"docker compose -f docker-compose.yml config --services",
+		"docker-compose -f docker-compose.yml down",
+	}
+	if strings.Join(ran, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ran:\n%s\nwant:\n%s", strings.Join(ran, "\n"), strings.Join(want, "\n"))
+	}
+}
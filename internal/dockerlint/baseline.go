@@ -0,0 +1,94 @@
+package dockerlint
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// BaselineFileName is the location of the baseline relative to the project root
+var BaselineFileName = filepath.Join(".om", "dockerlint-baseline.yaml")
+
+// Baseline is the set of findings a project has accepted. Findings are
+// matched without their line numbers, so editing other parts of a Dockerfile
+// does not bring accepted findings back.
+type Baseline struct {
+	Findings []Finding `yaml:"findings"`
+}
+
+// LoadBaseline reads the baseline of the project at projectRoot. A project
+// without one has an empty baseline.
+func LoadBaseline(fsys filesystem.FS, projectRoot string) (*Baseline, error) {
+	path := filepath.Join(projectRoot, BaselineFileName)
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		if !filesystem.Exists(fsys, path) {
+			return &Baseline{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", BaselineFileName, err)
+	}
+
+	var baseline Baseline
+	if err := yaml.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", BaselineFileName, err)
+	}
+	return &baseline, nil
+}
+
+// NewBaseline accepts every finding in findings
+func NewBaseline(findings []Finding) *Baseline {
+	accepted := append([]Finding(nil), findings...)
+	sort.SliceStable(accepted, func(i, j int) bool {
+		if accepted[i].Service != accepted[j].Service {
+			return accepted[i].Service < accepted[j].Service
+		}
+		return accepted[i].Line < accepted[j].Line
+	})
+	return &Baseline{Findings: accepted}
+}
+
+// Save writes the baseline into the project at projectRoot
+func (b *Baseline) Save(fsys filesystem.FS, projectRoot string) error {
+	data, err := yaml.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", BaselineFileName, err)
+	}
+	path := filepath.Join(projectRoot, BaselineFileName)
+	if err := fsys.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(BaselineFileName), err)
+	}
+	if err := fsys.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", BaselineFileName, err)
+	}
+	return nil
+}
+
+// Filter returns the findings the baseline does not accept. Each accepted
+// finding suppresses one matching finding, so a rule broken a second time in
+// the same Dockerfile is still reported.
+func (b *Baseline) Filter(findings []Finding) []Finding {
+	remaining := make(map[Finding]int, len(b.Findings))
+	for _, finding := range b.Findings {
+		remaining[baselineKey(finding)]++
+	}
+
+	var fresh []Finding
+	for _, finding := range findings {
+		key := baselineKey(finding)
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+		fresh = append(fresh, finding)
+	}
+	return fresh
+}
+
+// baselineKey is the part of a finding that identifies it across edits
+func baselineKey(finding Finding) Finding {
+	finding.Line = 0
+	return finding
+}
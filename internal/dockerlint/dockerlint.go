@@ -0,0 +1,57 @@
+// Package dockerlint checks the Dockerfiles of a project's services with
+// hadolint and compares the findings against a baseline of accepted ones, so
+// only new issues need attention.
+package dockerlint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+)
+
+// ErrNotInstalled is returned when hadolint cannot be found on the PATH
+var ErrNotInstalled = errors.New("hadolint is not installed (see https://github.com/hadolint/hadolint#install)")
+
+// Finding is one issue hadolint reported in a service's Dockerfile
+type Finding struct {
+	Service string `json:"-" yaml:"service"`
+	File    string `json:"file" yaml:"file"` // relative to the project root
+	Line    int    `json:"line" yaml:"-"`
+	Code    string `json:"code" yaml:"code"`   // rule, e.g. DL3008 or SC2086
+	Level   string `json:"level" yaml:"level"` // error, warning, info, or style
+	Message string `json:"message" yaml:"message"`
+}
+
+// Lint runs hadolint against the Dockerfile of the service in dir. file is
+// the Dockerfile's path relative to the project root and is recorded on
+// every finding.
+func Lint(run deps.Runner, service, dir, file string) ([]Finding, error) {
+	// --no-fail keeps the exit status zero when there are findings, so a
+	// failure always means hadolint itself could not run
+	output, err := run(dir, "hadolint", "--format", "json", "--no-fail", "Dockerfile")
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, ErrNotInstalled
+		}
+		return nil, err
+	}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+
+	var findings []Finding
+	if err := json.Unmarshal(output, &findings); err != nil {
+		return nil, fmt.Errorf("failed to parse hadolint output: %w", err)
+	}
+	for i := range findings {
+		findings[i].Service = service
+		findings[i].File = file
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Line < findings[j].Line })
+	return findings, nil
+}
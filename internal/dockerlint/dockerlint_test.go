@@ -0,0 +1,76 @@
+package dockerlint
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestLint(t *testing.T) {
+	run := func(dir, name string, args ...string) ([]byte, error) {
+		return []byte(`[
+  {"line": 4, "code": "DL3008", "message": "Pin versions in apt get install", "column": 1, "file": "Dockerfile", "level": "warning"},
+  {"line": 1, "code": "DL3007", "message": "Using latest is prone to errors", "column": 1, "file": "Dockerfile", "level": "warning"}
+]`), nil
+	}
+
+	findings, err := Lint(run, "api", "api", "api/Dockerfile")
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	if len(findings) != 2 || findings[0].Code != "DL3007" || findings[1].Line != 4 {
+		t.Fatalf("expected findings ordered by line, got %+v", findings)
+	}
+	if findings[0].Service != "api" || findings[0].File != "api/Dockerfile" {
+		t.Errorf("expected findings to carry the service and project-relative file, got %+v", findings[0])
+	}
+
+	missing := func(dir, name string, args ...string) ([]byte, error) {
+		return nil, fmt.Errorf("hadolint: %w", &exec.Error{Name: name, Err: exec.ErrNotFound})
+	}
+	if _, err := Lint(missing, "api", "api", "api/Dockerfile"); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("expected ErrNotInstalled, got %v", err)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	pin := Finding{Service: "web", File: "web/Dockerfile", Line: 3, Code: "DL3008", Level: "warning", Message: "Pin versions"}
+	fsys := filesystem.NewMemFS()
+
+	empty, err := LoadBaseline(fsys, "demo")
+	if err != nil {
+		t.Fatalf("LoadBaseline failed: %v", err)
+	}
+	if len(empty.Filter([]Finding{pin})) != 1 {
+		t.Error("expected a missing baseline to accept nothing")
+	}
+
+	if err := NewBaseline([]Finding{pin}).Save(fsys, "demo"); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	baseline, err := LoadBaseline(fsys, "demo")
+	if err != nil {
+		t.Fatalf("LoadBaseline failed: %v", err)
+	}
+
+	// Accepted findings stay accepted when their line moves
+	moved := pin
+	moved.Line = 7
+	if fresh := baseline.Filter([]Finding{moved}); len(fresh) != 0 {
+		t.Errorf("expected the moved finding to be accepted, got %+v", fresh)
+	}
+
+	// A second occurrence of the same rule is new
+	if fresh := baseline.Filter([]Finding{pin, moved}); len(fresh) != 1 {
+		t.Errorf("expected one new finding, got %+v", fresh)
+	}
+
+	other := pin
+	other.Service = "api"
+	if fresh := baseline.Filter([]Finding{other}); len(fresh) != 1 {
+		t.Errorf("expected findings of another service to be new, got %+v", fresh)
+	}
+}
@@ -0,0 +1,151 @@
+// Package examples holds the example projects `om examples create` scaffolds.
+// Each example is a directory under projects/ with an example.yaml listing
+// its services, the templates and answers they are scaffolded with, and
+// their resources, plus a files/ directory with per-service files written
+// over the template output, which turn generic templates into a working
+// demo. Examples are embedded into the binary.
+package examples
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed projects
+var projectsFS embed.FS
+
+// Example is a project om can scaffold in one command
+type Example struct {
+	Name        string    `yaml:"-"`
+	Description string    `yaml:"description"`
+	Services    []Service `yaml:"services"`
+}
+
+// Service is a service of an example, in the order it is scaffolded
+type Service struct {
+	Name        string                 `yaml:"name"`
+	Template    string                 `yaml:"template"`
+	Parameters  map[string]interface{} `yaml:"parameters,omitempty"`
+	Environment map[string]string      `yaml:"environment,omitempty"`
+	Resources   []Resource             `yaml:"resources,omitempty"`
+}
+
+// Resource is a resource of an example service
+type Resource struct {
+	Name    string            `yaml:"name"`
+	Type    string            `yaml:"type"`
+	Version string            `yaml:"version,omitempty"`
+	Config  map[string]string `yaml:"config,omitempty"`
+}
+
+// List returns every example sorted by name
+func List() ([]Example, error) {
+	entries, err := fs.ReadDir(projectsFS, "projects")
+	if err != nil {
+		return nil, err
+	}
+	var examples []Example
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		example, err := Get(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		examples = append(examples, *example)
+	}
+	sort.Slice(examples, func(i, j int) bool {
+		return examples[i].Name < examples[j].Name
+	})
+	return examples, nil
+}
+
+// Names returns the names of every example
+func Names() []string {
+	examples, err := List()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(examples))
+	for _, example := range examples {
+		names = append(names, example.Name)
+	}
+	return names
+}
+
+// Get returns the example with the given name. It reports fs.ErrNotExist
+// for unknown names.
+func Get(name string) (*Example, error) {
+	if name == "" || !fs.ValidPath(name) || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("example '%s': %w", name, fs.ErrNotExist)
+	}
+	data, err := fs.ReadFile(projectsFS, path.Join("projects", name, "example.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("example '%s': %w", name, fs.ErrNotExist)
+	}
+	var example Example
+	if err := yaml.Unmarshal(data, &example); err != nil {
+		return nil, fmt.Errorf("failed to parse example '%s': %w", name, err)
+	}
+	example.Name = name
+	return &example, nil
+}
+
+// Params returns the service's template answers as the types templates
+// take: strings, booleans, and lists of strings
+func (s Service) Params() map[string]interface{} {
+	params := make(map[string]interface{}, len(s.Parameters))
+	for name, value := range s.Parameters {
+		switch v := value.(type) {
+		case bool, string:
+			params[name] = v
+		case []interface{}:
+			values := make([]string, 0, len(v))
+			for _, item := range v {
+				values = append(values, fmt.Sprintf("%v", item))
+			}
+			params[name] = values
+		default:
+			params[name] = fmt.Sprintf("%v", v)
+		}
+	}
+	return params
+}
+
+// Files returns the files the example writes over the output of the
+// service's template, keyed by their slash-separated path inside the
+// service directory
+func (e *Example) Files(service string) (map[string][]byte, error) {
+	root := path.Join("projects", e.Name, "files", service)
+	files := make(map[string][]byte)
+	err := fs.WalkDir(projectsFS, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Services the example does not change have no files
+			if name == root && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		data, err := fs.ReadFile(projectsFS, name)
+		if err != nil {
+			return err
+		}
+		files[strings.TrimPrefix(name, root+"/")] = data
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the files of example '%s': %w", e.Name, err)
+	}
+	return files, nil
+}
@@ -0,0 +1,86 @@
+package examples
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	list, err := List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) == 0 {
+		t.Fatal("List() returned no examples")
+	}
+	for i, example := range list {
+		if example.Name == "" || example.Description == "" {
+			t.Errorf("example %d has no name or description: %+v", i, example)
+		}
+		if len(example.Services) == 0 {
+			t.Errorf("example %s has no services", example.Name)
+		}
+		if i > 0 && list[i-1].Name >= example.Name {
+			t.Errorf("examples are not sorted: %s before %s", list[i-1].Name, example.Name)
+		}
+		for _, service := range example.Services {
+			if service.Name == "" || service.Template == "" {
+				t.Errorf("example %s has a service without a name or template: %+v", example.Name, service)
+			}
+		}
+	}
+}
+
+func TestGet_Unknown(t *testing.T) {
+	for _, name := range []string{"chat-app", "", "../todo-app", "todo-app/files"} {
+		if _, err := Get(name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Get(%q) error = %v, want fs.ErrNotExist", name, err)
+		}
+	}
+}
+
+func TestExampleFiles(t *testing.T) {
+	example, err := Get("todo-app")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	files, err := example.Files("api")
+	if err != nil {
+		t.Fatalf("Files() error = %v", err)
+	}
+	if _, found := files["src/todos.ts"]; !found {
+		t.Errorf("Files(api) = %v, want src/todos.ts", keys(files))
+	}
+
+	// A service the example does not change has no files
+	files, err = example.Files("missing")
+	if err != nil || len(files) != 0 {
+		t.Errorf("Files(missing) = %v, %v, want no files", keys(files), err)
+	}
+}
+
+func TestServiceParams(t *testing.T) {
+	service := Service{Parameters: map[string]interface{}{
+		"IncludeDocker": true,
+		"DatabaseType":  "PostgreSQL",
+		"Port":          8080,
+		"Features":      []interface{}{"auth", 1},
+	}}
+	params := service.Params()
+	if params["IncludeDocker"] != true || params["DatabaseType"] != "PostgreSQL" || params["Port"] != "8080" {
+		t.Errorf("Params() = %v", params)
+	}
+	features, ok := params["Features"].([]string)
+	if !ok || len(features) != 2 || features[1] != "1" {
+		t.Errorf("Params()[Features] = %#v, want []string{auth, 1}", params["Features"])
+	}
+}
+
+func keys(files map[string][]byte) []string {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,52 @@
+import express from 'express'
+import cors from 'cors'
+import helmet from 'helmet'
+import morgan from 'morgan'
+import dotenv from 'dotenv'
+import { todos, migrate } from './todos'
+
+// Load environment variables
+dotenv.config()
+
+const app = express()
+const port = process.env.PORT || 3000
+
+// Middleware
+app.use(helmet())
+app.use(cors())
+app.use(morgan('combined'))
+app.use(express.json())
+
+// Routes
+app.get('/api/status', (req, res) => {
+  res.json({ status: 'OK', timestamp: new Date().toISOString() })
+})
+app.use('/api/todos', todos)
+
+// Error handling middleware
+app.use((err: Error, req: express.Request, res: express.Response, next: express.NextFunction) => {
+  console.error(err.stack)
+  res.status(500).json({ error: err.message })
+})
+
+// The database may still be starting; retry until the table exists
+async function start(attempt = 1): Promise<void> {
+  try {
+    await migrate()
+  } catch (err) {
+    if (attempt >= 30) {
+      throw err
+    }
+    console.log(`⏳ Waiting for the database (attempt ${attempt})...`)
+    await new Promise((resolve) => setTimeout(resolve, 2000))
+    return start(attempt + 1)
+  }
+  app.listen(port, () => {
+    console.log(`🚀 todo API running on port ${port}`)
+  })
+}
+
+start().catch((err) => {
+  console.error(err)
+  process.exit(1)
+})
@@ -0,0 +1,63 @@
+import { Router } from 'express'
+import pool from './database/postgres'
+
+// migrate creates the todos table when it does not exist yet
+export async function migrate(): Promise<void> {
+  await pool.query(`
+    CREATE TABLE IF NOT EXISTS todos (
+      id SERIAL PRIMARY KEY,
+      title TEXT NOT NULL,
+      done BOOLEAN NOT NULL DEFAULT FALSE,
+      created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+    )`)
+}
+
+export const todos = Router()
+
+todos.get('/', async (req, res, next) => {
+  try {
+    const result = await pool.query('SELECT id, title, done FROM todos ORDER BY created_at')
+    res.json(result.rows)
+  } catch (err) {
+    next(err)
+  }
+})
+
+todos.post('/', async (req, res, next) => {
+  const title = typeof req.body.title === 'string' ? req.body.title.trim() : ''
+  if (!title) {
+    res.status(400).json({ error: 'title is required' })
+    return
+  }
+  try {
+    const result = await pool.query('INSERT INTO todos (title) VALUES ($1) RETURNING id, title, done', [title])
+    res.status(201).json(result.rows[0])
+  } catch (err) {
+    next(err)
+  }
+})
+
+todos.patch('/:id', async (req, res, next) => {
+  try {
+    const result = await pool.query(
+      'UPDATE todos SET done = $1 WHERE id = $2 RETURNING id, title, done',
+      [Boolean(req.body.done), req.params.id]
+    )
+    if (result.rowCount === 0) {
+      res.status(404).json({ error: 'todo not found' })
+      return
+    }
+    res.json(result.rows[0])
+  } catch (err) {
+    next(err)
+  }
+})
+
+todos.delete('/:id', async (req, res, next) => {
+  try {
+    await pool.query('DELETE FROM todos WHERE id = $1', [req.params.id])
+    res.status(204).end()
+  } catch (err) {
+    next(err)
+  }
+})
@@ -0,0 +1,86 @@
+import React, { FormEvent, useEffect, useState } from 'react'
+import './App.css'
+
+type Todo = {
+  id: number
+  title: string
+  done: boolean
+}
+
+const api = (import.meta.env.VITE_API_URL || 'http://localhost:3001') + '/api/todos'
+
+function App() {
+  const [todos, setTodos] = useState<Todo[]>([])
+  const [title, setTitle] = useState('')
+  const [error, setError] = useState('')
+
+  const request = async (url: string, init?: RequestInit) => {
+    const response = await fetch(url, {
+      ...init,
+      headers: { 'Content-Type': 'application/json' }
+    })
+    if (!response.ok) {
+      throw new Error(`${response.status} ${response.statusText}`)
+    }
+    return response.status === 204 ? null : response.json()
+  }
+
+  const load = () =>
+    request(api)
+      .then((items: Todo[]) => {
+        setTodos(items)
+        setError('')
+      })
+      .catch((err: Error) => setError(`Could not reach the API: ${err.message}`))
+
+  useEffect(() => {
+    load()
+  }, [])
+
+  const add = async (event: FormEvent) => {
+    event.preventDefault()
+    if (!title.trim()) {
+      return
+    }
+    await request(api, { method: 'POST', body: JSON.stringify({ title }) })
+    setTitle('')
+    load()
+  }
+
+  const toggle = async (todo: Todo) => {
+    await request(`${api}/${todo.id}`, { method: 'PATCH', body: JSON.stringify({ done: !todo.done }) })
+    load()
+  }
+
+  const remove = async (todo: Todo) => {
+    await request(`${api}/${todo.id}`, { method: 'DELETE' })
+    load()
+  }
+
+  return (
+    <div className="App">
+      <main>
+        <h1>Todos</h1>
+        <form onSubmit={add}>
+          <input value={title} onChange={(event) => setTitle(event.target.value)} placeholder="What needs doing?" />
+          <button type="submit">Add</button>
+        </form>
+        {error && <p className="error">{error}</p>}
+        <ul>
+          {todos.map((todo) => (
+            <li key={todo.id}>
+              <label style={{ textDecoration: todo.done ? 'line-through' : 'none' }}>
+                <input type="checkbox" checked={todo.done} onChange={() => toggle(todo)} /> {todo.title}
+              </label>
+              <button onClick={() => remove(todo)} aria-label={`Delete ${todo.title}`}>
+                ✕
+              </button>
+            </li>
+          ))}
+        </ul>
+      </main>
+    </div>
+  )
+}
+
+export default App
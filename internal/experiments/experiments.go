@@ -0,0 +1,156 @@
+// Package experiments gates subsystems that are still taking shape behind
+// opt-in flags, so they can ship one piece at a time without changing what
+// om does for everyone else. Experiments are enabled with the OM_EXPERIMENTS
+// environment variable or the experiments list of ~/.om/config.yaml, and move
+// through stages until they are on by default.
+package experiments
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvVar lists the experiments to enable or, prefixed with "-", disable,
+// separated by commas
+const EnvVar = "OM_EXPERIMENTS"
+
+// Stages an experiment moves through
+const (
+	StageAlpha  = "alpha"  // incomplete; enabled only by name or with "all"
+	StageBeta   = "beta"   // feature complete; also enabled with "beta"
+	StageStable = "stable" // enabled by default; can still be disabled by name
+)
+
+// Experiment is a gated subsystem
+type Experiment struct {
+	Name        string
+	Stage       string
+	Description string
+}
+
+// Known lists every experiment, in the order they are documented
+var Known = []Experiment{
+	{Name: "kubernetes", Stage: StageAlpha, Description: "Kubernetes manifests as an om compose target"},
+	{Name: "plugins", Stage: StageAlpha, Description: "External om-<name> commands on the PATH"},
+	{Name: "tui", Stage: StageAlpha, Description: "Full-screen terminal interface"},
+}
+
+// Lookup returns the named experiment
+func Lookup(name string) (Experiment, bool) {
+	for _, experiment := range Known {
+		if experiment.Name == name {
+			return experiment, true
+		}
+	}
+	return Experiment{}, false
+}
+
+// Settings are the experiments the user turned on or off. Later entries
+// override earlier ones, so the environment variable wins over the config.
+type Settings struct {
+	enabled  map[string]bool
+	all      bool
+	beta     bool
+	disabled map[string]bool
+}
+
+// split returns the entries of a comma or space separated list
+func split(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// apply adds the entries to the settings, in order, and returns the unknown
+// names. "all" enables every experiment, "beta" every beta one, and "-name"
+// disables an experiment.
+func (s *Settings) apply(entries []string) []string {
+	if s.enabled == nil {
+		s.enabled = make(map[string]bool)
+		s.disabled = make(map[string]bool)
+	}
+	var unknown []string
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		name := strings.TrimPrefix(entry, "-")
+		switch {
+		case entry == "":
+		case entry == "all":
+			s.all, s.disabled = true, make(map[string]bool)
+		case entry == StageBeta:
+			s.beta = true
+		case !isKnown(name):
+			unknown = append(unknown, name)
+		case strings.HasPrefix(entry, "-"):
+			s.disabled[name], s.enabled[name] = true, false
+		default:
+			s.enabled[name], s.disabled[name] = true, false
+		}
+	}
+	return unknown
+}
+
+// isKnown reports whether name is a known experiment
+func isKnown(name string) bool {
+	_, exists := Lookup(name)
+	return exists
+}
+
+// userConfig is the experiments section of ~/.om/config.yaml
+type userConfig struct {
+	Experiments []string `yaml:"experiments"`
+}
+
+// Load reads the experiments list of the user config at configPath, when it
+// exists, followed by the value of the environment variable. Unknown names
+// are returned rather than rejected, since a newer om may know them.
+func Load(fsys filesystem.FS, configPath, env string) (*Settings, []string, error) {
+	settings := &Settings{}
+	var unknown []string
+	if configPath != "" && filesystem.Exists(fsys, configPath) {
+		data, err := fsys.ReadFile(configPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", configPath, err)
+		}
+		var config userConfig
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+		}
+		unknown = settings.apply(config.Experiments)
+	}
+	unknown = append(unknown, settings.apply(split(env))...)
+	sort.Strings(unknown)
+	return settings, unknown, nil
+}
+
+// Enabled reports whether the named experiment is on. Unknown experiments
+// are always off.
+func (s *Settings) Enabled(name string) bool {
+	experiment, exists := Lookup(name)
+	if !exists || s.disabled[name] {
+		return false
+	}
+	switch {
+	case s.enabled[name], s.all, experiment.Stage == StageStable:
+		return true
+	case experiment.Stage == StageBeta:
+		return s.beta
+	}
+	return false
+}
+
+// Banner is the warning printed whenever an experimental subsystem runs
+func Banner(experiment Experiment) string {
+	return fmt.Sprintf("🧪 %s is experimental (%s): its behavior, flags, and output may change between releases. Disable it with %s=-%s",
+		experiment.Name, experiment.Stage, EnvVar, experiment.Name)
+}
+
+// DisabledError explains how to turn on an experiment that is off
+func DisabledError(experiment Experiment) error {
+	return fmt.Errorf("%s is experimental and disabled; enable it with %s=%s or by adding it to the experiments list of ~/.om/config.yaml",
+		experiment.Name, EnvVar, experiment.Name)
+}
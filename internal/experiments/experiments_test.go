@@ -0,0 +1,79 @@
+package experiments
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestEnabled(t *testing.T) {
+	original := Known
+	Known = []Experiment{
+		{Name: "kubernetes", Stage: StageAlpha},
+		{Name: "plugins", Stage: StageBeta},
+		{Name: "tui", Stage: StageStable},
+	}
+	t.Cleanup(func() { Known = original })
+
+	tests := []struct {
+		env  string
+		want map[string]bool
+	}{
+		{"", map[string]bool{"kubernetes": false, "plugins": false, "tui": true}},
+		{"kubernetes", map[string]bool{"kubernetes": true, "plugins": false, "tui": true}},
+		{"beta", map[string]bool{"kubernetes": false, "plugins": true, "tui": true}},
+		{"all,-plugins", map[string]bool{"kubernetes": true, "plugins": false, "tui": true}},
+		{"-tui", map[string]bool{"kubernetes": false, "plugins": false, "tui": false}},
+		{"-kubernetes kubernetes", map[string]bool{"kubernetes": true, "plugins": false, "tui": true}},
+		{"KUBERNETES", map[string]bool{"kubernetes": true, "plugins": false, "tui": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			settings, unknown, err := Load(filesystem.NewMemFS(), "", tt.env)
+			if err != nil || len(unknown) > 0 {
+				t.Fatalf("Load failed: %v, unknown %v", err, unknown)
+			}
+			for name, want := range tt.want {
+				if got := settings.Enabled(name); got != want {
+					t.Errorf("%s: expected enabled=%v, got %v", name, want, got)
+				}
+			}
+			if settings.Enabled("warp-drive") {
+				t.Error("expected unknown experiments to be disabled")
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	configPath := filepath.Join("home", ".om", "config.yaml")
+	if err := fsys.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "hooks: []\nexperiments: [kubernetes, tui, warp-drive]\n"
+	if err := fsys.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The environment variable overrides the config file
+	settings, unknown, err := Load(fsys, configPath, "-tui,hover")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !settings.Enabled("kubernetes") || settings.Enabled("tui") {
+		t.Errorf("expected kubernetes from the config and tui disabled by the environment")
+	}
+	if !reflect.DeepEqual(unknown, []string{"hover", "warp-drive"}) {
+		t.Errorf("expected the unknown names to be reported, got %v", unknown)
+	}
+
+	if err := fsys.WriteFile(configPath, []byte("experiments: {"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Load(fsys, configPath, ""); err == nil {
+		t.Error("expected an invalid config file to be rejected")
+	}
+}
@@ -0,0 +1,163 @@
+// Package explain links generated files back to the workbench.yaml entries
+// that produced them. Generators write a marker comment above every block
+// they derive from the manifest; Find reads those markers back.
+package explain
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rules describing how generators turn manifest entries into output
+const (
+	RuleService         = "service"
+	RuleResource        = "resource"
+	RuleComponent       = "component"
+	RuleTraefikGateway  = "traefik-gateway"
+	RuleGraphQLGateway  = "graphql-gateway"
+	RuleAPIDocs         = "api-docs"
+	RuleMock            = "mock"
+	RuleECSService      = "ecs-service"
+	RuleECSComponent    = "ecs-component"
+	RuleTerraformModule = "terraform-module"
+)
+
+// Rule explains one generator rule
+type Rule struct {
+	Description string // what the generator produced
+	Change      string // how to change it
+}
+
+// rules documents every rule generators mark their output with
+var rules = map[string]Rule{
+	RuleService: {
+		Description: "om compose builds the service from its path and publishes its port, environment, and gateway labels.",
+		Change:      "Edit the service in workbench.yaml (port, protocol, subdomain, environment) and re-run om compose.",
+	},
+	RuleResource: {
+		Description: "om compose runs the resource from its blueprint, with credentials taken from .env.",
+		Change:      "Edit the resource's type, version, or config in workbench.yaml, or use om add resource / om delete resource, and re-run om compose.",
+	},
+	RuleComponent: {
+		Description: "om compose builds the component from its path and publishes its ports.",
+		Change:      "Edit the component in workbench.yaml and re-run om compose.",
+	},
+	RuleTraefikGateway: {
+		Description: "om compose runs Traefik for a traefik-gateway component; services are routed by their own labels.",
+		Change:      "Edit the component's ports in workbench.yaml, or a service's subdomain to change its route, and re-run om compose.",
+	},
+	RuleGraphQLGateway: {
+		Description: "om compose runs GraphQL Mesh for a graphql-gateway component and starts it after every service with a graphql section.",
+		Change:      "Add or edit graphql.path on services in workbench.yaml and re-run om compose.",
+	},
+	RuleAPIDocs: {
+		Description: "om compose serves the api.spec of every service from one Swagger UI container.",
+		Change:      "Add or edit api.spec on services in workbench.yaml and re-run om compose.",
+	},
+	RuleMock: {
+		Description: "om compose mocks an external dependency with Prism because it sets spec.",
+		Change:      "Edit or remove the dependency's spec in workbench.yaml and re-run om compose.",
+	},
+	RuleECSService: {
+		Description: "The Terraform target deploys the service to ECS, behind the load balancer when it has an HTTP or gRPC port.",
+		Change:      "Edit the service in workbench.yaml (port, protocol, environment) or the environment's services list and regenerate.",
+	},
+	RuleECSComponent: {
+		Description: "The Terraform target deploys the component to ECS.",
+		Change:      "Edit the component in workbench.yaml and regenerate.",
+	},
+	RuleTerraformModule: {
+		Description: "The Terraform target provisions the resource with the module of its blueprint, written to terraform/modules; passwords are variables set in terraform.tfvars.",
+		Change:      "Edit the resource's type, version, or config in workbench.yaml and regenerate.",
+	},
+}
+
+// Origin identifies the manifest entry and rule that produced a block of
+// generated output
+type Origin struct {
+	Entry string // manifest path such as services.backend
+	Rule  string
+}
+
+// Marker returns the comment text, without the comment character, that a
+// generator writes above the block
+func (o Origin) Marker() string {
+	return fmt.Sprintf("om: %s (%s)", o.Entry, o.Rule)
+}
+
+// Describe returns the explanation of the origin's rule
+func (o Origin) Describe() (Rule, bool) {
+	rule, ok := rules[o.Rule]
+	return rule, ok
+}
+
+// markerPattern matches a marker comment in YAML or HCL
+var markerPattern = regexp.MustCompile(`^(\s*)#\s*om: (\S+) \(([^)]+)\)\s*$`)
+
+// Find returns the origin of the given 1-based line of a generated file and
+// the line its marker is on. The origin is the nearest marker above the line
+// that encloses it; lines outside every marked block are fixed parts of the
+// generator output and report found as false.
+func Find(content []byte, line int) (origin Origin, markerLine int, found bool, err error) {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return Origin{}, 0, false, fmt.Errorf("failed to read file: %w", err)
+	}
+	if line < 1 || line > len(lines) {
+		return Origin{}, 0, false, fmt.Errorf("line %d is out of range (the file has %d lines)", line, len(lines))
+	}
+
+	// Walk up from the line, tracking the shallowest indentation seen. A
+	// marker encloses the line when it is no deeper than that; for HCL, a
+	// closing brace in the first column ends the search.
+	minIndent := -1
+	for i := line - 1; i >= 0; i-- {
+		text := lines[i]
+		if match := markerPattern.FindStringSubmatch(text); match != nil {
+			if minIndent < 0 || len(match[1]) <= minIndent {
+				return Origin{Entry: match[2], Rule: match[3]}, i + 1, true, nil
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(text) - len(strings.TrimLeft(text, " \t"))
+
+		// A top-level block closed above the line does not enclose it
+		if i < line-1 && indent == 0 && minIndent == 0 && strings.HasPrefix(trimmed, "}") {
+			return Origin{}, 0, false, nil
+		}
+		if minIndent < 0 || indent < minIndent {
+			minIndent = indent
+		}
+	}
+	return Origin{}, 0, false, nil
+}
+
+// Marked is a marker found in a generated file
+type Marked struct {
+	Origin Origin
+	Line   int
+}
+
+// Markers returns every marker of a generated file in file order
+func Markers(content []byte) []Marked {
+	var markers []Marked
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		if match := markerPattern.FindStringSubmatch(scanner.Text()); match != nil {
+			markers = append(markers, Marked{Origin: Origin{Entry: match[2], Rule: match[3]}, Line: line})
+		}
+	}
+	return markers
+}
@@ -0,0 +1,91 @@
+package explain
+
+import "testing"
+
+const composeFile = `# THIS FILE IS AUTO-GENERATED BY 'om compose'.
+
+services:
+    # om: services.api (service)
+    api:
+        build:
+            context: api
+        ports:
+            - 8000:8000
+    # om: services.api.resources.db (resource)
+    api-db:
+        image: postgres:15
+volumes:
+    api_db_data: null
+`
+
+const terraformFile = `provider "aws" {
+  region = var.aws_region
+}
+
+# Service: api
+# om: services.api (ecs-service)
+resource "aws_ecs_service" "api" {
+  name = "api"
+  network_configuration {
+    subnets = [aws_subnet.public.id]
+  }
+}
+
+# om: services.api (ecs-service)
+resource "aws_ecs_task_definition" "api" {
+  family = "api"
+}
+
+output "cluster" {
+  value = aws_ecs_cluster.main.id
+}
+`
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		line       int
+		found      bool
+		entry      string
+		markerLine int
+	}{
+		{"compose service property", composeFile, 9, true, "services.api", 4},
+		{"compose service key", composeFile, 5, true, "services.api", 4},
+		{"compose resource", composeFile, 12, true, "services.api.resources.db", 10},
+		{"compose top-level section", composeFile, 14, false, "", 0},
+		{"compose header", composeFile, 1, false, "", 0},
+		{"terraform nested block", terraformFile, 10, true, "services.api", 6},
+		{"terraform closing brace", terraformFile, 12, true, "services.api", 6},
+		{"terraform second resource", terraformFile, 16, true, "services.api", 14},
+		{"terraform fixed block", terraformFile, 20, false, "", 0},
+		{"terraform provider", terraformFile, 2, false, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origin, markerLine, found, err := Find([]byte(tt.content), tt.line)
+			if err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+			if found != tt.found || origin.Entry != tt.entry || markerLine != tt.markerLine {
+				t.Errorf("expected (%v, %q, line %d), got (%v, %q, line %d)",
+					tt.found, tt.entry, tt.markerLine, found, origin.Entry, markerLine)
+			}
+		})
+	}
+
+	if _, _, _, err := Find([]byte(composeFile), 100); err == nil {
+		t.Error("expected an error for a line past the end of the file")
+	}
+}
+
+func TestMarkers(t *testing.T) {
+	markers := Markers([]byte(terraformFile))
+	if len(markers) != 2 || markers[0].Line != 6 || markers[1].Line != 14 {
+		t.Fatalf("unexpected markers: %+v", markers)
+	}
+	if rule, ok := markers[0].Origin.Describe(); !ok || rule.Change == "" {
+		t.Errorf("expected a description of the %s rule", markers[0].Origin.Rule)
+	}
+}
@@ -0,0 +1,222 @@
+package filesystem
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+var (
+	errIsDir    = errors.New("is a directory")
+	errNotDir   = errors.New("not a directory")
+	errNotEmpty = errors.New("directory not empty")
+)
+
+// OperationType identifies a mutating file system operation
+type OperationType string
+
+const (
+	// OpWrite records a file being created or overwritten
+	OpWrite OperationType = "write"
+	// OpMkdir records a directory being created
+	OpMkdir OperationType = "mkdir"
+	// OpRemove records a file or directory tree being removed
+	OpRemove OperationType = "remove"
+)
+
+// Operation is a single change recorded by a DryRunFS
+type Operation struct {
+	Type OperationType
+	Path string
+	Size int // bytes written, for OpWrite
+}
+
+// DryRunFS records every change instead of applying it to the underlying FS.
+// Reads see the recorded changes layered over the underlying FS, so multi-step
+// operations (create a directory, then write into it) behave as they would
+// for real.
+type DryRunFS struct {
+	mutex   sync.Mutex
+	base    FS
+	overlay *MemFS
+	removed map[string]bool
+	ops     []Operation
+}
+
+// NewDryRunFS wraps base so that writes are recorded but never performed
+func NewDryRunFS(base FS) *DryRunFS {
+	return &DryRunFS{
+		base:    base,
+		overlay: NewMemFS(),
+		removed: make(map[string]bool),
+	}
+}
+
+// Operations returns the recorded changes in the order they were made
+func (d *DryRunFS) Operations() []Operation {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]Operation(nil), d.ops...)
+}
+
+// isRemoved reports whether name or one of its parents was removed
+func (d *DryRunFS) isRemoved(name string) bool {
+	for current := clean(name); ; current = filepath.Dir(current) {
+		if d.removed[current] {
+			return true
+		}
+		if isRoot(current) {
+			return false
+		}
+	}
+}
+
+func (d *DryRunFS) record(op Operation) {
+	d.ops = append(d.ops, op)
+}
+
+func (d *DryRunFS) ReadFile(name string) ([]byte, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if data, err := d.overlay.ReadFile(name); err == nil {
+		return data, nil
+	}
+	if d.isRemoved(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return d.base.ReadFile(name)
+}
+
+func (d *DryRunFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	parent := filepath.Dir(clean(name))
+	if !d.existsLocked(parent) {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	// Mirror the parent into the overlay so the write succeeds there
+	if err := d.overlay.MkdirAll(parent, 0755); err != nil {
+		return err
+	}
+	if err := d.overlay.WriteFile(name, data, perm); err != nil {
+		return err
+	}
+	delete(d.removed, clean(name))
+	d.record(Operation{Type: OpWrite, Path: clean(name), Size: len(data)})
+	return nil
+}
+
+func (d *DryRunFS) MkdirAll(path string, perm fs.FileMode) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.existsLocked(path) {
+		return nil
+	}
+	if err := d.overlay.MkdirAll(path, perm); err != nil {
+		return err
+	}
+	for current := clean(path); !isRoot(current); current = filepath.Dir(current) {
+		delete(d.removed, current)
+	}
+	d.record(Operation{Type: OpMkdir, Path: clean(path)})
+	return nil
+}
+
+func (d *DryRunFS) Remove(name string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if !d.existsLocked(name) {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	_ = d.overlay.RemoveAll(name)
+	d.removed[clean(name)] = true
+	d.record(Operation{Type: OpRemove, Path: clean(name)})
+	return nil
+}
+
+func (d *DryRunFS) RemoveAll(path string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if !d.existsLocked(path) {
+		return nil
+	}
+	_ = d.overlay.RemoveAll(path)
+	d.removed[clean(path)] = true
+	d.record(Operation{Type: OpRemove, Path: clean(path)})
+	return nil
+}
+
+func (d *DryRunFS) Stat(name string) (fs.FileInfo, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if info, err := d.overlay.Stat(name); err == nil && !isRoot(clean(name)) {
+		return info, nil
+	}
+	if d.isRemoved(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return d.base.Stat(name)
+}
+
+func (d *DryRunFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	merged := make(map[string]fs.DirEntry)
+	var baseErr error
+	if !d.isRemoved(name) {
+		var entries []fs.DirEntry
+		entries, baseErr = d.base.ReadDir(name)
+		for _, entry := range entries {
+			if !d.removed[filepath.Join(clean(name), entry.Name())] {
+				merged[entry.Name()] = entry
+			}
+		}
+	}
+
+	overlayEntries, overlayErr := d.overlay.ReadDir(name)
+	for _, entry := range overlayEntries {
+		merged[entry.Name()] = entry
+	}
+	if baseErr != nil && (overlayErr != nil || len(overlayEntries) == 0) && !d.overlayHasDir(name) {
+		return nil, baseErr
+	}
+
+	result := make([]fs.DirEntry, 0, len(merged))
+	for _, entry := range merged {
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name() < result[j].Name() })
+	return result, nil
+}
+
+// overlayHasDir reports whether the overlay created the directory name
+func (d *DryRunFS) overlayHasDir(name string) bool {
+	info, err := d.overlay.Stat(name)
+	return err == nil && info.IsDir() && !isRoot(clean(name))
+}
+
+// existsLocked reports whether name exists in the overlay or the base FS.
+// The caller must hold the lock.
+func (d *DryRunFS) existsLocked(name string) bool {
+	if isRoot(clean(name)) {
+		return true
+	}
+	if _, err := d.overlay.Stat(name); err == nil {
+		return true
+	}
+	if d.isRemoved(name) {
+		return false
+	}
+	_, err := d.base.Stat(name)
+	return err == nil
+}
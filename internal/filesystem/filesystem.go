@@ -0,0 +1,87 @@
+// Package filesystem provides the file system abstraction used by the command
+// layer, the templating engine, and the generators. Production code uses the
+// real file system; tests use an in-memory implementation, and preview modes
+// wrap either one with a recorder that never touches the disk.
+package filesystem
+
+import (
+	"io/fs"
+	"os"
+)
+
+// FS is the set of file system operations used when scaffolding projects,
+// writing manifests, and generating deployment configuration. Paths use the
+// host path syntax, exactly as they would be passed to the os package.
+type FS interface {
+	// ReadFile reads the named file
+	ReadFile(name string) ([]byte, error)
+
+	// WriteFile writes data to the named file, creating it if necessary.
+	// The parent directory must already exist.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+
+	// MkdirAll creates a directory along with any necessary parents
+	MkdirAll(path string, perm fs.FileMode) error
+
+	// Remove removes the named file or empty directory
+	Remove(name string) error
+
+	// RemoveAll removes path and any children it contains
+	RemoveAll(path string) error
+
+	// Stat returns file information for the named file
+	Stat(name string) (fs.FileInfo, error)
+
+	// ReadDir reads the named directory, returning its entries sorted by name
+	ReadDir(name string) ([]fs.DirEntry, error)
+}
+
+// osFS implements FS using the os package
+type osFS struct{}
+
+// NewOSFS returns an FS backed by the real file system
+func NewOSFS() FS {
+	return osFS{}
+}
+
+func (osFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (osFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (osFS) MkdirAll(path string, perm fs.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (osFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+func (osFS) RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
+
+func (osFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+// IsOS reports whether fsys writes to the real file system. Callers use it
+// to skip side effects, such as running shell commands, that only make sense
+// when files actually exist on disk.
+func IsOS(fsys FS) bool {
+	_, ok := fsys.(osFS)
+	return ok
+}
+
+// Exists reports whether the named file or directory exists
+func Exists(fsys FS, name string) bool {
+	_, err := fsys.Stat(name)
+	return err == nil
+}
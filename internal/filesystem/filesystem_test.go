@@ -0,0 +1,137 @@
+package filesystem
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMemFS_WriteAndRead(t *testing.T) {
+	m := NewMemFS()
+
+	if err := m.WriteFile(filepath.Join("missing", "file.txt"), []byte("x"), 0644); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist writing into a missing directory, got %v", err)
+	}
+
+	if err := m.MkdirAll(filepath.Join("project", "api"), 0755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	path := filepath.Join("project", "api", "main.go")
+	if err := m.WriteFile(path, []byte("package main"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	data, err := m.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(data) != "package main" {
+		t.Errorf("unexpected content: %q", string(data))
+	}
+
+	if _, err := m.ReadFile("nope.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+	if err := m.MkdirAll(filepath.Join(path, "sub"), 0755); err == nil {
+		t.Error("expected MkdirAll under a file to fail")
+	}
+
+	if got := m.Files(); !reflect.DeepEqual(got, []string{path}) {
+		t.Errorf("Files() = %v, want [%s]", got, path)
+	}
+}
+
+func TestMemFS_ReadDirAndRemove(t *testing.T) {
+	m := NewMemFS()
+	if err := m.MkdirAll("dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"b.txt", "a.txt"} {
+		if err := m.WriteFile(filepath.Join("dir", name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := m.ReadDir("dir")
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name() != "a.txt" || entries[1].Name() != "b.txt" {
+		t.Errorf("unexpected entries: %v", entries)
+	}
+
+	if err := m.Remove("dir"); err == nil {
+		t.Error("expected Remove of a non-empty directory to fail")
+	}
+	if err := m.RemoveAll("dir"); err != nil {
+		t.Fatalf("RemoveAll failed: %v", err)
+	}
+	if Exists(m, "dir") || Exists(m, filepath.Join("dir", "a.txt")) {
+		t.Error("expected directory tree to be removed")
+	}
+	if !Exists(m, ".") {
+		t.Error("expected current directory to always exist")
+	}
+}
+
+func TestDryRunFS_RecordsWithoutWriting(t *testing.T) {
+	base := NewMemFS()
+	if err := base.WriteFile("existing.txt", []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDryRunFS(base)
+	if err := d.MkdirAll("project", 0755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := d.WriteFile(filepath.Join("project", "workbench.yaml"), []byte("name: demo"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := d.Remove("existing.txt"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+
+	// Reads see the pending changes
+	data, err := d.ReadFile(filepath.Join("project", "workbench.yaml"))
+	if err != nil || string(data) != "name: demo" {
+		t.Errorf("expected pending write to be readable, got %q, %v", string(data), err)
+	}
+	if Exists(d, "existing.txt") {
+		t.Error("expected removed file to be hidden")
+	}
+
+	// The underlying file system is untouched
+	if got := base.Files(); !reflect.DeepEqual(got, []string{"existing.txt"}) {
+		t.Errorf("base file system was modified: %v", got)
+	}
+
+	want := []Operation{
+		{Type: OpMkdir, Path: "project"},
+		{Type: OpWrite, Path: filepath.Join("project", "workbench.yaml"), Size: 10},
+		{Type: OpRemove, Path: "existing.txt"},
+	}
+	if got := d.Operations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Operations() = %+v, want %+v", got, want)
+	}
+}
+
+func TestOSFS(t *testing.T) {
+	fsys := NewOSFS()
+	if !IsOS(fsys) {
+		t.Error("expected NewOSFS to report IsOS")
+	}
+	if IsOS(NewMemFS()) || IsOS(NewDryRunFS(fsys)) {
+		t.Error("expected in-memory file systems not to report IsOS")
+	}
+
+	path := filepath.Join(t.TempDir(), "file.txt")
+	if err := fsys.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "hello" {
+		t.Errorf("expected file on disk, got %q, %v", string(data), err)
+	}
+}
@@ -0,0 +1,217 @@
+package filesystem
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memFile is a file or directory stored in a MemFS
+type memFile struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// MemFS is an in-memory FS for tests. It follows the semantics of the os
+// package closely enough that code behaves the same against either: writing
+// a file requires its parent directory to exist, errors are *fs.PathError
+// values wrapping fs.ErrNotExist / fs.ErrExist, and so on.
+type MemFS struct {
+	mutex sync.RWMutex
+	files map[string]*memFile
+}
+
+// NewMemFS creates an empty in-memory file system. The root directory and
+// the current directory (".") always exist.
+func NewMemFS() *MemFS {
+	return &MemFS{files: make(map[string]*memFile)}
+}
+
+// clean normalizes a path for use as a map key
+func clean(name string) string {
+	return filepath.Clean(name)
+}
+
+// isRoot reports whether name refers to a directory that always exists
+func isRoot(name string) bool {
+	return name == "." || name == string(filepath.Separator) || filepath.Dir(name) == name
+}
+
+// lookup returns the entry for name, treating root directories as present
+func (m *MemFS) lookup(name string) (*memFile, bool) {
+	if isRoot(name) {
+		return &memFile{mode: fs.ModeDir | 0755}, true
+	}
+	f, ok := m.files[name]
+	return f, ok
+}
+
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	name = clean(name)
+	f, ok := m.lookup(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if f.mode.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errIsDir}
+	}
+	return append([]byte(nil), f.data...), nil
+}
+
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	name = clean(name)
+	parent, ok := m.lookup(filepath.Dir(name))
+	if !ok {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if !parent.mode.IsDir() {
+		return &fs.PathError{Op: "open", Path: name, Err: errNotDir}
+	}
+	if existing, ok := m.lookup(name); ok && existing.mode.IsDir() {
+		return &fs.PathError{Op: "open", Path: name, Err: errIsDir}
+	}
+
+	m.files[name] = &memFile{
+		data:    append([]byte(nil), data...),
+		mode:    perm.Perm(),
+		modTime: time.Now(),
+	}
+	return nil
+}
+
+func (m *MemFS) MkdirAll(path string, perm fs.FileMode) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	path = clean(path)
+	var missing []string
+	for current := path; !isRoot(current); current = filepath.Dir(current) {
+		f, ok := m.files[current]
+		if ok {
+			if !f.mode.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: current, Err: errNotDir}
+			}
+			break
+		}
+		missing = append(missing, current)
+	}
+
+	now := time.Now()
+	for _, dir := range missing {
+		m.files[dir] = &memFile{mode: fs.ModeDir | perm.Perm(), modTime: now}
+	}
+	return nil
+}
+
+func (m *MemFS) Remove(name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	name = clean(name)
+	f, ok := m.files[name]
+	if !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if f.mode.IsDir() && len(m.children(name)) > 0 {
+		return &fs.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+	}
+	delete(m.files, name)
+	return nil
+}
+
+func (m *MemFS) RemoveAll(path string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	path = clean(path)
+	prefix := path + string(filepath.Separator)
+	for name := range m.files {
+		if name == path || strings.HasPrefix(name, prefix) {
+			delete(m.files, name)
+		}
+	}
+	return nil
+}
+
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	name = clean(name)
+	f, ok := m.lookup(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return &memFileInfo{name: filepath.Base(name), file: f}, nil
+}
+
+func (m *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	name = clean(name)
+	f, ok := m.lookup(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if !f.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdirent", Path: name, Err: errNotDir}
+	}
+
+	var entries []fs.DirEntry
+	for _, child := range m.children(name) {
+		info := &memFileInfo{name: filepath.Base(child), file: m.files[child]}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// Files returns the paths of all regular files, sorted
+func (m *MemFS) Files() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var names []string
+	for name, f := range m.files {
+		if !f.mode.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// children returns the direct children of dir. The caller must hold the lock.
+func (m *MemFS) children(dir string) []string {
+	var result []string
+	for name := range m.files {
+		if filepath.Dir(name) == dir && name != dir {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// memFileInfo implements fs.FileInfo for MemFS entries
+type memFileInfo struct {
+	name string
+	file *memFile
+}
+
+func (i *memFileInfo) Name() string       { return i.name }
+func (i *memFileInfo) Size() int64        { return int64(len(i.file.data)) }
+func (i *memFileInfo) Mode() fs.FileMode  { return i.file.mode }
+func (i *memFileInfo) ModTime() time.Time { return i.file.modTime }
+func (i *memFileInfo) IsDir() bool        { return i.file.mode.IsDir() }
+func (i *memFileInfo) Sys() interface{}   { return nil }
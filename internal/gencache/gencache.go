@@ -0,0 +1,245 @@
+// Package gencache lets generators skip work that would reproduce the files
+// already on disk. A run is recorded with a key describing its inputs (the
+// manifest and the selected target, environment, and group), the files the
+// generator read, and the files it wrote. A later run with the same key is
+// up to date while none of those files changed, like a build tool's cache.
+package gencache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the location of the cache relative to the project root
+var FileName = filepath.Join(".om", "cache", "generate.yaml")
+
+// missing is the hash recorded for a file that did not exist
+const missing = "missing"
+
+// Entry is the record of the last run of one generator configuration
+type Entry struct {
+	Key      string             `yaml:"key"`
+	Reads    map[string]string  `yaml:"reads,omitempty"`   // project-relative path to content hash
+	Outputs  map[string]string  `yaml:"outputs,omitempty"` // project-relative path to content hash
+	Warnings []warnings.Warning `yaml:"warnings,omitempty"`
+}
+
+// Cache is the set of recorded runs of a project
+type Cache struct {
+	fsys        filesystem.FS
+	projectRoot string
+	Entries     map[string]Entry `yaml:"entries"` // keyed by target, e.g. docker or docker/group=data
+}
+
+// Load reads the cache of a project. A missing or unreadable cache is
+// treated as empty, since it only ever saves work.
+func Load(fsys filesystem.FS, projectRoot string) *Cache {
+	cache := &Cache{fsys: fsys, projectRoot: projectRoot}
+	if data, err := fsys.ReadFile(filepath.Join(projectRoot, FileName)); err == nil {
+		_ = yaml.Unmarshal(data, cache)
+	}
+	if cache.Entries == nil {
+		cache.Entries = make(map[string]Entry)
+	}
+	return cache
+}
+
+// Key hashes the values a generator run depends on into a cache key
+func Key(values ...interface{}) (string, error) {
+	hash := sha256.New()
+	for _, value := range values {
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash generator inputs: %w", err)
+		}
+		hash.Write(data)
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Lookup returns the recorded run of a target when it was made with the same
+// key and none of the files it read or wrote changed since
+func (c *Cache) Lookup(target, key string) (Entry, bool) {
+	entry, ok := c.Entries[target]
+	if !ok || entry.Key != key {
+		return Entry{}, false
+	}
+	for path, hash := range entry.Outputs {
+		if c.hashFile(path) != hash {
+			return Entry{}, false
+		}
+	}
+	for path, hash := range entry.Reads {
+		if _, written := entry.Outputs[path]; written {
+			continue // files the generator updates, like .gitignore, are checked as outputs
+		}
+		if c.hashFile(path) != hash {
+			return Entry{}, false
+		}
+	}
+	return entry, true
+}
+
+// Record stores a run of a target from the files a TrackingFS saw and the
+// warnings the generator reported
+func (c *Cache) Record(target, key string, tracker *TrackingFS, found []warnings.Warning) {
+	entry := Entry{
+		Key:      key,
+		Reads:    make(map[string]string),
+		Outputs:  make(map[string]string),
+		Warnings: found,
+	}
+	for path, hash := range tracker.reads {
+		entry.Reads[c.relative(path)] = hash
+	}
+	for _, path := range tracker.Written() {
+		rel := c.relative(path)
+		entry.Outputs[rel] = c.hashFile(rel)
+	}
+	c.Entries[target] = entry
+}
+
+// Targets returns the sorted targets the cache holds runs of, without the
+// group or environment part of their entry names
+func (c *Cache) Targets() []string {
+	seen := make(map[string]bool)
+	var targets []string
+	for name := range c.Entries {
+		target := strings.SplitN(name, "/", 2)[0]
+		if !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// Outputs returns the sorted project-relative paths of the files written by
+// every recorded run
+func (c *Cache) Outputs() []string {
+	seen := make(map[string]bool)
+	var outputs []string
+	for _, entry := range c.Entries {
+		for path := range entry.Outputs {
+			if !seen[path] {
+				seen[path] = true
+				outputs = append(outputs, path)
+			}
+		}
+	}
+	sort.Strings(outputs)
+	return outputs
+}
+
+// Save writes the cache to the project
+func (c *Cache) Save() error {
+	path := filepath.Join(c.projectRoot, FileName)
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", FileName, err)
+	}
+	if err := c.fsys.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := c.fsys.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// hashFile returns the content hash of a project-relative file
+func (c *Cache) hashFile(rel string) string {
+	data, err := c.fsys.ReadFile(filepath.Join(c.projectRoot, filepath.FromSlash(rel)))
+	if err != nil {
+		return missing
+	}
+	return hashContent(data)
+}
+
+// relative returns a path relative to the project root with forward slashes,
+// so caches stay valid when the project moves
+func (c *Cache) relative(path string) string {
+	rel, err := filepath.Rel(c.projectRoot, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// hashContent returns the hash recorded for file content
+func hashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// TrackingFS passes every operation through to another FS and remembers
+// which files were read, with their content at the time, and which were
+// written
+type TrackingFS struct {
+	filesystem.FS
+	reads   map[string]string
+	written map[string]bool
+}
+
+// NewTrackingFS wraps base in a TrackingFS
+func NewTrackingFS(base filesystem.FS) *TrackingFS {
+	return &TrackingFS{FS: base, reads: make(map[string]string), written: make(map[string]bool)}
+}
+
+// ReadFile reads a file and records its content
+func (t *TrackingFS) ReadFile(name string) ([]byte, error) {
+	data, err := t.FS.ReadFile(name)
+	if _, seen := t.reads[name]; !seen && !t.written[name] {
+		switch {
+		case err == nil:
+			t.reads[name] = hashContent(data)
+		case errors.Is(err, fs.ErrNotExist):
+			t.reads[name] = missing
+		}
+	}
+	return data, err
+}
+
+// WriteFile writes a file and records that it was written
+func (t *TrackingFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if err := t.FS.WriteFile(name, data, perm); err != nil {
+		return err
+	}
+	t.written[name] = true
+	return nil
+}
+
+// Read returns the files that were read and existed, sorted. Files written
+// before they were read are not included.
+func (t *TrackingFS) Read() []string {
+	paths := make([]string, 0, len(t.reads))
+	for path, hash := range t.reads {
+		if hash != missing {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Written returns the written files, sorted
+func (t *TrackingFS) Written() []string {
+	paths := make([]string, 0, len(t.written))
+	for path := range t.written {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,139 @@
+package gencache
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+)
+
+// generate simulates a generator that reads a Dockerfile and writes a
+// compose file, recording the run in a freshly loaded cache
+func generate(t *testing.T, fsys filesystem.FS, key string) {
+	t.Helper()
+	cache := Load(fsys, "project")
+	tracker := NewTrackingFS(fsys)
+	_, _ = tracker.ReadFile(filepath.Join("project", "api", "Dockerfile"))
+	if err := tracker.WriteFile(filepath.Join("project", "docker-compose.yml"), []byte("services: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cache.Record("docker", key, tracker, []warnings.Warning{warnings.New("services.api", "no HEALTHCHECK")})
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newProject(t *testing.T) *filesystem.MemFS {
+	t.Helper()
+	fsys := filesystem.NewMemFS()
+	if err := fsys.MkdirAll(filepath.Join("project", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(filepath.Join("project", "api", "Dockerfile"), []byte("FROM node:20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return fsys
+}
+
+func TestLookup_UpToDate(t *testing.T) {
+	fsys := newProject(t)
+	generate(t, fsys, "key")
+
+	entry, ok := Load(fsys, "project").Lookup("docker", "key")
+	if !ok {
+		t.Fatal("expected the recorded run to be up to date")
+	}
+	if len(entry.Warnings) != 1 || entry.Warnings[0].Entry != "services.api" {
+		t.Errorf("expected the recorded warning, got %v", entry.Warnings)
+	}
+	if _, ok := entry.Outputs["docker-compose.yml"]; !ok {
+		t.Errorf("expected outputs relative to the project, got %v", entry.Outputs)
+	}
+}
+
+func TestLookup_Stale(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		target string
+		change func(fsys filesystem.FS) error
+	}{
+		{name: "different key", key: "other", target: "docker"},
+		{name: "other target", key: "key", target: "terraform"},
+		{name: "input changed", key: "key", target: "docker", change: func(fsys filesystem.FS) error {
+			return fsys.WriteFile(filepath.Join("project", "api", "Dockerfile"), []byte("FROM node:22\n"), 0644)
+		}},
+		{name: "output edited", key: "key", target: "docker", change: func(fsys filesystem.FS) error {
+			return fsys.WriteFile(filepath.Join("project", "docker-compose.yml"), []byte("services: {api: {}}\n"), 0644)
+		}},
+		{name: "output deleted", key: "key", target: "docker", change: func(fsys filesystem.FS) error {
+			return fsys.Remove(filepath.Join("project", "docker-compose.yml"))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := newProject(t)
+			generate(t, fsys, "key")
+			if tt.change != nil {
+				if err := tt.change(fsys); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, ok := Load(fsys, "project").Lookup(tt.target, tt.key); ok {
+				t.Error("expected the recorded run to be stale")
+			}
+		})
+	}
+}
+
+func TestLoad_Corrupt(t *testing.T) {
+	fsys := newProject(t)
+	if err := fsys.MkdirAll(filepath.Join("project", ".om", "cache"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(filepath.Join("project", FileName), []byte("entries: ["), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Load(fsys, "project").Lookup("docker", "key"); ok {
+		t.Error("expected a corrupt cache to be empty")
+	}
+}
+
+func TestKey(t *testing.T) {
+	a, err := Key("v1", "docker", map[string]int{"port": 8000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := Key("v1", "docker", map[string]int{"port": 8000})
+	c, _ := Key("v1", "docker", map[string]int{"port": 9000})
+	if a != b {
+		t.Error("expected equal inputs to give equal keys")
+	}
+	if a == c {
+		t.Error("expected different inputs to give different keys")
+	}
+}
+
+func TestTargets(t *testing.T) {
+	cache := Load(filesystem.NewMemFS(), "project")
+	if len(cache.Targets()) != 0 {
+		t.Error("expected an empty cache to have no targets")
+	}
+
+	cache.Entries["docker"] = Entry{}
+	cache.Entries["docker/group=data"] = Entry{}
+	cache.Entries["terraform/env=prod"] = Entry{}
+	if got := cache.Targets(); len(got) != 2 || got[0] != "docker" || got[1] != "terraform" {
+		t.Errorf("expected docker and terraform, got %v", got)
+	}
+}
+
+func TestOutputs(t *testing.T) {
+	cache := Load(filesystem.NewMemFS(), "project")
+	cache.Entries["docker"] = Entry{Outputs: map[string]string{"docker-compose.yml": "a", ".env": "b"}}
+	cache.Entries["docker/group=data"] = Entry{Outputs: map[string]string{"docker-compose.yml": "c"}}
+	if got := cache.Outputs(); len(got) != 2 || got[0] != ".env" || got[1] != "docker-compose.yml" {
+		t.Errorf("expected .env and docker-compose.yml, got %v", got)
+	}
+}
@@ -1,20 +1,91 @@
 package docker
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/generator"
+	"github.com/jashkahar/open-workbench-platform/internal/labels"
 	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/ports"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
 )
 
+// PrerequisiteChecker verifies that the Docker tooling is installed
+type PrerequisiteChecker interface {
+	CheckAllPrerequisites() error
+	GetDockerComposeCommand() string
+}
+
 // Generator implements the Generator interface for Docker Compose
-type Generator struct{}
+type Generator struct {
+	fs        filesystem.FS
+	outputDir string
+	checker   PrerequisiteChecker
+	labels    labels.Info
+	host      string
+	engine    compose.Engine
+	secrets   compose.SecretsProvider
+	available func(port int) bool
+	warnings  []warnings.Warning
+}
+
+var _ generator.Generator = (*Generator)(nil)
 
-// NewGenerator creates a new Docker generator
+// NewGenerator creates a new Docker generator that writes to the current directory
 func NewGenerator() *Generator {
-	return &Generator{}
+	return NewGeneratorWithFS(filesystem.NewOSFS(), ".")
+}
+
+// NewGeneratorWithFS creates a Docker generator that writes into outputDir on fsys
+func NewGeneratorWithFS(fsys filesystem.FS, outputDir string) *Generator {
+	return &Generator{
+		fs:        fsys,
+		outputDir: outputDir,
+		checker:   compose.NewPrerequisiteChecker(),
+		available: ports.Available,
+	}
+}
+
+// SetPrerequisiteChecker replaces the check for Docker and Docker Compose
+func (g *Generator) SetPrerequisiteChecker(checker PrerequisiteChecker) {
+	g.checker = checker
+}
+
+// SetPortProbe replaces the check for whether a host port is free, which
+// decides where new ports are published
+func (g *Generator) SetPortProbe(available func(port int) bool) {
+	g.available = available
+}
+
+// SetLabels sets the om version and environment the generated containers,
+// volumes, and images are labeled with
+func (g *Generator) SetLabels(info labels.Info) {
+	g.labels = info
+}
+
+// SetHost sets the environment om runs in, one of the
+// templating.Environment constants, which decides how paths are written
+func (g *Generator) SetHost(environment string) {
+	g.host = environment
+}
+
+// SetEngine sets the container engine the generated file runs on, such as
+// Podman, whose socket Traefik gateways mount
+func (g *Generator) SetEngine(engine compose.Engine) {
+	g.engine = engine
+}
+
+// SetSecretsProvider sets where the passwords written to .env come from
+func (g *Generator) SetSecretsProvider(provider compose.SecretsProvider) {
+	g.secrets = provider
 }
 
 // Name returns the unique identifier for this generator
@@ -44,123 +115,143 @@ func (g *Generator) Validate(manifest *manifest.WorkbenchManifest) error {
 	return nil
 }
 
+// Warnings returns the warnings found by the last call to Generate
+func (g *Generator) Warnings() []warnings.Warning {
+	return warnings.Sort(g.warnings)
+}
+
+// Config returns the typed model of the docker-compose.yml that Generate
+// writes for the manifest, without checking prerequisites or writing files.
+// Ports are published as recorded in the port lock; new ones are not probed.
+func (g *Generator) Config(manifest *manifest.WorkbenchManifest) (*compose.DockerComposeConfig, error) {
+	if err := g.Validate(manifest); err != nil {
+		return nil, generator.NewValidationError(g.Name(), err)
+	}
+	config, err := g.newComposeGenerator(manifest).Generate()
+	if err != nil {
+		return nil, generator.NewGenerationError(g.Name(), "failed to generate docker-compose configuration", err)
+	}
+	lock, err := ports.Load(g.fs, g.outputDir)
+	if err != nil {
+		return nil, generator.NewGenerationError(g.Name(), "failed to read the port lock", err)
+	}
+	ports.Assign(config, lock, nil)
+	return config, nil
+}
+
+// newComposeGenerator returns the compose generator of the manifest,
+// labeling what it generates for this run
+func (g *Generator) newComposeGenerator(manifest *manifest.WorkbenchManifest) *compose.Generator {
+	composeGen := compose.NewGenerator(manifest)
+	composeGen.SetLabels(g.labels)
+	composeGen.SetHost(g.host, g.outputDir)
+	composeGen.SetEngine(g.engine)
+	composeGen.SetSecretsProvider(g.secrets)
+	return composeGen
+}
+
 // Generate creates the Docker Compose configuration for the given manifest
 func (g *Generator) Generate(manifest *manifest.WorkbenchManifest) error {
+	g.warnings = nil
+
 	// Validate the manifest
 	if err := g.Validate(manifest); err != nil {
-		return fmt.Errorf("manifest validation failed: %w", err)
+		return generator.NewValidationError(g.Name(), err)
 	}
 
 	// Check prerequisites
 	fmt.Println("🔍 Checking prerequisites...")
-	checker := compose.NewPrerequisiteChecker()
-	if err := checker.CheckAllPrerequisites(); err != nil {
-		return fmt.Errorf("prerequisite check failed: %w", err)
+	if err := g.checker.CheckAllPrerequisites(); err != nil {
+		return generator.NewPrerequisiteError(g.Name(), err)
 	}
 	fmt.Println("✅ Prerequisites satisfied")
 
-	// Convert manifest to compose.WorkbenchProject
-	project := convertManifestToProject(manifest)
-
-	// Create generator
-	generator := compose.NewGenerator(project)
+	composeGen := g.newComposeGenerator(manifest)
 
 	fmt.Println("🔧 Generating Docker Compose configuration...")
 
 	// Generate docker-compose.yml
-	config, err := generator.Generate()
+	config, err := composeGen.Generate()
+	if err != nil {
+		return generator.NewGenerationError(g.Name(), "failed to generate docker-compose configuration", err)
+	}
+	g.warnings = append(g.warnings, manifest.Lint(g.fs, g.outputDir)...)
+	g.warnings = append(g.warnings, composeGen.Warnings()...)
+
+	// Publish on the host ports recorded for the project, moving new ports
+	// that another process already uses
+	lock, err := ports.Load(g.fs, g.outputDir)
 	if err != nil {
-		return fmt.Errorf("failed to generate docker-compose configuration: %w", err)
+		return generator.NewGenerationError(g.Name(), "failed to read the port lock", err)
+	}
+	moved := ports.Assign(config, lock, g.available)
+	if err := ports.Save(g.fs, g.outputDir, lock); err != nil {
+		return generator.NewGenerationError(g.Name(), "failed to save the port lock", err)
 	}
 
 	// Save docker-compose.yml
-	if err := compose.SaveDockerCompose(config, "docker-compose.yml"); err != nil {
-		return fmt.Errorf("failed to save docker-compose.yml: %w", err)
+	if err := compose.WriteDockerCompose(g.fs, config, filepath.Join(g.outputDir, "docker-compose.yml")); err != nil {
+		return generator.NewGenerationError(g.Name(), "failed to save docker-compose.yml", err)
 	}
 
 	fmt.Println("✅ Generated docker-compose.yml")
+	for _, allocation := range moved {
+		fmt.Printf("🔀 Port %d is in use; %s publishes port %d on %d instead\n", allocation.Requested, allocation.Service, allocation.Container, allocation.Host)
+	}
+
+	// Regenerate the stitching config of GraphQL gateways
+	for path, meshConfig := range composeGen.GenerateMeshConfigs() {
+		if err := compose.WriteMeshConfig(g.fs, meshConfig, filepath.Join(g.outputDir, path)); err != nil {
+			return generator.NewGenerationError(g.Name(), "failed to save GraphQL gateway config", err)
+		}
+		fmt.Printf("✅ Generated %s\n", path)
+	}
 
 	// Generate environment files
 	fmt.Println("🔐 Generating environment files...")
 
-	envVars, err := generator.GenerateEnvFile()
+	envVars, err := composeGen.GenerateEnvFile()
 	if err != nil {
-		return fmt.Errorf("failed to generate environment variables: %w", err)
+		return generator.NewGenerationError(g.Name(), "failed to generate environment variables", err)
 	}
 
 	// Save .env file
-	if err := compose.SaveEnvFile(envVars, ".env"); err != nil {
-		return fmt.Errorf("failed to save .env file: %w", err)
+	if err := compose.WriteEnvFile(g.fs, envVars, filepath.Join(g.outputDir, ".env")); err != nil {
+		return generator.NewGenerationError(g.Name(), "failed to save .env file", err)
 	}
 
 	// Save .env.example file
-	if err := compose.SaveEnvExampleFile(envVars, ".env.example"); err != nil {
-		return fmt.Errorf("failed to save .env.example file: %w", err)
+	if err := compose.WriteEnvExampleFile(g.fs, compose.EnvSections(manifest), envVars, filepath.Join(g.outputDir, ".env.example")); err != nil {
+		return generator.NewGenerationError(g.Name(), "failed to save .env.example file", err)
 	}
 
 	fmt.Println("✅ Generated .env and .env.example files")
 
 	// Update .gitignore to include .env
-	if err := updateGitignore(); err != nil {
-		fmt.Printf("⚠️  Warning: Could not update .gitignore: %s\n", err)
+	if err := g.updateGitignore(); err != nil {
+		g.warnings = append(g.warnings, warnings.New("", "could not update .gitignore: %s", err))
 	}
 
 	// Print success message with instructions
-	printComposeSuccessMessage(checker.GetDockerComposeCommand())
-
-	return nil
-}
-
-// convertManifestToProject converts manifest.WorkbenchManifest to compose.WorkbenchProject
-func convertManifestToProject(manifest *manifest.WorkbenchManifest) *compose.WorkbenchProject {
-	project := &compose.WorkbenchProject{
-		APIVersion: manifest.APIVersion,
-		Kind:       manifest.Kind,
-		Metadata: compose.ProjectMetadata{
-			Name: manifest.Metadata.Name,
-		},
-		Components: make(map[string]compose.Component),
-		Services:   make(map[string]compose.Service),
-	}
-
-	// Convert components
-	for name, component := range manifest.Components {
-		project.Components[name] = compose.Component{
-			Template: component.Template,
-			Path:     component.Path,
-			Ports:    component.Ports,
-		}
-	}
+	printComposeSuccessMessage(g.checker.GetDockerComposeCommand())
 
-	// Convert services
-	for name, service := range manifest.Services {
-		project.Services[name] = compose.Service{
-			Template:    service.Template,
-			Path:        service.Path,
-			Port:        service.Port,
-			Environment: service.Environment,
-			Resources:   make(map[string]compose.Resource),
-		}
+	// Host-run services are started separately
+	printLocalServices(manifest)
 
-		// Convert resources
-		for resourceName, resource := range service.Resources {
-			project.Services[name].Resources[resourceName] = compose.Resource{
-				Type:    resource.Type,
-				Version: resource.Version,
-				Config:  resource.Config,
-			}
-		}
+	// Published ports live on the remote machine when DOCKER_HOST points elsewhere
+	if remote := compose.ParseDockerHost(os.Getenv("DOCKER_HOST")); remote != nil {
+		printPortForwardingHelp(remote, compose.PublishedPorts(config))
 	}
 
-	return project
+	return nil
 }
 
-func updateGitignore() error {
-	gitignorePath := ".gitignore"
+func (g *Generator) updateGitignore() error {
+	gitignorePath := filepath.Join(g.outputDir, ".gitignore")
 
 	// Read existing .gitignore
-	content, err := os.ReadFile(gitignorePath)
-	if err != nil && !os.IsNotExist(err) {
+	content, err := g.fs.ReadFile(gitignorePath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return err
 	}
 
@@ -177,7 +268,7 @@ func updateGitignore() error {
 	}
 	newContent += "\n# Environment variables\n.env\n"
 
-	return os.WriteFile(gitignorePath, []byte(newContent), 0644)
+	return g.fs.WriteFile(gitignorePath, []byte(newContent), 0644)
 }
 
 func contains(s, substr string) bool {
@@ -215,3 +306,33 @@ func printComposeSuccessMessage(dockerComposeCmd string) {
 
 	fmt.Println("\n🎉 Your local development environment is ready!")
 }
+
+// printLocalServices lists services that run on the host rather than in
+// docker-compose.yml, with the commands that start them
+func printLocalServices(m *manifest.WorkbenchManifest) {
+	var names []string
+	for name, service := range m.Services {
+		if service.RunsOnHost() {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	sort.Strings(names)
+
+	fmt.Println("\n📱 These services run on your machine and are not part of docker-compose.yml:")
+	for _, name := range names {
+		service := m.Services[name]
+		fmt.Printf("  %s: cd %s && %s\n", name, service.Path, service.Dev)
+	}
+}
+
+func printPortForwardingHelp(remote *compose.RemoteDockerHost, ports []string) {
+	fmt.Printf("\n🌐 DOCKER_HOST points at %s, so published ports are not on localhost.\n", remote.Host)
+	if len(ports) == 0 {
+		return
+	}
+	fmt.Println("   Forward them over SSH before opening localhost URLs:")
+	fmt.Printf("  %s\n", remote.ForwardCommand(ports))
+}
@@ -0,0 +1,149 @@
+package docker
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/generator"
+	"github.com/jashkahar/open-workbench-platform/internal/labels"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/testutil"
+)
+
+// installedDocker passes the prerequisite check without looking at the PATH
+type installedDocker struct{}
+
+func (installedDocker) CheckAllPrerequisites() error    { return nil }
+func (installedDocker) GetDockerComposeCommand() string { return "docker compose" }
+
+// postgresConfig holds what om add resource asks for a postgres-db
+var postgresConfig = map[string]string{"databaseName": "app", "username": "app", "password": "secret", "port": "5432"}
+
+func TestGenerator_Generate(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+
+	tests := []struct {
+		name     string
+		manifest *manifest.WorkbenchManifest
+	}{
+		{
+			name: "services",
+			manifest: &manifest.WorkbenchManifest{
+				Metadata: manifest.ProjectMetadata{Name: "demo"},
+				Services: map[string]manifest.Service{
+					"api":    {Template: "fastapi-basic", Path: "./api", Port: 8000, Restart: manifest.RestartOnFailure},
+					"auth":   {Image: "registry.internal/auth:1.4.2", Port: 9000},
+					"mobile": {Path: "./mobile", Kind: manifest.ServiceKindLocal, Dev: "npx expo start"},
+				},
+			},
+		},
+		{
+			name: "components",
+			manifest: &manifest.WorkbenchManifest{
+				Metadata: manifest.ProjectMetadata{Name: "demo"},
+				Components: map[string]manifest.Component{
+					"gateway": {Template: compose.TraefikTemplate, Path: "./gateway"},
+					"proxy":   {Template: "nginx-gateway", Path: "./proxy", Ports: []string{"8080:80"}, Init: true},
+				},
+				Services: map[string]manifest.Service{
+					"web": {Template: "react-typescript", Path: "./web", Port: 3000, Subdomain: "app"},
+				},
+			},
+		},
+		{
+			name: "resources",
+			manifest: &manifest.WorkbenchManifest{
+				Metadata: manifest.ProjectMetadata{Name: "demo"},
+				Services: map[string]manifest.Service{
+					"api": {
+						Template: "express-api",
+						Path:     "./api",
+						Port:     3001,
+						Resources: map[string]manifest.Resource{
+							"db":    {Type: "postgres-db", Version: "15", Config: postgresConfig},
+							"cache": {Type: "redis-cache"},
+						},
+						Environment: map[string]string{
+							"DATABASE_HOST": "${services.api.resources.db.name}",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "mixed",
+			manifest: &manifest.WorkbenchManifest{
+				Metadata: manifest.ProjectMetadata{Name: "demo"},
+				Components: map[string]manifest.Component{
+					"proxy": {Template: "nginx-gateway", Path: "./proxy", Ports: []string{"8080:80"}},
+				},
+				Services: map[string]manifest.Service{
+					"api": {
+						Template:  "fastapi-basic",
+						Path:      "./api",
+						Port:      8000,
+						Libraries: []string{"models"},
+						Resources: map[string]manifest.Resource{"db": {Type: "postgres-db", Version: "16", Config: postgresConfig}},
+						Environment: map[string]string{
+							"PAYMENTS_URL": "${external.payments.url}",
+							"PROXY_PORT":   "${components.proxy.port}",
+						},
+						Security: &manifest.Security{User: "1000:1000", CapDrop: []string{"ALL"}, NoNewPrivileges: true},
+					},
+					"models": {Path: "./shared/models", Kind: manifest.ServiceKindLibrary},
+				},
+				External: map[string]manifest.External{
+					"payments": {URL: "https://api.payments.example", Spec: "specs/payments.yaml"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := filesystem.NewMemFS()
+			if err := fsys.MkdirAll("project", 0755); err != nil {
+				t.Fatal(err)
+			}
+			g := NewGeneratorWithFS(fsys, "project")
+			g.SetPrerequisiteChecker(installedDocker{})
+			g.SetLabels(labels.Info{Version: "1.0.0"})
+			g.SetPortProbe(func(int) bool { return true })
+			g.SetSecretsProvider(compose.GeneratedSecrets{Rand: rand.New(rand.NewSource(1))})
+
+			if err := g.Generate(tt.manifest); err != nil {
+				t.Fatalf("Generate() failed: %v", err)
+			}
+			testutil.AssertGolden(t, tt.name, testutil.Snapshot(fsys, "project"))
+		})
+	}
+}
+
+func TestGenerator_Config(t *testing.T) {
+	g := NewGeneratorWithFS(filesystem.NewMemFS(), ".")
+	config, err := g.Config(&manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "demo"},
+		Services: map[string]manifest.Service{
+			"api":    {Path: "./api", Port: 8000, Resources: map[string]manifest.Resource{"db": {Type: "postgres-db"}}},
+			"mobile": {Path: "./mobile", Kind: manifest.ServiceKindLocal, Dev: "npx expo start"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := config.Services["mobile"]; exists {
+		t.Error("host-run services should not be part of the compose model")
+	}
+	if config.Services["api"].Build == nil || config.Services["api"].Build.Context != "./api" {
+		t.Errorf("expected api to be built from its path, got %+v", config.Services["api"])
+	}
+	if config.Origins["api-db"].Entry != "services.api.resources.db" {
+		t.Errorf("expected the database to come from its resource entry, got %+v", config.Origins["api-db"])
+	}
+
+	if _, err := g.Config(&manifest.WorkbenchManifest{}); !generator.IsGeneratorError(err, generator.ErrorTypeValidation) {
+		t.Errorf("expected a validation error for an empty manifest, got %v", err)
+	}
+}
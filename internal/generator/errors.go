@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorType represents the stage at which a generator failed
+type ErrorType int
+
+const (
+	// ErrorTypeValidation indicates the manifest is not compatible with the generator
+	ErrorTypeValidation ErrorType = iota
+	// ErrorTypePrerequisite indicates a required external tool is missing or unusable
+	ErrorTypePrerequisite
+	// ErrorTypeGeneration indicates the configuration could not be generated or written
+	ErrorTypeGeneration
+)
+
+// GeneratorError represents a structured error returned by a generator
+type GeneratorError struct {
+	Type        ErrorType // The stage at which generation failed
+	Generator   string    // Name of the generator (docker, terraform, ...)
+	Message     string    // User-friendly error message
+	OriginalErr error     // Original error for debugging
+}
+
+// Error returns the user-friendly error message
+func (e *GeneratorError) Error() string {
+	if e.OriginalErr != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.OriginalErr)
+	}
+	return e.Message
+}
+
+// Unwrap returns the original error
+func (e *GeneratorError) Unwrap() error {
+	return e.OriginalErr
+}
+
+// NewValidationError creates an error for a manifest the generator cannot handle
+func NewValidationError(generatorName string, originalErr error) *GeneratorError {
+	return &GeneratorError{
+		Type:        ErrorTypeValidation,
+		Generator:   generatorName,
+		Message:     "manifest validation failed",
+		OriginalErr: originalErr,
+	}
+}
+
+// NewPrerequisiteError creates an error for missing or broken external tools
+func NewPrerequisiteError(generatorName string, originalErr error) *GeneratorError {
+	return &GeneratorError{
+		Type:        ErrorTypePrerequisite,
+		Generator:   generatorName,
+		Message:     "prerequisite check failed",
+		OriginalErr: originalErr,
+	}
+}
+
+// NewGenerationError creates an error for failures while producing output
+func NewGenerationError(generatorName, message string, originalErr error) *GeneratorError {
+	return &GeneratorError{
+		Type:        ErrorTypeGeneration,
+		Generator:   generatorName,
+		Message:     message,
+		OriginalErr: originalErr,
+	}
+}
+
+// IsGeneratorError reports whether err is or wraps a GeneratorError of the given type
+func IsGeneratorError(err error, errType ErrorType) bool {
+	var generatorErr *GeneratorError
+	return errors.As(err, &generatorErr) && generatorErr.Type == errType
+}
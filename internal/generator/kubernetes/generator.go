@@ -0,0 +1,634 @@
+// Package kubernetes generates Kubernetes manifests from a workbench
+// manifest: a Deployment for every service, component, and resource that
+// Docker Compose would run, a Service for the ports it listens on, a
+// ConfigMap for its environment, and a PersistentVolumeClaim for each named
+// volume. It translates the compose model rather than the manifest, so
+// images, commands, environment, and volumes match docker-compose.yml. With
+// a mesh section the pods are annotated for Linkerd or Istio injection, and
+// the workloads other workloads call get a ServiceProfile or
+// PeerAuthentication.
+package kubernetes
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/generator"
+	"github.com/jashkahar/open-workbench-platform/internal/labels"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/revision"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+	"gopkg.in/yaml.v3"
+)
+
+// OutputDir is the directory the manifests are written to, relative to the
+// project root
+const OutputDir = "k8s"
+
+// header opens every generated file; files starting with it are removed
+// once the entry they were generated from is gone
+const header = "# THIS FILE IS AUTO-GENERATED BY 'om compose'.\n# For permanent changes, modify your workbench.yaml and re-run the command.\n"
+
+// claimSize is the storage requested for each named volume
+const claimSize = "1Gi"
+
+// Labels Kubernetes tooling recognizes
+const (
+	nameLabel      = "app.kubernetes.io/name"
+	partOfLabel    = "app.kubernetes.io/part-of"
+	managedByLabel = "app.kubernetes.io/managed-by"
+)
+
+// Annotations that have a mesh inject its proxy into a pod
+var injectAnnotations = map[string][2]string{
+	manifest.MeshLinkerd: {"linkerd.io/inject", "enabled"},
+	manifest.MeshIstio:   {"sidecar.istio.io/inject", "true"},
+}
+
+// retryBudget is the retry budget of the generated ServiceProfiles, Linkerd's
+// own default written out so teams can see and tune it
+var retryBudget = RetryBudget{RetryRatio: 0.2, MinRetriesPerSecond: 10, TTL: "10s"}
+
+// labelValuePattern matches the values Kubernetes accepts for labels; om
+// labels with other values, such as tags joined by spaces, become annotations
+var labelValuePattern = regexp.MustCompile(`^(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?$`)
+
+// Generator implements the Generator interface for Kubernetes
+type Generator struct {
+	fs        filesystem.FS
+	outputDir string
+	labels    labels.Info
+	secrets   compose.SecretsProvider
+	warnings  []warnings.Warning
+}
+
+var _ generator.Generator = (*Generator)(nil)
+
+// NewGenerator creates a new Kubernetes generator that writes to the current directory
+func NewGenerator() *Generator {
+	return NewGeneratorWithFS(filesystem.NewOSFS(), ".")
+}
+
+// NewGeneratorWithFS creates a Kubernetes generator that writes into outputDir on fsys
+func NewGeneratorWithFS(fsys filesystem.FS, outputDir string) *Generator {
+	return &Generator{fs: fsys, outputDir: outputDir}
+}
+
+// SetLabels sets the om version and environment the generated objects are
+// labeled with
+func (g *Generator) SetLabels(info labels.Info) {
+	g.labels = info
+}
+
+// SetSecretsProvider sets where the values of the generated Secrets come
+// from
+func (g *Generator) SetSecretsProvider(provider compose.SecretsProvider) {
+	g.secrets = provider
+}
+
+// Name returns the unique identifier for this generator
+func (g *Generator) Name() string {
+	return "kubernetes"
+}
+
+// Description returns a human-readable description of this generator
+func (g *Generator) Description() string {
+	return "Generate Kubernetes manifests for a cluster such as k3s"
+}
+
+// Validate checks if the manifest is compatible with this generator
+func (g *Generator) Validate(manifest *manifest.WorkbenchManifest) error {
+	if manifest == nil {
+		return fmt.Errorf("manifest cannot be nil")
+	}
+
+	if manifest.Metadata.Name == "" {
+		return fmt.Errorf("project name is required")
+	}
+
+	if len(manifest.Services) == 0 {
+		return fmt.Errorf("at least one service is required")
+	}
+
+	return nil
+}
+
+// Warnings returns the warnings found by the last call to Generate
+func (g *Generator) Warnings() []warnings.Warning {
+	return warnings.Sort(g.warnings)
+}
+
+// builtImage is an image the cluster needs that is built from the project
+type builtImage struct {
+	Image   string
+	Context string
+}
+
+// Generate writes the Kubernetes manifests for the given manifest
+func (g *Generator) Generate(manifest *manifest.WorkbenchManifest) error {
+	g.warnings = nil
+
+	if err := g.Validate(manifest); err != nil {
+		return generator.NewValidationError(g.Name(), err)
+	}
+
+	fmt.Println("🔧 Generating Kubernetes manifests...")
+
+	composeGen := compose.NewGenerator(withResourcePorts(manifest))
+	composeGen.SetLabels(g.labels)
+	composeGen.SetSecretsProvider(g.secrets)
+	config, err := composeGen.Generate()
+	if err != nil {
+		return generator.NewGenerationError(g.Name(), "failed to translate the manifest", err)
+	}
+	envVars, err := composeGen.GenerateEnvFile()
+	if err != nil {
+		return generator.NewGenerationError(g.Name(), "failed to generate environment variables", err)
+	}
+	g.warnings = append(g.warnings, manifest.Lint(g.fs, g.outputDir)...)
+	g.warnings = append(g.warnings, composeGen.Warnings()...)
+
+	files, images := g.render(dnsName(manifest.Metadata.Name), manifest, config, envVars)
+
+	dir := filepath.Join(g.outputDir, OutputDir)
+	if err := g.fs.MkdirAll(dir, 0755); err != nil {
+		return generator.NewGenerationError(g.Name(), "failed to create "+OutputDir, err)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := g.fs.WriteFile(filepath.Join(dir, name), files[name], 0644); err != nil {
+			return generator.NewGenerationError(g.Name(), "failed to save "+name, err)
+		}
+	}
+	if err := g.removeStale(dir, files); err != nil {
+		return generator.NewGenerationError(g.Name(), "failed to remove stale manifests", err)
+	}
+
+	printSuccessMessage(names, images, manifest.Mesh)
+	return nil
+}
+
+// withResourcePorts returns a copy of the manifest with every resource
+// exposed, so the compose model keeps the ports their blueprints listen on.
+// Resources are only reachable in a cluster through a Service on that port.
+func withResourcePorts(m *manifest.WorkbenchManifest) *manifest.WorkbenchManifest {
+	exposed := m.Clone()
+	for name, service := range exposed.Services {
+		for resourceName, resource := range service.Resources {
+			resource.Expose = manifest.ExposeHost
+			service.Resources[resourceName] = resource
+		}
+		exposed.Services[name] = service
+	}
+	return exposed
+}
+
+// render returns the contents of every file to write, by name, and the
+// images the cluster needs built from the project
+func (g *Generator) render(project string, m *manifest.WorkbenchManifest, config *compose.DockerComposeConfig, envVars map[string]string) (map[string][]byte, []builtImage) {
+	files := make(map[string][]byte)
+	var images []builtImage
+	called := calledWorkloads(m, config)
+
+	// The .env of the compose target becomes one ConfigMap shared by every
+	// workload that reads it
+	projectEnv := ""
+	if len(envVars) > 0 {
+		projectEnv = project + "-env"
+		files["env.yaml"] = encode(config.Revision, "", ConfigMap{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Metadata:   Metadata{Name: projectEnv, Labels: map[string]string{partOfLabel: project, managedByLabel: "om"}},
+			Data:       envVars,
+		})
+	}
+
+	names := make([]string, 0, len(config.Services))
+	for name := range config.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		service := config.Services[name]
+		origin := config.Origins[name]
+		workload := dnsName(name)
+		if workload != name {
+			g.warnings = append(g.warnings, warnings.New(origin.Entry,
+				"'%s' is not a valid Kubernetes name; it is named '%s' in the cluster, so references to its hostname need updating", name, workload))
+		}
+
+		metadata := objectMetadata(workload, project, service.Labels)
+		selector := map[string]string{nameLabel: workload, partOfLabel: project}
+		container := Container{
+			Name:    workload,
+			Image:   service.Image,
+			Command: service.Entrypoint,
+			Args:    service.Command,
+		}
+		if service.Build != nil {
+			container.Image = fmt.Sprintf("%s-%s:latest", project, workload)
+			container.ImagePullPolicy = "IfNotPresent"
+			images = append(images, builtImage{Image: container.Image, Context: service.Build.Context})
+		}
+		container.SecurityContext = g.securityContext(origin.Entry, service)
+
+		var objects []interface{}
+
+		// Environment
+		if projectEnv != "" && len(service.EnvFile) > 0 {
+			container.EnvFrom = append(container.EnvFrom, EnvFromSource{ConfigMapRef: &ObjectRef{Name: projectEnv}})
+		}
+		if len(service.Environment) > 0 {
+			data := make(map[string]string, len(service.Environment))
+			for _, entry := range service.Environment {
+				key, value, _ := strings.Cut(entry, "=")
+				data[key] = value
+			}
+			objects = append(objects, ConfigMap{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Metadata:   Metadata{Name: workload + "-env", Labels: metadata.Labels, Annotations: metadata.Annotations},
+				Data:       data,
+			})
+			container.EnvFrom = append(container.EnvFrom, EnvFromSource{ConfigMapRef: &ObjectRef{Name: workload + "-env"}})
+		}
+
+		// Named volumes become claims; bind mounts have no equivalent
+		var volumes []Volume
+		for _, entry := range service.Volumes {
+			parts := strings.Split(entry, ":")
+			if len(parts) < 2 {
+				continue
+			}
+			if _, named := config.Volumes[parts[0]]; !named {
+				g.warnings = append(g.warnings, warnings.New(origin.Entry,
+					"bind mount %s is not carried over to Kubernetes; build the files into the image or mount a ConfigMap", entry))
+				continue
+			}
+			claim := dnsName(parts[0])
+			objects = append(objects, PersistentVolumeClaim{
+				APIVersion: "v1",
+				Kind:       "PersistentVolumeClaim",
+				Metadata:   Metadata{Name: claim, Labels: metadata.Labels, Annotations: metadata.Annotations},
+				Spec: ClaimSpec{
+					AccessModes: []string{"ReadWriteOnce"},
+					Resources:   ResourceRequirements{Requests: map[string]string{"storage": claimSize}},
+				},
+			})
+			volumes = append(volumes, Volume{Name: claim, PersistentVolumeClaim: &ClaimRef{ClaimName: claim}})
+			container.VolumeMounts = append(container.VolumeMounts, VolumeMount{
+				Name:      claim,
+				MountPath: parts[1],
+				ReadOnly:  len(parts) > 2 && parts[2] == "ro",
+			})
+		}
+
+		// Ports, named after what they speak
+		var servicePorts []ServicePort
+		appProtocol := ""
+		for _, mapping := range service.Ports {
+			port, protocol, ok := containerPort(mapping)
+			if !ok {
+				g.warnings = append(g.warnings, warnings.New(origin.Entry,
+					"port %s is not a single port; it is left out of the Kubernetes Service", mapping))
+				continue
+			}
+			portName, portProtocol := namedPort(m, origin.Entry, port, protocol)
+			if portProtocol != "" {
+				appProtocol = portProtocol
+			}
+			container.Ports = append(container.Ports, ContainerPort{Name: portName, ContainerPort: port, Protocol: protocol})
+			servicePorts = append(servicePorts, ServicePort{
+				Name:        portName,
+				Port:        port,
+				TargetPort:  port,
+				Protocol:    protocol,
+				AppProtocol: portProtocol,
+			})
+		}
+
+		deployment := Deployment{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Metadata:   metadata,
+			Spec: DeploymentSpec{
+				Replicas: 1,
+				Selector: LabelSelector{MatchLabels: selector},
+				Template: PodTemplate{
+					Metadata: Metadata{Labels: metadata.Labels, Annotations: podAnnotations(metadata.Annotations, m.Mesh)},
+					Spec:     PodSpec{Containers: []Container{container}, Volumes: volumes},
+				},
+			},
+		}
+		// A ReadWriteOnce claim cannot be mounted by the old and new pod at once
+		if len(volumes) > 0 {
+			deployment.Spec.Strategy = &Strategy{Type: "Recreate"}
+		}
+		objects = append([]interface{}{deployment}, objects...)
+		if len(servicePorts) > 0 {
+			objects = append(objects, Service{
+				APIVersion: "v1",
+				Kind:       "Service",
+				Metadata:   metadata,
+				Spec:       ServiceSpec{Selector: selector, Ports: servicePorts},
+			})
+			if called[name] {
+				objects = append(objects, meshObjects(m.Mesh, metadata, selector, appProtocol)...)
+			}
+		}
+
+		files[workload+".yaml"] = encode(config.Revision, origin.Marker(), objects...)
+	}
+
+	resources := make([]string, 0, len(files))
+	for name := range files {
+		resources = append(resources, name)
+	}
+	sort.Strings(resources)
+	files["kustomization.yaml"] = encode(config.Revision, "", Kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Namespace:  m.Mesh.Namespace,
+		Resources:  resources,
+	})
+	return files, images
+}
+
+// calledWorkloads returns the compose services other workloads call,
+// following the service graph of the manifest: the services and components
+// a service or component references, and the resources of each service
+func calledWorkloads(m *manifest.WorkbenchManifest, config *compose.DockerComposeConfig) map[string]bool {
+	called := make(map[string]bool)
+	callers := make([]string, 0, len(m.Services)+len(m.Components))
+	for name := range m.Services {
+		callers = append(callers, name)
+	}
+	for name := range m.Components {
+		callers = append(callers, name)
+	}
+	for _, caller := range callers {
+		for _, referenced := range m.References(caller) {
+			called[referenced] = true
+		}
+	}
+	for name, origin := range config.Origins {
+		if strings.Contains(origin.Entry, ".resources.") {
+			called[name] = true
+		}
+	}
+	return called
+}
+
+// namedPort returns the name of a port and the application protocol it
+// speaks, when known. The main port of a service is named after its
+// protocol, e.g. http or grpc, which is how Istio and Linkerd tell HTTP
+// traffic from plain TCP; other ports are named after their transport and
+// number, e.g. tcp-5432.
+func namedPort(m *manifest.WorkbenchManifest, entry string, port int, protocol string) (string, string) {
+	if name, isService := strings.CutPrefix(entry, "services."); isService && protocol == "TCP" {
+		if service, exists := m.Services[name]; exists && service.Port == port {
+			switch service.ProtocolOrDefault() {
+			case manifest.ProtocolHTTP, manifest.ProtocolGRPC:
+				return service.ProtocolOrDefault(), service.ProtocolOrDefault()
+			}
+		}
+	}
+	return fmt.Sprintf("%s-%d", strings.ToLower(protocol), port), ""
+}
+
+// podAnnotations returns the annotations of a workload's pods: those of the
+// workload and, with a mesh, the one that injects its proxy
+func podAnnotations(annotations map[string]string, mesh manifest.Mesh) map[string]string {
+	inject, ok := injectAnnotations[mesh.Provider]
+	if !ok {
+		return annotations
+	}
+	result := make(map[string]string, len(annotations)+1)
+	for key, value := range annotations {
+		result[key] = value
+	}
+	result[inject[0]] = inject[1]
+	return result
+}
+
+// meshObjects returns the mesh configuration of a workload other workloads
+// call: a PeerAuthentication setting its mTLS mode on Istio, and on Linkerd
+// a ServiceProfile when it speaks HTTP or gRPC. Workloads nothing calls,
+// such as a public frontend, are left alone so traffic from outside the mesh
+// keeps reaching them.
+func meshObjects(mesh manifest.Mesh, metadata Metadata, selector map[string]string, appProtocol string) []interface{} {
+	switch mesh.Provider {
+	case manifest.MeshIstio:
+		return []interface{}{PeerAuthentication{
+			APIVersion: "security.istio.io/v1beta1",
+			Kind:       "PeerAuthentication",
+			Metadata:   metadata,
+			Spec: PeerAuthenticationSpec{
+				Selector: LabelSelector{MatchLabels: selector},
+				MTLS:     MutualTLS{Mode: strings.ToUpper(mesh.MTLSOrDefault())},
+			},
+		}}
+	case manifest.MeshLinkerd:
+		if appProtocol == "" {
+			return nil
+		}
+		profile := metadata
+		profile.Name = fmt.Sprintf("%s.%s.svc.cluster.local", metadata.Name, mesh.NamespaceOrDefault())
+		budget := retryBudget
+		return []interface{}{ServiceProfile{
+			APIVersion: "linkerd.io/v1alpha2",
+			Kind:       "ServiceProfile",
+			Metadata:   profile,
+			Spec: ServiceProfileSpec{
+				Routes:      []Route{{Name: "all", Condition: RouteCondition{PathRegex: ".*"}}},
+				RetryBudget: &budget,
+			},
+		}}
+	}
+	return nil
+}
+
+// objectMetadata returns the metadata of the objects generated for a
+// workload. The om labels of the compose service are kept as labels when
+// Kubernetes accepts their values and as annotations otherwise.
+func objectMetadata(name, project string, composeLabels []string) Metadata {
+	metadata := Metadata{
+		Name:   name,
+		Labels: map[string]string{nameLabel: name, partOfLabel: project, managedByLabel: "om"},
+	}
+	for _, label := range composeLabels {
+		key, value, _ := strings.Cut(label, "=")
+		if !strings.HasPrefix(key, "com.openworkbench.") {
+			continue
+		}
+		if len(value) <= 63 && labelValuePattern.MatchString(value) {
+			metadata.Labels[key] = value
+			continue
+		}
+		if metadata.Annotations == nil {
+			metadata.Annotations = make(map[string]string)
+		}
+		metadata.Annotations[key] = value
+	}
+	return metadata
+}
+
+// securityContext translates the security settings of a compose service
+func (g *Generator) securityContext(entry string, service compose.DockerComposeService) *SecurityContext {
+	context := &SecurityContext{ReadOnlyRootFilesystem: service.ReadOnly}
+	if len(service.CapDrop) > 0 {
+		context.Capabilities = &Capabilities{Drop: service.CapDrop}
+	}
+	for _, option := range service.SecurityOpt {
+		if option == "no-new-privileges:true" {
+			allow := false
+			context.AllowPrivilegeEscalation = &allow
+		}
+	}
+	if service.User != "" {
+		user, group, hasGroup := strings.Cut(service.User, ":")
+		uid, err := strconv.ParseInt(user, 10, 64)
+		gid, groupErr := strconv.ParseInt(group, 10, 64)
+		if err != nil || (hasGroup && groupErr != nil) {
+			g.warnings = append(g.warnings, warnings.New(entry,
+				"user '%s' is not numeric; Kubernetes runs the container as the image's user", service.User))
+		} else {
+			context.RunAsUser = &uid
+			if hasGroup {
+				context.RunAsGroup = &gid
+			}
+		}
+	}
+	if *context == (SecurityContext{}) {
+		return nil
+	}
+	return context
+}
+
+// containerPort returns the container side of a compose port mapping such
+// as 8080:80, 127.0.0.1:5432:5432, or 53:53/udp
+func containerPort(mapping string) (int, string, bool) {
+	mapping, protocol, _ := strings.Cut(mapping, "/")
+	protocol = strings.ToUpper(protocol)
+	if protocol == "" {
+		protocol = "TCP"
+	}
+	parts := strings.Split(mapping, ":")
+	port, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, "", false
+	}
+	return port, protocol, true
+}
+
+// dnsName turns a name into a valid Kubernetes object name: lowercase
+// letters, digits, and dashes, at most 63 characters
+func dnsName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	result := strings.Trim(b.String(), "-")
+	if len(result) > 63 {
+		result = strings.TrimRight(result[:63], "-")
+	}
+	return result
+}
+
+// encode returns a file holding the objects as YAML documents, under the
+// generated-file header with the manifest revision and, when given, an
+// origin marker
+func encode(manifestRevision, marker string, objects ...interface{}) []byte {
+	var b strings.Builder
+	b.WriteString(header)
+	b.WriteString(revision.Comment(manifestRevision))
+	if marker != "" {
+		b.WriteString("# " + marker + "\n")
+	}
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	for _, object := range objects {
+		// The types above always marshal
+		_ = encoder.Encode(object)
+	}
+	_ = encoder.Close()
+	return []byte(b.String())
+}
+
+// removeStale deletes generated files that the last run did not write, such
+// as the manifests of a removed service. Files the team added are kept.
+func (g *Generator) removeStale(dir string, written map[string][]byte) error {
+	entries, err := g.fs.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+		if _, current := written[entry.Name()]; current {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := g.fs.ReadFile(path)
+		if err != nil || !strings.HasPrefix(string(data), header) {
+			continue
+		}
+		if err := g.fs.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printSuccessMessage(files []string, images []builtImage, mesh manifest.Mesh) {
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("✅ Successfully generated your Kubernetes manifests!")
+	fmt.Println(strings.Repeat("=", 60))
+
+	fmt.Printf("\n📁 Generated files in %s/:\n", OutputDir)
+	for _, file := range files {
+		fmt.Printf("  • %s\n", file)
+	}
+
+	if len(images) > 0 {
+		fmt.Println("\n📦 Build the images the cluster runs and make them available to it:")
+		for _, image := range images {
+			fmt.Printf("  docker build -t %s %s\n", image.Image, image.Context)
+		}
+		fmt.Println("  On k3s, import them with: docker save <image> | sudo k3s ctr images import -")
+		fmt.Println("  Elsewhere, push them to a registry and set image: in workbench.yaml")
+	}
+
+	fmt.Println("\n🔑 Security notes:")
+	fmt.Println("  • Default credentials from .env are stored in ConfigMaps - change them before sharing the cluster")
+
+	if mesh.Enabled() {
+		fmt.Printf("\n🕸️  Prepared for %s in the %s namespace:\n", mesh.Provider, mesh.NamespaceOrDefault())
+		fmt.Printf("  • Pods are annotated for proxy injection; install the %s control plane first\n", mesh.Provider)
+		if mesh.Provider == manifest.MeshLinkerd {
+			fmt.Println("  • ServiceProfiles have one catch-all route; 'linkerd profile --open-api' generates one per endpoint")
+		}
+	}
+
+	fmt.Println("\n🚀 To deploy, run:")
+	fmt.Printf("  kubectl apply -k %s\n", OutputDir)
+}
@@ -0,0 +1,175 @@
+package kubernetes
+
+import (
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/labels"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/testutil"
+)
+
+// postgresConfig holds what om add resource asks for a postgres-db
+var postgresConfig = map[string]string{"databaseName": "app", "username": "app", "password": "secret", "port": "5432"}
+
+func TestGenerator_Generate(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest *manifest.WorkbenchManifest
+	}{
+		{
+			name: "services",
+			manifest: &manifest.WorkbenchManifest{
+				Metadata: manifest.ProjectMetadata{Name: "demo"},
+				Components: map[string]manifest.Component{
+					"proxy": {Template: "nginx-gateway", Path: "./proxy", Ports: []string{"8080:80"}},
+				},
+				Services: map[string]manifest.Service{
+					"api": {
+						Ownership:   manifest.Ownership{Team: "payments", Tags: []string{"pci", "tier:1"}},
+						Template:    "fastapi-basic",
+						Path:        "./api",
+						Port:        8000,
+						Command:     []string{"uvicorn", "main:app"},
+						Environment: map[string]string{"LOG_LEVEL": "debug"},
+						Security:    &manifest.Security{User: "1000:1000", CapDrop: []string{"ALL"}, NoNewPrivileges: true},
+					},
+					"auth":   {Image: "registry.internal/auth:1.4.2", Port: 9000},
+					"mobile": {Path: "./mobile", Kind: manifest.ServiceKindLocal, Dev: "npx expo start"},
+				},
+			},
+		},
+		{
+			name: "resources",
+			manifest: &manifest.WorkbenchManifest{
+				Metadata: manifest.ProjectMetadata{Name: "demo"},
+				Services: map[string]manifest.Service{
+					"api": {
+						Template: "express-api",
+						Path:     "./api",
+						Port:     3001,
+						Resources: map[string]manifest.Resource{
+							"db": {Type: "postgres-db", Version: "15", Config: postgresConfig},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, mesh := range []manifest.Mesh{
+		{Provider: manifest.MeshLinkerd, Namespace: "shop"},
+		{Provider: manifest.MeshIstio, MTLS: manifest.MTLSPermissive},
+	} {
+		tests = append(tests, struct {
+			name     string
+			manifest *manifest.WorkbenchManifest
+		}{
+			name: mesh.Provider,
+			manifest: &manifest.WorkbenchManifest{
+				Metadata: manifest.ProjectMetadata{Name: "demo"},
+				Mesh:     mesh,
+				Services: map[string]manifest.Service{
+					"web": {
+						Template:    "nextjs-full-stack",
+						Path:        "./web",
+						Port:        3000,
+						Environment: map[string]string{"API_URL": "${services.api.url}"},
+					},
+					"api": {
+						Template: "go-api",
+						Path:     "./api",
+						Port:     8080,
+						Resources: map[string]manifest.Resource{
+							"db": {Type: "postgres-db", Version: "15", Config: postgresConfig},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := filesystem.NewMemFS()
+			if err := fsys.MkdirAll("project", 0755); err != nil {
+				t.Fatal(err)
+			}
+			g := NewGeneratorWithFS(fsys, "project")
+			g.SetLabels(labels.Info{Version: "1.0.0"})
+			g.SetSecretsProvider(compose.GeneratedSecrets{Rand: rand.New(rand.NewSource(1))})
+
+			if err := g.Generate(tt.manifest); err != nil {
+				t.Fatalf("Generate() failed: %v", err)
+			}
+			testutil.AssertGolden(t, tt.name, testutil.Snapshot(fsys, "project"))
+		})
+	}
+}
+
+func TestGenerator_RemovesStaleManifests(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	dir := filepath.Join("project", OutputDir)
+	if err := fsys.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	handwritten := "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: demo\n"
+	if err := fsys.WriteFile(filepath.Join(dir, "namespace.yaml"), []byte(handwritten), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "demo"},
+		Services: map[string]manifest.Service{
+			"api":    {Path: "./api", Port: 8000},
+			"worker": {Path: "./worker"},
+		},
+	}
+	g := NewGeneratorWithFS(fsys, "project")
+	if err := g.Generate(m); err != nil {
+		t.Fatal(err)
+	}
+	delete(m.Services, "worker")
+	if err := g.Generate(m); err != nil {
+		t.Fatal(err)
+	}
+
+	if filesystem.Exists(fsys, filepath.Join(dir, "worker.yaml")) {
+		t.Error("expected the manifests of a removed service to be deleted")
+	}
+	if !filesystem.Exists(fsys, filepath.Join(dir, "namespace.yaml")) {
+		t.Error("expected files the team wrote to be kept")
+	}
+	kustomization, err := fsys.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(kustomization), "worker") || !strings.Contains(string(kustomization), "- api.yaml") {
+		t.Errorf("expected the kustomization to list the current manifests, got:\n%s", kustomization)
+	}
+}
+
+func TestContainerPort(t *testing.T) {
+	tests := []struct {
+		mapping  string
+		port     int
+		protocol string
+		ok       bool
+	}{
+		{"8080:80", 80, "TCP", true},
+		{"127.0.0.1:5432:5432", 5432, "TCP", true},
+		{"53:53/udp", 53, "UDP", true},
+		{"3000", 3000, "TCP", true},
+		{"8000-8010:8000-8010", 0, "", false},
+	}
+	for _, tt := range tests {
+		port, protocol, ok := containerPort(tt.mapping)
+		if port != tt.port || protocol != tt.protocol || ok != tt.ok {
+			t.Errorf("containerPort(%q) = %d, %q, %v, want %d, %q, %v", tt.mapping, port, protocol, ok, tt.port, tt.protocol, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package kubernetes
+
+// The types below model the subset of the Kubernetes API the generator
+// writes. Field names follow the API so the YAML reads like hand-written
+// manifests.
+
+// Metadata is the object metadata of every generated object
+type Metadata struct {
+	Name        string            `yaml:"name,omitempty"` // left out of pod templates
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// Deployment runs the container of a service, component, or resource
+type Deployment struct {
+	APIVersion string         `yaml:"apiVersion"`
+	Kind       string         `yaml:"kind"`
+	Metadata   Metadata       `yaml:"metadata"`
+	Spec       DeploymentSpec `yaml:"spec"`
+}
+
+// DeploymentSpec is the spec of a Deployment
+type DeploymentSpec struct {
+	Replicas int           `yaml:"replicas"`
+	Selector LabelSelector `yaml:"selector"`
+	Strategy *Strategy     `yaml:"strategy,omitempty"`
+	Template PodTemplate   `yaml:"template"`
+}
+
+// LabelSelector selects the pods of a Deployment or Service
+type LabelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+// Strategy is how a Deployment replaces its pods
+type Strategy struct {
+	Type string `yaml:"type"`
+}
+
+// PodTemplate is the pod a Deployment runs
+type PodTemplate struct {
+	Metadata Metadata `yaml:"metadata"`
+	Spec     PodSpec  `yaml:"spec"`
+}
+
+// PodSpec is the spec of a pod
+type PodSpec struct {
+	Containers []Container `yaml:"containers"`
+	Volumes    []Volume    `yaml:"volumes,omitempty"`
+}
+
+// Container is the single container of a pod
+type Container struct {
+	Name            string           `yaml:"name"`
+	Image           string           `yaml:"image"`
+	ImagePullPolicy string           `yaml:"imagePullPolicy,omitempty"`
+	Command         []string         `yaml:"command,omitempty"` // replaces the image's entrypoint
+	Args            []string         `yaml:"args,omitempty"`    // replaces the image's command
+	Ports           []ContainerPort  `yaml:"ports,omitempty"`
+	EnvFrom         []EnvFromSource  `yaml:"envFrom,omitempty"`
+	VolumeMounts    []VolumeMount    `yaml:"volumeMounts,omitempty"`
+	SecurityContext *SecurityContext `yaml:"securityContext,omitempty"`
+}
+
+// ContainerPort is a port the container listens on
+type ContainerPort struct {
+	Name          string `yaml:"name,omitempty"`
+	ContainerPort int    `yaml:"containerPort"`
+	Protocol      string `yaml:"protocol,omitempty"`
+}
+
+// EnvFromSource loads the variables of a ConfigMap into the container
+type EnvFromSource struct {
+	ConfigMapRef *ObjectRef `yaml:"configMapRef,omitempty"`
+}
+
+// ObjectRef refers to another object by name
+type ObjectRef struct {
+	Name string `yaml:"name"`
+}
+
+// VolumeMount mounts a volume of the pod into the container
+type VolumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+	ReadOnly  bool   `yaml:"readOnly,omitempty"`
+}
+
+// Volume is a volume of the pod backed by a PersistentVolumeClaim
+type Volume struct {
+	Name                  string    `yaml:"name"`
+	PersistentVolumeClaim *ClaimRef `yaml:"persistentVolumeClaim"`
+}
+
+// ClaimRef refers to a PersistentVolumeClaim
+type ClaimRef struct {
+	ClaimName string `yaml:"claimName"`
+}
+
+// SecurityContext restricts what the container may do
+type SecurityContext struct {
+	RunAsUser                *int64        `yaml:"runAsUser,omitempty"`
+	RunAsGroup               *int64        `yaml:"runAsGroup,omitempty"`
+	ReadOnlyRootFilesystem   bool          `yaml:"readOnlyRootFilesystem,omitempty"`
+	AllowPrivilegeEscalation *bool         `yaml:"allowPrivilegeEscalation,omitempty"`
+	Capabilities             *Capabilities `yaml:"capabilities,omitempty"`
+}
+
+// Capabilities are the Linux capabilities dropped from the container
+type Capabilities struct {
+	Drop []string `yaml:"drop,omitempty"`
+}
+
+// Service gives the pods of a Deployment a stable name on the cluster
+type Service struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Metadata   Metadata    `yaml:"metadata"`
+	Spec       ServiceSpec `yaml:"spec"`
+}
+
+// ServiceSpec is the spec of a Service
+type ServiceSpec struct {
+	Selector map[string]string `yaml:"selector"`
+	Ports    []ServicePort     `yaml:"ports"`
+}
+
+// ServicePort is a port of a Service. Meshes tell HTTP and gRPC apart from
+// plain TCP by its name and application protocol.
+type ServicePort struct {
+	Name        string `yaml:"name"`
+	Port        int    `yaml:"port"`
+	TargetPort  int    `yaml:"targetPort"`
+	Protocol    string `yaml:"protocol,omitempty"`
+	AppProtocol string `yaml:"appProtocol,omitempty"`
+}
+
+// ConfigMap holds environment variables
+type ConfigMap struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   Metadata          `yaml:"metadata"`
+	Data       map[string]string `yaml:"data"`
+}
+
+// PersistentVolumeClaim stores the data of a named volume
+type PersistentVolumeClaim struct {
+	APIVersion string    `yaml:"apiVersion"`
+	Kind       string    `yaml:"kind"`
+	Metadata   Metadata  `yaml:"metadata"`
+	Spec       ClaimSpec `yaml:"spec"`
+}
+
+// ClaimSpec is the spec of a PersistentVolumeClaim
+type ClaimSpec struct {
+	AccessModes []string             `yaml:"accessModes"`
+	Resources   ResourceRequirements `yaml:"resources"`
+}
+
+// ResourceRequirements is the storage a claim requests
+type ResourceRequirements struct {
+	Requests map[string]string `yaml:"requests"`
+}
+
+// Kustomization lists the generated files so kubectl apply -k applies them
+// together
+type Kustomization struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Kind       string   `yaml:"kind"`
+	Namespace  string   `yaml:"namespace,omitempty"`
+	Resources  []string `yaml:"resources"`
+}
+
+// PeerAuthentication sets whether an Istio workload accepts only mutual TLS
+type PeerAuthentication struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   Metadata               `yaml:"metadata"`
+	Spec       PeerAuthenticationSpec `yaml:"spec"`
+}
+
+// PeerAuthenticationSpec is the spec of a PeerAuthentication
+type PeerAuthenticationSpec struct {
+	Selector LabelSelector `yaml:"selector"`
+	MTLS     MutualTLS     `yaml:"mtls"`
+}
+
+// MutualTLS is the mTLS mode of a PeerAuthentication
+type MutualTLS struct {
+	Mode string `yaml:"mode"`
+}
+
+// ServiceProfile describes the routes of a Service to Linkerd, for per-route
+// metrics and retries
+type ServiceProfile struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   Metadata           `yaml:"metadata"`
+	Spec       ServiceProfileSpec `yaml:"spec"`
+}
+
+// ServiceProfileSpec is the spec of a ServiceProfile
+type ServiceProfileSpec struct {
+	Routes      []Route      `yaml:"routes"`
+	RetryBudget *RetryBudget `yaml:"retryBudget,omitempty"`
+}
+
+// Route is a set of requests of a ServiceProfile
+type Route struct {
+	Name      string         `yaml:"name"`
+	Condition RouteCondition `yaml:"condition"`
+}
+
+// RouteCondition matches the requests of a Route
+type RouteCondition struct {
+	PathRegex string `yaml:"pathRegex"`
+}
+
+// RetryBudget limits the retries Linkerd adds on top of the requests
+type RetryBudget struct {
+	RetryRatio          float64 `yaml:"retryRatio"`
+	MinRetriesPerSecond int     `yaml:"minRetriesPerSecond"`
+	TTL                 string  `yaml:"ttl"`
+}
@@ -1,20 +1,46 @@
 package terraform
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/generator"
+	"github.com/jashkahar/open-workbench-platform/internal/infrastate"
+	"github.com/jashkahar/open-workbench-platform/internal/labels"
 	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/resources"
 )
 
+// placeholderImage is the image of services and components that are built
+// from source, until a pipeline pushes theirs and sets the _image variable
+const placeholderImage = "nginx:alpine"
+
 // Generator implements the Generator interface for Terraform
-type Generator struct{}
+type Generator struct {
+	fs        filesystem.FS
+	outputDir string
+	labels    labels.Info
+}
 
-// NewGenerator creates a new Terraform generator
+var _ generator.Generator = (*Generator)(nil)
+
+// NewGenerator creates a new Terraform generator that writes to the current directory
 func NewGenerator() *Generator {
-	return &Generator{}
+	return NewGeneratorWithFS(filesystem.NewOSFS(), ".")
+}
+
+// NewGeneratorWithFS creates a Terraform generator that writes into outputDir on fsys
+func NewGeneratorWithFS(fsys filesystem.FS, outputDir string) *Generator {
+	return &Generator{fs: fsys, outputDir: outputDir}
+}
+
+// SetLabels sets the om version the generated resources are tagged with.
+// Generate fills in the project and the environment it generates for.
+func (g *Generator) SetLabels(info labels.Info) {
+	g.labels = info
 }
 
 // Name returns the unique identifier for this generator
@@ -53,15 +79,15 @@ func (g *Generator) Validate(manifest *manifestPkg.WorkbenchManifest) error {
 func (g *Generator) Generate(manifest *manifestPkg.WorkbenchManifest) error {
 	// Validate the manifest
 	if err := g.Validate(manifest); err != nil {
-		return fmt.Errorf("manifest validation failed: %w", err)
+		return generator.NewValidationError(g.Name(), err)
 	}
 
 	fmt.Println("🔧 Generating Terraform configuration...")
 
 	// Create terraform directory
-	terraformDir := "terraform"
-	if err := os.MkdirAll(terraformDir, 0755); err != nil {
-		return fmt.Errorf("failed to create terraform directory: %w", err)
+	terraformDir := filepath.Join(g.outputDir, "terraform")
+	if err := g.fs.MkdirAll(terraformDir, 0755); err != nil {
+		return generator.NewGenerationError(g.Name(), "failed to create terraform directory", err)
 	}
 
 	// Get the first environment (for now, we'll use the first one)
@@ -73,39 +99,77 @@ func (g *Generator) Generate(manifest *manifestPkg.WorkbenchManifest) error {
 		break
 	}
 
-	// Get services for this environment
+	// Leave out what the environment excludes, or what only other
+	// environments include
+	manifest, err := manifest.ForEnvironment(targetEnv)
+	if err != nil {
+		return generator.NewValidationError(g.Name(), err)
+	}
+
+	// Resolve ${infra.*} references from the outputs recorded by 'om infra
+	// pull'; until then they stay unresolved and are left out. ${env.*}
+	// references resolve to the values of the environment.
+	state, err := infrastate.Load(g.fs, g.outputDir, targetEnv)
+	if errors.Is(err, infrastate.ErrNoState) {
+		state, err = &infrastate.State{Environment: targetEnv}, nil
+	}
+	if err != nil {
+		return generator.NewGenerationError(g.Name(), "failed to read the infrastructure state", err)
+	}
+
+	// Get services for this environment, pointing external dependencies at
+	// the environment's URLs
 	servicesForEnv := g.getServicesForEnvironment(manifest.Services, targetEnvConfig)
+	for serviceName, service := range servicesForEnv {
+		environment := make(map[string]string, len(service.Environment))
+		for key, value := range service.Environment {
+			value = manifest.ResolveEnvReferences(manifest.ResolveExternalReferences(value, targetEnv), targetEnv)
+			environment[key] = state.Resolve(value)
+		}
+		service.Environment = environment
+		servicesForEnv[serviceName] = service
+	}
 
 	if len(servicesForEnv) == 0 {
-		return fmt.Errorf("no services configured for environment '%s'", targetEnv)
+		return generator.NewValidationError(g.Name(), fmt.Errorf("no services configured for environment '%s'", targetEnv))
 	}
 
 	fmt.Printf("📋 Generating infrastructure for %d services in '%s' environment\n", len(servicesForEnv), targetEnv)
 
 	// Generate main.tf
-	if err := g.generateMainTf(manifest, terraformDir, servicesForEnv, targetEnvConfig); err != nil {
-		return fmt.Errorf("failed to generate main.tf: %w", err)
+	g.labels.Project, g.labels.Environment = manifest.Metadata.Name, targetEnv
+	if err := g.generateMainTf(manifest, terraformDir, servicesForEnv); err != nil {
+		return generator.NewGenerationError(g.Name(), "failed to generate main.tf", err)
 	}
 
 	// Generate variables.tf
 	if err := g.generateVariablesTf(manifest, terraformDir, servicesForEnv); err != nil {
-		return fmt.Errorf("failed to generate variables.tf: %w", err)
+		return generator.NewGenerationError(g.Name(), "failed to generate variables.tf", err)
 	}
 
 	// Generate outputs.tf
 	if err := g.generateOutputsTf(manifest, terraformDir, servicesForEnv); err != nil {
-		return fmt.Errorf("failed to generate outputs.tf: %w", err)
+		return generator.NewGenerationError(g.Name(), "failed to generate outputs.tf", err)
 	}
 
 	// Generate terraform.tfvars.example
 	if err := g.generateTfvarsExample(manifest, terraformDir, servicesForEnv); err != nil {
-		return fmt.Errorf("failed to generate terraform.tfvars.example: %w", err)
+		return generator.NewGenerationError(g.Name(), "failed to generate terraform.tfvars.example", err)
+	}
+
+	// Write the modules the resources are provisioned with
+	data := g.newProjectData(manifest, servicesForEnv)
+	if err := g.writeModules(terraformDir, data); err != nil {
+		return generator.NewGenerationError(g.Name(), "failed to write the resource modules", err)
+	}
+	for _, entry := range data.Unprovisioned {
+		fmt.Printf("⚠️  %s has no Terraform module and is not provisioned\n", entry)
 	}
 
 	fmt.Println("✅ Generated Terraform configuration")
 
 	// Print success message with instructions
-	printTerraformSuccessMessage()
+	printTerraformSuccessMessage(len(data.Resources) > 0)
 
 	return nil
 }
@@ -113,550 +177,93 @@ func (g *Generator) Generate(manifest *manifestPkg.WorkbenchManifest) error {
 // getServicesForEnvironment filters services based on environment configuration
 func (g *Generator) getServicesForEnvironment(allServices map[string]manifestPkg.Service, envConfig manifestPkg.Environment) map[string]manifestPkg.Service {
 	servicesForEnv := make(map[string]manifestPkg.Service)
-
-	// If no services are specified in environment config, include all services
-	if envConfig.Config == nil || envConfig.Config["services"] == "" {
-		return allServices
-	}
-
-	// Parse services from environment config
-	serviceList := strings.Split(envConfig.Config["services"], ",")
-	for _, serviceName := range serviceList {
-		serviceName = strings.TrimSpace(serviceName)
-		if service, exists := allServices[serviceName]; exists {
+	for serviceName, service := range allServices {
+		if envConfig.DeploysService(serviceName, service) {
 			servicesForEnv[serviceName] = service
 		}
 	}
-
 	return servicesForEnv
 }
 
-func (g *Generator) generateMainTf(manifest *manifestPkg.WorkbenchManifest, terraformDir string, servicesForEnv map[string]manifestPkg.Service, envConfig manifestPkg.Environment) error {
-	content := `# Terraform configuration for ` + manifest.Metadata.Name + `
-
-terraform {
-  required_version = ">= 1.0"
-  required_providers {
-    aws = {
-      source  = "hashicorp/aws"
-      version = "~> 5.0"
-    }
-  }
-}
-
-provider "aws" {
-  region = var.aws_region
+// generateMainTf writes main.tf: the network, cluster, and load balancer
+// shared by the project, followed by the resources of every service and
+// component
+func (g *Generator) generateMainTf(manifest *manifestPkg.WorkbenchManifest, terraformDir string, servicesForEnv map[string]manifestPkg.Service) error {
+	return g.writeFile(terraformDir, "main.tf", g.newProjectData(manifest, servicesForEnv))
 }
 
-# VPC and networking
-resource "aws_vpc" "main" {
-  cidr_block           = var.vpc_cidr
-  enable_dns_hostnames = true
-  enable_dns_support   = true
-
-  tags = {
-    Name = "${var.project_name}-vpc"
-  }
-}
-
-resource "aws_subnet" "public" {
-  vpc_id            = aws_vpc.main.id
-  cidr_block        = var.public_subnet_cidr
-  availability_zone = var.availability_zone
-
-  tags = {
-    Name = "${var.project_name}-public-subnet"
-  }
+// generateServiceResources returns the resources of one service
+func (g *Generator) generateServiceResources(serviceName string, service manifestPkg.Service) (string, error) {
+	return render("unit.tf.tmpl", newServiceData(g.labels, serviceName, service))
 }
 
-resource "aws_internet_gateway" "main" {
-  vpc_id = aws_vpc.main.id
-
-  tags = {
-    Name = "${var.project_name}-igw"
-  }
+// generateComponentResources returns the resources of one component
+func (g *Generator) generateComponentResources(componentName string, component manifestPkg.Component) (string, error) {
+	return render("unit.tf.tmpl", newComponentData(g.labels, componentName, component))
 }
 
-resource "aws_route_table" "public" {
-  vpc_id = aws_vpc.main.id
-
-  route {
-    cidr_block = "0.0.0.0/0"
-    gateway_id = aws_internet_gateway.main.id
-  }
-
-  tags = {
-    Name = "${var.project_name}-public-rt"
-  }
-}
-
-resource "aws_route_table_association" "public" {
-  subnet_id      = aws_subnet.public.id
-  route_table_id = aws_route_table.public.id
-}
-
-# Security groups
-resource "aws_security_group" "app" {
-  name_prefix = "${var.project_name}-app-"
-  vpc_id      = aws_vpc.main.id
-
-  ingress {
-    from_port   = 80
-    to_port     = 80
-    protocol    = "tcp"
-    cidr_blocks = ["0.0.0.0/0"]
-  }
-
-  ingress {
-    from_port   = 443
-    to_port     = 443
-    protocol    = "tcp"
-    cidr_blocks = ["0.0.0.0/0"]
-  }
-
-  egress {
-    from_port   = 0
-    to_port     = 0
-    protocol    = "-1"
-    cidr_blocks = ["0.0.0.0/0"]
-  }
-
-  tags = {
-    Name = "${var.project_name}-app-sg"
-  }
-}
-
-# ECS Cluster
-resource "aws_ecs_cluster" "main" {
-  name = "${var.project_name}-cluster"
-
-  setting {
-    name  = "containerInsights"
-    value = "enabled"
-  }
-
-  tags = {
-    Name = "${var.project_name}-cluster"
-  }
-}
-
-# Application Load Balancer (only if we have web services)
-resource "aws_lb" "main" {
-  count              = var.create_load_balancer ? 1 : 0
-  name               = "${var.project_name}-alb"
-  internal           = false
-  load_balancer_type = "application"
-  security_groups    = [aws_security_group.app.id]
-  subnets            = [aws_subnet.public.id]
-
-  tags = {
-    Name = "${var.project_name}-alb"
-  }
-}
-
-resource "aws_lb_listener" "http" {
-  count             = var.create_load_balancer ? 1 : 0
-  load_balancer_arn = aws_lb.main[0].arn
-  port              = "80"
-  protocol          = "HTTP"
-
-  default_action {
-    type = "redirect"
-
-    redirect {
-      port        = "443"
-      protocol    = "HTTPS"
-      status_code = "HTTP_301"
-    }
-  }
-}
-
-# Services
-`
-
-	// Add service-specific resources
-	for serviceName, service := range servicesForEnv {
-		content += g.generateServiceResources(serviceName, service)
+// serviceImage returns the image a service's task definition runs by default:
+// its prebuilt image, or the placeholder for services built from source
+func serviceImage(service manifestPkg.Service) string {
+	if service.UsesImage() {
+		return service.Image
 	}
-
-	// Add component-specific resources (only if they exist)
-	for componentName, component := range manifest.Components {
-		content += g.generateComponentResources(componentName, component)
-	}
-
-	return os.WriteFile(filepath.Join(terraformDir, "main.tf"), []byte(content), 0644)
-}
-
-func (g *Generator) generateServiceResources(serviceName string, service manifestPkg.Service) string {
-	// Determine if this is a web service (has a port)
-	isWebService := service.Port > 0
-
-	// Generate ECS service
-	ecsService := fmt.Sprintf(`
-# Service: %s
-resource "aws_ecs_service" "%s" {
-  name            = "%s"
-  cluster         = aws_ecs_cluster.main.id
-  task_definition = aws_ecs_task_definition.%s.arn
-  desired_count   = var.%s_desired_count
-
-  network_configuration {
-    subnets         = [aws_subnet.public.id]
-    security_groups = [aws_security_group.app.id]
-  }
-`, serviceName, serviceName, serviceName, serviceName, serviceName)
-
-	// Add load balancer configuration only for web services
-	if isWebService {
-		ecsService += fmt.Sprintf(`
-  load_balancer {
-    target_group_arn = aws_lb_target_group.%s.arn
-    container_name   = "%s"
-    container_port   = %d
-  }
-
-  depends_on = [aws_lb_listener.http]
-`, serviceName, serviceName, service.Port)
-	}
-
-	ecsService += fmt.Sprintf(`
-  tags = {
-    Name = "%s"
-  }
-}
-`, serviceName)
-
-	// Generate ECS task definition
-	taskDefinition := fmt.Sprintf(`
-resource "aws_ecs_task_definition" "%s" {
-  family                   = "%s"
-  network_mode             = "awsvpc"
-  requires_compatibilities = ["FARGATE"]
-  cpu                     = var.%s_cpu
-  memory                  = var.%s_memory
-
-  container_definitions = jsonencode([
-    {
-      name  = "%s"
-      image = var.%s_image
-`, serviceName, serviceName, serviceName, serviceName, serviceName, serviceName)
-
-	// Add port mappings only for web services
-	if isWebService {
-		taskDefinition += fmt.Sprintf(`      portMappings = [
-        {
-          containerPort = %d
-          protocol      = "tcp"
-        }
-      ]
-`, service.Port)
-	}
-
-	taskDefinition += fmt.Sprintf(`      environment = [
-        {
-          name  = "NODE_ENV"
-          value = "production"
-        }
-      ]
-      logConfiguration = {
-        logDriver = "awslogs"
-        options = {
-          awslogs-group         = "/ecs/%s"
-          awslogs-region        = var.aws_region
-          awslogs-stream-prefix = "ecs"
-        }
-      }
-    }
-  ])
-
-  tags = {
-    Name = "%s"
-  }
-}
-`, serviceName, serviceName)
-
-	// Generate load balancer target group only for web services
-	var targetGroup string
-	if isWebService {
-		targetGroup = fmt.Sprintf(`
-resource "aws_lb_target_group" "%s" {
-  name     = "%s-tg"
-  port     = %d
-  protocol = "HTTP"
-  vpc_id   = aws_vpc.main.id
-
-  health_check {
-    enabled             = true
-    healthy_threshold   = 2
-    interval            = 30
-    matcher             = "200"
-    path                = "/"
-    port                = "traffic-port"
-    protocol            = "HTTP"
-    timeout             = 5
-    unhealthy_threshold = 2
-  }
-
-  tags = {
-    Name = "%s-tg"
-  }
-}
-`, serviceName, serviceName, service.Port, serviceName)
-	}
-
-	return ecsService + taskDefinition + targetGroup
-}
-
-func (g *Generator) generateComponentResources(componentName string, component manifestPkg.Component) string {
-	content := fmt.Sprintf(`
-# Component: %s
-resource "aws_ecs_service" "%s" {
-  name            = "%s"
-  cluster         = aws_ecs_cluster.main.id
-  task_definition = aws_ecs_task_definition.%s.arn
-  desired_count   = var.%s_desired_count
-
-  network_configuration {
-    subnets         = [aws_subnet.public.id]
-    security_groups = [aws_security_group.app.id]
-  }
-
-  tags = {
-    Name = "%s"
-  }
-}
-
-resource "aws_ecs_task_definition" "%s" {
-  family                   = "%s"
-  network_mode             = "awsvpc"
-  requires_compatibilities = ["FARGATE"]
-  cpu                     = var.%s_cpu
-  memory                  = var.%s_memory
-
-  container_definitions = jsonencode([
-    {
-      name  = "%s"
-      image = var.%s_image
-      portMappings = [
-        {
-          containerPort = 80
-          protocol      = "tcp"
-        }
-      ]
-      environment = [
-        {
-          name  = "NODE_ENV"
-          value = "production"
-        }
-      ]
-      logConfiguration = {
-        logDriver = "awslogs"
-        options = {
-          awslogs-group         = "/ecs/%s"
-          awslogs-region        = var.aws_region
-          awslogs-stream-prefix = "ecs"
-        }
-      }
-    }
-  ])
-
-  tags = {
-    Name = "%s"
-  }
-}
-
-`, componentName, componentName, componentName, componentName, componentName, componentName, componentName, componentName, componentName, componentName, componentName, componentName, componentName, componentName)
-
-	return content
+	return placeholderImage
 }
 
 func (g *Generator) generateVariablesTf(manifest *manifestPkg.WorkbenchManifest, terraformDir string, servicesForEnv map[string]manifestPkg.Service) error {
-	content := `# Variables for ` + manifest.Metadata.Name + `
-
-variable "aws_region" {
-  description = "AWS region"
-  type        = string
-  default     = "us-east-1"
-}
-
-variable "project_name" {
-  description = "Project name"
-  type        = string
-  default     = "` + manifest.Metadata.Name + `"
-}
-
-variable "vpc_cidr" {
-  description = "CIDR block for VPC"
-  type        = string
-  default     = "10.0.0.0/16"
-}
-
-variable "public_subnet_cidr" {
-  description = "CIDR block for public subnet"
-  type        = string
-  default     = "10.0.1.0/24"
-}
-
-variable "availability_zone" {
-  description = "Availability zone"
-  type        = string
-  default     = "us-east-1a"
-}
-
-variable "create_load_balancer" {
-  description = "Whether to create a load balancer"
-  type        = bool
-  default     = true
-}
-
-`
-
-	// Add variables for each service in the environment
-	for serviceName := range servicesForEnv {
-		content += fmt.Sprintf(`
-variable "%s_desired_count" {
-  description = "Desired count for %s service"
-  type        = number
-  default     = 1
-}
-
-variable "%s_cpu" {
-  description = "CPU units for %s service"
-  type        = number
-  default     = 256
-}
-
-variable "%s_memory" {
-  description = "Memory for %s service"
-  type        = number
-  default     = 512
-}
-
-variable "%s_image" {
-  description = "Docker image for %s service"
-  type        = string
-  default     = "nginx:alpine"
-}
-
-`, serviceName, serviceName, serviceName, serviceName, serviceName, serviceName, serviceName, serviceName)
-	}
-
-	// Add variables for each component
-	for componentName := range manifest.Components {
-		content += fmt.Sprintf(`
-variable "%s_desired_count" {
-  description = "Desired count for %s component"
-  type        = number
-  default     = 1
-}
-
-variable "%s_cpu" {
-  description = "CPU units for %s component"
-  type        = number
-  default     = 256
-}
-
-variable "%s_memory" {
-  description = "Memory for %s component"
-  type        = number
-  default     = 512
-}
-
-variable "%s_image" {
-  description = "Docker image for %s component"
-  type        = string
-  default     = "nginx:alpine"
-}
-
-`, componentName, componentName, componentName, componentName, componentName, componentName, componentName, componentName)
-	}
-
-	return os.WriteFile(filepath.Join(terraformDir, "variables.tf"), []byte(content), 0644)
+	return g.writeFile(terraformDir, "variables.tf", g.newProjectData(manifest, servicesForEnv))
 }
 
 func (g *Generator) generateOutputsTf(manifest *manifestPkg.WorkbenchManifest, terraformDir string, servicesForEnv map[string]manifestPkg.Service) error {
-	content := `# Outputs for ` + manifest.Metadata.Name + `
-
-output "vpc_id" {
-  description = "VPC ID"
-  value       = aws_vpc.main.id
-}
-
-output "ecs_cluster_name" {
-  description = "ECS cluster name"
-  value       = aws_ecs_cluster.main.name
-}
-
-`
-
-	// Add load balancer output only if we're creating one
-	content += `
-output "alb_dns_name" {
-  description = "Application Load Balancer DNS name"
-  value       = var.create_load_balancer ? aws_lb.main[0].dns_name : null
+	return g.writeFile(terraformDir, "outputs.tf", g.newProjectData(manifest, servicesForEnv))
 }
 
-`
-
-	// Add outputs for each service in the environment
-	for serviceName := range servicesForEnv {
-		content += fmt.Sprintf(`
-output "%s_service_name" {
-  description = "%s service name"
-  value       = aws_ecs_service.%s.name
-}
-
-output "%s_task_definition_arn" {
-  description = "%s task definition ARN"
-  value       = aws_ecs_task_definition.%s.arn
+func (g *Generator) generateTfvarsExample(manifest *manifestPkg.WorkbenchManifest, terraformDir string, servicesForEnv map[string]manifestPkg.Service) error {
+	return g.writeFile(terraformDir, "terraform.tfvars.example", g.newProjectData(manifest, servicesForEnv))
 }
 
-`, serviceName, serviceName, serviceName, serviceName, serviceName, serviceName)
+// writeFile renders the template of a generated file into terraformDir
+func (g *Generator) writeFile(terraformDir, name string, data projectData) error {
+	content, err := render(name+".tmpl", data)
+	if err != nil {
+		return err
 	}
-
-	return os.WriteFile(filepath.Join(terraformDir, "outputs.tf"), []byte(content), 0644)
+	return g.fs.WriteFile(filepath.Join(terraformDir, name), []byte(content), 0644)
 }
 
-func (g *Generator) generateTfvarsExample(manifest *manifestPkg.WorkbenchManifest, terraformDir string, servicesForEnv map[string]manifestPkg.Service) error {
-	content := `# Example terraform.tfvars for ` + manifest.Metadata.Name + `
-
-aws_region = "us-east-1"
-project_name = "` + manifest.Metadata.Name + `"
-vpc_cidr = "10.0.0.0/16"
-public_subnet_cidr = "10.0.1.0/24"
-availability_zone = "us-east-1a"
-create_load_balancer = true
-
-`
-
-	// Add example values for each service in the environment
-	for serviceName := range servicesForEnv {
-		content += fmt.Sprintf(`
-# %s service configuration
-%s_desired_count = 1
-%s_cpu = 256
-%s_memory = 512
-%s_image = "nginx:alpine"
-
-`, serviceName, serviceName, serviceName, serviceName, serviceName)
-	}
-
-	// Add example values for each component
-	for componentName := range manifest.Components {
-		content += fmt.Sprintf(`
-# %s component configuration
-%s_desired_count = 1
-%s_cpu = 256
-%s_memory = 512
-%s_image = "nginx:alpine"
-
-`, componentName, componentName, componentName, componentName, componentName)
+// writeModules writes the vendored module of every resource's blueprint
+// into terraformDir, where the module blocks of main.tf reference them
+func (g *Generator) writeModules(terraformDir string, data projectData) error {
+	registry := resources.NewRegistry()
+	written := make(map[string]bool)
+	for _, resource := range data.Resources {
+		if !strings.HasPrefix(resource.Source, "./") || written[resource.Source] {
+			continue
+		}
+		written[resource.Source] = true
+		blueprint, err := registry.Get(resources.BlueprintName(resource.Type))
+		if err != nil {
+			return err
+		}
+		files, err := blueprint.ModuleFiles()
+		if err != nil {
+			return err
+		}
+		moduleDir := filepath.Join(terraformDir, filepath.FromSlash(strings.TrimPrefix(resource.Source, "./")))
+		if err := g.fs.MkdirAll(moduleDir, 0755); err != nil {
+			return err
+		}
+		for name, content := range files {
+			if err := g.fs.WriteFile(filepath.Join(moduleDir, name), content, 0644); err != nil {
+				return err
+			}
+		}
 	}
-
-	return os.WriteFile(filepath.Join(terraformDir, "terraform.tfvars.example"), []byte(content), 0644)
+	return nil
 }
 
-func printTerraformSuccessMessage() {
+func printTerraformSuccessMessage(modules bool) {
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("✅ Successfully generated Terraform configuration!")
 	fmt.Println(strings.Repeat("=", 60))
@@ -666,6 +273,9 @@ func printTerraformSuccessMessage() {
 	fmt.Println("  • terraform/variables.tf - Variable definitions")
 	fmt.Println("  • terraform/outputs.tf - Output definitions")
 	fmt.Println("  • terraform/terraform.tfvars.example - Example variable values")
+	if modules {
+		fmt.Println("  • terraform/modules/ - Modules provisioning the resources")
+	}
 
 	fmt.Println("\n🚀 To deploy your infrastructure:")
 	fmt.Println("  1. cd terraform")
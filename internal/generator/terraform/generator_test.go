@@ -3,8 +3,12 @@ package terraform
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/infrastate"
+	"github.com/jashkahar/open-workbench-platform/internal/labels"
 	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
 )
 
@@ -203,6 +207,109 @@ func TestGenerator_Generate(t *testing.T) {
 	}
 }
 
+func TestGenerator_Tags(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	generator := NewGeneratorWithFS(fsys, ".")
+	generator.SetLabels(labels.Info{Version: "1.2.0"})
+
+	manifest := &manifestPkg.WorkbenchManifest{
+		Metadata: manifestPkg.ProjectMetadata{Name: "demo"},
+		Services: map[string]manifestPkg.Service{
+			"api": {Template: "fastapi-basic", Path: "api", Port: 8000,
+				Ownership: manifestPkg.Ownership{Team: "payments", Tags: []string{"pci", "tier:1"}}},
+		},
+		Environments: map[string]manifestPkg.Environment{
+			"prod": {Provider: "aws", Region: "us-east-1"},
+		},
+	}
+	if err := generator.Generate(manifest); err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	data, err := fsys.ReadFile(filepath.Join("terraform", "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+
+	// Every resource gets the project, environment, and version from the provider
+	for _, element := range []string{
+		"  default_tags {",
+		`      "com.openworkbench.environment" = "prod"`,
+		`      "com.openworkbench.project" = "demo"`,
+		`      "com.openworkbench.version" = "1.2.0"`,
+	} {
+		if !contains(content, element) {
+			t.Errorf("main.tf missing default tag: %s", element)
+		}
+	}
+
+	// The resources of a service are tagged with its entry, template, and owners
+	for _, element := range []string{
+		`    "com.openworkbench.entry" = "services.api"`,
+		`    "com.openworkbench.service" = "api"`,
+		`    "com.openworkbench.tags" = "pci tier:1"`,
+		`    "com.openworkbench.team" = "payments"`,
+		`    "com.openworkbench.template" = "fastapi-basic"`,
+	} {
+		if strings.Count(content, element) != 3 {
+			t.Errorf("expected the service, task definition, and target group to be tagged %s", element)
+		}
+	}
+}
+
+func TestGenerator_DeterministicOrder(t *testing.T) {
+	manifest := &manifestPkg.WorkbenchManifest{
+		Metadata: manifestPkg.ProjectMetadata{Name: "demo"},
+		Services: map[string]manifestPkg.Service{
+			"web":    {Path: "web", Port: 3000},
+			"api":    {Path: "api", Port: 8000},
+			"worker": {Path: "worker"},
+		},
+		Components: map[string]manifestPkg.Component{
+			"gateway": {Path: "gateway"},
+			"cache":   {Path: "cache"},
+		},
+		Environments: map[string]manifestPkg.Environment{
+			"prod": {Provider: "aws", Region: "us-east-1"},
+		},
+	}
+
+	var previous map[string]string
+	for run := 0; run < 3; run++ {
+		fsys := filesystem.NewMemFS()
+		if err := NewGeneratorWithFS(fsys, ".").Generate(manifest); err != nil {
+			t.Fatalf("Generate() failed: %v", err)
+		}
+		files := map[string]string{}
+		for _, name := range []string{"main.tf", "variables.tf", "outputs.tf", "terraform.tfvars.example"} {
+			data, err := fsys.ReadFile(filepath.Join("terraform", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			files[name] = string(data)
+		}
+		if previous != nil {
+			for name, content := range files {
+				if content != previous[name] {
+					t.Errorf("%s changed between runs", name)
+				}
+			}
+		}
+		previous = files
+	}
+
+	main := previous["main.tf"]
+	order := []string{"# Service: api", "# Service: web", "# Service: worker", "# Component: cache", "# Component: gateway"}
+	for i := 1; i < len(order); i++ {
+		if strings.Index(main, order[i-1]) > strings.Index(main, order[i]) {
+			t.Errorf("expected %q before %q in main.tf", order[i-1], order[i])
+		}
+	}
+	if !strings.HasSuffix(main, "}\n") || strings.Contains(main, "\n\n\n") {
+		t.Error("expected main.tf without runs of blank lines")
+	}
+}
+
 func TestGenerator_generateServiceResources(t *testing.T) {
 	generator := NewGenerator()
 
@@ -215,11 +322,12 @@ func TestGenerator_generateServiceResources(t *testing.T) {
 		},
 	}
 
-	content := generator.generateServiceResources("frontend", service)
+	content := serviceResources(t, generator, "frontend", service)
 
 	// Verify that the generated content contains expected elements
 	expectedElements := []string{
 		"# Service: frontend",
+		"# om: services.frontend (ecs-service)",
 		"resource \"aws_ecs_service\" \"frontend\"",
 		"resource \"aws_ecs_task_definition\" \"frontend\"",
 		"resource \"aws_lb_target_group\" \"frontend\"",
@@ -235,6 +343,302 @@ func TestGenerator_generateServiceResources(t *testing.T) {
 	}
 }
 
+func TestGenerator_generateServiceResources_Protocols(t *testing.T) {
+	generator := NewGenerator()
+
+	grpc := serviceResources(t, generator, "orders", manifestPkg.Service{Path: "orders", Port: 50051, Protocol: manifestPkg.ProtocolGRPC})
+	for _, element := range []string{
+		"protocol_version = \"GRPC\"",
+		"path                = \"/grpc.health.v1.Health/Check\"",
+		"matcher             = \"0\"",
+	} {
+		if !contains(grpc, element) {
+			t.Errorf("gRPC target group missing expected element: %s", element)
+		}
+	}
+
+	http := serviceResources(t, generator, "api", manifestPkg.Service{Path: "api", Port: 8000, Health: &manifestPkg.Health{Path: "/health", Status: 204}})
+	for _, element := range []string{
+		"path                = \"/health\"",
+		"matcher             = \"204\"",
+	} {
+		if !contains(http, element) {
+			t.Errorf("HTTP target group missing expected element: %s", element)
+		}
+	}
+
+	tcp := serviceResources(t, generator, "broker", manifestPkg.Service{Path: "broker", Port: 1883, Protocol: manifestPkg.ProtocolTCP})
+	if contains(tcp, "aws_lb_target_group") || contains(tcp, "load_balancer {") {
+		t.Error("TCP services should not be attached to the application load balancer")
+	}
+	if !contains(tcp, "containerPort = 1883") {
+		t.Error("TCP services should still publish their container port")
+	}
+}
+
+func TestGenerator_generateServiceResources_Environment(t *testing.T) {
+	generator := NewGenerator()
+
+	content := serviceResources(t, generator, "api", manifestPkg.Service{
+		Path: "api",
+		Port: 8080,
+		Environment: map[string]string{
+			"STRIPE_URL": "https://api.stripe.com",
+			"DB_HOST":    "${services.api.resources.db.name}",
+		},
+	})
+
+	if !contains(content, `name  = "STRIPE_URL"`) || !contains(content, `value = "https://api.stripe.com"`) {
+		t.Error("resolved environment variables should be passed to the container")
+	}
+	if !contains(content, `name  = "NODE_ENV"`) {
+		t.Error("NODE_ENV should always be set")
+	}
+	if contains(content, "DB_HOST") {
+		t.Error("unresolved references should not be passed to the container")
+	}
+}
+
+func TestGenerator_CommandOverrides(t *testing.T) {
+	generator := NewGenerator()
+
+	content := serviceResources(t, generator, "api", manifestPkg.Service{
+		Path:       "api",
+		Port:       8000,
+		Entrypoint: manifestPkg.Command{"/bin/sh", "-c"},
+		Command:    manifestPkg.Command{"uvicorn", "app:app", "--port", "8000"},
+	})
+	if !contains(content, `entryPoint = ["/bin/sh", "-c"]`) {
+		t.Errorf("expected the entrypoint override in the task definition, got:\n%s", content)
+	}
+	if !contains(content, `command    = ["uvicorn", "app:app", "--port", "8000"]`) {
+		t.Errorf("expected the command override in the task definition, got:\n%s", content)
+	}
+
+	component := componentResources(t, generator, "gateway", manifestPkg.Component{
+		Path:    "gateway",
+		Command: manifestPkg.Command{"nginx", "-g", "daemon off;"},
+	})
+	if !contains(component, `command    = ["nginx", "-g", "daemon off;"]`) {
+		t.Errorf("expected the command override in the component task definition, got:\n%s", component)
+	}
+	if contains(serviceResources(t, generator, "web", manifestPkg.Service{Path: "web", Port: 3000}), "command") {
+		t.Error("services without overrides should keep the image's command")
+	}
+}
+
+func TestGenerator_RestartAndInit(t *testing.T) {
+	generator := NewGenerator()
+
+	content := serviceResources(t, generator, "api", manifestPkg.Service{
+		Path:    "api",
+		Port:    8000,
+		Restart: manifestPkg.RestartOnFailure,
+		Init:    true,
+	})
+	if !contains(content, "deployment_circuit_breaker {\n    enable   = true\n    rollback = true\n  }") {
+		t.Errorf("expected on-failure to roll back failed deployments, got:\n%s", content)
+	}
+	if !contains(content, "initProcessEnabled = true") {
+		t.Errorf("expected the init process to be enabled, got:\n%s", content)
+	}
+
+	component := componentResources(t, generator, "gateway", manifestPkg.Component{
+		Path:    "gateway",
+		Restart: manifestPkg.RestartNo,
+	})
+	if !contains(component, "rollback = false") {
+		t.Errorf("expected restart: no to stop failed deployments without rollback, got:\n%s", component)
+	}
+
+	plain := serviceResources(t, generator, "web", manifestPkg.Service{Path: "web", Port: 3000, Restart: manifestPkg.RestartUnlessStopped})
+	if contains(plain, "deployment_circuit_breaker") || contains(plain, "linuxParameters") {
+		t.Errorf("unless-stopped matches the ECS default and needs no settings, got:\n%s", plain)
+	}
+}
+
+func TestGenerator_Security(t *testing.T) {
+	generator := NewGenerator()
+
+	content := serviceResources(t, generator, "api", manifestPkg.Service{
+		Path: "api",
+		Port: 8000,
+		Init: true,
+		Security: &manifestPkg.Security{
+			ReadOnlyRootFilesystem: true,
+			CapDrop:                []string{"ALL"},
+			User:                   "1000:1000",
+			NoNewPrivileges:        true,
+		},
+	})
+	for _, want := range []string{
+		`user  = "1000:1000"`,
+		"readonlyRootFilesystem = true",
+		"linuxParameters = {\n        initProcessEnabled = true\n        capabilities = {\n          drop = [\"ALL\"]\n        }\n      }",
+	} {
+		if !contains(content, want) {
+			t.Errorf("expected %q in the task definition, got:\n%s", want, content)
+		}
+	}
+	if contains(content, "dockerSecurityOptions") {
+		t.Error("Fargate task definitions cannot set docker security options")
+	}
+
+	component := componentResources(t, generator, "gateway", manifestPkg.Component{
+		Path:     "gateway",
+		Security: &manifestPkg.Security{ReadOnlyRootFilesystem: true, User: "101"},
+	})
+	if !contains(component, `user  = "101"`) || !contains(component, "readonlyRootFilesystem = true") {
+		t.Errorf("expected the component's hardening in its task definition, got:\n%s", component)
+	}
+}
+
+func TestGenerator_getServicesForEnvironment_SkipsLocalServices(t *testing.T) {
+	generator := NewGenerator()
+	services := map[string]manifestPkg.Service{
+		"backend": {Path: "backend", Port: 8080},
+		"mobile":  {Path: "mobile", Kind: manifestPkg.ServiceKindLocal, Dev: "npx expo start"},
+	}
+
+	all := generator.getServicesForEnvironment(services, manifestPkg.Environment{})
+	if _, exists := all["mobile"]; exists || len(all) != 1 {
+		t.Errorf("expected only container services, got %v", all)
+	}
+
+	listed := generator.getServicesForEnvironment(services, manifestPkg.Environment{
+		Config: map[string]string{"services": "backend,mobile"},
+	})
+	if _, exists := listed["mobile"]; exists || len(listed) != 1 {
+		t.Errorf("expected local services to be skipped even when listed, got %v", listed)
+	}
+}
+
+func TestGenerator_EnvironmentExclude(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	manifest := &manifestPkg.WorkbenchManifest{
+		Metadata: manifestPkg.ProjectMetadata{Name: "demo"},
+		Services: map[string]manifestPkg.Service{
+			"api":           {Path: "api", Port: 8000},
+			"payments-mock": {Path: "payments-mock", Port: 4010},
+		},
+		Environments: map[string]manifestPkg.Environment{
+			"prod": {Provider: "aws", Exclude: []string{"payments-mock"}},
+		},
+	}
+	if err := NewGeneratorWithFS(fsys, ".").Generate(manifest); err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	for _, file := range []string{"main.tf", "variables.tf", "outputs.tf"} {
+		data, err := fsys.ReadFile(filepath.Join("terraform", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !contains(string(data), "api") || contains(string(data), "payments-mock") {
+			t.Errorf("expected %s to deploy api without the excluded mock", file)
+		}
+	}
+}
+
+func TestGenerator_ResourceModules(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	manifest := &manifestPkg.WorkbenchManifest{
+		Metadata: manifestPkg.ProjectMetadata{Name: "demo"},
+		Services: map[string]manifestPkg.Service{
+			"api": {Path: "api", Port: 8000, Resources: map[string]manifestPkg.Resource{
+				"db":     {Type: "postgres", Version: "16", Config: map[string]string{"database_name": "orders"}},
+				"search": {Type: "elasticsearch"},
+			}},
+		},
+		Environments: map[string]manifestPkg.Environment{"prod": {Provider: "aws"}},
+	}
+	if err := NewGeneratorWithFS(fsys, ".").Generate(manifest); err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+
+	read := func(name string) string {
+		data, err := fsys.ReadFile(filepath.Join("terraform", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	mainTf := read("main.tf")
+	for _, want := range []string{
+		"# Resource: services.api.resources.db\n# om: services.api.resources.db (terraform-module)\nmodule \"api_db\" {\n  source = \"./modules/aws/rds-postgres\"\n\n  name",
+		`name                       = "${var.project_name}-api-db"`,
+		`engine_version             = "16"`,
+		`database_name              = "orders"`,
+		`username                   = "postgres"`,
+		`password                   = var.api_db_password`,
+		`port                       = 5432`,
+	} {
+		if !contains(mainTf, want) {
+			t.Errorf("expected %q in main.tf, got:\n%s", want, mainTf)
+		}
+	}
+	if contains(mainTf, "search") {
+		t.Error("expected a resource without a Terraform module to be left out")
+	}
+	if variables := read("variables.tf"); !contains(variables, "variable \"api_db_password\" {\n  description = \"Database password of services.api.resources.db\"\n  type        = string\n  sensitive   = true\n}") {
+		t.Errorf("expected the password to be a sensitive variable, got:\n%s", variables)
+	}
+	if !contains(read("outputs.tf"), "value       = module.api_db.endpoint") {
+		t.Error("expected the endpoint of the database as an output")
+	}
+	if !contains(read("terraform.tfvars.example"), `api_db_password = "change-me"`) {
+		t.Error("expected the password in terraform.tfvars.example")
+	}
+
+	// The module ships with the configuration
+	for _, file := range []string{"main.tf", "variables.tf", "outputs.tf"} {
+		if !filesystem.Exists(fsys, filepath.Join("terraform", "modules", "aws", "rds-postgres", file)) {
+			t.Errorf("expected the rds-postgres module to include %s", file)
+		}
+	}
+}
+
+func TestGenerator_Interpolation(t *testing.T) {
+	manifest := &manifestPkg.WorkbenchManifest{
+		Metadata: manifestPkg.ProjectMetadata{Name: "demo"},
+		Services: map[string]manifestPkg.Service{
+			"api": {Path: "api", Port: 8000, Environment: map[string]string{
+				"ASSETS_BUCKET": "${infra.assets_bucket}",
+				"DATABASE_HOST": "${infra.db_endpoint}",
+				"LOG_LEVEL":     "${env.logLevel}",
+				"STAGE":         "${env.name}-${env.region}",
+			}},
+		},
+		Infra: map[string]string{"assets_bucket": "assets-local", "db_endpoint": "${services.api.resources.db.name}"},
+		Environments: map[string]manifestPkg.Environment{
+			"prod": {Provider: "aws", Region: "eu-west-1", Config: map[string]string{"logLevel": "warn"}},
+		},
+	}
+
+	fsys := filesystem.NewMemFS()
+	state := &infrastate.State{Environment: "prod", Outputs: map[string]string{"assets_bucket": "demo-assets-prod"}}
+	if err := infrastate.Save(fsys, ".", state); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewGeneratorWithFS(fsys, ".").Generate(manifest); err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	data, err := fsys.ReadFile(filepath.Join("terraform", "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !contains(string(data), `"demo-assets-prod"`) {
+		t.Error("expected the recorded bucket name in the task definition")
+	}
+	for _, element := range []string{`"warn"`, `"prod-eu-west-1"`} {
+		if !contains(string(data), element) {
+			t.Errorf("expected the environment's value %s in the task definition", element)
+		}
+	}
+	if contains(string(data), "DATABASE_HOST") {
+		t.Error("expected references to outputs not recorded yet to be left out")
+	}
+}
+
 func TestGenerator_generateComponentResources(t *testing.T) {
 	generator := NewGenerator()
 
@@ -244,7 +648,7 @@ func TestGenerator_generateComponentResources(t *testing.T) {
 		Ports:    []string{"80", "443"},
 	}
 
-	content := generator.generateComponentResources("gateway", component)
+	content := componentResources(t, generator, "gateway", component)
 
 	// Verify that the generated content contains expected elements
 	expectedElements := []string{
@@ -260,6 +664,19 @@ func TestGenerator_generateComponentResources(t *testing.T) {
 	}
 }
 
+func TestGenerator_writeFile_RenderError(t *testing.T) {
+	generator := NewGeneratorWithFS(filesystem.NewMemFS(), ".")
+
+	// A file that cannot be rendered is reported instead of crashing om
+	err := generator.writeFile("terraform", "missing.tf", projectData{})
+	if err == nil || !strings.Contains(err.Error(), "failed to render missing.tf.tmpl") {
+		t.Fatalf("expected a render error, got %v", err)
+	}
+	if _, err := generator.fs.Stat(filepath.Join("terraform", "missing.tf")); err == nil {
+		t.Error("expected nothing to be written for a file that failed to render")
+	}
+}
+
 func TestGenerator_generateVariablesTf(t *testing.T) {
 	generator := NewGenerator()
 
@@ -332,6 +749,39 @@ func TestGenerator_generateVariablesTf(t *testing.T) {
 	}
 }
 
+func TestGenerator_generateVariablesTf_PrebuiltImage(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	generator := NewGeneratorWithFS(fsys, ".")
+	if err := fsys.MkdirAll("terraform", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	servicesForEnv := map[string]manifestPkg.Service{
+		"auth":     {Image: "registry.internal/auth:1.4.2", Port: 9000},
+		"frontend": {Path: "frontend", Port: 3000},
+	}
+	manifest := &manifestPkg.WorkbenchManifest{
+		Metadata: manifestPkg.ProjectMetadata{Name: "test-project"},
+		Services: servicesForEnv,
+	}
+
+	if err := generator.generateVariablesTf(manifest, "terraform", servicesForEnv); err != nil {
+		t.Fatalf("generateVariablesTf() failed: %v", err)
+	}
+	content, err := fsys.ReadFile(filepath.Join("terraform", "variables.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"variable \"auth_image\" {\n  description = \"Docker image for auth service\"\n  type        = string\n  default     = \"registry.internal/auth:1.4.2\"",
+		"variable \"frontend_image\" {\n  description = \"Docker image for frontend service\"\n  type        = string\n  default     = \"nginx:alpine\"",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("variables.tf missing %q", want)
+		}
+	}
+}
+
 func TestGenerator_generateOutputsTf(t *testing.T) {
 	generator := NewGenerator()
 
@@ -468,6 +918,28 @@ func TestGenerator_generateTfvarsExample(t *testing.T) {
 }
 
 // Helper function to check if a string contains a substring
+// serviceResources renders the resources of one service, failing the test
+// if the template cannot be rendered
+func serviceResources(t *testing.T, generator *Generator, name string, service manifestPkg.Service) string {
+	t.Helper()
+	content, err := generator.generateServiceResources(name, service)
+	if err != nil {
+		t.Fatalf("generateServiceResources failed: %v", err)
+	}
+	return content
+}
+
+// componentResources renders the resources of one component, failing the
+// test if the template cannot be rendered
+func componentResources(t *testing.T, generator *Generator, name string, component manifestPkg.Component) string {
+	t.Helper()
+	content, err := generator.generateComponentResources(name, component)
+	if err != nil {
+		t.Fatalf("generateComponentResources failed: %v", err)
+	}
+	return content
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
 		(len(s) > len(substr) && (s[:len(substr)] == substr ||
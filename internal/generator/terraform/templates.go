@@ -0,0 +1,366 @@
+package terraform
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/jashkahar/open-workbench-platform/internal/explain"
+	"github.com/jashkahar/open-workbench-platform/internal/labels"
+	manifestPkg "github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/resources"
+	"github.com/jashkahar/open-workbench-platform/internal/revision"
+)
+
+// The generated files are rendered from embedded templates, one per file,
+// plus unit.tf.tmpl for the resources of a single service or component and
+// module.tf.tmpl for the module of a single resource.
+// Templates only lay out HCL; every decision about what a unit needs is made
+// while building its unitData.
+//
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+var templates = template.Must(template.New("terraform").Funcs(template.FuncMap{
+	"quote":           func(value string) string { return fmt.Sprintf("%q", value) },
+	"list":            hclList,
+	"revisionComment": revision.Comment,
+}).ParseFS(templatesFS, "templates/*.tmpl"))
+
+// Unit kinds, as they appear in variable descriptions
+const (
+	kindService   = "service"
+	kindComponent = "component"
+)
+
+// projectData is what the generated files of a project are rendered from
+type projectData struct {
+	Project     string
+	Revision    string // revision of workbench.yaml, recorded in the header
+	DefaultTags []tag  // tags the provider puts on every resource
+	Services    []unitData
+	Components  []unitData
+	Resources   []resourceData
+
+	// Unprovisioned lists the resources without a Terraform module
+	Unprovisioned []string
+}
+
+// Units returns the services followed by the components
+func (d projectData) Units() []unitData {
+	return append(append([]unitData(nil), d.Services...), d.Components...)
+}
+
+// unitData describes the ECS service, task definition, and, for services
+// behind the load balancer, the target group of one service or component
+type unitData struct {
+	Kind   string
+	Name   string
+	Marker string // explain marker of the entry the resources come from
+	Tags   []tag  // tags beyond the provider's default tags
+	Image  string // default of the _image variable
+
+	Port         int // container port, zero when nothing is published
+	LoadBalanced bool
+	GRPC         bool
+	HealthPath   string // target group health check of HTTP units
+	HealthStatus int
+
+	// A restart policy maps onto the deployment circuit breaker
+	CircuitBreaker bool
+	Rollback       bool
+
+	Entrypoint             []string
+	Command                []string
+	User                   string
+	ReadOnlyRootFilesystem bool
+	Init                   bool
+	CapDrop                []string
+	Environment            []tag
+}
+
+// resourceData describes the module block that provisions one resource of
+// a service from the Terraform module of its blueprint
+type resourceData struct {
+	Name    string // module name, the service and resource joined by _
+	Entry   string // manifest path of the resource
+	Type    string // the resource's blueprint
+	Marker  string
+	Tags    []tag
+	Source  string
+	Version string // version of a module fetched from a registry
+
+	// Inputs are the module's arguments as HCL expressions; values that
+	// are secret or missing come from Variables instead
+	Inputs    []tag
+	Variables []moduleVariable
+}
+
+// moduleVariable is a variable the generated configuration declares for a
+// module argument
+type moduleVariable struct {
+	Name        string
+	Description string
+	Sensitive   bool
+}
+
+// InputWidth returns the length of the longest input name, which the
+// template aligns the arguments on
+func (r resourceData) InputWidth() int {
+	width := 0
+	for _, input := range r.Inputs {
+		width = max(width, len(input.Key))
+	}
+	return width
+}
+
+// TagBlock returns the tags of the resource's module
+func (r resourceData) TagBlock() tagBlock {
+	return tagBlock{Name: strings.ReplaceAll(r.Name, "_", "-"), Tags: r.Tags}
+}
+
+// tag is a key and value of a tags map or a container environment
+type tag struct {
+	Key   string
+	Value string
+}
+
+// tagBlock is what the tags template renders: the Name tag and the rest
+type tagBlock struct {
+	Name string
+	Tags []tag
+}
+
+// TagBlock returns the tags of the unit's resource named after the unit
+// with suffix appended
+func (u unitData) TagBlock(suffix string) tagBlock {
+	return tagBlock{Name: u.Name + suffix, Tags: u.Tags}
+}
+
+// newServiceData builds the unit of a service. Plain TCP services publish
+// their port but cannot sit behind the application load balancer.
+func newServiceData(info labels.Info, name string, service manifestPkg.Service) unitData {
+	unit := unitData{
+		Kind:        kindService,
+		Name:        name,
+		Marker:      explain.Origin{Entry: "services." + name, Rule: explain.RuleECSService}.Marker(),
+		Tags:        tagList(entryTags(info, "services."+name, name, service.Template, service.Ownership)),
+		Image:       serviceImage(service),
+		Port:        service.Port,
+		Entrypoint:  service.Entrypoint,
+		Command:     service.Command,
+		Init:        service.Init,
+		Environment: containerEnvironment(service),
+	}
+	protocol := service.ProtocolOrDefault()
+	unit.LoadBalanced = service.Port > 0 && protocol != manifestPkg.ProtocolTCP
+	unit.GRPC = protocol == manifestPkg.ProtocolGRPC
+	unit.HealthPath, unit.HealthStatus = service.HealthPath(), service.HealthStatus()
+	unit.CircuitBreaker, unit.Rollback = deploymentCircuitBreaker(service.Restart)
+	unit.applySecurity(service.Security)
+	return unit
+}
+
+// newComponentData builds the unit of a component, which serves on port 80
+func newComponentData(info labels.Info, name string, component manifestPkg.Component) unitData {
+	unit := unitData{
+		Kind:         kindComponent,
+		Name:         name,
+		Marker:       explain.Origin{Entry: "components." + name, Rule: explain.RuleECSComponent}.Marker(),
+		Tags:         tagList(entryTags(info, "components."+name, name, component.Template, component.Ownership)),
+		Image:        placeholderImage,
+		Port:         80,
+		HealthPath:   manifestPkg.DefaultHealthPath,
+		HealthStatus: manifestPkg.DefaultHealthStatus,
+		Entrypoint:   component.Entrypoint,
+		Command:      component.Command,
+		Init:         component.Init,
+		Environment:  []tag{{Key: "NODE_ENV", Value: "production"}},
+	}
+	unit.CircuitBreaker, unit.Rollback = deploymentCircuitBreaker(component.Restart)
+	unit.applySecurity(component.Security)
+	return unit
+}
+
+// applySecurity sets the hardening settings of a service or component on its
+// container. noNewPrivileges has no counterpart: Fargate does not accept
+// dockerSecurityOptions.
+func (u *unitData) applySecurity(security *manifestPkg.Security) {
+	if security == nil {
+		return
+	}
+	u.User = security.User
+	u.ReadOnlyRootFilesystem = security.ReadOnlyRootFilesystem
+	u.CapDrop = security.CapDrop
+}
+
+// newProjectData builds the data of the generated files, with the services
+// and components in name order
+func (g *Generator) newProjectData(manifest *manifestPkg.WorkbenchManifest, servicesForEnv map[string]manifestPkg.Service) projectData {
+	data := projectData{
+		Project:     manifest.Metadata.Name,
+		Revision:    g.labels.Revision,
+		DefaultTags: tagList(g.labels.Common()),
+	}
+	for _, name := range slices.Sorted(maps.Keys(servicesForEnv)) {
+		data.Services = append(data.Services, newServiceData(g.labels, name, servicesForEnv[name]))
+	}
+	for _, name := range slices.Sorted(maps.Keys(manifest.Components)) {
+		data.Components = append(data.Components, newComponentData(g.labels, name, manifest.Components[name]))
+	}
+	registry := resources.NewRegistry()
+	for _, serviceName := range slices.Sorted(maps.Keys(servicesForEnv)) {
+		service := servicesForEnv[serviceName]
+		for _, resourceName := range slices.Sorted(maps.Keys(service.Resources)) {
+			entry := fmt.Sprintf("services.%s.resources.%s", serviceName, resourceName)
+			blueprint, err := registry.Get(resources.BlueprintName(service.Resources[resourceName].Type))
+			if err != nil || blueprint.TerraformModule == "" {
+				data.Unprovisioned = append(data.Unprovisioned, entry)
+				continue
+			}
+			data.Resources = append(data.Resources, newResourceData(g.labels, serviceName, service, resourceName, blueprint))
+		}
+	}
+	return data
+}
+
+// newResourceData builds the module block of a resource. Every parameter of
+// the blueprint is passed to the module: passwords and parameters without a
+// value become variables of the configuration, so that no secret is
+// written into main.tf.
+func newResourceData(info labels.Info, serviceName string, service manifestPkg.Service, resourceName string, blueprint resources.ResourceBlueprint) resourceData {
+	resource := service.Resources[resourceName]
+	entry := fmt.Sprintf("services.%s.resources.%s", serviceName, resourceName)
+	name := moduleName(serviceName + "_" + resourceName)
+	data := resourceData{
+		Name:   name,
+		Entry:  entry,
+		Type:   blueprint.Name,
+		Marker: explain.Origin{Entry: entry, Rule: explain.RuleTerraformModule}.Marker(),
+		Tags:   tagList(entryTags(info, entry, serviceName, "", service.Ownership)),
+		Source: blueprint.TerraformModule,
+		Inputs: []tag{
+			{Key: "name", Value: fmt.Sprintf("%q", "${var.project_name}-"+strings.ReplaceAll(name, "_", "-"))},
+			{Key: "vpc_id", Value: "aws_vpc.main.id"},
+			{Key: "subnet_ids", Value: "length(var.resource_subnet_ids) > 0 ? var.resource_subnet_ids : [aws_subnet.public.id]"},
+			{Key: "allowed_security_group_ids", Value: "[aws_security_group.app.id]"},
+		},
+	}
+	if blueprint.VendoredModule() {
+		data.Source = "./" + path.Clean(blueprint.TerraformModule)
+	} else {
+		data.Version = blueprint.TerraformModuleVersion
+	}
+
+	values := resources.TemplateData(resource.Version, resource.Config)
+	for _, param := range blueprint.Parameters {
+		input := resources.ModuleInput(param.Name)
+		value, found := values[param.Name]
+		if !found && param.Default != nil {
+			value, found = param.Default, true
+		}
+		secret := strings.Contains(strings.ToLower(param.Name), "password")
+		if !found || secret {
+			variable := name + "_" + input
+			data.Inputs = append(data.Inputs, tag{Key: input, Value: "var." + variable})
+			data.Variables = append(data.Variables, moduleVariable{
+				Name:        variable,
+				Description: fmt.Sprintf("%s of %s", param.Description, entry),
+				Sensitive:   secret,
+			})
+			continue
+		}
+		text := fmt.Sprint(value)
+		if _, err := strconv.Atoi(text); err == nil && param.Type == "number" {
+			data.Inputs = append(data.Inputs, tag{Key: input, Value: text})
+		} else {
+			data.Inputs = append(data.Inputs, tag{Key: input, Value: fmt.Sprintf("%q", text)})
+		}
+	}
+	return data
+}
+
+// moduleName turns a name into a Terraform identifier by replacing every
+// character other than letters, digits, -, and _ with _
+func moduleName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// render executes the named template
+func render(name string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// containerEnvironment returns the environment of a service's task
+// definition: NODE_ENV plus every variable whose value is fully resolved.
+// Variables that still reference other parts of the project are left out,
+// as the prototype has no service discovery to resolve them.
+func containerEnvironment(service manifestPkg.Service) []tag {
+	environment := map[string]string{"NODE_ENV": "production"}
+	for key, value := range service.Environment {
+		if !strings.Contains(value, "${") {
+			environment[key] = value
+		}
+	}
+	return tagList(environment)
+}
+
+// deploymentCircuitBreaker maps a restart policy onto an ECS service. ECS
+// always replaces stopped tasks, so the policy can only decide what happens
+// when a deployment keeps failing: on-failure rolls back to the last working
+// task definition and no stops the deployment instead of retrying it. It
+// reports whether the service needs a circuit breaker, and whether it rolls
+// back.
+func deploymentCircuitBreaker(restart string) (enabled, rollback bool) {
+	switch restart {
+	case manifestPkg.RestartOnFailure:
+		return true, true
+	case manifestPkg.RestartNo:
+		return true, false
+	}
+	return false, false
+}
+
+// entryTags returns the tags of the resources generated from one entry that
+// the provider's default tags do not already set, including who owns it
+func entryTags(info labels.Info, entry, service, template string, ownership manifestPkg.Ownership) map[string]string {
+	tags := info.For(entry, service, template)
+	for key := range info.Common() {
+		delete(tags, key)
+	}
+	labels.AddOwnership(tags, ownership.Owner, ownership.Team, ownership.Tags)
+	return tags
+}
+
+// tagList returns a map as tags in key order
+func tagList(values map[string]string) []tag {
+	tags := make([]tag, 0, len(values))
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		tags = append(tags, tag{Key: key, Value: values[key]})
+	}
+	return tags
+}
+
+// hclList renders strings as an HCL list literal
+func hclList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", value))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
@@ -0,0 +1,90 @@
+// Package help provides the long-form topic guides shown by `om help <topic>`.
+// Guides are written in markdown, embedded into the binary, and rendered for
+// the terminal so users can learn the manifest and template formats offline.
+package help
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed topics/*.md
+var topicsFS embed.FS
+
+// Topic represents a single embedded help guide.
+type Topic struct {
+	Name    string // Topic identifier used on the command line (e.g. "manifest")
+	Title   string // Title taken from the first markdown heading
+	Summary string // One-line summary taken from the first paragraph
+	Body    string // Raw markdown content
+}
+
+// Topics returns all embedded help topics sorted by name.
+func Topics() []Topic {
+	entries, err := fs.ReadDir(topicsFS, "topics")
+	if err != nil {
+		return nil
+	}
+
+	var topics []Topic
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		topic, err := load(strings.TrimSuffix(entry.Name(), ".md"))
+		if err != nil {
+			continue
+		}
+		topics = append(topics, topic)
+	}
+
+	sort.Slice(topics, func(i, j int) bool {
+		return topics[i].Name < topics[j].Name
+	})
+
+	return topics
+}
+
+// Lookup returns the help topic with the given name.
+func Lookup(name string) (Topic, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || strings.ContainsAny(name, `/\.`) {
+		return Topic{}, false
+	}
+
+	topic, err := load(name)
+	if err != nil {
+		return Topic{}, false
+	}
+	return topic, true
+}
+
+// load reads a topic file and extracts its title and summary
+func load(name string) (Topic, error) {
+	data, err := fs.ReadFile(topicsFS, path.Join("topics", name+".md"))
+	if err != nil {
+		return Topic{}, fmt.Errorf("help topic '%s' not found: %w", name, err)
+	}
+
+	topic := Topic{Name: name, Body: string(data)}
+	for _, line := range strings.Split(topic.Body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			continue
+		case topic.Title == "" && strings.HasPrefix(trimmed, "# "):
+			topic.Title = strings.TrimPrefix(trimmed, "# ")
+		case topic.Title != "" && topic.Summary == "" && !strings.HasPrefix(trimmed, "#"):
+			topic.Summary = trimmed
+		}
+		if topic.Title != "" && topic.Summary != "" {
+			break
+		}
+	}
+
+	return topic, nil
+}
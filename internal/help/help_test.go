@@ -0,0 +1,64 @@
+package help
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTopics(t *testing.T) {
+	topics := Topics()
+	if len(topics) == 0 {
+		t.Fatal("expected embedded help topics")
+	}
+
+	for _, topic := range topics {
+		if topic.Title == "" {
+			t.Errorf("topic %q has no title", topic.Name)
+		}
+		if topic.Summary == "" {
+			t.Errorf("topic %q has no summary", topic.Name)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name  string
+		found bool
+	}{
+		{name: "manifest", found: true},
+		{name: "templates", found: true},
+		{name: "deployment", found: true},
+		{name: "unknown", found: false},
+		{name: "../help", found: false},
+		{name: "", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := Lookup(tt.name)
+			if ok != tt.found {
+				t.Errorf("Lookup(%q) found = %v, want %v", tt.name, ok, tt.found)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	markdown := "# Title\n\nUse `om compose` and **read** [docs](https://example.com).\n\n- item\n\n```yaml\nkey: value\n```\n"
+
+	plain := Render(markdown, false)
+	if strings.Contains(plain, "\033[") {
+		t.Error("plain output should not contain ANSI escape codes")
+	}
+	for _, want := range []string{"TITLE", "Use om compose and read docs (https://example.com).", "• item", "    key: value"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("plain output missing %q:\n%s", want, plain)
+		}
+	}
+
+	colored := Render(markdown, true)
+	if !strings.Contains(colored, ansiReset) {
+		t.Error("colored output should contain ANSI escape codes")
+	}
+}
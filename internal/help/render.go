@@ -0,0 +1,90 @@
+package help
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ANSI escape sequences used by the terminal renderer
+const (
+	ansiReset     = "\033[0m"
+	ansiBold      = "\033[1m"
+	ansiDim       = "\033[2m"
+	ansiUnderline = "\033[4m"
+	ansiCyan      = "\033[36m"
+	ansiYellow    = "\033[33m"
+	ansiGreen     = "\033[32m"
+)
+
+var (
+	boldPattern = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	codePattern = regexp.MustCompile("`([^`]+)`")
+	linkPattern = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+)
+
+// Render converts markdown into terminal-friendly text. When color is true,
+// headings, inline code, emphasis, and links are highlighted with ANSI codes;
+// otherwise only the markdown syntax is simplified for plain output.
+func Render(markdown string, color bool) string {
+	var out strings.Builder
+	inCodeBlock := false
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		// Fenced code blocks are indented and dimmed, never reformatted
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			out.WriteString("    " + style(line, ansiGreen, color) + "\n")
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "# "):
+			title := strings.TrimPrefix(trimmed, "# ")
+			out.WriteString(style(strings.ToUpper(title), ansiBold+ansiCyan, color) + "\n")
+			out.WriteString(style(strings.Repeat("=", len(title)), ansiCyan, color) + "\n")
+		case strings.HasPrefix(trimmed, "## "):
+			title := strings.TrimPrefix(trimmed, "## ")
+			out.WriteString(style(title, ansiBold+ansiCyan, color) + "\n")
+			out.WriteString(style(strings.Repeat("-", len(title)), ansiCyan, color) + "\n")
+		case strings.HasPrefix(trimmed, "### "):
+			out.WriteString(style(strings.TrimPrefix(trimmed, "### "), ansiBold, color) + "\n")
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+			out.WriteString("  " + indent + "• " + renderInline(trimmed[2:], color) + "\n")
+		case strings.HasPrefix(trimmed, "> "):
+			out.WriteString("  " + style("│ "+renderInline(trimmed[2:], color), ansiDim, color) + "\n")
+		default:
+			out.WriteString(renderInline(line, color) + "\n")
+		}
+	}
+
+	return strings.TrimRight(out.String(), "\n") + "\n"
+}
+
+// renderInline applies inline markdown formatting (bold, code, links)
+func renderInline(text string, color bool) string {
+	text = linkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := linkPattern.FindStringSubmatch(match)
+		return parts[1] + " (" + style(parts[2], ansiUnderline, color) + ")"
+	})
+	text = codePattern.ReplaceAllStringFunc(text, func(match string) string {
+		return style(strings.Trim(match, "`"), ansiYellow, color)
+	})
+	text = boldPattern.ReplaceAllStringFunc(text, func(match string) string {
+		return style(strings.Trim(match, "*"), ansiBold, color)
+	})
+	return text
+}
+
+// style wraps text in the given ANSI codes when color is enabled
+func style(text, codes string, color bool) string {
+	if !color || text == "" {
+		return text
+	}
+	return codes + text + ansiReset
+}
@@ -0,0 +1,300 @@
+// Package hooks notifies user-defined commands and webhooks when om finishes
+// something, such as scaffolding a service or generating compose files.
+// Hooks are declared in the user's ~/.om/config.yaml and in a project's
+// .om/hooks.yaml; each receives the event as environment variables and as a
+// JSON document. The package also runs the lifecycle hooks a project declares
+// in workbench.yaml. Hooks a project declares, in either file, only run once
+// the user approved them, and their commands run in a reduced environment.
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/debuglog"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// Events hooks can subscribe to
+const (
+	EventScaffoldComplete = "scaffold-complete" // om init, om add service, om add component
+	EventComposeGenerated = "compose-generated" // om compose wrote new configuration
+)
+
+// Events lists every event, in the order they are documented
+var Events = []string{EventScaffoldComplete, EventComposeGenerated}
+
+// ProjectFileName is the location of a project's hooks relative to its root
+var ProjectFileName = filepath.Join(".om", "hooks.yaml")
+
+// timeout bounds every hook, so a hung script or endpoint cannot hang om
+const timeout = 30 * time.Second
+
+// Hook runs a command or calls a webhook when one of its events happens
+type Hook struct {
+	Events  []string `yaml:"events"`            // events to run on; "*" matches every event
+	Command string   `yaml:"command,omitempty"` // shell command, run in the project root
+	Webhook string   `yaml:"webhook,omitempty"` // URL the event is POSTed to as JSON
+
+	// Project is set for hooks of the project's .om/hooks.yaml, which may
+	// have been written by someone else, rather than the user's configuration
+	Project bool `yaml:"-"`
+}
+
+// Config is the hooks section of ~/.om/config.yaml or .om/hooks.yaml
+type Config struct {
+	Hooks []Hook `yaml:"hooks"`
+}
+
+// Event is something om finished. Data holds event-specific details, such as
+// the name and template of a scaffolded service.
+type Event struct {
+	Name        string            `json:"event"`
+	Project     string            `json:"project,omitempty"`
+	ProjectRoot string            `json:"projectRoot"`
+	Time        time.Time         `json:"time"`
+	Data        map[string]string `json:"data,omitempty"`
+}
+
+// Load reads the hooks in configPath, when it exists, followed by those of
+// the project at projectRoot, which are marked as Project. Either file may be
+// missing.
+func Load(fsys filesystem.FS, configPath, projectRoot string) (*Config, error) {
+	merged := &Config{}
+	projectPath := filepath.Join(projectRoot, ProjectFileName)
+	for _, path := range []string{configPath, projectPath} {
+		if path == "" || !filesystem.Exists(fsys, path) {
+			continue
+		}
+		data, err := fsys.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var config Config
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if err := config.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", path, err)
+		}
+		for _, hook := range config.Hooks {
+			hook.Project = path == projectPath
+			merged.Hooks = append(merged.Hooks, hook)
+		}
+	}
+	return merged, nil
+}
+
+// ProjectActions returns what the project's hooks do on the named event, the
+// actions a user has to approve before they happen: each command, and each
+// webhook as "POST <url>", since the event it receives names the project and
+// its location on disk
+func (c *Config) ProjectActions(event string) []string {
+	var actions []string
+	for _, hook := range c.Hooks {
+		if !hook.Project || !hook.Matches(event) {
+			continue
+		}
+		if hook.Command != "" {
+			actions = append(actions, hook.Command)
+		} else {
+			actions = append(actions, "POST "+hook.Webhook)
+		}
+	}
+	return actions
+}
+
+// WithoutProjectHooks returns the configuration without the project's hooks,
+// for when the user did not approve them
+func (c *Config) WithoutProjectHooks() *Config {
+	kept := &Config{}
+	for _, hook := range c.Hooks {
+		if !hook.Project {
+			kept.Hooks = append(kept.Hooks, hook)
+		}
+	}
+	return kept
+}
+
+// Validate checks that every hook names known events and exactly one action
+func (c *Config) Validate() error {
+	known := make(map[string]bool, len(Events))
+	for _, event := range Events {
+		known[event] = true
+	}
+	for i, hook := range c.Hooks {
+		if len(hook.Events) == 0 {
+			return fmt.Errorf("hooks[%d]: events are required (%s, or * for all)", i, strings.Join(Events, ", "))
+		}
+		for _, event := range hook.Events {
+			if event != "*" && !known[event] {
+				return fmt.Errorf("hooks[%d]: unknown event '%s' (use %s, or * for all)", i, event, strings.Join(Events, ", "))
+			}
+		}
+		if (hook.Command == "") == (hook.Webhook == "") {
+			return fmt.Errorf("hooks[%d]: set either command or webhook", i)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the hook runs on the named event
+func (h Hook) Matches(event string) bool {
+	for _, subscribed := range h.Events {
+		if subscribed == "*" || subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe returns the command or webhook of the hook, for messages
+func (h Hook) Describe() string {
+	if h.Command != "" {
+		return h.Command
+	}
+	return h.Webhook
+}
+
+// Runner runs a shell command in dir with extra environment variables and
+// the event JSON on its standard input
+type Runner func(dir, command string, env []string, stdin []byte) error
+
+// Poster sends the event JSON to a webhook URL
+type Poster func(url string, body []byte) error
+
+// Dispatcher runs the hooks of a configuration. Commands of the user's
+// configuration go to Run, those of the project to RunProject.
+type Dispatcher struct {
+	Run        Runner
+	RunProject Runner
+	Post       Poster
+}
+
+// NewDispatcher returns a dispatcher that runs the user's commands in the
+// host shell, the project's commands in a reduced environment, and calls
+// webhooks over HTTP
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{Run: ShellRunner, RunProject: SandboxRunner, Post: HTTPPoster}
+}
+
+// Dispatch runs every hook of config that matches the event. A failing hook
+// does not stop the others; their errors are returned together, one per hook.
+func (d *Dispatcher) Dispatch(config *Config, event Event) []error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return []error{fmt.Errorf("failed to encode %s event: %w", event.Name, err)}
+	}
+	env := Environment(event, body)
+
+	var failures []error
+	for _, hook := range config.Hooks {
+		if !hook.Matches(event.Name) {
+			continue
+		}
+		debuglog.Printf("running %s hook: %s", event.Name, hook.Describe())
+		if hook.Command != "" && hook.Project {
+			err = d.RunProject(event.ProjectRoot, hook.Command, env, body)
+		} else if hook.Command != "" {
+			err = d.Run(event.ProjectRoot, hook.Command, env, body)
+		} else {
+			err = d.Post(hook.Webhook, body)
+		}
+		if err != nil {
+			failures = append(failures, fmt.Errorf("hook '%s' failed: %w", hook.Describe(), err))
+		}
+	}
+	return failures
+}
+
+// Environment returns the variables a command hook receives: OM_EVENT,
+// OM_PROJECT, OM_PROJECT_ROOT, OM_EVENT_JSON, and one OM_<KEY> variable per
+// data entry, e.g. OM_SERVICE for "service"
+func Environment(event Event, body []byte) []string {
+	env := []string{
+		"OM_EVENT=" + event.Name,
+		"OM_PROJECT=" + event.Project,
+		"OM_PROJECT_ROOT=" + event.ProjectRoot,
+		"OM_EVENT_JSON=" + string(body),
+	}
+	keys := make([]string, 0, len(event.Data))
+	for key := range event.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+		env = append(env, "OM_"+name+"="+event.Data[key])
+	}
+	return env
+}
+
+// ShellRunner runs command with sh -c, or cmd /C on Windows, in the user's
+// environment extended with env
+func ShellRunner(dir, command string, env []string, stdin []byte) error {
+	return runShell(dir, command, append(os.Environ(), env...), stdin)
+}
+
+// runShell runs command through the host shell with exactly the variables in
+// env, stopping it after the hook timeout
+func runShell(dir, command string, env []string, stdin []byte) error {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.Command(shell, flag, command)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdin = bytes.NewReader(stdin)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			if message := strings.TrimSpace(output.String()); message != "" {
+				return fmt.Errorf("%w: %s", err, message)
+			}
+			return err
+		}
+		return nil
+	case <-time.After(timeout):
+		_ = cmd.Process.Kill()
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+}
+
+// HTTPPoster POSTs body to url as JSON and fails on non-2xx responses
+func HTTPPoster(url string, body []byte) error {
+	client := &http.Client{Timeout: timeout}
+	response, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		var urlErr interface{ Timeout() bool }
+		if errors.As(err, &urlErr) && urlErr.Timeout() {
+			return fmt.Errorf("timed out after %s", timeout)
+		}
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", response.Status)
+	}
+	return nil
+}
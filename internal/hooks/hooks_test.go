@@ -0,0 +1,170 @@
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	config, err := Load(fsys, filepath.Join("home", ".om", "config.yaml"), "demo")
+	if err != nil {
+		t.Fatalf("Load without configuration failed: %v", err)
+	}
+	if len(config.Hooks) != 0 {
+		t.Errorf("expected no hooks, got %v", config.Hooks)
+	}
+
+	user := "hooks:\n  - events: [\"*\"]\n    webhook: https://hooks.example.com/om\n"
+	project := "hooks:\n  - events: [compose-generated]\n    command: ./notify.sh\n  - events: [\"*\"]\n    webhook: https://example.com/collect\n"
+	for _, dir := range []string{filepath.Join("home", ".om"), filepath.Join("demo", ".om")} {
+		if err := fsys.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fsys.WriteFile(filepath.Join("home", ".om", "config.yaml"), []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(filepath.Join("demo", ProjectFileName), []byte(project), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err = Load(fsys, filepath.Join("home", ".om", "config.yaml"), "demo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(config.Hooks) != 3 || config.Hooks[0].Webhook == "" || config.Hooks[1].Command != "./notify.sh" {
+		t.Errorf("expected the user's hooks followed by the project's, got %+v", config.Hooks)
+	}
+	if config.Hooks[0].Project || !config.Hooks[1].Project || !config.Hooks[2].Project {
+		t.Errorf("expected only the project's hooks to be marked as such, got %+v", config.Hooks)
+	}
+	if actions := config.ProjectActions(EventComposeGenerated); len(actions) != 2 || actions[0] != "./notify.sh" || actions[1] != "POST https://example.com/collect" {
+		t.Errorf("expected the project's command and webhook to need approval, got %v", actions)
+	}
+	if actions := config.ProjectActions(EventScaffoldComplete); len(actions) != 1 || actions[0] != "POST https://example.com/collect" {
+		t.Errorf("expected only the project's webhook on other events, got %v", actions)
+	}
+	if kept := config.WithoutProjectHooks(); len(kept.Hooks) != 1 || kept.Hooks[0].Webhook != "https://hooks.example.com/om" {
+		t.Errorf("expected only the user's webhook without the project's hooks, got %+v", kept.Hooks)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		hook Hook
+		want string
+	}{
+		"no events":     {Hook{Command: "true"}, "events are required"},
+		"unknown event": {Hook{Events: []string{"deployed"}, Command: "true"}, "unknown event 'deployed'"},
+		"no action":     {Hook{Events: []string{"*"}}, "set either command or webhook"},
+		"both actions":  {Hook{Events: []string{"*"}, Command: "true", Webhook: "https://example.com"}, "set either command or webhook"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := (&Config{Hooks: []Hook{tt.hook}}).Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	config := &Config{Hooks: []Hook{
+		{Events: []string{EventComposeGenerated}, Command: "./never.sh"},
+		{Events: []string{EventScaffoldComplete}, Command: "./notify.sh"},
+		{Events: []string{"*"}, Command: "./broken.sh"},
+		{Events: []string{"*"}, Webhook: "https://hooks.example.com/om"},
+		{Events: []string{"*"}, Command: "./project.sh", Project: true},
+	}}
+	event := Event{
+		Name:        EventScaffoldComplete,
+		Project:     "demo",
+		ProjectRoot: "/work/demo",
+		Time:        time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC),
+		Data:        map[string]string{"kind": "service", "name": "api"},
+	}
+
+	var commands, sandboxed, urls []string
+	var env []string
+	var stdin, posted []byte
+	dispatcher := &Dispatcher{
+		Run: func(dir, command string, e []string, input []byte) error {
+			if dir != "/work/demo" {
+				t.Errorf("expected hooks to run in the project root, got %s", dir)
+			}
+			commands = append(commands, command)
+			if command == "./broken.sh" {
+				return errors.New("exit status 1")
+			}
+			env, stdin = e, input
+			return nil
+		},
+		RunProject: func(dir, command string, e []string, input []byte) error {
+			sandboxed = append(sandboxed, command)
+			return nil
+		},
+		Post: func(url string, body []byte) error {
+			urls = append(urls, url)
+			posted = body
+			return nil
+		},
+	}
+
+	failures := dispatcher.Dispatch(config, event)
+	if strings.Join(commands, ",") != "./notify.sh,./broken.sh" || len(urls) != 1 {
+		t.Errorf("expected the matching hooks to run, got commands %v and webhooks %v", commands, urls)
+	}
+	if strings.Join(sandboxed, ",") != "./project.sh" {
+		t.Errorf("expected the project's command to run sandboxed, got %v", sandboxed)
+	}
+	if len(failures) != 1 || !strings.Contains(failures[0].Error(), "hook './broken.sh' failed: exit status 1") {
+		t.Errorf("expected the failing hook to be reported without stopping the others, got %v", failures)
+	}
+
+	for _, want := range []string{"OM_EVENT=scaffold-complete", "OM_PROJECT=demo", "OM_PROJECT_ROOT=/work/demo", "OM_KIND=service", "OM_NAME=api"} {
+		if !strings.Contains(strings.Join(env, "\n"), want) {
+			t.Errorf("expected %s in the hook environment %v", want, env)
+		}
+	}
+	var decoded Event
+	if err := json.Unmarshal(stdin, &decoded); err != nil {
+		t.Fatalf("expected the event as JSON on stdin: %v", err)
+	}
+	if decoded.Name != EventScaffoldComplete || decoded.Data["name"] != "api" || string(posted) != string(stdin) {
+		t.Errorf("unexpected event payload %s", stdin)
+	}
+}
+
+func TestHTTPPoster(t *testing.T) {
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected a JSON body, got %s", r.Header.Get("Content-Type"))
+		}
+		received, _ = io.ReadAll(r.Body)
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	if err := HTTPPoster(server.URL+"/ok", []byte(`{"event":"compose-generated"}`)); err != nil {
+		t.Fatalf("HTTPPoster failed: %v", err)
+	}
+	if string(received) != `{"event":"compose-generated"}` {
+		t.Errorf("unexpected body %s", received)
+	}
+	if err := HTTPPoster(server.URL+"/broken", []byte(`{}`)); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("expected a non-2xx answer to fail, got %v", err)
+	}
+}
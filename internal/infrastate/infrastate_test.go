@@ -0,0 +1,95 @@
+package infrastate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+const terraformOutput = `{
+  "alb_dns_name": {"sensitive": false, "type": "string", "value": "demo-alb-123.eu-west-1.elb.amazonaws.com"},
+  "db_password": {"sensitive": true, "type": "string", "value": "hunter2"},
+  "replicas": {"sensitive": false, "type": "number", "value": 3},
+  "subnets": {"sensitive": false, "type": ["tuple", ["string", "string"]], "value": ["subnet-a", "subnet-b"]},
+  "unused_lb": {"sensitive": false, "type": "string", "value": null}
+}`
+
+func TestOutputs(t *testing.T) {
+	run := func(dir, name string, args ...string) ([]byte, error) {
+		if dir != "terraform" || name != "terraform" || !reflect.DeepEqual(args, []string{"output", "-json"}) {
+			t.Errorf("unexpected command %s %v in %s", name, args, dir)
+		}
+		return []byte(terraformOutput), nil
+	}
+	outputs, sensitive, err := Outputs(run, "terraform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"alb_dns_name": "demo-alb-123.eu-west-1.elb.amazonaws.com",
+		"replicas":     "3",
+		"subnets":      `["subnet-a","subnet-b"]`,
+	}
+	if !reflect.DeepEqual(outputs, want) {
+		t.Errorf("Outputs() = %v, want %v", outputs, want)
+	}
+	if !reflect.DeepEqual(sensitive, []string{"db_password"}) {
+		t.Errorf("expected db_password to be reported as sensitive, got %v", sensitive)
+	}
+
+	failing := func(dir, name string, args ...string) ([]byte, error) { return nil, errors.New("no state file") }
+	if _, _, err := Outputs(failing, "terraform"); err == nil {
+		t.Error("expected an error when terraform fails")
+	}
+	garbled := func(dir, name string, args ...string) ([]byte, error) { return []byte("Warning: no outputs"), nil }
+	if _, _, err := Outputs(garbled, "terraform"); err == nil {
+		t.Error("expected an error for output that is not JSON")
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	if _, err := Load(fsys, "project", "prod"); !errors.Is(err, ErrNoState) {
+		t.Fatalf("expected ErrNoState before anything is saved, got %v", err)
+	}
+
+	state := &State{
+		Environment: "prod",
+		UpdatedAt:   time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC),
+		Outputs:     map[string]string{"alb_dns_name": "demo-alb.elb.example"},
+	}
+	if err := Save(fsys, "project", state); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.ReadFile("project/.om/state/prod.json"); err != nil {
+		t.Errorf("expected the state under .om/state: %v", err)
+	}
+	loaded, err := Load(fsys, "project", "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, state) {
+		t.Errorf("Load() = %+v, want %+v", loaded, state)
+	}
+}
+
+func TestState_Resolve(t *testing.T) {
+	state := &State{Outputs: map[string]string{"alb_dns_name": "demo-alb.elb.example", "assets_bucket": "demo-assets"}}
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"https://${infra.alb_dns_name}/api", "https://demo-alb.elb.example/api"},
+		{"s3://${infra.assets_bucket}/${infra.assets_bucket}", "s3://demo-assets/demo-assets"},
+		{"${infra.db_endpoint}:5432", "${infra.db_endpoint}:5432"},
+		{"${services.api.url}", "${services.api.url}"},
+	}
+	for _, tt := range tests {
+		if got := state.Resolve(tt.value); got != tt.want {
+			t.Errorf("Resolve(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Package labels names the labels and tags om puts on everything it
+// generates, so that containers, images, volumes, and cloud resources can be
+// traced back to the workbench project, entry, and om version behind them.
+// Docker Compose writes them as labels and Terraform as AWS tags, under the
+// same keys.
+package labels
+
+import (
+	"sort"
+	"strings"
+)
+
+// Label keys
+const (
+	Project     = "com.openworkbench.project"     // metadata.name of the project
+	Entry       = "com.openworkbench.entry"       // workbench.yaml entry, e.g. services.api.resources.db
+	Service     = "com.openworkbench.service"     // service or component the artifact belongs to
+	Template    = "com.openworkbench.template"    // template the service or component was created from
+	Version     = "com.openworkbench.version"     // version of om that generated the artifact
+	Environment = "com.openworkbench.environment" // environment generated for, when one was selected
+	Owner       = "com.openworkbench.owner"       // owner of the service or component
+	Team        = "com.openworkbench.team"        // team of the service or component
+	Tags        = "com.openworkbench.tags"        // tags of the service or component, separated by spaces
+)
+
+// Info is what every artifact of a generation run is attributed to
+type Info struct {
+	Project     string
+	Version     string
+	Environment string
+
+	// Revision of workbench.yaml the run generates from. Generators record
+	// it in file headers rather than as a label, so that editing the
+	// manifest does not change every container.
+	Revision string
+}
+
+// Common returns the labels shared by every artifact of the run, without the
+// empty ones. It returns nil when the project has no name.
+func (i Info) Common() map[string]string {
+	if i.Project == "" {
+		return nil
+	}
+	labels := map[string]string{Project: i.Project}
+	add(labels, Version, i.Version)
+	add(labels, Environment, i.Environment)
+	return labels
+}
+
+// For returns the labels of an artifact generated from entry, belonging to
+// the named service or component and created from template. Empty values
+// are left out; it returns nil when the project has no name.
+func (i Info) For(entry, service, template string) map[string]string {
+	labels := i.Common()
+	if labels == nil {
+		return nil
+	}
+	add(labels, Entry, entry)
+	add(labels, Service, service)
+	add(labels, Template, template)
+	return labels
+}
+
+// AddOwnership adds the owner, team, and tags of the service or component an
+// artifact belongs to, leaving out the empty ones. Tags are joined with
+// spaces, which both Docker labels and AWS tags accept.
+func AddOwnership(labels map[string]string, owner, team string, tags []string) {
+	if labels == nil {
+		return
+	}
+	add(labels, Owner, owner)
+	add(labels, Team, team)
+	add(labels, Tags, strings.Join(tags, " "))
+}
+
+// List returns labels as key=value entries sorted by key
+func List(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	list := make([]string, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, key+"="+labels[key])
+	}
+	return list
+}
+
+// add sets key to value unless value is empty
+func add(labels map[string]string, key, value string) {
+	if value != "" {
+		labels[key] = value
+	}
+}
@@ -0,0 +1,42 @@
+package labels
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFor(t *testing.T) {
+	info := Info{Project: "demo", Version: "1.2.0"}
+	got := List(info.For("services.api", "api", "fastapi-basic"))
+	want := []string{
+		"com.openworkbench.entry=services.api",
+		"com.openworkbench.project=demo",
+		"com.openworkbench.service=api",
+		"com.openworkbench.template=fastapi-basic",
+		"com.openworkbench.version=1.2.0",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	info.Environment = "prod"
+	if labels := info.Common(); labels[Environment] != "prod" || labels[Entry] != "" {
+		t.Errorf("expected the environment without an entry, got %v", labels)
+	}
+
+	if labels := (Info{Version: "1.2.0"}).For("services.api", "api", ""); labels != nil {
+		t.Errorf("expected no labels without a project, got %v", labels)
+	}
+}
+
+func TestAddOwnership(t *testing.T) {
+	labels := Info{Project: "demo"}.For("services.api", "api", "")
+	AddOwnership(labels, "jane@example.com", "", []string{"pci", "tier:1"})
+	if labels[Owner] != "jane@example.com" || labels[Tags] != "pci tier:1" {
+		t.Errorf("expected the owner and tags, got %v", labels)
+	}
+	if _, exists := labels[Team]; exists {
+		t.Errorf("expected no team label without a team, got %v", labels)
+	}
+	AddOwnership(nil, "jane@example.com", "payments", nil)
+}
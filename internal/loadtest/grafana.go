@@ -0,0 +1,59 @@
+package loadtest
+
+// grafanaDatasource points Grafana at the InfluxDB database k6 writes to
+const grafanaDatasource = `apiVersion: 1
+datasources:
+  - name: k6
+    type: influxdb
+    access: proxy
+    url: http://influxdb:8086
+    database: k6
+    isDefault: true
+`
+
+// grafanaDashboardProvider loads the dashboards mounted into the container
+const grafanaDashboardProvider = `apiVersion: 1
+providers:
+  - name: k6
+    folder: Load tests
+    type: file
+    options:
+      path: /var/lib/grafana/dashboards
+`
+
+// grafanaDashboard charts the request rate, latency, errors, and virtual users
+// of every k6 run
+const grafanaDashboard = `{
+  "title": "k6 Load Tests",
+  "uid": "om-k6",
+  "refresh": "5s",
+  "time": { "from": "now-15m", "to": "now" },
+  "panels": [
+    {
+      "title": "Requests per second",
+      "type": "timeseries",
+      "gridPos": { "h": 8, "w": 12, "x": 0, "y": 0 },
+      "targets": [{ "query": "SELECT sum(\"value\") FROM \"http_reqs\" WHERE $timeFilter GROUP BY time(1s)", "rawQuery": true }]
+    },
+    {
+      "title": "Request duration p95 (ms)",
+      "type": "timeseries",
+      "gridPos": { "h": 8, "w": 12, "x": 12, "y": 0 },
+      "targets": [{ "query": "SELECT percentile(\"value\", 95) FROM \"http_req_duration\" WHERE $timeFilter GROUP BY time(1s)", "rawQuery": true }]
+    },
+    {
+      "title": "Failed requests",
+      "type": "timeseries",
+      "gridPos": { "h": 8, "w": 12, "x": 0, "y": 8 },
+      "targets": [{ "query": "SELECT sum(\"value\") FROM \"http_req_failed\" WHERE $timeFilter GROUP BY time(1s)", "rawQuery": true }]
+    },
+    {
+      "title": "Virtual users",
+      "type": "timeseries",
+      "gridPos": { "h": 8, "w": 12, "x": 12, "y": 8 },
+      "targets": [{ "query": "SELECT max(\"value\") FROM \"vus\" WHERE $timeFilter GROUP BY time(1s)", "rawQuery": true }]
+    }
+  ],
+  "schemaVersion": 39
+}
+`
@@ -0,0 +1,158 @@
+// Package loadtest generates k6 smoke tests for the HTTP services of a
+// project, together with a Docker Compose overlay that runs them and charts
+// the results in Grafana.
+package loadtest
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"gopkg.in/yaml.v3"
+)
+
+// ComposeFile is the Docker Compose overlay that adds the load generators to
+// the project's docker-compose.yml
+const ComposeFile = "docker-compose.loadtest.yml"
+
+// ScriptDir holds the generated k6 scripts and Grafana provisioning
+const ScriptDir = "loadtest"
+
+// Profile is the Docker Compose profile of every load testing container, so
+// they only start when asked for
+const Profile = "loadtest"
+
+const (
+	k6Image       = "grafana/k6:0.52.0"
+	influxDBImage = "influxdb:1.8"
+	grafanaImage  = "grafana/grafana:11.1.0"
+	grafanaPort   = 3030
+)
+
+// Services returns the sorted names of the services that can be load tested:
+// containerised HTTP services with a port
+func Services(m *manifest.WorkbenchManifest) []string {
+	var names []string
+	for name, service := range m.Services {
+		if service.IsContainer() && service.ProtocolOrDefault() == manifest.ProtocolHTTP && service.Port > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Generate returns the load testing files for the named services, keyed by
+// path relative to the project root. Every name must be a service returned
+// by Services.
+func Generate(m *manifest.WorkbenchManifest, names []string) (map[string][]byte, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no HTTP services to load test")
+	}
+
+	testable := make(map[string]bool)
+	for _, name := range Services(m) {
+		testable[name] = true
+	}
+
+	files := map[string][]byte{
+		ScriptDir + "/grafana/provisioning/datasources/influxdb.yaml": []byte(grafanaDatasource),
+		ScriptDir + "/grafana/provisioning/dashboards/k6.yaml":        []byte(grafanaDashboardProvider),
+		ScriptDir + "/grafana/dashboards/k6.json":                     []byte(grafanaDashboard),
+	}
+
+	config := &compose.DockerComposeConfig{
+		Services: map[string]compose.DockerComposeService{
+			"influxdb": {
+				Image:       influxDBImage,
+				Environment: []string{"INFLUXDB_DB=k6"},
+				Networks:    []string{"workbench_net"},
+				Profiles:    []string{Profile},
+			},
+			"grafana": {
+				Image: grafanaImage,
+				Ports: []string{fmt.Sprintf("%d:3000", grafanaPort)},
+				Environment: []string{
+					"GF_AUTH_ANONYMOUS_ENABLED=true",
+					"GF_AUTH_ANONYMOUS_ORG_ROLE=Admin",
+				},
+				Volumes: []string{
+					"./" + ScriptDir + "/grafana/provisioning:/etc/grafana/provisioning:ro",
+					"./" + ScriptDir + "/grafana/dashboards:/var/lib/grafana/dashboards:ro",
+				},
+				Networks:  []string{"workbench_net"},
+				DependsOn: []string{"influxdb"},
+				Profiles:  []string{Profile},
+			},
+		},
+		Networks: map[string]interface{}{
+			"workbench_net": map[string]string{
+				"driver": "bridge",
+			},
+		},
+	}
+
+	for _, name := range names {
+		if !testable[name] {
+			return nil, fmt.Errorf("service '%s' is not an HTTP service with a port", name)
+		}
+		service := m.Services[name]
+		files[fmt.Sprintf("%s/%s.js", ScriptDir, name)] = []byte(script(name, service.Port))
+
+		config.Services[RunnerName(name)] = compose.DockerComposeService{
+			Image:     k6Image,
+			Command:   []string{"run", "--out", "influxdb=http://influxdb:8086/k6", fmt.Sprintf("/scripts/%s.js", name)},
+			Volumes:   []string{"./" + ScriptDir + ":/scripts:ro"},
+			Networks:  []string{"workbench_net"},
+			DependsOn: []string{"influxdb", name},
+			Profiles:  []string{Profile},
+		}
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", ComposeFile, err)
+	}
+	header := "# THIS FILE IS AUTO-GENERATED BY 'om generate loadtest'.\n" +
+		"# Use it together with docker-compose.yml:\n" +
+		"#   docker compose -f docker-compose.yml -f " + ComposeFile + " --profile " + Profile + " up\n\n"
+	files[ComposeFile] = append([]byte(header), data...)
+
+	return files, nil
+}
+
+// RunnerName returns the compose service that load tests the named service
+func RunnerName(service string) string {
+	return "loadtest-" + service
+}
+
+// script returns a k6 smoke test that ramps up a handful of virtual users
+// against the service's root path
+func script(name string, port int) string {
+	return fmt.Sprintf(`// THIS FILE IS AUTO-GENERATED BY 'om generate loadtest'. Edit freely; it is
+// only rewritten when the command is run again.
+import http from 'k6/http';
+import { check, sleep } from 'k6';
+
+const BASE_URL = __ENV.BASE_URL || 'http://%s:%d';
+
+export const options = {
+  stages: [
+    { duration: '30s', target: 10 },
+    { duration: '1m', target: 10 },
+    { duration: '15s', target: 0 },
+  ],
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    http_req_duration: ['p(95)<500'],
+  },
+};
+
+export default function () {
+  const res = http.get(`+"`${BASE_URL}/`"+`);
+  check(res, { 'status is not 5xx': (r) => r.status < 500 });
+  sleep(1);
+}
+`, name, port)
+}
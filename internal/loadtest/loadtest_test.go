@@ -0,0 +1,78 @@
+package loadtest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"gopkg.in/yaml.v3"
+)
+
+func loadTestManifest() *manifest.WorkbenchManifest {
+	return &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "demo"},
+		Services: map[string]manifest.Service{
+			"backend":  {Path: "backend", Port: 8000},
+			"frontend": {Path: "frontend", Port: 3000},
+			"orders":   {Path: "orders", Port: 50051, Protocol: manifest.ProtocolGRPC},
+			"worker":   {Path: "worker"},
+			"mobile":   {Path: "mobile", Port: 8081, Kind: manifest.ServiceKindLocal, Dev: "npx expo start"},
+		},
+	}
+}
+
+func TestServices(t *testing.T) {
+	got := Services(loadTestManifest())
+	if !reflect.DeepEqual(got, []string{"backend", "frontend"}) {
+		t.Errorf("expected only HTTP container services, got %v", got)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	files, err := Generate(loadTestManifest(), []string{"backend"})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	script, ok := files["loadtest/backend.js"]
+	if !ok {
+		t.Fatalf("expected a script for backend, got %v", files)
+	}
+	if !strings.Contains(string(script), "'http://backend:8000'") {
+		t.Errorf("script should target the service's compose address:\n%s", script)
+	}
+	if _, ok := files["loadtest/frontend.js"]; ok {
+		t.Error("only the selected services should get a script")
+	}
+
+	var config compose.DockerComposeConfig
+	if err := yaml.Unmarshal(files[ComposeFile], &config); err != nil {
+		t.Fatalf("overlay is not valid YAML: %v", err)
+	}
+	runner, ok := config.Services[RunnerName("backend")]
+	if !ok {
+		t.Fatalf("expected a k6 runner for backend, got %v", config.Services)
+	}
+	if !reflect.DeepEqual(runner.DependsOn, []string{"influxdb", "backend"}) {
+		t.Errorf("unexpected runner dependencies: %v", runner.DependsOn)
+	}
+	for name, service := range config.Services {
+		if !reflect.DeepEqual(service.Profiles, []string{Profile}) {
+			t.Errorf("%s should be in the %s profile, got %v", name, Profile, service.Profiles)
+		}
+	}
+}
+
+func TestGenerate_RejectsNonHTTPServices(t *testing.T) {
+	if _, err := Generate(loadTestManifest(), []string{"orders"}); err == nil {
+		t.Error("expected an error for a gRPC service")
+	}
+	if _, err := Generate(loadTestManifest(), []string{"missing"}); err == nil {
+		t.Error("expected an error for an unknown service")
+	}
+	if _, err := Generate(loadTestManifest(), nil); err == nil {
+		t.Error("expected an error when no services are selected")
+	}
+}
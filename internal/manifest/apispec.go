@@ -0,0 +1,85 @@
+package manifest
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// APISpecPath returns the path of the service's OpenAPI document relative to
+// the project root, or "" when the service declares none
+func (s Service) APISpecPath() string {
+	if s.API == nil || s.API.Spec == "" {
+		return ""
+	}
+	return filepath.Join(s.Path, s.API.Spec)
+}
+
+// ValidateAPISpecs checks that the OpenAPI document of every service and
+// external dependency exists under projectRoot and parses as an OpenAPI or
+// Swagger document. It returns one error per invalid spec, services first,
+// each ordered by name.
+func (m *WorkbenchManifest) ValidateAPISpecs(fsys filesystem.FS, projectRoot string) []error {
+	names := make([]string, 0, len(m.Services))
+	for name := range m.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []error
+	for _, name := range names {
+		specPath := m.Services[name].APISpecPath()
+		if specPath == "" {
+			continue
+		}
+		if err := validateAPISpec(fsys, filepath.Join(projectRoot, specPath)); err != nil {
+			problems = append(problems, NewValidationError(fmt.Sprintf("services.%s.api.spec", name),
+				fmt.Sprintf("%s: %v", specPath, err)))
+		}
+	}
+
+	externalNames := make([]string, 0, len(m.External))
+	for name := range m.External {
+		externalNames = append(externalNames, name)
+	}
+	sort.Strings(externalNames)
+
+	for _, name := range externalNames {
+		specPath := m.External[name].Spec
+		if specPath == "" {
+			continue
+		}
+		if err := validateAPISpec(fsys, filepath.Join(projectRoot, specPath)); err != nil {
+			problems = append(problems, NewValidationError(fmt.Sprintf("external.%s.spec", name),
+				fmt.Sprintf("%s: %v", specPath, err)))
+		}
+	}
+	return problems
+}
+
+// validateAPISpec reads an OpenAPI document in YAML or JSON and checks that it
+// declares an OpenAPI or Swagger version
+func validateAPISpec(fsys filesystem.FS, path string) error {
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		if !filesystem.Exists(fsys, path) {
+			return fmt.Errorf("file does not exist")
+		}
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var document struct {
+		OpenAPI string `yaml:"openapi"`
+		Swagger string `yaml:"swagger"`
+	}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("failed to parse: %w", err)
+	}
+	if document.OpenAPI == "" && document.Swagger == "" {
+		return fmt.Errorf("not an OpenAPI document (missing 'openapi' version)")
+	}
+	return nil
+}
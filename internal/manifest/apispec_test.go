@@ -0,0 +1,53 @@
+package manifest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestValidateAPISpecs(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	writeFiles(t, fsys, map[string]string{
+		filepath.Join("project", "users", "openapi.yaml"):   "openapi: 3.0.3\ninfo:\n  title: Users\n  version: 1.0.0\npaths: {}\n",
+		filepath.Join("project", "orders", "swagger.json"):  `{"swagger": "2.0", "paths": {}}`,
+		filepath.Join("project", "billing", "openapi.yaml"): "title: [",
+		filepath.Join("project", "search", "openapi.yaml"):  "info:\n  title: Search\n",
+	})
+
+	m := &WorkbenchManifest{
+		Metadata: ProjectMetadata{Name: "demo"},
+		Services: map[string]Service{
+			"users":    {Path: "users", API: &API{Spec: "openapi.yaml"}},
+			"orders":   {Path: "orders", API: &API{Spec: "swagger.json"}},
+			"billing":  {Path: "billing", API: &API{Spec: "openapi.yaml"}},
+			"search":   {Path: "search", API: &API{Spec: "openapi.yaml"}},
+			"payments": {Path: "payments", API: &API{Spec: "openapi.yaml"}},
+			"web":      {Path: "web"},
+		},
+		External: map[string]External{
+			"stripe": {URL: "https://api.stripe.com", Spec: "mocks/stripe.yaml"},
+			"mailer": {URL: "https://mail.example.com"},
+		},
+	}
+
+	problems := m.ValidateAPISpecs(fsys, "project")
+	if len(problems) != 4 {
+		t.Fatalf("expected 4 problems, got %v", problems)
+	}
+
+	expected := []struct{ field, message string }{
+		{"services.billing.api.spec", "failed to parse"},
+		{"services.payments.api.spec", "does not exist"},
+		{"services.search.api.spec", "not an OpenAPI document"},
+		{"external.stripe.spec", "does not exist"},
+	}
+	for i, want := range expected {
+		err := problems[i].(*ManifestError)
+		if err.Field != want.field || !strings.Contains(err.Error(), want.message) {
+			t.Errorf("problem %d: expected %s (%s), got %s", i, want.field, want.message, err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package manifest
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// ValidateBuildSources checks that every container service either runs a
+// prebuilt image or has a Dockerfile in its path under projectRoot. It
+// returns one error per service without either, ordered by name.
+func (m *WorkbenchManifest) ValidateBuildSources(fsys filesystem.FS, projectRoot string) []error {
+	names := make([]string, 0, len(m.Services))
+	for name := range m.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []error
+	for _, name := range names {
+		service := m.Services[name]
+		if !service.IsContainer() || service.UsesImage() {
+			continue
+		}
+		if service.Path == "" {
+			problems = append(problems, NewValidationError(fmt.Sprintf("services.%s", name),
+				"set path to a directory with a Dockerfile, or image to run a prebuilt image"))
+			continue
+		}
+		dockerfile := filepath.Join(service.Path, "Dockerfile")
+		if !filesystem.Exists(fsys, filepath.Join(projectRoot, dockerfile)) {
+			problems = append(problems, NewValidationError(fmt.Sprintf("services.%s.path", name),
+				fmt.Sprintf("%s does not exist; add one, or set image to run a prebuilt image", filepath.ToSlash(dockerfile))))
+		}
+	}
+	return problems
+}
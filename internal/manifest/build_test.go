@@ -0,0 +1,43 @@
+package manifest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestValidateBuildSources(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	writeFiles(t, fsys, map[string]string{
+		filepath.Join("project", "api", "Dockerfile"): "FROM python:3.12\n",
+	})
+
+	m := &WorkbenchManifest{
+		Metadata: ProjectMetadata{Name: "demo"},
+		Services: map[string]Service{
+			"api":    {Path: "./api"},
+			"auth":   {Image: "registry.internal/auth:1.4.2"},
+			"web":    {Path: "./web"},
+			"worker": {},
+			"mobile": {Path: "./mobile", Kind: ServiceKindLocal, Dev: "npx expo start"},
+		},
+	}
+
+	problems := m.ValidateBuildSources(fsys, "project")
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %v", problems)
+	}
+
+	expected := []struct{ field, message string }{
+		{"services.web.path", "web/Dockerfile does not exist"},
+		{"services.worker", "set path"},
+	}
+	for i, want := range expected {
+		err := problems[i].(*ManifestError)
+		if err.Field != want.field || !strings.Contains(err.Error(), want.message) {
+			t.Errorf("problem %d: expected %s (%s), got %s", i, want.field, want.message, err)
+		}
+	}
+}
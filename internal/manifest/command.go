@@ -0,0 +1,93 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Command is a command line that overrides a container's command or
+// entrypoint. In workbench.yaml it is written either as a list of arguments
+// or as one string, which is split like a shell would split it:
+//
+//	command: uvicorn app:app --reload --port 8000
+//	entrypoint: ["/bin/sh", "-c"]
+type Command []string
+
+// UnmarshalYAML accepts a string or a list of strings
+func (c *Command) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		args, err := SplitCommand(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		*c = args
+		return nil
+	case yaml.SequenceNode:
+		var args []string
+		if err := node.Decode(&args); err != nil {
+			return err
+		}
+		*c = args
+		return nil
+	default:
+		return fmt.Errorf("line %d: a command must be a string or a list of strings", node.Line)
+	}
+}
+
+// SplitCommand splits a command line into arguments at unquoted whitespace.
+// Single quotes keep their content literally; within double quotes and
+// outside quotes a backslash escapes the next character.
+func SplitCommand(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inArg = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in command %q", quote, line)
+	}
+	if escaped {
+		return nil, fmt.Errorf("command %q ends with a backslash", line)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,54 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"uvicorn app:app --reload --port 8000", []string{"uvicorn", "app:app", "--reload", "--port", "8000"}},
+		{`sh -c "npm run dev -- --host 0.0.0.0"`, []string{"sh", "-c", "npm run dev -- --host 0.0.0.0"}},
+		{`echo 'it''s' a\ b ""`, []string{"echo", "its", "a b", ""}},
+		{"  spaced\targs  ", []string{"spaced", "args"}},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommand(tt.line)
+		if err != nil {
+			t.Errorf("SplitCommand(%q) failed: %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitCommand(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	for _, line := range []string{`echo "open`, `echo 'open`, `echo \`} {
+		if _, err := SplitCommand(line); err == nil {
+			t.Errorf("SplitCommand(%q) should fail", line)
+		}
+	}
+}
+
+func TestCommand_UnmarshalYAML(t *testing.T) {
+	var service Service
+	data := "path: ./api\ncommand: uvicorn app:app --reload\nentrypoint: [\"/bin/sh\", \"-c\"]\n"
+	if err := yaml.Unmarshal([]byte(data), &service); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Command{"uvicorn", "app:app", "--reload"}); !reflect.DeepEqual(service.Command, want) {
+		t.Errorf("command = %q, want %q", service.Command, want)
+	}
+	if want := (Command{"/bin/sh", "-c"}); !reflect.DeepEqual(service.Entrypoint, want) {
+		t.Errorf("entrypoint = %q, want %q", service.Entrypoint, want)
+	}
+
+	if err := yaml.Unmarshal([]byte("command: {run: server}\n"), &service); err == nil {
+		t.Error("a mapping should not be accepted as a command")
+	}
+}
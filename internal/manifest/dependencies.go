@@ -0,0 +1,174 @@
+package manifest
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Conditions a dependency must reach before the service that depends on it
+// starts
+const (
+	DependencyHealthy   = "healthy"   // its health check passes
+	DependencyStarted   = "started"   // its container runs
+	DependencyCompleted = "completed" // it ran to completion, e.g. a migration job
+)
+
+// Dependency is a service or component that must be up before a service
+// starts, besides those its environment variables refer to. In
+// workbench.yaml it is written as a name, or with the condition to wait for:
+//
+//	dependsOn:
+//	  - auth
+//	  - service: migrate
+//	    condition: completed
+type Dependency struct {
+	Service   string `yaml:"service"`
+	Condition string `yaml:"condition,omitempty"` // empty waits for a health check when the dependency has one
+}
+
+// UnmarshalYAML accepts a name or a mapping with service and condition
+func (d *Dependency) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		d.Service = node.Value
+		return nil
+	}
+	type plain Dependency
+	return node.Decode((*plain)(d))
+}
+
+// MarshalYAML writes a dependency without a condition as its name
+func (d Dependency) MarshalYAML() (interface{}, error) {
+	if d.Condition == "" {
+		return d.Service, nil
+	}
+	type plain Dependency
+	return plain(d), nil
+}
+
+// DependencyCondition returns the condition a dependency is waited for
+// with: the one it sets, or healthy when the service it names declares a
+// health check and started otherwise
+func (m *WorkbenchManifest) DependencyCondition(dependency Dependency) string {
+	if dependency.Condition != "" {
+		return dependency.Condition
+	}
+	if service, exists := m.Services[dependency.Service]; exists && service.Health != nil && service.Port > 0 {
+		return DependencyHealthy
+	}
+	return DependencyStarted
+}
+
+// validateDependencies checks that every dependency names a service or
+// component that runs in a container, can reach its condition, and does
+// not lead back to the service that depends on it
+func (m *WorkbenchManifest) validateDependencies() error {
+	for _, name := range slices.Sorted(maps.Keys(m.Services)) {
+		field := fmt.Sprintf("services.%s.dependsOn", name)
+		for _, dependency := range m.Services[name].DependsOn {
+			target, isService := m.Services[dependency.Service]
+			_, isComponent := m.Components[dependency.Service]
+			switch {
+			case dependency.Service == "":
+				return NewValidationError(field, "dependencies need a service name")
+			case dependency.Service == name:
+				return NewValidationError(field, "a service cannot depend on itself")
+			case !isService && !isComponent:
+				return NewValidationError(field, fmt.Sprintf("'%s' is not a service or component", dependency.Service))
+			case isService && !target.IsContainer():
+				return NewValidationError(field, fmt.Sprintf("'%s' does not run in a container, so nothing can wait for it", dependency.Service))
+			}
+			switch dependency.Condition {
+			case "", DependencyStarted, DependencyCompleted:
+			case DependencyHealthy:
+				if !isService || target.Health == nil || target.Port == 0 {
+					return NewValidationError(field,
+						fmt.Sprintf("'%s' declares no health check to wait for; add health and port to it or wait for started", dependency.Service))
+				}
+			default:
+				return NewValidationError(field,
+					fmt.Sprintf("unsupported condition '%s' (use healthy, started, or completed)", dependency.Condition))
+			}
+		}
+	}
+	if cycle := m.dependencyCycle(); cycle != nil {
+		return NewValidationError(fmt.Sprintf("services.%s.dependsOn", cycle[0]),
+			fmt.Sprintf("dependencies form a cycle: %s", strings.Join(cycle, " -> ")))
+	}
+	return nil
+}
+
+// dependencyCycle returns the services of a cycle of dependencies, starting
+// and ending with the same service, or nil when there is none
+func (m *WorkbenchManifest) dependencyCycle() []string {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			for i, entry := range path {
+				if entry == name {
+					return append(append([]string(nil), path[i:]...), name)
+				}
+			}
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dependency := range m.Services[name].DependsOn {
+			if cycle := visit(dependency.Service); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.Services)) {
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// RemoveFromDependencies removes a deleted service or component from the
+// dependencies of every service
+func (m *WorkbenchManifest) RemoveFromDependencies(name string) {
+	for serviceName, service := range m.Services {
+		if len(service.DependsOn) == 0 {
+			continue
+		}
+		kept := service.DependsOn[:0:0]
+		for _, dependency := range service.DependsOn {
+			if dependency.Service != name {
+				kept = append(kept, dependency)
+			}
+		}
+		if len(kept) == 0 {
+			kept = nil
+		}
+		service.DependsOn = kept
+		m.Services[serviceName] = service
+	}
+}
+
+// dependencyNames returns the sorted names of a service's dependencies
+func dependencyNames(dependencies []Dependency) []string {
+	names := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		names = append(names, dependency.Service)
+	}
+	sort.Strings(names)
+	return names
+}
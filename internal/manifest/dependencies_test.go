@@ -0,0 +1,105 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDependency_UnmarshalYAML(t *testing.T) {
+	var service Service
+	data := "path: ./api\ndependsOn:\n  - auth\n  - service: migrate\n    condition: completed\n"
+	if err := yaml.Unmarshal([]byte(data), &service); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	want := []Dependency{{Service: "auth"}, {Service: "migrate", Condition: DependencyCompleted}}
+	if !reflect.DeepEqual(service.DependsOn, want) {
+		t.Errorf("DependsOn = %+v, want %+v", service.DependsOn, want)
+	}
+
+	out, err := yaml.Marshal(service.DependsOn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out); got != "- auth\n- service: migrate\n  condition: completed\n" {
+		t.Errorf("expected dependencies without a condition to be written as names, got:\n%s", got)
+	}
+}
+
+func dependenciesManifest() *WorkbenchManifest {
+	return &WorkbenchManifest{
+		Metadata:   ProjectMetadata{Name: "demo"},
+		Components: map[string]Component{"gateway": {Path: "gateway"}},
+		Services: map[string]Service{
+			"web":     {Path: "web", DependsOn: []Dependency{{Service: "api"}, {Service: "gateway"}}},
+			"api":     {Path: "api", Port: 8000, Health: &Health{Path: "/health"}, DependsOn: []Dependency{{Service: "migrate", Condition: DependencyCompleted}}},
+			"migrate": {Path: "migrate"},
+			"mobile":  {Path: "mobile", Kind: ServiceKindLocal, Dev: "npx expo start"},
+		},
+	}
+}
+
+func TestDependencyCondition(t *testing.T) {
+	m := dependenciesManifest()
+	for dependency, want := range map[Dependency]string{
+		{Service: "api"}:                               DependencyHealthy,
+		{Service: "gateway"}:                           DependencyStarted,
+		{Service: "migrate"}:                           DependencyStarted,
+		{Service: "api", Condition: DependencyStarted}: DependencyStarted,
+	} {
+		if got := m.DependencyCondition(dependency); got != want {
+			t.Errorf("DependencyCondition(%+v) = %s, want %s", dependency, got, want)
+		}
+	}
+}
+
+func TestValidate_Dependencies(t *testing.T) {
+	if err := dependenciesManifest().Validate(); err != nil {
+		t.Fatalf("expected valid dependencies, got %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		service   string
+		dependsOn []Dependency
+		message   string
+	}{
+		{"unknown", "web", []Dependency{{Service: "billing"}}, "not a service or component"},
+		{"itself", "web", []Dependency{{Service: "web"}}, "cannot depend on itself"},
+		{"host service", "web", []Dependency{{Service: "mobile"}}, "does not run in a container"},
+		{"no health check", "web", []Dependency{{Service: "migrate", Condition: DependencyHealthy}}, "no health check"},
+		{"unknown condition", "web", []Dependency{{Service: "api", Condition: "ready"}}, "unsupported condition 'ready'"},
+		{"cycle", "migrate", []Dependency{{Service: "web"}}, "cycle: api -> migrate -> web -> api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := dependenciesManifest()
+			service := m.Services[tt.service]
+			service.DependsOn = tt.dependsOn
+			m.Services[tt.service] = service
+
+			err := m.Validate()
+			if !IsManifestError(err, ErrorTypeValidation) || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("expected a validation error containing %q, got %v", tt.message, err)
+			}
+		})
+	}
+}
+
+func TestRemoveFromDependencies(t *testing.T) {
+	m := dependenciesManifest()
+	if got := m.References("web"); !reflect.DeepEqual(got, []string{"api", "gateway"}) {
+		t.Errorf("expected the dependencies of web to be references, got %v", got)
+	}
+
+	m.RemoveFromDependencies("api")
+	if got := m.Services["web"].DependsOn; !reflect.DeepEqual(got, []Dependency{{Service: "gateway"}}) {
+		t.Errorf("expected api to be removed from the dependencies of web, got %+v", got)
+	}
+	m.RemoveFromDependencies("gateway")
+	if m.Services["web"].DependsOn != nil {
+		t.Errorf("expected web to have no dependencies left, got %+v", m.Services["web"].DependsOn)
+	}
+}
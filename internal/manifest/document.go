@@ -0,0 +1,314 @@
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultIndent matches the indentation yaml.Marshal uses for new manifests
+const defaultIndent = 4
+
+// mergeKey is the YAML merge key used to inherit values from an anchor
+const mergeKey = "<<"
+
+// Save writes m to path on fsys. When the file already exists, the changes
+// are merged into its existing YAML so that comments, key order, anchors,
+// and indentation written by hand survive the update. Entries that were
+// loaded from an included file are written back to that file.
+func Save(fsys filesystem.FS, path string, m *WorkbenchManifest) error {
+	if len(m.sources) == 0 {
+		return saveDocument(fsys, path, m)
+	}
+
+	root, fragments := m.split()
+	if err := saveDocument(fsys, path, root); err != nil {
+		return err
+	}
+
+	files := make([]string, 0, len(fragments))
+	for file := range fragments {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		if err := saveDocument(fsys, filepath.Join(filepath.Dir(path), file), fragments[file]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveDocument writes value to path, preserving the formatting of the existing file
+func saveDocument(fsys filesystem.FS, path string, value interface{}) error {
+	original, err := fsys.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	data, err := MarshalPreserving(original, value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	if err := fsys.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// MarshalPreserving encodes value (a *WorkbenchManifest or *Fragment) as
+// YAML, reusing the document in original as the starting point. Values that
+// did not change keep their formatting and comments, keys keep their order,
+// new keys are appended, and keys no longer present are removed. If original
+// is empty or cannot be parsed, value is marshaled from scratch.
+func MarshalPreserving(original []byte, value interface{}) ([]byte, error) {
+	var updated yaml.Node
+	if err := updated.Encode(value); err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if len(bytes.TrimSpace(original)) == 0 || yaml.Unmarshal(original, &doc) != nil ||
+		doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return yaml.Marshal(value)
+	}
+
+	mergeNode(doc.Content[0], &updated)
+	repairAliases(&doc)
+	return encodeNode(&doc, detectIndent(original))
+}
+
+// mergeNode updates existing in place so that it represents the same value as updated
+func mergeNode(existing, updated *yaml.Node) {
+	if existing.Kind == yaml.AliasNode {
+		// Keep the alias as long as it still resolves to the right value
+		if sameValue(existing, updated) {
+			return
+		}
+		replaceNode(existing, updated)
+		return
+	}
+	if existing.Kind != updated.Kind {
+		replaceNode(existing, updated)
+		return
+	}
+
+	switch existing.Kind {
+	case yaml.MappingNode:
+		mergeMapping(existing, updated)
+	case yaml.SequenceNode:
+		if !sameValue(existing, updated) {
+			existing.Content = updated.Content
+		}
+	case yaml.ScalarNode:
+		// Compare the text rather than the tag so that e.g. an unquoted
+		// number read into a string field keeps its original style
+		if existing.Value != updated.Value {
+			existing.Value = updated.Value
+			existing.Tag = updated.Tag
+			existing.Style = updated.Style
+		}
+	default:
+		replaceNode(existing, updated)
+	}
+}
+
+// mergeMapping merges the key/value pairs of updated into existing
+func mergeMapping(existing, updated *yaml.Node) {
+	updatedValues := make(map[string]*yaml.Node, len(updated.Content)/2)
+	var updatedOrder []string
+	for i := 0; i+1 < len(updated.Content); i += 2 {
+		key := updated.Content[i].Value
+		updatedValues[key] = updated.Content[i+1]
+		updatedOrder = append(updatedOrder, key)
+	}
+
+	inherited := inheritedValues(existing)
+	present := make(map[string]bool, len(existing.Content)/2)
+	content := make([]*yaml.Node, 0, len(existing.Content))
+	for i := 0; i+1 < len(existing.Content); i += 2 {
+		keyNode, valueNode := existing.Content[i], existing.Content[i+1]
+		key := keyNode.Value
+		if key == mergeKey {
+			// Without this the encoder writes the resolved tag out as "!!merge <<"
+			keyNode.Tag = ""
+			content = append(content, keyNode, valueNode)
+			continue
+		}
+		value, ok := updatedValues[key]
+		if !ok {
+			continue // removed
+		}
+		mergeNode(valueNode, value)
+		content = append(content, keyNode, valueNode)
+		present[key] = true
+	}
+
+	for _, key := range updatedOrder {
+		if present[key] {
+			continue
+		}
+		value := updatedValues[key]
+		if isZero(value) {
+			continue // absent keys decode to the same zero value
+		}
+		if source, ok := inherited[key]; ok && sameValue(source, value) {
+			continue // still provided by the merge key
+		}
+		content = append(content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	}
+	existing.Content = content
+}
+
+// repairAliases keeps every alias of doc resolvable after a merge. When the
+// node defining an anchor was removed or replaced, its anchor moves to the
+// first alias that still uses it, which takes the node's value.
+func repairAliases(doc *yaml.Node) {
+	defined := make(map[*yaml.Node]bool)
+	moved := make(map[*yaml.Node]*yaml.Node)
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Kind == yaml.AliasNode && node.Alias != nil {
+			if target, ok := moved[node.Alias]; ok {
+				node.Alias = target
+				return
+			}
+			if defined[node.Alias] {
+				return
+			}
+			target := node.Alias
+			head, line, foot := node.HeadComment, node.LineComment, node.FootComment
+			*node = *target
+			node.HeadComment, node.LineComment, node.FootComment = head, line, foot
+			moved[target] = node
+		}
+		if node.Anchor != "" {
+			defined[node] = true
+		}
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+	walk(doc)
+}
+
+// inheritedValues returns the values a mapping inherits through merge keys
+func inheritedValues(mapping *yaml.Node) map[string]*yaml.Node {
+	values := make(map[string]*yaml.Node)
+	var collect func(source *yaml.Node)
+	collect = func(source *yaml.Node) {
+		switch source.Kind {
+		case yaml.AliasNode:
+			collect(source.Alias)
+		case yaml.SequenceNode:
+			for _, item := range source.Content {
+				collect(item)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(source.Content); i += 2 {
+				if _, exists := values[source.Content[i].Value]; !exists {
+					values[source.Content[i].Value] = source.Content[i+1]
+				}
+			}
+		}
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == mergeKey {
+			collect(mapping.Content[i+1])
+		}
+	}
+	return values
+}
+
+// isZero reports whether a node holds an empty string, number, or collection
+func isZero(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value == "" || node.Tag == "!!null" || (node.Tag == "!!int" && node.Value == "0")
+	case yaml.MappingNode, yaml.SequenceNode:
+		return len(node.Content) == 0
+	}
+	return false
+}
+
+// replaceNode overwrites existing with updated, keeping its comments and anchor
+func replaceNode(existing, updated *yaml.Node) {
+	head, line, foot, anchor := existing.HeadComment, existing.LineComment, existing.FootComment, existing.Anchor
+	*existing = *updated
+	existing.HeadComment, existing.LineComment, existing.FootComment = head, line, foot
+	if existing.Kind != yaml.AliasNode {
+		existing.Anchor = anchor
+	}
+}
+
+// sameValue reports whether two nodes decode to the same value
+func sameValue(a, b *yaml.Node) bool {
+	var left, right interface{}
+	if a.Decode(&left) != nil || b.Decode(&right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(normalize(left), normalize(right))
+}
+
+// normalize converts decoded scalars to strings so that 5432 and "5432" compare equal
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = normalize(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = normalize(item)
+		}
+		return result
+	case nil:
+		return nil
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// encodeNode encodes a YAML document with the given indentation
+func encodeNode(doc *yaml.Node, indent int) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// detectIndent returns the indentation width used by a YAML document
+func detectIndent(data []byte) int {
+	indent := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+		if width := len(line) - len(trimmed); width > 0 && (indent == 0 || width < indent) {
+			indent = width
+		}
+	}
+	if indent < 2 || indent > 8 {
+		return defaultIndent
+	}
+	return indent
+}
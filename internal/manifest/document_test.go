@@ -0,0 +1,232 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+const handWrittenManifest = `# Project manifest for the demo app
+apiVersion: openworkbench.io/v1alpha1
+kind: Project
+metadata:
+  name: demo # keep this short
+services:
+  # The public website
+  web:
+    template: nextjs-full-stack
+    path: ./web
+    port: 3000
+    environment: &shared-env
+      LOG_LEVEL: info
+  api:
+    path: ./api
+    template: fastapi-basic
+    environment: *shared-env
+`
+
+func TestMarshalPreserving(t *testing.T) {
+	m, err := Parse([]byte(handWrittenManifest), "workbench.yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// Add a service and a resource, and remove nothing
+	m.Services["worker"] = Service{Template: "fastapi-basic", Path: "worker"}
+	api := m.Services["api"]
+	api.Resources = map[string]Resource{"db": {Type: "postgres-db"}}
+	m.Services["api"] = api
+
+	data, err := MarshalPreserving([]byte(handWrittenManifest), m)
+	if err != nil {
+		t.Fatalf("MarshalPreserving failed: %v", err)
+	}
+	out := string(data)
+
+	for _, want := range []string{
+		"# Project manifest for the demo app",
+		"name: demo # keep this short",
+		"# The public website",
+		"environment: &shared-env",
+		"environment: *shared-env",
+		"\n  worker:\n    template: fastapi-basic\n    path: worker\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	// User key order is kept: web before api, path before template in api
+	if strings.Index(out, "  web:") > strings.Index(out, "  api:") {
+		t.Errorf("expected service order to be preserved:\n%s", out)
+	}
+	apiSection := out[strings.Index(out, "  api:"):]
+	if strings.Index(apiSection, "path:") > strings.Index(apiSection, "template:") {
+		t.Errorf("expected key order within api to be preserved:\n%s", out)
+	}
+
+	// The result still decodes to the updated manifest
+	var roundTrip WorkbenchManifest
+	if err := yaml.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatalf("output is not valid YAML: %v", err)
+	}
+	if roundTrip.Services["api"].Resources["db"].Type != "postgres-db" {
+		t.Errorf("expected new resource in output:\n%s", out)
+	}
+	if roundTrip.Services["api"].Environment["LOG_LEVEL"] != "info" {
+		t.Errorf("expected alias to keep resolving:\n%s", out)
+	}
+}
+
+func TestMarshalPreserving_RemovesAndUpdates(t *testing.T) {
+	m, err := Parse([]byte(handWrittenManifest), "workbench.yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	delete(m.Services, "web")
+	api := m.Services["api"]
+	api.Environment = map[string]string{"LOG_LEVEL": "debug"}
+	m.Services["api"] = api
+
+	data, err := MarshalPreserving([]byte(handWrittenManifest), m)
+	if err != nil {
+		t.Fatalf("MarshalPreserving failed: %v", err)
+	}
+	out := string(data)
+
+	if strings.Contains(out, "web:") || strings.Contains(out, "The public website") {
+		t.Errorf("expected web service and its comment to be removed:\n%s", out)
+	}
+	if strings.Contains(out, "*shared-env") {
+		t.Errorf("expected alias to be replaced once the value changed:\n%s", out)
+	}
+	if !strings.Contains(out, "LOG_LEVEL: debug") {
+		t.Errorf("expected updated environment value:\n%s", out)
+	}
+	if !strings.HasPrefix(out, "# Project manifest for the demo app") {
+		t.Errorf("expected document comment to be preserved:\n%s", out)
+	}
+}
+
+func TestMarshalPreserving_RemovesAnchorOwner(t *testing.T) {
+	m, err := Parse([]byte(handWrittenManifest), "workbench.yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// web defines the anchor api's environment is an alias of
+	delete(m.Services, "web")
+
+	data, err := MarshalPreserving([]byte(handWrittenManifest), m)
+	if err != nil {
+		t.Fatalf("MarshalPreserving failed: %v", err)
+	}
+	out := string(data)
+
+	if strings.Contains(out, "web:") {
+		t.Errorf("expected web service to be removed:\n%s", out)
+	}
+	if !strings.Contains(out, "environment: &shared-env") {
+		t.Errorf("expected anchor to move to api's environment:\n%s", out)
+	}
+	reloaded, err := Parse(data, "workbench.yaml")
+	if err != nil {
+		t.Fatalf("output no longer loads: %v\n%s", err, out)
+	}
+	if reloaded.Services["api"].Environment["LOG_LEVEL"] != "info" {
+		t.Errorf("expected api to keep the shared environment:\n%s", out)
+	}
+}
+
+func TestMarshalPreserving_RemovesMergeSource(t *testing.T) {
+	original := `services:
+  base: &base
+    template: fastapi-basic
+    path: ./base
+  api:
+    <<: *base
+    path: ./api
+  worker:
+    <<: *base
+    path: ./worker
+metadata:
+  name: demo
+`
+	m, err := Parse([]byte(original), "workbench.yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	delete(m.Services, "base")
+
+	data, err := MarshalPreserving([]byte(original), m)
+	if err != nil {
+		t.Fatalf("MarshalPreserving failed: %v", err)
+	}
+	reloaded, err := Parse(data, "workbench.yaml")
+	if err != nil {
+		t.Fatalf("output no longer loads: %v\n%s", err, data)
+	}
+	if _, exists := reloaded.Services["base"]; exists {
+		t.Errorf("expected base service to be removed:\n%s", data)
+	}
+	for _, name := range []string{"api", "worker"} {
+		if reloaded.Services[name].Template != "fastapi-basic" {
+			t.Errorf("expected %s to keep inheriting its template:\n%s", name, data)
+		}
+	}
+}
+
+func TestMarshalPreserving_MergeKeys(t *testing.T) {
+	original := `services:
+  base: &base
+    template: fastapi-basic
+    path: ./base
+  api:
+    <<: *base
+    path: ./api
+metadata:
+  name: demo
+`
+	m, err := Parse([]byte(original), "workbench.yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	data, err := MarshalPreserving([]byte(original), m)
+	if err != nil {
+		t.Fatalf("MarshalPreserving failed: %v", err)
+	}
+	if string(data) != original {
+		t.Errorf("expected unchanged manifest to round-trip exactly, got:\n%s", data)
+	}
+}
+
+func TestSave(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	m := &WorkbenchManifest{Metadata: ProjectMetadata{Name: "demo"}}
+
+	// A new file is marshaled from scratch
+	if err := Save(fsys, "workbench.yaml", m); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	created, _ := fsys.ReadFile("workbench.yaml")
+	expected, _ := yaml.Marshal(m)
+	if string(created) != string(expected) {
+		t.Errorf("unexpected new manifest:\n%s", created)
+	}
+
+	// An existing file keeps its comments
+	if err := fsys.WriteFile("workbench.yaml", []byte("# hello\n"+string(created)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.Services = map[string]Service{"api": {Path: "api"}}
+	if err := Save(fsys, "workbench.yaml", m); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	updated, _ := fsys.ReadFile("workbench.yaml")
+	if !strings.HasPrefix(string(updated), "# hello\n") || !strings.Contains(string(updated), "path: api") {
+		t.Errorf("unexpected updated manifest:\n%s", updated)
+	}
+}
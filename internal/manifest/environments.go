@@ -0,0 +1,157 @@
+package manifest
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+)
+
+// validateEnvironments checks that the include and exclude lists of every
+// environment name services or components, and never the same one twice
+func (m *WorkbenchManifest) validateEnvironments() error {
+	for _, env := range slices.Sorted(maps.Keys(m.Environments)) {
+		environment := m.Environments[env]
+		included := make(map[string]bool, len(environment.Include))
+		for _, name := range environment.Include {
+			if !m.hasMember(name) {
+				return NewValidationError(fmt.Sprintf("environments.%s.include", env),
+					fmt.Sprintf("'%s' is not a service or component", name))
+			}
+			included[name] = true
+		}
+		for _, name := range environment.Exclude {
+			if !m.hasMember(name) {
+				return NewValidationError(fmt.Sprintf("environments.%s.exclude", env),
+					fmt.Sprintf("'%s' is not a service or component", name))
+			}
+			if included[name] {
+				return NewValidationError(fmt.Sprintf("environments.%s.exclude", env),
+					fmt.Sprintf("'%s' is both included and excluded", name))
+			}
+		}
+	}
+	return nil
+}
+
+// InEnvironment reports whether the named service or component is part of
+// the environment. A name in the environment's exclude list is left out; a
+// name in the include list of any environment is part of only the
+// environments that include it. Everything is part of the project when no
+// environment is selected.
+func (m *WorkbenchManifest) InEnvironment(env, name string) bool {
+	if env == "" {
+		return true
+	}
+	environment := m.Environments[env]
+	if containsString(environment.Exclude, name) {
+		return false
+	}
+	if containsString(environment.Include, name) {
+		return true
+	}
+	for _, other := range m.Environments {
+		if containsString(other.Include, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// ForEnvironment returns a copy of the manifest with only the services and
+// components that are part of the environment. Without an environment, or
+// when the project defines none, the copy is complete; an environment the
+// project does not define is an error.
+func (m *WorkbenchManifest) ForEnvironment(env string) (*WorkbenchManifest, error) {
+	if env == "" || len(m.Environments) == 0 {
+		return m.Clone(), nil
+	}
+	if _, exists := m.Environments[env]; !exists {
+		return nil, NewValidationError("environments",
+			fmt.Sprintf("environment '%s' not found (available: %s)", env, strings.Join(slices.Sorted(maps.Keys(m.Environments)), ", ")))
+	}
+
+	subset := m.Clone()
+	for name := range subset.Services {
+		if !m.InEnvironment(env, name) {
+			delete(subset.Services, name)
+		}
+	}
+	for name := range subset.Components {
+		if !m.InEnvironment(env, name) {
+			delete(subset.Components, name)
+		}
+	}
+	for group, members := range subset.Groups {
+		var kept []string
+		for _, member := range members {
+			if m.InEnvironment(env, member) {
+				kept = append(kept, member)
+			}
+		}
+		subset.Groups[group] = kept
+	}
+	return subset, nil
+}
+
+// EnvironmentWarnings returns a warning for every service or component that
+// refers to a service or component its environment leaves out, since it
+// will not find it once deployed there
+func (m *WorkbenchManifest) EnvironmentWarnings() []warnings.Warning {
+	var found []warnings.Warning
+	for _, env := range slices.Sorted(maps.Keys(m.Environments)) {
+		for _, name := range append(slices.Sorted(maps.Keys(m.Services)), slices.Sorted(maps.Keys(m.Components))...) {
+			if !m.InEnvironment(env, name) {
+				continue
+			}
+			for _, referenced := range m.References(name) {
+				if m.hasMember(referenced) && !m.InEnvironment(env, referenced) {
+					found = append(found, warnings.New(m.memberEntry(name),
+						"depends on '%s', which environment '%s' leaves out", referenced, env))
+				}
+			}
+		}
+	}
+	return warnings.Sort(found)
+}
+
+// RemoveFromEnvironments removes a deleted service or component from the
+// include and exclude lists of every environment
+func (m *WorkbenchManifest) RemoveFromEnvironments(name string) {
+	for env, environment := range m.Environments {
+		environment.Include = withoutString(environment.Include, name)
+		environment.Exclude = withoutString(environment.Exclude, name)
+		m.Environments[env] = environment
+	}
+}
+
+// memberEntry returns the manifest entry of a service or component
+func (m *WorkbenchManifest) memberEntry(name string) string {
+	if _, exists := m.Services[name]; exists {
+		return "services." + name
+	}
+	return "components." + name
+}
+
+// containsString reports whether values holds value
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutString returns values without value, or nil when nothing is left
+func withoutString(values []string, value string) []string {
+	var kept []string
+	for _, candidate := range values {
+		if candidate != value {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,84 @@
+package manifest
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func environmentsManifest() *WorkbenchManifest {
+	return &WorkbenchManifest{
+		Metadata: ProjectMetadata{Name: "demo"},
+		Components: map[string]Component{
+			"gateway": {Path: "gateway"},
+		},
+		Services: map[string]Service{
+			"web":      {Path: "web", Environment: map[string]string{"PAYMENTS_URL": "${services.payments.url}"}},
+			"payments": {Path: "payments"},
+			"admin":    {Path: "admin"},
+		},
+		Environments: map[string]Environment{
+			"staging": {Provider: "aws", Include: []string{"admin"}},
+			"prod":    {Provider: "aws", Exclude: []string{"payments"}},
+		},
+		Groups: map[string][]string{"billing": {"web", "payments"}},
+	}
+}
+
+func TestForEnvironment(t *testing.T) {
+	m := environmentsManifest()
+	if err := m.Validate(); err != nil {
+		t.Fatalf("expected a valid manifest, got %v", err)
+	}
+
+	tests := []struct {
+		env      string
+		services []string
+	}{
+		{"", []string{"admin", "payments", "web"}},
+		{"staging", []string{"admin", "payments", "web"}},
+		{"prod", []string{"web"}},
+	}
+	for _, tt := range tests {
+		subset, err := m.ForEnvironment(tt.env)
+		if err != nil {
+			t.Fatalf("ForEnvironment(%q) failed: %v", tt.env, err)
+		}
+		if got := slices.Sorted(maps.Keys(subset.Services)); !reflect.DeepEqual(got, tt.services) {
+			t.Errorf("ForEnvironment(%q): expected %v, got %v", tt.env, tt.services, got)
+		}
+		if _, exists := subset.Components["gateway"]; !exists {
+			t.Errorf("ForEnvironment(%q): expected the gateway to be kept", tt.env)
+		}
+	}
+
+	prod, _ := m.ForEnvironment("prod")
+	if !reflect.DeepEqual(prod.Groups["billing"], []string{"web"}) {
+		t.Errorf("expected excluded services to leave their groups, got %v", prod.Groups["billing"])
+	}
+	if len(m.Services) != 3 {
+		t.Error("ForEnvironment should not modify the manifest")
+	}
+
+	if _, err := m.ForEnvironment("qa"); !IsManifestError(err, ErrorTypeValidation) {
+		t.Errorf("expected a validation error for an unknown environment, got %v", err)
+	}
+}
+
+func TestEnvironmentWarnings(t *testing.T) {
+	m := environmentsManifest()
+	found := m.EnvironmentWarnings()
+	if len(found) != 1 || found[0].Entry != "services.web" || found[0].Message != "depends on 'payments', which environment 'prod' leaves out" {
+		t.Errorf("expected a warning about web depending on payments in prod, got %v", found)
+	}
+
+	m.RemoveFromEnvironments("payments")
+	m.RemoveFromEnvironments("admin")
+	if found := m.EnvironmentWarnings(); len(found) != 0 {
+		t.Errorf("expected no warnings once nothing is excluded, got %v", found)
+	}
+	if m.Environments["staging"].Include != nil || m.Environments["prod"].Exclude != nil {
+		t.Errorf("expected the deleted services to leave every environment, got %+v", m.Environments)
+	}
+}
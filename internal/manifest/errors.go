@@ -0,0 +1,76 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorType represents the category of a workbench.yaml error
+type ErrorType int
+
+const (
+	// ErrorTypeNotFound indicates that workbench.yaml could not be located
+	ErrorTypeNotFound ErrorType = iota
+	// ErrorTypeParse indicates that workbench.yaml is not valid YAML
+	ErrorTypeParse
+	// ErrorTypeValidation indicates that workbench.yaml is well-formed but its contents are invalid
+	ErrorTypeValidation
+)
+
+// ManifestError represents a structured error related to workbench.yaml
+type ManifestError struct {
+	Type        ErrorType // The category of error
+	Message     string    // User-friendly error message
+	Path        string    // Path to the manifest file if applicable
+	Field       string    // Manifest field if applicable (e.g. services.backend.port)
+	OriginalErr error     // Original error for debugging
+}
+
+// Error returns the user-friendly error message
+func (e *ManifestError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the original error
+func (e *ManifestError) Unwrap() error {
+	return e.OriginalErr
+}
+
+// NewNotFoundError creates an error for a missing workbench.yaml
+func NewNotFoundError(path string, originalErr error) *ManifestError {
+	return &ManifestError{
+		Type:        ErrorTypeNotFound,
+		Message:     fmt.Sprintf("workbench.yaml not found in %s. Please run this command from your project root", path),
+		Path:        path,
+		OriginalErr: originalErr,
+	}
+}
+
+// NewParseError creates an error for a workbench.yaml that cannot be parsed
+func NewParseError(path string, originalErr error) *ManifestError {
+	return &ManifestError{
+		Type:        ErrorTypeParse,
+		Message:     fmt.Sprintf("failed to parse %s: %v", path, originalErr),
+		Path:        path,
+		OriginalErr: originalErr,
+	}
+}
+
+// NewValidationError creates an error for invalid manifest contents
+func NewValidationError(field, message string) *ManifestError {
+	text := message
+	if field != "" {
+		text = fmt.Sprintf("%s: %s", field, message)
+	}
+	return &ManifestError{
+		Type:    ErrorTypeValidation,
+		Message: text,
+		Field:   field,
+	}
+}
+
+// IsManifestError reports whether err is or wraps a ManifestError of the given type
+func IsManifestError(err error, errType ErrorType) bool {
+	var manifestErr *ManifestError
+	return errors.As(err, &manifestErr) && manifestErr.Type == errType
+}
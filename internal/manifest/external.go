@@ -0,0 +1,92 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// externalReferencePattern matches ${external.<name>.<property>} references
+var externalReferencePattern = regexp.MustCompile(`\$\{external\.([^.}]+)\.([^}]+)\}`)
+
+// validateExternal checks external dependencies and the references to them
+func (m *WorkbenchManifest) validateExternal() error {
+	for name, external := range m.External {
+		if name == "" {
+			return NewValidationError("external", "external dependency names cannot be empty")
+		}
+		if external.URL == "" {
+			return NewValidationError(fmt.Sprintf("external.%s.url", name), "a base URL is required")
+		}
+		if len(m.Environments) == 0 {
+			continue
+		}
+		for env := range external.Environments {
+			if _, exists := m.Environments[env]; !exists {
+				return NewValidationError(fmt.Sprintf("external.%s.environments.%s", name, env),
+					fmt.Sprintf("environment '%s' is not defined", env))
+			}
+		}
+	}
+
+	for _, serviceName := range sortedServiceNames(m.Services) {
+		for key, value := range m.Services[serviceName].Environment {
+			for _, match := range externalReferencePattern.FindAllStringSubmatch(value, -1) {
+				field := fmt.Sprintf("services.%s.environment.%s", serviceName, key)
+				if _, exists := m.External[match[1]]; !exists {
+					return NewValidationError(field, fmt.Sprintf("external dependency '%s' is not defined", match[1]))
+				}
+				if match[2] != "url" {
+					return NewValidationError(field, fmt.Sprintf("unknown property '%s' of external dependency '%s' (use url)", match[2], match[1]))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ResolveExternalReferences replaces ${external.<name>.url} references in
+// value with the dependency's base URL for the given environment. An empty
+// environment selects the local development URL.
+func (m *WorkbenchManifest) ResolveExternalReferences(value, environment string) string {
+	return externalReferencePattern.ReplaceAllStringFunc(value, func(match string) string {
+		parts := externalReferencePattern.FindStringSubmatch(match)
+		external, exists := m.External[parts[1]]
+		if !exists || parts[2] != "url" {
+			return match
+		}
+		return external.URLFor(environment)
+	})
+}
+
+// ExternalReferences returns the sorted names of the external dependencies
+// that the environment variables of the named service or component refer to
+func (m *WorkbenchManifest) ExternalReferences(name string) []string {
+	environment := m.Components[name].Environment
+	if service, exists := m.Services[name]; exists {
+		environment = service.Environment
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, value := range environment {
+		for _, match := range externalReferencePattern.FindAllStringSubmatch(value, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedServiceNames returns the names of services in a stable order
+func sortedServiceNames(services map[string]Service) []string {
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
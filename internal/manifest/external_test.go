@@ -0,0 +1,103 @@
+package manifest
+
+import "testing"
+
+func externalManifest() *WorkbenchManifest {
+	return &WorkbenchManifest{
+		Metadata: ProjectMetadata{Name: "demo"},
+		Environments: map[string]Environment{
+			"dev":  {Provider: "aws"},
+			"prod": {Provider: "aws"},
+		},
+		External: map[string]External{
+			"stripe": {
+				URL:          "https://sandbox.stripe.test",
+				Environments: map[string]string{"prod": "https://api.stripe.com"},
+			},
+		},
+		Services: map[string]Service{
+			"api": {Path: "api", Environment: map[string]string{"STRIPE_URL": "${external.stripe.url}/v1"}},
+		},
+	}
+}
+
+func TestValidate_External(t *testing.T) {
+	if err := externalManifest().Validate(); err != nil {
+		t.Fatalf("expected valid external dependencies, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(m *WorkbenchManifest)
+		field  string
+	}{
+		{
+			name:   "missing url",
+			modify: func(m *WorkbenchManifest) { m.External["sendgrid"] = External{} },
+			field:  "external.sendgrid.url",
+		},
+		{
+			name: "unknown environment",
+			modify: func(m *WorkbenchManifest) {
+				m.External["stripe"] = External{URL: "https://api.stripe.com", Environments: map[string]string{"staging": "x"}}
+			},
+			field: "external.stripe.environments.staging",
+		},
+		{
+			name: "undefined dependency",
+			modify: func(m *WorkbenchManifest) {
+				m.Services["api"].Environment["MAIL_URL"] = "${external.sendgrid.url}"
+			},
+			field: "services.api.environment.MAIL_URL",
+		},
+		{
+			name: "unknown property",
+			modify: func(m *WorkbenchManifest) {
+				m.Services["api"].Environment["STRIPE_URL"] = "${external.stripe.key}"
+			},
+			field: "services.api.environment.STRIPE_URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := externalManifest()
+			tt.modify(m)
+			err := m.Validate()
+			if !IsManifestError(err, ErrorTypeValidation) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			if field := err.(*ManifestError).Field; field != tt.field {
+				t.Errorf("expected field %s, got %q", tt.field, field)
+			}
+		})
+	}
+}
+
+func TestResolveExternalReferences(t *testing.T) {
+	m := externalManifest()
+
+	if got := m.ResolveExternalReferences("${external.stripe.url}/v1", ""); got != "https://sandbox.stripe.test/v1" {
+		t.Errorf("expected local URL, got %q", got)
+	}
+	if got := m.ResolveExternalReferences("${external.stripe.url}/v1", "prod"); got != "https://api.stripe.com/v1" {
+		t.Errorf("expected prod URL, got %q", got)
+	}
+	if got := m.ResolveExternalReferences("${external.stripe.url}", "dev"); got != "https://sandbox.stripe.test" {
+		t.Errorf("expected fallback to base URL, got %q", got)
+	}
+	if got := m.ResolveExternalReferences("${services.api.host}", "prod"); got != "${services.api.host}" {
+		t.Errorf("expected other references to be left alone, got %q", got)
+	}
+}
+
+func TestExternalReferences(t *testing.T) {
+	m := externalManifest()
+
+	if got := m.ExternalReferences("api"); len(got) != 1 || got[0] != "stripe" {
+		t.Errorf("expected api to reference stripe, got %v", got)
+	}
+	if got := m.ExternalReferences("missing"); len(got) != 0 {
+		t.Errorf("expected no references for an unknown entry, got %v", got)
+	}
+}
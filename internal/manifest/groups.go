@@ -0,0 +1,147 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// referencePattern matches ${services.<name>.…} and ${components.<name>.…}
+// references in environment values
+var referencePattern = regexp.MustCompile(`\$\{(services|components)\.([^.}]+)[.}]`)
+
+// validateGroups checks that every group member names a service or component
+func (m *WorkbenchManifest) validateGroups() error {
+	for group, members := range m.Groups {
+		if group == "" {
+			return NewValidationError("groups", "group names cannot be empty")
+		}
+		for _, member := range members {
+			if !m.hasMember(member) {
+				return NewValidationError(fmt.Sprintf("groups.%s", group),
+					fmt.Sprintf("'%s' is not a service or component", member))
+			}
+		}
+	}
+	return nil
+}
+
+// hasMember reports whether name is a service or component
+func (m *WorkbenchManifest) hasMember(name string) bool {
+	if _, exists := m.Services[name]; exists {
+		return true
+	}
+	_, exists := m.Components[name]
+	return exists
+}
+
+// GroupNames returns the names of all groups, sorted
+func (m *WorkbenchManifest) GroupNames() []string {
+	names := make([]string, 0, len(m.Groups))
+	for name := range m.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GroupMembers returns the services and components in the named group
+func (m *WorkbenchManifest) GroupMembers(group string) ([]string, error) {
+	members, exists := m.Groups[group]
+	if !exists {
+		available := "none defined"
+		if len(m.Groups) > 0 {
+			available = strings.Join(m.GroupNames(), ", ")
+		}
+		return nil, NewValidationError("groups", fmt.Sprintf("group '%s' not found (available: %s)", group, available))
+	}
+	return append([]string(nil), members...), nil
+}
+
+// RemoveFromGroups removes a deleted service or component from every group
+func (m *WorkbenchManifest) RemoveFromGroups(name string) {
+	for group, members := range m.Groups {
+		kept := members[:0:0]
+		for _, member := range members {
+			if member != name {
+				kept = append(kept, member)
+			}
+		}
+		m.Groups[group] = kept
+	}
+}
+
+// References returns the sorted names of the services and components that the
+// environment variables of the named service or component refer to, the
+// services and libraries a service consumes, and those it depends on
+func (m *WorkbenchManifest) References(name string) []string {
+	environment := m.Components[name].Environment
+	var consumes []string
+	if service, exists := m.Services[name]; exists {
+		environment = service.Environment
+		consumes = append(append(consumes, service.Consumes...), service.Libraries...)
+		consumes = append(consumes, dependencyNames(service.DependsOn)...)
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	add := func(referenced string) {
+		if referenced != name && !seen[referenced] {
+			seen[referenced] = true
+			names = append(names, referenced)
+		}
+	}
+	for _, value := range environment {
+		for _, match := range referencePattern.FindAllStringSubmatch(value, -1) {
+			add(match[2])
+		}
+	}
+	for _, consumed := range consumes {
+		add(consumed)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Subset returns a copy of m containing only the named services and
+// components, plus every service and component they reference from their
+// environment variables, so that the result still resolves on its own
+func (m *WorkbenchManifest) Subset(names []string) (*WorkbenchManifest, error) {
+	selected := make(map[string]bool)
+	queue := append([]string(nil), names...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if selected[name] {
+			continue
+		}
+		if !m.hasMember(name) {
+			return nil, NewValidationError("", fmt.Sprintf("'%s' is not a service or component", name))
+		}
+		selected[name] = true
+		queue = append(queue, m.References(name)...)
+	}
+
+	subset := m.Clone()
+	for name := range subset.Services {
+		if !selected[name] {
+			delete(subset.Services, name)
+		}
+	}
+	for name := range subset.Components {
+		if !selected[name] {
+			delete(subset.Components, name)
+		}
+	}
+	for group, members := range subset.Groups {
+		var kept []string
+		for _, member := range members {
+			if selected[member] {
+				kept = append(kept, member)
+			}
+		}
+		subset.Groups[group] = kept
+	}
+	return subset, nil
+}
@@ -0,0 +1,124 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func groupsManifest() *WorkbenchManifest {
+	return &WorkbenchManifest{
+		Metadata: ProjectMetadata{Name: "demo"},
+		Components: map[string]Component{
+			"gateway": {Path: "gateway"},
+		},
+		Services: map[string]Service{
+			"web":    {Path: "web", Environment: map[string]string{"API_URL": "http://${services.api.host}:8080"}},
+			"api":    {Path: "api", Environment: map[string]string{"DB_URL": "${services.db.resources.main.url}"}},
+			"db":     {Path: "db"},
+			"worker": {Path: "worker"},
+		},
+		Groups: map[string][]string{
+			"frontend": {"web", "gateway"},
+			"data":     {"api", "db"},
+		},
+	}
+}
+
+func TestValidate_Groups(t *testing.T) {
+	m := groupsManifest()
+	if err := m.Validate(); err != nil {
+		t.Fatalf("expected valid groups, got %v", err)
+	}
+
+	m.Groups["broken"] = []string{"web", "missing"}
+	err := m.Validate()
+	if !IsManifestError(err, ErrorTypeValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if field := err.(*ManifestError).Field; field != "groups.broken" {
+		t.Errorf("expected field groups.broken, got %q", field)
+	}
+}
+
+func TestGroupMembers(t *testing.T) {
+	m := groupsManifest()
+
+	members, err := m.GroupMembers("data")
+	if err != nil {
+		t.Fatalf("GroupMembers failed: %v", err)
+	}
+	if !reflect.DeepEqual(members, []string{"api", "db"}) {
+		t.Errorf("unexpected members: %v", members)
+	}
+
+	if _, err := m.GroupMembers("backend"); !IsManifestError(err, ErrorTypeValidation) {
+		t.Errorf("expected validation error for unknown group, got %v", err)
+	}
+}
+
+func TestSubset_IncludesReferences(t *testing.T) {
+	m := groupsManifest()
+
+	subset, err := m.Subset([]string{"web", "gateway"})
+	if err != nil {
+		t.Fatalf("Subset failed: %v", err)
+	}
+
+	var services []string
+	for name := range subset.Services {
+		services = append(services, name)
+	}
+	if len(services) != 3 || subset.Services["worker"].Path != "" {
+		t.Errorf("expected web and its dependencies api and db, got %v", services)
+	}
+	if len(subset.Components) != 1 {
+		t.Errorf("expected gateway component, got %v", subset.Components)
+	}
+	if len(m.Services) != 4 {
+		t.Error("Subset modified the original manifest")
+	}
+	if err := subset.Validate(); err != nil {
+		t.Errorf("expected subset to be valid, got %v", err)
+	}
+
+	if _, err := m.Subset([]string{"missing"}); err == nil {
+		t.Error("expected error for unknown name")
+	}
+}
+
+func TestReferences(t *testing.T) {
+	m := groupsManifest()
+	m.Components["gateway"] = Component{Path: "gateway", Environment: map[string]string{
+		"UPSTREAMS": "${services.web.name},${services.api.name},${services.web.port}",
+	}}
+
+	if got := m.References("gateway"); !reflect.DeepEqual(got, []string{"api", "web"}) {
+		t.Errorf("expected gateway to reference api and web once each, got %v", got)
+	}
+	if got := m.References("web"); !reflect.DeepEqual(got, []string{"api"}) {
+		t.Errorf("expected web to reference api, got %v", got)
+	}
+	if got := m.References("worker"); len(got) != 0 {
+		t.Errorf("expected no references, got %v", got)
+	}
+
+	worker := m.Services["worker"]
+	worker.Consumes = []string{"api"}
+	m.Services["worker"] = worker
+	if got := m.References("worker"); !reflect.DeepEqual(got, []string{"api"}) {
+		t.Errorf("expected consumed services to be referenced, got %v", got)
+	}
+}
+
+func TestRemoveFromGroups(t *testing.T) {
+	m := groupsManifest()
+	delete(m.Services, "db")
+	m.RemoveFromGroups("db")
+
+	if !reflect.DeepEqual(m.Groups["data"], []string{"api"}) {
+		t.Errorf("expected db to be removed from data, got %v", m.Groups["data"])
+	}
+	if err := m.Validate(); err != nil {
+		t.Errorf("expected valid manifest after removal, got %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// IncludeDir is the directory next to workbench.yaml whose *.yaml files are
+// merged into the manifest automatically
+const IncludeDir = "workbench.d"
+
+// Fragment is the content of an included manifest file. Fragments can define
+// environments, components, and services; project metadata stays in
+// workbench.yaml.
+type Fragment struct {
+	Environments map[string]Environment `yaml:"environments,omitempty"`
+	Components   map[string]Component   `yaml:"components,omitempty"`
+	Services     map[string]Service     `yaml:"services,omitempty"`
+}
+
+// sourceKey identifies a manifest entry in WorkbenchManifest.sources
+func sourceKey(section, name string) string {
+	return section + "." + name
+}
+
+// Source returns the included file, relative to the directory of
+// workbench.yaml, that defines the named entry of a section ("services",
+// "components", or "environments"), or "" when the entry lives in
+// workbench.yaml itself
+func (m *WorkbenchManifest) Source(section, name string) string {
+	return m.sources[sourceKey(section, name)]
+}
+
+// Load reads, parses, and validates the manifest at path together with any
+// files it includes, without caching
+func Load(fsys filesystem.FS, path string) (*WorkbenchManifest, error) {
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workbench.yaml: %w", err)
+	}
+	manifest, _, err := loadWithIncludes(fsys, path, data)
+	return manifest, err
+}
+
+// IncludedFiles returns the files the include patterns of the manifest at
+// path currently match
+func IncludedFiles(fsys filesystem.FS, path string, manifest *WorkbenchManifest) ([]string, error) {
+	return resolveIncludes(fsys, path, manifest.Include)
+}
+
+// loadWithIncludes parses the root manifest, merges in every included
+// fragment, and validates the result. It also returns the raw content of each
+// fragment so callers can detect changes.
+func loadWithIncludes(fsys filesystem.FS, path string, data []byte) (*WorkbenchManifest, map[string][]byte, error) {
+	manifest, err := decode(data, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files, err := resolveIncludes(fsys, path, manifest.Include)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fragments := make(map[string][]byte, len(files))
+	for _, file := range files {
+		content, err := fsys.ReadFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read included file %s: %w", file, err)
+		}
+		fragments[file] = content
+
+		var fragment Fragment
+		if err := yaml.Unmarshal(content, &fragment); err != nil {
+			return nil, nil, NewParseError(file, err)
+		}
+		rel, err := filepath.Rel(filepath.Dir(path), file)
+		if err != nil {
+			rel = file
+		}
+		if err := manifest.mergeFragment(rel, &fragment); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := manifest.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return manifest, fragments, nil
+}
+
+// mergeFragment adds the entries of an included file, rejecting duplicates
+func (m *WorkbenchManifest) mergeFragment(file string, fragment *Fragment) error {
+	if m.sources == nil {
+		m.sources = make(map[string]string)
+	}
+
+	duplicate := func(section, name string) error {
+		where := m.sources[sourceKey(section, name)]
+		if where == "" {
+			where = "workbench.yaml"
+		}
+		return NewValidationError(fmt.Sprintf("%s.%s", section, name),
+			fmt.Sprintf("defined in both %s and %s", where, file))
+	}
+
+	for name, env := range fragment.Environments {
+		if _, exists := m.Environments[name]; exists {
+			return duplicate("environments", name)
+		}
+		if m.Environments == nil {
+			m.Environments = make(map[string]Environment)
+		}
+		m.Environments[name] = env
+		m.sources[sourceKey("environments", name)] = file
+	}
+	for name, component := range fragment.Components {
+		if _, exists := m.Components[name]; exists {
+			return duplicate("components", name)
+		}
+		if m.Components == nil {
+			m.Components = make(map[string]Component)
+		}
+		m.Components[name] = component
+		m.sources[sourceKey("components", name)] = file
+	}
+	for name, service := range fragment.Services {
+		if _, exists := m.Services[name]; exists {
+			return duplicate("services", name)
+		}
+		if m.Services == nil {
+			m.Services = make(map[string]Service)
+		}
+		m.Services[name] = service
+		m.sources[sourceKey("services", name)] = file
+	}
+	return nil
+}
+
+// resolveIncludes expands the include patterns of the manifest at path and
+// adds the files in IncludeDir. Patterns are relative to the manifest's
+// directory and may use wildcards in their final element. The result is
+// sorted and free of duplicates.
+func resolveIncludes(fsys filesystem.FS, path string, patterns []string) ([]string, error) {
+	dir := filepath.Dir(path)
+	seen := make(map[string]bool)
+	var files []string
+	add := func(file string) {
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, pattern := range patterns {
+		if filepath.IsAbs(pattern) || strings.HasPrefix(filepath.Clean(pattern), "..") {
+			return nil, NewValidationError("include", fmt.Sprintf("%q must be a path inside the project", pattern))
+		}
+		full := filepath.Join(dir, pattern)
+		if !strings.ContainsAny(pattern, "*?[") {
+			if !filesystem.Exists(fsys, full) {
+				return nil, NewValidationError("include", fmt.Sprintf("included file %s does not exist", pattern))
+			}
+			add(full)
+			continue
+		}
+		matches, err := matchFiles(fsys, filepath.Dir(full), filepath.Base(full))
+		if err != nil {
+			return nil, NewValidationError("include", fmt.Sprintf("invalid pattern %q: %v", pattern, err))
+		}
+		for _, match := range matches {
+			add(match)
+		}
+	}
+
+	matches, err := matchFiles(fsys, filepath.Join(dir, IncludeDir), "*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	ymlMatches, err := matchFiles(fsys, filepath.Join(dir, IncludeDir), "*.yml")
+	if err != nil {
+		return nil, err
+	}
+	automatic := append(matches, ymlMatches...)
+	sort.Strings(automatic)
+	for _, match := range automatic {
+		add(match)
+	}
+	return files, nil
+}
+
+// matchFiles returns the regular files in dir whose names match pattern. A
+// missing directory matches nothing.
+func matchFiles(fsys filesystem.FS, dir, pattern string) ([]string, error) {
+	entries, err := fsys.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var matches []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ok, err := filepath.Match(pattern, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matches = append(matches, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return matches, nil
+}
+
+// split divides m into the part stored in workbench.yaml and one Fragment per
+// included file, following the recorded source of each entry. Entries added
+// since loading have no source and stay in workbench.yaml. Fragment paths are
+// relative to the directory of workbench.yaml.
+func (m *WorkbenchManifest) split() (*WorkbenchManifest, map[string]*Fragment) {
+	root := *m
+	root.Environments = nil
+	root.Components = nil
+	root.Services = make(map[string]Service)
+
+	fragments := make(map[string]*Fragment)
+	for _, file := range m.sources {
+		if _, exists := fragments[file]; !exists {
+			fragments[file] = &Fragment{}
+		}
+	}
+
+	for name, env := range m.Environments {
+		if file := m.Source("environments", name); file != "" {
+			if fragments[file].Environments == nil {
+				fragments[file].Environments = make(map[string]Environment)
+			}
+			fragments[file].Environments[name] = env
+			continue
+		}
+		if root.Environments == nil {
+			root.Environments = make(map[string]Environment)
+		}
+		root.Environments[name] = env
+	}
+	for name, component := range m.Components {
+		if file := m.Source("components", name); file != "" {
+			if fragments[file].Components == nil {
+				fragments[file].Components = make(map[string]Component)
+			}
+			fragments[file].Components[name] = component
+			continue
+		}
+		if root.Components == nil {
+			root.Components = make(map[string]Component)
+		}
+		root.Components[name] = component
+	}
+	for name, service := range m.Services {
+		if file := m.Source("services", name); file != "" {
+			if fragments[file].Services == nil {
+				fragments[file].Services = make(map[string]Service)
+			}
+			fragments[file].Services[name] = service
+			continue
+		}
+		root.Services[name] = service
+	}
+	return &root, fragments
+}
@@ -0,0 +1,142 @@
+package manifest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// writeFiles creates the given files, and their directories, on fsys
+func writeFiles(t *testing.T, fsys filesystem.FS, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := fsys.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsys.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoad_Includes(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	writeFiles(t, fsys, map[string]string{
+		"workbench.yaml":                          "metadata:\n  name: demo\ninclude:\n  - domains/*.yaml\nservices:\n  web:\n    path: web\n",
+		filepath.Join("domains", "billing.yaml"):  "services:\n  billing:\n    path: billing\n",
+		filepath.Join("domains", "notes.txt"):     "not a manifest",
+		filepath.Join(IncludeDir, "gateway.yaml"): "components:\n  gateway:\n    path: gateway\n",
+	})
+
+	m, err := Load(fsys, "workbench.yaml")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(m.Services) != 2 || m.Services["billing"].Path != "billing" {
+		t.Errorf("expected merged services, got %+v", m.Services)
+	}
+	if m.Components["gateway"].Path != "gateway" {
+		t.Errorf("expected component from %s, got %+v", IncludeDir, m.Components)
+	}
+	if got := m.Source("services", "billing"); got != filepath.Join("domains", "billing.yaml") {
+		t.Errorf("unexpected source for billing: %q", got)
+	}
+	if got := m.Source("services", "web"); got != "" {
+		t.Errorf("expected web to come from workbench.yaml, got %q", got)
+	}
+}
+
+func TestLoad_IncludeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		field string
+	}{
+		{
+			name: "duplicate service",
+			files: map[string]string{
+				"workbench.yaml":                      "metadata:\n  name: demo\nservices:\n  api:\n    path: api\n",
+				filepath.Join(IncludeDir, "api.yaml"): "services:\n  api:\n    path: other\n",
+			},
+			field: "services.api",
+		},
+		{
+			name: "missing include",
+			files: map[string]string{
+				"workbench.yaml": "metadata:\n  name: demo\ninclude: [missing.yaml]\n",
+			},
+			field: "include",
+		},
+		{
+			name: "include outside project",
+			files: map[string]string{
+				"workbench.yaml": "metadata:\n  name: demo\ninclude: [../shared.yaml]\n",
+			},
+			field: "include",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := filesystem.NewMemFS()
+			writeFiles(t, fsys, tt.files)
+
+			_, err := Load(fsys, "workbench.yaml")
+			if !IsManifestError(err, ErrorTypeValidation) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			if field := err.(*ManifestError).Field; field != tt.field {
+				t.Errorf("expected field %q, got %q", tt.field, field)
+			}
+		})
+	}
+}
+
+func TestSave_WritesToFragments(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	fragmentPath := filepath.Join(IncludeDir, "billing.yaml")
+	writeFiles(t, fsys, map[string]string{
+		"workbench.yaml": "metadata:\n  name: demo\nservices:\n  web:\n    path: web\n",
+		fragmentPath:     "# Billing domain\nservices:\n  billing:\n    path: billing\n  invoices:\n    path: invoices\n",
+	})
+
+	loader := NewLoader(fsys)
+	m, err := loader.Load("workbench.yaml")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Modify an included service, delete another, and add a new one
+	billing := m.Services["billing"]
+	billing.Port = 9000
+	m.Services["billing"] = billing
+	delete(m.Services, "invoices")
+	m.Services["api"] = Service{Path: "api"}
+
+	if err := Save(fsys, "workbench.yaml", m); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	root, _ := fsys.ReadFile("workbench.yaml")
+	fragment, _ := fsys.ReadFile(fragmentPath)
+	if strings.Contains(string(root), "billing") || !strings.Contains(string(root), "api:") {
+		t.Errorf("unexpected workbench.yaml:\n%s", root)
+	}
+	if !strings.HasPrefix(string(fragment), "# Billing domain") ||
+		!strings.Contains(string(fragment), "port: 9000") ||
+		strings.Contains(string(fragment), "invoices") {
+		t.Errorf("unexpected fragment:\n%s", fragment)
+	}
+
+	// The loader notices the changed fragment
+	reloaded, err := loader.Load("workbench.yaml")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if reloaded.Services["billing"].Port != 9000 || len(reloaded.Services) != 3 {
+		t.Errorf("unexpected reloaded services: %+v", reloaded.Services)
+	}
+}
@@ -0,0 +1,127 @@
+package manifest
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// infraReferencePattern matches ${infra.<output>} references
+var infraReferencePattern = regexp.MustCompile(`\$\{infra\.([^}]+)\}`)
+
+// envReferencePattern matches ${env.<key>} references
+var envReferencePattern = regexp.MustCompile(`\$\{env\.([^}]+)\}`)
+
+// Keys every environment defines for ${env.<key>}; any other key is looked
+// up in the environment's config
+const (
+	EnvKeyName     = "name"
+	EnvKeyProvider = "provider"
+	EnvKeyRegion   = "region"
+)
+
+// validateInterpolation checks the ${infra.*} and ${env.*} references of
+// every environment variable: infra outputs must be declared under infra,
+// and every environment must define the keys referenced with env
+func (m *WorkbenchManifest) validateInterpolation() error {
+	for _, name := range slices.Sorted(maps.Keys(m.Infra)) {
+		if strings.Contains(m.Infra[name], "${infra.") {
+			return NewValidationError("infra."+name, "the local value of an infra output cannot refer to other infra outputs")
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(m.Services)) {
+		service := m.Services[name]
+		if err := m.validateReferences("services."+name, service.Environment); err != nil {
+			return err
+		}
+		for _, resourceName := range slices.Sorted(maps.Keys(service.Resources)) {
+			entry := fmt.Sprintf("services.%s.resources.%s", name, resourceName)
+			if err := m.validateReferences(entry, service.Resources[resourceName].Environment); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.Components)) {
+		if err := m.validateReferences("components."+name, m.Components[name].Environment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateReferences checks the infra and env references of one entry's
+// environment variables
+func (m *WorkbenchManifest) validateReferences(entry string, environment map[string]string) error {
+	for _, key := range slices.Sorted(maps.Keys(environment)) {
+		field := fmt.Sprintf("%s.environment.%s", entry, key)
+		for _, match := range infraReferencePattern.FindAllStringSubmatch(environment[key], -1) {
+			if _, exists := m.Infra[match[1]]; !exists {
+				return NewValidationError(field, fmt.Sprintf("infra output '%s' is not declared under infra", match[1]))
+			}
+		}
+		for _, match := range envReferencePattern.FindAllStringSubmatch(environment[key], -1) {
+			if len(m.Environments) == 0 {
+				return NewValidationError(field, fmt.Sprintf("'${env.%s}' needs an environment, and none are defined", match[1]))
+			}
+			for _, env := range slices.Sorted(maps.Keys(m.Environments)) {
+				if _, defined := m.Environments[env].Value(env, match[1]); !defined {
+					return NewValidationError(field, fmt.Sprintf("environment '%s' does not define '%s'", env, match[1]))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Value returns the value of ${env.<key>} in the environment called name:
+// its name, provider, or region, or else the key of its config
+func (e Environment) Value(name, key string) (string, bool) {
+	switch key {
+	case EnvKeyName:
+		return name, true
+	case EnvKeyProvider:
+		return e.Provider, true
+	case EnvKeyRegion:
+		return e.Region, e.Region != ""
+	}
+	value, exists := e.Config[key]
+	return value, exists
+}
+
+// ResolveEnvReferences replaces ${env.<key>} references in value with the
+// values of the named environment. Without an environment the references
+// are kept, so callers can tell the value is not resolved.
+func (m *WorkbenchManifest) ResolveEnvReferences(value, env string) string {
+	environment, exists := m.Environments[env]
+	if env == "" || !exists {
+		return value
+	}
+	return envReferencePattern.ReplaceAllStringFunc(value, func(match string) string {
+		resolved, defined := environment.Value(env, envReferencePattern.FindStringSubmatch(match)[1])
+		if !defined {
+			return match
+		}
+		return resolved
+	})
+}
+
+// ResolveLocalInfraReferences replaces ${infra.<output>} references in value
+// with the local stand-in declared under infra, which may itself refer to
+// local services and resources
+func (m *WorkbenchManifest) ResolveLocalInfraReferences(value string) string {
+	return infraReferencePattern.ReplaceAllStringFunc(value, func(match string) string {
+		local, exists := m.Infra[infraReferencePattern.FindStringSubmatch(match)[1]]
+		if !exists {
+			return match
+		}
+		return local
+	})
+}
+
+// HasEnvReferences reports whether value still holds ${env.<key>} references
+func HasEnvReferences(value string) bool {
+	return envReferencePattern.MatchString(value)
+}
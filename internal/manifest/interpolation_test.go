@@ -0,0 +1,107 @@
+package manifest
+
+import "testing"
+
+func interpolationManifest() *WorkbenchManifest {
+	return &WorkbenchManifest{
+		Metadata: ProjectMetadata{Name: "demo"},
+		Environments: map[string]Environment{
+			"dev":  {Provider: "aws", Region: "eu-west-1", Config: map[string]string{"logLevel": "debug"}},
+			"prod": {Provider: "aws", Region: "us-east-1", Config: map[string]string{"logLevel": "warn"}},
+		},
+		Infra: map[string]string{
+			"alb_dns_name": "localhost",
+			"db_endpoint":  "${services.api.resources.db.name}",
+		},
+		Services: map[string]Service{
+			"api": {Path: "api", Environment: map[string]string{
+				"DATABASE_HOST": "${infra.db_endpoint}",
+				"LOG_LEVEL":     "${env.logLevel}",
+				"STAGE":         "${env.name}-${env.region}",
+			}},
+		},
+		Components: map[string]Component{
+			"proxy": {Path: "proxy", Environment: map[string]string{"PUBLIC_HOST": "${infra.alb_dns_name}"}},
+		},
+	}
+}
+
+func TestValidate_Interpolation(t *testing.T) {
+	if err := interpolationManifest().Validate(); err != nil {
+		t.Fatalf("expected valid references, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(m *WorkbenchManifest)
+		field  string
+	}{
+		{
+			name:   "undeclared infra output",
+			modify: func(m *WorkbenchManifest) { m.Services["api"].Environment["BUCKET"] = "${infra.assets_bucket}" },
+			field:  "services.api.environment.BUCKET",
+		},
+		{
+			name:   "infra output referring to another",
+			modify: func(m *WorkbenchManifest) { m.Infra["api_url"] = "https://${infra.alb_dns_name}" },
+			field:  "infra.api_url",
+		},
+		{
+			name:   "key one environment lacks",
+			modify: func(m *WorkbenchManifest) { m.Environments["staging"] = Environment{Provider: "aws"} },
+			field:  "services.api.environment.LOG_LEVEL",
+		},
+		{
+			name:   "env reference without environments",
+			modify: func(m *WorkbenchManifest) { m.Environments = nil },
+			field:  "services.api.environment.LOG_LEVEL",
+		},
+		{
+			name:   "component reference",
+			modify: func(m *WorkbenchManifest) { m.Components["proxy"].Environment["ZONE"] = "${env.zone}" },
+			field:  "components.proxy.environment.ZONE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := interpolationManifest()
+			tt.modify(m)
+			err := m.Validate()
+			if !IsManifestError(err, ErrorTypeValidation) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+			if field := err.(*ManifestError).Field; field != tt.field {
+				t.Errorf("expected field %s, got %q", tt.field, field)
+			}
+		})
+	}
+}
+
+func TestResolveEnvReferences(t *testing.T) {
+	m := interpolationManifest()
+
+	if got := m.ResolveEnvReferences("${env.name}-${env.region}", "prod"); got != "prod-us-east-1" {
+		t.Errorf("expected the built-in keys of prod, got %q", got)
+	}
+	if got := m.ResolveEnvReferences("level=${env.logLevel}", "dev"); got != "level=debug" {
+		t.Errorf("expected the config of dev, got %q", got)
+	}
+	if got := m.ResolveEnvReferences("${env.logLevel}", ""); got != "${env.logLevel}" || !HasEnvReferences(got) {
+		t.Errorf("expected references to stay without an environment, got %q", got)
+	}
+	if got := m.ResolveEnvReferences("${env.missing}", "dev"); got != "${env.missing}" {
+		t.Errorf("expected undefined keys to stay, got %q", got)
+	}
+}
+
+func TestResolveLocalInfraReferences(t *testing.T) {
+	m := interpolationManifest()
+
+	if got := m.ResolveLocalInfraReferences("${infra.db_endpoint}:5432"); got != "${services.api.resources.db.name}:5432" {
+		t.Errorf("expected the local stand-in, got %q", got)
+	}
+	if got := m.ResolveLocalInfraReferences("${infra.unknown}"); got != "${infra.unknown}" {
+		t.Errorf("expected undeclared outputs to stay, got %q", got)
+	}
+}
@@ -0,0 +1,119 @@
+package manifest
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Layout holds the project's conventions for where new services live, e.g.
+//
+//	layout:
+//	  services: apps
+//
+// puts a service added as "backend" at apps/backend, as monorepos usually do
+type Layout struct {
+	Services string `yaml:"services,omitempty"` // directory new services are created in, relative to the project root
+}
+
+// ServicePath returns the project-relative path, with forward slashes, where
+// a new service of the given name is created by default
+func (m *WorkbenchManifest) ServicePath(name string) string {
+	if m.Layout.Services == "" {
+		return name
+	}
+	return path.Join(filepath.ToSlash(m.Layout.Services), name)
+}
+
+// validateLayout checks that the layout directories stay inside the project
+func (m *WorkbenchManifest) validateLayout() error {
+	if m.Layout.Services != "" && !IsProjectPath(m.Layout.Services) {
+		return NewValidationError("layout.services", "must be a relative path inside the project")
+	}
+	return nil
+}
+
+// validatePaths checks that every path in the manifest stays inside the
+// project, so that no command reads, generates, or deletes files outside it.
+// Entries are checked in name order so the same manifest always reports the
+// same entry.
+func (m *WorkbenchManifest) validatePaths() error {
+	for _, name := range slices.Sorted(maps.Keys(m.Services)) {
+		service := m.Services[name]
+		if service.Path != "" && !IsProjectPath(service.Path) {
+			return m.pathError("services", name, "path", service.Path)
+		}
+		if spec := service.APISpecPath(); spec != "" && !IsProjectPath(spec) {
+			return m.pathError("services", name, "api.spec", service.API.Spec)
+		}
+		for _, resourceName := range slices.Sorted(maps.Keys(service.Resources)) {
+			config := service.Resources[resourceName].Config
+			for _, key := range slices.Sorted(maps.Keys(config)) {
+				if hostPathConfigKeys[key] && escapesProject(config[key]) {
+					return m.pathError("services", name, fmt.Sprintf("resources.%s.config.%s", resourceName, key), config[key])
+				}
+			}
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.Components)) {
+		if component := m.Components[name]; component.Path != "" && !IsProjectPath(component.Path) {
+			return m.pathError("components", name, "path", component.Path)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.External)) {
+		if spec := m.External[name].Spec; spec != "" && !IsProjectPath(spec) {
+			return m.pathError("external", name, "spec", spec)
+		}
+	}
+	return nil
+}
+
+// pathError reports a path that leaves the project, naming the included
+// file the entry comes from
+func (m *WorkbenchManifest) pathError(section, name, field, value string) error {
+	message := fmt.Sprintf("'%s' must be a relative path inside the project", value)
+	if source := m.Source(section, name); source != "" {
+		message += fmt.Sprintf(" (defined in %s)", source)
+	}
+	return NewValidationError(fmt.Sprintf("%s.%s.%s", section, name, field), message)
+}
+
+// hostPathConfigKeys are the resource config keys whose values name files or
+// directories on the host. Other settings, such as a mount point inside the
+// container (/data) or a health check route (/health), are not checked.
+var hostPathConfigKeys = map[string]bool{
+	"initScripts": true,
+	"configFile":  true,
+	"dataDir":     true,
+	"hostPath":    true,
+}
+
+// escapesProject reports whether a resource setting naming a host path is
+// absolute or climbs out with "..". URLs are never rejected.
+func escapesProject(value string) bool {
+	if strings.Contains(value, "://") {
+		return false
+	}
+	if filepath.IsAbs(value) || strings.HasPrefix(value, "/") || strings.HasPrefix(value, `\`) {
+		return true
+	}
+	for _, segment := range strings.FieldsFunc(value, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if segment == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// IsProjectPath reports whether p is a relative path that stays inside the
+// project root
+func IsProjectPath(p string) bool {
+	if p == "" || filepath.IsAbs(p) || strings.HasPrefix(p, "/") || strings.HasPrefix(p, `\`) {
+		return false
+	}
+	clean := path.Clean(filepath.ToSlash(p))
+	return clean != "." && clean != ".." && !strings.HasPrefix(clean, "../")
+}
@@ -0,0 +1,64 @@
+package manifest
+
+import "testing"
+
+func TestServicePath(t *testing.T) {
+	manifest := &WorkbenchManifest{}
+	if got := manifest.ServicePath("api"); got != "api" {
+		t.Errorf("expected api at the project root, got %q", got)
+	}
+	manifest.Layout.Services = "./apps/"
+	if got := manifest.ServicePath("api"); got != "apps/api" {
+		t.Errorf("expected apps/api, got %q", got)
+	}
+}
+
+func TestIsProjectPath(t *testing.T) {
+	tests := map[string]bool{
+		"apps":         true,
+		"apps/backend": true,
+		"./services":   true,
+		"":             false,
+		".":            false,
+		"..":           false,
+		"../apps":      false,
+		"apps/../..":   false,
+		"/srv/apps":    false,
+	}
+	for path, want := range tests {
+		if got := IsProjectPath(path); got != want {
+			t.Errorf("IsProjectPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestValidate_Layout(t *testing.T) {
+	manifest := &WorkbenchManifest{
+		Metadata: ProjectMetadata{Name: "demo"},
+		Layout:   Layout{Services: "../elsewhere"},
+	}
+	if err := manifest.Validate(); err == nil {
+		t.Error("expected a layout outside the project to be rejected")
+	}
+}
+
+func TestValidate_ResourceConfigPaths(t *testing.T) {
+	manifest := &WorkbenchManifest{
+		Metadata: ProjectMetadata{Name: "demo"},
+		Services: map[string]Service{"api": {
+			Path: "api",
+			Resources: map[string]Resource{"db": {
+				Type:   "postgres-db",
+				Config: map[string]string{"mountPath": "/data", "healthPath": "/health", "initScripts": "./db/init"},
+			}},
+		}},
+	}
+	if err := manifest.Validate(); err != nil {
+		t.Errorf("expected container paths and routes in resource config to load, got %v", err)
+	}
+
+	manifest.Services["api"].Resources["db"].Config["initScripts"] = "/etc/postgres"
+	if err := manifest.Validate(); err == nil {
+		t.Error("expected an absolute host path in resource config to be rejected")
+	}
+}
@@ -0,0 +1,57 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lifecycle hooks a project can declare. Pre hooks run before the command
+// changes anything and stop it when they fail; post hooks run once it has
+// finished.
+const (
+	HookPreCompose       = "preCompose"
+	HookPostCompose      = "postCompose"
+	HookPreAddService    = "preAddService"
+	HookPostAddService   = "postAddService"
+	HookPreAddComponent  = "preAddComponent"
+	HookPostAddComponent = "postAddComponent"
+)
+
+// LifecycleHooks lists every lifecycle hook, in the order they are documented
+var LifecycleHooks = []string{
+	HookPreCompose, HookPostCompose,
+	HookPreAddService, HookPostAddService,
+	HookPreAddComponent, HookPostAddComponent,
+}
+
+// validateHooks checks the project's lifecycle hooks, e.g.
+//
+//	hooks:
+//	  preCompose:
+//	    - ./scripts/generate-clients.sh
+//
+// Every hook must be a known one, and scripts named by path must live inside
+// the project, so a hook cannot reach for files the project does not own.
+func (m *WorkbenchManifest) validateHooks() error {
+	known := make(map[string]bool, len(LifecycleHooks))
+	for _, name := range LifecycleHooks {
+		known[name] = true
+	}
+	for name, commands := range m.Hooks {
+		field := "hooks." + name
+		if !known[name] {
+			return NewValidationError(field, fmt.Sprintf("unknown hook (use %s)", strings.Join(LifecycleHooks, ", ")))
+		}
+		for i, command := range commands {
+			fields := strings.Fields(command)
+			if len(fields) == 0 {
+				return NewValidationError(fmt.Sprintf("%s[%d]", field, i), "command cannot be empty")
+			}
+			if strings.ContainsAny(fields[0], `/\`) && !IsProjectPath(fields[0]) {
+				return NewValidationError(fmt.Sprintf("%s[%d]", field, i),
+					fmt.Sprintf("script '%s' must be a relative path inside the project", fields[0]))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+)
+
+// Lint returns warnings for parts of the manifest that work but are likely
+// mistakes: resources whose version is not pinned, and container services
+// whose Dockerfile under projectRoot declares no HEALTHCHECK. Services
+// without a Dockerfile, including those running a prebuilt image, are not
+// checked for a health check.
+func (m *WorkbenchManifest) Lint(fsys filesystem.FS, projectRoot string) []warnings.Warning {
+	names := make([]string, 0, len(m.Services))
+	for name := range m.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var found []warnings.Warning
+	for _, name := range names {
+		service := m.Services[name]
+		for resourceName, resource := range service.Resources {
+			version := strings.TrimSpace(resource.Version)
+			if version == "" || version == "latest" {
+				found = append(found, warnings.New(fmt.Sprintf("services.%s.resources.%s", name, resourceName),
+					"version is not pinned; set version so every machine runs the same %s", resource.Type))
+			}
+		}
+
+		if !service.IsContainer() || service.UsesImage() {
+			continue
+		}
+		dockerfile, err := fsys.ReadFile(filepath.Join(projectRoot, service.Path, "Dockerfile"))
+		if err != nil {
+			continue
+		}
+		if !hasHealthcheck(dockerfile) {
+			found = append(found, warnings.New("services."+name,
+				"Dockerfile has no HEALTHCHECK; services that depend on it can start before it is ready"))
+		}
+	}
+	return warnings.Sort(found)
+}
+
+// hasHealthcheck reports whether a Dockerfile declares a HEALTHCHECK other
+// than HEALTHCHECK NONE
+func hasHealthcheck(dockerfile []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(dockerfile))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && strings.EqualFold(fields[0], "HEALTHCHECK") {
+			return len(fields) < 2 || !strings.EqualFold(fields[1], "NONE")
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package manifest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestLint(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	writeFiles(t, fsys, map[string]string{
+		filepath.Join("project", "api", "Dockerfile"):    "FROM python:3.12\nHEALTHCHECK CMD curl -f http://localhost:8000/health\n",
+		filepath.Join("project", "web", "Dockerfile"):    "FROM node:18\nCMD [\"npm\", \"start\"]\n",
+		filepath.Join("project", "worker", "Dockerfile"): "FROM golang:1.22\nHEALTHCHECK NONE\n",
+		filepath.Join("project", "mobile", "Dockerfile"): "FROM node:18\n",
+	})
+
+	m := &WorkbenchManifest{
+		Metadata: ProjectMetadata{Name: "demo"},
+		Services: map[string]Service{
+			"api": {Path: "api", Resources: map[string]Resource{
+				"db":    {Type: "postgres", Version: "16"},
+				"cache": {Type: "redis"},
+			}},
+			"web":    {Path: "web"},
+			"worker": {Path: "worker", Resources: map[string]Resource{"queue": {Type: "rabbitmq", Version: "latest"}}},
+			"mobile": {Path: "mobile", Kind: ServiceKindLocal},
+			"docs":   {Path: "docs"},
+		},
+	}
+
+	want := []string{
+		"services.api.resources.cache: version is not pinned; set version so every machine runs the same redis",
+		"services.web: Dockerfile has no HEALTHCHECK; services that depend on it can start before it is ready",
+		"services.worker: Dockerfile has no HEALTHCHECK; services that depend on it can start before it is ready",
+		"services.worker.resources.queue: version is not pinned; set version so every machine runs the same rabbitmq",
+	}
+	got := m.Lint(fsys, "project")
+	if len(got) != len(want) {
+		t.Fatalf("expected %d warnings, got %v", len(want), got)
+	}
+	for i, warning := range got {
+		if warning.String() != want[i] {
+			t.Errorf("warning %d = %q, want %q", i, warning.String(), want[i])
+		}
+	}
+}
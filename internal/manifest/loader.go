@@ -0,0 +1,411 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// Parse decodes and validates workbench.yaml content. path is only used in
+// error messages.
+func Parse(data []byte, path string) (*WorkbenchManifest, error) {
+	manifest, err := decode(data, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// decode parses workbench.yaml content without validating it
+func decode(data []byte, path string) (*WorkbenchManifest, error) {
+	var manifest WorkbenchManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, NewParseError(path, err)
+	}
+	return &manifest, nil
+}
+
+// Validate checks the structural rules every command relies on
+func (m *WorkbenchManifest) Validate() error {
+	if m.Metadata.Name == "" {
+		return NewValidationError("metadata.name", "project name is required")
+	}
+	if err := m.Mesh.validate(); err != nil {
+		return err
+	}
+	for name, service := range m.Services {
+		if name == "" {
+			return NewValidationError("services", "service names cannot be empty")
+		}
+		switch service.Kind {
+		case "", ServiceKindContainer:
+		case ServiceKindLocal:
+			if service.Dev == "" {
+				return NewValidationError(fmt.Sprintf("services.%s.dev", name), "local services need a dev command to start them")
+			}
+		case ServiceKindLibrary:
+			if service.Path == "" {
+				return NewValidationError(fmt.Sprintf("services.%s.path", name), "libraries need a path")
+			}
+		default:
+			return NewValidationError(fmt.Sprintf("services.%s.kind", name),
+				fmt.Sprintf("unsupported kind '%s' (use container, local, or library)", service.Kind))
+		}
+		if service.UsesImage() {
+			if !service.IsContainer() {
+				return NewValidationError(fmt.Sprintf("services.%s.image", name), "only container services can run an image")
+			}
+			if service.Path != "" {
+				return NewValidationError(fmt.Sprintf("services.%s.image", name),
+					"set either image or path, not both: image runs a prebuilt image, path builds one from its Dockerfile")
+			}
+		}
+		switch service.Protocol {
+		case "", ProtocolHTTP, ProtocolGRPC, ProtocolTCP:
+		default:
+			return NewValidationError(fmt.Sprintf("services.%s.protocol", name),
+				fmt.Sprintf("unsupported protocol '%s' (use http, grpc, or tcp)", service.Protocol))
+		}
+		if err := validateRestart(fmt.Sprintf("services.%s.restart", name), service.Restart); err != nil {
+			return err
+		}
+		if err := service.Ownership.Validate("services." + name); err != nil {
+			return err
+		}
+		if err := validateSecurity("services."+name, service.Security); err != nil {
+			return err
+		}
+		if service.Health != nil {
+			if service.ProtocolOrDefault() != ProtocolHTTP {
+				return NewValidationError(fmt.Sprintf("services.%s.health", name), "only HTTP services have a health endpoint")
+			}
+			if service.Health.Path != "" && !strings.HasPrefix(service.Health.Path, "/") {
+				return NewValidationError(fmt.Sprintf("services.%s.health.path", name), "health path must start with '/'")
+			}
+			if service.Health.Status != 0 && (service.Health.Status < 100 || service.Health.Status > 599) {
+				return NewValidationError(fmt.Sprintf("services.%s.health.status", name),
+					fmt.Sprintf("%d is not an HTTP status code", service.Health.Status))
+			}
+		}
+		if service.GraphQL != nil && !strings.HasPrefix(service.GraphQL.Path, "/") {
+			return NewValidationError(fmt.Sprintf("services.%s.graphql.path", name), "GraphQL path must start with '/'")
+		}
+		if service.API != nil && service.API.Spec == "" {
+			return NewValidationError(fmt.Sprintf("services.%s.api.spec", name), "API spec path is required")
+		}
+		for _, library := range service.Libraries {
+			if !m.Services[library].IsLibrary() {
+				return NewValidationError(fmt.Sprintf("services.%s.libraries", name), fmt.Sprintf("'%s' is not a service of kind library", library))
+			}
+		}
+		for _, consumed := range service.Consumes {
+			provider, exists := m.Services[consumed]
+			switch {
+			case consumed == name:
+				return NewValidationError(fmt.Sprintf("services.%s.consumes", name), "a service cannot consume itself")
+			case !exists:
+				return NewValidationError(fmt.Sprintf("services.%s.consumes", name), fmt.Sprintf("service '%s' is not defined", consumed))
+			case provider.APISpecPath() == "":
+				return NewValidationError(fmt.Sprintf("services.%s.consumes", name), fmt.Sprintf("service '%s' has no api.spec to generate a client from", consumed))
+			}
+		}
+		for resourceName, resource := range service.Resources {
+			if resource.Type == "" {
+				return NewValidationError(fmt.Sprintf("services.%s.resources.%s.type", name, resourceName), "resource type is required")
+			}
+			switch resource.Expose {
+			case "", ExposeInternal, ExposeHost:
+			default:
+				return NewValidationError(fmt.Sprintf("services.%s.resources.%s.expose", name, resourceName),
+					fmt.Sprintf("unsupported expose '%s' (use internal or host)", resource.Expose))
+			}
+			for property := range resource.EnvDocs {
+				if !slices.Contains(ResourceEnvProperties, property) {
+					return NewValidationError(fmt.Sprintf("services.%s.resources.%s.envDocs.%s", name, resourceName, property),
+						fmt.Sprintf("not a variable of the resource (use %s)", strings.Join(ResourceEnvProperties, ", ")))
+				}
+			}
+		}
+		for key := range service.EnvDocs {
+			if _, exists := service.Environment[key]; !exists {
+				return NewValidationError(fmt.Sprintf("services.%s.envDocs.%s", name, key), "documents a variable the service's environment does not set")
+			}
+		}
+	}
+	for name, component := range m.Components {
+		if name == "" {
+			return NewValidationError("components", "component names cannot be empty")
+		}
+		if err := validateRestart(fmt.Sprintf("components.%s.restart", name), component.Restart); err != nil {
+			return err
+		}
+		if err := component.Ownership.Validate("components." + name); err != nil {
+			return err
+		}
+		if err := validateSecurity("components."+name, component.Security); err != nil {
+			return err
+		}
+	}
+	if err := m.validateDependencies(); err != nil {
+		return err
+	}
+	if err := m.validateExternal(); err != nil {
+		return err
+	}
+	if err := m.validateInterpolation(); err != nil {
+		return err
+	}
+	if err := m.validateLayout(); err != nil {
+		return err
+	}
+	if err := m.CodeOwners.validate(); err != nil {
+		return err
+	}
+	if err := m.Secrets.validate(); err != nil {
+		return err
+	}
+	if err := m.validatePaths(); err != nil {
+		return err
+	}
+	if err := m.validateHooks(); err != nil {
+		return err
+	}
+	if err := m.validateEnvironments(); err != nil {
+		return err
+	}
+	return m.validateGroups()
+}
+
+// validateRestart checks that a restart policy is one the generators can map
+func validateRestart(field, restart string) error {
+	switch restart {
+	case "", RestartNo, RestartOnFailure, RestartUnlessStopped:
+		return nil
+	}
+	return NewValidationError(field,
+		fmt.Sprintf("unsupported restart policy '%s' (use no, on-failure, or unless-stopped)", restart))
+}
+
+// validateSecurity checks the security block of the service or component
+// at entry
+func validateSecurity(entry string, security *Security) error {
+	if security == nil {
+		return nil
+	}
+	for _, capability := range security.CapDrop {
+		if strings.TrimSpace(capability) == "" {
+			return NewValidationError(entry+".security.capDrop", "capability names cannot be empty")
+		}
+	}
+	return nil
+}
+
+// Clone returns a deep copy of the manifest, so callers can modify the
+// result without affecting cached copies
+func (m *WorkbenchManifest) Clone() *WorkbenchManifest {
+	clone := *m
+	clone.sources = cloneStrings(m.sources)
+	if m.Include != nil {
+		clone.Include = append([]string(nil), m.Include...)
+	}
+	if m.CodeOwners.Default != nil {
+		clone.CodeOwners.Default = append([]string(nil), m.CodeOwners.Default...)
+	}
+	if m.Hooks != nil {
+		clone.Hooks = make(map[string][]string, len(m.Hooks))
+		for name, commands := range m.Hooks {
+			clone.Hooks[name] = append([]string(nil), commands...)
+		}
+	}
+	if m.Groups != nil {
+		clone.Groups = make(map[string][]string, len(m.Groups))
+		for name, members := range m.Groups {
+			clone.Groups[name] = append([]string(nil), members...)
+		}
+	}
+	clone.Infra = cloneStrings(m.Infra)
+	if m.External != nil {
+		clone.External = make(map[string]External, len(m.External))
+		for name, external := range m.External {
+			external.Environments = cloneStrings(external.Environments)
+			clone.External[name] = external
+		}
+	}
+	if m.Environments != nil {
+		clone.Environments = make(map[string]Environment, len(m.Environments))
+		for name, env := range m.Environments {
+			env.Config = cloneStrings(env.Config)
+			if env.Include != nil {
+				env.Include = append([]string(nil), env.Include...)
+			}
+			if env.Exclude != nil {
+				env.Exclude = append([]string(nil), env.Exclude...)
+			}
+			clone.Environments[name] = env
+		}
+	}
+	if m.Components != nil {
+		clone.Components = make(map[string]Component, len(m.Components))
+		for name, component := range m.Components {
+			if component.Ports != nil {
+				component.Ports = append([]string(nil), component.Ports...)
+			}
+			component.Environment = cloneStrings(component.Environment)
+			if component.Tags != nil {
+				component.Tags = append([]string(nil), component.Tags...)
+			}
+			component.Command = cloneCommand(component.Command)
+			component.Entrypoint = cloneCommand(component.Entrypoint)
+			component.Security = component.Security.clone()
+			clone.Components[name] = component
+		}
+	}
+	if m.Services != nil {
+		clone.Services = make(map[string]Service, len(m.Services))
+		for name, service := range m.Services {
+			service.Environment = cloneStrings(service.Environment)
+			service.EnvDocs = cloneStrings(service.EnvDocs)
+			service.Command = cloneCommand(service.Command)
+			service.Entrypoint = cloneCommand(service.Entrypoint)
+			service.Security = service.Security.clone()
+			if service.Health != nil {
+				health := *service.Health
+				service.Health = &health
+			}
+			if service.GraphQL != nil {
+				graphQL := *service.GraphQL
+				service.GraphQL = &graphQL
+			}
+			if service.API != nil {
+				api := *service.API
+				service.API = &api
+			}
+			if service.Tags != nil {
+				service.Tags = append([]string(nil), service.Tags...)
+			}
+			if service.Consumes != nil {
+				service.Consumes = append([]string(nil), service.Consumes...)
+			}
+			if service.Libraries != nil {
+				service.Libraries = append([]string(nil), service.Libraries...)
+			}
+			if service.DependsOn != nil {
+				service.DependsOn = append([]Dependency(nil), service.DependsOn...)
+			}
+			if service.Resources != nil {
+				resources := make(map[string]Resource, len(service.Resources))
+				for resourceName, resource := range service.Resources {
+					resource.Config = cloneStrings(resource.Config)
+					resource.Environment = cloneStrings(resource.Environment)
+					resource.EnvDocs = cloneStrings(resource.EnvDocs)
+					resources[resourceName] = resource
+				}
+				service.Resources = resources
+			}
+			clone.Services[name] = service
+		}
+	}
+	return &clone
+}
+
+func cloneCommand(command Command) Command {
+	if command == nil {
+		return nil
+	}
+	return append(Command(nil), command...)
+}
+
+func cloneStrings(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	clone := make(map[string]string, len(values))
+	for key, value := range values {
+		clone[key] = value
+	}
+	return clone
+}
+
+// cacheEntry is a parsed manifest together with the bytes it was parsed from
+type cacheEntry struct {
+	data      []byte
+	fragments map[string][]byte
+	manifest  *WorkbenchManifest
+}
+
+// Loader reads and validates workbench.yaml files and the files they include,
+// caching the merged result per path. A cached manifest is reused for as long
+// as the content of every file is unchanged, so commands that load the
+// manifest several times only pay for decoding once. Every call returns an
+// independent copy.
+type Loader struct {
+	mutex sync.Mutex
+	fs    filesystem.FS
+	cache map[string]cacheEntry
+}
+
+// NewLoader creates a Loader that reads from fsys
+func NewLoader(fsys filesystem.FS) *Loader {
+	return &Loader{
+		fs:    fsys,
+		cache: make(map[string]cacheEntry),
+	}
+}
+
+// FS returns the file system the loader reads from
+func (l *Loader) FS() filesystem.FS {
+	return l.fs
+}
+
+// Load reads, parses, and validates the manifest at path
+func (l *Loader) Load(path string) (*WorkbenchManifest, error) {
+	data, err := l.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workbench.yaml: %w", err)
+	}
+
+	l.mutex.Lock()
+	entry, ok := l.cache[path]
+	l.mutex.Unlock()
+	if ok && bytes.Equal(entry.data, data) && l.fragmentsUnchanged(path, entry) {
+		return entry.manifest.Clone(), nil
+	}
+
+	manifest, fragments, err := loadWithIncludes(l.fs, path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mutex.Lock()
+	l.cache[path] = cacheEntry{data: data, fragments: fragments, manifest: manifest}
+	l.mutex.Unlock()
+	return manifest.Clone(), nil
+}
+
+// fragmentsUnchanged reports whether the included files of a cached entry
+// are still the same set of files with the same content
+func (l *Loader) fragmentsUnchanged(path string, entry cacheEntry) bool {
+	files, err := resolveIncludes(l.fs, path, entry.manifest.Include)
+	if err != nil || len(files) != len(entry.fragments) {
+		return false
+	}
+	for _, file := range files {
+		cached, ok := entry.fragments[file]
+		if !ok {
+			return false
+		}
+		content, err := l.fs.ReadFile(file)
+		if err != nil || !bytes.Equal(content, cached) {
+			return false
+		}
+	}
+	return true
+}
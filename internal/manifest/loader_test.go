@@ -0,0 +1,217 @@
+package manifest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// largeManifest builds a manifest with the given number of services, each
+// with a database resource and a few environment variables
+func largeManifest(services int) []byte {
+	manifest := WorkbenchManifest{
+		APIVersion: "openworkbench.io/v1alpha1",
+		Kind:       "Project",
+		Metadata:   ProjectMetadata{Name: "large-project"},
+		Services:   make(map[string]Service, services),
+	}
+	for i := 0; i < services; i++ {
+		name := fmt.Sprintf("service-%03d", i)
+		manifest.Services[name] = Service{
+			Template: "fastapi-basic",
+			Path:     name,
+			Port:     8000 + i,
+			Resources: map[string]Resource{
+				"db": {Type: "postgres-db", Config: map[string]string{"version": "15", "databaseName": "app"}},
+			},
+			Environment: map[string]string{"LOG_LEVEL": "info", "SERVICE_NAME": name},
+		}
+	}
+	data, err := yaml.Marshal(&manifest)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func TestLoader_Load(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	content := "metadata:\n  name: demo\nservices:\n  api:\n    template: demo\n    path: api\n"
+	if err := fsys.WriteFile("workbench.yaml", []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewLoader(fsys)
+	first, err := loader.Load("workbench.yaml")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if first.Metadata.Name != "demo" || first.Services["api"].Path != "api" {
+		t.Errorf("unexpected manifest: %+v", first)
+	}
+
+	// Modifying a loaded manifest must not leak into later loads
+	first.Services["web"] = Service{Path: "web"}
+	second, err := loader.Load("workbench.yaml")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, exists := second.Services["web"]; exists {
+		t.Error("expected cached manifest to be isolated from caller modifications")
+	}
+
+	// Changed content is parsed again
+	if err := fsys.WriteFile("workbench.yaml", []byte("metadata:\n  name: renamed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	third, err := loader.Load("workbench.yaml")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if third.Metadata.Name != "renamed" {
+		t.Errorf("expected reloaded manifest, got name %q", third.Metadata.Name)
+	}
+}
+
+func TestLoader_Errors(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	files := map[string]string{
+		"invalid.yaml":   "metadata: [",
+		"noname.yaml":    "services:\n  api:\n    path: api\n",
+		"resource.yaml":  "metadata:\n  name: demo\nservices:\n  api:\n    resources:\n      db: {}\n",
+		"protocol.yaml":  "metadata:\n  name: demo\nservices:\n  api:\n    protocol: udp\n",
+		"graphql.yaml":   "metadata:\n  name: demo\nservices:\n  api:\n    graphql:\n      path: graphql\n",
+		"health.yaml":    "metadata:\n  name: demo\nservices:\n  api:\n    health:\n      path: /health\n      status: 42\n",
+		"infra.yaml":     "metadata:\n  name: demo\ninfra:\n  bucket: local\nservices:\n  api:\n    environment:\n      BUCKET: ${infra.bucket}\n      QUEUE: ${infra.queue}\n",
+		"rinfra.yaml":    "metadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    resources:\n      db:\n        type: postgres\n        environment:\n          BACKUP_BUCKET: ${infra.bucket}\n",
+		"tags.yaml":      "metadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    tags: [pci, needs review]\n",
+		"team.yaml":      "metadata:\n  name: demo\ncomponents:\n  gateway:\n    path: ./gateway\n    team: payments, platform\nservices:\n  api:\n    path: ./api\n",
+		"owners.yaml":    "metadata:\n  name: demo\ncodeowners:\n  org: acme\n  default: [platform]\nservices:\n  api:\n    path: ./api\n",
+		"expose.yaml":    "metadata:\n  name: demo\nservices:\n  api:\n    resources:\n      db:\n        type: postgres-db\n        expose: public\n",
+		"tcphealth.yaml": "metadata:\n  name: demo\nservices:\n  db:\n    protocol: tcp\n    health:\n      path: /\n",
+		"kind.yaml":      "metadata:\n  name: demo\nservices:\n  api:\n    kind: vm\n",
+		"local.yaml":     "metadata:\n  name: demo\nservices:\n  app:\n    kind: local\n",
+		"image.yaml":     "metadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    image: registry.internal/api:1.0\n",
+		"hostimg.yaml":   "metadata:\n  name: demo\nservices:\n  app:\n    kind: local\n    dev: npm start\n    image: node:20\n",
+		"restart.yaml":   "metadata:\n  name: demo\nservices:\n  api:\n    restart: always\n",
+		"capdrop.yaml":   "metadata:\n  name: demo\nservices:\n  api:\n    security:\n      capDrop: [ALL, \"\"]\n",
+		"crestart.yaml":  "metadata:\n  name: demo\nservices: {}\ncomponents:\n  gateway:\n    restart: sometimes\n",
+		"ccapdrop.yaml":  "metadata:\n  name: demo\nservices: {}\ncomponents:\n  gateway:\n    security:\n      capDrop: [\"\"]\n",
+		"hookname.yaml":  "metadata:\n  name: demo\nservices: {}\nhooks:\n  beforeCompose: [make clients]\n",
+		"consumes.yaml":  "metadata:\n  name: demo\nservices:\n  web:\n    path: ./web\n    consumes: [api]\n  api:\n    path: ./api\n",
+		"hookpath.yaml":  "metadata:\n  name: demo\nservices: {}\nhooks:\n  preCompose: [../shared/generate.sh]\n",
+		"library.yaml":   "metadata:\n  name: demo\nservices:\n  models:\n    kind: library\n",
+		"libraries.yaml": "metadata:\n  name: demo\nservices:\n  web:\n    path: ./web\n    libraries: [api]\n  api:\n    path: ./api\n",
+		"abspath.yaml":   "metadata:\n  name: demo\nservices:\n  api:\n    path: /srv/api\n",
+		"cpath.yaml":     "metadata:\n  name: demo\nservices: {}\ncomponents:\n  gateway:\n    path: gateway/../../gateway\n",
+		"specpath.yaml":  "metadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    api:\n      spec: ../../openapi.yaml\n",
+		"extspec.yaml":   "metadata:\n  name: demo\nservices: {}\nexternal:\n  stripe:\n    url: https://api.stripe.com\n    spec: /tmp/stripe.yaml\n",
+		"rconfig.yaml":   "metadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    resources:\n      db:\n        type: postgres-db\n        config:\n          initScripts: ../../etc\n",
+		"envinc.yaml":    "metadata:\n  name: demo\nservices:\n  api:\n    path: ./api\nenvironments:\n  staging:\n    provider: aws\n    include: [admin]\n",
+		"envboth.yaml":   "metadata:\n  name: demo\nservices:\n  api:\n    path: ./api\nenvironments:\n  prod:\n    provider: aws\n    include: [api]\n    exclude: [api]\n",
+		"secrets.yaml":   "metadata:\n  name: demo\nservices: {}\nsecrets:\n  provider: keychain\n",
+		"vault.yaml":     "metadata:\n  name: demo\nservices: {}\nsecrets:\n  provider: vault\n",
+		"sops.yaml":      "metadata:\n  name: demo\nservices: {}\nsecrets:\n  provider: sops\n  source: ../secrets.enc.yaml\n",
+		"envdocs.yaml":   "metadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    environment:\n      LOG_LEVEL: info\n    envDocs:\n      LOG_LEVEL: How much to log\n      STRIPE_KEY: Stripe API key\n",
+		"renvdocs.yaml":  "metadata:\n  name: demo\nservices:\n  api:\n    path: ./api\n    resources:\n      db:\n        type: postgres\n        envDocs:\n          host: Database host\n",
+	}
+	for name, content := range files {
+		if err := fsys.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		errType ErrorType
+		field   string
+	}{
+		{"parse error", "invalid.yaml", ErrorTypeParse, ""},
+		{"missing name", "noname.yaml", ErrorTypeValidation, "metadata.name"},
+		{"missing resource type", "resource.yaml", ErrorTypeValidation, "services.api.resources.db.type"},
+		{"unsupported protocol", "protocol.yaml", ErrorTypeValidation, "services.api.protocol"},
+		{"relative graphql path", "graphql.yaml", ErrorTypeValidation, "services.api.graphql.path"},
+		{"invalid health status", "health.yaml", ErrorTypeValidation, "services.api.health.status"},
+		{"undeclared infra output", "infra.yaml", ErrorTypeValidation, "services.api.environment.QUEUE"},
+		{"undeclared infra output in a resource", "rinfra.yaml", ErrorTypeValidation, "services.api.resources.db.environment.BACKUP_BUCKET"},
+		{"tag with a space", "tags.yaml", ErrorTypeValidation, "services.api.tags"},
+		{"team with a comma", "team.yaml", ErrorTypeValidation, "components.gateway.team"},
+		{"default code owner without a handle", "owners.yaml", ErrorTypeValidation, "codeowners.default"},
+		{"unsupported resource expose", "expose.yaml", ErrorTypeValidation, "services.api.resources.db.expose"},
+		{"health on tcp service", "tcphealth.yaml", ErrorTypeValidation, "services.db.health"},
+		{"unsupported kind", "kind.yaml", ErrorTypeValidation, "services.api.kind"},
+		{"local service without dev command", "local.yaml", ErrorTypeValidation, "services.app.dev"},
+		{"image and path", "image.yaml", ErrorTypeValidation, "services.api.image"},
+		{"local service with image", "hostimg.yaml", ErrorTypeValidation, "services.app.image"},
+		{"unsupported restart policy", "restart.yaml", ErrorTypeValidation, "services.api.restart"},
+		{"empty dropped capability", "capdrop.yaml", ErrorTypeValidation, "services.api.security.capDrop"},
+		{"unsupported component restart policy", "crestart.yaml", ErrorTypeValidation, "components.gateway.restart"},
+		{"empty dropped capability of a component", "ccapdrop.yaml", ErrorTypeValidation, "components.gateway.security.capDrop"},
+		{"unknown lifecycle hook", "hookname.yaml", ErrorTypeValidation, "hooks.beforeCompose"},
+		{"consumed service without a spec", "consumes.yaml", ErrorTypeValidation, "services.web.consumes"},
+		{"hook script outside the project", "hookpath.yaml", ErrorTypeValidation, "hooks.preCompose[0]"},
+		{"library without a path", "library.yaml", ErrorTypeValidation, "services.models.path"},
+		{"library that is not a library", "libraries.yaml", ErrorTypeValidation, "services.web.libraries"},
+		{"absolute service path", "abspath.yaml", ErrorTypeValidation, "services.api.path"},
+		{"component path outside the project", "cpath.yaml", ErrorTypeValidation, "components.gateway.path"},
+		{"API spec outside the project", "specpath.yaml", ErrorTypeValidation, "services.api.api.spec"},
+		{"absolute external spec", "extspec.yaml", ErrorTypeValidation, "external.stripe.spec"},
+		{"resource config path outside the project", "rconfig.yaml", ErrorTypeValidation, "services.api.resources.db.config.initScripts"},
+		{"unknown included service", "envinc.yaml", ErrorTypeValidation, "environments.staging.include"},
+		{"service included and excluded", "envboth.yaml", ErrorTypeValidation, "environments.prod.exclude"},
+		{"unsupported secrets provider", "secrets.yaml", ErrorTypeValidation, "secrets.provider"},
+		{"vault secrets without a source", "vault.yaml", ErrorTypeValidation, "secrets.source"},
+		{"SOPS file outside the project", "sops.yaml", ErrorTypeValidation, "secrets.source"},
+		{"documented variable the service does not set", "envdocs.yaml", ErrorTypeValidation, "services.api.envDocs.STRIPE_KEY"},
+		{"documented resource variable that does not exist", "renvdocs.yaml", ErrorTypeValidation, "services.api.resources.db.envDocs.host"},
+	}
+
+	loader := NewLoader(fsys)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loader.Load(tt.path)
+			if !IsManifestError(err, tt.errType) {
+				t.Fatalf("expected manifest error of type %v, got %v", tt.errType, err)
+			}
+			if manifestErr := err.(*ManifestError); manifestErr.Field != tt.field {
+				t.Errorf("expected field %q, got %q", tt.field, manifestErr.Field)
+			}
+		})
+	}
+
+	if _, err := loader.Load("missing.yaml"); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func BenchmarkParse_150Services(b *testing.B) {
+	data := largeManifest(150)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Parse(data, "workbench.yaml"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoader_Cached_150Services(b *testing.B) {
+	fsys := filesystem.NewMemFS()
+	if err := fsys.WriteFile("workbench.yaml", largeManifest(150), 0644); err != nil {
+		b.Fatal(err)
+	}
+	loader := NewLoader(fsys)
+	if _, err := loader.Load("workbench.yaml"); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := loader.Load("workbench.yaml"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
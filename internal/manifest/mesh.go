@@ -0,0 +1,84 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Service meshes the generated Kubernetes manifests can be prepared for
+const (
+	MeshLinkerd = "linkerd"
+	MeshIstio   = "istio"
+)
+
+// Mutual TLS modes of the Istio PeerAuthentications
+const (
+	MTLSStrict     = "strict"     // only mTLS traffic from other meshed workloads
+	MTLSPermissive = "permissive" // plain text is accepted too, e.g. while migrating
+)
+
+// namespacePattern matches the names Kubernetes accepts for namespaces
+var namespacePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// DefaultMeshNamespace is the namespace the manifests are assumed to be
+// applied to when the mesh section names none
+const DefaultMeshNamespace = "default"
+
+// Mesh prepares the generated Kubernetes manifests for a service mesh, e.g.
+//
+//	mesh:
+//	  provider: istio
+//	  namespace: shop
+//
+// Without it the manifests are mesh-agnostic.
+type Mesh struct {
+	Provider  string `yaml:"provider,omitempty"`  // linkerd or istio
+	Namespace string `yaml:"namespace,omitempty"` // namespace the manifests are applied to
+	MTLS      string `yaml:"mtls,omitempty"`      // istio only: strict or permissive
+}
+
+// Enabled reports whether the manifests are prepared for a mesh
+func (m Mesh) Enabled() bool {
+	return m.Provider != ""
+}
+
+// NamespaceOrDefault returns the configured namespace, default otherwise
+func (m Mesh) NamespaceOrDefault() string {
+	if m.Namespace == "" {
+		return DefaultMeshNamespace
+	}
+	return m.Namespace
+}
+
+// MTLSOrDefault returns the configured mutual TLS mode, strict by default
+func (m Mesh) MTLSOrDefault() string {
+	if m.MTLS == "" {
+		return MTLSStrict
+	}
+	return m.MTLS
+}
+
+// validate checks the mesh section
+func (m Mesh) validate() error {
+	switch m.Provider {
+	case "":
+		if m.Namespace != "" || m.MTLS != "" {
+			return NewValidationError("mesh.provider", "is required to prepare the manifests for a mesh (use linkerd or istio)")
+		}
+		return nil
+	case MeshLinkerd:
+		if m.MTLS != "" {
+			return NewValidationError("mesh.mtls", "only applies to istio; linkerd always uses mTLS between meshed workloads")
+		}
+	case MeshIstio:
+		if m.MTLS != "" && m.MTLS != MTLSStrict && m.MTLS != MTLSPermissive {
+			return NewValidationError("mesh.mtls", fmt.Sprintf("unsupported mode '%s' (use strict or permissive)", m.MTLS))
+		}
+	default:
+		return NewValidationError("mesh.provider", fmt.Sprintf("unsupported mesh '%s' (use linkerd or istio)", m.Provider))
+	}
+	if m.Namespace != "" && !namespacePattern.MatchString(m.Namespace) {
+		return NewValidationError("mesh.namespace", "must be a Kubernetes namespace name: lowercase letters, digits, and dashes")
+	}
+	return nil
+}
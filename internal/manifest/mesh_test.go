@@ -0,0 +1,46 @@
+package manifest
+
+import "testing"
+
+func TestValidate_Mesh(t *testing.T) {
+	tests := []struct {
+		name  string
+		mesh  Mesh
+		field string
+	}{
+		{"no mesh", Mesh{}, ""},
+		{"linkerd", Mesh{Provider: MeshLinkerd, Namespace: "shop"}, ""},
+		{"permissive istio", Mesh{Provider: MeshIstio, MTLS: MTLSPermissive}, ""},
+		{"unsupported mesh", Mesh{Provider: "consul"}, "mesh.provider"},
+		{"namespace without a mesh", Mesh{Namespace: "shop"}, "mesh.provider"},
+		{"mTLS mode for linkerd", Mesh{Provider: MeshLinkerd, MTLS: MTLSPermissive}, "mesh.mtls"},
+		{"unsupported mTLS mode", Mesh{Provider: MeshIstio, MTLS: "disabled"}, "mesh.mtls"},
+		{"invalid mesh namespace", Mesh{Provider: MeshIstio, Namespace: "Shop_Dev"}, "mesh.namespace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := &WorkbenchManifest{Metadata: ProjectMetadata{Name: "demo"}, Mesh: tt.mesh}
+			err := manifest.Validate()
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("expected mesh to be valid, got %v", err)
+				}
+				return
+			}
+			manifestErr, ok := err.(*ManifestError)
+			if !ok {
+				t.Fatalf("expected a validation error for %s, got %v", tt.field, err)
+			}
+			if manifestErr.Field != tt.field {
+				t.Errorf("expected error on %s, got %s", tt.field, manifestErr.Field)
+			}
+		})
+	}
+}
+
+func TestMesh_Defaults(t *testing.T) {
+	var mesh Mesh
+	if mesh.Enabled() || mesh.NamespaceOrDefault() != DefaultMeshNamespace || mesh.MTLSOrDefault() != MTLSStrict {
+		t.Errorf("unexpected defaults for an empty mesh: %+v", mesh)
+	}
+}
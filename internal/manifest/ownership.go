@@ -0,0 +1,85 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Ownership attributes a service or component to the people responsible for
+// it. Owner, team, and tags are carried into the labels and cloud tags of
+// what is generated from the entry; the description is shown by om ls only.
+type Ownership struct {
+	Owner       string   `yaml:"owner,omitempty"` // person or alias responsible, e.g. jane@example.com
+	Team        string   `yaml:"team,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+}
+
+// ownerPattern and tagPattern restrict owners, teams, and tags to the
+// characters both Docker labels and AWS tags accept; tags are joined with
+// spaces, so they cannot contain any
+var (
+	ownerPattern = regexp.MustCompile(`^[\pL\pN _.:/=+@-]*$`)
+	tagPattern   = regexp.MustCompile(`^[\pL\pN_.:/=+@-]+$`)
+	orgPattern   = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+)
+
+// HasTag reports whether the entry is tagged with tag
+func (o Ownership) HasTag(tag string) bool {
+	return containsString(o.Tags, tag)
+}
+
+// Matches reports whether the entry belongs to team, is owned by owner, and
+// is tagged with tag; empty arguments match anything
+func (o Ownership) Matches(team, owner, tag string) bool {
+	return (team == "" || o.Team == team) && (owner == "" || o.Owner == owner) && (tag == "" || o.HasTag(tag))
+}
+
+// Validate checks the ownership of the entry at field, such as
+// services.api
+func (o Ownership) Validate(field string) error {
+	if !ownerPattern.MatchString(o.Owner) {
+		return NewValidationError(field+".owner", fmt.Sprintf("owner '%s' may only contain letters, digits, spaces, and _ . : / = + @ -", o.Owner))
+	}
+	if !ownerPattern.MatchString(o.Team) {
+		return NewValidationError(field+".team", fmt.Sprintf("team '%s' may only contain letters, digits, spaces, and _ . : / = + @ -", o.Team))
+	}
+	seen := make(map[string]bool)
+	for _, tag := range o.Tags {
+		if !tagPattern.MatchString(tag) {
+			return NewValidationError(field+".tags", fmt.Sprintf("tag '%s' must be non-empty and may only contain letters, digits, and _ . : / = + @ -", tag))
+		}
+		if seen[tag] {
+			return NewValidationError(field+".tags", fmt.Sprintf("tag '%s' is listed twice", tag))
+		}
+		seen[tag] = true
+	}
+	return nil
+}
+
+// CodeOwners configures the CODEOWNERS file 'om generate codeowners' writes
+// from the owners and teams of services and components
+type CodeOwners struct {
+	Org     string   `yaml:"org,omitempty"`     // GitHub organization or GitLab group; team payments becomes @<org>/payments
+	Default []string `yaml:"default,omitempty"` // owners of workbench.yaml and the generated compose and terraform files
+}
+
+// IsHandle reports whether an owner is written the way CODEOWNERS expects:
+// a @user or @org/team handle, or an email address
+func IsHandle(owner string) bool {
+	return strings.Contains(owner, "@") && !strings.ContainsAny(owner, " \t")
+}
+
+// validate checks the codeowners section
+func (c CodeOwners) validate() error {
+	if c.Org != "" && !orgPattern.MatchString(c.Org) {
+		return NewValidationError("codeowners.org", fmt.Sprintf("'%s' is not an organization or group name", c.Org))
+	}
+	for _, owner := range c.Default {
+		if !IsHandle(owner) {
+			return NewValidationError("codeowners.default", fmt.Sprintf("'%s' is not a @user or @org/team handle or an email address", owner))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package manifest
+
+import "fmt"
+
+// Providers of the secrets in the generated .env file
+const (
+	SecretsGenerate = "generate" // random credentials, kept in .env across runs
+	SecretsEnv      = "env"      // environment variables of the shell om runs in
+	SecretsSOPS     = "sops"     // a file encrypted with SOPS
+	SecretsVault    = "vault"    // a HashiCorp Vault KV secret
+	SecretsAWS      = "aws"      // an AWS Secrets Manager secret
+)
+
+// Secrets configures where the passwords of resources in the generated .env
+// file come from, e.g.
+//
+//	secrets:
+//	  provider: vault
+//	  source: secret/shop/dev
+//
+// Without it they are generated at random.
+type Secrets struct {
+	Provider string `yaml:"provider,omitempty"` // generate, env, sops, vault, or aws
+	Source   string `yaml:"source,omitempty"`   // SOPS file, Vault KV path, or AWS secret id
+}
+
+// ProviderName returns the configured provider, generate by default
+func (s Secrets) ProviderName() string {
+	if s.Provider == "" {
+		return SecretsGenerate
+	}
+	return s.Provider
+}
+
+// validate checks the secrets section
+func (s Secrets) validate() error {
+	switch s.ProviderName() {
+	case SecretsGenerate, SecretsEnv:
+		if s.Source != "" {
+			return NewValidationError("secrets.source", fmt.Sprintf("the %s provider takes no source", s.ProviderName()))
+		}
+	case SecretsSOPS:
+		if s.Source == "" {
+			return NewValidationError("secrets.source", "the sops provider needs the encrypted file to read")
+		}
+		if !IsProjectPath(s.Source) {
+			return NewValidationError("secrets.source", "must be a relative path inside the project")
+		}
+	case SecretsVault, SecretsAWS:
+		if s.Source == "" {
+			return NewValidationError("secrets.source", fmt.Sprintf("the %s provider needs the secret to read", s.Provider))
+		}
+	default:
+		return NewValidationError("secrets.provider",
+			fmt.Sprintf("unsupported provider '%s' (use generate, env, sops, vault, or aws)", s.Provider))
+	}
+	return nil
+}
@@ -1,5 +1,7 @@
 package manifest
 
+import "strings"
+
 // WorkbenchManifest represents the evolved structure of workbench.yaml
 type WorkbenchManifest struct {
 	APIVersion   string                 `yaml:"apiVersion"`
@@ -8,6 +10,38 @@ type WorkbenchManifest struct {
 	Environments map[string]Environment `yaml:"environments,omitempty"`
 	Components   map[string]Component   `yaml:"components,omitempty"`
 	Services     map[string]Service     `yaml:"services"`
+	External     map[string]External    `yaml:"external,omitempty"`   // third-party dependencies that are not built or deployed
+	Infra        map[string]string      `yaml:"infra,omitempty"`      // deployed infrastructure outputs referenced with ${infra.<output>}, with their local stand-ins
+	Groups       map[string][]string    `yaml:"groups,omitempty"`     // named sets of services and components
+	Include      []string               `yaml:"include,omitempty"`    // additional manifest files, relative to workbench.yaml
+	Layout       Layout                 `yaml:"layout,omitempty"`     // where new services are scaffolded
+	Hooks        map[string][]string    `yaml:"hooks,omitempty"`      // lifecycle hook name to the commands it runs
+	CodeOwners   CodeOwners             `yaml:"codeowners,omitempty"` // how owners are written to CODEOWNERS
+	Secrets      Secrets                `yaml:"secrets,omitempty"`    // where the passwords in the generated .env file come from
+	Mesh         Mesh                   `yaml:"mesh,omitempty"`       // service mesh the Kubernetes manifests are prepared for
+
+	// sources records which included file defines each entry, keyed by
+	// "<section>.<name>"; entries defined in workbench.yaml are absent
+	sources map[string]string
+}
+
+// External describes a third-party dependency, such as a payment provider or
+// a shared corporate API, that services call but the project does not build
+// or deploy. Services reference it with ${external.<name>.url}.
+type External struct {
+	Description  string            `yaml:"description,omitempty"`
+	URL          string            `yaml:"url"`                    // base URL for local development
+	Environments map[string]string `yaml:"environments,omitempty"` // base URL per deployment environment
+	Spec         string            `yaml:"spec,omitempty"`         // OpenAPI document used to mock the dependency locally
+}
+
+// URLFor returns the base URL to use in the named environment, falling back
+// to the local development URL. An empty environment means local development.
+func (e External) URLFor(environment string) string {
+	if url, ok := e.Environments[environment]; ok && environment != "" {
+		return url
+	}
+	return e.URL
 }
 
 // ProjectMetadata contains project-level information
@@ -20,27 +54,205 @@ type Environment struct {
 	Provider string            `yaml:"provider"` // aws, gcp, azure, etc.
 	Region   string            `yaml:"region,omitempty"`
 	Config   map[string]string `yaml:"config,omitempty"`
+	Include  []string          `yaml:"include,omitempty"` // services and components deployed only to the environments that include them
+	Exclude  []string          `yaml:"exclude,omitempty"` // services and components left out of this environment
+}
+
+// DeploysService reports whether the environment deploys the named service:
+// the services listed in config.services, or every container service when
+// the environment lists none, plus the services it includes and minus the
+// ones it excludes. Services that run on the host and shared libraries are
+// never deployed. Services that only other environments include are left
+// out by WorkbenchManifest.InEnvironment.
+func (e Environment) DeploysService(name string, service Service) bool {
+	if !service.IsContainer() || containsString(e.Exclude, name) {
+		return false
+	}
+	if e.Config["services"] == "" || containsString(e.Include, name) {
+		return true
+	}
+	for _, listed := range strings.Split(e.Config["services"], ",") {
+		if strings.TrimSpace(listed) == name {
+			return true
+		}
+	}
+	return false
 }
 
 // Component represents a shared project component (like a gateway)
 type Component struct {
-	Template string   `yaml:"template"`
-	Path     string   `yaml:"path"`
-	Ports    []string `yaml:"ports,omitempty"`
+	Ownership   `yaml:",inline"`
+	Template    string            `yaml:"template"`
+	Path        string            `yaml:"path"`
+	Ports       []string          `yaml:"ports,omitempty"`
+	Environment map[string]string `yaml:"environment,omitempty"`
+	Command     Command           `yaml:"command,omitempty"`    // overrides the container's command
+	Entrypoint  Command           `yaml:"entrypoint,omitempty"` // overrides the container's entrypoint
+	Restart     string            `yaml:"restart,omitempty"`    // restart policy; empty keeps the runtime's default
+	Init        bool              `yaml:"init,omitempty"`       // run an init process that reaps zombies and forwards signals
+	Security    *Security         `yaml:"security,omitempty"`   // hardening of the component's container
 }
 
 // Service represents a service in the project with its configuration
 type Service struct {
+	Ownership   `yaml:",inline"`
 	Template    string              `yaml:"template"`
 	Path        string              `yaml:"path"`
+	Image       string              `yaml:"image,omitempty"`      // prebuilt image to run instead of building path
+	Kind        string              `yaml:"kind,omitempty"`       // how the service runs; empty means a container
+	Dev         string              `yaml:"dev,omitempty"`        // command that starts the service on the host
+	Command     Command             `yaml:"command,omitempty"`    // overrides the container's command
+	Entrypoint  Command             `yaml:"entrypoint,omitempty"` // overrides the container's entrypoint
+	Restart     string              `yaml:"restart,omitempty"`    // restart policy; empty keeps the runtime's default
+	Init        bool                `yaml:"init,omitempty"`       // run an init process that reaps zombies and forwards signals
+	Security    *Security           `yaml:"security,omitempty"`   // hardening of the service's container
 	Port        int                 `yaml:"port,omitempty"`
+	Protocol    string              `yaml:"protocol,omitempty"`
+	Subdomain   string              `yaml:"subdomain,omitempty"`
+	Health      *Health             `yaml:"health,omitempty"` // endpoint that reports whether the service is up
+	GraphQL     *GraphQL            `yaml:"graphql,omitempty"`
+	API         *API                `yaml:"api,omitempty"`
+	Consumes    []string            `yaml:"consumes,omitempty"`  // services whose APIs this service calls through generated clients
+	Libraries   []string            `yaml:"libraries,omitempty"` // shared libraries (kind: library) built into this service
+	DependsOn   []Dependency        `yaml:"dependsOn,omitempty"` // services and components to wait for before starting
 	Resources   map[string]Resource `yaml:"resources,omitempty"`
 	Environment map[string]string   `yaml:"environment,omitempty"`
+	EnvDocs     map[string]string   `yaml:"envDocs,omitempty"` // descriptions of environment variables, by name
+}
+
+// GraphQL marks a service as a GraphQL API that graphql-gateway components
+// combine into a single schema
+type GraphQL struct {
+	Path string `yaml:"path"` // endpoint path, e.g. /graphql
+}
+
+// Health describes the HTTP endpoint a service answers health checks on
+type Health struct {
+	Path   string `yaml:"path,omitempty"`   // e.g. /health; defaults to /
+	Status int    `yaml:"status,omitempty"` // status code of a healthy service; defaults to 200
+}
+
+// Default health check of HTTP services without a health entry
+const (
+	DefaultHealthPath   = "/"
+	DefaultHealthStatus = 200
+)
+
+// HealthPath returns the path the service answers health checks on
+func (s Service) HealthPath() string {
+	if s.Health == nil || s.Health.Path == "" {
+		return DefaultHealthPath
+	}
+	return s.Health.Path
+}
+
+// HealthStatus returns the status code the service answers health checks
+// with when it is healthy
+func (s Service) HealthStatus() int {
+	if s.Health == nil || s.Health.Status == 0 {
+		return DefaultHealthStatus
+	}
+	return s.Health.Status
+}
+
+// API points at the OpenAPI document describing a service
+type API struct {
+	Spec string `yaml:"spec"` // OpenAPI file, relative to the service's path
+}
+
+// Security hardens the container of a service or component
+type Security struct {
+	ReadOnlyRootFilesystem bool     `yaml:"readOnlyRootFilesystem,omitempty"` // mount the root filesystem read-only
+	CapDrop                []string `yaml:"capDrop,omitempty"`                // Linux capabilities to drop, e.g. ALL or NET_RAW
+	User                   string   `yaml:"user,omitempty"`                   // user (and group) the process runs as, e.g. 1000:1000
+	NoNewPrivileges        bool     `yaml:"noNewPrivileges,omitempty"`        // stop processes from gaining privileges
+}
+
+// clone returns a deep copy of the settings, or nil without any
+func (s *Security) clone() *Security {
+	if s == nil {
+		return nil
+	}
+	security := *s
+	security.CapDrop = append([]string(nil), s.CapDrop...)
+	return &security
+}
+
+// Service kinds. Services without a kind are built and run as containers.
+const (
+	ServiceKindContainer = "container"
+	ServiceKindLocal     = "local"   // runs on the developer's machine, e.g. a mobile app bundler
+	ServiceKindLibrary   = "library" // shared code built into other services; never runs on its own
+)
+
+// IsContainer reports whether generators should build and run the service as a container
+func (s Service) IsContainer() bool {
+	return s.Kind == "" || s.Kind == ServiceKindContainer
+}
+
+// RunsOnHost reports whether the service runs on the developer's machine
+// through its dev command
+func (s Service) RunsOnHost() bool {
+	return s.Kind == ServiceKindLocal
+}
+
+// IsLibrary reports whether the service is a shared library
+func (s Service) IsLibrary() bool {
+	return s.Kind == ServiceKindLibrary
 }
 
+// UsesImage reports whether the service runs a prebuilt image instead of
+// building its path
+func (s Service) UsesImage() bool {
+	return s.Image != ""
+}
+
+// Service protocols. Services without a protocol speak HTTP.
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+	ProtocolTCP  = "tcp"
+)
+
+// ProtocolOrDefault returns the service's protocol, defaulting to HTTP
+func (s Service) ProtocolOrDefault() string {
+	if s.Protocol == "" {
+		return ProtocolHTTP
+	}
+	return s.Protocol
+}
+
+// Restart policies of services and components
+const (
+	RestartNo            = "no"
+	RestartOnFailure     = "on-failure"
+	RestartUnlessStopped = "unless-stopped"
+)
+
 // Resource represents a service-owned resource (like a database)
 type Resource struct {
-	Type    string            `yaml:"type"`
-	Version string            `yaml:"version,omitempty"`
-	Config  map[string]string `yaml:"config,omitempty"`
+	Type        string            `yaml:"type"`
+	Version     string            `yaml:"version,omitempty"`
+	Config      map[string]string `yaml:"config,omitempty"`
+	Environment map[string]string `yaml:"environment,omitempty"` // extra variables for the resource's container, e.g. POSTGRES_INITDB_ARGS
+	Expose      string            `yaml:"expose,omitempty"`      // internal (default) or host
+	EnvDocs     map[string]string `yaml:"envDocs,omitempty"`     // descriptions of the resource's .env variables by ResourceEnvProperties, replacing the built-in ones
+}
+
+// ResourceEnvProperties are the properties of a resource that are written
+// to .env as <service>_<resource>_<property>, for the resource types that
+// have them
+var ResourceEnvProperties = []string{"user", "password", "name", "dbname"}
+
+// Where a resource is reachable. Internal resources are only reachable by
+// name on the project's network; host resources also publish their port on
+// the developer's machine.
+const (
+	ExposeInternal = "internal"
+	ExposeHost     = "host"
+)
+
+// PublishesPort reports whether the resource's port is published on the host
+func (r Resource) PublishesPort() bool {
+	return r.Expose == ExposeHost
 }
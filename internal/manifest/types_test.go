@@ -296,3 +296,24 @@ func TestResource_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironment_DeploysService(t *testing.T) {
+	api := Service{Path: "api"}
+	mobile := Service{Path: "mobile", Kind: ServiceKindLocal, Dev: "npx expo start"}
+
+	all := Environment{Provider: "aws"}
+	if !all.DeploysService("api", api) {
+		t.Error("expected an environment without a service list to deploy every container service")
+	}
+	if all.DeploysService("mobile", mobile) {
+		t.Error("expected local services never to be deployed")
+	}
+
+	listed := Environment{Provider: "aws", Config: map[string]string{"services": "web, api"}}
+	if !listed.DeploysService("api", api) {
+		t.Error("expected listed services to be deployed")
+	}
+	if listed.DeploysService("worker", Service{Path: "worker"}) {
+		t.Error("expected unlisted services not to be deployed")
+	}
+}
@@ -0,0 +1,173 @@
+// Package onboarding keeps the personal defaults om asks for on first run,
+// stored in the profile section of ~/.om/config.yaml next to the user's
+// hooks and experiments, and builds the getting-started checklist shown
+// after the wizard, tailored to the tools installed on the machine.
+package onboarding
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// Section is the key of the profile in ~/.om/config.yaml
+const Section = "profile"
+
+// PackageManagers are the JavaScript package managers the wizard offers
+var PackageManagers = []string{"npm", "pnpm", "yarn", "bun"}
+
+// Providers are the cloud providers the wizard offers; none skips the cloud
+// questions
+var Providers = []string{"aws", "gcp", "azure", "none"}
+
+// DefaultRegions suggests a region for each provider
+var DefaultRegions = map[string]string{
+	"aws":   "us-east-1",
+	"gcp":   "us-central1",
+	"azure": "eastus",
+}
+
+// Profile holds the user's defaults
+type Profile struct {
+	Owner          string `yaml:"owner,omitempty"`          // pre-fills the Owner template parameter
+	PackageManager string `yaml:"packageManager,omitempty"` // pre-fills the PackageManager template parameter
+	Telemetry      bool   `yaml:"telemetry"`                // whether the user opted in to anonymous usage statistics
+	Cloud          Cloud  `yaml:"cloud,omitempty"`
+}
+
+// Cloud is where the user deploys by default
+type Cloud struct {
+	Provider string `yaml:"provider,omitempty"`
+	Region   string `yaml:"region,omitempty"`
+}
+
+// userConfig is the profile section of ~/.om/config.yaml
+type userConfig struct {
+	Profile *Profile `yaml:"profile"`
+}
+
+// Load reads the profile of the user config at configPath. It reports
+// false when the file or its profile section does not exist yet, which is
+// what makes a run the first one.
+func Load(fsys filesystem.FS, configPath string) (Profile, bool, error) {
+	if configPath == "" || !filesystem.Exists(fsys, configPath) {
+		return Profile{}, false, nil
+	}
+	data, err := fsys.ReadFile(configPath)
+	if err != nil {
+		return Profile{}, false, fmt.Errorf("failed to read %s: %w", configPath, err)
+	}
+	var config userConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Profile{}, false, fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+	if config.Profile == nil {
+		return Profile{}, false, nil
+	}
+	return *config.Profile, true, nil
+}
+
+// Save writes the profile into the user config at configPath, replacing
+// its profile section and keeping every other section as written
+func Save(fsys filesystem.FS, configPath string, profile Profile) error {
+	var document yaml.Node
+	if filesystem.Exists(fsys, configPath) {
+		data, err := fsys.ReadFile(configPath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", configPath, err)
+		}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", configPath, err)
+		}
+	}
+	if len(document.Content) == 0 {
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s is not a mapping of sections", configPath)
+	}
+
+	var value yaml.Node
+	if err := value.Encode(profile); err != nil {
+		return fmt.Errorf("failed to encode profile: %w", err)
+	}
+	replaced := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == Section {
+			root.Content[i+1] = &value
+			replaced = true
+		}
+	}
+	if !replaced {
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: Section}, &value)
+	}
+
+	data, err := yaml.Marshal(&document)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", configPath, err)
+	}
+	if err := fsys.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(configPath), err)
+	}
+	return fsys.WriteFile(configPath, data, 0644)
+}
+
+// Tools are the external tools the checklist depends on
+type Tools struct {
+	Docker    bool
+	Terraform bool
+}
+
+// DetectTools reports which tools are on the PATH, using lookPath such as
+// exec.LookPath
+func DetectTools(lookPath func(string) (string, error)) Tools {
+	_, dockerErr := lookPath("docker")
+	_, terraformErr := lookPath("terraform")
+	return Tools{Docker: dockerErr == nil, Terraform: terraformErr == nil}
+}
+
+// Step is an item of the getting-started checklist. Done steps need nothing
+// from the user.
+type Step struct {
+	Done bool
+	Text string
+}
+
+// Checklist returns the getting-started steps for the profile and tools
+func Checklist(profile Profile, tools Tools) []Step {
+	steps := []Step{
+		{Text: "Create a project: om init"},
+		{Text: "Add a service from a template: om add service (om list-templates shows them)"},
+	}
+	if tools.Docker {
+		steps = append(steps,
+			Step{Done: true, Text: "Docker is installed"},
+			Step{Text: "Run everything locally and follow the logs: om dev"})
+	} else {
+		steps = append(steps,
+			Step{Text: "Install Docker to run services locally: https://docs.docker.com/get-docker/"},
+			Step{Text: "Then run everything locally and follow the logs: om dev"})
+	}
+
+	if profile.Cloud.Provider != "" && profile.Cloud.Provider != "none" {
+		target := profile.Cloud.Provider
+		if profile.Cloud.Region != "" {
+			target += " (" + profile.Cloud.Region + ")"
+		}
+		if tools.Terraform {
+			steps = append(steps,
+				Step{Done: true, Text: "Terraform is installed"},
+				Step{Text: fmt.Sprintf("After deploying to %s, read its outputs with: om infra pull --env <env>", target)})
+		} else {
+			steps = append(steps, Step{Text: fmt.Sprintf("Install Terraform to deploy to %s: https://developer.hashicorp.com/terraform/install", target)})
+		}
+	}
+
+	if profile.Owner == "" {
+		steps = append(steps, Step{Text: "Set a default owner for new services: om setup"})
+	}
+	return steps
+}
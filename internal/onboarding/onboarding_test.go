@@ -0,0 +1,87 @@
+package onboarding
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestSave_KeepsOtherSections(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	configPath := filepath.Join("home", ".om", "config.yaml")
+
+	if _, found, err := Load(fsys, configPath); err != nil || found {
+		t.Fatalf("expected no profile without a config, got %v, %v", found, err)
+	}
+
+	profile := Profile{Owner: "jane@example.com", PackageManager: "pnpm", Cloud: Cloud{Provider: "aws", Region: "eu-west-1"}}
+	if err := Save(fsys, configPath, profile); err != nil {
+		t.Fatal(err)
+	}
+	loaded, found, err := Load(fsys, configPath)
+	if err != nil || !found || loaded != profile {
+		t.Fatalf("Load() = %+v, %v, %v, want %+v", loaded, found, err, profile)
+	}
+
+	config := "# my hooks\nhooks:\n  - events: [compose.generated]\n    command: make notify\nexperiments: [kubernetes]\n"
+	if err := fsys.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := Load(fsys, configPath); found {
+		t.Error("expected a config without a profile section to be a first run")
+	}
+	if err := Save(fsys, configPath, Profile{Telemetry: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(fsys, configPath, Profile{Owner: "sam"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := fsys.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	for _, want := range []string{"# my hooks", "command: make notify", "experiments: [kubernetes]", "owner: sam"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in the saved config, got:\n%s", want, text)
+		}
+	}
+	if strings.Count(text, "profile:") != 1 || strings.Contains(text, "telemetry: true") {
+		t.Errorf("expected the profile section to be replaced, got:\n%s", text)
+	}
+}
+
+func TestChecklist(t *testing.T) {
+	missing := func(string) (string, error) { return "", errors.New("not found") }
+	installed := func(name string) (string, error) { return "/usr/bin/" + name, nil }
+
+	render := func(steps []Step) string {
+		var lines []string
+		for _, step := range steps {
+			mark := "[ ]"
+			if step.Done {
+				mark = "[x]"
+			}
+			lines = append(lines, mark+" "+step.Text)
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	bare := render(Checklist(Profile{Cloud: Cloud{Provider: "none"}}, DetectTools(missing)))
+	if !strings.Contains(bare, "[ ] Install Docker") || strings.Contains(bare, "Terraform") || !strings.Contains(bare, "om setup") {
+		t.Errorf("expected Docker to be installed first and no cloud steps, got:\n%s", bare)
+	}
+
+	aws := Profile{Owner: "jane", Cloud: Cloud{Provider: "aws", Region: "eu-west-1"}}
+	without := render(Checklist(aws, Tools{Docker: true}))
+	if !strings.Contains(without, "[x] Docker is installed") || !strings.Contains(without, "[ ] Install Terraform to deploy to aws (eu-west-1)") {
+		t.Errorf("expected an install step for Terraform, got:\n%s", without)
+	}
+	with := render(Checklist(aws, DetectTools(installed)))
+	if !strings.Contains(with, "[x] Terraform is installed") || !strings.Contains(with, "om infra pull") || strings.Contains(with, "om setup") {
+		t.Errorf("expected the deployment steps with Terraform installed, got:\n%s", with)
+	}
+}
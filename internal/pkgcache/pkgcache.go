@@ -0,0 +1,226 @@
+// Package pkgcache pre-downloads the packages templates depend on into a
+// local cache, so that scaffolding installs them from there instead of the
+// public registries, e.g. at workshops or offline. Each ecosystem is
+// handled by a Warmer; npm and pip are built in and others can be
+// registered. Warming can also go through registry mirrors, such as the
+// Verdaccio and devpi containers of ComposeFile, which keep every package
+// they served.
+package pkgcache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// RegistriesFile records, in the cache directory, the registry mirrors the
+// cache was warmed through
+const RegistriesFile = "registries.yaml"
+
+// Registries are the registry mirrors packages are fetched through. Empty
+// fields use the ecosystem's public registry.
+type Registries struct {
+	NPM string `yaml:"npm,omitempty"`
+	Pip string `yaml:"pip,omitempty"`
+}
+
+// Warmer downloads the packages of one ecosystem into the cache
+type Warmer interface {
+	// Ecosystem names the warmer, e.g. "npm", and the cache subdirectory
+	// its packages are kept in
+	Ecosystem() string
+
+	// ManifestFile is the dependency manifest the warmer reads, e.g.
+	// package.json
+	ManifestFile() string
+
+	// Warm downloads the packages of the manifest in dir into cacheDir
+	Warm(run deps.Runner, dir, cacheDir string, registries Registries) error
+
+	// Environment returns the variables that make installs use cacheDir
+	Environment(cacheDir string, registries Registries) []string
+}
+
+var (
+	warmersMu sync.RWMutex
+	warmers   = map[string]Warmer{}
+)
+
+func init() {
+	Register(npmWarmer{})
+	Register(pipWarmer{})
+}
+
+// Register adds a warmer, replacing the one of the same ecosystem
+func Register(warmer Warmer) {
+	warmersMu.Lock()
+	defer warmersMu.Unlock()
+	warmers[warmer.Ecosystem()] = warmer
+}
+
+// Warmers returns every registered warmer sorted by ecosystem
+func Warmers() []Warmer {
+	warmersMu.RLock()
+	defer warmersMu.RUnlock()
+	list := make([]Warmer, 0, len(warmers))
+	for _, warmer := range warmers {
+		list = append(list, warmer)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Ecosystem() < list[j].Ecosystem() })
+	return list
+}
+
+// Environment returns the variables that make the installs of every
+// ecosystem use the cache in dir. It returns nil when the cache has never
+// been warmed.
+func Environment(fsys filesystem.FS, dir string) []string {
+	if !filesystem.Exists(fsys, dir) {
+		return nil
+	}
+	registries, err := LoadRegistries(fsys, dir)
+	if err != nil {
+		return nil
+	}
+	var env []string
+	for _, warmer := range Warmers() {
+		cacheDir := filepath.Join(dir, warmer.Ecosystem())
+		if filesystem.Exists(fsys, cacheDir) {
+			env = append(env, warmer.Environment(cacheDir, registries)...)
+		}
+	}
+	return env
+}
+
+// LoadRegistries reads the registry mirrors recorded in the cache in dir.
+// A cache without mirrors returns empty Registries.
+func LoadRegistries(fsys filesystem.FS, dir string) (Registries, error) {
+	var registries Registries
+	data, err := fsys.ReadFile(filepath.Join(dir, RegistriesFile))
+	if err != nil {
+		return registries, nil
+	}
+	if err := yaml.Unmarshal(data, &registries); err != nil {
+		return registries, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, RegistriesFile), err)
+	}
+	return registries, nil
+}
+
+// SaveRegistries records the registry mirrors of the cache in dir
+func SaveRegistries(fsys filesystem.FS, dir string, registries Registries) error {
+	if err := fsys.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(registries)
+	if err != nil {
+		return err
+	}
+	return fsys.WriteFile(filepath.Join(dir, RegistriesFile), data, 0644)
+}
+
+// Default addresses of the mirrors started from ComposeFile
+const (
+	ComposeNPMRegistry = "http://localhost:4873/"
+	ComposePipIndex    = "http://localhost:3141/root/pypi/+simple/"
+)
+
+// ComposeFile returns a Docker Compose file that runs a Verdaccio npm
+// mirror and a devpi PyPI mirror, with volumes that keep what they cached
+func ComposeFile() string {
+	return `# Registry mirrors for om cache warm. Start them with
+#   docker compose -f <this file> up -d
+# and warm them with
+#   om cache warm --npm-registry ` + ComposeNPMRegistry + ` --pip-index ` + ComposePipIndex + `
+name: om-package-cache
+services:
+  verdaccio:
+    image: verdaccio/verdaccio:5
+    ports:
+      - "4873:4873"
+    volumes:
+      - verdaccio_storage:/verdaccio/storage
+    restart: unless-stopped
+  devpi:
+    image: jonasal/devpi-server:latest
+    environment:
+      DEVPI_PASSWORD: devpi
+    ports:
+      - "3141:3141"
+    volumes:
+      - devpi_data:/devpi
+    restart: unless-stopped
+volumes:
+  verdaccio_storage:
+  devpi_data:
+`
+}
+
+// Manifest is a dependency manifest a Warmer reads
+type Manifest struct {
+	Warmer Warmer
+	Path   string
+}
+
+// Find returns the dependency manifests under dir, in lexical order, that a
+// registered warmer reads. Dependency directories are skipped.
+func Find(fsys filesystem.FS, dir string) ([]Manifest, error) {
+	byFile := make(map[string]Warmer)
+	for _, warmer := range Warmers() {
+		byFile[warmer.ManifestFile()] = warmer
+	}
+
+	var manifests []Manifest
+	var walk func(string) error
+	walk = func(current string) error {
+		entries, err := fsys.ReadDir(current)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			path := filepath.Join(current, entry.Name())
+			if entry.IsDir() {
+				if entry.Name() == "node_modules" || entry.Name() == ".venv" {
+					continue
+				}
+				if err := walk(path); err != nil {
+					return err
+				}
+				continue
+			}
+			if warmer, found := byFile[entry.Name()]; found {
+				manifests = append(manifests, Manifest{Warmer: warmer, Path: path})
+			}
+		}
+		return nil
+	}
+	if err := walk(dir); err != nil {
+		return nil, err
+	}
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].Path < manifests[j].Path })
+	return manifests, nil
+}
+
+// Warm downloads the packages of a manifest, whose content is data, into
+// the cache in dir. The manifest is copied to a scratch directory first so
+// that installs never touch the directory it was read from.
+func Warm(run deps.Runner, manifest Manifest, data []byte, dir string, registries Registries) error {
+	scratch, err := os.MkdirTemp("", "om-cache-warm-")
+	if err != nil {
+		return fmt.Errorf("failed to create a scratch directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	if err := os.WriteFile(filepath.Join(scratch, manifest.Warmer.ManifestFile()), data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", manifest.Warmer.ManifestFile(), err)
+	}
+	cacheDir := filepath.Join(dir, manifest.Warmer.Ecosystem())
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", cacheDir, err)
+	}
+	return manifest.Warmer.Warm(run, scratch, cacheDir, registries)
+}
@@ -0,0 +1,98 @@
+package pkgcache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestFind(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	for _, name := range []string{
+		"/app/web/package.json",
+		"/app/web/node_modules/react/package.json",
+		"/app/api/requirements.txt",
+		"/app/api/.venv/requirements.txt",
+		"/app/README.md",
+	} {
+		if err := fsys.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsys.WriteFile(name, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manifests, err := Find(fsys, "/app")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	var got []string
+	for _, manifest := range manifests {
+		got = append(got, manifest.Warmer.Ecosystem()+":"+filepath.ToSlash(manifest.Path))
+	}
+	want := "pip:/app/api/requirements.txt npm:/app/web/package.json"
+	if strings.Join(got, " ") != want {
+		t.Errorf("Find() = %v, want %s", got, want)
+	}
+}
+
+func TestWarm(t *testing.T) {
+	dir := t.TempDir()
+	var commands []string
+	run := func(runDir, name string, args ...string) ([]byte, error) {
+		if _, err := os.Stat(filepath.Join(runDir, map[string]string{"npm": "package.json", "pip": "requirements.txt"}[name])); err != nil {
+			t.Errorf("%s ran without its manifest: %v", name, err)
+		}
+		commands = append(commands, strings.Join(append([]string{name}, args...), " "))
+		return nil, nil
+	}
+	registries := Registries{NPM: ComposeNPMRegistry}
+
+	for _, warmer := range Warmers() {
+		if err := Warm(run, Manifest{Warmer: warmer}, []byte("{}"), dir, registries); err != nil {
+			t.Fatalf("Warm(%s) error = %v", warmer.Ecosystem(), err)
+		}
+	}
+
+	want := []string{
+		"npm install --ignore-scripts --no-audit --no-fund --prefer-offline --cache " + filepath.Join(dir, "npm") + " --registry " + ComposeNPMRegistry,
+		"pip download --disable-pip-version-check --requirement requirements.txt --dest " + filepath.Join(dir, "pip"),
+	}
+	if strings.Join(commands, "\n") != strings.Join(want, "\n") {
+		t.Errorf("commands =\n%s\nwant\n%s", strings.Join(commands, "\n"), strings.Join(want, "\n"))
+	}
+
+	failing := func(string, string, ...string) ([]byte, error) { return nil, errors.New("offline") }
+	if err := Warm(failing, Manifest{Warmer: npmWarmer{}}, []byte("{}"), dir, Registries{}); err == nil || !strings.Contains(err.Error(), "offline") {
+		t.Errorf("Warm() with a failing install error = %v", err)
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	if env := Environment(fsys, "/cache"); env != nil {
+		t.Errorf("Environment() of a missing cache = %v, want nil", env)
+	}
+
+	if err := fsys.MkdirAll("/cache/pip", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveRegistries(fsys, "/cache", Registries{NPM: ComposeNPMRegistry, Pip: ComposePipIndex}); err != nil {
+		t.Fatal(err)
+	}
+	registries, err := LoadRegistries(fsys, "/cache")
+	if err != nil || registries.Pip != ComposePipIndex {
+		t.Fatalf("LoadRegistries() = %+v, %v", registries, err)
+	}
+
+	// Only the warmed ecosystems are pointed at the cache
+	want := []string{"PIP_FIND_LINKS=" + filepath.Join("/cache", "pip"), "PIP_INDEX_URL=" + ComposePipIndex}
+	if got := Environment(fsys, "/cache"); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Environment() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package pkgcache
+
+import (
+	"fmt"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+)
+
+// npmWarmer fills an npm cache directory by installing package.json into a
+// scratch directory with that cache
+type npmWarmer struct{}
+
+func (npmWarmer) Ecosystem() string    { return "npm" }
+func (npmWarmer) ManifestFile() string { return "package.json" }
+
+func (npmWarmer) Warm(run deps.Runner, dir, cacheDir string, registries Registries) error {
+	args := []string{"install", "--ignore-scripts", "--no-audit", "--no-fund", "--prefer-offline", "--cache", cacheDir}
+	if registries.NPM != "" {
+		args = append(args, "--registry", registries.NPM)
+	}
+	if _, err := run(dir, "npm", args...); err != nil {
+		return fmt.Errorf("npm install failed: %w", err)
+	}
+	return nil
+}
+
+func (npmWarmer) Environment(cacheDir string, registries Registries) []string {
+	env := []string{"npm_config_cache=" + cacheDir, "npm_config_prefer_offline=true"}
+	if registries.NPM != "" {
+		env = append(env, "npm_config_registry="+registries.NPM)
+	}
+	return env
+}
+
+// pipWarmer downloads the packages of requirements.txt, as wheels or
+// source archives, into a directory pip installs from with --find-links
+type pipWarmer struct{}
+
+func (pipWarmer) Ecosystem() string    { return "pip" }
+func (pipWarmer) ManifestFile() string { return "requirements.txt" }
+
+func (pipWarmer) Warm(run deps.Runner, dir, cacheDir string, registries Registries) error {
+	args := []string{"download", "--disable-pip-version-check", "--requirement", "requirements.txt", "--dest", cacheDir}
+	if registries.Pip != "" {
+		args = append(args, "--index-url", registries.Pip)
+	}
+	if _, err := run(dir, "pip", args...); err != nil {
+		return fmt.Errorf("pip download failed: %w", err)
+	}
+	return nil
+}
+
+func (pipWarmer) Environment(cacheDir string, registries Registries) []string {
+	env := []string{"PIP_FIND_LINKS=" + cacheDir}
+	if registries.Pip != "" {
+		env = append(env, "PIP_INDEX_URL="+registries.Pip)
+	}
+	return env
+}
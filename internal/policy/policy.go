@@ -0,0 +1,258 @@
+// Package policy applies a project's scaffolding policy, .om/policy.yaml, to
+// every service added to the project: a license header on source files,
+// formatter configs, and files every service must carry.
+package policy
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the location of the policy relative to the project root
+var FileName = filepath.Join(".om", "policy.yaml")
+
+// Policy is the content of .om/policy.yaml
+type Policy struct {
+	License    License           `yaml:"license,omitempty"`
+	Formatters map[string]string `yaml:"formatters,omitempty"` // formatter name to config file content
+	Files      []File            `yaml:"files,omitempty"`
+}
+
+// License describes the header added to the top of source files
+type License struct {
+	Header     string   `yaml:"header"`
+	Extensions []string `yaml:"extensions,omitempty"` // limits the header to these extensions, e.g. [.go, .py]
+}
+
+// File is a file every service must contain
+type File struct {
+	Path    string `yaml:"path"`              // relative to the service directory
+	Content string `yaml:"content,omitempty"` // inline content
+	Source  string `yaml:"source,omitempty"`  // file to copy, relative to the project root
+}
+
+// formatter describes where a formatter's config goes and which services it
+// applies to
+type formatter struct {
+	file    string   // config file written into the service
+	markers []string // files that identify services the formatter applies to
+}
+
+// formatters are the formatters a policy can configure
+var formatters = map[string]formatter{
+	"prettier": {file: ".prettierrc", markers: []string{"package.json"}},
+	"ruff":     {file: "ruff.toml", markers: []string{"requirements.txt", "pyproject.toml"}},
+}
+
+// commentPrefixes maps source file extensions to their line comment syntax
+var commentPrefixes = map[string]string{
+	".go": "// ", ".js": "// ", ".jsx": "// ", ".ts": "// ", ".tsx": "// ", ".mjs": "// ",
+	".java": "// ", ".kt": "// ", ".rs": "// ", ".swift": "// ", ".c": "// ", ".h": "// ",
+	".cpp": "// ", ".cs": "// ", ".proto": "// ", ".scss": "// ", ".vue": "// ",
+	".py": "# ", ".rb": "# ", ".sh": "# ",
+}
+
+// Load reads the policy of the project at projectRoot. It returns nil when
+// the project has none.
+func Load(fsys filesystem.FS, projectRoot string) (*Policy, error) {
+	path := filepath.Join(projectRoot, FileName)
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		if !filesystem.Exists(fsys, path) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", FileName, err)
+	}
+	return &policy, nil
+}
+
+// Validate checks that the policy only names known formatters and that every
+// mandatory file has a path inside the service and exactly one source
+func (p *Policy) Validate() error {
+	for name := range p.Formatters {
+		if _, ok := formatters[name]; !ok {
+			return fmt.Errorf("formatters.%s: unknown formatter (use prettier or ruff)", name)
+		}
+	}
+	for i, file := range p.Files {
+		if file.Path == "" {
+			return fmt.Errorf("files[%d]: path is required", i)
+		}
+		if filepath.IsAbs(file.Path) || strings.HasPrefix(filepath.Clean(file.Path), "..") {
+			return fmt.Errorf("files[%d]: path '%s' must stay inside the service", i, file.Path)
+		}
+		if (file.Content == "") == (file.Source == "") {
+			return fmt.Errorf("files[%d]: set either content or source for '%s'", i, file.Path)
+		}
+	}
+	return nil
+}
+
+// Apply applies the policy to the service scaffolded in serviceDir. It
+// returns the files it wrote or changed, relative to serviceDir and sorted.
+// Policy files replace files of the same name from the template.
+func (p *Policy) Apply(fsys filesystem.FS, projectRoot, serviceDir string) ([]string, error) {
+	changed := make(map[string]bool)
+
+	for _, name := range slices.Sorted(maps.Keys(p.Formatters)) {
+		formatter := formatters[name]
+		if !hasAny(fsys, serviceDir, formatter.markers) {
+			continue
+		}
+		if err := fsys.WriteFile(filepath.Join(serviceDir, formatter.file), []byte(withNewline(p.Formatters[name])), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", formatter.file, err)
+		}
+		changed[formatter.file] = true
+	}
+
+	for _, file := range p.Files {
+		content := []byte(file.Content)
+		if file.Source != "" {
+			data, err := fsys.ReadFile(filepath.Join(projectRoot, file.Source))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s for %s: %w", file.Source, file.Path, err)
+			}
+			content = data
+		}
+		target := filepath.Join(serviceDir, file.Path)
+		if err := fsys.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory for %s: %w", file.Path, err)
+		}
+		if err := fsys.WriteFile(target, content, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+		changed[filepath.ToSlash(filepath.Clean(file.Path))] = true
+	}
+
+	if strings.TrimSpace(p.License.Header) != "" {
+		headed, err := p.addLicenseHeaders(fsys, serviceDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range headed {
+			changed[path] = true
+		}
+	}
+
+	return slices.Sorted(maps.Keys(changed)), nil
+}
+
+// addLicenseHeaders prepends the license header to every source file under
+// serviceDir that does not carry it yet
+func (p *Policy) addLicenseHeaders(fsys filesystem.FS, serviceDir string) ([]string, error) {
+	allowed := make(map[string]bool, len(p.License.Extensions))
+	for _, extension := range p.License.Extensions {
+		allowed["."+strings.TrimPrefix(extension, ".")] = true
+	}
+
+	var headed []string
+	err := walkFiles(fsys, serviceDir, func(path string) error {
+		extension := filepath.Ext(path)
+		prefix, ok := commentPrefixes[extension]
+		if !ok || (len(allowed) > 0 && !allowed[extension]) {
+			return nil
+		}
+
+		data, err := fsys.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		header := commentBlock(p.License.Header, prefix)
+		if strings.Contains(string(data), header) {
+			return nil
+		}
+		if err := fsys.WriteFile(path, []byte(prependHeader(string(data), header)), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		rel, err := filepath.Rel(serviceDir, path)
+		if err != nil {
+			return err
+		}
+		headed = append(headed, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add license headers: %w", err)
+	}
+	return headed, nil
+}
+
+// walkFiles calls visit for every file under dir, skipping dependency and
+// version control directories
+func walkFiles(fsys filesystem.FS, dir string, visit func(path string) error) error {
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() {
+			if err := visit(path); err != nil {
+				return err
+			}
+			continue
+		}
+		switch entry.Name() {
+		case "node_modules", ".git", "vendor", ".venv":
+			continue
+		}
+		if err := walkFiles(fsys, path, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commentBlock turns header text into line comments
+func commentBlock(header, prefix string) string {
+	lines := strings.Split(strings.TrimRight(header, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(prefix+line, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// prependHeader adds the header to the top of a file, after a shebang line,
+// followed by a blank line so Go does not read it as a package comment
+func prependHeader(content, header string) string {
+	if strings.HasPrefix(content, "#!") {
+		end := strings.Index(content, "\n")
+		if end == -1 {
+			return content + "\n" + header
+		}
+		return content[:end+1] + header + "\n" + content[end+1:]
+	}
+	return header + "\n" + content
+}
+
+// hasAny reports whether dir contains any of the files
+func hasAny(fsys filesystem.FS, dir string, files []string) bool {
+	for _, file := range files {
+		if filesystem.Exists(fsys, filepath.Join(dir, file)) {
+			return true
+		}
+	}
+	return false
+}
+
+// withNewline ensures content ends with a newline
+func withNewline(content string) string {
+	if strings.HasSuffix(content, "\n") {
+		return content
+	}
+	return content + "\n"
+}
@@ -0,0 +1,140 @@
+package policy
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+const policyYAML = `license:
+  header: |
+    Copyright (c) Acme Corp.
+    SPDX-License-Identifier: Apache-2.0
+formatters:
+  prettier: '{ "singleQuote": true }'
+  ruff: "line-length = 100"
+files:
+  - path: CODEOWNERS
+    content: "* @acme/platform\n"
+  - path: SECURITY.md
+    source: .om/SECURITY.md
+`
+
+func writeFiles(t *testing.T, fsys *filesystem.MemFS, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		if err := fsys.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsys.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	policy, err := Load(filesystem.NewMemFS(), "project")
+	if err != nil || policy != nil {
+		t.Fatalf("expected no policy, got %v (%v)", policy, err)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown formatter": "formatters:\n  black: \"line-length = 88\"\n",
+		"missing path":      "files:\n  - content: hi\n",
+		"escaping path":     "files:\n  - path: ../CODEOWNERS\n    content: hi\n",
+		"two sources":       "files:\n  - path: CODEOWNERS\n    content: hi\n    source: .om/CODEOWNERS\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			fsys := filesystem.NewMemFS()
+			writeFiles(t, fsys, map[string]string{filepath.Join("project", FileName): content})
+			if _, err := Load(fsys, "project"); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	writeFiles(t, fsys, map[string]string{
+		filepath.Join("project", FileName):                      policyYAML,
+		filepath.Join("project", ".om", "SECURITY.md"):          "Report issues to security@acme.test\n",
+		filepath.Join("project", "web", "package.json"):         "{}\n",
+		filepath.Join("project", "web", ".prettierrc"):          "{}\n",
+		filepath.Join("project", "web", "src", "index.ts"):      "export const answer = 42\n",
+		filepath.Join("project", "web", "scripts", "build.sh"):  "#!/bin/sh\nnpm run build\n",
+		filepath.Join("project", "web", "README.md"):            "# web\n",
+		filepath.Join("project", "web", "node_modules", "x.js"): "module.exports = 1\n",
+	})
+
+	policy, err := Load(fsys, "project")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	serviceDir := filepath.Join("project", "web")
+	changed, err := policy.Apply(fsys, "project", serviceDir)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := []string{".prettierrc", "CODEOWNERS", "SECURITY.md", "scripts/build.sh", "src/index.ts"}
+	if strings.Join(changed, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, changed)
+	}
+
+	read := func(path string) string {
+		data, err := fsys.ReadFile(filepath.Join(serviceDir, path))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		return string(data)
+	}
+	if got := read(".prettierrc"); got != "{ \"singleQuote\": true }\n" {
+		t.Errorf("expected the policy's prettier config to replace the template's, got %q", got)
+	}
+	if filesystem.Exists(fsys, filepath.Join(serviceDir, "ruff.toml")) {
+		t.Error("expected no ruff config for a Node service")
+	}
+	if got := read("SECURITY.md"); got != "Report issues to security@acme.test\n" {
+		t.Errorf("unexpected SECURITY.md %q", got)
+	}
+	if got := read(filepath.Join("src", "index.ts")); got != "// Copyright (c) Acme Corp.\n// SPDX-License-Identifier: Apache-2.0\n\nexport const answer = 42\n" {
+		t.Errorf("unexpected header in index.ts:\n%s", got)
+	}
+	if got := read(filepath.Join("scripts", "build.sh")); !strings.HasPrefix(got, "#!/bin/sh\n# Copyright (c) Acme Corp.\n") {
+		t.Errorf("expected the header after the shebang, got:\n%s", got)
+	}
+	if got := read(filepath.Join("node_modules", "x.js")); strings.Contains(got, "Copyright") {
+		t.Error("expected node_modules to be skipped")
+	}
+
+	// Applying twice does not add a second header
+	if _, err := policy.Apply(fsys, "project", serviceDir); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(filepath.Join("src", "index.ts")); strings.Count(got, "Copyright") != 1 {
+		t.Errorf("expected a single header, got:\n%s", got)
+	}
+}
+
+func TestApply_LicenseExtensions(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	writeFiles(t, fsys, map[string]string{
+		filepath.Join("api", "main.py"): "print('hi')\n",
+		filepath.Join("api", "app.js"):  "console.log('hi')\n",
+	})
+	policy := &Policy{License: License{Header: "Acme", Extensions: []string{"py"}}}
+
+	changed, err := policy.Apply(fsys, ".", "api")
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if strings.Join(changed, ",") != "main.py" {
+		t.Errorf("expected only the Python file to get a header, got %v", changed)
+	}
+}
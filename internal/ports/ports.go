@@ -0,0 +1,216 @@
+// Package ports keeps the host ports a project publishes free of collisions
+// with other projects running on the same machine. The first time a port is
+// published, its host side is moved to the next free port when the one it
+// asks for is taken; the choice is recorded in .om/ports.lock so the same
+// mapping is used on every later run, whether or not the project is up.
+package ports
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// LockFile is the file, relative to the project root, that records the
+// host port of every published port
+var LockFile = filepath.Join(".om", "ports.lock")
+
+// maxPort is the highest port a mapping can be moved to
+const maxPort = 65535
+
+// Allocation is the host port one container port of a compose service is
+// published on
+type Allocation struct {
+	Service   string `json:"service"`
+	Container int    `json:"container"`
+	Host      int    `json:"host"`
+	Requested int    `json:"requested"` // host port the configuration asked for
+}
+
+// Moved reports whether the port is published somewhere other than where
+// the configuration asked for
+func (a Allocation) Moved() bool {
+	return a.Host != a.Requested
+}
+
+// Lock is the recorded host ports of a project
+type Lock struct {
+	Ports []Allocation `json:"ports"`
+}
+
+// HostPort returns the host port a container port of a compose service is
+// published on, or the container port itself when none is recorded
+func (l *Lock) HostPort(service string, container int) int {
+	if allocation, exists := l.find(service, container); exists {
+		return allocation.Host
+	}
+	return container
+}
+
+// find returns the recorded allocation of a container port
+func (l *Lock) find(service string, container int) (Allocation, bool) {
+	for _, allocation := range l.Ports {
+		if allocation.Service == service && allocation.Container == container {
+			return allocation, true
+		}
+	}
+	return Allocation{}, false
+}
+
+// Load reads the lock of the project. A project that never published a
+// port has an empty lock.
+func Load(fsys filesystem.FS, projectRoot string) (*Lock, error) {
+	data, err := fsys.ReadFile(filepath.Join(projectRoot, LockFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Lock{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", LockFile, err)
+	}
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", LockFile, err)
+	}
+	return &lock, nil
+}
+
+// Save writes the lock of the project
+func Save(fsys filesystem.FS, projectRoot string, lock *Lock) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the port lock: %w", err)
+	}
+	if err := fsys.MkdirAll(filepath.Join(projectRoot, filepath.Dir(LockFile)), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(LockFile), err)
+	}
+	if err := fsys.WriteFile(filepath.Join(projectRoot, LockFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", LockFile, err)
+	}
+	return nil
+}
+
+// Available reports whether nothing listens on the port on this machine
+func Available(port int) bool {
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// Assign publishes every port of config on the host port recorded in the
+// lock. Ports the lock does not know yet keep the host port they ask for
+// unless another mapping of the project has it or available reports it
+// taken, in which case they move to the next free port. The lock is updated
+// to hold exactly the ports of config, and the allocations made by this
+// call that were moved are returned. A nil available treats every port as
+// free. Mappings without a host port, and port ranges, are left as they are.
+func Assign(config *compose.DockerComposeConfig, lock *Lock, available func(port int) bool) []Allocation {
+	if available == nil {
+		available = func(int) bool { return true }
+	}
+
+	names := make([]string, 0, len(config.Services))
+	for name := range config.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Ports recorded for mappings that still exist stay reserved, so new
+	// mappings cannot take them
+	taken := make(map[int]bool)
+	for _, name := range names {
+		for _, value := range config.Services[name].Ports {
+			if m, ok := parseMapping(value); ok {
+				if allocation, exists := lock.find(name, m.container); exists {
+					taken[allocation.Host] = true
+				}
+			}
+		}
+	}
+
+	assigned := []Allocation{}
+	var moved []Allocation
+	for _, name := range names {
+		service := config.Services[name]
+		// The ports may be shared with the manifest, so they are copied
+		service.Ports = append([]string(nil), service.Ports...)
+		for i, value := range service.Ports {
+			m, ok := parseMapping(value)
+			if !ok {
+				continue
+			}
+			allocation, exists := lock.find(name, m.container)
+			if !exists || allocation.Requested != m.host {
+				allocation = Allocation{Service: name, Container: m.container, Requested: m.host, Host: m.host}
+				for allocation.Host < maxPort && (taken[allocation.Host] || !available(allocation.Host)) {
+					allocation.Host++
+				}
+				taken[allocation.Host] = true
+				if allocation.Moved() {
+					moved = append(moved, allocation)
+				}
+			}
+			m.host = allocation.Host
+			service.Ports[i] = m.String()
+			assigned = append(assigned, allocation)
+		}
+		config.Services[name] = service
+	}
+	lock.Ports = assigned
+	return moved
+}
+
+// mapping is a "[ip:]host:container[/protocol]" port mapping
+type mapping struct {
+	ip        string
+	host      int
+	container int
+	protocol  string
+}
+
+// parseMapping parses a mapping with a single host port
+func parseMapping(value string) (mapping, bool) {
+	var m mapping
+	spec, protocol, hasProtocol := strings.Cut(value, "/")
+	if hasProtocol {
+		m.protocol = protocol
+	}
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 {
+		return m, false
+	}
+	host, err := strconv.Atoi(parts[len(parts)-2])
+	if err != nil || host <= 0 {
+		return m, false
+	}
+	container, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil || container <= 0 {
+		return m, false
+	}
+	m.ip = strings.Join(parts[:len(parts)-2], ":")
+	m.host, m.container = host, container
+	return m, true
+}
+
+// String formats the mapping as compose writes it
+func (m mapping) String() string {
+	value := fmt.Sprintf("%d:%d", m.host, m.container)
+	if m.ip != "" {
+		value = m.ip + ":" + value
+	}
+	if m.protocol != "" {
+		value += "/" + m.protocol
+	}
+	return value
+}
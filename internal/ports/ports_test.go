@@ -0,0 +1,106 @@
+package ports
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func testConfig() *compose.DockerComposeConfig {
+	return &compose.DockerComposeConfig{Services: map[string]compose.DockerComposeService{
+		"api":     {Ports: []string{"8000:8000"}},
+		"api-db":  {Ports: []string{"127.0.0.1:5432:5432/tcp"}},
+		"gateway": {Ports: []string{"8080:80", "8443:443"}},
+		"proxy":   {Ports: []string{"8080:81", "9000"}},
+		"worker":  {},
+	}}
+}
+
+func TestAssign(t *testing.T) {
+	config := testConfig()
+	lock := &Lock{}
+	moved := Assign(config, lock, func(port int) bool { return port != 5432 && port != 5433 })
+
+	want := map[string][]string{
+		"api":     {"8000:8000"},
+		"api-db":  {"127.0.0.1:5434:5432/tcp"},
+		"gateway": {"8080:80", "8443:443"},
+		"proxy":   {"8081:81", "9000"},
+	}
+	for name, ports := range want {
+		if got := config.Services[name].Ports; !reflect.DeepEqual(got, ports) {
+			t.Errorf("%s publishes %v, want %v", name, got, ports)
+		}
+	}
+	wantMoved := []Allocation{
+		{Service: "api-db", Container: 5432, Host: 5434, Requested: 5432},
+		{Service: "proxy", Container: 81, Host: 8081, Requested: 8080},
+	}
+	if !reflect.DeepEqual(moved, wantMoved) {
+		t.Errorf("Assign() moved %+v, want %+v", moved, wantMoved)
+	}
+	if len(lock.Ports) != 5 {
+		t.Errorf("expected every mapping with a host port to be recorded, got %+v", lock.Ports)
+	}
+}
+
+func TestAssign_KeepsRecordedPorts(t *testing.T) {
+	lock := &Lock{}
+	Assign(testConfig(), lock, func(port int) bool { return port != 5432 })
+
+	// The project's own containers now hold their ports, and the gateway's
+	// HTTPS port is gone
+	config := testConfig()
+	config.Services["gateway"] = compose.DockerComposeService{Ports: []string{"8080:80"}}
+	config.Services["web"] = compose.DockerComposeService{Ports: []string{"5433:3000"}}
+	moved := Assign(config, lock, func(int) bool { return true })
+
+	if got := config.Services["api-db"].Ports; !reflect.DeepEqual(got, []string{"127.0.0.1:5433:5432/tcp"}) {
+		t.Errorf("expected the database to keep its recorded port, got %v", got)
+	}
+	if got := config.Services["web"].Ports; !reflect.DeepEqual(got, []string{"5434:3000"}) {
+		t.Errorf("expected a new port not to take a recorded one, got %v", got)
+	}
+	if len(moved) != 1 || moved[0].Service != "web" {
+		t.Errorf("expected only the new mapping to be reported as moved, got %+v", moved)
+	}
+	if lock.HostPort("gateway", 443) != 443 {
+		t.Errorf("expected mappings that are gone to be dropped from the lock, got %+v", lock.Ports)
+	}
+}
+
+func TestAssign_DoesNotChangeSharedPorts(t *testing.T) {
+	componentPorts := []string{"8080:80"}
+	config := &compose.DockerComposeConfig{Services: map[string]compose.DockerComposeService{
+		"gateway": {Ports: componentPorts},
+	}}
+	Assign(config, &Lock{}, func(port int) bool { return port != 8080 })
+	if componentPorts[0] != "8080:80" {
+		t.Errorf("expected the manifest's ports to be left alone, got %v", componentPorts)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	lock, err := Load(fsys, "project")
+	if err != nil || len(lock.Ports) != 0 {
+		t.Fatalf("expected an empty lock before anything is saved, got %+v, %v", lock, err)
+	}
+
+	lock.Ports = []Allocation{{Service: "api", Container: 8000, Host: 8001, Requested: 8000}}
+	if err := Save(fsys, "project", lock); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(fsys, "project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, lock) {
+		t.Errorf("Load() = %+v, want %+v", loaded, lock)
+	}
+	if loaded.HostPort("api", 8000) != 8001 || loaded.HostPort("web", 3000) != 3000 {
+		t.Errorf("unexpected host ports from %+v", loaded.Ports)
+	}
+}
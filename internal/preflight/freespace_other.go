@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd && !openbsd && !windows
+
+package preflight
+
+// FreeSpace reports ErrUnsupported, so the disk space check is skipped
+func FreeSpace(dir string) (uint64, error) {
+	return 0, ErrUnsupported
+}
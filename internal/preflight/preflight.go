@@ -0,0 +1,151 @@
+// Package preflight checks that a template can be scaffolded into a
+// directory before anything is written: that the disk has room for the
+// files and the dependencies their post-scaffold commands install, and, on
+// Windows, that the resulting paths stay within MAX_PATH.
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+)
+
+const (
+	// MinFreeBytes is the free space below which scaffolding is refused
+	MinFreeBytes = 50 << 20
+	// DependencyFreeBytes is the free space recommended for templates that
+	// install dependencies, e.g. a node_modules directory
+	DependencyFreeBytes = 500 << 20
+	// WindowsMaxPath is the longest path Windows supports without long
+	// path support enabled
+	WindowsMaxPath = 260
+	// dependencyPathMargin approximates how much deeper than the template's
+	// own files installed dependencies nest, e.g. node_modules/a/node_modules/b
+	dependencyPathMargin = 100
+)
+
+// ErrUnsupported is returned by FreeSpace on platforms where free disk
+// space cannot be determined
+var ErrUnsupported = errors.New("free disk space is not available on this platform")
+
+// installCommands are the post-scaffold commands that download dependencies
+var installCommands = []string{"npm install", "npm ci", "yarn", "pnpm install", "pip install", "poetry install", "go mod download", "bundle install"}
+
+// Options describes a scaffold to check
+type Options struct {
+	Dir       string   // Absolute directory the template is scaffolded into
+	Files     []string // Paths of the template's files, relative to Dir
+	Commands  []string // Post-scaffold commands of the template
+	TotalSize int64    // Combined size of the template's files in bytes
+	GOOS      string   // Operating system the scaffold runs on
+
+	// FreeSpace returns the bytes available to the user on the file system
+	// holding a directory
+	FreeSpace func(dir string) (uint64, error)
+}
+
+// Result holds the problems that stop scaffolding and the warnings to
+// report before it starts
+type Result struct {
+	Errors   []error
+	Warnings []warnings.Warning
+}
+
+// InstallsDependencies reports whether any command downloads dependencies
+func InstallsDependencies(commands []string) bool {
+	for _, command := range commands {
+		for _, install := range installCommands {
+			if strings.Contains(command, install) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Run checks free disk space and path lengths for a scaffold
+func Run(opts Options) Result {
+	var result Result
+	installs := InstallsDependencies(opts.Commands)
+	checkDiskSpace(opts, installs, &result)
+	if opts.GOOS == "windows" {
+		checkPathLength(opts, installs, &result)
+	}
+	return result
+}
+
+// checkDiskSpace fails when the disk cannot hold the template's files and
+// warns when it is unlikely to hold the dependencies they install
+func checkDiskSpace(opts Options, installs bool, result *Result) {
+	if opts.FreeSpace == nil {
+		return
+	}
+	free, err := opts.FreeSpace(existingAncestor(opts.Dir))
+	if err != nil {
+		// Free space is best effort: a failed probe must not block scaffolding
+		return
+	}
+
+	required := uint64(MinFreeBytes)
+	if size := uint64(opts.TotalSize) * 2; size > required {
+		required = size
+	}
+	if free < required {
+		result.Errors = append(result.Errors, fmt.Errorf("only %s free on the disk holding %s, at least %s is needed; free up space or choose a directory on another disk", formatBytes(free), opts.Dir, formatBytes(required)))
+		return
+	}
+	if installs && free < DependencyFreeBytes {
+		result.Warnings = append(result.Warnings, warnings.New("", "only %s free on the disk holding %s; installing the template's dependencies can take %s or more", formatBytes(free), opts.Dir, formatBytes(DependencyFreeBytes)))
+	}
+}
+
+// checkPathLength fails when a template file would exceed MAX_PATH and
+// warns when installed dependencies are likely to
+func checkPathLength(opts Options, installs bool, result *Result) {
+	longest := ""
+	for _, file := range opts.Files {
+		path := filepath.Join(opts.Dir, file)
+		if len(path) > len(longest) {
+			longest = path
+		}
+	}
+	if longest == "" {
+		longest = opts.Dir
+	}
+
+	guidance := "enable long paths (set LongPathsEnabled in the registry and run 'git config --system core.longpaths true') or choose a shorter project directory"
+	if len(longest) > WindowsMaxPath {
+		result.Errors = append(result.Errors, fmt.Errorf("%s is %d characters long, over the Windows limit of %d; %s", longest, len(longest), WindowsMaxPath, guidance))
+		return
+	}
+	if installs && len(longest)+dependencyPathMargin > WindowsMaxPath {
+		result.Warnings = append(result.Warnings, warnings.New("", "paths in %s are up to %d characters long, leaving little room for installed dependencies under the Windows limit of %d; %s", opts.Dir, len(longest), WindowsMaxPath, guidance))
+	}
+}
+
+// existingAncestor returns dir or its closest parent that exists, since the
+// scaffold directory is usually created later
+func existingAncestor(dir string) string {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// formatBytes formats a byte count in MB or GB
+func formatBytes(bytes uint64) string {
+	if bytes >= 1<<30 {
+		return fmt.Sprintf("%.1f GB", float64(bytes)/(1<<30))
+	}
+	return fmt.Sprintf("%d MB", bytes>>20)
+}
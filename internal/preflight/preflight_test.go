@@ -0,0 +1,83 @@
+package preflight
+
+import (
+	"strings"
+	"testing"
+)
+
+func freeSpace(bytes uint64) func(string) (uint64, error) {
+	return func(string) (uint64, error) { return bytes, nil }
+}
+
+func TestRun_DiskSpace(t *testing.T) {
+	tests := []struct {
+		name         string
+		free         uint64
+		commands     []string
+		wantErr      bool
+		wantWarnings int
+	}{
+		{name: "plenty of space", free: 10 << 30, commands: []string{"npm install"}},
+		{name: "below minimum", free: 10 << 20, wantErr: true},
+		{name: "little room for dependencies", free: 200 << 20, commands: []string{"npm install"}, wantWarnings: 1},
+		{name: "no dependencies to install", free: 200 << 20, commands: []string{"git init"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Run(Options{Dir: "/work/demo/api", Commands: tt.commands, GOOS: "linux", FreeSpace: freeSpace(tt.free)})
+			if (len(result.Errors) > 0) != tt.wantErr {
+				t.Errorf("errors = %v, wantErr %v", result.Errors, tt.wantErr)
+			}
+			if len(result.Warnings) != tt.wantWarnings {
+				t.Errorf("warnings = %v, want %d", result.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestRun_TemplateSizeRaisesMinimum(t *testing.T) {
+	result := Run(Options{Dir: "/work/api", TotalSize: 60 << 20, GOOS: "linux", FreeSpace: freeSpace(100 << 20)})
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), "at least 120 MB") {
+		t.Errorf("expected room for twice the template size to be required, got %v", result.Errors)
+	}
+}
+
+func TestRun_FreeSpaceUnavailable(t *testing.T) {
+	result := Run(Options{Dir: "/work/api", GOOS: "linux", FreeSpace: func(string) (uint64, error) { return 0, ErrUnsupported }})
+	if len(result.Errors) > 0 || len(result.Warnings) > 0 {
+		t.Errorf("expected an unavailable probe to be ignored, got %+v", result)
+	}
+}
+
+func TestRun_WindowsPathLength(t *testing.T) {
+	deepDir := `C:\Users\developer\` + strings.Repeat("nested-folder\\", 12) + "api"
+	tests := []struct {
+		name         string
+		dir          string
+		goos         string
+		commands     []string
+		wantErr      bool
+		wantWarnings int
+	}{
+		{name: "short path", dir: `C:\work\api`, goos: "windows", commands: []string{"npm install"}},
+		{name: "file over MAX_PATH", dir: deepDir + strings.Repeat("x", 100), goos: "windows", wantErr: true},
+		{name: "dependencies likely over MAX_PATH", dir: deepDir, goos: "windows", commands: []string{"npm install"}, wantWarnings: 1},
+		{name: "not checked elsewhere", dir: deepDir + strings.Repeat("x", 100), goos: "linux"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Run(Options{Dir: tt.dir, Files: []string{`src\components\App.tsx`}, Commands: tt.commands, GOOS: tt.goos})
+			if (len(result.Errors) > 0) != tt.wantErr {
+				t.Errorf("errors = %v, wantErr %v", result.Errors, tt.wantErr)
+			}
+			if len(result.Warnings) != tt.wantWarnings {
+				t.Errorf("warnings = %v, want %d", result.Warnings, tt.wantWarnings)
+			}
+			for _, err := range result.Errors {
+				if !strings.Contains(err.Error(), "enable long paths") {
+					t.Errorf("expected long path guidance, got %v", err)
+				}
+			}
+		})
+	}
+}
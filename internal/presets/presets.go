@@ -0,0 +1,175 @@
+// Package presets loads team presets: om-defaults.yaml files that pre-fill
+// template parameter answers, so services scaffolded by anyone on a team
+// start from the same testing, linting, and ownership choices.
+package presets
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of preset files discovered next to a project or in
+// any directory above it
+const FileName = "om-defaults.yaml"
+
+// fetchTimeout bounds downloading a preset from a URL
+const fetchTimeout = 10 * time.Second
+
+// Preset holds the answers a team pre-fills for template parameters
+type Preset struct {
+	Owner      string                            `yaml:"owner,omitempty"`      // default project owner
+	Parameters map[string]interface{}            `yaml:"parameters,omitempty"` // answers for every template
+	Templates  map[string]map[string]interface{} `yaml:"templates,omitempty"`  // answers for one template, overriding parameters
+
+	// Sources lists the files and URLs the preset was loaded from, lowest
+	// precedence first
+	Sources []string `yaml:"-"`
+}
+
+// Parse parses the content of a preset file
+func Parse(data []byte, source string) (*Preset, error) {
+	var preset Preset
+	if err := yaml.Unmarshal(data, &preset); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", source, err)
+	}
+	preset.Sources = []string{source}
+	return &preset, nil
+}
+
+// Discover loads every om-defaults.yaml from dir up to the file system root,
+// so an organization-wide file in a parent directory applies to all of its
+// repositories. Files closer to dir take precedence. It returns an empty
+// preset when there are none.
+func Discover(fsys filesystem.FS, dir string) (*Preset, error) {
+	var paths []string
+	for {
+		path := filepath.Join(dir, FileName)
+		if filesystem.Exists(fsys, path) {
+			paths = append(paths, path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	merged := &Preset{}
+	for i := len(paths) - 1; i >= 0; i-- {
+		data, err := fsys.ReadFile(paths[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", paths[i], err)
+		}
+		preset, err := Parse(data, paths[i])
+		if err != nil {
+			return nil, err
+		}
+		merged = merged.Merge(preset)
+	}
+	return merged, nil
+}
+
+// Load reads a preset from a file on fsys or, for http(s) sources, a URL
+func Load(fsys filesystem.FS, source string) (*Preset, error) {
+	if IsURL(source) {
+		return Fetch(&http.Client{Timeout: fetchTimeout}, source)
+	}
+	data, err := fsys.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	return Parse(data, source)
+}
+
+// IsURL reports whether a preset source is an http(s) URL
+func IsURL(source string) bool {
+	return strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://")
+}
+
+// Fetch downloads a preset from a URL
+func Fetch(client *http.Client, url string) (*Preset, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	return Parse(data, url)
+}
+
+// Merge returns a preset with the answers of other layered over those of p
+func (p *Preset) Merge(other *Preset) *Preset {
+	merged := &Preset{
+		Owner:      p.Owner,
+		Parameters: make(map[string]interface{}),
+		Templates:  make(map[string]map[string]interface{}),
+		Sources:    append(append([]string(nil), p.Sources...), other.Sources...),
+	}
+	if other.Owner != "" {
+		merged.Owner = other.Owner
+	}
+	for _, preset := range []*Preset{p, other} {
+		for name, value := range preset.Parameters {
+			merged.Parameters[name] = value
+		}
+		for template, answers := range preset.Templates {
+			if merged.Templates[template] == nil {
+				merged.Templates[template] = make(map[string]interface{})
+			}
+			for name, value := range answers {
+				merged.Templates[template][name] = value
+			}
+		}
+	}
+	return merged
+}
+
+// Answers returns the pre-filled answers for a template, keyed by parameter
+// name. Values are bool, string, or []string, matching the prompt kinds.
+// The owner is returned as the Owner parameter.
+func (p *Preset) Answers(templateName string) map[string]interface{} {
+	answers := make(map[string]interface{})
+	if p == nil {
+		return answers
+	}
+	if p.Owner != "" {
+		answers["Owner"] = p.Owner
+	}
+	for name, value := range p.Parameters {
+		answers[name] = normalize(value)
+	}
+	for name, value := range p.Templates[templateName] {
+		answers[name] = normalize(value)
+	}
+	return answers
+}
+
+// normalize converts a YAML value to the type of answer its prompt returns
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprintf("%v", item))
+		}
+		return values
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
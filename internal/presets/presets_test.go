@@ -0,0 +1,84 @@
+package presets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestDiscover(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	repo := filepath.Join("org", "shop")
+	if err := fsys.MkdirAll(filepath.Join(repo, "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join("org", FileName): "owner: Platform Team\nparameters:\n  IncludeTesting: true\n  TestingFramework: Jest\n",
+		filepath.Join(repo, FileName):  "parameters:\n  TestingFramework: Vitest\ntemplates:\n  go-api:\n    IncludeTesting: false\n    Features: [metrics, tracing]\n",
+	}
+	for path, content := range files {
+		if err := fsys.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	preset, err := Discover(fsys, filepath.Join(repo, "api"))
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if want := []string{filepath.Join("org", FileName), filepath.Join(repo, FileName)}; !reflect.DeepEqual(preset.Sources, want) {
+		t.Errorf("Sources = %v, want %v", preset.Sources, want)
+	}
+
+	// The repository file overrides the organization file, and template
+	// answers override answers for every template
+	want := map[string]interface{}{
+		"Owner":            "Platform Team",
+		"IncludeTesting":   false,
+		"TestingFramework": "Vitest",
+		"Features":         []string{"metrics", "tracing"},
+	}
+	if got := preset.Answers("go-api"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Answers(go-api) = %v, want %v", got, want)
+	}
+	if got := preset.Answers("fastapi-basic")["IncludeTesting"]; got != true {
+		t.Errorf("Answers(fastapi-basic)[IncludeTesting] = %v, want true", got)
+	}
+}
+
+func TestDiscover_NoPresets(t *testing.T) {
+	preset, err := Discover(filesystem.NewMemFS(), "project")
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if len(preset.Sources) != 0 || len(preset.Answers("any")) != 0 {
+		t.Errorf("expected an empty preset, got %+v", preset)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/om-defaults.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("owner: Platform Team\nparameters:\n  Port: 8080\n"))
+	}))
+	defer server.Close()
+
+	preset, err := Fetch(server.Client(), server.URL+"/om-defaults.yaml")
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if got := preset.Answers("any")["Port"]; got != "8080" {
+		t.Errorf("expected numbers to become strings, got %#v", got)
+	}
+
+	if _, err := Fetch(server.Client(), server.URL+"/missing.yaml"); err == nil {
+		t.Error("expected an error for a missing preset")
+	}
+}
@@ -0,0 +1,293 @@
+// Package projectdocs generates a project's README section and
+// ARCHITECTURE.md from workbench.yaml. Generated content lives in a managed
+// block delimited by marker comments, so regenerating replaces only that
+// block and keeps whatever the team wrote around it.
+package projectdocs
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+// Generated files, relative to the project root
+const (
+	ReadmeFile       = "README.md"
+	ArchitectureFile = "ARCHITECTURE.md"
+)
+
+// Markers delimiting the managed block of a generated file
+const (
+	BeginMarker = "<!-- om:docs:begin -->"
+	EndMarker   = "<!-- om:docs:end -->"
+)
+
+// notice opens every managed block
+const notice = "<!-- Generated by 'om generate docs' from workbench.yaml. Edits inside this block are overwritten. -->"
+
+// Generate returns the README and ARCHITECTURE.md of the project at
+// projectRoot with their managed blocks brought up to date, keyed by path
+// relative to the project root. Files that do not exist yet are created
+// with a title and the block.
+func Generate(fsys filesystem.FS, projectRoot string, m *manifest.WorkbenchManifest) (map[string][]byte, error) {
+	blocks := map[string]struct{ title, content string }{
+		ReadmeFile:       {m.Metadata.Name, Readme(m)},
+		ArchitectureFile: {m.Metadata.Name + " Architecture", Architecture(m)},
+	}
+
+	files := make(map[string][]byte, len(blocks))
+	for file, block := range blocks {
+		path := filepath.Join(projectRoot, file)
+		var existing []byte
+		if filesystem.Exists(fsys, path) {
+			data, err := fsys.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			existing = data
+		}
+		updated, err := UpdateBlock(existing, block.title, block.content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		files[file] = updated
+	}
+	return files, nil
+}
+
+// UpdateBlock replaces the managed block of a document with content. A
+// document without a block gets one appended; an empty document becomes a
+// title followed by the block.
+func UpdateBlock(document []byte, title, content string) ([]byte, error) {
+	block := BeginMarker + "\n" + notice + "\n\n" + strings.TrimRight(content, "\n") + "\n" + EndMarker + "\n"
+	if len(bytes.TrimSpace(document)) == 0 {
+		return []byte("# " + title + "\n\n" + block), nil
+	}
+
+	text := string(document)
+	begin := strings.Index(text, BeginMarker)
+	if begin < 0 {
+		return []byte(strings.TrimRight(text, "\n") + "\n\n" + block), nil
+	}
+	end := strings.Index(text[begin:], EndMarker)
+	if end < 0 {
+		return nil, fmt.Errorf("the generated block is missing its end marker %s", EndMarker)
+	}
+	end += begin + len(EndMarker)
+	rest := strings.TrimPrefix(text[end:], "\n")
+	return []byte(text[:begin] + block + rest), nil
+}
+
+// Readme renders the README section: the services and how to run them
+func Readme(m *manifest.WorkbenchManifest) string {
+	var b strings.Builder
+	b.WriteString("## Services\n\n")
+	writeServiceTable(&b, m)
+	b.WriteString("\n## Getting started\n\n")
+	writeGettingStarted(&b, m)
+	fmt.Fprintf(&b, "\nSee [%s](%s) for resources, environments, and dependencies.\n", ArchitectureFile, ArchitectureFile)
+	return b.String()
+}
+
+// Architecture renders ARCHITECTURE.md: every part of the project and how
+// the parts depend on each other
+func Architecture(m *manifest.WorkbenchManifest) string {
+	var b strings.Builder
+	b.WriteString("## Services\n\n")
+	writeServiceTable(&b, m)
+
+	if len(m.Components) > 0 {
+		b.WriteString("\n## Components\n\n")
+		b.WriteString("| Component | Template | Path | Ports |\n|---|---|---|---|\n")
+		for _, name := range slices.Sorted(maps.Keys(m.Components)) {
+			component := m.Components[name]
+			fmt.Fprintf(&b, "| %s | %s | `%s` | %s |\n", name, orDash(component.Template), component.Path, orDash(strings.Join(component.Ports, ", ")))
+		}
+	}
+
+	b.WriteString("\n## Resources\n\n")
+	writeResourceTable(&b, m)
+
+	if len(m.External) > 0 {
+		b.WriteString("\n## External dependencies\n\n")
+		b.WriteString("| Dependency | Description | URL |\n|---|---|---|\n")
+		for _, name := range slices.Sorted(maps.Keys(m.External)) {
+			external := m.External[name]
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", name, orDash(external.Description), external.URL)
+		}
+	}
+
+	b.WriteString("\n## Environments\n\n")
+	writeEnvironmentMatrix(&b, m)
+
+	b.WriteString("\n## Dependencies\n\n")
+	writeDiagram(&b, m)
+	return b.String()
+}
+
+// writeServiceTable lists every service with its template, port, and source
+func writeServiceTable(b *strings.Builder, m *manifest.WorkbenchManifest) {
+	if len(m.Services) == 0 {
+		b.WriteString("The project has no services yet. Add one with `om add service`.\n")
+		return
+	}
+	b.WriteString("| Service | Template | Port | Path |\n|---|---|---|---|\n")
+	for _, name := range slices.Sorted(maps.Keys(m.Services)) {
+		service := m.Services[name]
+		source := "`" + service.Path + "`"
+		if service.UsesImage() {
+			source = "image `" + service.Image + "`"
+		}
+		port := "-"
+		if service.Port > 0 {
+			port = fmt.Sprintf("%d", service.Port)
+			if protocol := service.ProtocolOrDefault(); protocol != manifest.ProtocolHTTP {
+				port += " (" + protocol + ")"
+			}
+		}
+		fmt.Fprintf(b, "| %s | %s | %s | %s |\n", name, orDash(service.Template), port, source)
+	}
+}
+
+// writeResourceTable lists the resources of every service
+func writeResourceTable(b *strings.Builder, m *manifest.WorkbenchManifest) {
+	var rows []string
+	for _, name := range slices.Sorted(maps.Keys(m.Services)) {
+		resources := m.Services[name].Resources
+		for _, resourceName := range slices.Sorted(maps.Keys(resources)) {
+			resource := resources[resourceName]
+			rows = append(rows, fmt.Sprintf("| %s | %s | %s | %s |\n", name, resourceName, resource.Type, orDash(resource.Version)))
+		}
+	}
+	if len(rows) == 0 {
+		b.WriteString("No service owns a resource. Add one with `om add resource`.\n")
+		return
+	}
+	b.WriteString("| Service | Resource | Type | Version |\n|---|---|---|---|\n")
+	b.WriteString(strings.Join(rows, ""))
+}
+
+// writeEnvironmentMatrix shows where each service runs: locally through
+// om compose or on the host, and in which deployment environments
+func writeEnvironmentMatrix(b *strings.Builder, m *manifest.WorkbenchManifest) {
+	environments := slices.Sorted(maps.Keys(m.Environments))
+	b.WriteString("| Service | local")
+	for _, environment := range environments {
+		b.WriteString(" | " + environment)
+	}
+	b.WriteString(" |\n|---|---" + strings.Repeat("|---", len(environments)) + "|\n")
+
+	for _, name := range slices.Sorted(maps.Keys(m.Services)) {
+		service := m.Services[name]
+		local := "✓"
+		if service.RunsOnHost() {
+			local = "host"
+		} else if service.IsLibrary() {
+			local = "library"
+		}
+		b.WriteString("| " + name + " | " + local)
+		for _, environment := range environments {
+			cell := ""
+			if m.Environments[environment].DeploysService(name, service) && m.InEnvironment(environment, name) {
+				cell = "✓"
+			}
+			b.WriteString(" | " + cell)
+		}
+		b.WriteString(" |\n")
+	}
+	if len(environments) == 0 {
+		b.WriteString("\nNo deployment environments are defined yet.\n")
+	}
+}
+
+// writeDiagram draws the project as a Mermaid graph: references between
+// services and components, the resources each service owns, and the
+// external dependencies they call
+func writeDiagram(b *strings.Builder, m *manifest.WorkbenchManifest) {
+	b.WriteString("```mermaid\ngraph LR\n")
+	for _, name := range slices.Sorted(maps.Keys(m.Components)) {
+		fmt.Fprintf(b, "  %s[\"%s\"]\n", nodeID("component", name), name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.Services)) {
+		fmt.Fprintf(b, "  %s([\"%s\"])\n", nodeID("service", name), name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.External)) {
+		fmt.Fprintf(b, "  %s{{\"%s\"}}\n", nodeID("external", name), name)
+	}
+
+	var names []string
+	names = append(names, slices.Sorted(maps.Keys(m.Components))...)
+	names = append(names, slices.Sorted(maps.Keys(m.Services))...)
+	for _, name := range names {
+		from := memberID(m, name)
+		for _, referenced := range m.References(name) {
+			fmt.Fprintf(b, "  %s --> %s\n", from, memberID(m, referenced))
+		}
+		if service, exists := m.Services[name]; exists {
+			for _, resourceName := range slices.Sorted(maps.Keys(service.Resources)) {
+				id := nodeID("resource", name+"-"+resourceName)
+				fmt.Fprintf(b, "  %s --> %s[(\"%s: %s\")]\n", from, id, resourceName, service.Resources[resourceName].Type)
+			}
+		}
+		for _, external := range m.ExternalReferences(name) {
+			fmt.Fprintf(b, "  %s -.-> %s\n", from, nodeID("external", external))
+		}
+	}
+	b.WriteString("```\n")
+}
+
+// memberID returns the node of a service or component
+func memberID(m *manifest.WorkbenchManifest, name string) string {
+	if _, exists := m.Services[name]; exists {
+		return nodeID("service", name)
+	}
+	return nodeID("component", name)
+}
+
+// nodeID returns a Mermaid node identifier. Names are prefixed with their
+// kind so a service and a component of the same name stay distinct.
+func nodeID(kind, name string) string {
+	id := []byte(kind + "_" + name)
+	for i, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			id[i] = '_'
+		}
+	}
+	return string(id)
+}
+
+// writeGettingStarted lists the commands that bring the project up locally
+func writeGettingStarted(b *strings.Builder, m *manifest.WorkbenchManifest) {
+	b.WriteString("```bash\n# Generate docker-compose.yml from workbench.yaml\nom compose --target docker\n\n# Build and start the stack\ndocker compose up --build\n")
+	for _, name := range slices.Sorted(maps.Keys(m.Services)) {
+		if service := m.Services[name]; service.RunsOnHost() {
+			fmt.Fprintf(b, "\n# Start %s on this machine\ncd %s && %s\n", name, service.Path, service.Dev)
+		}
+	}
+	b.WriteString("```\n")
+
+	var urls []string
+	for _, name := range slices.Sorted(maps.Keys(m.Services)) {
+		service := m.Services[name]
+		if service.IsContainer() && service.Port > 0 && service.ProtocolOrDefault() == manifest.ProtocolHTTP {
+			urls = append(urls, fmt.Sprintf("- %s: http://localhost:%d\n", name, service.Port))
+		}
+	}
+	if len(urls) > 0 {
+		b.WriteString("\nOnce the stack is up:\n\n" + strings.Join(urls, ""))
+	}
+}
+
+// orDash returns value, or a dash for empty table cells
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
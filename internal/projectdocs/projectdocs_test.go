@@ -0,0 +1,204 @@
+package projectdocs
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+func docsManifest() *manifest.WorkbenchManifest {
+	return &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "shop"},
+		Environments: map[string]manifest.Environment{
+			"dev":  {Provider: "aws"},
+			"prod": {Provider: "aws", Config: map[string]string{"services": "api"}},
+		},
+		Components: map[string]manifest.Component{
+			"gateway": {Template: "nginx-gateway", Path: "./gateway", Ports: []string{"8080:80"},
+				Environment: map[string]string{"UPSTREAM": "${services.api.name}"}},
+		},
+		External: map[string]manifest.External{
+			"stripe": {Description: "Payments API", URL: "https://api.stripe.com"},
+		},
+		Services: map[string]manifest.Service{
+			"api": {Template: "fastapi-basic", Path: "./api", Port: 8000,
+				Resources:   map[string]manifest.Resource{"db": {Type: "postgres-db", Version: "16"}},
+				Environment: map[string]string{"STRIPE_URL": "${external.stripe.url}"}},
+			"web":    {Template: "react-typescript", Path: "./web", Port: 4173},
+			"mobile": {Template: "expo-app", Path: "./mobile", Kind: manifest.ServiceKindLocal, Dev: "npx expo start"},
+		},
+	}
+}
+
+func TestArchitecture(t *testing.T) {
+	content := Architecture(docsManifest())
+
+	for _, want := range []string{
+		"| api | fastapi-basic | 8000 | `./api` |",
+		"| gateway | nginx-gateway | `./gateway` | 8080:80 |",
+		"| api | db | postgres-db | 16 |",
+		"| stripe | Payments API | https://api.stripe.com |",
+		"| Service | local | dev | prod |",
+		"| api | ✓ | ✓ | ✓ |",
+		"| web | ✓ | ✓ |  |",
+		"| mobile | host |  |  |",
+		"component_gateway --> service_api",
+		"service_api --> resource_api_db[(\"db: postgres-db\")]",
+		"service_api -.-> external_stripe",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in:\n%s", want, content)
+		}
+	}
+}
+
+func TestReadme(t *testing.T) {
+	content := Readme(docsManifest())
+
+	for _, want := range []string{
+		"om compose --target docker",
+		"cd ./mobile && npx expo start",
+		"- api: http://localhost:8000",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in:\n%s", want, content)
+		}
+	}
+}
+
+func TestUpdateBlock(t *testing.T) {
+	created, err := UpdateBlock(nil, "shop", "first\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(created), "# shop\n\n"+BeginMarker) {
+		t.Errorf("expected a title and the block, got:\n%s", created)
+	}
+
+	// Text around the block survives regeneration
+	edited := "Intro written by hand.\n\n" + string(created[len("# shop\n\n"):]) + "\n## Notes\nKeep me.\n"
+	updated, err := UpdateBlock([]byte(edited), "shop", "second\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(updated)
+	if strings.Contains(text, "first") || !strings.Contains(text, "second\n"+EndMarker) {
+		t.Errorf("expected the block to be replaced, got:\n%s", text)
+	}
+	if !strings.HasPrefix(text, "Intro written by hand.\n\n") || !strings.HasSuffix(text, "\n## Notes\nKeep me.\n") {
+		t.Errorf("expected hand-written text to be kept, got:\n%s", text)
+	}
+
+	// Documents without a block get one appended
+	appended, err := UpdateBlock([]byte("# Existing\n"), "shop", "content")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(appended), "# Existing\n\n"+BeginMarker) {
+		t.Errorf("expected the block after the existing text, got:\n%s", appended)
+	}
+
+	if _, err := UpdateBlock([]byte(BeginMarker+"\nunterminated\n"), "shop", "content"); err == nil {
+		t.Error("expected an error for a block without an end marker")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	if err := fsys.MkdirAll("shop", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(filepath.Join("shop", ReadmeFile), []byte("# Shop\n\nOur storefront.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := Generate(fsys, "shop", docsManifest())
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if readme := string(files[ReadmeFile]); !strings.HasPrefix(readme, "# Shop\n\nOur storefront.\n\n"+BeginMarker) {
+		t.Errorf("expected the README block after the existing text, got:\n%s", readme)
+	}
+	if architecture := string(files[ArchitectureFile]); !strings.HasPrefix(architecture, "# shop Architecture\n\n") {
+		t.Errorf("expected a new ARCHITECTURE.md, got:\n%s", architecture)
+	}
+}
+
+func TestServiceReadme(t *testing.T) {
+	m := docsManifest()
+	api := m.Services["api"]
+	api.EnvDocs = map[string]string{"STRIPE_URL": "Where payments are sent"}
+	m.Services["api"] = api
+	content := ServiceReadme(m, "api", []Parameter{{Name: "ServiceName", Value: "api"}, {Name: "Command", Value: "a | b"}})
+
+	for _, want := range []string{
+		"Scaffolded from the `fastapi-basic` template with:",
+		"| `Command` | a \\| b |",
+		"docker compose up --build api\n",
+		"docker build -t shop-api .\ndocker run --rm -p 8000:8000 --env-file ../.env shop-api\n",
+		"Listens on port 8000 (http). With the project running it is reachable on http://localhost:8000",
+		"| `STRIPE_URL` | `${external.stripe.url}` | Where payments are sent |",
+		"| `api_db_password` | from `.env` | Password of the user |",
+		"| db | postgres-db | 16 |",
+		"- stripe (external)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected the README to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	mobile := ServiceReadme(m, "mobile", nil)
+	if !strings.Contains(mobile, "On its own, from this directory:\n\n```bash\nnpx expo start\n```") || strings.Contains(mobile, "docker compose up") {
+		t.Errorf("expected a host service to be run with its dev command, got:\n%s", mobile)
+	}
+	if strings.Contains(mobile, parametersHeading) {
+		t.Errorf("expected no parameters section without parameters, got:\n%s", mobile)
+	}
+}
+
+func TestGenerateServiceReadme(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	m := docsManifest()
+	m.Services["api"] = manifest.Service{Template: "fastapi-basic", Path: "./apps/api", Port: 8000}
+	if err := fsys.MkdirAll(filepath.Join("shop", "apps", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(filepath.Join("shop", "apps", "api", "README.md"), []byte("# API\n\nFrom the template.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	parameters := []Parameter{{Name: "ServiceName", Value: "api"}, {Name: "Command", Value: "a | b"}, {Name: "Extra", Value: ""}}
+	file, data, err := GenerateServiceReadme(fsys, "shop", m, "api", parameters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file != "apps/api/README.md" || !strings.HasPrefix(string(data), "# API\n\nFrom the template.\n\n"+BeginMarker) {
+		t.Fatalf("expected the block to be appended to apps/api/README.md, got %s:\n%s", file, data)
+	}
+	if !strings.Contains(string(data), "--env-file ../../.env") {
+		t.Errorf("expected the env file to be found from the nested directory, got:\n%s", data)
+	}
+	if err := fsys.WriteFile(filepath.Join("shop", file), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Refreshing keeps the parameters it was scaffolded with
+	m.Services["api"] = manifest.Service{Template: "fastapi-basic", Path: "./apps/api", Port: 9000}
+	_, refreshed, err := GenerateServiceReadme(fsys, "shop", m, "api", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := blockParameters(string(refreshed)); len(got) != 3 || got[1] != parameters[1] || got[2] != parameters[2] {
+		t.Errorf("expected the parameters %v to be kept, got %v", parameters, got)
+	}
+	if !strings.Contains(string(refreshed), "Listens on port 9000") || strings.Count(string(refreshed), BeginMarker) != 1 {
+		t.Errorf("expected the block to be replaced, got:\n%s", refreshed)
+	}
+
+	if _, _, err := GenerateServiceReadme(fsys, "shop", m, "missing", nil); err == nil {
+		t.Error("expected an error for a service without a directory")
+	}
+}
@@ -0,0 +1,215 @@
+package projectdocs
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/compose"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+// ServiceReadmeFile is the README of a service, relative to its directory
+const ServiceReadmeFile = "README.md"
+
+// parametersHeading opens the section listing the template parameters,
+// which is read back when the README is refreshed without them
+const parametersHeading = "## Template parameters"
+
+// Parameter is a template parameter a service was scaffolded with
+type Parameter struct {
+	Name  string
+	Value string
+}
+
+// GenerateServiceReadme returns the README of a service with its managed
+// block brought up to date, and its path relative to the project root. The
+// template parameters are only known when the service is scaffolded; with
+// nil parameters those of the existing block are kept.
+func GenerateServiceReadme(fsys filesystem.FS, projectRoot string, m *manifest.WorkbenchManifest, name string, parameters []Parameter) (string, []byte, error) {
+	service, exists := m.Services[name]
+	if !exists || service.Path == "" {
+		return "", nil, fmt.Errorf("service '%s' has no directory to write a README to", name)
+	}
+	file := path.Join(path.Clean(service.Path), ServiceReadmeFile)
+	target := filepath.Join(projectRoot, filepath.FromSlash(file))
+
+	var existing []byte
+	if filesystem.Exists(fsys, target) {
+		data, err := fsys.ReadFile(target)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		existing = data
+	}
+	if parameters == nil {
+		parameters = blockParameters(string(existing))
+	}
+	updated, err := UpdateBlock(existing, name, ServiceReadme(m, name, parameters))
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return file, updated, nil
+}
+
+// HasBlock reports whether a document has a managed block
+func HasBlock(document []byte) bool {
+	return strings.Contains(string(document), BeginMarker)
+}
+
+// ServiceReadme renders the README section of one service: what it was
+// scaffolded with, how to run it with the project and on its own, and the
+// port, variables, resources, and services it relies on
+func ServiceReadme(m *manifest.WorkbenchManifest, name string, parameters []Parameter) string {
+	service := m.Services[name]
+	var b strings.Builder
+
+	if len(parameters) > 0 {
+		b.WriteString(parametersHeading + "\n\n")
+		fmt.Fprintf(&b, "Scaffolded from the `%s` template with:\n\n", orDash(service.Template))
+		b.WriteString("| Parameter | Value |\n|---|---|\n")
+		for _, parameter := range parameters {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", parameter.Name, tableCell(parameter.Value))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Running\n\n")
+	writeServiceRunning(&b, m, name, service)
+
+	if service.Port > 0 {
+		b.WriteString("\n## Port\n\n")
+		fmt.Fprintf(&b, "Listens on port %d (%s).", service.Port, service.ProtocolOrDefault())
+		if service.IsContainer() && service.ProtocolOrDefault() == manifest.ProtocolHTTP {
+			fmt.Fprintf(&b, " With the project running it is reachable on http://localhost:%d, unless `om ports` shows it was moved to a free port.", service.Port)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n## Environment variables\n\n")
+	writeServiceEnvironment(&b, m, name, service)
+
+	if len(service.Resources) > 0 {
+		b.WriteString("\n## Resources\n\n")
+		b.WriteString("| Resource | Type | Version |\n|---|---|---|\n")
+		for _, resourceName := range slices.Sorted(maps.Keys(service.Resources)) {
+			resource := service.Resources[resourceName]
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", resourceName, resource.Type, orDash(resource.Version))
+		}
+	}
+
+	references := m.References(name)
+	externals := m.ExternalReferences(name)
+	if len(references) > 0 || len(externals) > 0 {
+		b.WriteString("\n## Depends on\n\n")
+		for _, referenced := range references {
+			kind := "service"
+			if _, exists := m.Components[referenced]; exists {
+				kind = "component"
+			}
+			fmt.Fprintf(&b, "- %s (%s)\n", referenced, kind)
+		}
+		for _, external := range externals {
+			fmt.Fprintf(&b, "- %s (external)\n", external)
+		}
+	}
+	return b.String()
+}
+
+// writeServiceRunning gives the commands that run the service with the rest
+// of the project and on its own
+func writeServiceRunning(b *strings.Builder, m *manifest.WorkbenchManifest, name string, service manifest.Service) {
+	if service.IsLibrary() {
+		b.WriteString("This is a shared library. It does not run on its own; it is built into the services that list it under `libraries`.\n")
+		return
+	}
+
+	root := strings.Repeat("../", strings.Count(path.Clean(service.Path), "/")+1)
+	b.WriteString("With the rest of the project, from the project root:\n\n```bash\n")
+	b.WriteString("# Everything, following the logs\nom dev\n")
+	if service.IsContainer() {
+		fmt.Fprintf(b, "\n# Only %s and what it depends on\nom compose --target docker\ndocker compose up --build %s\n", name, name)
+	}
+	b.WriteString("```\n\nOn its own, from this directory:\n\n```bash\n")
+	if service.RunsOnHost() {
+		fmt.Fprintf(b, "%s\n", service.Dev)
+	} else {
+		image := m.Metadata.Name + "-" + name
+		fmt.Fprintf(b, "docker build -t %s .\n", image)
+		run := "docker run --rm"
+		if service.Port > 0 {
+			run += fmt.Sprintf(" -p %d:%d", service.Port, service.Port)
+		}
+		fmt.Fprintf(b, "%s --env-file %s.env %s\n", run, root, image)
+	}
+	b.WriteString("```\n")
+	if len(service.Resources) > 0 || len(m.References(name)) > 0 {
+		b.WriteString("\nOn its own the service cannot reach its resources or the services it depends on by name; run them with `om dev` or point its variables at other instances.\n")
+	}
+}
+
+// writeServiceEnvironment lists the variables the service is given in
+// workbench.yaml and those of its resources in the project's .env
+func writeServiceEnvironment(b *strings.Builder, m *manifest.WorkbenchManifest, name string, service manifest.Service) {
+	var rows []string
+	for _, key := range slices.Sorted(maps.Keys(service.Environment)) {
+		rows = append(rows, fmt.Sprintf("| `%s` | `%s` | %s |\n", key, tableCell(service.Environment[key]), tableCell(service.EnvDocs[key])))
+	}
+	for _, section := range compose.EnvSections(m) {
+		if section.Service != name {
+			continue
+		}
+		for _, variable := range section.Variables {
+			rows = append(rows, fmt.Sprintf("| `%s` | from `.env` | %s |\n", variable.Name, tableCell(variable.Description)))
+		}
+	}
+	if len(rows) == 0 {
+		b.WriteString("The service is given no variables in `workbench.yaml`; add them under `environment` and describe them under `envDocs`.\n")
+		return
+	}
+	b.WriteString("| Variable | Value | Description |\n|---|---|---|\n")
+	b.WriteString(strings.Join(rows, ""))
+}
+
+// blockParameters reads the template parameters back from the managed
+// block of a service README
+func blockParameters(document string) []Parameter {
+	begin := strings.Index(document, BeginMarker)
+	if begin < 0 {
+		return nil
+	}
+	block := document[begin:]
+	if end := strings.Index(block, EndMarker); end >= 0 {
+		block = block[:end]
+	}
+	start := strings.Index(block, parametersHeading)
+	if start < 0 {
+		return nil
+	}
+
+	var parameters []Parameter
+	for _, line := range strings.Split(block[start+len(parametersHeading):], "\n") {
+		if strings.HasPrefix(line, "## ") {
+			break
+		}
+		if !strings.HasPrefix(line, "| `") {
+			continue
+		}
+		name, value, ok := strings.Cut(strings.TrimPrefix(line, "| `"), "` | ")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "|"))
+		parameters = append(parameters, Parameter{Name: name, Value: strings.ReplaceAll(value, `\|`, "|")})
+	}
+	return parameters
+}
+
+// tableCell escapes a value for a Markdown table cell
+func tableCell(value string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(value, "|", `\|`), "\n", " ")
+}
@@ -0,0 +1,144 @@
+package prompt
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// JSONQuestion is the message written for every question in the JSON protocol
+type JSONQuestion struct {
+	Type     string      `json:"type"` // always "prompt"
+	Kind     Kind        `json:"kind"`
+	Name     string      `json:"name"`
+	Message  string      `json:"message"`
+	Help     string      `json:"help,omitempty"`
+	Default  interface{} `json:"default,omitempty"`
+	Options  []string    `json:"options,omitempty"`
+	Required bool        `json:"required,omitempty"`
+}
+
+// JSONAnswer is the message a frontend sends back for each question
+type JSONAnswer struct {
+	Name      string          `json:"name"`
+	Value     json.RawMessage `json:"value"`
+	Cancelled bool            `json:"cancelled,omitempty"`
+}
+
+// jsonPrompter speaks a line-delimited JSON protocol: one JSONQuestion per
+// line on the writer, one JSONAnswer per line on the reader
+type jsonPrompter struct {
+	mutex   sync.Mutex
+	scanner *bufio.Scanner
+	writer  io.Writer
+}
+
+// NewJSONPrompter creates a Prompter for frontends (API, TUI) that exchange
+// questions and answers as JSON lines
+func NewJSONPrompter(r io.Reader, w io.Writer) Prompter {
+	return &jsonPrompter{
+		scanner: bufio.NewScanner(r),
+		writer:  w,
+	}
+}
+
+// ask sends a question and decodes the answer value into target
+func (p *jsonPrompter) ask(kind Kind, q Question, target interface{}) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	message, err := json.Marshal(JSONQuestion{
+		Type:     "prompt",
+		Kind:     kind,
+		Name:     q.Name,
+		Message:  q.Message,
+		Help:     q.Help,
+		Default:  q.Default,
+		Options:  q.Options,
+		Required: q.Required,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode question %q: %w", q.Name, err)
+	}
+	if _, err := fmt.Fprintf(p.writer, "%s\n", message); err != nil {
+		return fmt.Errorf("failed to send question %q: %w", q.Name, err)
+	}
+
+	if !p.scanner.Scan() {
+		if err := p.scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read answer for %q: %w", q.Name, err)
+		}
+		return fmt.Errorf("no answer received for %q: %w", q.Name, io.ErrUnexpectedEOF)
+	}
+
+	var answer JSONAnswer
+	if err := json.Unmarshal(p.scanner.Bytes(), &answer); err != nil {
+		return fmt.Errorf("invalid answer for %q: %w", q.Name, err)
+	}
+	if answer.Cancelled {
+		return ErrCancelled
+	}
+	if answer.Name != "" && answer.Name != q.Name {
+		return fmt.Errorf("answer for %q received while waiting for %q", answer.Name, q.Name)
+	}
+	if len(answer.Value) == 0 || string(answer.Value) == "null" {
+		if q.Default != nil {
+			answer.Value, _ = json.Marshal(q.Default)
+		} else {
+			return fmt.Errorf("answer for %q has no value", q.Name)
+		}
+	}
+	if err := json.Unmarshal(answer.Value, target); err != nil {
+		return fmt.Errorf("invalid value for %q: %w", q.Name, err)
+	}
+	return nil
+}
+
+// Input asks for a line of text
+func (p *jsonPrompter) Input(q Question) (string, error) {
+	var answer string
+	if err := p.ask(KindInput, q, &answer); err != nil {
+		return "", err
+	}
+	if err := q.checkInput(answer); err != nil {
+		return "", err
+	}
+	return answer, nil
+}
+
+// Confirm asks a yes/no question
+func (p *jsonPrompter) Confirm(q Question) (bool, error) {
+	var answer bool
+	if err := p.ask(KindConfirm, q, &answer); err != nil {
+		return false, err
+	}
+	return answer, nil
+}
+
+// Select asks the user to pick one option
+func (p *jsonPrompter) Select(q Question) (string, error) {
+	var answer string
+	if err := p.ask(KindSelect, q, &answer); err != nil {
+		return "", err
+	}
+	if err := q.checkOption(answer); err != nil {
+		return "", err
+	}
+	return answer, nil
+}
+
+// MultiSelect asks the user to pick any number of options
+func (p *jsonPrompter) MultiSelect(q Question) ([]string, error) {
+	var answer []string
+	if err := p.ask(KindMultiSelect, q, &answer); err != nil {
+		return nil, err
+	}
+	for _, item := range answer {
+		if err := q.checkOption(item); err != nil {
+			return nil, err
+		}
+	}
+	return answer, nil
+}
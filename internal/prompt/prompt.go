@@ -0,0 +1,106 @@
+// Package prompt abstracts interactive questions asked by the CLI so that
+// commands can be driven by a terminal, by scripted answers in tests, or by
+// a JSON line protocol used by alternative frontends.
+package prompt
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+)
+
+// ErrCancelled is returned when the user cancels a question (e.g. Ctrl+C).
+// It is the same value survey returns, so errors.Is works for every frontend.
+var ErrCancelled = terminal.InterruptErr
+
+// Kind identifies the type of answer a question expects
+type Kind string
+
+const (
+	// KindInput asks for free-form text
+	KindInput Kind = "input"
+	// KindConfirm asks a yes/no question
+	KindConfirm Kind = "confirm"
+	// KindSelect asks for exactly one of Options
+	KindSelect Kind = "select"
+	// KindMultiSelect asks for any number of Options
+	KindMultiSelect Kind = "multiselect"
+)
+
+// Question describes a single prompt shown to the user
+type Question struct {
+	Name     string             // Stable identifier used by scripted and JSON frontends
+	Message  string             // Question text
+	Help     string             // Extra guidance shown on request, in markdown
+	Default  interface{}        // string, bool, or []string depending on the prompt kind
+	Options  []string           // Choices for select and multiselect prompts
+	Required bool               // Whether an empty answer is rejected
+	Validate func(string) error // Optional validation for input prompts
+}
+
+// Prompter asks questions and returns the user's answers
+type Prompter interface {
+	// Input asks for a line of text
+	Input(q Question) (string, error)
+
+	// Confirm asks a yes/no question
+	Confirm(q Question) (bool, error)
+
+	// Select asks the user to pick one option
+	Select(q Question) (string, error)
+
+	// MultiSelect asks the user to pick any number of options
+	MultiSelect(q Question) ([]string, error)
+}
+
+// defaultString returns the question default as a string
+func (q Question) defaultString() string {
+	if s, ok := q.Default.(string); ok {
+		return s
+	}
+	return ""
+}
+
+// defaultBool returns the question default as a bool
+func (q Question) defaultBool() bool {
+	if b, ok := q.Default.(bool); ok {
+		return b
+	}
+	return false
+}
+
+// defaultStrings returns the question default as a string slice
+func (q Question) defaultStrings() []string {
+	switch v := q.Default.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprintf("%v", item))
+		}
+		return values
+	}
+	return nil
+}
+
+// checkInput applies the Required and Validate rules to an input answer
+func (q Question) checkInput(answer string) error {
+	if q.Required && answer == "" {
+		return fmt.Errorf("%s: value is required", q.Name)
+	}
+	if q.Validate != nil {
+		return q.Validate(answer)
+	}
+	return nil
+}
+
+// checkOption verifies that answer is one of the question's options
+func (q Question) checkOption(answer string) error {
+	for _, option := range q.Options {
+		if option == answer {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %q is not a valid option", q.Name, answer)
+}
@@ -0,0 +1,100 @@
+package prompt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestScripted(t *testing.T) {
+	p := NewScripted(map[string]interface{}{
+		"name":     "api",
+		"testing":  true,
+		"template": "react",
+		"features": []string{"lint"},
+		"cancel":   ErrCancelled,
+	})
+
+	if name, err := p.Input(Question{Name: "name", Required: true}); err != nil || name != "api" {
+		t.Errorf("Input() = %q, %v", name, err)
+	}
+	if ok, err := p.Confirm(Question{Name: "testing"}); err != nil || !ok {
+		t.Errorf("Confirm() = %v, %v", ok, err)
+	}
+	if choice, err := p.Select(Question{Name: "template", Options: []string{"react", "vue"}}); err != nil || choice != "react" {
+		t.Errorf("Select() = %q, %v", choice, err)
+	}
+	if choices, err := p.MultiSelect(Question{Name: "features", Options: []string{"lint", "test"}}); err != nil || len(choices) != 1 {
+		t.Errorf("MultiSelect() = %v, %v", choices, err)
+	}
+	if value, err := p.Input(Question{Name: "unscripted", Default: "fallback"}); err != nil || value != "fallback" {
+		t.Errorf("expected default answer, got %q, %v", value, err)
+	}
+	if _, err := p.Input(Question{Name: "missing"}); err == nil {
+		t.Error("expected error for unscripted question without default")
+	}
+	if _, err := p.Select(Question{Name: "template", Options: []string{"vue"}}); err == nil {
+		t.Error("expected error for answer outside of options")
+	}
+	if _, err := p.Confirm(Question{Name: "cancel"}); !errors.Is(err, ErrCancelled) {
+		t.Errorf("expected ErrCancelled, got %v", err)
+	}
+	if asked := p.Asked(); len(asked) != 8 {
+		t.Errorf("expected 8 recorded questions, got %d", len(asked))
+	}
+}
+
+func TestJSONPrompter(t *testing.T) {
+	input := strings.Join([]string{
+		`{"name":"template","value":"vue"}`,
+		`{"name":"confirm","value":true}`,
+		`{"name":"serviceName"}`,
+		`{"cancelled":true}`,
+	}, "\n")
+	var output bytes.Buffer
+	p := NewJSONPrompter(strings.NewReader(input), &output)
+
+	choice, err := p.Select(Question{Name: "template", Message: "Template?", Options: []string{"react", "vue"}})
+	if err != nil || choice != "vue" {
+		t.Fatalf("Select() = %q, %v", choice, err)
+	}
+	confirmed, err := p.Confirm(Question{Name: "confirm", Message: "Sure?"})
+	if err != nil || !confirmed {
+		t.Fatalf("Confirm() = %v, %v", confirmed, err)
+	}
+	name, err := p.Input(Question{Name: "serviceName", Message: "Name?", Default: "backend"})
+	if err != nil || name != "backend" {
+		t.Fatalf("expected default answer, got %q, %v", name, err)
+	}
+	if _, err := p.Input(Question{Name: "other", Message: "Other?"}); !errors.Is(err, ErrCancelled) {
+		t.Fatalf("expected ErrCancelled, got %v", err)
+	}
+	if _, err := p.Input(Question{Name: "eof", Message: "EOF?"}); err == nil {
+		t.Fatal("expected error when the frontend stops answering")
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 questions written, got %d", len(lines))
+	}
+	var first JSONQuestion
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("question is not valid JSON: %v", err)
+	}
+	if first.Type != "prompt" || first.Kind != KindSelect || first.Name != "template" || len(first.Options) != 2 {
+		t.Errorf("unexpected question: %+v", first)
+	}
+}
+
+func TestRenderHelp(t *testing.T) {
+	got := renderHelp("Use `go mod init`, see [the docs](https://go.dev/ref/mod).\n\n```\ngo mod init example.com/api\n```\n")
+	want := "Use go mod init, see the docs (https://go.dev/ref/mod).\n\n    go mod init example.com/api"
+	if got != want {
+		t.Errorf("renderHelp() = %q, want %q", got, want)
+	}
+	if renderHelp("") != "" {
+		t.Error("renderHelp() of no help should be empty")
+	}
+}
@@ -0,0 +1,137 @@
+package prompt
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Scripted answers questions from a predefined set of answers keyed by
+// Question.Name. It is intended for tests and non-interactive automation.
+//
+// An answer may be a string, bool, []string, or an error; an error answer is
+// returned as-is, which allows tests to simulate cancellation with ErrCancelled.
+// Questions without a scripted answer fall back to their default when one is
+// set, and fail otherwise.
+type Scripted struct {
+	mutex   sync.Mutex
+	answers map[string]interface{}
+	asked   []Question
+}
+
+// NewScripted creates a Prompter that answers from the given map
+func NewScripted(answers map[string]interface{}) *Scripted {
+	if answers == nil {
+		answers = make(map[string]interface{})
+	}
+	return &Scripted{answers: answers}
+}
+
+// Asked returns the questions asked so far, in order
+func (s *Scripted) Asked() []Question {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Question(nil), s.asked...)
+}
+
+// Unasked returns the names of scripted answers no question has used yet,
+// sorted. Tests use it to catch answers that no longer match any prompt.
+func (s *Scripted) Unasked() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	asked := make(map[string]bool, len(s.asked))
+	for _, q := range s.asked {
+		asked[q.Name] = true
+	}
+	var names []string
+	for name := range s.answers {
+		if !asked[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// answer looks up the scripted answer for q, falling back to its default
+func (s *Scripted) answer(q Question) (interface{}, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.asked = append(s.asked, q)
+	value, ok := s.answers[q.Name]
+	if !ok {
+		if q.Default != nil {
+			return q.Default, nil
+		}
+		return nil, fmt.Errorf("no scripted answer for question %q (%s)", q.Name, q.Message)
+	}
+	if err, isErr := value.(error); isErr {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Input asks for a line of text
+func (s *Scripted) Input(q Question) (string, error) {
+	value, err := s.answer(q)
+	if err != nil {
+		return "", err
+	}
+	answer, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("scripted answer for %q must be a string, got %T", q.Name, value)
+	}
+	if err := q.checkInput(answer); err != nil {
+		return "", err
+	}
+	return answer, nil
+}
+
+// Confirm asks a yes/no question
+func (s *Scripted) Confirm(q Question) (bool, error) {
+	value, err := s.answer(q)
+	if err != nil {
+		return false, err
+	}
+	answer, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("scripted answer for %q must be a bool, got %T", q.Name, value)
+	}
+	return answer, nil
+}
+
+// Select asks the user to pick one option
+func (s *Scripted) Select(q Question) (string, error) {
+	value, err := s.answer(q)
+	if err != nil {
+		return "", err
+	}
+	answer, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("scripted answer for %q must be a string, got %T", q.Name, value)
+	}
+	if err := q.checkOption(answer); err != nil {
+		return "", err
+	}
+	return answer, nil
+}
+
+// MultiSelect asks the user to pick any number of options
+func (s *Scripted) MultiSelect(q Question) ([]string, error) {
+	value, err := s.answer(q)
+	if err != nil {
+		return nil, err
+	}
+	answer, ok := value.([]string)
+	if !ok {
+		return nil, fmt.Errorf("scripted answer for %q must be a []string, got %T", q.Name, value)
+	}
+	for _, item := range answer {
+		if err := q.checkOption(item); err != nil {
+			return nil, err
+		}
+	}
+	return answer, nil
+}
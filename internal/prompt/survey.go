@@ -0,0 +1,86 @@
+package prompt
+
+import (
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/jashkahar/open-workbench-platform/internal/help"
+)
+
+// surveyPrompter asks questions on the terminal using survey
+type surveyPrompter struct{}
+
+// NewSurveyPrompter creates a Prompter that asks questions on the terminal
+func NewSurveyPrompter() Prompter {
+	return &surveyPrompter{}
+}
+
+// Input asks for a line of text
+func (p *surveyPrompter) Input(q Question) (string, error) {
+	var answer string
+	prompt := &survey.Input{
+		Message: q.Message,
+		Help:    renderHelp(q.Help),
+		Default: q.defaultString(),
+	}
+
+	err := survey.AskOne(prompt, &answer, survey.WithValidator(func(val interface{}) error {
+		str, _ := val.(string)
+		return q.checkInput(str)
+	}))
+	return answer, err
+}
+
+// Confirm asks a yes/no question
+func (p *surveyPrompter) Confirm(q Question) (bool, error) {
+	var answer bool
+	prompt := &survey.Confirm{
+		Message: q.Message,
+		Help:    renderHelp(q.Help),
+		Default: q.defaultBool(),
+	}
+
+	err := survey.AskOne(prompt, &answer)
+	return answer, err
+}
+
+// Select asks the user to pick one option
+func (p *surveyPrompter) Select(q Question) (string, error) {
+	var answer string
+	prompt := &survey.Select{
+		Message: q.Message,
+		Options: q.Options,
+		Help:    renderHelp(q.Help),
+	}
+	if def := q.defaultString(); def != "" {
+		prompt.Default = def
+	}
+
+	err := survey.AskOne(prompt, &answer)
+	return answer, err
+}
+
+// MultiSelect asks the user to pick any number of options
+func (p *surveyPrompter) MultiSelect(q Question) ([]string, error) {
+	var answer []string
+	prompt := &survey.MultiSelect{
+		Message: q.Message,
+		Options: q.Options,
+		Help:    renderHelp(q.Help),
+	}
+	if def := q.defaultStrings(); len(def) > 0 {
+		prompt.Default = def
+	}
+
+	err := survey.AskOne(prompt, &answer)
+	return answer, err
+}
+
+// renderHelp turns the markdown of a question's help into plain text for the
+// terminal; survey colors the help itself
+func renderHelp(markdown string) string {
+	if markdown == "" {
+		return ""
+	}
+	return strings.TrimRight(help.Render(markdown, false), "\n")
+}
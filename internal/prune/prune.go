@@ -0,0 +1,169 @@
+// Package prune finds the Docker containers, volumes, and images of a
+// project whose workbench.yaml entry no longer exists. Generated compose
+// files label everything with the project name and the entry behind it
+// (see labels.Project and labels.Entry); an artifact is orphaned
+// when its entry names a service, component, resource, or external
+// dependency the manifest no longer has.
+package prune
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/labels"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+// Kinds of Docker artifacts, in the order they are removed: containers
+// first, since they hold on to their volumes and images
+const (
+	KindContainer = "container"
+	KindVolume    = "volume"
+	KindImage     = "image"
+)
+
+// Artifact is a labeled Docker container, volume, or image
+type Artifact struct {
+	Kind  string
+	ID    string
+	Name  string
+	Entry string // workbench.yaml entry it was generated from
+}
+
+// String describes the artifact for listings
+func (a Artifact) String() string {
+	return fmt.Sprintf("%s %s (%s)", a.Kind, a.Name, a.Entry)
+}
+
+// List returns the containers, volumes, and images labeled with the project
+func List(run deps.Runner, dir, project string) ([]Artifact, error) {
+	filter := fmt.Sprintf("label=%s=%s", labels.Project, project)
+	entry := fmt.Sprintf(`{{.Label %q}}`, labels.Entry)
+
+	var artifacts []Artifact
+	output, err := run(dir, "docker", "ps", "--all", "--filter", filter, "--format", "{{.ID}}\t{{.Names}}\t"+entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	artifacts = append(artifacts, parse(KindContainer, output, true)...)
+
+	output, err = run(dir, "docker", "volume", "ls", "--filter", filter, "--format", "{{.Name}}\t"+entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+	artifacts = append(artifacts, parse(KindVolume, output, false)...)
+
+	// docker image ls cannot print labels, so the images are inspected
+	output, err = run(dir, "docker", "image", "ls", "--quiet", "--no-trunc", "--filter", filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+	ids := strings.Fields(string(output))
+	if len(ids) > 0 {
+		format := "{{.Id}}\t{{join .RepoTags \",\"}}\t" + fmt.Sprintf(`{{index .Config.Labels %q}}`, labels.Entry)
+		args := append([]string{"image", "inspect", "--format", format}, unique(ids)...)
+		output, err = run(dir, "docker", args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect images: %w", err)
+		}
+		artifacts = append(artifacts, parse(KindImage, output, true)...)
+	}
+	return artifacts, nil
+}
+
+// parse reads one artifact per line of tab-separated fields: the ID, the
+// name when withName is set, and the entry. Volumes are known by name only.
+func parse(kind string, output []byte, withName bool) []Artifact {
+	var artifacts []Artifact
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if fields[0] == "" {
+			continue
+		}
+		artifact := Artifact{Kind: kind, ID: fields[0], Name: fields[0]}
+		rest := fields[1:]
+		if withName && len(rest) > 0 {
+			if rest[0] != "" && rest[0] != "<no value>" {
+				artifact.Name = rest[0]
+			}
+			rest = rest[1:]
+		}
+		if len(rest) > 0 && rest[0] != "<no value>" {
+			artifact.Entry = rest[0]
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts
+}
+
+// unique returns values without repeats, keeping their order
+func unique(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var result []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// Orphaned returns the artifacts whose entry the manifest no longer has.
+// Artifacts without an entry, or generated from something other than a
+// single entry, such as the API docs portal, are never orphaned.
+func Orphaned(artifacts []Artifact, m *manifest.WorkbenchManifest) []Artifact {
+	var orphaned []Artifact
+	for _, artifact := range artifacts {
+		if !hasEntry(m, artifact.Entry) {
+			orphaned = append(orphaned, artifact)
+		}
+	}
+	return orphaned
+}
+
+// hasEntry reports whether the manifest still has the entry an artifact was
+// generated from, or whether the entry is not one prune understands
+func hasEntry(m *manifest.WorkbenchManifest, entry string) bool {
+	parts := strings.Split(entry, ".")
+	switch {
+	case len(parts) == 2 && parts[0] == "services" && parts[1] != "*":
+		_, exists := m.Services[parts[1]]
+		return exists
+	case len(parts) == 4 && parts[0] == "services" && parts[2] == "resources":
+		_, exists := m.Services[parts[1]].Resources[parts[3]]
+		return exists
+	case len(parts) == 2 && parts[0] == "components":
+		_, exists := m.Components[parts[1]]
+		return exists
+	case len(parts) == 2 && parts[0] == "external":
+		_, exists := m.External[parts[1]]
+		return exists
+	}
+	return true
+}
+
+// Remove deletes the artifacts, containers first, then volumes, then images.
+// It stops at the first failure and returns the artifacts removed so far.
+func Remove(run deps.Runner, dir string, artifacts []Artifact) ([]Artifact, error) {
+	commands := map[string][]string{
+		KindContainer: {"rm", "--force"},
+		KindVolume:    {"volume", "rm"},
+		KindImage:     {"image", "rm"},
+	}
+	var removed []Artifact
+	for _, kind := range []string{KindContainer, KindVolume, KindImage} {
+		for _, artifact := range artifacts {
+			if artifact.Kind != kind {
+				continue
+			}
+			args := append(append([]string(nil), commands[kind]...), artifact.ID)
+			if _, err := run(dir, "docker", args...); err != nil {
+				return removed, fmt.Errorf("failed to remove %s %s: %w", kind, artifact.Name, err)
+			}
+			removed = append(removed, artifact)
+		}
+	}
+	return removed, nil
+}
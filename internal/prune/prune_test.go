@@ -0,0 +1,79 @@
+package prune
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+// fakeDocker answers docker commands from canned output and records them
+type fakeDocker struct {
+	outputs map[string]string // keyed by the first two arguments
+	ran     []string
+}
+
+func (f *fakeDocker) run(dir, name string, args ...string) ([]byte, error) {
+	f.ran = append(f.ran, strings.Join(append([]string{name}, args...), " "))
+	key := args[0]
+	if len(args) > 1 {
+		key += " " + args[1]
+	}
+	return []byte(f.outputs[key]), nil
+}
+
+func TestListAndOrphaned(t *testing.T) {
+	docker := &fakeDocker{outputs: map[string]string{
+		"ps --all":      "c1\tdemo-api-1\tservices.api\nc2\tdemo-old-1\tservices.old\nc3\tdemo-docs-1\tservices.*.api.spec\n",
+		"volume ls":     "demo_api_db_data\tservices.api.resources.db\ndemo_api_cache_data\tservices.api.resources.cache\n",
+		"image ls":      "sha256:i1\nsha256:i2\n",
+		"image inspect": "sha256:i1\tdemo-api:latest\tservices.api\nsha256:i2\t\tcomponents.gateway\n",
+	}}
+
+	artifacts, err := List(docker.run, "project", "demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(artifacts) != 7 {
+		t.Fatalf("expected 7 artifacts, got %v", artifacts)
+	}
+	if artifacts[4].Kind != KindVolume || artifacts[4].Name != "demo_api_cache_data" {
+		t.Errorf("expected volumes to be known by name, got %+v", artifacts[4])
+	}
+	if artifacts[6].Name != "sha256:i2" {
+		t.Errorf("expected an untagged image to be known by its ID, got %+v", artifacts[6])
+	}
+	if !strings.Contains(docker.ran[0], "label=com.openworkbench.project=demo") {
+		t.Errorf("expected the listing to filter by project, ran %v", docker.ran)
+	}
+
+	m := &manifest.WorkbenchManifest{Services: map[string]manifest.Service{
+		"api": {Path: "./api", Resources: map[string]manifest.Resource{"db": {Type: "postgres-db"}}},
+	}}
+	var names []string
+	for _, artifact := range Orphaned(artifacts, m) {
+		names = append(names, artifact.Name)
+	}
+	if strings.Join(names, ",") != "demo-old-1,demo_api_cache_data,sha256:i2" {
+		t.Errorf("expected the old service, cache volume, and gateway image to be orphaned, got %v", names)
+	}
+}
+
+func TestRemove_ContainersFirst(t *testing.T) {
+	docker := &fakeDocker{}
+	removed, err := Remove(docker.run, "project", []Artifact{
+		{Kind: KindImage, ID: "sha256:i1"},
+		{Kind: KindVolume, ID: "demo_old_data"},
+		{Kind: KindContainer, ID: "c1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 3 {
+		t.Errorf("expected 3 removals, got %v", removed)
+	}
+	want := "docker rm --force c1|docker volume rm demo_old_data|docker image rm sha256:i1"
+	if got := strings.Join(docker.ran, "|"); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,299 @@
+// Package refs finds where a part of the manifest is used: the ${...}
+// references and lists of workbench.yaml and its included files that name
+// it, and the lines of generated files that mention it, such as the compose
+// service of a resource or the host name another service reaches it on.
+// It answers "what breaks if I rename or delete this?" before doing so.
+package refs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNotFound is returned by ParseTarget when the manifest does not define
+// what it names
+var ErrNotFound = errors.New("not defined in workbench.yaml")
+
+// Target is a part of the manifest to look for
+type Target struct {
+	Entry string   // manifest path, e.g. services.api.resources.db or infra.assets_bucket
+	Name  string   // name services and components are listed under in the manifest
+	Names []string // names it goes by in generated files, e.g. the compose service api-db
+}
+
+// Ref is one place a target is used
+type Ref struct {
+	File  string // relative to the project root
+	Line  int
+	Field string // manifest path of the value; empty in generated files
+	Text  string // the value, or the line of a generated file
+}
+
+// ParseTarget returns the target an argument names: a service or component
+// by name, a resource as <service>.<resource>, or a manifest path such as
+// services.api.resources.db, external.payments, infra.assets_bucket, or
+// env.region
+func ParseTarget(m *manifest.WorkbenchManifest, arg string) (Target, error) {
+	parts := strings.Split(arg, ".")
+	switch {
+	case len(parts) == 2 && parts[0] == "services":
+		return serviceTarget(m, parts[1])
+	case len(parts) == 4 && parts[0] == "services" && parts[2] == "resources":
+		return resourceTarget(m, parts[1], parts[3])
+	case len(parts) == 2 && parts[0] == "components":
+		return componentTarget(m, parts[1])
+	case len(parts) == 2 && parts[0] == "external":
+		if _, exists := m.External[parts[1]]; !exists {
+			return Target{}, fmt.Errorf("external dependency '%s' is %w", parts[1], ErrNotFound)
+		}
+		return Target{Entry: arg}, nil
+	case len(parts) == 2 && parts[0] == "infra":
+		if _, exists := m.Infra[parts[1]]; !exists {
+			return Target{}, fmt.Errorf("infra output '%s' is %w", parts[1], ErrNotFound)
+		}
+		return Target{Entry: arg}, nil
+	case len(parts) == 2 && parts[0] == "env":
+		for name, environment := range m.Environments {
+			if _, defined := environment.Value(name, parts[1]); defined {
+				return Target{Entry: arg}, nil
+			}
+		}
+		return Target{}, fmt.Errorf("environment key '%s' is %w", parts[1], ErrNotFound)
+	case len(parts) == 1:
+		if _, exists := m.Components[arg]; exists {
+			return componentTarget(m, arg)
+		}
+		return serviceTarget(m, arg)
+	case len(parts) == 2:
+		return resourceTarget(m, parts[0], parts[1])
+	}
+	return Target{}, fmt.Errorf("cannot tell what '%s' names; use a service, component, <service>.<resource>, or a path such as external.<name>", arg)
+}
+
+func serviceTarget(m *manifest.WorkbenchManifest, name string) (Target, error) {
+	if _, exists := m.Services[name]; !exists {
+		return Target{}, fmt.Errorf("service '%s' is %w", name, ErrNotFound)
+	}
+	return Target{Entry: "services." + name, Name: name, Names: []string{name}}, nil
+}
+
+func componentTarget(m *manifest.WorkbenchManifest, name string) (Target, error) {
+	if _, exists := m.Components[name]; !exists {
+		return Target{}, fmt.Errorf("component '%s' is %w", name, ErrNotFound)
+	}
+	return Target{Entry: "components." + name, Name: name, Names: []string{name}}, nil
+}
+
+func resourceTarget(m *manifest.WorkbenchManifest, service, resource string) (Target, error) {
+	if _, exists := m.Services[service].Resources[resource]; !exists {
+		return Target{}, fmt.Errorf("resource '%s' of service '%s' is %w", resource, service, ErrNotFound)
+	}
+	return Target{
+		Entry: fmt.Sprintf("services.%s.resources.%s", service, resource),
+		Names: []string{service + "-" + resource},
+	}, nil
+}
+
+// referencePattern matches ${...} references to the target and to anything
+// under it
+func (t Target) referencePattern() *regexp.Regexp {
+	return regexp.MustCompile(`\$\{` + regexp.QuoteMeta(t.Entry) + `[.}]`)
+}
+
+// generatedPattern matches the lines of generated files that mention the
+// target: its references, its entry label, and the names it goes by
+func (t Target) generatedPattern() *regexp.Regexp {
+	alternatives := []string{`\$\{` + regexp.QuoteMeta(t.Entry) + `[.}]`, `(^|[^\w.-])` + regexp.QuoteMeta(t.Entry) + `($|[^\w-])`}
+	for _, name := range t.Names {
+		alternatives = append(alternatives, `(^|[^\w.-])`+regexp.QuoteMeta(name)+`($|[^\w.-])`)
+	}
+	return regexp.MustCompile(strings.Join(alternatives, "|"))
+}
+
+// Find returns the references to the target in the manifest files and in
+// the generated files, each relative to projectRoot. Directories among the
+// generated files are searched through, apart from hidden ones such as
+// terraform/.terraform, and files that do not exist are skipped.
+func Find(fsys filesystem.FS, projectRoot string, manifestFiles, generated []string, target Target) ([]Ref, error) {
+	var refs []Ref
+	for _, file := range manifestFiles {
+		data, err := fsys.ReadFile(filepath.Join(projectRoot, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		found, err := InManifest(file, data, target)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, found...)
+	}
+
+	files, err := expand(fsys, projectRoot, generated)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := fsys.ReadFile(filepath.Join(projectRoot, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		refs = append(refs, InGenerated(file, data, target)...)
+	}
+	return refs, nil
+}
+
+// InManifest returns the references to the target in one manifest file:
+// values that refer to it with ${...}, and the lists that name a service or
+// component (consumes, libraries, dependsOn, groups, and the include and
+// exclude lists and config.services of environments)
+func InManifest(file string, data []byte, target Target) ([]Ref, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	pattern := target.referencePattern()
+	var refs []Ref
+	walk(&document, nil, false, func(path []string, node *yaml.Node, listed bool) {
+		if pattern.MatchString(node.Value) || (target.Name != "" && names(path, node, listed, target.Name)) {
+			refs = append(refs, Ref{File: file, Line: node.Line, Field: strings.Join(path, "."), Text: node.Value})
+		}
+	})
+	return refs, nil
+}
+
+// names reports whether a scalar at path lists a service or component by
+// name
+func names(path []string, node *yaml.Node, listed bool, name string) bool {
+	switch {
+	case len(path) == 4 && path[0] == "environments" && path[2] == "config" && path[3] == "services":
+		for _, service := range strings.Split(node.Value, ",") {
+			if strings.TrimSpace(service) == name {
+				return true
+			}
+		}
+		return false
+	case len(path) == 4 && path[0] == "services" && path[2] == "dependsOn" && path[3] == "service":
+		return node.Value == name
+	case !listed || node.Value != name:
+		return false
+	case len(path) == 2 && path[0] == "groups":
+		return true
+	case len(path) == 3 && path[0] == "services":
+		return path[2] == "consumes" || path[2] == "libraries" || path[2] == "dependsOn"
+	case len(path) == 3 && path[0] == "environments":
+		return path[2] == "include" || path[2] == "exclude"
+	}
+	return false
+}
+
+// walk calls visit for every scalar under node with the keys that lead to
+// it and whether it is an item of a list
+func walk(node *yaml.Node, path []string, listed bool, visit func(path []string, node *yaml.Node, listed bool)) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			walk(child, path, false, visit)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			walk(node.Content[i+1], append(append([]string(nil), path...), node.Content[i].Value), false, visit)
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			walk(child, path, true, visit)
+		}
+	case yaml.ScalarNode:
+		visit(path, node, listed)
+	}
+}
+
+// InGenerated returns the lines of a generated file that mention the target
+func InGenerated(file string, data []byte, target Target) []Ref {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return nil
+	}
+	pattern := target.generatedPattern()
+	var refs []Ref
+	for i, line := range strings.Split(string(data), "\n") {
+		if pattern.MatchString(line) {
+			refs = append(refs, Ref{File: file, Line: i + 1, Text: strings.TrimSpace(line)})
+		}
+	}
+	return refs
+}
+
+// EnvFiles returns the .env files that may sit next to every service and
+// component
+func EnvFiles(m *manifest.WorkbenchManifest) []string {
+	var dirs []string
+	for _, service := range m.Services {
+		dirs = append(dirs, service.Path)
+	}
+	for _, component := range m.Components {
+		dirs = append(dirs, component.Path)
+	}
+	var files []string
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		for _, name := range []string{".env", ".env.example"} {
+			files = append(files, filepath.ToSlash(filepath.Join(dir, name)))
+		}
+	}
+	return files
+}
+
+// expand returns the existing files among paths, with the files under
+// directories in their place, sorted and without duplicates
+func expand(fsys filesystem.FS, projectRoot string, paths []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var files []string
+	var add func(path string) error
+	add = func(path string) error {
+		info, err := fsys.Stat(filepath.Join(projectRoot, filepath.FromSlash(path)))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			if !seen[path] {
+				seen[path] = true
+				files = append(files, path)
+			}
+			return nil
+		}
+		entries, err := fsys.ReadDir(filepath.Join(projectRoot, filepath.FromSlash(path)))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			if err := add(path + "/" + entry.Name()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, path := range paths {
+		if err := add(filepath.ToSlash(filepath.Clean(path))); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,169 @@
+package refs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+const workbench = `metadata:
+  name: demo
+services:
+  api:
+    path: ./api
+    port: 8000
+    resources:
+      db:
+        type: postgres-db
+    environment:
+      DATABASE_HOST: ${services.api.resources.db.name}
+  web:
+    path: ./web
+    consumes: [api]
+    dependsOn:
+      - service: api
+        condition: started
+    environment:
+      API_URL: ${services.api.url}
+      APIARY: ${services.apiary.url}
+groups:
+  backend: [api]
+environments:
+  dev:
+    provider: aws
+    exclude:
+      - web
+    config:
+      services: api, web
+`
+
+const compose = `services:
+    # om: services.api (service)
+    api:
+        build:
+            context: ./api
+    # om: services.api.resources.db (resource)
+    api-db:
+        image: postgres:15
+    web:
+        environment:
+            - API_URL=http://api:8000
+            - DATABASE_HOST=api-db
+            - APIARY_URL=http://apiary:8000
+`
+
+func testManifest() *manifest.WorkbenchManifest {
+	return &manifest.WorkbenchManifest{
+		Services: map[string]manifest.Service{
+			"api": {Path: "./api", Resources: map[string]manifest.Resource{"db": {Type: "postgres-db"}}},
+			"web": {Path: "./web"},
+		},
+		Components:   map[string]manifest.Component{"gateway": {Path: "./gateway"}},
+		External:     map[string]manifest.External{"payments": {URL: "https://payments.example"}},
+		Infra:        map[string]string{"assets_bucket": "assets"},
+		Environments: map[string]manifest.Environment{"dev": {Provider: "aws", Region: "eu-west-1"}},
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		arg   string
+		entry string
+	}{
+		{"api", "services.api"},
+		{"services.api", "services.api"},
+		{"gateway", "components.gateway"},
+		{"api.db", "services.api.resources.db"},
+		{"services.api.resources.db", "services.api.resources.db"},
+		{"external.payments", "external.payments"},
+		{"infra.assets_bucket", "infra.assets_bucket"},
+		{"env.region", "env.region"},
+	}
+	for _, tt := range tests {
+		target, err := ParseTarget(testManifest(), tt.arg)
+		if err != nil || target.Entry != tt.entry {
+			t.Errorf("ParseTarget(%q) = %q, %v, want %q", tt.arg, target.Entry, err, tt.entry)
+		}
+	}
+
+	for _, arg := range []string{"billing", "api.cache", "external.mail", "infra.cdn", "env.account"} {
+		if _, err := ParseTarget(testManifest(), arg); !errors.Is(err, ErrNotFound) {
+			t.Errorf("ParseTarget(%q): expected ErrNotFound, got %v", arg, err)
+		}
+	}
+	if _, err := ParseTarget(testManifest(), "a.b.c"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a path that names nothing to be rejected, got %v", err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	files := map[string]string{
+		"demo/workbench.yaml":            workbench,
+		"demo/docker-compose.yml":        compose,
+		"demo/terraform/main.tf":         "module \"api\" {}\n",
+		"demo/terraform/.terraform/x.tf": "module \"api\" {}\n",
+		"demo/api/.env":                  "DATABASE_HOST=api-db\n",
+	}
+	for _, dir := range []string{"demo/terraform/.terraform", "demo/api"} {
+		if err := fsys.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path, content := range files {
+		if err := fsys.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	generated := append([]string{"docker-compose.yml", ".env", "terraform"}, EnvFiles(testManifest())...)
+
+	target, _ := ParseTarget(testManifest(), "api")
+	found, err := Find(fsys, "demo", []string{"workbench.yaml"}, generated, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, ref := range found {
+		got = append(got, ref.File+":"+ref.Field+":"+ref.Text)
+	}
+	want := []string{
+		"workbench.yaml:services.api.environment.DATABASE_HOST:${services.api.resources.db.name}",
+		"workbench.yaml:services.web.consumes:api",
+		"workbench.yaml:services.web.dependsOn.service:api",
+		"workbench.yaml:services.web.environment.API_URL:${services.api.url}",
+		"workbench.yaml:groups.backend:api",
+		"workbench.yaml:environments.dev.config.services:api, web",
+		"docker-compose.yml::# om: services.api (service)",
+		"docker-compose.yml::api:",
+		"docker-compose.yml::context: ./api",
+		"docker-compose.yml::# om: services.api.resources.db (resource)",
+		"docker-compose.yml::- API_URL=http://api:8000",
+		"terraform/main.tf::module \"api\" {}",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Find() =\n%v\nwant\n%v", got, want)
+	}
+
+	target, _ = ParseTarget(testManifest(), "api.db")
+	found, err = Find(fsys, "demo", []string{"workbench.yaml"}, generated, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	for _, ref := range found {
+		got = append(got, ref.File+":"+ref.Text)
+	}
+	want = []string{
+		"workbench.yaml:${services.api.resources.db.name}",
+		"api/.env:DATABASE_HOST=api-db",
+		"docker-compose.yml:# om: services.api.resources.db (resource)",
+		"docker-compose.yml:api-db:",
+		"docker-compose.yml:- DATABASE_HOST=api-db",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Find() =\n%v\nwant\n%v", got, want)
+	}
+}
@@ -0,0 +1,216 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/debuglog"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// Runner runs an external command with stdin and returns its standard output
+type Runner func(stdin []byte, name string, args ...string) ([]byte, error)
+
+// ExecRunner runs commands on the host
+func ExecRunner(stdin []byte, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	// Arguments are logged but stdin is not, since it carries secrets
+	debuglog.Printf("running: %s %s", name, strings.Join(args, " "))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%s: %w: %s", name, err, message)
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return output, nil
+}
+
+// DefaultConfigPath returns the docker CLI config file: config.json in
+// $DOCKER_CONFIG, or in ~/.docker when it is not set
+func DefaultConfigPath() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the home directory: %w", err)
+	}
+	return filepath.Join(home, ".docker", "config.json"), nil
+}
+
+// Store saves registry credentials in the docker CLI config. Credentials go
+// to the credential helper configured for the registry (credHelpers) or for
+// every registry (credsStore); without one they are written to the config
+// file itself, as 'docker login' does.
+type Store struct {
+	fs         filesystem.FS
+	configPath string
+	run        Runner
+}
+
+// NewStore creates a store for the docker config at configPath on fsys.
+// Credential helpers are invoked through run.
+func NewStore(fsys filesystem.FS, configPath string, run Runner) *Store {
+	return &Store{fs: fsys, configPath: configPath, run: run}
+}
+
+// Login stores a username and secret for host. It returns the credential
+// helper that holds them, or "" when they were written to the config file.
+func (s *Store) Login(host, username, secret string) (string, error) {
+	config, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	key := credentialKey(host)
+	auths, err := config.auths()
+	if err != nil {
+		return "", err
+	}
+
+	helper, err := config.helperFor(key)
+	if err != nil {
+		return "", err
+	}
+	if helper != "" {
+		payload, err := json.Marshal(map[string]string{"ServerURL": key, "Username": username, "Secret": secret})
+		if err != nil {
+			return "", fmt.Errorf("failed to encode credentials: %w", err)
+		}
+		if _, err := s.run(payload, "docker-credential-"+helper, "store"); err != nil {
+			return "", fmt.Errorf("failed to store credentials with docker-credential-%s: %w", helper, err)
+		}
+		// The docker CLI lists registries kept by a helper with an empty entry
+		auths[key] = json.RawMessage("{}")
+	} else {
+		auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + secret))
+		entry, err := json.Marshal(map[string]string{"auth": auth})
+		if err != nil {
+			return "", fmt.Errorf("failed to encode credentials: %w", err)
+		}
+		auths[key] = entry
+	}
+
+	if err := config.set("auths", auths); err != nil {
+		return "", err
+	}
+	return helper, s.save(config)
+}
+
+// HasCredentials reports whether the docker config holds credentials for host
+func (s *Store) HasCredentials(host string) bool {
+	config, err := s.load()
+	if err != nil {
+		return false
+	}
+	key := credentialKey(host)
+	if helpers, err := config.credHelpers(); err == nil && helpers[key] != "" {
+		return true
+	}
+	auths, err := config.auths()
+	if err != nil {
+		return false
+	}
+	_, found := auths[key]
+	return found
+}
+
+// dockerConfig is the docker CLI config file. It is kept as raw JSON so
+// settings om does not know about are written back unchanged.
+type dockerConfig map[string]json.RawMessage
+
+// load reads the docker config, returning an empty one when it does not exist
+func (s *Store) load() (dockerConfig, error) {
+	data, err := s.fs.ReadFile(s.configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return dockerConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", s.configPath, err)
+	}
+	config := dockerConfig{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return config, nil
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.configPath, err)
+	}
+	return config, nil
+}
+
+// save writes the docker config, readable only by the user
+func (s *Store) save(config dockerConfig) error {
+	data, err := json.MarshalIndent(config, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", s.configPath, err)
+	}
+	if err := s.fs.MkdirAll(filepath.Dir(s.configPath), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(s.configPath), err)
+	}
+	if err := s.fs.WriteFile(s.configPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.configPath, err)
+	}
+	return nil
+}
+
+// set replaces a key of the config
+func (c dockerConfig) set(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	c[key] = data
+	return nil
+}
+
+// auths returns the per-registry entries of the config
+func (c dockerConfig) auths() (map[string]json.RawMessage, error) {
+	auths := make(map[string]json.RawMessage)
+	if raw, found := c["auths"]; found {
+		if err := json.Unmarshal(raw, &auths); err != nil {
+			return nil, fmt.Errorf("failed to parse auths: %w", err)
+		}
+	}
+	return auths, nil
+}
+
+// credHelpers returns the credential helpers configured per registry
+func (c dockerConfig) credHelpers() (map[string]string, error) {
+	helpers := make(map[string]string)
+	if raw, found := c["credHelpers"]; found {
+		if err := json.Unmarshal(raw, &helpers); err != nil {
+			return nil, fmt.Errorf("failed to parse credHelpers: %w", err)
+		}
+	}
+	return helpers, nil
+}
+
+// helperFor returns the credential helper for a registry key, or "" when
+// credentials are kept in the config file
+func (c dockerConfig) helperFor(key string) (string, error) {
+	helpers, err := c.credHelpers()
+	if err != nil {
+		return "", err
+	}
+	if helper := helpers[key]; helper != "" {
+		return helper, nil
+	}
+	var store string
+	if raw, found := c["credsStore"]; found {
+		if err := json.Unmarshal(raw, &store); err != nil {
+			return "", fmt.Errorf("failed to parse credsStore: %w", err)
+		}
+	}
+	return store, nil
+}
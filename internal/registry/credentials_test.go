@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// recordingRunner records the commands it is asked to run
+type recordingRunner struct {
+	commands []string
+	stdin    []string
+}
+
+func (r *recordingRunner) run(stdin []byte, name string, args ...string) ([]byte, error) {
+	r.commands = append(r.commands, strings.Join(append([]string{name}, args...), " "))
+	r.stdin = append(r.stdin, string(stdin))
+	return nil, nil
+}
+
+func TestStore_LoginWithCredentialHelper(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	if err := fsys.MkdirAll(".docker", 0700); err != nil {
+		t.Fatal(err)
+	}
+	config := `{"credsStore": "desktop", "credHelpers": {"ghcr.io": "pass"}, "currentContext": "colima"}`
+	if err := fsys.WriteFile(".docker/config.json", []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	runner := &recordingRunner{}
+	store := NewStore(fsys, ".docker/config.json", runner.run)
+
+	if store.HasCredentials("registry.internal") {
+		t.Fatal("expected no credentials before logging in")
+	}
+	helper, err := store.Login("registry.internal", "ci", "token")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if helper != "desktop" {
+		t.Errorf("expected the credsStore helper, got %q", helper)
+	}
+	if helper, _ := store.Login("ghcr.io", "ci", "token"); helper != "pass" {
+		t.Errorf("expected the per-registry helper for ghcr.io, got %q", helper)
+	}
+
+	if len(runner.commands) != 2 || runner.commands[0] != "docker-credential-desktop store" {
+		t.Fatalf("unexpected helper calls: %v", runner.commands)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal([]byte(runner.stdin[0]), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["ServerURL"] != "registry.internal" || payload["Username"] != "ci" || payload["Secret"] != "token" {
+		t.Errorf("unexpected helper payload: %v", payload)
+	}
+
+	if !store.HasCredentials("registry.internal") || !store.HasCredentials("ghcr.io") {
+		t.Error("expected credentials after logging in")
+	}
+	data, _ := fsys.ReadFile(".docker/config.json")
+	if strings.Contains(string(data), "token") || !strings.Contains(string(data), `"currentContext": "colima"`) {
+		t.Errorf("expected secrets kept out of the config and other settings preserved, got:\n%s", data)
+	}
+}
+
+func TestStore_LoginWithoutCredentialHelper(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	runner := &recordingRunner{}
+	store := NewStore(fsys, "home/.docker/config.json", runner.run)
+
+	helper, err := store.Login(DockerHub, "jane", "secret")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if helper != "" || len(runner.commands) != 0 {
+		t.Fatalf("expected credentials in the config file, got helper %q and calls %v", helper, runner.commands)
+	}
+
+	data, err := fsys.ReadFile("home/.docker/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"https://index.docker.io/v1/"`) || !strings.Contains(string(data), "amFuZTpzZWNyZXQ=") {
+		t.Errorf("expected a Docker Hub auth entry, got:\n%s", data)
+	}
+}
+
+func TestECRPassword(t *testing.T) {
+	var command string
+	run := func(stdin []byte, name string, args ...string) ([]byte, error) {
+		command = strings.Join(append([]string{name}, args...), " ")
+		return []byte("ecr-token\n"), nil
+	}
+
+	password, err := ECRPassword(run, "eu-west-1")
+	if err != nil {
+		t.Fatalf("ECRPassword failed: %v", err)
+	}
+	if password != "ecr-token" || command != "aws ecr get-login-password --region eu-west-1" {
+		t.Errorf("unexpected password %q from %q", password, command)
+	}
+}
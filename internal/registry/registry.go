@@ -0,0 +1,65 @@
+// Package registry resolves the container registries images are pulled from
+// and stores registry credentials the way the docker CLI does, so 'docker
+// compose' and 'docker pull' pick them up.
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DockerHub is the registry of images without a registry host
+const DockerHub = "docker.io"
+
+// dockerHubKey is the key the docker CLI stores Docker Hub credentials under
+const dockerHubKey = "https://index.docker.io/v1/"
+
+// ecrHost matches Amazon ECR registries, e.g.
+// 123456789012.dkr.ecr.eu-west-1.amazonaws.com
+var ecrHost = regexp.MustCompile(`^\d{12}\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
+
+// Host returns the registry an image reference is pulled from. References
+// without a registry, such as postgres:16 or library/nginx, come from
+// Docker Hub.
+func Host(image string) string {
+	first, _, found := strings.Cut(image, "/")
+	if !found {
+		return DockerHub
+	}
+	if strings.ContainsAny(first, ".:") || first == "localhost" {
+		return first
+	}
+	return DockerHub
+}
+
+// ECRRegion returns the AWS region of an Amazon ECR registry host
+func ECRRegion(host string) (string, bool) {
+	match := ecrHost.FindStringSubmatch(host)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// ECRPassword fetches a registry password for an Amazon ECR region with the
+// AWS CLI. The password is valid for 12 hours and is used with the user AWS.
+func ECRPassword(run Runner, region string) (string, error) {
+	output, err := run(nil, "aws", "ecr", "get-login-password", "--region", region)
+	if err != nil {
+		return "", fmt.Errorf("failed to get an ECR login password for %s: %w", region, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ECRUsername is the user of every Amazon ECR login
+const ECRUsername = "AWS"
+
+// credentialKey returns the key credentials for host are stored under
+func credentialKey(host string) string {
+	switch host {
+	case DockerHub, "index.docker.io", "registry-1.docker.io":
+		return dockerHubKey
+	}
+	return host
+}
@@ -0,0 +1,31 @@
+package registry
+
+import "testing"
+
+func TestHost(t *testing.T) {
+	tests := map[string]string{
+		"postgres:16":                                        DockerHub,
+		"library/nginx:alpine":                               DockerHub,
+		"ghcr.io/acme/api:1.2":                               "ghcr.io",
+		"registry.internal:5000/auth:1.4":                    "registry.internal:5000",
+		"localhost/dev-image":                                "localhost",
+		"123456789012.dkr.ecr.eu-west-1.amazonaws.com/api:7": "123456789012.dkr.ecr.eu-west-1.amazonaws.com",
+	}
+	for image, want := range tests {
+		if got := Host(image); got != want {
+			t.Errorf("Host(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestECRRegion(t *testing.T) {
+	if region, ok := ECRRegion("123456789012.dkr.ecr.eu-west-1.amazonaws.com"); !ok || region != "eu-west-1" {
+		t.Errorf("expected eu-west-1, got %q (%v)", region, ok)
+	}
+	if region, ok := ECRRegion("123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn"); !ok || region != "cn-north-1" {
+		t.Errorf("expected cn-north-1, got %q (%v)", region, ok)
+	}
+	if _, ok := ECRRegion("ghcr.io"); ok {
+		t.Error("ghcr.io is not an ECR registry")
+	}
+}
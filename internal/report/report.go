@@ -0,0 +1,189 @@
+// Package report keeps an audit trail of generation runs. Every run that
+// writes configuration leaves a JSON report under .om/reports/ recording
+// what it was generated from (the manifest hash, target, environment, and
+// the files the generator read), what it wrote, the warnings it found, and
+// how long each phase took.
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+)
+
+// Dir is the directory, relative to the project root, that holds reports
+var Dir = filepath.Join(".om", "reports")
+
+// ErrNoReports is returned by Latest when a project has no reports yet
+var ErrNoReports = errors.New("no generation reports found")
+
+// Report describes one generation run
+type Report struct {
+	Command    string    `json:"command"` // e.g. compose
+	OMVersion  string    `json:"omVersion"`
+	Project    string    `json:"project"`
+	StartedAt  time.Time `json:"startedAt"`
+	Inputs     Inputs    `json:"inputs"`
+	Outputs    []File    `json:"outputs"`
+	Warnings   []Warning `json:"warnings,omitempty"`
+	Durations  []Phase   `json:"durations"`
+	DurationMS int64     `json:"durationMs"`
+}
+
+// Inputs are what a run was generated from
+type Inputs struct {
+	Manifest       string `json:"manifest"` // path relative to the project root
+	ManifestSHA256 string `json:"manifestSha256"`
+	Target         string `json:"target"`
+	Group          string `json:"group,omitempty"`
+	Env            string `json:"env,omitempty"`
+	Files          []File `json:"files,omitempty"` // other files the generator read
+}
+
+// File is a file read or written by a run, relative to the project root
+type File struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// Warning is a warning reported by the run
+type Warning struct {
+	Entry   string `json:"entry,omitempty"`
+	Message string `json:"message"`
+}
+
+// Phase is the duration of one step of a run, such as loading the manifest
+type Phase struct {
+	Name       string `json:"name"`
+	DurationMS int64  `json:"durationMs"`
+}
+
+// Warnings converts warnings to their report form
+func Warnings(list []warnings.Warning) []Warning {
+	converted := make([]Warning, 0, len(list))
+	for _, warning := range list {
+		converted = append(converted, Warning{Entry: warning.Entry, Message: warning.Message})
+	}
+	return converted
+}
+
+// Describe returns the size and hash of a file relative to projectRoot, with
+// a zero size and no hash when it cannot be read
+func Describe(fsys filesystem.FS, projectRoot, path string) File {
+	file := File{Path: filepath.ToSlash(path)}
+	if data, err := fsys.ReadFile(filepath.Join(projectRoot, filepath.FromSlash(path))); err == nil {
+		sum := sha256.Sum256(data)
+		file.Size = int64(len(data))
+		file.SHA256 = hex.EncodeToString(sum[:])
+	}
+	return file
+}
+
+// Timer records the phases of a run
+type Timer struct {
+	now   func() time.Time
+	start time.Time
+	last  time.Time
+	// Phases are the finished phases, in order
+	Phases []Phase
+}
+
+// NewTimer starts timing a run
+func NewTimer(now func() time.Time) *Timer {
+	start := now()
+	return &Timer{now: now, start: start, last: start}
+}
+
+// Started returns when the run started
+func (t *Timer) Started() time.Time {
+	return t.start
+}
+
+// Phase ends the current phase under the given name and starts the next
+func (t *Timer) Phase(name string) {
+	now := t.now()
+	t.Phases = append(t.Phases, Phase{Name: name, DurationMS: now.Sub(t.last).Milliseconds()})
+	t.last = now
+}
+
+// Total returns the time since the run started, in milliseconds
+func (t *Timer) Total() int64 {
+	return t.now().Sub(t.start).Milliseconds()
+}
+
+// Write saves report as <command>-<timestamp>.json under the project's
+// reports directory and returns its path relative to the project root
+func Write(fsys filesystem.FS, projectRoot string, report *Report) (string, error) {
+	for _, files := range [][]File{report.Inputs.Files, report.Outputs} {
+		sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode the report: %w", err)
+	}
+
+	dir := filepath.Join(projectRoot, Dir)
+	if err := fsys.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", Dir, err)
+	}
+	name := filepath.Join(Dir, fmt.Sprintf("%s-%s.json", report.Command, report.StartedAt.UTC().Format("20060102-150405")))
+	if err := fsys.WriteFile(filepath.Join(projectRoot, name), append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return name, nil
+}
+
+// Latest returns the newest report of the project and its path relative to
+// the project root. Report names sort by time within a command, so the
+// newest is the one with the latest timestamp across commands.
+func Latest(fsys filesystem.FS, projectRoot string) (string, *Report, error) {
+	entries, err := fsys.ReadDir(filepath.Join(projectRoot, Dir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil, ErrNoReports
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read %s: %w", Dir, err)
+	}
+
+	newest, newestStamp := "", ""
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		stamp := strings.TrimSuffix(name, ".json")
+		if i := strings.LastIndex(stamp, "-"); i > 0 {
+			if j := strings.LastIndex(stamp[:i], "-"); j >= 0 {
+				stamp = stamp[j+1:]
+			}
+		}
+		if stamp > newestStamp || stamp == newestStamp && name > newest {
+			newest, newestStamp = name, stamp
+		}
+	}
+	if newest == "" {
+		return "", nil, ErrNoReports
+	}
+
+	path := filepath.Join(Dir, newest)
+	data, err := fsys.ReadFile(filepath.Join(projectRoot, path))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return "", nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return path, &report, nil
+}
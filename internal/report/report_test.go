@@ -0,0 +1,92 @@
+package report
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/warnings"
+)
+
+func TestWriteAndLatest(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	if err := fsys.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Latest(fsys, "demo"); !errors.Is(err, ErrNoReports) {
+		t.Fatalf("expected ErrNoReports without reports, got %v", err)
+	}
+
+	first := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	for i, started := range []time.Time{first, first.Add(time.Hour)} {
+		path, err := Write(fsys, "demo", &Report{
+			Command:   "compose",
+			StartedAt: started,
+			Inputs:    Inputs{Target: "docker"},
+			Outputs:   []File{{Path: "b.yml"}, {Path: "a.yml"}},
+			Warnings:  Warnings([]warnings.Warning{warnings.New("services.api", "no HEALTHCHECK")}),
+			Durations: []Phase{{Name: "generate", DurationMS: int64(i)}},
+		})
+		if err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		want := filepath.Join(".om", "reports", "compose-"+started.Format("20060102-150405")+".json")
+		if path != want {
+			t.Errorf("expected %s, got %s", want, path)
+		}
+	}
+
+	path, latest, err := Latest(fsys, "demo")
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if path != filepath.Join(".om", "reports", "compose-20260304-060607.json") {
+		t.Errorf("expected the newest report, got %s", path)
+	}
+	if !latest.StartedAt.Equal(first.Add(time.Hour)) || latest.Durations[0].DurationMS != 1 {
+		t.Errorf("unexpected report: %+v", latest)
+	}
+	if latest.Outputs[0].Path != "a.yml" {
+		t.Errorf("expected outputs sorted by path, got %v", latest.Outputs)
+	}
+	if len(latest.Warnings) != 1 || latest.Warnings[0].Entry != "services.api" {
+		t.Errorf("expected the warning, got %v", latest.Warnings)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	if err := fsys.MkdirAll("demo", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(filepath.Join("demo", "docker-compose.yml"), []byte("services: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	file := Describe(fsys, "demo", "docker-compose.yml")
+	if file.Size != 13 || len(file.SHA256) != 64 {
+		t.Errorf("unexpected description: %+v", file)
+	}
+	if missing := Describe(fsys, "demo", "missing.yml"); missing.Size != 0 || missing.SHA256 != "" {
+		t.Errorf("expected no size or hash for a missing file, got %+v", missing)
+	}
+}
+
+func TestTimer(t *testing.T) {
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	timer := NewTimer(func() time.Time { return now })
+	now = now.Add(20 * time.Millisecond)
+	timer.Phase("load")
+	now = now.Add(150 * time.Millisecond)
+	timer.Phase("generate")
+
+	want := []Phase{{Name: "load", DurationMS: 20}, {Name: "generate", DurationMS: 150}}
+	if len(timer.Phases) != 2 || timer.Phases[0] != want[0] || timer.Phases[1] != want[1] {
+		t.Errorf("expected %v, got %v", want, timer.Phases)
+	}
+	if timer.Total() != 170 {
+		t.Errorf("expected a total of 170 ms, got %d", timer.Total())
+	}
+}
@@ -0,0 +1,110 @@
+package resources
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// The Terraform modules of the built-in blueprints are embedded under the
+// path their blueprint's TerraformModule names. Generators write them out
+// next to the configuration that uses them, so projects need no access to
+// a module registry.
+//
+//go:embed modules
+var modulesFS embed.FS
+
+// moduleInputs are the variables every Terraform module of a blueprint
+// takes, besides one per blueprint parameter (see ModuleInput)
+var moduleInputs = []string{"name", "vpc_id", "subnet_ids", "allowed_security_group_ids", "engine_version", "port", "tags"}
+
+// moduleVariablePattern finds the variable declarations of a module
+var moduleVariablePattern = regexp.MustCompile(`(?m)^variable\s+"([^"]+)"`)
+
+// VendoredModule reports whether the blueprint's Terraform module ships
+// with om. Other modules are fetched from TerraformModule as a module
+// source, such as a registry address pinned with TerraformModuleVersion.
+func (b ResourceBlueprint) VendoredModule() bool {
+	if b.TerraformModule == "" {
+		return false
+	}
+	info, err := fs.Stat(modulesFS, path.Clean(b.TerraformModule))
+	return err == nil && info.IsDir()
+}
+
+// ModuleFiles returns the files of the blueprint's vendored Terraform
+// module by their name
+func (b ResourceBlueprint) ModuleFiles() (map[string][]byte, error) {
+	if !b.VendoredModule() {
+		return nil, fmt.Errorf("resource blueprint '%s' has no vendored Terraform module", b.Name)
+	}
+	dir := path.Clean(b.TerraformModule)
+	entries, err := fs.ReadDir(modulesFS, dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := fs.ReadFile(modulesFS, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files[entry.Name()] = data
+	}
+	return files, nil
+}
+
+// ModuleInput returns the Terraform variable a blueprint parameter is
+// passed to its module as: the parameter name in snake_case, so
+// databaseName becomes database_name. The version and port parameters are
+// passed as engine_version and port.
+func ModuleInput(parameter string) string {
+	if parameter == "version" {
+		return "engine_version"
+	}
+	var name strings.Builder
+	for i, r := range parameter {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				name.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		name.WriteRune(r)
+	}
+	return name.String()
+}
+
+// validateModule checks that a vendored module declares every variable om
+// passes it, so that a blueprint and its module cannot drift apart
+func (b ResourceBlueprint) validateModule() error {
+	if !b.VendoredModule() {
+		return nil
+	}
+	files, err := b.ModuleFiles()
+	if err != nil {
+		return err
+	}
+	declared := make(map[string]bool)
+	for _, data := range files {
+		for _, match := range moduleVariablePattern.FindAllSubmatch(data, -1) {
+			declared[string(match[1])] = true
+		}
+	}
+	required := append([]string(nil), moduleInputs...)
+	for _, param := range b.Parameters {
+		required = append(required, ModuleInput(param.Name))
+	}
+	for _, variable := range required {
+		if !declared[variable] {
+			return fmt.Errorf("the Terraform module of resource blueprint '%s' does not declare variable '%s'", b.Name, variable)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+# MongoDB-compatible Amazon DocumentDB cluster
+
+resource "aws_docdb_subnet_group" "this" {
+  name       = var.name
+  subnet_ids = var.subnet_ids
+  tags       = var.tags
+}
+
+resource "aws_security_group" "this" {
+  name_prefix = "${var.name}-"
+  vpc_id      = var.vpc_id
+
+  ingress {
+    from_port       = var.port
+    to_port         = var.port
+    protocol        = "tcp"
+    security_groups = var.allowed_security_group_ids
+  }
+
+  tags = var.tags
+}
+
+resource "aws_docdb_cluster" "this" {
+  cluster_identifier     = var.name
+  engine                 = "docdb"
+  engine_version         = var.docdb_engine_version
+  master_username        = var.username
+  master_password        = var.password
+  port                   = var.port
+  db_subnet_group_name   = aws_docdb_subnet_group.this.name
+  vpc_security_group_ids = [aws_security_group.this.id]
+  skip_final_snapshot    = true
+  tags                   = var.tags
+}
+
+resource "aws_docdb_cluster_instance" "this" {
+  identifier         = "${var.name}-1"
+  cluster_identifier = aws_docdb_cluster.this.id
+  instance_class     = var.instance_class
+  tags               = var.tags
+}
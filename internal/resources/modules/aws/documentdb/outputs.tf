@@ -0,0 +1,14 @@
+output "endpoint" {
+  description = "Host name clients connect to"
+  value       = aws_docdb_cluster.this.endpoint
+}
+
+output "port" {
+  description = "Port clients connect to"
+  value       = aws_docdb_cluster.this.port
+}
+
+output "database_name" {
+  description = "Database the services use"
+  value       = var.database_name
+}
@@ -0,0 +1,63 @@
+variable "name" {
+  description = "Name of the resources, unique within the account and region"
+  type        = string
+}
+
+variable "vpc_id" {
+  description = "VPC to create the resources in"
+  type        = string
+}
+
+variable "subnet_ids" {
+  description = "Subnets to place the resources in"
+  type        = list(string)
+}
+
+variable "allowed_security_group_ids" {
+  description = "Security groups allowed to connect"
+  type        = list(string)
+}
+
+variable "engine_version" {
+  description = "MongoDB version the resource runs locally; DocumentDB runs docdb_engine_version"
+  type        = string
+}
+
+variable "port" {
+  description = "Port clients connect to"
+  type        = number
+}
+
+variable "tags" {
+  description = "Tags of every resource"
+  type        = map(string)
+  default     = {}
+}
+
+variable "database_name" {
+  description = "Database the services use; DocumentDB creates it on first write"
+  type        = string
+}
+
+variable "username" {
+  description = "Master user name"
+  type        = string
+}
+
+variable "password" {
+  description = "Master user password"
+  type        = string
+  sensitive   = true
+}
+
+variable "docdb_engine_version" {
+  description = "DocumentDB engine version"
+  type        = string
+  default     = "5.0.0"
+}
+
+variable "instance_class" {
+  description = "Instance class"
+  type        = string
+  default     = "db.t3.medium"
+}
@@ -0,0 +1,33 @@
+# Memcached on Amazon ElastiCache
+
+resource "aws_elasticache_subnet_group" "this" {
+  name       = var.name
+  subnet_ids = var.subnet_ids
+  tags       = var.tags
+}
+
+resource "aws_security_group" "this" {
+  name_prefix = "${var.name}-"
+  vpc_id      = var.vpc_id
+
+  ingress {
+    from_port       = var.port
+    to_port         = var.port
+    protocol        = "tcp"
+    security_groups = var.allowed_security_group_ids
+  }
+
+  tags = var.tags
+}
+
+resource "aws_elasticache_cluster" "this" {
+  cluster_id         = var.name
+  engine             = "memcached"
+  engine_version     = var.engine_version
+  node_type          = var.node_type
+  num_cache_nodes    = 1
+  port               = var.port
+  subnet_group_name  = aws_elasticache_subnet_group.this.name
+  security_group_ids = [aws_security_group.this.id]
+  tags               = var.tags
+}
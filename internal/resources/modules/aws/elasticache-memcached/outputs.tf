@@ -0,0 +1,9 @@
+output "endpoint" {
+  description = "Configuration endpoint clients connect to"
+  value       = aws_elasticache_cluster.this.cluster_address
+}
+
+output "port" {
+  description = "Port clients connect to"
+  value       = aws_elasticache_cluster.this.port
+}
@@ -0,0 +1,9 @@
+output "endpoint" {
+  description = "Host name clients connect to"
+  value       = aws_elasticache_replication_group.this.primary_endpoint_address
+}
+
+output "port" {
+  description = "Port clients connect to"
+  value       = aws_elasticache_replication_group.this.port
+}
@@ -0,0 +1,52 @@
+variable "name" {
+  description = "Name of the resources, unique within the account and region"
+  type        = string
+}
+
+variable "vpc_id" {
+  description = "VPC to create the resources in"
+  type        = string
+}
+
+variable "subnet_ids" {
+  description = "Subnets to place the resources in"
+  type        = list(string)
+}
+
+variable "allowed_security_group_ids" {
+  description = "Security groups allowed to connect"
+  type        = list(string)
+}
+
+variable "engine_version" {
+  description = "Redis version"
+  type        = string
+}
+
+variable "port" {
+  description = "Port clients connect to"
+  type        = number
+}
+
+variable "tags" {
+  description = "Tags of every resource"
+  type        = map(string)
+  default     = {}
+}
+
+variable "password" {
+  description = "Auth token clients authenticate with"
+  type        = string
+  sensitive   = true
+
+  validation {
+    condition     = length(var.password) >= 16 && length(var.password) <= 128
+    error_message = "ElastiCache auth tokens are 16 to 128 characters long."
+  }
+}
+
+variable "node_type" {
+  description = "Node type"
+  type        = string
+  default     = "cache.t3.micro"
+}
@@ -0,0 +1,35 @@
+# RabbitMQ on Amazon MQ, as a single instance broker. Amazon MQ serves
+# AMQP over TLS on port 5671 whatever port the resource uses locally.
+
+resource "aws_security_group" "this" {
+  name_prefix = "${var.name}-"
+  vpc_id      = var.vpc_id
+
+  ingress {
+    from_port       = 5671
+    to_port         = 5671
+    protocol        = "tcp"
+    security_groups = var.allowed_security_group_ids
+  }
+
+  tags = var.tags
+}
+
+resource "aws_mq_broker" "this" {
+  broker_name                = var.name
+  engine_type                = "RabbitMQ"
+  engine_version             = var.engine_version
+  host_instance_type         = var.instance_type
+  deployment_mode            = "SINGLE_INSTANCE"
+  subnet_ids                 = [var.subnet_ids[0]]
+  security_groups            = [aws_security_group.this.id]
+  publicly_accessible        = false
+  auto_minor_version_upgrade = true
+
+  user {
+    username = var.username
+    password = var.password
+  }
+
+  tags = var.tags
+}
@@ -0,0 +1,9 @@
+output "endpoint" {
+  description = "AMQPS URL clients connect to"
+  value       = aws_mq_broker.this.instances[0].endpoints[0]
+}
+
+output "port" {
+  description = "Port clients connect to"
+  value       = 5671
+}
@@ -0,0 +1,52 @@
+variable "name" {
+  description = "Name of the resources, unique within the account and region"
+  type        = string
+}
+
+variable "vpc_id" {
+  description = "VPC to create the resources in"
+  type        = string
+}
+
+variable "subnet_ids" {
+  description = "Subnets to place the resources in"
+  type        = list(string)
+}
+
+variable "allowed_security_group_ids" {
+  description = "Security groups allowed to connect"
+  type        = list(string)
+}
+
+variable "engine_version" {
+  description = "RabbitMQ version"
+  type        = string
+}
+
+variable "port" {
+  description = "Port of the resource locally; Amazon MQ always uses 5671"
+  type        = number
+}
+
+variable "tags" {
+  description = "Tags of every resource"
+  type        = map(string)
+  default     = {}
+}
+
+variable "username" {
+  description = "Broker user name"
+  type        = string
+}
+
+variable "password" {
+  description = "Broker user password, at least 12 characters"
+  type        = string
+  sensitive   = true
+}
+
+variable "instance_type" {
+  description = "Broker instance type"
+  type        = string
+  default     = "mq.t3.micro"
+}
@@ -0,0 +1,9 @@
+output "endpoint" {
+  description = "Host name clients connect to"
+  value       = aws_db_instance.this.address
+}
+
+output "port" {
+  description = "Port clients connect to"
+  value       = aws_db_instance.this.port
+}
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 )
 
@@ -23,6 +24,28 @@ func NewRegistry() *Registry {
 	return registry
 }
 
+// Register adds a blueprint to the registry after checking that its Docker
+// Compose snippet renders and parses (see ResourceBlueprint.Validate)
+func (r *Registry) Register(blueprint ResourceBlueprint) error {
+	if err := blueprint.Validate(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.blueprints[blueprint.Name] = blueprint
+	return nil
+}
+
+// mustRegister registers a built-in blueprint. A built-in blueprint that does
+// not validate is a bug in om, so it panics rather than failing later, when
+// the blueprint is first used.
+func (r *Registry) mustRegister(blueprint ResourceBlueprint) {
+	if err := r.Register(blueprint); err != nil {
+		panic(err)
+	}
+}
+
 // Get retrieves a resource blueprint by name
 func (r *Registry) Get(name string) (ResourceBlueprint, error) {
 	r.mutex.RLock()
@@ -36,6 +59,28 @@ func (r *Registry) Get(name string) (ResourceBlueprint, error) {
 	return blueprint, nil
 }
 
+// BlueprintName returns the name of the blueprint a resource type in
+// workbench.yaml refers to, accepting the short names such as postgres and
+// redis
+func BlueprintName(resourceType string) string {
+	switch strings.ToLower(resourceType) {
+	case "postgres", "postgres-db":
+		return "postgres-db"
+	case "mysql", "mysql-db":
+		return "mysql-db"
+	case "mongodb", "mongo":
+		return "mongodb"
+	case "redis", "redis-cache":
+		return "redis-cache"
+	case "memcached":
+		return "memcached"
+	case "rabbitmq":
+		return "rabbitmq"
+	default:
+		return resourceType
+	}
+}
+
 // List returns all available resource blueprints
 func (r *Registry) List() []ResourceBlueprint {
 	r.mutex.RLock()
@@ -97,11 +142,8 @@ func (r *Registry) Categories() []string {
 
 // registerDefaultBlueprints registers the default resource blueprints
 func (r *Registry) registerDefaultBlueprints() {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-
 	// Database resources
-	r.blueprints["postgres-db"] = ResourceBlueprint{
+	r.mustRegister(ResourceBlueprint{
 		Name:        "postgres-db",
 		Description: "A PostgreSQL Database",
 		Category:    "database",
@@ -114,7 +156,7 @@ func (r *Registry) registerDefaultBlueprints() {
     volumes:
       - postgres_data:/var/lib/postgresql/data
     ports:
-      - "{{.Port}}:5432"
+      - "{{.Port | default "5432"}}:5432"
     healthcheck:
       test: ["CMD-SHELL", "pg_isready -U {{.Username}} -d {{.DatabaseName}}"]
       interval: 10s
@@ -128,9 +170,15 @@ func (r *Registry) registerDefaultBlueprints() {
 			{Name: "password", Description: "Database password", Type: "string", Required: true},
 			{Name: "port", Description: "Database port", Type: "number", Required: false, Default: 5432},
 		},
-	}
+		EnvDocs: map[string]string{
+			"user":     "User the service connects to the database as",
+			"password": "Password of the user",
+			"name":     "Name of the database resource",
+			"dbname":   "Database the service's tables are created in",
+		},
+	})
 
-	r.blueprints["mysql-db"] = ResourceBlueprint{
+	r.mustRegister(ResourceBlueprint{
 		Name:        "mysql-db",
 		Description: "A MySQL Database",
 		Category:    "database",
@@ -144,7 +192,7 @@ func (r *Registry) registerDefaultBlueprints() {
     volumes:
       - mysql_data:/var/lib/mysql
     ports:
-      - "{{.Port}}:3306"
+      - "{{.Port | default "3306"}}:3306"
     healthcheck:
       test: ["CMD", "mysqladmin", "ping", "-h", "localhost"]
       interval: 10s
@@ -159,9 +207,15 @@ func (r *Registry) registerDefaultBlueprints() {
 			{Name: "rootPassword", Description: "Root password", Type: "string", Required: true},
 			{Name: "port", Description: "Database port", Type: "number", Required: false, Default: 3306},
 		},
-	}
+		EnvDocs: map[string]string{
+			"user":     "User the service connects to the database as",
+			"password": "Password of the user",
+			"name":     "Name of the database resource",
+			"dbname":   "Database the service's tables are created in",
+		},
+	})
 
-	r.blueprints["mongodb"] = ResourceBlueprint{
+	r.mustRegister(ResourceBlueprint{
 		Name:        "mongodb",
 		Description: "A MongoDB Database",
 		Category:    "database",
@@ -174,7 +228,7 @@ func (r *Registry) registerDefaultBlueprints() {
     volumes:
       - mongodb_data:/data/db
     ports:
-      - "{{.Port}}:27017"
+      - "{{.Port | default "27017"}}:27017"
     healthcheck:
       test: ["CMD", "mongosh", "--eval", "db.adminCommand('ping')"]
       interval: 10s
@@ -188,10 +242,10 @@ func (r *Registry) registerDefaultBlueprints() {
 			{Name: "password", Description: "Database password", Type: "string", Required: true},
 			{Name: "port", Description: "Database port", Type: "number", Required: false, Default: 27017},
 		},
-	}
+	})
 
 	// Cache resources
-	r.blueprints["redis-cache"] = ResourceBlueprint{
+	r.mustRegister(ResourceBlueprint{
 		Name:        "redis-cache",
 		Description: "A Redis Cache",
 		Category:    "cache",
@@ -201,7 +255,7 @@ func (r *Registry) registerDefaultBlueprints() {
     volumes:
       - redis_data:/data
     ports:
-      - "{{.Port}}:6379"
+      - "{{.Port | default "6379"}}:6379"
     healthcheck:
       test: ["CMD", "redis-cli", "--raw", "incr", "ping"]
       interval: 10s
@@ -213,16 +267,19 @@ func (r *Registry) registerDefaultBlueprints() {
 			{Name: "password", Description: "Redis password", Type: "string", Required: true},
 			{Name: "port", Description: "Redis port", Type: "number", Required: false, Default: 6379},
 		},
-	}
+		EnvDocs: map[string]string{
+			"password": "Password clients authenticate to Redis with",
+		},
+	})
 
-	r.blueprints["memcached"] = ResourceBlueprint{
+	r.mustRegister(ResourceBlueprint{
 		Name:        "memcached",
 		Description: "A Memcached Cache",
 		Category:    "cache",
 		DockerComposeSnippet: `
     image: memcached:{{.Version}}
     ports:
-      - "{{.Port}}:11211"
+      - "{{.Port | default "11211"}}:11211"
     healthcheck:
       test: ["CMD", "memcached-tool", "localhost:11211", "stats"]
       interval: 10s
@@ -233,10 +290,10 @@ func (r *Registry) registerDefaultBlueprints() {
 			{Name: "version", Description: "Memcached version", Type: "select", Required: true, Default: "1.6", Options: []string{"1.6"}},
 			{Name: "port", Description: "Memcached port", Type: "number", Required: false, Default: 11211},
 		},
-	}
+	})
 
 	// Message queue resources
-	r.blueprints["rabbitmq"] = ResourceBlueprint{
+	r.mustRegister(ResourceBlueprint{
 		Name:        "rabbitmq",
 		Description: "A RabbitMQ Message Queue",
 		Category:    "message-queue",
@@ -248,7 +305,7 @@ func (r *Registry) registerDefaultBlueprints() {
     volumes:
       - rabbitmq_data:/var/lib/rabbitmq
     ports:
-      - "{{.Port}}:5672"
+      - "{{.Port | default "5672"}}:5672"
       - "15672:15672"
     healthcheck:
       test: ["CMD", "rabbitmq-diagnostics", "ping"]
@@ -262,5 +319,5 @@ func (r *Registry) registerDefaultBlueprints() {
 			{Name: "password", Description: "RabbitMQ password", Type: "string", Required: true},
 			{Name: "port", Description: "RabbitMQ port", Type: "number", Required: false, Default: 5672},
 		},
-	}
+	})
 }
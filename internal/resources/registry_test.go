@@ -0,0 +1,150 @@
+package resources
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewRegistry_DefaultBlueprintsValidate(t *testing.T) {
+	registry := NewRegistry()
+	for _, blueprint := range registry.List() {
+		if err := blueprint.Validate(); err != nil {
+			t.Errorf("built-in blueprint does not validate: %v", err)
+		}
+	}
+	if len(registry.Names()) == 0 {
+		t.Fatal("expected built-in blueprints")
+	}
+}
+
+func TestNewRegistry_DefaultBlueprintsVendorModules(t *testing.T) {
+	for _, blueprint := range NewRegistry().List() {
+		if !blueprint.VendoredModule() {
+			t.Errorf("built-in blueprint '%s' references %s, which om does not ship", blueprint.Name, blueprint.TerraformModule)
+			continue
+		}
+		files, err := blueprint.ModuleFiles()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"main.tf", "variables.tf", "outputs.tf"} {
+			if _, found := files[name]; !found {
+				t.Errorf("the module of '%s' has no %s", blueprint.Name, name)
+			}
+		}
+	}
+
+	// A blueprint and its module must agree on the inputs
+	broken := ResourceBlueprint{Name: "search", TerraformModule: "modules/aws/elasticache-memcached", Parameters: []ResourceParameter{{Name: "indexName"}}}
+	if err := broken.Validate(); err == nil || !strings.Contains(err.Error(), "index_name") {
+		t.Errorf("expected a module without a variable for a parameter to be rejected, got %v", err)
+	}
+	registry := ResourceBlueprint{Name: "search", TerraformModule: "registry.example.com/search/aws"}
+	if registry.VendoredModule() || registry.Validate() != nil {
+		t.Error("expected a module source outside om to be accepted as is")
+	}
+}
+
+func TestModuleInput(t *testing.T) {
+	for parameter, want := range map[string]string{"version": "engine_version", "port": "port", "databaseName": "database_name", "rootPassword": "root_password"} {
+		if got := ModuleInput(parameter); got != want {
+			t.Errorf("ModuleInput(%q) = %q, want %q", parameter, got, want)
+		}
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	parameters := []ResourceParameter{
+		{Name: "version", Default: "1.0"},
+		{Name: "password", Required: true},
+	}
+	tests := []struct {
+		name    string
+		snippet string
+		wantErr string
+	}{
+		{name: "valid", snippet: "\n    image: search:{{.Version}}\n    environment:\n      - PASSWORD={{.Password}}"},
+		{name: "no snippet", snippet: ""},
+		{name: "broken template", snippet: "\n    image: search:{{.Version", wantErr: "invalid snippet template"},
+		{name: "undeclared parameter", snippet: "\n    image: search:{{.Tag}}", wantErr: "failed to render snippet"},
+		{name: "invalid yaml", snippet: "\n    image: search\n    ports: [", wantErr: "not a valid service definition"},
+		{name: "misspelled key", snippet: "\n    image: search\n    volume:\n      - data:/data", wantErr: "not a valid service definition"},
+		{name: "wrong shape", snippet: "\n    image: search\n    ports:\n      http: 9200", wantErr: "not a valid service definition"},
+		{name: "no image", snippet: "\n    ports:\n      - \"9200:9200\"", wantErr: "sets no image"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry()
+			err := registry.Register(ResourceBlueprint{Name: "search", DockerComposeSnippet: tt.snippet, Parameters: parameters})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Register() failed: %v", err)
+				}
+				if _, err := registry.Get("search"); err != nil {
+					t.Errorf("expected the blueprint to be registered: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "'search'") {
+				t.Fatalf("expected an error naming the blueprint and containing %q, got %v", tt.wantErr, err)
+			}
+			if _, err := registry.Get("search"); err == nil {
+				t.Error("expected an invalid blueprint not to be registered")
+			}
+		})
+	}
+}
+
+func TestTemplateData(t *testing.T) {
+	data := TemplateData("16", map[string]string{
+		"databaseName":  "app",
+		"root_password": "secret",
+		"max-conns":     "100",
+		"Username":      "admin",
+		"api.key":       "k",
+	})
+	want := map[string]string{
+		"Version": "16", "version": "16",
+		"databaseName": "app", "DatabaseName": "app",
+		"root_password": "secret", "rootPassword": "secret", "RootPassword": "secret",
+		"max-conns": "100", "maxConns": "100", "MaxConns": "100",
+		"Username": "admin", "username": "admin",
+		"api.key": "k", "apiKey": "k", "ApiKey": "k",
+	}
+	for key, value := range want {
+		if data[key] != value {
+			t.Errorf("expected %s=%s, got %v", key, value, data[key])
+		}
+	}
+	if len(data) != len(want) {
+		t.Errorf("expected %d names, got %d: %v", len(want), len(data), data)
+	}
+
+	// A key written in a form wins over one normalized into it; otherwise the
+	// first key in sorted order does
+	data = TemplateData("", map[string]string{"db_name": "derived", "dbName": "explicit"})
+	if data["dbName"] != "explicit" || data["db_name"] != "derived" || data["DbName"] != "explicit" {
+		t.Errorf("expected the explicit key to win, got %v", data)
+	}
+}
+
+func TestRenderSnippet_Default(t *testing.T) {
+	blueprint := ResourceBlueprint{Name: "cache", DockerComposeSnippet: "\n    image: cache:{{.Version | default \"latest\"}}\n    ports:\n      - \"{{.Port | default \"6379\"}}:6379\""}
+
+	snippet, err := blueprint.RenderSnippet(TemplateData("", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(snippet, "image: cache:latest") || !strings.Contains(snippet, `"6379:6379"`) {
+		t.Errorf("expected the defaults, got %q", snippet)
+	}
+
+	snippet, err = blueprint.RenderSnippet(TemplateData("7.2", map[string]string{"port": "6380"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(snippet, "image: cache:7.2") || !strings.Contains(snippet, `"6380:6379"`) {
+		t.Errorf("expected the given values, got %q", snippet)
+	}
+}
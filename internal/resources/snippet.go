@@ -0,0 +1,156 @@
+package resources
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// placeholderValue stands in for required parameters without a default when
+// a snippet is rendered to validate it
+const placeholderValue = "placeholder"
+
+// snippetSchema lists the keys a Docker Compose snippet may set. Decoding
+// into it with known fields only catches misspelled keys and values of the
+// wrong shape, such as ports written as a map.
+type snippetSchema struct {
+	Image       string                 `yaml:"image"`
+	Command     interface{}            `yaml:"command"`
+	Environment []string               `yaml:"environment"`
+	Volumes     []string               `yaml:"volumes"`
+	Ports       []string               `yaml:"ports"`
+	Healthcheck map[string]interface{} `yaml:"healthcheck"`
+}
+
+// TemplateData returns what a blueprint's snippet is rendered with. The
+// resource's version is available as both .version and .Version. Every
+// config key is available under its own name and in camelCase and
+// PascalCase, with _, -, and . treated as word breaks: root_password,
+// rootPassword, and RootPassword all give .rootPassword and .RootPassword.
+// When two keys give the same name, the key written that way wins, and
+// otherwise the first key in sorted order.
+func TemplateData(version string, config map[string]string) map[string]interface{} {
+	data := map[string]interface{}{}
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		camel, pascal := keyForms(key)
+		for _, name := range []string{camel, pascal} {
+			if _, exists := data[name]; !exists && name != "" {
+				data[name] = config[key]
+			}
+		}
+	}
+	for key, value := range config {
+		data[key] = value
+	}
+	if version != "" {
+		data["Version"] = version
+		data["version"] = version
+	}
+	return data
+}
+
+// keyForms returns the camelCase and PascalCase forms of a config key. The
+// casing inside each word is kept, so databaseName stays one word.
+func keyForms(key string) (camel, pascal string) {
+	words := strings.FieldsFunc(key, func(r rune) bool { return r == '_' || r == '-' || r == '.' })
+	for i, word := range words {
+		runes := []rune(word)
+		upper := strings.ToUpper(string(runes[0])) + string(runes[1:])
+		pascal += upper
+		if i == 0 {
+			camel += strings.ToLower(string(runes[0])) + string(runes[1:])
+		} else {
+			camel += upper
+		}
+	}
+	return camel, pascal
+}
+
+// snippetFuncs are the functions snippets can use besides the template
+// builtins. default returns its first argument when the second is missing
+// or empty: {{.Port | default "5432"}}.
+var snippetFuncs = template.FuncMap{
+	"default": func(fallback string, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// RenderSnippet renders the blueprint's Docker Compose snippet with data
+// and checks that the result is a valid service definition
+func (b ResourceBlueprint) RenderSnippet(data map[string]interface{}) (string, error) {
+	return b.renderSnippet(data, "missingkey=default")
+}
+
+// Validate checks that the blueprint's Docker Compose snippet renders with
+// the defaults of its parameters and parses as a service definition. Every
+// field the snippet uses must be one of its parameters, and a vendored
+// Terraform module must declare a variable for each of them.
+func (b ResourceBlueprint) Validate() error {
+	if b.Name == "" {
+		return fmt.Errorf("resource blueprint has no name")
+	}
+	if err := b.validateModule(); err != nil {
+		return err
+	}
+	if strings.TrimSpace(b.DockerComposeSnippet) == "" {
+		return nil
+	}
+
+	config := make(map[string]string, len(b.Parameters))
+	version := ""
+	for _, parameter := range b.Parameters {
+		value := placeholderValue
+		if parameter.Default != nil {
+			value = fmt.Sprint(parameter.Default)
+		}
+		if parameter.Name == "version" {
+			version = value
+			continue
+		}
+		config[parameter.Name] = value
+	}
+	if _, err := b.renderSnippet(TemplateData(version, config), "missingkey=error"); err != nil {
+		return fmt.Errorf("resource blueprint '%s': %w", b.Name, err)
+	}
+	return nil
+}
+
+// renderSnippet renders the snippet with the given template option for
+// missing keys and decodes the result against the snippet schema
+func (b ResourceBlueprint) renderSnippet(data map[string]interface{}, missingKey string) (string, error) {
+	tmpl, err := template.New(b.Name).Option(missingKey).Funcs(snippetFuncs).Parse(b.DockerComposeSnippet)
+	if err != nil {
+		return "", fmt.Errorf("invalid snippet template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render snippet: %w", err)
+	}
+
+	// Snippets are indented as they would be under a service's name
+	snippet := strings.TrimLeft(buf.String(), "\n")
+	decoder := yaml.NewDecoder(strings.NewReader("service:\n" + snippet))
+	decoder.KnownFields(true)
+	var wrapped struct {
+		Service snippetSchema `yaml:"service"`
+	}
+	if err := decoder.Decode(&wrapped); err != nil {
+		return "", fmt.Errorf("snippet is not a valid service definition: %w", err)
+	}
+	if wrapped.Service.Image == "" {
+		return "", fmt.Errorf("snippet sets no image")
+	}
+	return snippet, nil
+}
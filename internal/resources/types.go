@@ -7,15 +7,26 @@ type ResourceBlueprint struct {
 	Description string `json:"description"`
 	Category    string `json:"category"` // database, cache, storage, etc.
 
-	// Docker Compose configuration
+	// Docker Compose configuration: the keys of the resource's service,
+	// indented as under its name. It is a text/template rendered with
+	// TemplateData and may use default for optional parameters.
 	DockerComposeSnippet string `json:"dockerComposeSnippet"`
 
-	// Terraform configuration
-	TerraformModule string `json:"terraformModule"`
+	// Terraform configuration: the module that provisions the resource,
+	// either one vendored with om (see VendoredModule) or a module source
+	// such as a registry address, pinned to TerraformModuleVersion. The
+	// module takes the variables described by ModuleInput.
+	TerraformModule        string `json:"terraformModule"`
+	TerraformModuleVersion string `json:"terraformModuleVersion,omitempty"`
 
 	// Resource-specific parameters
 	Parameters []ResourceParameter `json:"parameters,omitempty"`
 
+	// Descriptions of the variables om writes to .env for the resource, by
+	// property (see manifest.ResourceEnvProperties). They document the
+	// generated .env.example.
+	EnvDocs map[string]string `json:"envDocs,omitempty"`
+
 	// Dependencies
 	DependsOn []string `json:"dependsOn,omitempty"`
 }
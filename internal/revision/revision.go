@@ -0,0 +1,89 @@
+// Package revision identifies the revision of workbench.yaml that generated
+// files were generated from. Generators write it into the header of their
+// files, so that commands about to use them can tell they were generated
+// from a manifest that changed since, as happens after a git pull that
+// brings in a teammate's edits but not the regenerated files.
+package revision
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// marker starts the header line that records the revision
+const marker = "# om-manifest-revision: "
+
+// headerLines is how many lines at the start of a file Read looks through
+const headerLines = 10
+
+// Compute returns the revision of a manifest made of files, workbench.yaml
+// followed by the files it includes: the first 12 hex digits of the SHA-256
+// of their names, relative to projectRoot, and contents
+func Compute(fsys filesystem.FS, projectRoot string, files []string) (string, error) {
+	hash := sha256.New()
+	for _, file := range files {
+		data, err := fsys.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		name, err := filepath.Rel(projectRoot, file)
+		if err != nil {
+			name = file
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", filepath.ToSlash(name), len(data))
+		hash.Write(data)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:12], nil
+}
+
+// Comment returns the header line, with its newline, that records a
+// revision in a file using # comments. An empty revision gives no line.
+func Comment(revision string) string {
+	if revision == "" {
+		return ""
+	}
+	return marker + revision + "\n"
+}
+
+// Read returns the revision recorded in the header of a generated file, or
+// an empty string when it records none
+func Read(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 0; line < headerLines && scanner.Scan(); line++ {
+		if revision, found := strings.CutPrefix(scanner.Text(), marker); found {
+			return strings.TrimSpace(revision)
+		}
+	}
+	return ""
+}
+
+// Stale is a generated file recorded as generated from another revision
+type Stale struct {
+	File     string // relative to the project root
+	Revision string
+}
+
+// Check returns the generated files, relative to projectRoot, that record a
+// revision other than current. Missing files and files that record no
+// revision, such as those written by hand or by older releases, are not
+// reported.
+func Check(fsys filesystem.FS, projectRoot string, files []string, current string) []Stale {
+	var stale []Stale
+	for _, file := range files {
+		data, err := fsys.ReadFile(filepath.Join(projectRoot, filepath.FromSlash(file)))
+		if err != nil {
+			continue
+		}
+		if recorded := Read(data); recorded != "" && recorded != current {
+			stale = append(stale, Stale{File: file, Revision: recorded})
+		}
+	}
+	return stale
+}
@@ -0,0 +1,64 @@
+package revision
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestCompute(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	workbench, fragment := filepath.Join("/app", "workbench.yaml"), filepath.Join("/app", "services", "api.yaml")
+	if err := fsys.MkdirAll(filepath.Dir(fragment), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := fsys.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(workbench, "metadata:\n  name: demo\n")
+	write(fragment, "services: {}\n")
+
+	first, err := Compute(fsys, "/app", []string{workbench, fragment})
+	if err != nil || len(first) != 12 {
+		t.Fatalf("Compute() = %q, %v", first, err)
+	}
+	if again, _ := Compute(fsys, "/app", []string{workbench, fragment}); again != first {
+		t.Errorf("Compute() of the same files = %s, want %s", again, first)
+	}
+	write(fragment, "services: {api: {}}\n")
+	if changed, _ := Compute(fsys, "/app", []string{workbench, fragment}); changed == first {
+		t.Error("expected a change to an included file to change the revision")
+	}
+	if _, err := Compute(fsys, "/app", []string{filepath.Join("/app", "missing.yaml")}); err == nil {
+		t.Error("expected a missing manifest file to fail")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	if err := fsys.MkdirAll("/app/terraform", 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"/app/docker-compose.yml":  "# THIS FILE IS AUTO-GENERATED BY 'om compose'.\n" + Comment("aaaaaaaaaaaa") + "\nservices: {}\n",
+		"/app/terraform/main.tf":   "# Terraform configuration for demo\n" + Comment("bbbbbbbbbbbb"),
+		"/app/docker-compose.prod": "services: {}\n",
+	}
+	for path, content := range files {
+		if err := fsys.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if Comment("") != "" {
+		t.Errorf("Comment(\"\") = %q, want no line", Comment(""))
+	}
+
+	stale := Check(fsys, "/app", []string{"docker-compose.yml", "terraform/main.tf", "docker-compose.prod", "k8s/api.yaml"}, "bbbbbbbbbbbb")
+	if len(stale) != 1 || stale[0] != (Stale{File: "docker-compose.yml", Revision: "aaaaaaaaaaaa"}) {
+		t.Errorf("Check() = %+v, want only docker-compose.yml", stale)
+	}
+}
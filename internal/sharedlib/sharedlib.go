@@ -0,0 +1,239 @@
+// Package sharedlib scaffolds shared library packages, such as common
+// models, that several services of a project build in instead of copying
+// them, and links the library into each consuming service's dependencies.
+package sharedlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// Supported library languages
+const (
+	LanguageTypeScript = "typescript"
+	LanguagePython     = "python"
+)
+
+// Languages lists every library language
+var Languages = []string{LanguageTypeScript, LanguagePython}
+
+// Dir is the directory libraries are created in, relative to the project root
+const Dir = "shared"
+
+// Template returns the value recorded as a library's template in
+// workbench.yaml, e.g. typescript-library
+func Template(language string) string {
+	return language + "-library"
+}
+
+// PackageName returns the name consumers import the library by:
+// @<project>/<name> for TypeScript and <project>-<name> for Python
+func PackageName(project, name, language string) string {
+	if language == LanguagePython {
+		return strings.ToLower(project + "-" + name)
+	}
+	return "@" + strings.ToLower(project) + "/" + name
+}
+
+// moduleName returns the Python module of a library, e.g. demo_models
+func moduleName(project, name string) string {
+	return strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToLower(project + "_" + name))
+}
+
+// Files returns the files of a new library, keyed by slash-separated path
+// relative to the library's directory
+func Files(project, name, language string) (map[string][]byte, error) {
+	pkg := PackageName(project, name, language)
+	switch language {
+	case LanguageTypeScript:
+		return map[string][]byte{
+			"package.json": []byte(fmt.Sprintf(`{
+  "name": %q,
+  "version": "0.1.0",
+  "private": true,
+  "main": "dist/index.js",
+  "types": "dist/index.d.ts",
+  "scripts": {
+    "build": "tsc",
+    "prepare": "tsc"
+  },
+  "devDependencies": {
+    "typescript": "^5.4.0"
+  }
+}
+`, pkg)),
+			"tsconfig.json": []byte(`{
+  "compilerOptions": {
+    "target": "ES2020",
+    "module": "commonjs",
+    "declaration": true,
+    "outDir": "dist",
+    "rootDir": "src",
+    "strict": true
+  },
+  "include": ["src"]
+}
+`),
+			"src/index.ts": []byte(fmt.Sprintf(`// Types shared by the services of %s. Import them with:
+//
+//   import { Example } from "%s";
+
+export interface Example {
+  id: string;
+}
+`, project, pkg)),
+			"README.md": []byte(readme(name, pkg, "npm run build")),
+		}, nil
+	case LanguagePython:
+		module := moduleName(project, name)
+		return map[string][]byte{
+			"pyproject.toml": []byte(fmt.Sprintf(`[build-system]
+requires = ["setuptools>=68"]
+build-backend = "setuptools.build_meta"
+
+[project]
+name = %q
+version = "0.1.0"
+requires-python = ">=3.9"
+
+[tool.setuptools.packages.find]
+where = ["src"]
+`, pkg)),
+			"src/" + module + "/__init__.py": []byte(fmt.Sprintf(`"""Models shared by the services of %s. Import them with:
+
+    from %s import Example
+"""
+
+from dataclasses import dataclass
+
+
+@dataclass
+class Example:
+    id: str
+`, project, module)),
+			"README.md": []byte(readme(name, pkg, "pip install -e .")),
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported library language '%s' (use %s)", language, strings.Join(Languages, " or "))
+}
+
+// readme explains how services use the library, locally and in Docker builds
+func readme(name, pkg, build string) string {
+	return fmt.Sprintf(`# %s
+
+Shared library %s, managed by om. Services list it under libraries in
+workbench.yaml and depend on it through a local path.
+
+Build it with:
+
+    %s
+
+'om compose' passes the library to the builds of its services as the extra
+build context %q. Copy it in from the service's Dockerfile, next to where the
+service expects it:
+
+    COPY --from=%s . /%s/%s
+`, name, pkg, build, name, name, Dir, name)
+}
+
+// Link adds the library at libraryPath to the dependencies of the service
+// at serviceDir, both relative to the project root. It returns the
+// dependency file it changed, or "" when the service already depends on the
+// library or has no package.json or requirements.txt to add it to.
+func Link(fsys filesystem.FS, projectRoot, serviceDir, libraryPath, pkg, language string) (string, error) {
+	relative, err := filepath.Rel(filepath.FromSlash(serviceDir), filepath.FromSlash(libraryPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to locate %s from %s: %w", libraryPath, serviceDir, err)
+	}
+	relative = filepath.ToSlash(relative)
+
+	file, update := "requirements.txt", linkRequirements
+	if language == LanguageTypeScript {
+		file, update = "package.json", linkPackageJSON
+	}
+	target := filepath.Join(projectRoot, filepath.FromSlash(serviceDir), file)
+	if !filesystem.Exists(fsys, target) {
+		return "", nil
+	}
+	data, err := fsys.ReadFile(target)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	updated, err := update(data, pkg, relative)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path.Join(serviceDir, file), err)
+	}
+	if bytes.Equal(updated, data) {
+		return "", nil
+	}
+	if err := fsys.WriteFile(target, updated, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return path.Join(serviceDir, file), nil
+}
+
+// dependenciesPattern matches the opening of a package.json dependencies object
+var dependenciesPattern = regexp.MustCompile(`"dependencies"\s*:\s*\{`)
+
+// linkPackageJSON adds a file: dependency on the library to a package.json,
+// editing the text so the rest of the file keeps its order and formatting
+func linkPackageJSON(data []byte, pkg, relative string) ([]byte, error) {
+	var manifest struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse: %w", err)
+	}
+	if _, exists := manifest.Dependencies[pkg]; exists {
+		return data, nil
+	}
+
+	entry := fmt.Sprintf("%q: %q", pkg, "file:"+relative)
+	var updated []byte
+	if location := dependenciesPattern.FindIndex(data); location != nil {
+		rest, separator := data[location[1]:], ","
+		if trimmed := bytes.TrimLeft(rest, " \t\r\n"); bytes.HasPrefix(trimmed, []byte("}")) {
+			rest, separator = trimmed, "\n  "
+		}
+		updated = append(append(append([]byte(nil), data[:location[1]]...), "\n    "+entry+separator...), rest...)
+	} else {
+		end := bytes.LastIndexByte(data, '}')
+		if end < 0 {
+			return nil, fmt.Errorf("not a JSON object")
+		}
+		head := bytes.TrimRight(data[:end], " \t\r\n")
+		separator := ","
+		if bytes.HasSuffix(head, []byte("{")) {
+			separator = ""
+		}
+		updated = append(append([]byte(nil), head...), separator+"\n  \"dependencies\": {\n    "+entry+"\n  }\n"...)
+		updated = append(updated, data[end:]...)
+	}
+
+	if !json.Valid(updated) {
+		return nil, fmt.Errorf("could not add %s to the dependencies", pkg)
+	}
+	return updated, nil
+}
+
+// linkRequirements adds an editable install of the library to a
+// requirements.txt
+func linkRequirements(data []byte, pkg, relative string) ([]byte, error) {
+	line := "-e " + relative
+	for _, existing := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(existing) == line {
+			return data, nil
+		}
+	}
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	return append(data, line+"\n"...), nil
+}
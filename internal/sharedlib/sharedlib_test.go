@@ -0,0 +1,124 @@
+package sharedlib
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestFiles(t *testing.T) {
+	files, err := Files("demo", "models", LanguageTypeScript)
+	if err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(files["package.json"], &pkg); err != nil {
+		t.Fatalf("package.json is not valid JSON: %v", err)
+	}
+	if pkg.Name != "@demo/models" {
+		t.Errorf("expected package @demo/models, got %s", pkg.Name)
+	}
+	if _, exists := files["src/index.ts"]; !exists {
+		t.Error("expected src/index.ts")
+	}
+
+	files, err = Files("demo", "models", LanguagePython)
+	if err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+	if !strings.Contains(string(files["pyproject.toml"]), `name = "demo-models"`) {
+		t.Errorf("unexpected pyproject.toml:\n%s", files["pyproject.toml"])
+	}
+	if _, exists := files["src/demo_models/__init__.py"]; !exists {
+		t.Error("expected src/demo_models/__init__.py")
+	}
+
+	if _, err := Files("demo", "models", "go"); err == nil {
+		t.Error("expected an unsupported language to be rejected")
+	}
+}
+
+func TestLinkPackageJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "existing dependencies",
+			input: "{\n  \"name\": \"web\",\n  \"dependencies\": {\n    \"react\": \"^18.0.0\"\n  }\n}\n",
+			want:  "{\n  \"name\": \"web\",\n  \"dependencies\": {\n    \"@demo/models\": \"file:../shared/models\",\n    \"react\": \"^18.0.0\"\n  }\n}\n",
+		},
+		{
+			name:  "empty dependencies",
+			input: "{\n  \"dependencies\": {}\n}\n",
+			want:  "{\n  \"dependencies\": {\n    \"@demo/models\": \"file:../shared/models\"\n  }\n}\n",
+		},
+		{
+			name:  "no dependencies",
+			input: "{\n  \"name\": \"web\"\n}\n",
+			want:  "{\n  \"name\": \"web\",\n  \"dependencies\": {\n    \"@demo/models\": \"file:../shared/models\"\n  }\n}\n",
+		},
+		{
+			name:  "already linked",
+			input: "{\"dependencies\": {\"@demo/models\": \"file:../shared/models\"}}",
+			want:  "{\"dependencies\": {\"@demo/models\": \"file:../shared/models\"}}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := linkPackageJSON([]byte(tt.input), "@demo/models", "../shared/models")
+			if err != nil {
+				t.Fatalf("linkPackageJSON failed: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("unexpected package.json:\n got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := linkPackageJSON([]byte("not json"), "@demo/models", "../shared/models"); err == nil {
+		t.Error("expected an invalid package.json to be rejected")
+	}
+}
+
+func TestLink(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	for _, dir := range []string{"web", "api", "worker"} {
+		if err := fsys.MkdirAll(filepath.Join("demo", dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fsys.WriteFile(filepath.Join("demo", "web", "package.json"), []byte("{\n  \"name\": \"web\"\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(filepath.Join("demo", "api", "requirements.txt"), []byte("fastapi"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := Link(fsys, "demo", "web", "shared/models", "@demo/models", LanguageTypeScript)
+	if err != nil || changed != "web/package.json" {
+		t.Fatalf("expected web/package.json to change, got %q, %v", changed, err)
+	}
+
+	changed, err = Link(fsys, "demo", "api", "shared/models", "demo-models", LanguagePython)
+	if err != nil || changed != "api/requirements.txt" {
+		t.Fatalf("expected api/requirements.txt to change, got %q, %v", changed, err)
+	}
+	data, _ := fsys.ReadFile(filepath.Join("demo", "api", "requirements.txt"))
+	if string(data) != "fastapi\n-e ../shared/models\n" {
+		t.Errorf("unexpected requirements.txt:\n%s", data)
+	}
+	if changed, _ := Link(fsys, "demo", "api", "shared/models", "demo-models", LanguagePython); changed != "" {
+		t.Errorf("expected a second link to change nothing, got %s", changed)
+	}
+
+	if changed, err := Link(fsys, "demo", "worker", "shared/models", "demo-models", LanguagePython); err != nil || changed != "" {
+		t.Errorf("expected a service without requirements.txt to be left alone, got %q, %v", changed, err)
+	}
+}
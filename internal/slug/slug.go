@@ -0,0 +1,82 @@
+// Package slug turns text people type, such as "Café Orders" or "Жураўлі",
+// into the lowercase, dash-separated identifiers om uses for project,
+// service, and component names.
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// transliterations spells letters outside a-z with the ASCII letters they
+// are usually written with. Letters missing here are dropped.
+var transliterations = map[rune]string{
+	// Latin
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ĉ': "c", 'ċ': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ĝ': "g", 'ġ': "g", 'ģ': "g", 'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i", 'ĵ': "j", 'ķ': "k",
+	'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ł': "l", 'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ŕ': "r", 'ř': "r", 'ś': "s", 'ş': "s", 'š': "s", 'ŝ': "s", 'ș': "s", 'ß': "ss",
+	'ţ': "t", 'ť': "t", 'ț': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u", 'ŭ': "u",
+	'ý': "y", 'ÿ': "y", 'ŵ': "w", 'ŷ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+	// Greek
+	'α': "a", 'ά': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'έ': "e", 'ζ': "z", 'η': "i", 'ή': "i",
+	'θ': "th", 'ι': "i", 'ί': "i", 'ϊ': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'ό': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'ύ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o", 'ώ': "o",
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e", 'ё': "e", 'є': "ye",
+	'ж': "zh", 'з': "z", 'и': "i", 'і': "i", 'ї': "yi", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ў': "u", 'ф': "f",
+	'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+	'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// Make returns text as an identifier of lowercase ASCII letters, digits,
+// and single dashes, with no dash at either end. Accented, Greek, and
+// Cyrillic letters are transliterated, and every run of other characters
+// becomes one dash, so "Café Orders API" becomes "cafe-orders-api". Make
+// returns "" when text has nothing it can spell.
+func Make(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range text {
+		r = unicode.ToLower(r)
+		spelled, found := transliterations[r]
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			spelled = string(r)
+		case found && spelled == "":
+			// Signs with no sound of their own, such as ь, join their word
+			continue
+		case !found:
+			dash = true
+			continue
+		}
+		if dash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(spelled)
+		dash = false
+	}
+	return b.String()
+}
+
+// Unique returns name when taken reports it free, and otherwise name with
+// the lowest suffix from -2 up that is free
+func Unique(name string, taken func(string) bool) string {
+	if taken == nil || !taken(name) {
+		return name
+	}
+	for n := 2; ; n++ {
+		candidate := name + "-" + strconv.Itoa(n)
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+}
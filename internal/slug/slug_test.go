@@ -0,0 +1,51 @@
+package slug
+
+import "testing"
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"orders", "orders"},
+		{"My Project", "my-project"},
+		{"  Café Orders API  ", "cafe-orders-api"},
+		{"Straße & Ölmühle", "strasse-olmuhle"},
+		{"Łódź_payments", "lodz-payments"},
+		{"Жураўлі", "zhurauli"},
+		{"Объект", "obekt"},
+		{"Αθήνα", "athina"},
+		{"api--v2!!", "api-v2"},
+		{"123 go", "123-go"},
+		{"日本 shop", "shop"},
+		{"日本", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Make(tt.text); got != tt.want {
+			t.Errorf("Make(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestUnique(t *testing.T) {
+	taken := map[string]bool{"api": true, "api-2": true, "web": false}
+	isTaken := func(name string) bool { return taken[name] }
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"api", "api-3"},
+		{"web", "web"},
+		{"worker", "worker"},
+	}
+	for _, tt := range tests {
+		if got := Unique(tt.name, isTaken); got != tt.want {
+			t.Errorf("Unique(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := Unique("api", nil); got != "api" {
+		t.Errorf("Unique() without a check = %q, want api", got)
+	}
+}
@@ -0,0 +1,163 @@
+// Package smoke checks that the HTTP services of a project answer their
+// health checks: locally through the ports docker compose publishes, or
+// remotely through the load balancer of a deployed environment. Every check
+// expects the status code of the service's health entry (see
+// manifest.Service.HealthStatus) at its health path.
+package smoke
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/ports"
+)
+
+// Doer sends an HTTP request. *http.Client satisfies it.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// LocalHost is the host docker compose publishes service ports on
+const LocalHost = "localhost"
+
+// Target is a service to check, or to report as skipped
+type Target struct {
+	Service string
+	URL     string
+	Expect  int    // status code of a healthy service
+	Skip    string // why the service is not checked; empty when it is
+}
+
+// Result is the outcome of checking one target
+type Result struct {
+	Target
+	Status int   // status code received; zero when there was no response
+	Err    error // why no response was received
+}
+
+// Passed reports whether the service answered with the expected status code
+func (r Result) Passed() bool {
+	return r.Skip == "" && r.Err == nil && r.Status == r.Expect
+}
+
+// LocalTargets returns a target for every container service, checked
+// through the host port docker compose publishes it on, as recorded in lock
+func LocalTargets(m *manifest.WorkbenchManifest, lock *ports.Lock) []Target {
+	var targets []Target
+	for _, name := range serviceNames(m) {
+		service := m.Services[name]
+		if !service.IsContainer() {
+			continue
+		}
+		targets = append(targets, newTarget(name, service, fmt.Sprintf("http://%s:%d", LocalHost, lock.HostPort(name, service.Port))))
+	}
+	return targets
+}
+
+// RemoteTargets returns a target for every service deployed to the
+// environment, checked through the load balancer at baseURL. The generated
+// load balancer has no per-service routing, so every service is checked at
+// its health path on the same host.
+func RemoteTargets(m *manifest.WorkbenchManifest, env, baseURL string) []Target {
+	environment := m.Environments[env]
+	var targets []Target
+	for _, name := range serviceNames(m) {
+		service := m.Services[name]
+		if !environment.DeploysService(name, service) {
+			continue
+		}
+		targets = append(targets, newTarget(name, service, strings.TrimSuffix(baseURL, "/")))
+	}
+	return targets
+}
+
+// newTarget returns the target of a service served at base, or a skipped
+// target when the service has no HTTP endpoint to check
+func newTarget(name string, service manifest.Service, base string) Target {
+	target := Target{Service: name, Expect: service.HealthStatus()}
+	switch {
+	case service.Port == 0:
+		target.Skip = "publishes no port"
+	case service.ProtocolOrDefault() != manifest.ProtocolHTTP:
+		target.Skip = fmt.Sprintf("speaks %s, not HTTP", service.ProtocolOrDefault())
+	default:
+		target.URL = base + service.HealthPath()
+	}
+	return target
+}
+
+// LoadBalancerOutput is the output of the generated Terraform that holds the
+// DNS name of the load balancer
+const LoadBalancerOutput = "alb_dns_name"
+
+// LoadBalancerURL reads the DNS name of a deployed load balancer from the
+// output of the generated Terraform in terraformDir
+func LoadBalancerURL(run deps.Runner, terraformDir string) (string, error) {
+	output, err := run(terraformDir, "terraform", "output", "-raw", LoadBalancerOutput)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the load balancer from terraform output: %w", err)
+	}
+	host := strings.TrimSpace(string(output))
+	if host == "" {
+		return "", fmt.Errorf("terraform output has no %s; is the environment deployed with a load balancer?", LoadBalancerOutput)
+	}
+	return BaseURL(host), nil
+}
+
+// BaseURL returns the URL of the load balancer with the given DNS name. The
+// load balancer redirects plain HTTP to HTTPS.
+func BaseURL(host string) string {
+	return "https://" + host
+}
+
+// Check sends a GET request to every target that is not skipped
+func Check(client Doer, targets []Target) []Result {
+	results := make([]Result, 0, len(targets))
+	for _, target := range targets {
+		result := Result{Target: target}
+		if target.Skip == "" {
+			result.Status, result.Err = get(client, target.URL)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// get returns the status code url answers a GET request with
+func get(client Doer, url string) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// Failed returns the number of checked targets that did not pass
+func Failed(results []Result) int {
+	failed := 0
+	for _, result := range results {
+		if result.Skip == "" && !result.Passed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+// serviceNames returns the names of the manifest's services in order
+func serviceNames(m *manifest.WorkbenchManifest) []string {
+	names := make([]string, 0, len(m.Services))
+	for name := range m.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
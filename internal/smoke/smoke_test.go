@@ -0,0 +1,114 @@
+package smoke
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+	"github.com/jashkahar/open-workbench-platform/internal/ports"
+)
+
+func testManifest() *manifest.WorkbenchManifest {
+	return &manifest.WorkbenchManifest{
+		Metadata: manifest.ProjectMetadata{Name: "demo"},
+		Services: map[string]manifest.Service{
+			"api":    {Path: "./api", Port: 8000, Health: &manifest.Health{Path: "/health", Status: 204}},
+			"web":    {Path: "./web", Port: 3000},
+			"orders": {Path: "./orders", Port: 50051, Protocol: manifest.ProtocolGRPC},
+			"worker": {Path: "./worker"},
+			"mobile": {Path: "./mobile", Kind: manifest.ServiceKindLocal, Port: 8081},
+		},
+		Environments: map[string]manifest.Environment{
+			"dev": {Provider: "aws", Exclude: []string{"web"}},
+		},
+	}
+}
+
+func TestLocalTargets(t *testing.T) {
+	want := []Target{
+		{Service: "api", URL: "http://localhost:8000/health", Expect: 204},
+		{Service: "orders", Expect: 200, Skip: "speaks grpc, not HTTP"},
+		{Service: "web", URL: "http://localhost:3001/", Expect: 200},
+		{Service: "worker", Expect: 200, Skip: "publishes no port"},
+	}
+	lock := &ports.Lock{Ports: []ports.Allocation{{Service: "web", Container: 3000, Host: 3001, Requested: 3000}}}
+	if got := LocalTargets(testManifest(), lock); !reflect.DeepEqual(got, want) {
+		t.Errorf("LocalTargets() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRemoteTargets(t *testing.T) {
+	targets := RemoteTargets(testManifest(), "dev", "https://demo.elb.example/")
+	var names []string
+	for _, target := range targets {
+		names = append(names, target.Service)
+	}
+	if !reflect.DeepEqual(names, []string{"api", "orders", "worker"}) {
+		t.Errorf("expected the services deployed to dev, got %v", names)
+	}
+	if targets[0].URL != "https://demo.elb.example/health" {
+		t.Errorf("expected the health path on the load balancer, got %q", targets[0].URL)
+	}
+}
+
+func TestLoadBalancerURL(t *testing.T) {
+	run := func(dir, name string, args ...string) ([]byte, error) {
+		if dir != "terraform" || name != "terraform" {
+			t.Errorf("unexpected command %s in %s", name, dir)
+		}
+		return []byte("demo-alb-123.eu-west-1.elb.amazonaws.com\n"), nil
+	}
+	url, err := LoadBalancerURL(run, "terraform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "https://demo-alb-123.eu-west-1.elb.amazonaws.com" {
+		t.Errorf("unexpected URL %q", url)
+	}
+
+	empty := func(dir, name string, args ...string) ([]byte, error) { return nil, nil }
+	if _, err := LoadBalancerURL(empty, "terraform"); err == nil {
+		t.Error("expected an error without a load balancer")
+	}
+	failing := func(dir, name string, args ...string) ([]byte, error) { return nil, errors.New("no state") }
+	if _, err := LoadBalancerURL(failing, "terraform"); err == nil {
+		t.Error("expected an error when terraform fails")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	results := Check(server.Client(), []Target{
+		{Service: "api", URL: server.URL + "/health", Expect: 204},
+		{Service: "web", URL: server.URL + "/", Expect: 200},
+		{Service: "orders", Expect: 200, Skip: "speaks grpc, not HTTP"},
+		{Service: "down", URL: "http://127.0.0.1:0/", Expect: 200},
+	})
+
+	if !results[0].Passed() || results[0].Status != 204 {
+		t.Errorf("expected api to pass, got %+v", results[0])
+	}
+	if results[1].Passed() || results[1].Status != 503 {
+		t.Errorf("expected web to fail with 503, got %+v", results[1])
+	}
+	if results[2].Passed() || results[2].Status != 0 {
+		t.Errorf("expected orders to be skipped, got %+v", results[2])
+	}
+	if results[3].Err == nil {
+		t.Errorf("expected an error for an unreachable service, got %+v", results[3])
+	}
+	if failed := Failed(results); failed != 2 {
+		t.Errorf("expected 2 failures, got %d", failed)
+	}
+}
@@ -0,0 +1,97 @@
+package templating
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+// TemplateCatalog gives the commands of a single run a shared view of the
+// templates on a file system. Template discovery and manifest loading touch
+// the file system once; later lookups are served from memory, including
+// lookups that failed.
+type TemplateCatalog struct {
+	templateFS fs.FS
+	templates  []TemplateInfo
+	discovered bool
+	manifests  map[string]*TemplateManifest
+	errors     map[string]error
+}
+
+// NewTemplateCatalog creates a catalog for the templates on templateFS
+func NewTemplateCatalog(templateFS fs.FS) *TemplateCatalog {
+	return &TemplateCatalog{
+		templateFS: templateFS,
+		manifests:  make(map[string]*TemplateManifest),
+		errors:     make(map[string]error),
+	}
+}
+
+// FS returns the file system the templates are read from
+func (c *TemplateCatalog) FS() fs.FS {
+	return c.templateFS
+}
+
+// Templates returns the available templates sorted by name, like
+// DiscoverTemplates. Templates with invalid manifests are skipped.
+func (c *TemplateCatalog) Templates() ([]TemplateInfo, error) {
+	if c.discovered {
+		return c.templates, nil
+	}
+
+	entries, err := fs.ReadDir(c.templateFS, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates directory: %w", err)
+	}
+
+	var templates []TemplateInfo
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		info, err := c.Info(entry.Name())
+		if err != nil {
+			continue
+		}
+		templates = append(templates, *info)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	c.templates = templates
+	c.discovered = true
+	return templates, nil
+}
+
+// Manifest returns the manifest of a template, loading it on first use
+func (c *TemplateCatalog) Manifest(templateName string) (*TemplateManifest, error) {
+	if manifest, ok := c.manifests[templateName]; ok {
+		return manifest, nil
+	}
+	if err, ok := c.errors[templateName]; ok {
+		return nil, err
+	}
+
+	manifest, err := LoadTemplateManifest(c.templateFS, templateName)
+	if err != nil {
+		c.errors[templateName] = err
+		return nil, err
+	}
+	c.manifests[templateName] = manifest
+	return manifest, nil
+}
+
+// Info returns information about a template, like GetTemplateInfo
+func (c *TemplateCatalog) Info(templateName string) (*TemplateInfo, error) {
+	manifest, err := c.Manifest(templateName)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateInfo{
+		Name:        templateName,
+		Description: manifest.Description,
+		Path:        fmt.Sprintf("templates/%s", templateName),
+		Manifest:    manifest,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package templating
+
+import (
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/testutil"
+)
+
+func TestTemplateCatalog_LoadsEachManifestOnce(t *testing.T) {
+	fsys := testutil.NewCountingFS(testutil.NewCatalog(map[string]testutil.Template{
+		"api": {Manifest: `{"name": "api", "description": "An API", "parameters": [{"name": "ServiceName", "prompt": "Name?", "type": "string"}]}`},
+		"web": {Manifest: `{"name": "web", "description": "A web app", "parameters": [{"name": "ServiceName", "prompt": "Name?", "type": "string"}]}`},
+		"bad": {Manifest: `{"name": "bad"}`},
+	}))
+	catalog := NewTemplateCatalog(fsys)
+
+	for i := 0; i < 2; i++ {
+		templates, err := catalog.Templates()
+		if err != nil {
+			t.Fatalf("Templates failed: %v", err)
+		}
+		if len(templates) != 2 || templates[0].Name != "api" || templates[1].Name != "web" {
+			t.Fatalf("expected the api and web templates, got %+v", templates)
+		}
+	}
+	manifest, err := catalog.Manifest("api")
+	if err != nil {
+		t.Fatalf("Manifest failed: %v", err)
+	}
+	info, err := catalog.Info("api")
+	if err != nil {
+		t.Fatalf("Info failed: %v", err)
+	}
+	if info.Manifest != manifest || info.Path != "templates/api" {
+		t.Errorf("expected Info to share the cached manifest, got %+v", info)
+	}
+
+	// Failed lookups are remembered as well
+	for i := 0; i < 2; i++ {
+		if _, err := catalog.Manifest("bad"); err == nil {
+			t.Error("expected an error for an invalid manifest")
+		}
+		if _, err := catalog.Manifest("missing"); err == nil {
+			t.Error("expected an error for a missing template")
+		}
+	}
+
+	for _, name := range []string{"api", "web", "bad", "missing"} {
+		if opens := fsys.Opens("templates/" + name + "/template.json"); opens != 1 {
+			t.Errorf("expected %s/template.json to be read once, got %d", name, opens)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package templating
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DevelopmentVersion is the CLI version of builds without release metadata.
+// Development builds satisfy every minimumCliVersion.
+const DevelopmentVersion = "dev"
+
+// DeprecationNotice describes why a template should no longer be used, or
+// returns "" when it is not deprecated
+func (m *TemplateManifest) DeprecationNotice() string {
+	if m == nil || !m.Deprecated {
+		return ""
+	}
+	if m.SupersededBy != "" {
+		return fmt.Sprintf("deprecated, use '%s' instead", m.SupersededBy)
+	}
+	return "deprecated"
+}
+
+// CheckCLIVersion returns an error when the template requires a newer CLI
+// than cliVersion
+func CheckCLIVersion(templateName string, manifest *TemplateManifest, cliVersion string) error {
+	if manifest == nil || manifest.MinimumCLIVersion == "" || cliVersion == DevelopmentVersion {
+		return nil
+	}
+
+	required, err := parseVersion(manifest.MinimumCLIVersion)
+	if err != nil {
+		return NewInvalidManifestError(templateName, fmt.Sprintf("Invalid minimumCliVersion: %v", err), err)
+	}
+	current, err := parseVersion(cliVersion)
+	if err != nil {
+		// Unknown version formats are treated like development builds
+		return nil
+	}
+	if compareVersions(current, required) >= 0 {
+		return nil
+	}
+	return NewIncompatibleCLIError(templateName, manifest.MinimumCLIVersion, cliVersion)
+}
+
+// parseVersion parses a MAJOR.MINOR.PATCH version with an optional "v"
+// prefix. Missing components are zero and pre-release suffixes are ignored.
+func parseVersion(version string) ([3]int, error) {
+	var parsed [3]int
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) == 0 || len(parts) > 3 || parts[0] == "" {
+		return parsed, fmt.Errorf("'%s' is not a MAJOR.MINOR.PATCH version", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, fmt.Errorf("'%s' is not a MAJOR.MINOR.PATCH version", version)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
+
+// compareVersions returns -1, 0, or 1 as a is older than, equal to, or newer than b
+func compareVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
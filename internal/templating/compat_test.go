@@ -0,0 +1,68 @@
+package templating
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckCLIVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		minimum    string
+		cliVersion string
+		wantErr    bool
+	}{
+		{name: "no minimum", minimum: "", cliVersion: "0.1.0"},
+		{name: "newer CLI", minimum: "1.2.0", cliVersion: "1.10.0"},
+		{name: "equal versions", minimum: "v1.2", cliVersion: "1.2.0"},
+		{name: "older CLI", minimum: "1.3.0", cliVersion: "1.2.9", wantErr: true},
+		{name: "pre-release CLI", minimum: "2.0.0", cliVersion: "2.0.0-rc.1"},
+		{name: "development build", minimum: "99.0.0", cliVersion: DevelopmentVersion},
+		{name: "invalid minimum", minimum: "latest", cliVersion: "1.0.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := &TemplateManifest{Name: "test", MinimumCLIVersion: tt.minimum}
+			err := CheckCLIVersion("test", manifest, tt.cliVersion)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckCLIVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckCLIVersion_UpgradeHint(t *testing.T) {
+	manifest := &TemplateManifest{Name: "test", MinimumCLIVersion: "2.0.0"}
+	err := CheckCLIVersion("test", manifest, "1.4.0")
+
+	var templateErr *TemplateError
+	if !errors.As(err, &templateErr) || templateErr.Type != ErrorTypeIncompatibleCLI {
+		t.Fatalf("expected an incompatible CLI error, got %v", err)
+	}
+	if !strings.Contains(templateErr.Message, "Upgrade om") {
+		t.Errorf("expected an upgrade hint, got %q", templateErr.Message)
+	}
+}
+
+func TestTemplateManifest_DeprecationNotice(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest *TemplateManifest
+		want     string
+	}{
+		{name: "nil manifest", manifest: nil, want: ""},
+		{name: "not deprecated", manifest: &TemplateManifest{SupersededBy: "new"}, want: ""},
+		{name: "deprecated", manifest: &TemplateManifest{Deprecated: true}, want: "deprecated"},
+		{name: "superseded", manifest: &TemplateManifest{Deprecated: true, SupersededBy: "new"}, want: "deprecated, use 'new' instead"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.manifest.DeprecationNotice(); got != tt.want {
+				t.Errorf("DeprecationNotice() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
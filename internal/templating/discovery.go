@@ -7,7 +7,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/fs"
+	"net/url"
+	"path"
 	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/debuglog"
 )
 
 // TemplateManifest represents the structure of a template.json file.
@@ -17,11 +22,65 @@ import (
 // The manifest is loaded from JSON files in each template directory and
 // provides the blueprint for template processing and parameter collection.
 type TemplateManifest struct {
-	Name         string        `json:"name"`                   // Display name for the template
-	Description  string        `json:"description"`            // Human-readable description
-	Type         string        `json:"type,omitempty"`         // Template type (service, component, etc.)
-	Parameters   []Parameter   `json:"parameters"`             // List of parameters to collect
-	PostScaffold *PostScaffold `json:"postScaffold,omitempty"` // Post-processing actions
+	Name         string           `json:"name"`                   // Display name for the template
+	Description  string           `json:"description"`            // Human-readable description
+	Type         string           `json:"type,omitempty"`         // Template type (service, component, etc.)
+	Parameters   []Parameter      `json:"parameters"`             // List of parameters to collect
+	Files        ConditionalFiles `json:"files,omitempty"`        // Files and directories scaffolded only under a condition
+	RawFiles     RawFiles         `json:"rawFiles,omitempty"`     // Files copied byte-for-byte instead of rendered
+	PostScaffold *PostScaffold    `json:"postScaffold,omitempty"` // Post-processing actions
+	DocsURL      string           `json:"docsUrl,omitempty"`      // Page documenting the template
+
+	// Lifecycle metadata
+	Deprecated        bool   `json:"deprecated,omitempty"`        // Whether new projects should avoid the template
+	SupersededBy      string `json:"supersededBy,omitempty"`      // Template to use instead of a deprecated one
+	MinimumCLIVersion string `json:"minimumCliVersion,omitempty"` // Oldest om release that can scaffold the template
+}
+
+// ConditionalFiles maps paths of a template, relative to its root and
+// slash-separated, to the condition under which they are scaffolded. A
+// directory is skipped with everything in it; a file may be named with or
+// without its .tmpl suffix.
+type ConditionalFiles map[string]string
+
+// Includes reports whether the template file or directory at relPath is
+// scaffolded for values. Paths without a condition always are.
+func (files ConditionalFiles) Includes(relPath string, values map[string]interface{}) (bool, error) {
+	relPath = strings.Trim(relPath, "/")
+	for path, condition := range files {
+		path = strings.Trim(path, "/")
+		if relPath == path || strings.TrimSuffix(relPath, TemplateFileSuffix) == path {
+			return evaluateCondition(condition, values)
+		}
+	}
+	return true, nil
+}
+
+// RawFiles lists glob patterns, in path.Match syntax, of the template files
+// that are copied as they are instead of being rendered, such as images,
+// fonts, or files that use {{ }} themselves. Patterns are matched against
+// paths relative to the template root and slash-separated; a pattern
+// without a slash also matches file names in any directory, and a pattern
+// matching a directory copies everything in it as is.
+type RawFiles []string
+
+// Matches reports whether the template file at relPath is copied as is
+func (patterns RawFiles) Matches(relPath string) bool {
+	relPath = strings.Trim(relPath, "/")
+	for _, pattern := range patterns {
+		pattern = strings.Trim(pattern, "/")
+		if !strings.Contains(pattern, "/") {
+			if matched, _ := path.Match(pattern, path.Base(relPath)); matched {
+				return true
+			}
+		}
+		for dir := relPath; dir != "."; dir = path.Dir(dir) {
+			if matched, _ := path.Match(pattern, dir); matched {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // Parameter represents a single parameter that the user needs to provide.
@@ -30,7 +89,7 @@ type TemplateManifest struct {
 type Parameter struct {
 	Name       string      `json:"name"`                 // Unique parameter identifier
 	Prompt     string      `json:"prompt"`               // User-facing question text
-	HelpText   string      `json:"helpText,omitempty"`   // Additional help information
+	HelpText   string      `json:"helpText,omitempty"`   // Additional help information, in markdown
 	Group      string      `json:"group,omitempty"`      // Group for organizing parameters
 	Type       string      `json:"type"`                 // Parameter type (string, boolean, select, multiselect)
 	Required   bool        `json:"required,omitempty"`   // Whether parameter is mandatory
@@ -38,6 +97,38 @@ type Parameter struct {
 	Options    []string    `json:"options,omitempty"`    // Available options for select/multiselect
 	Condition  string      `json:"condition,omitempty"`  // Conditional visibility rule
 	Validation *Validation `json:"validation,omitempty"` // Validation rules for the parameter
+	Tier       string      `json:"tier,omitempty"`       // basic (the default) or advanced
+}
+
+// Parameter tiers. Advanced parameters are only asked when the user opts in;
+// otherwise they take their default.
+const (
+	ParameterTierBasic    = "basic"
+	ParameterTierAdvanced = "advanced"
+)
+
+// IsAdvanced reports whether the parameter is in the advanced tier
+func (p Parameter) IsAdvanced() bool {
+	return p.Tier == ParameterTierAdvanced
+}
+
+// DefaultValue returns the parameter's default as the type its prompt
+// answers with (string, bool, or []string), or nil when it has none
+func (p Parameter) DefaultValue() interface{} {
+	switch value := p.Default.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			values = append(values, fmt.Sprintf("%v", item))
+		}
+		return values
+	case bool, []string:
+		return value
+	default:
+		return fmt.Sprintf("%v", value)
+	}
 }
 
 // Validation represents validation rules for string parameters.
@@ -100,8 +191,10 @@ func LoadTemplateManifest(templateFS fs.FS, templateName string) (*TemplateManif
 	manifestPath := fmt.Sprintf("templates/%s/template.json", templateName)
 
 	// Read the manifest file from the embedded filesystem
+	debuglog.Printf("loading template manifest %s", manifestPath)
 	manifestBytes, err := fs.ReadFile(templateFS, manifestPath)
 	if err != nil {
+		debuglog.Printf("template manifest %s: %v", manifestPath, err)
 		return nil, NewTemplateNotFoundError(templateName, err)
 	}
 
@@ -121,6 +214,16 @@ func LoadTemplateManifest(templateFS fs.FS, templateName string) (*TemplateManif
 	if len(manifest.Parameters) == 0 {
 		return nil, NewInvalidManifestError(templateName, "Missing required field: parameters", nil)
 	}
+	if manifest.MinimumCLIVersion != "" {
+		if _, err := parseVersion(manifest.MinimumCLIVersion); err != nil {
+			return nil, NewInvalidManifestError(templateName, fmt.Sprintf("Invalid minimumCliVersion: %v", err), err)
+		}
+	}
+	if manifest.DocsURL != "" {
+		if parsed, err := url.Parse(manifest.DocsURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, NewInvalidManifestError(templateName, fmt.Sprintf("Invalid docsUrl: %s is not an http(s) URL", manifest.DocsURL), err)
+		}
+	}
 
 	return &manifest, nil
 }
@@ -237,6 +340,12 @@ func ValidateTemplate(templateFS fs.FS, templateName string) error {
 			return NewInvalidManifestError(templateName, fmt.Sprintf("Parameter %d missing required field: type", i), nil)
 		}
 
+		switch param.Tier {
+		case "", ParameterTierBasic, ParameterTierAdvanced:
+		default:
+			return NewInvalidManifestError(templateName, fmt.Sprintf("Parameter '%s' has invalid tier: %s", param.Name, param.Tier), nil)
+		}
+
 		// Validate parameter type against supported types
 		switch param.Type {
 		case "string", "boolean", "select", "multiselect":
@@ -249,6 +358,56 @@ func ValidateTemplate(templateFS fs.FS, templateName string) error {
 		if (param.Type == "select" || param.Type == "multiselect") && len(param.Options) == 0 {
 			return NewInvalidManifestError(templateName, fmt.Sprintf("Parameter '%s' of type %s must have options", param.Name, param.Type), nil)
 		}
+
+		if param.Condition != "" {
+			if _, err := parseCondition(param.Condition); err != nil {
+				return NewInvalidManifestError(templateName, fmt.Sprintf("Parameter '%s' has an invalid condition", param.Name), err)
+			}
+		}
+	}
+
+	// Conditional files must name a file or directory of the template, so
+	// a typo does not silently scaffold everything
+	paths := make([]string, 0, len(manifest.Files))
+	for path := range manifest.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if _, err := parseCondition(manifest.Files[path]); err != nil {
+			return NewInvalidManifestError(templateName, fmt.Sprintf("File '%s' has an invalid condition", path), err)
+		}
+		source := "templates/" + templateName + "/" + strings.Trim(path, "/")
+		if _, err := fs.Stat(templateFS, source); err != nil {
+			if _, err := fs.Stat(templateFS, source+TemplateFileSuffix); err != nil {
+				return NewInvalidManifestError(templateName, fmt.Sprintf("File '%s' has a condition but does not exist in the template", path), nil)
+			}
+		}
+	}
+
+	for _, pattern := range manifest.RawFiles {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return NewInvalidManifestError(templateName, fmt.Sprintf("Raw file pattern '%s' is invalid", pattern), err)
+		}
+	}
+
+	// Conditions of post-scaffold actions fail the scaffold when they do not
+	// parse, so catch them here
+	if manifest.PostScaffold == nil {
+		return nil
+	}
+	for _, action := range manifest.PostScaffold.FilesToDelete {
+		if _, err := parseCondition(action.Condition); err != nil {
+			return NewInvalidManifestError(templateName, fmt.Sprintf("File deletion '%s' has an invalid condition", action.Path), err)
+		}
+	}
+	for _, action := range manifest.PostScaffold.Commands {
+		if action.Condition == "" {
+			continue
+		}
+		if _, err := parseCondition(action.Condition); err != nil {
+			return NewInvalidManifestError(templateName, fmt.Sprintf("Command '%s' has an invalid condition", action.Command), err)
+		}
 	}
 
 	return nil
@@ -1,7 +1,11 @@
 package templating
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestTemplateManifest_Validation(t *testing.T) {
@@ -378,3 +382,112 @@ func TestCommandAction_Structure(t *testing.T) {
 		t.Error("expected description to be non-empty")
 	}
 }
+
+func TestParameter_DefaultValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		param Parameter
+		want  interface{}
+	}{
+		{name: "no default", param: Parameter{Type: "string"}, want: nil},
+		{name: "boolean", param: Parameter{Type: "boolean", Default: true}, want: true},
+		{name: "string", param: Parameter{Type: "select", Default: "Jest"}, want: "Jest"},
+		{name: "number", param: Parameter{Type: "string", Default: float64(8080)}, want: "8080"},
+		{name: "multiselect", param: Parameter{Type: "multiselect", Default: []interface{}{"a", "b"}}, want: []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.param.DefaultValue()
+			if fmt.Sprintf("%#v", got) != fmt.Sprintf("%#v", tt.want) {
+				t.Errorf("DefaultValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTemplate_InvalidTier(t *testing.T) {
+	catalog := fstest.MapFS{
+		"templates/tiered/template.json": &fstest.MapFile{Data: []byte(`{"name": "tiered", "description": "Tiered", "parameters": [{"name": "A", "prompt": "A?", "type": "boolean", "tier": "expert"}]}`)},
+	}
+	if err := ValidateTemplate(catalog, "tiered"); err == nil {
+		t.Error("expected an unknown tier to be rejected")
+	}
+}
+
+func TestValidateTemplate_InvalidCondition(t *testing.T) {
+	catalog := fstest.MapFS{
+		"templates/conditional/template.json": &fstest.MapFile{Data: []byte(`{"name": "conditional", "description": "Conditional", "parameters": [{"name": "A", "prompt": "A?", "type": "boolean", "condition": "B == true &&"}]}`)},
+		"templates/cleanup/template.json":     &fstest.MapFile{Data: []byte(`{"name": "cleanup", "description": "Cleanup", "parameters": [{"name": "A", "prompt": "A?", "type": "boolean"}], "postScaffold": {"filesToDelete": [{"path": "x", "condition": "(A == true"}]}}`)},
+	}
+	for _, name := range []string{"conditional", "cleanup"} {
+		var templateErr *TemplateError
+		if err := ValidateTemplate(catalog, name); !errors.As(err, &templateErr) || templateErr.OriginalErr == nil || !strings.Contains(templateErr.OriginalErr.Error(), "invalid condition") {
+			t.Errorf("ValidateTemplate(%s) = %v, want an invalid condition error", name, err)
+		}
+	}
+}
+
+func TestValidateTemplate_ConditionalFiles(t *testing.T) {
+	manifest := `{"name": "%s", "description": "Files", "parameters": [{"name": "A", "prompt": "A?", "type": "boolean"}], "files": {%s}}`
+	catalog := fstest.MapFS{
+		"templates/valid/template.json":      &fstest.MapFile{Data: []byte(fmt.Sprintf(manifest, "valid", `"tests/": "A == true", "main.go": "A"`))},
+		"templates/valid/tests/app_test.js":  &fstest.MapFile{Data: []byte("test")},
+		"templates/valid/main.go.tmpl":       &fstest.MapFile{Data: []byte("package main")},
+		"templates/missing/template.json":    &fstest.MapFile{Data: []byte(fmt.Sprintf(manifest, "missing", `"tset/": "A"`))},
+		"templates/unparsable/template.json": &fstest.MapFile{Data: []byte(fmt.Sprintf(manifest, "unparsable", `"README.md": "A ==" `))},
+		"templates/unparsable/README.md":     &fstest.MapFile{Data: []byte("readme")},
+	}
+	if err := ValidateTemplate(catalog, "valid"); err != nil {
+		t.Errorf("ValidateTemplate(valid) = %v", err)
+	}
+	var templateErr *TemplateError
+	if err := ValidateTemplate(catalog, "missing"); !errors.As(err, &templateErr) || !strings.Contains(templateErr.Details, "does not exist") {
+		t.Errorf("ValidateTemplate(missing) = %v, want a missing path error", err)
+	}
+	if err := ValidateTemplate(catalog, "unparsable"); !errors.As(err, &templateErr) || templateErr.OriginalErr == nil {
+		t.Errorf("ValidateTemplate(unparsable) = %v, want an invalid condition error", err)
+	}
+}
+
+func TestValidateTemplate_RawFiles(t *testing.T) {
+	manifest := `{"name": "%s", "description": "Raw", "parameters": [{"name": "A", "prompt": "A?", "type": "boolean"}], "rawFiles": [%s]}`
+	catalog := fstest.MapFS{
+		"templates/valid/template.json":   &fstest.MapFile{Data: []byte(fmt.Sprintf(manifest, "valid", `"charts", "*.hbs"`))},
+		"templates/invalid/template.json": &fstest.MapFile{Data: []byte(fmt.Sprintf(manifest, "invalid", `"assets/[a-"`))},
+	}
+	if err := ValidateTemplate(catalog, "valid"); err != nil {
+		t.Errorf("ValidateTemplate(valid) = %v", err)
+	}
+	var templateErr *TemplateError
+	if err := ValidateTemplate(catalog, "invalid"); !errors.As(err, &templateErr) || !strings.Contains(templateErr.Details, "assets/[a-") {
+		t.Errorf("ValidateTemplate(invalid) = %v, want an invalid pattern error", err)
+	}
+
+	raw := RawFiles{"charts", "*.hbs", "public/*.svg"}
+	for relPath, want := range map[string]bool{
+		"/charts/api/values.yaml": true,
+		"/src/email.hbs":          true,
+		"/public/logo.svg":        true,
+		"/src/logo.svg":           false,
+		"/src/charts.js":          false,
+	} {
+		if got := raw.Matches(relPath); got != want {
+			t.Errorf("Matches(%s) = %v, want %v", relPath, got, want)
+		}
+	}
+}
+
+func TestLoadTemplateManifest_DocsURL(t *testing.T) {
+	catalog := fstest.MapFS{
+		"templates/documented/template.json": &fstest.MapFile{Data: []byte(`{"name": "documented", "description": "Documented", "docsUrl": "https://example.com/docs", "parameters": [{"name": "A", "prompt": "A?", "type": "boolean"}]}`)},
+		"templates/relative/template.json":   &fstest.MapFile{Data: []byte(`{"name": "relative", "description": "Relative", "docsUrl": "docs/README.md", "parameters": [{"name": "A", "prompt": "A?", "type": "boolean"}]}`)},
+	}
+	manifest, err := LoadTemplateManifest(catalog, "documented")
+	if err != nil || manifest.DocsURL != "https://example.com/docs" {
+		t.Errorf("LoadTemplateManifest(documented) = %v, %v, want the docs URL", manifest, err)
+	}
+	if _, err := LoadTemplateManifest(catalog, "relative"); err == nil {
+		t.Error("expected a docsUrl that is not an http(s) URL to be rejected")
+	}
+}
@@ -4,6 +4,7 @@
 package templating
 
 import (
+	"errors"
 	"fmt"
 	"os"
 )
@@ -30,6 +31,8 @@ const (
 	ErrorTypePermission
 	// ErrorTypeNetwork indicates a network-related error (e.g., git operations)
 	ErrorTypeNetwork
+	// ErrorTypeIncompatibleCLI indicates that a template requires a newer CLI
+	ErrorTypeIncompatibleCLI
 )
 
 // TemplateError represents a structured error with context and user-friendly messages
@@ -236,10 +239,29 @@ func NewNetworkError(operation string, originalErr error) *TemplateError {
 	}
 }
 
+// NewIncompatibleCLIError creates an error for a template that requires a newer CLI
+func NewIncompatibleCLIError(templateName, requiredVersion, cliVersion string) *TemplateError {
+	message := fmt.Sprintf("Template '%s' requires om %s or newer (this is om %s).", templateName, requiredVersion, cliVersion)
+
+	suggestions := "\n\nPossible solutions:\n"
+	suggestions += "• Upgrade om: brew upgrade open-workbench-platform, scoop update open-workbench-platform,\n"
+	suggestions += "  or go install github.com/jashkahar/open-workbench-platform@latest\n"
+	suggestions += "• Choose a different template\n"
+
+	return &TemplateError{
+		Type:     ErrorTypeIncompatibleCLI,
+		Message:  message + suggestions,
+		Details:  fmt.Sprintf("minimumCliVersion: %s, current version: %s", requiredVersion, cliVersion),
+		Template: templateName,
+	}
+}
+
 // FormatErrorForUser formats an error for user display, providing
-// context and suggestions based on the error type.
+// context and suggestions based on the error type. Wrapped template
+// errors are unwrapped so their suggestions survive fmt.Errorf("%w").
 func FormatErrorForUser(err error) string {
-	if templateErr, ok := err.(*TemplateError); ok {
+	var templateErr *TemplateError
+	if errors.As(err, &templateErr) {
 		return templateErr.Message
 	}
 
@@ -249,13 +271,14 @@ func FormatErrorForUser(err error) string {
 
 // IsTemplateError checks if an error is a structured template error
 func IsTemplateError(err error) bool {
-	_, ok := err.(*TemplateError)
-	return ok
+	var templateErr *TemplateError
+	return errors.As(err, &templateErr)
 }
 
 // GetErrorType returns the error type for a template error
 func GetErrorType(err error) ErrorType {
-	if templateErr, ok := err.(*TemplateError); ok {
+	var templateErr *TemplateError
+	if errors.As(err, &templateErr) {
 		return templateErr.Type
 	}
 	return ErrorTypeTemplateProcessing // Default for unknown errors
@@ -263,7 +286,8 @@ func GetErrorType(err error) ErrorType {
 
 // ShouldRetry determines if an operation should be retried based on the error type
 func ShouldRetry(err error) bool {
-	if templateErr, ok := err.(*TemplateError); ok {
+	var templateErr *TemplateError
+	if errors.As(err, &templateErr) {
 		switch templateErr.Type {
 		case ErrorTypeNetwork, ErrorTypeFileSystem:
 			return true
@@ -0,0 +1,450 @@
+package templating
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Conditions of parameters and post-scaffold actions are small expressions
+// over the parameter values:
+//
+//	IncludeTesting == true && TestingFramework != 'Jest'
+//	!(IncludeDocker || contains(Features, 'API'))
+//	Port >= 1024
+//
+// Operators, from the lowest precedence to the highest, are ||, &&, the
+// comparisons == != < <= > >=, and !. Operands are parameter names, strings
+// in single or double quotes, numbers, true and false, parenthesised
+// expressions, and calls to contains(list or string, value). A parameter
+// without a value, such as one hidden by its own condition, is only equal to
+// nothing and is false where a boolean is expected.
+
+// evaluateCondition parses and evaluates a condition against values
+func evaluateCondition(condition string, values map[string]interface{}) (bool, error) {
+	expr, err := parseCondition(condition)
+	if err != nil {
+		return false, err
+	}
+	value, err := expr.eval(values)
+	if err != nil {
+		return false, fmt.Errorf("condition %q: %w", condition, err)
+	}
+	return truthy(value), nil
+}
+
+// parseCondition parses a condition, reporting the position of the first
+// syntax error
+func parseCondition(condition string) (expression, error) {
+	tokens, err := tokenize(condition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", condition, err)
+	}
+	p := &conditionParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err == nil && p.peek().kind != tokenEOF {
+		err = p.unexpected()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", condition, err)
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type conditionToken struct {
+	kind  tokenKind
+	text  string
+	value interface{} // the literal of string and number tokens
+	pos   int
+}
+
+// operators are the operator and punctuation tokens, longest first
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", ","}
+
+// tokenize splits a condition into tokens
+func tokenize(input string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"':
+			var text strings.Builder
+			j := i + 1
+			for ; j < len(input) && input[j] != c; j++ {
+				if input[j] == '\\' && j+1 < len(input) {
+					j++
+				}
+				text.WriteByte(input[j])
+			}
+			if j >= len(input) {
+				return nil, fmt.Errorf("unterminated string at position %d", i+1)
+			}
+			tokens = append(tokens, conditionToken{kind: tokenString, text: input[i : j+1], value: text.String(), pos: i})
+			i = j + 1
+		case isDigit(c) || (c == '-' && i+1 < len(input) && isDigit(input[i+1])):
+			j := i + 1
+			for j < len(input) && (isDigit(input[j]) || input[j] == '.') {
+				j++
+			}
+			number, err := strconv.ParseFloat(input[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", input[i:j], i+1)
+			}
+			tokens = append(tokens, conditionToken{kind: tokenNumber, text: input[i:j], value: number, pos: i})
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(input) && (input[j] == '_' || isDigit(input[j]) || unicode.IsLetter(rune(input[j]))) {
+				j++
+			}
+			tokens = append(tokens, conditionToken{kind: tokenIdent, text: input[i:j], pos: i})
+			i = j
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(input[i:], op) {
+					tokens = append(tokens, conditionToken{kind: tokenOperator, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected %q at position %d", string(c), i+1)
+			}
+		}
+	}
+	return append(tokens, conditionToken{kind: tokenEOF, pos: len(input)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// conditionParser is a recursive descent parser over the tokens of a
+// condition, with one function per precedence level
+type conditionParser struct {
+	tokens []conditionToken
+	next   int
+}
+
+func (p *conditionParser) peek() conditionToken {
+	return p.tokens[p.next]
+}
+
+// accept consumes the next token if it is the operator op
+func (p *conditionParser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.text == op {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *conditionParser) unexpected() error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of condition")
+	}
+	return fmt.Errorf("unexpected %q at position %d", t.text, t.pos+1)
+}
+
+func (p *conditionParser) parseOr() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (expression, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseComparison() (expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			right, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return comparisonExpr{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (expression, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *conditionParser) parsePrimary() (expression, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenString, tokenNumber:
+		p.next++
+		return literalExpr{value: t.value}, nil
+	case tokenIdent:
+		p.next++
+		switch t.text {
+		case "true":
+			return literalExpr{value: true}, nil
+		case "false":
+			return literalExpr{value: false}, nil
+		}
+		if !p.accept("(") {
+			return paramExpr{name: t.text}, nil
+		}
+		if t.text != "contains" {
+			return nil, fmt.Errorf("unknown function %q at position %d", t.text, t.pos+1)
+		}
+		var args []expression
+		for !p.accept(")") {
+			if len(args) > 0 && !p.accept(",") {
+				return nil, p.unexpected()
+			}
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+		}
+		if len(args) != 2 {
+			return nil, fmt.Errorf("contains() at position %d takes 2 arguments, got %d", t.pos+1, len(args))
+		}
+		return containsExpr{haystack: args[0], needle: args[1]}, nil
+	case tokenOperator:
+		if p.accept("(") {
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.accept(")") {
+				return nil, p.unexpected()
+			}
+			return expr, nil
+		}
+	}
+	return nil, p.unexpected()
+}
+
+// expression is a parsed condition or part of one
+type expression interface {
+	eval(values map[string]interface{}) (interface{}, error)
+}
+
+type literalExpr struct{ value interface{} }
+
+func (e literalExpr) eval(map[string]interface{}) (interface{}, error) {
+	return e.value, nil
+}
+
+type paramExpr struct{ name string }
+
+func (e paramExpr) eval(values map[string]interface{}) (interface{}, error) {
+	return values[e.name], nil
+}
+
+type notExpr struct{ operand expression }
+
+func (e notExpr) eval(values map[string]interface{}) (interface{}, error) {
+	value, err := e.operand.eval(values)
+	if err != nil {
+		return nil, err
+	}
+	return !truthy(value), nil
+}
+
+// logicalExpr is && or ||, evaluating its right side only when needed
+type logicalExpr struct {
+	or          bool
+	left, right expression
+}
+
+func (e logicalExpr) eval(values map[string]interface{}) (interface{}, error) {
+	left, err := e.left.eval(values)
+	if err != nil {
+		return nil, err
+	}
+	if truthy(left) == e.or {
+		return e.or, nil
+	}
+	right, err := e.right.eval(values)
+	if err != nil {
+		return nil, err
+	}
+	return truthy(right), nil
+}
+
+type comparisonExpr struct {
+	op          string
+	left, right expression
+}
+
+func (e comparisonExpr) eval(values map[string]interface{}) (interface{}, error) {
+	left, err := e.left.eval(values)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(values)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	}
+
+	// A parameter without a value is neither smaller nor larger than anything
+	if left == nil || right == nil {
+		return false, nil
+	}
+	l, lok := toNumber(left)
+	r, rok := toNumber(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("cannot compare %v %s %v: both sides must be numbers", formatMatrixValue(left), e.op, formatMatrixValue(right))
+	}
+	switch e.op {
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	default:
+		return l >= r, nil
+	}
+}
+
+// containsExpr reports whether a list has an element equal to the needle, or
+// a string contains it
+type containsExpr struct{ haystack, needle expression }
+
+func (e containsExpr) eval(values map[string]interface{}) (interface{}, error) {
+	haystack, err := e.haystack.eval(values)
+	if err != nil {
+		return nil, err
+	}
+	needle, err := e.needle.eval(values)
+	if err != nil {
+		return nil, err
+	}
+	switch h := haystack.(type) {
+	case nil:
+		return false, nil
+	case string:
+		return strings.Contains(h, fmt.Sprintf("%v", needle)), nil
+	case []string:
+		for _, element := range h {
+			if equal(element, needle) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case []interface{}:
+		for _, element := range h {
+			if equal(element, needle) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return nil, fmt.Errorf("contains() needs a list or a string, got %v", haystack)
+	}
+}
+
+// equal compares two values: numerically when both are numbers, such as a
+// port given as "8080", and by their text otherwise, so a boolean parameter
+// supplied as "true" still equals true
+func equal(left, right interface{}) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	if l, ok := toNumber(left); ok {
+		if r, ok := toNumber(right); ok {
+			return l == r
+		}
+	}
+	return formatMatrixValue(left) == formatMatrixValue(right)
+}
+
+// toNumber converts numbers and numeric strings to float64
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+// truthy reports whether a value counts as true where a boolean is expected:
+// false, nothing, "", "false", zero, and empty lists do not
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != "" && v != "false"
+	case []string:
+		return len(v) > 0
+	case []interface{}:
+		return len(v) > 0
+	}
+	if number, ok := toNumber(value); ok {
+		return number != 0
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package templating
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	values := map[string]interface{}{
+		"IncludeTesting":   true,
+		"TestingFramework": "Vitest",
+		"IncludeDocker":    false,
+		"Features":         []string{"auth", "cache"},
+		"Port":             "8080",
+		"Replicas":         3,
+		"ServiceName":      "it's-api",
+	}
+
+	tests := []struct {
+		condition string
+		want      bool
+	}{
+		{"IncludeTesting == true && TestingFramework != 'Jest'", true},
+		{"IncludeTesting == true && TestingFramework == 'Jest'", false},
+		{`TestingFramework == "Vitest"`, true},
+		{"IncludeDocker || TestingFramework == 'Vitest'", true},
+		{"!IncludeDocker", true},
+		{"!(IncludeDocker || IncludeTesting)", false},
+		{"IncludeDocker == false", true},
+		{"true", true},
+		{"contains(Features, 'auth') && !contains(Features, 'api')", true},
+		{"contains(TestingFramework, 'test')", true},
+		{"contains(Missing, 'auth')", false},
+		{"Port >= 1024 && Port < 65536", true},
+		{"Port == 8080", true},
+		{"Replicas > 2.5", true},
+		{"Replicas <= -1", false},
+		{"ServiceName == 'it\\'s-api'", true},
+		// A parameter without a value equals nothing and is false
+		{"Missing == 'Jest'", false},
+		{"Missing != 'Jest'", true},
+		{"Missing == false", false},
+		{"Missing > 1", false},
+		{"Missing", false},
+		// && binds tighter than ||
+		{"IncludeTesting || IncludeDocker && false", true},
+		{"(IncludeTesting || IncludeDocker) && false", false},
+	}
+	for _, tt := range tests {
+		got, err := evaluateCondition(tt.condition, values)
+		if err != nil {
+			t.Errorf("evaluateCondition(%q) error = %v", tt.condition, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("evaluateCondition(%q) = %v, want %v", tt.condition, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateCondition_Errors(t *testing.T) {
+	tests := []struct {
+		condition string
+		want      string
+	}{
+		{"", "unexpected end of condition"},
+		{"IncludeTesting == true &&", "unexpected end of condition"},
+		{"(IncludeTesting == true", "unexpected end of condition"},
+		{"IncludeTesting = true", `unexpected "=" at position 16`},
+		{"TestingFramework != 'Jest", "unterminated string at position 21"},
+		{"A == B == C", `unexpected "==" at position 8`},
+		{"startsWith(A, 'x')", `unknown function "startsWith"`},
+		{"contains(Features)", "takes 2 arguments, got 1"},
+		{"TestingFramework > 2", "both sides must be numbers"},
+		{"contains(IncludeTesting, 'x')", "needs a list or a string"},
+	}
+	values := map[string]interface{}{"IncludeTesting": true, "TestingFramework": "Jest", "Features": []string{}}
+	for _, tt := range tests {
+		_, err := evaluateCondition(tt.condition, values)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("evaluateCondition(%q) error = %v, want %q", tt.condition, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package templating
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"gopkg.in/yaml.v3"
+)
+
+// dockerfileInstructions are the instructions a Dockerfile may contain
+var dockerfileInstructions = map[string]bool{
+	"ADD": true, "ARG": true, "CMD": true, "COPY": true, "ENTRYPOINT": true,
+	"ENV": true, "EXPOSE": true, "FROM": true, "HEALTHCHECK": true, "LABEL": true,
+	"MAINTAINER": true, "ONBUILD": true, "RUN": true, "SHELL": true,
+	"STOPSIGNAL": true, "USER": true, "VOLUME": true, "WORKDIR": true,
+}
+
+// CheckRenderedFiles checks that the files of a rendered template parse:
+// JSON documents, YAML documents, Go sources, and Dockerfiles. Files of other
+// kinds are not checked. It returns one error per invalid file, with paths
+// relative to root.
+func CheckRenderedFiles(output *filesystem.MemFS, root string) []error {
+	var problems []error
+	for _, name := range output.Files() {
+		rel, err := filepath.Rel(root, name)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		data, err := output.ReadFile(name)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", filepath.ToSlash(rel), err))
+			continue
+		}
+		if err := checkRenderedFile(rel, data); err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", filepath.ToSlash(rel), err))
+		}
+	}
+	return problems
+}
+
+// checkRenderedFile checks a single file based on its name
+func checkRenderedFile(name string, data []byte) error {
+	base := filepath.Base(name)
+	switch {
+	case strings.HasPrefix(base, "Dockerfile"):
+		return lintDockerfile(data)
+	case filepath.Ext(base) == ".json" && !strings.HasPrefix(base, "tsconfig") && !strings.HasPrefix(base, "jsconfig"):
+		// tsconfig and jsconfig files allow comments, which JSON does not
+		if !json.Valid(data) {
+			return fmt.Errorf("invalid JSON")
+		}
+	case filepath.Ext(base) == ".yml" || filepath.Ext(base) == ".yaml":
+		var document interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return fmt.Errorf("invalid YAML: %w", err)
+		}
+	case filepath.Ext(base) == ".go":
+		if _, err := parser.ParseFile(token.NewFileSet(), base, data, parser.AllErrors); err != nil {
+			return fmt.Errorf("invalid Go source: %w", err)
+		}
+	}
+	return nil
+}
+
+// lintDockerfile checks that every instruction is known and that the first
+// one, after any ARG, is FROM with an image
+func lintDockerfile(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	continued := false
+	sawFrom := false
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if continued {
+			// Comment lines inside a continued instruction are skipped by Docker
+			if line != "" && !strings.HasPrefix(line, "#") {
+				continued = strings.HasSuffix(line, "\\")
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		continued = strings.HasSuffix(line, "\\")
+
+		fields := strings.Fields(line)
+		instruction := strings.ToUpper(fields[0])
+		if !dockerfileInstructions[instruction] {
+			return fmt.Errorf("line %d: unknown instruction '%s'", lineNumber, fields[0])
+		}
+		if !sawFrom && instruction != "FROM" && instruction != "ARG" {
+			return fmt.Errorf("line %d: %s before FROM", lineNumber, instruction)
+		}
+		if instruction == "FROM" {
+			if len(fields) < 2 {
+				return fmt.Errorf("line %d: FROM without an image", lineNumber)
+			}
+			sawFrom = true
+		}
+	}
+	if !sawFrom {
+		return fmt.Errorf("no FROM instruction")
+	}
+	return nil
+}
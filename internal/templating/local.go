@@ -0,0 +1,123 @@
+package templating
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/debuglog"
+)
+
+// WithLocalTemplates returns templateFS with the templates kept in the
+// directories dirs added to it. Each directory holds one subdirectory per
+// template, laid out like the built-in templates/ directory. A local
+// template replaces a built-in one of the same name, and the first directory
+// wins when several hold the same template. Directories that do not exist
+// are skipped, and templateFS is returned as is when there are no local
+// templates.
+//
+// Local templates are read from disk on every run, so changes to them take
+// effect without rebuilding om.
+func WithLocalTemplates(templateFS fs.FS, dirs ...string) fs.FS {
+	overlay := &localFS{base: templateFS, templates: make(map[string]string)}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				debuglog.Printf("skipping local templates in %s: %v", dir, err)
+			}
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if _, found := overlay.templates[name]; found {
+				continue
+			}
+			templateDir := filepath.Join(dir, name)
+			if _, err := os.Stat(filepath.Join(templateDir, "template.json")); err != nil {
+				continue
+			}
+			debuglog.Printf("using local template %s from %s", name, templateDir)
+			overlay.templates[name] = templateDir
+		}
+	}
+	if len(overlay.templates) == 0 {
+		return templateFS
+	}
+	return overlay
+}
+
+// localFS serves local templates from disk on top of the built-in templates
+type localFS struct {
+	base      fs.FS
+	templates map[string]string // template name to its directory on disk
+}
+
+// local returns the directory on disk and the path inside it of a file that
+// belongs to a local template
+func (l *localFS) local(name string) (string, string, bool) {
+	rest, found := strings.CutPrefix(name, "templates/")
+	if !found {
+		return "", "", false
+	}
+	template, inside, _ := strings.Cut(rest, "/")
+	dir, found := l.templates[template]
+	if !found {
+		return "", "", false
+	}
+	if inside == "" {
+		inside = "."
+	}
+	return dir, inside, true
+}
+
+// Open opens files of local templates from disk and every other file from
+// the built-in templates
+func (l *localFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if dir, inside, found := l.local(name); found {
+		return os.DirFS(dir).Open(inside)
+	}
+	return l.base.Open(name)
+}
+
+// ReadDir lists the templates directory with the local templates merged
+// in, and every other directory from where its files are read
+func (l *localFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if dir, inside, found := l.local(name); found {
+		return fs.ReadDir(os.DirFS(dir), inside)
+	}
+	if name != "templates" {
+		return fs.ReadDir(l.base, name)
+	}
+
+	entries, err := fs.ReadDir(l.base, name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	merged := make([]fs.DirEntry, 0, len(entries)+len(l.templates))
+	for _, entry := range entries {
+		if _, found := l.templates[entry.Name()]; !found {
+			merged = append(merged, entry)
+		}
+	}
+	for _, dir := range l.templates {
+		info, err := os.Stat(dir)
+		if err != nil {
+			continue
+		}
+		merged = append(merged, fs.FileInfoToDirEntry(info))
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Name() < merged[j].Name()
+	})
+	return merged, nil
+}
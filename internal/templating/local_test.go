@@ -0,0 +1,62 @@
+package templating
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/testutil"
+)
+
+func TestWithLocalTemplates(t *testing.T) {
+	builtin := testutil.NewCatalog(map[string]testutil.Template{
+		"api": {Manifest: `{"name": "api", "description": "Built-in API", "parameters": [{"name": "ServiceName", "prompt": "Name?", "type": "string"}]}`},
+		"web": {Manifest: `{"name": "web", "description": "Built-in web app", "parameters": [{"name": "ServiceName", "prompt": "Name?", "type": "string"}]}`},
+	})
+	write := func(dir, name, description string) {
+		t.Helper()
+		files := map[string]string{
+			"template.json":    `{"name": "` + name + `", "description": "` + description + `", "parameters": [{"name": "ServiceName", "prompt": "Name?", "type": "string"}]}`,
+			"src/main.go.tmpl": "package main\n",
+		}
+		for file, content := range files {
+			path := filepath.Join(dir, name, filepath.FromSlash(file))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	first, second := t.TempDir(), t.TempDir()
+	write(first, "api", "My API")
+	write(second, "api", "Shadowed API")
+	write(second, "worker", "My worker")
+	if err := os.MkdirAll(filepath.Join(second, "notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	templateFS := WithLocalTemplates(builtin, first, filepath.Join(first, "missing"), second)
+	templates, err := DiscoverTemplates(templateFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var listed []string
+	for _, template := range templates {
+		listed = append(listed, template.Name+"="+template.Description)
+	}
+	if got := strings.Join(listed, ","); got != "api=My API,web=Built-in web app,worker=My worker" {
+		t.Errorf("DiscoverTemplates() = %s", got)
+	}
+
+	files, _, err := TemplateFiles(templateFS, "worker")
+	if err != nil || strings.Join(files, ",") != "src/main.go" {
+		t.Errorf("TemplateFiles() = %v, %v", files, err)
+	}
+
+	if _, overlay := WithLocalTemplates(builtin, filepath.Join(first, "missing")).(*localFS); overlay {
+		t.Error("expected no overlay without local templates")
+	}
+}
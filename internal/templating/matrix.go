@@ -0,0 +1,183 @@
+package templating
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// SampleStringValue is used for string parameters without a default when a
+// template is rendered across its parameter matrix. It satisfies the
+// lowercase name patterns the bundled templates validate against.
+const SampleStringValue = "sample-app"
+
+// MatrixCase is one parameter combination a template is rendered with
+type MatrixCase struct {
+	Name   string                 // Short description, e.g. "IncludeTesting=false"
+	Values map[string]interface{} // Values of the visible parameters
+}
+
+// ParameterMatrix returns the parameter combinations that exercise every
+// choice of a template: the defaults, then one case per flipped boolean and
+// per select option, and the empty and full choice of each multiselect.
+// Parameters hidden by their condition are left out, and combinations that
+// end up identical are only returned once. seed holds values, such as the
+// project name, that templates expect without declaring them.
+func ParameterMatrix(manifest *TemplateManifest, seed map[string]interface{}) []MatrixCase {
+	defaults := make(map[string]interface{}, len(manifest.Parameters))
+	for _, param := range manifest.Parameters {
+		defaults[param.Name] = matrixDefault(param)
+	}
+
+	cases := []MatrixCase{{Name: "defaults", Values: defaults}}
+	for _, param := range manifest.Parameters {
+		for _, value := range matrixVariants(param, defaults[param.Name]) {
+			values := make(map[string]interface{}, len(defaults))
+			for name, v := range defaults {
+				values[name] = v
+			}
+			values[param.Name] = value
+			cases = append(cases, MatrixCase{Name: fmt.Sprintf("%s=%v", param.Name, formatMatrixValue(value)), Values: values})
+		}
+	}
+
+	var matrix []MatrixCase
+	seen := make(map[string]bool)
+	for _, c := range cases {
+		c.Values = visibleValues(manifest, c.Values, seed)
+		key := matrixKey(c.Values)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		matrix = append(matrix, c)
+	}
+	return matrix
+}
+
+// matrixDefault returns the value a parameter takes in the defaults case
+func matrixDefault(param Parameter) interface{} {
+	switch param.Type {
+	case "boolean":
+		if value, ok := param.Default.(bool); ok {
+			return value
+		}
+		return false
+	case "multiselect":
+		var values []string
+		if defaults, ok := param.Default.([]interface{}); ok {
+			for _, value := range defaults {
+				values = append(values, fmt.Sprintf("%v", value))
+			}
+		}
+		if values == nil {
+			values = []string{}
+		}
+		return values
+	case "select":
+		if value, ok := param.Default.(string); ok && value != "" {
+			return value
+		}
+		if len(param.Options) > 0 {
+			return param.Options[0]
+		}
+		return ""
+	default:
+		if param.Default != nil {
+			if value := fmt.Sprintf("%v", param.Default); value != "" {
+				return value
+			}
+		}
+		return SampleStringValue
+	}
+}
+
+// matrixVariants returns the values of a parameter besides its default
+func matrixVariants(param Parameter, defaultValue interface{}) []interface{} {
+	switch param.Type {
+	case "boolean":
+		return []interface{}{!defaultValue.(bool)}
+	case "select":
+		var variants []interface{}
+		for _, option := range param.Options {
+			if option != defaultValue {
+				variants = append(variants, option)
+			}
+		}
+		return variants
+	case "multiselect":
+		return []interface{}{[]string{}, append([]string(nil), param.Options...)}
+	default:
+		return nil
+	}
+}
+
+// visibleValues drops the parameters whose condition does not hold for the
+// other values, as if the user had never been asked for them
+func visibleValues(manifest *TemplateManifest, values, seed map[string]interface{}) map[string]interface{} {
+	processor := NewParameterProcessor(manifest)
+	visible := make(map[string]interface{}, len(values)+len(seed))
+	for name, value := range seed {
+		processor.SetValue(name, value)
+		visible[name] = value
+	}
+	for _, param := range manifest.Parameters {
+		if !processor.shouldShowParameter(param) {
+			continue
+		}
+		processor.SetValue(param.Name, values[param.Name])
+		visible[param.Name] = values[param.Name]
+	}
+	return visible
+}
+
+// matrixKey returns a stable representation of a combination for deduplication
+func matrixKey(values map[string]interface{}) string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var key strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&key, "%s=%v;", name, formatMatrixValue(values[name]))
+	}
+	return key.String()
+}
+
+// formatMatrixValue formats multiselect values the way --params accepts them
+func formatMatrixValue(value interface{}) string {
+	if values, ok := value.([]string); ok {
+		return "[" + strings.Join(values, ",") + "]"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// RenderTemplate scaffolds a template into an in-memory file system under
+// destDir and applies its conditional file deletions. Post-scaffold commands
+// are not run.
+func RenderTemplate(templateFS fs.FS, templateName string, values map[string]interface{}, destDir string) (*filesystem.MemFS, error) {
+	manifest, err := LoadTemplateManifest(templateFS, templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	output := filesystem.NewMemFS()
+	processor := NewTemplateProcessor(manifest, values, false)
+	processor.SetFileSystem(output)
+	processor.progress.SetOutput(io.Discard)
+	if err := processor.ScaffoldProject(templateFS, templateName, destDir); err != nil {
+		return nil, err
+	}
+	if manifest.PostScaffold != nil {
+		if err := processor.executeFileDeletions(destDir); err != nil {
+			return nil, err
+		}
+	}
+	return output, nil
+}
@@ -0,0 +1,93 @@
+package templating
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestParameterMatrix(t *testing.T) {
+	manifest := &TemplateManifest{
+		Name:        "matrix",
+		Description: "A template with every parameter type",
+		Parameters: []Parameter{
+			{Name: "ServiceName", Prompt: "Name?", Type: "string"},
+			{Name: "IncludeTesting", Prompt: "Testing?", Type: "boolean", Default: true},
+			{Name: "TestingFramework", Prompt: "Framework?", Type: "select", Options: []string{"Jest", "Vitest"}, Default: "Jest", Condition: "IncludeTesting == true"},
+			{Name: "Features", Prompt: "Features?", Type: "multiselect", Options: []string{"auth", "cache"}},
+		},
+	}
+
+	matrix := ParameterMatrix(manifest, map[string]interface{}{"ProjectName": "demo"})
+
+	var names []string
+	for _, c := range matrix {
+		names = append(names, c.Name)
+	}
+	// Features=[] is the default and is only rendered once
+	want := "defaults,IncludeTesting=false,TestingFramework=Vitest,Features=[auth,cache]"
+	if got := strings.Join(names, ","); got != want {
+		t.Fatalf("ParameterMatrix() cases = %s, want %s", got, want)
+	}
+
+	defaults := matrix[0].Values
+	if defaults["ServiceName"] != SampleStringValue || defaults["ProjectName"] != "demo" {
+		t.Errorf("unexpected default values: %v", defaults)
+	}
+	if _, exists := matrix[1].Values["TestingFramework"]; exists {
+		t.Error("expected TestingFramework to be hidden when IncludeTesting is false")
+	}
+}
+
+func TestCheckRenderedFiles(t *testing.T) {
+	output := filesystem.NewMemFS()
+	if err := output.MkdirAll("svc", 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"svc/package.json":  `{"name": "svc",}`,
+		"svc/tsconfig.json": "{\n  // comments are allowed\n}",
+		"svc/config.yaml":   "key: [unclosed",
+		"svc/main.go":       "package main\n\nfunc main() {\n",
+		"svc/Dockerfile":    "FROM node:18 AS base\nENV A=1 \\\n    # comment\n    B=2\nRUN npm ci\n",
+		"svc/README.md":     "{ not checked",
+	}
+	for name, content := range files {
+		if err := output.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	problems := CheckRenderedFiles(output, "svc")
+	var got []string
+	for _, problem := range problems {
+		got = append(got, strings.SplitN(problem.Error(), ":", 2)[0])
+	}
+	want := "config.yaml,main.go,package.json"
+	if strings.Join(got, ",") != want {
+		t.Errorf("CheckRenderedFiles() flagged %v, want %s", problems, want)
+	}
+}
+
+func TestLintDockerfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		wantErr    bool
+	}{
+		{name: "valid", dockerfile: "ARG VERSION=18\nFROM node:${VERSION}\nCOPY . .\nCMD [\"node\", \"index.js\"]\n"},
+		{name: "no FROM", dockerfile: "RUN echo hi\n", wantErr: true},
+		{name: "empty", dockerfile: "# nothing\n", wantErr: true},
+		{name: "unknown instruction", dockerfile: "FROM alpine\nRUNN echo hi\n", wantErr: true},
+		{name: "FROM without image", dockerfile: "FROM\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := lintDockerfile([]byte(tt.dockerfile)); (err != nil) != tt.wantErr {
+				t.Errorf("lintDockerfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
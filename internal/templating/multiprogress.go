@@ -0,0 +1,204 @@
+package templating
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// taskState is the state of a task shown by a MultiProgress
+type taskState int
+
+const (
+	taskRunning taskState = iota
+	taskSucceeded
+	taskFailed
+)
+
+// taskEvent is a status update sent by a task reporter to the renderer
+type taskEvent struct {
+	task   string
+	state  taskState
+	status string
+	at     time.Time
+}
+
+// taskLine is the rendered state of one task
+type taskLine struct {
+	name    string
+	state   taskState
+	status  string
+	started time.Time
+	ended   time.Time
+}
+
+// MultiProgress renders the progress of tasks that run concurrently, such as
+// services scaffolded or built in parallel. Each task reports through its
+// own ProgressReporter; a single renderer goroutine consumes their updates
+// from a channel, so output from concurrent producers never interleaves.
+//
+// On a terminal every task keeps one line that is redrawn in place, like
+// docker buildx. Otherwise each status change is printed as a "[task]"
+// line. Close prints a summary of all tasks.
+type MultiProgress struct {
+	out         io.Writer
+	interactive bool
+	started     time.Time
+
+	events chan taskEvent
+	done   chan struct{}
+
+	mu     sync.RWMutex // guards closed against concurrent sends
+	closed bool
+
+	// Renderer state, only touched by the renderer goroutine until done
+	tasks    []*taskLine
+	byName   map[string]*taskLine
+	drawn    int
+	lastLine map[string]string
+}
+
+// ProgressSummary counts the tasks of a MultiProgress by outcome
+type ProgressSummary struct {
+	Succeeded int
+	Failed    int
+	Running   int // Tasks that never completed
+}
+
+// NewMultiProgress starts a renderer that writes to out. interactive
+// selects in-place redrawing for terminals.
+func NewMultiProgress(out io.Writer, interactive bool) *MultiProgress {
+	m := &MultiProgress{
+		out:         out,
+		interactive: interactive,
+		started:     time.Now(),
+		events:      make(chan taskEvent, 64),
+		done:        make(chan struct{}),
+		byName:      make(map[string]*taskLine),
+		lastLine:    make(map[string]string),
+	}
+	go m.render()
+	return m
+}
+
+// Task returns a progress reporter for the named task. Tasks are drawn in
+// the order they first report.
+func (m *MultiProgress) Task(name string) *ProgressReporter {
+	reporter := NewProgressReporter(0, false)
+	reporter.task = name
+	reporter.multi = m
+	return reporter
+}
+
+// send queues an update for the renderer. Updates sent after Close are
+// dropped.
+func (m *MultiProgress) send(event taskEvent) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return
+	}
+	m.events <- event
+}
+
+// Close waits for the pending updates to be drawn, prints a summary of all
+// tasks, and returns their outcome counts
+func (m *MultiProgress) Close() ProgressSummary {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.events)
+	}
+	m.mu.Unlock()
+	<-m.done
+
+	var summary ProgressSummary
+	for _, task := range m.tasks {
+		switch task.state {
+		case taskSucceeded:
+			summary.Succeeded++
+		case taskFailed:
+			summary.Failed++
+		default:
+			summary.Running++
+		}
+	}
+
+	width := 0
+	for _, task := range m.tasks {
+		if len(task.name) > width {
+			width = len(task.name)
+		}
+	}
+	fmt.Fprintf(m.out, "📊 %d task(s): %d succeeded, %d failed in %v\n",
+		len(m.tasks), summary.Succeeded, summary.Failed, time.Since(m.started).Round(time.Millisecond))
+	for _, task := range m.tasks {
+		end := task.ended
+		if end.IsZero() {
+			end = time.Now()
+		}
+		fmt.Fprintf(m.out, "  %s %-*s %8v  %s\n", taskIcon(task.state), width, task.name, end.Sub(task.started).Round(time.Millisecond), task.status)
+	}
+	return summary
+}
+
+// render consumes updates until the channel is closed
+func (m *MultiProgress) render() {
+	defer close(m.done)
+	for event := range m.events {
+		task, ok := m.byName[event.task]
+		if !ok {
+			task = &taskLine{name: event.task, started: event.at}
+			m.byName[event.task] = task
+			m.tasks = append(m.tasks, task)
+		}
+		task.state = event.state
+		task.status = event.status
+		if event.state != taskRunning {
+			task.ended = event.at
+		}
+
+		if m.interactive {
+			m.redraw()
+		} else {
+			m.printChange(task)
+		}
+	}
+}
+
+// redraw rewrites the block of task lines in place
+func (m *MultiProgress) redraw() {
+	var block strings.Builder
+	if m.drawn > 0 {
+		fmt.Fprintf(&block, "\033[%dA", m.drawn)
+	}
+	for _, task := range m.tasks {
+		fmt.Fprintf(&block, "\033[2K%s %s: %s\n", taskIcon(task.state), task.name, task.status)
+	}
+	m.drawn = len(m.tasks)
+	io.WriteString(m.out, block.String())
+}
+
+// printChange prints a task line when its text changed since the last one
+func (m *MultiProgress) printChange(task *taskLine) {
+	line := fmt.Sprintf("%s [%s] %s", taskIcon(task.state), task.name, task.status)
+	if m.lastLine[task.name] == line {
+		return
+	}
+	m.lastLine[task.name] = line
+	fmt.Fprintln(m.out, line)
+}
+
+// taskIcon returns the marker of a task state
+func taskIcon(state taskState) string {
+	switch state {
+	case taskSucceeded:
+		return "✅"
+	case taskFailed:
+		return "❌"
+	default:
+		return "🔄"
+	}
+}
@@ -0,0 +1,69 @@
+package templating
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMultiProgress_ConcurrentTasks(t *testing.T) {
+	var out bytes.Buffer
+	multi := NewMultiProgress(&out, false)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reporter := multi.Task(fmt.Sprintf("service-%d", i))
+			reporter.StartOperation("Scaffolding project")
+			for file := 1; file <= 20; file++ {
+				reporter.ReportFileOperation("Writing", "main.go", file, 20)
+			}
+			reporter.ReportCommandExecution("npm install", "Installing dependencies")
+			reporter.ReportCommandResult("npm install", i%4 != 0, "")
+			reporter.CompleteOperation(i%4 != 0, "")
+		}(i)
+	}
+	wg.Wait()
+	summary := multi.Close()
+
+	if summary.Succeeded != 6 || summary.Failed != 2 || summary.Running != 0 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if strings.HasPrefix(line, "📊") || strings.HasPrefix(line, "  ") {
+			continue
+		}
+		// Every line belongs to exactly one task: nothing interleaved
+		if strings.Count(line, "[service-") != 1 {
+			t.Errorf("garbled progress line: %q", line)
+		}
+	}
+	if !strings.Contains(out.String(), "📊 8 task(s): 6 succeeded, 2 failed") {
+		t.Errorf("expected a final summary, got:\n%s", out.String())
+	}
+}
+
+func TestMultiProgress_InteractiveRedraw(t *testing.T) {
+	var out bytes.Buffer
+	multi := NewMultiProgress(&out, true)
+	api, web := multi.Task("api"), multi.Task("web")
+	api.StartOperation("Scaffolding project")
+	web.StartOperation("Scaffolding project")
+	api.CompleteOperation(true, "Scaffolded")
+	multi.Close()
+
+	// The third update redraws both task lines in place
+	if !strings.Contains(out.String(), "\033[2A\033[2K✅ api: Scaffolded\n\033[2K🔄 web: Scaffolding project\n") {
+		t.Errorf("expected the task block to be redrawn, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), "1 succeeded, 0 failed") {
+		t.Errorf("expected the unfinished task to be left out of the counts, got %q", out.String())
+	}
+
+	// Updates after Close are dropped instead of panicking
+	web.CompleteOperation(true, "")
+}
@@ -78,72 +78,16 @@ func (pp *ParameterProcessor) shouldShowParameter(param Parameter) bool {
 }
 
 // evaluateCondition evaluates a condition string against current parameter values.
-// This function implements a simple condition evaluator that supports equality
-// and inequality comparisons for boolean and string values.
+// See expression.go for the syntax.
 //
 // Parameters:
 //   - condition: The condition string to evaluate (e.g., "IncludeTesting == true")
 //
 // Returns:
 //   - true if the condition is met, false otherwise
-//   - An error if the condition cannot be evaluated
+//   - An error if the condition cannot be parsed or evaluated
 func (pp *ParameterProcessor) evaluateCondition(condition string) (bool, error) {
-	// Simple condition evaluation for now
-	// In the future, this could be enhanced with a proper expression parser
-
-	condition = strings.TrimSpace(condition)
-
-	// Handle simple equality conditions like "IncludeTesting == true"
-	if strings.Contains(condition, "==") {
-		parts := strings.Split(condition, "==")
-		if len(parts) != 2 {
-			return false, fmt.Errorf("invalid condition format: %s", condition)
-		}
-
-		paramName := strings.TrimSpace(parts[0])
-		expectedValue := strings.TrimSpace(parts[1])
-
-		// Get the actual value for the parameter
-		actualValue, exists := pp.values[paramName]
-		if !exists {
-			return false, nil
-		}
-
-		// Convert expected value to appropriate type and compare
-		switch expectedValue {
-		case "true":
-			return actualValue == true, nil
-		case "false":
-			return actualValue == false, nil
-		default:
-			// String comparison
-			return fmt.Sprintf("%v", actualValue) == expectedValue, nil
-		}
-	}
-
-	// Handle inequality conditions like "TestingFramework != 'Jest'"
-	if strings.Contains(condition, "!=") {
-		parts := strings.Split(condition, "!=")
-		if len(parts) != 2 {
-			return false, fmt.Errorf("invalid condition format: %s", condition)
-		}
-
-		paramName := strings.TrimSpace(parts[0])
-		expectedValue := strings.TrimSpace(parts[1])
-
-		// Get the actual value for the parameter
-		actualValue, exists := pp.values[paramName]
-		if !exists {
-			return true, nil // If parameter doesn't exist, condition is true
-		}
-
-		// Remove quotes from expected value
-		expectedValue = strings.Trim(expectedValue, "'\"")
-
-		return fmt.Sprintf("%v", actualValue) != expectedValue, nil
-	}
-
-	return false, fmt.Errorf("unsupported condition format: %s", condition)
+	return evaluateCondition(condition, pp.values)
 }
 
 // SetValue sets a parameter value and updates the processor state.
@@ -362,3 +306,72 @@ func (pp *ParameterProcessor) IsComplete() bool {
 	}
 	return true
 }
+
+// CompleteParameterValues returns a copy of values in which every parameter
+// declared by the manifest has a value. Parameters that were not collected,
+// typically because their condition hid them, get the zero value of their
+// type: false for booleans, an empty list for multiselects, and an empty
+// string otherwise.
+//
+// Parameters:
+//   - manifest: The template manifest containing parameter definitions
+//   - values: The collected parameter values
+//
+// Returns:
+//   - A map with a value for every declared parameter
+func CompleteParameterValues(manifest *TemplateManifest, values map[string]interface{}) map[string]interface{} {
+	complete := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		complete[name] = value
+	}
+	if manifest == nil {
+		return complete
+	}
+
+	for _, param := range manifest.Parameters {
+		if _, exists := complete[param.Name]; exists {
+			continue
+		}
+		switch param.Type {
+		case "boolean":
+			complete[param.Name] = false
+		case "multiselect":
+			complete[param.Name] = []string{}
+		default:
+			complete[param.Name] = ""
+		}
+	}
+	return complete
+}
+
+// DefaultParameterValues returns a copy of values with the default of every
+// parameter that has no value yet added, as if the user had accepted every
+// default. Parameters are visited in manifest order and only take their
+// default when their condition holds, so a default can reveal, or hide, the
+// parameters after it.
+//
+// Parameters:
+//   - manifest: The template manifest containing parameter definitions
+//   - values: The values already given
+//
+// Returns:
+//   - A map with the given values and the defaults of the shown parameters
+func DefaultParameterValues(manifest *TemplateManifest, values map[string]interface{}) map[string]interface{} {
+	processor := NewParameterProcessor(manifest)
+	complete := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		complete[name] = value
+		processor.SetValue(name, value)
+	}
+
+	for _, param := range manifest.Parameters {
+		if _, exists := complete[param.Name]; exists || !processor.shouldShowParameter(param) {
+			continue
+		}
+		if value := param.DefaultValue(); value != nil {
+			complete[param.Name] = value
+			processor.SetValue(param.Name, value)
+		}
+	}
+	return complete
+}
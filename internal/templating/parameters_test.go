@@ -0,0 +1,34 @@
+package templating
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefaultParameterValues(t *testing.T) {
+	manifest := &TemplateManifest{
+		Parameters: []Parameter{
+			{Name: "ProjectName", Type: "string"},
+			{Name: "IncludeTesting", Type: "boolean", Default: true},
+			{Name: "TestingFramework", Type: "select", Options: []string{"Jest", "Vitest"}, Default: "Jest", Condition: "IncludeTesting == true"},
+			{Name: "IncludeDocker", Type: "boolean", Default: true},
+			{Name: "Features", Type: "multiselect", Options: []string{"auth", "cache"}, Default: []interface{}{"auth"}},
+			{Name: "Registry", Type: "string", Default: "ghcr.io", Condition: "IncludeDocker == true"},
+		},
+	}
+
+	got := DefaultParameterValues(manifest, map[string]interface{}{
+		"ProjectName":   "api",
+		"IncludeDocker": false,
+	})
+	want := map[string]interface{}{
+		"ProjectName":      "api",
+		"IncludeTesting":   true,
+		"TestingFramework": "Jest",
+		"IncludeDocker":    false,
+		"Features":         []string{"auth"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DefaultParameterValues() = %v, want %v", got, want)
+	}
+}
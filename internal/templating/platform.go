@@ -11,6 +11,15 @@ import (
 // PlatformUtils provides cross-platform utility functions
 type PlatformUtils struct{}
 
+// Environments om can run in that read paths differently from the Docker
+// engine they talk to
+const (
+	EnvironmentNative  = ""         // Linux or macOS
+	EnvironmentWindows = "windows"  // cmd or PowerShell on Windows
+	EnvironmentWSL     = "wsl"      // Linux in the Windows Subsystem for Linux
+	EnvironmentGitBash = "git-bash" // Git Bash, MSYS2, or Cygwin on Windows
+)
+
 // NewPlatformUtils creates a new platform utilities instance
 func NewPlatformUtils() *PlatformUtils {
 	return &PlatformUtils{}
@@ -93,6 +102,41 @@ func (pu *PlatformUtils) IsUnixLike() bool {
 	return runtime.GOOS == "linux" || runtime.GOOS == "darwin"
 }
 
+// Environment returns the environment om runs in
+func (pu *PlatformUtils) Environment() string {
+	var procVersion string
+	if runtime.GOOS == "linux" {
+		if data, err := os.ReadFile("/proc/version"); err == nil {
+			procVersion = string(data)
+		}
+	}
+	return DetectEnvironment(runtime.GOOS, os.Getenv, procVersion)
+}
+
+// IsWSL returns true if running in the Windows Subsystem for Linux
+func (pu *PlatformUtils) IsWSL() bool {
+	return pu.Environment() == EnvironmentWSL
+}
+
+// DetectEnvironment tells the environment apart from the operating system,
+// its environment variables, and the contents of /proc/version. WSL runs a
+// Microsoft kernel and sets WSL_DISTRO_NAME; Git Bash and MSYS2 set MSYSTEM.
+func DetectEnvironment(goos string, getenv func(string) string, procVersion string) string {
+	switch goos {
+	case "windows":
+		if getenv("MSYSTEM") != "" || strings.HasPrefix(getenv("OSTYPE"), "cygwin") {
+			return EnvironmentGitBash
+		}
+		return EnvironmentWindows
+	case "linux":
+		if getenv("WSL_DISTRO_NAME") != "" || getenv("WSL_INTEROP") != "" ||
+			strings.Contains(strings.ToLower(procVersion), "microsoft") {
+			return EnvironmentWSL
+		}
+	}
+	return EnvironmentNative
+}
+
 // GetShellCommand returns the appropriate shell and arguments for the current platform
 func (pu *PlatformUtils) GetShellCommand(command string) (string, []string) {
 	switch runtime.GOOS {
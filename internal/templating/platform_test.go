@@ -0,0 +1,29 @@
+package templating
+
+import "testing"
+
+func TestDetectEnvironment(t *testing.T) {
+	tests := []struct {
+		name        string
+		goos        string
+		env         map[string]string
+		procVersion string
+		expected    string
+	}{
+		{name: "linux", goos: "linux", procVersion: "Linux version 6.8.0-45-generic", expected: EnvironmentNative},
+		{name: "macos", goos: "darwin", expected: EnvironmentNative},
+		{name: "wsl by kernel", goos: "linux", procVersion: "Linux version 5.15.153.1-microsoft-standard-WSL2", expected: EnvironmentWSL},
+		{name: "wsl by variable", goos: "linux", env: map[string]string{"WSL_DISTRO_NAME": "Ubuntu"}, expected: EnvironmentWSL},
+		{name: "windows", goos: "windows", expected: EnvironmentWindows},
+		{name: "git bash", goos: "windows", env: map[string]string{"MSYSTEM": "MINGW64"}, expected: EnvironmentGitBash},
+		{name: "cygwin", goos: "windows", env: map[string]string{"OSTYPE": "cygwin"}, expected: EnvironmentGitBash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := DetectEnvironment(tt.goos, getenv, tt.procVersion); got != tt.expected {
+				t.Errorf("DetectEnvironment() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -12,15 +12,29 @@ import (
 	"path/filepath"
 	"strings"
 	"text/template"
+
+	"github.com/jashkahar/open-workbench-platform/internal/debuglog"
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/slug"
 )
 
+// TemplateFileSuffix is stripped from the names of template files when they
+// are scaffolded. Templates use it for files the Go toolchain would otherwise
+// pick up from the embedded templates directory, such as .go sources and
+// go.mod, which would be compiled or split off into a separate module.
+const TemplateFileSuffix = ".tmpl"
+
 // TemplateProcessor handles dynamic template processing with conditional logic.
 // This struct manages the complete template processing workflow, including
 // file generation, variable substitution, and post-scaffolding actions.
 type TemplateProcessor struct {
 	manifest *TemplateManifest      // The template manifest containing configuration
 	values   map[string]interface{} // Collected parameter values for substitution
+	provided map[string]bool        // Parameters the user actually supplied
 	progress *ProgressReporter      // Progress reporter for user feedback
+	fs       filesystem.FS          // File system scaffolded files are written to
+	env      []string               // Extra environment of post-scaffold commands
+	failed   []string               // Post-scaffold commands that failed
 }
 
 // NewTemplateProcessor creates a new template processor.
@@ -32,16 +46,62 @@ type TemplateProcessor struct {
 //   - values: A map of parameter names to their collected values
 //   - verbose: Whether to show detailed progress information
 //
+// Parameters the user was never asked for, because their condition hid them,
+// are filled with the zero value of their type so templates can reference
+// every declared parameter.
+//
 // Returns:
 //   - A pointer to the initialized TemplateProcessor
 func NewTemplateProcessor(manifest *TemplateManifest, values map[string]interface{}, verbose bool) *TemplateProcessor {
+	provided := make(map[string]bool, len(values))
+	for name := range values {
+		provided[name] = true
+	}
+
 	return &TemplateProcessor{
 		manifest: manifest,
-		values:   values,
+		values:   CompleteParameterValues(manifest, values),
+		provided: provided,
 		progress: NewProgressReporter(0, verbose), // Will be updated with actual steps
+		fs:       filesystem.NewOSFS(),
 	}
 }
 
+// SetFileSystem sets the file system that scaffolded files are written to.
+// By default the processor writes to the real file system; tests and preview
+// modes can supply an in-memory or dry-run implementation instead.
+//
+// Parameters:
+//   - fsys: The destination file system
+func (tp *TemplateProcessor) SetFileSystem(fsys filesystem.FS) {
+	tp.fs = fsys
+}
+
+// SetProgressReporter replaces the processor's progress reporter, e.g. with
+// a MultiProgress task reporter when several templates are scaffolded at once
+func (tp *TemplateProcessor) SetProgressReporter(reporter *ProgressReporter) {
+	tp.progress = reporter
+}
+
+// SetCommandEnvironment adds KEY=value pairs to the environment post-scaffold
+// commands run with, e.g. to share package manager caches between services
+// installed at once. Pairs added later take precedence.
+func (tp *TemplateProcessor) SetCommandEnvironment(env []string) {
+	tp.env = append(tp.env, env...)
+}
+
+// HasPostScaffoldCommands reports whether the template declares
+// post-scaffold commands
+func (tp *TemplateProcessor) HasPostScaffoldCommands() bool {
+	return tp.manifest.PostScaffold != nil && len(tp.manifest.PostScaffold.Commands) > 0
+}
+
+// FailedCommands returns the post-scaffold commands that failed. Failing
+// commands do not stop scaffolding; callers report them.
+func (tp *TemplateProcessor) FailedCommands() []string {
+	return append([]string(nil), tp.failed...)
+}
+
 // ProcessTemplate processes a template string with the provided values.
 // This function applies Go template processing to a string, substituting
 // variables and executing conditional logic based on the collected parameters.
@@ -49,12 +109,15 @@ func NewTemplateProcessor(manifest *TemplateManifest, values map[string]interfac
 // Parameters:
 //   - content: The template string to process
 //
+// References to values that do not exist are errors rather than "<no value>";
+// use hasParam or paramOr for values that may be absent.
+//
 // Returns:
 //   - The processed string with variables substituted
 //   - An error if template processing fails
 func (tp *TemplateProcessor) ProcessTemplate(content string) (string, error) {
 	// Create a template with custom functions for enhanced processing
-	tmpl, err := template.New("content").Funcs(tp.getTemplateFunctions()).Parse(content)
+	tmpl, err := template.New("content").Option("missingkey=error").Funcs(tp.getTemplateFunctions()).Parse(content)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
@@ -133,9 +196,55 @@ func (tp *TemplateProcessor) getTemplateFunctions() template.FuncMap {
 			return first + rest
 		},
 		"trim": strings.TrimSpace,
+		// Text the user typed as an identifier, e.g. for package names
+		"slug": slug.Make,
+		// Whether the user supplied a parameter, as opposed to it being
+		// hidden by its condition or never declared
+		"hasParam": func(name string) bool {
+			return tp.provided[name]
+		},
+		// A supplied, non-empty parameter value, or the fallback
+		"paramOr": func(name string, fallback interface{}) interface{} {
+			if !tp.provided[name] || isZeroValue(tp.values[name]) {
+				return fallback
+			}
+			return tp.values[name]
+		},
+		// Safe map access that yields an empty string for missing keys
+		"get": func(m interface{}, key string) interface{} {
+			switch typed := m.(type) {
+			case map[string]interface{}:
+				if value, ok := typed[key]; ok {
+					return value
+				}
+			case map[string]string:
+				if value, ok := typed[key]; ok {
+					return value
+				}
+			}
+			return ""
+		},
 	}
 }
 
+// isZeroValue reports whether a parameter value is empty: nil, "", false, or
+// an empty list
+func isZeroValue(value interface{}) bool {
+	switch typed := value.(type) {
+	case nil:
+		return true
+	case string:
+		return typed == ""
+	case bool:
+		return !typed
+	case []string:
+		return len(typed) == 0
+	case []interface{}:
+		return len(typed) == 0
+	}
+	return false
+}
+
 // ScaffoldProject scaffolds a complete project from a template.
 // This function performs the main scaffolding operation, copying template files
 // and processing them with parameter substitution to create a new project.
@@ -154,7 +263,7 @@ func (tp *TemplateProcessor) ScaffoldProject(templateFS fs.FS, templateName, des
 	tp.progress.StartOperation("Scaffolding project")
 
 	// Create the destination directory if it doesn't exist
-	if err := os.MkdirAll(destDir, 0755); err != nil {
+	if err := tp.fs.MkdirAll(destDir, 0755); err != nil {
 		return NewFileSystemError("create destination directory", destDir, err)
 	}
 
@@ -175,6 +284,18 @@ func (tp *TemplateProcessor) ScaffoldProject(templateFS fs.FS, templateName, des
 			return nil // Skip the root directory
 		}
 
+		// Skip the files and directories whose condition does not hold
+		include, err := tp.manifest.Files.Includes(relPath, tp.values)
+		if err != nil {
+			return NewTemplateProcessingError(templateName, fmt.Sprintf("Failed to evaluate the condition of '%s'", strings.TrimPrefix(relPath, "/")), err)
+		}
+		if !include {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
 		// Process the filename template
 		processedFileName, err := tp.ProcessFileName(d.Name())
 		if err != nil {
@@ -182,9 +303,16 @@ func (tp *TemplateProcessor) ScaffoldProject(templateFS fs.FS, templateName, des
 		}
 
 		// If filename is empty after processing, skip this file/directory
+		// and, for a directory, everything in it
 		if processedFileName == "" {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
 			return nil
 		}
+		if !d.IsDir() {
+			processedFileName = strings.TrimSuffix(processedFileName, TemplateFileSuffix)
+		}
 
 		// Calculate the destination path
 		destPath := filepath.Join(destDir, relPath)
@@ -192,12 +320,13 @@ func (tp *TemplateProcessor) ScaffoldProject(templateFS fs.FS, templateName, des
 
 		if d.IsDir() {
 			// Create directory with appropriate permissions
-			if err := os.MkdirAll(destPath, 0755); err != nil {
+			if err := tp.fs.MkdirAll(destPath, 0755); err != nil {
 				return NewFileSystemError("create directory", destPath, err)
 			}
 		} else {
 			// Process and write the file
-			if err := tp.processAndWriteFile(templateFS, path, destPath); err != nil {
+			raw := tp.manifest.RawFiles.Matches(relPath)
+			if err := tp.processAndWriteFile(templateFS, path, destPath, raw); err != nil {
 				return err
 			}
 		}
@@ -205,18 +334,48 @@ func (tp *TemplateProcessor) ScaffoldProject(templateFS fs.FS, templateName, des
 	})
 }
 
+// TemplateFiles lists the files of a template, relative to the directory it
+// is scaffolded into and without the .tmpl suffix, along with their combined
+// size. File names are listed before their templates are rendered.
+func TemplateFiles(templateFS fs.FS, templateName string) ([]string, int64, error) {
+	sourceDir := fmt.Sprintf("templates/%s", templateName)
+	var files []string
+	var size int64
+	err := fs.WalkDir(templateFS, sourceDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() == "template.json" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		files = append(files, filepath.FromSlash(strings.TrimSuffix(strings.TrimPrefix(path, sourceDir+"/"), TemplateFileSuffix)))
+		return nil
+	})
+	if err != nil {
+		return nil, 0, NewFileSystemError("list template files", sourceDir, err)
+	}
+	return files, size, nil
+}
+
 // processAndWriteFile processes a single file and writes it to the destination.
 // This function reads a template file, processes it with parameter substitution,
-// and writes the result to the destination location.
+// and writes the result to the destination location. Raw files and binary
+// files, such as images and fonts, are copied byte-for-byte instead.
 //
 // Parameters:
 //   - templateFS: The embedded filesystem containing the source file
 //   - sourcePath: The path to the source file in the template
 //   - destPath: The destination path for the processed file
+//   - raw: Whether the template lists the file in rawFiles
 //
 // Returns:
 //   - An error if file processing fails
-func (tp *TemplateProcessor) processAndWriteFile(templateFS fs.FS, sourcePath, destPath string) error {
+func (tp *TemplateProcessor) processAndWriteFile(templateFS fs.FS, sourcePath, destPath string, raw bool) error {
 	// Read the source file content
 	content, err := fs.ReadFile(templateFS, sourcePath)
 	if err != nil {
@@ -224,25 +383,39 @@ func (tp *TemplateProcessor) processAndWriteFile(templateFS fs.FS, sourcePath, d
 	}
 
 	// Process the file content with template substitution
-	processedContent, err := tp.ProcessTemplate(string(content))
-	if err != nil {
-		return NewTemplateProcessingError("", fmt.Sprintf("Failed to process file content: %s", sourcePath), err)
+	processedContent := content
+	if !raw && !isBinary(content) {
+		processed, err := tp.ProcessTemplate(string(content))
+		if err != nil {
+			return NewTemplateProcessingError("", fmt.Sprintf("Failed to process file content: %s", sourcePath), err)
+		}
+		processedContent = []byte(processed)
 	}
 
 	// Ensure the destination directory exists
 	destDir := filepath.Dir(destPath)
-	if err := os.MkdirAll(destDir, 0755); err != nil {
+	if err := tp.fs.MkdirAll(destDir, 0755); err != nil {
 		return NewFileSystemError("create destination directory", destDir, err)
 	}
 
 	// Write the processed content to the destination file
-	if err := os.WriteFile(destPath, []byte(processedContent), 0644); err != nil {
+	if err := tp.fs.WriteFile(destPath, processedContent, 0644); err != nil {
 		return NewFileSystemError("write destination file", destPath, err)
 	}
 
 	return nil
 }
 
+// binarySniffLength is how much of a file isBinary looks at
+const binarySniffLength = 8000
+
+// isBinary reports whether content is binary rather than text: like git, it
+// looks for a NUL byte in the first 8000 bytes, which text files in any
+// ASCII-compatible encoding never contain
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(len(content), binarySniffLength)], 0) >= 0
+}
+
 // ExecutePostScaffoldActions executes post-scaffolding actions.
 // This function performs cleanup and setup actions after the main scaffolding
 // is complete, such as file deletion and command execution.
@@ -253,6 +426,23 @@ func (tp *TemplateProcessor) processAndWriteFile(templateFS fs.FS, sourcePath, d
 // Returns:
 //   - An error if post-scaffolding actions fail
 func (tp *TemplateProcessor) ExecutePostScaffoldActions(projectDir string) error {
+	if err := tp.ExecutePostScaffoldDeletions(projectDir); err != nil {
+		return err
+	}
+	return tp.ExecutePostScaffoldCommands(projectDir)
+}
+
+// ExecutePostScaffoldDeletions executes the file deletions of the
+// post-scaffolding actions. Together with ExecutePostScaffoldCommands it
+// lets callers scaffold several projects first and run their commands,
+// such as dependency installs, later and concurrently.
+//
+// Parameters:
+//   - projectDir: The directory containing the scaffolded project
+//
+// Returns:
+//   - An error if a file cannot be deleted
+func (tp *TemplateProcessor) ExecutePostScaffoldDeletions(projectDir string) error {
 	// Skip if no post-scaffolding actions are defined
 	if tp.manifest.PostScaffold == nil {
 		return nil
@@ -265,12 +455,28 @@ func (tp *TemplateProcessor) ExecutePostScaffoldActions(projectDir string) error
 	if err := tp.executeFileDeletions(projectDir); err != nil {
 		return NewTemplateProcessingError("", "Failed to execute file deletions", err)
 	}
+	return nil
+}
+
+// ExecutePostScaffoldCommands executes the commands of the post-scaffolding
+// actions. A failing command is reported and skipped, and listed by
+// FailedCommands afterwards.
+//
+// Parameters:
+//   - projectDir: The directory containing the scaffolded project
+//
+// Returns:
+//   - An error if the commands cannot be run at all
+func (tp *TemplateProcessor) ExecutePostScaffoldCommands(projectDir string) error {
+	// Skip if no post-scaffolding actions are defined
+	if tp.manifest.PostScaffold == nil {
+		return nil
+	}
 
 	// Execute commands based on conditions
 	if err := tp.executeCommands(projectDir); err != nil {
 		return NewTemplateProcessingError("", "Failed to execute commands", err)
 	}
-
 	return nil
 }
 
@@ -303,7 +509,7 @@ func (tp *TemplateProcessor) executeFileDeletions(projectDir string) error {
 		// Delete the file if the condition is met
 		if shouldDelete {
 			filePath := filepath.Join(projectDir, fileAction.Path)
-			if err := os.RemoveAll(filePath); err != nil {
+			if err := tp.fs.RemoveAll(filePath); err != nil {
 				return NewFileSystemError("delete file", filePath, err)
 			}
 			tp.progress.CompleteStep(fmt.Sprintf("Deleted file: %s", fileAction.Path), true, "")
@@ -328,6 +534,12 @@ func (tp *TemplateProcessor) executeCommands(projectDir string) error {
 		return nil
 	}
 
+	// Commands need real files on disk to operate on
+	if !filesystem.IsOS(tp.fs) {
+		fmt.Printf("[INFO] Skipping %d post-scaffold command(s): files were not written to disk.\n", len(tp.manifest.PostScaffold.Commands))
+		return nil
+	}
+
 	// Process each command action
 	for i, commandAction := range tp.manifest.PostScaffold.Commands {
 		shouldExecute := true
@@ -364,7 +576,11 @@ func (tp *TemplateProcessor) executeCommands(projectDir string) error {
 					err = tp.tryPipFallback(commandAction, projectDir)
 				}
 				if err != nil {
-					fmt.Printf("[WARN] Post-scaffold command '%s' failed: %v. Skipping.\n", commandAction.Command, err)
+					tp.failed = append(tp.failed, commandAction.Command)
+					// Task reporters show the failure in their summary instead
+					if tp.progress.multi == nil {
+						fmt.Printf("[WARN] Post-scaffold command '%s' failed: %v. Skipping.\n", commandAction.Command, err)
+					}
 					continue // Do not abort the whole process
 				}
 			}
@@ -375,72 +591,16 @@ func (tp *TemplateProcessor) executeCommands(projectDir string) error {
 }
 
 // evaluateCondition evaluates a condition string against current values.
-// This function implements a simple condition evaluator that supports equality
-// and inequality comparisons for boolean and string values.
+// See expression.go for the syntax.
 //
 // Parameters:
 //   - condition: The condition string to evaluate (e.g., "IncludeTesting == true")
 //
 // Returns:
 //   - true if the condition is met, false otherwise
-//   - An error if the condition cannot be evaluated
+//   - An error if the condition cannot be parsed or evaluated
 func (tp *TemplateProcessor) evaluateCondition(condition string) (bool, error) {
-	// This is a simplified condition evaluator
-	// In a production system, you might want to use a proper expression parser
-
-	condition = strings.TrimSpace(condition)
-
-	// Handle simple equality conditions like "IncludeTesting == true"
-	if strings.Contains(condition, "==") {
-		parts := strings.Split(condition, "==")
-		if len(parts) != 2 {
-			return false, fmt.Errorf("invalid condition format: %s", condition)
-		}
-
-		paramName := strings.TrimSpace(parts[0])
-		expectedValue := strings.TrimSpace(parts[1])
-
-		// Get the actual value for the parameter
-		actualValue, exists := tp.values[paramName]
-		if !exists {
-			return false, nil
-		}
-
-		// Convert expected value to appropriate type and compare
-		switch expectedValue {
-		case "true":
-			return actualValue == true, nil
-		case "false":
-			return actualValue == false, nil
-		default:
-			// String comparison
-			return fmt.Sprintf("%v", actualValue) == expectedValue, nil
-		}
-	}
-
-	// Handle inequality conditions like "TestingFramework != 'Jest'"
-	if strings.Contains(condition, "!=") {
-		parts := strings.Split(condition, "!=")
-		if len(parts) != 2 {
-			return false, fmt.Errorf("invalid condition format: %s", condition)
-		}
-
-		paramName := strings.TrimSpace(parts[0])
-		expectedValue := strings.TrimSpace(parts[1])
-
-		// Get the actual value for the parameter
-		actualValue, exists := tp.values[paramName]
-		if !exists {
-			return true, nil // If parameter doesn't exist, condition is true
-		}
-
-		// Remove quotes from expected value
-		expectedValue = strings.Trim(expectedValue, "'\"")
-
-		return fmt.Sprintf("%v", actualValue) != expectedValue, nil
-	}
-
-	return false, fmt.Errorf("unsupported condition format: %s", condition)
+	return evaluateCondition(condition, tp.values)
 }
 
 // executeCommand executes a single command.
@@ -469,13 +629,15 @@ func (tp *TemplateProcessor) executeCommand(commandAction CommandAction, project
 	cmd.Dir = projectDir
 
 	// Set environment variables for better compatibility
-	cmd.Env = append(os.Environ(),
+	cmd.Env = tp.commandEnvironment(
 		"CI=true", // Prevent interactive prompts
 		"NODE_ENV=development",
 	)
 
 	// Capture output for reporting
+	debuglog.Printf("running post-scaffold command in %s: %s", projectDir, commandAction.Command)
 	output, err := cmd.CombinedOutput()
+	debuglog.Printf("post-scaffold command output (err=%v):\n%s", err, output)
 
 	if err != nil {
 		// Try to provide more helpful error messages
@@ -489,6 +651,13 @@ func (tp *TemplateProcessor) executeCommand(commandAction CommandAction, project
 	return nil
 }
 
+// commandEnvironment returns the environment of a post-scaffold command: the
+// user's, the variables set with SetCommandEnvironment, and vars
+func (tp *TemplateProcessor) commandEnvironment(vars ...string) []string {
+	env := append(os.Environ(), tp.env...)
+	return append(env, vars...)
+}
+
 // enhanceErrorMessage provides more helpful error messages for common issues
 func (tp *TemplateProcessor) enhanceErrorMessage(command, output string, err error) string {
 	outputLower := strings.ToLower(output)
@@ -535,7 +704,7 @@ func (tp *TemplateProcessor) tryNpmFallback(commandAction CommandAction, project
 	cmd = exec.Command(shell, args...)
 
 	cmd.Dir = projectDir
-	cmd.Env = append(os.Environ(), "CI=true", "NODE_ENV=development")
+	cmd.Env = tp.commandEnvironment("CI=true", "NODE_ENV=development")
 
 	output, err := cmd.CombinedOutput()
 	if err == nil {
@@ -551,7 +720,7 @@ func (tp *TemplateProcessor) tryNpmFallback(commandAction CommandAction, project
 	cmd = exec.Command(shell, args...)
 
 	cmd.Dir = projectDir
-	cmd.Env = append(os.Environ(), "CI=true", "NODE_ENV=development")
+	cmd.Env = tp.commandEnvironment("CI=true", "NODE_ENV=development")
 
 	output, err = cmd.CombinedOutput()
 	if err == nil {
@@ -575,7 +744,7 @@ func (tp *TemplateProcessor) tryPipFallback(commandAction CommandAction, project
 	cmd = exec.Command(shell, args...)
 
 	cmd.Dir = projectDir
-	cmd.Env = append(os.Environ(), "CI=true")
+	cmd.Env = tp.commandEnvironment("CI=true")
 
 	output, err := cmd.CombinedOutput()
 	if err == nil {
@@ -591,7 +760,7 @@ func (tp *TemplateProcessor) tryPipFallback(commandAction CommandAction, project
 	cmd = exec.Command(shell, args...)
 
 	cmd.Dir = projectDir
-	cmd.Env = append(os.Environ(), "CI=true")
+	cmd.Env = tp.commandEnvironment("CI=true")
 
 	output, err = cmd.CombinedOutput()
 	if err == nil {
@@ -607,7 +776,7 @@ func (tp *TemplateProcessor) tryPipFallback(commandAction CommandAction, project
 	cmd = exec.Command(shell, args...)
 
 	cmd.Dir = projectDir
-	cmd.Env = append(os.Environ(), "CI=true")
+	cmd.Env = tp.commandEnvironment("CI=true")
 
 	output, err = cmd.CombinedOutput()
 	if err == nil {
@@ -0,0 +1,211 @@
+package templating
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestScaffoldProject_StripsTemplateSuffix(t *testing.T) {
+	templateFS := fstest.MapFS{
+		"templates/demo/template.json":     {Data: []byte(`{"name": "demo"}`)},
+		"templates/demo/go.mod.tmpl":       {Data: []byte("module {{.ModulePath}}\n")},
+		"templates/demo/cmd/main.go.tmpl":  {Data: []byte("package main\n")},
+		"templates/demo/README.md":         {Data: []byte("# {{.ProjectName}}\n")},
+		"templates/demo/docs.tmpl/page.md": {Data: []byte("kept\n")},
+	}
+
+	memFS := filesystem.NewMemFS()
+	processor := NewTemplateProcessor(&TemplateManifest{Name: "demo"}, map[string]interface{}{
+		"ProjectName": "api",
+		"ModulePath":  "example.com/api",
+	}, false)
+	processor.SetFileSystem(memFS)
+
+	if err := processor.ScaffoldProject(templateFS, "demo", "out"); err != nil {
+		t.Fatalf("ScaffoldProject failed: %v", err)
+	}
+
+	expected := map[string]string{
+		filepath.Join("out", "go.mod"):               "module example.com/api\n",
+		filepath.Join("out", "cmd", "main.go"):       "package main\n",
+		filepath.Join("out", "README.md"):            "# api\n",
+		filepath.Join("out", "docs.tmpl", "page.md"): "kept\n",
+	}
+	for path, content := range expected {
+		data, err := memFS.ReadFile(path)
+		if err != nil {
+			t.Errorf("expected %s to be written: %v", path, err)
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("unexpected content of %s: %q", path, data)
+		}
+	}
+	if filesystem.Exists(memFS, filepath.Join("out", "go.mod.tmpl")) {
+		t.Error("expected the .tmpl suffix to be stripped")
+	}
+}
+
+func TestScaffoldProject_ConditionalFiles(t *testing.T) {
+	templateFS := fstest.MapFS{
+		"templates/demo/template.json":                           {Data: []byte(`{"name": "demo"}`)},
+		"templates/demo/README.md":                               {Data: []byte("readme\n")},
+		"templates/demo/tests/app.test.js":                       {Data: []byte("test\n")},
+		"templates/demo/tailwind/tailwind.config.js":             {Data: []byte("config\n")},
+		"templates/demo/main.go.tmpl":                            {Data: []byte("package main\n")},
+		"templates/demo/{{ if .IncludeDocs }}docs{{ end }}/a.md": {Data: []byte("docs\n")},
+	}
+	manifest := &TemplateManifest{
+		Name: "demo",
+		Files: ConditionalFiles{
+			"tests/":   "IncludeTesting == true",
+			"tailwind": "Styling == 'Tailwind'",
+			"main.go":  "Language == 'Go'",
+		},
+	}
+
+	memFS := filesystem.NewMemFS()
+	processor := NewTemplateProcessor(manifest, map[string]interface{}{
+		"IncludeTesting": false,
+		"IncludeDocs":    false,
+		"Styling":        "Tailwind",
+		"Language":       "Go",
+	}, false)
+	processor.SetFileSystem(memFS)
+	if err := processor.ScaffoldProject(templateFS, "demo", "out"); err != nil {
+		t.Fatalf("ScaffoldProject failed: %v", err)
+	}
+
+	for _, path := range []string{"README.md", filepath.Join("tailwind", "tailwind.config.js"), "main.go"} {
+		if !filesystem.Exists(memFS, filepath.Join("out", path)) {
+			t.Errorf("expected %s to be scaffolded", path)
+		}
+	}
+	for _, path := range []string{"tests", "docs", "{{ if .IncludeDocs }}docs{{ end }}"} {
+		if filesystem.Exists(memFS, filepath.Join("out", path)) {
+			t.Errorf("expected %s to be skipped with its files", path)
+		}
+	}
+}
+
+func TestScaffoldProject_RawAndBinaryFiles(t *testing.T) {
+	// A PNG header holds a NUL byte and bytes that are not valid UTF-8, and
+	// {{ in a font or an icon would fail to render
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR{{ .ProjectName }}")
+	templateFS := fstest.MapFS{
+		"templates/demo/template.json":           {Data: []byte(`{"name": "demo"}`)},
+		"templates/demo/README.md":               {Data: []byte("# {{ .ProjectName }}\n")},
+		"templates/demo/public/logo.png":         {Data: png},
+		"templates/demo/charts/api/values.yaml":  {Data: []byte("image: {{ .Values.image }}\n")},
+		"templates/demo/src/email.hbs":           {Data: []byte("Hello {{name}}\n")},
+		"templates/demo/src/{{.ProjectName}}.js": {Data: []byte("// {{ .ProjectName }}\n")},
+	}
+	manifest := &TemplateManifest{Name: "demo", RawFiles: RawFiles{"charts", "*.hbs"}}
+
+	memFS := filesystem.NewMemFS()
+	processor := NewTemplateProcessor(manifest, map[string]interface{}{"ProjectName": "shop"}, false)
+	processor.SetFileSystem(memFS)
+	if err := processor.ScaffoldProject(templateFS, "demo", "out"); err != nil {
+		t.Fatalf("ScaffoldProject failed: %v", err)
+	}
+
+	for path, want := range map[string]string{
+		"README.md":                                   "# shop\n",
+		filepath.Join("public", "logo.png"):           string(png),
+		filepath.Join("charts", "api", "values.yaml"): "image: {{ .Values.image }}\n",
+		filepath.Join("src", "email.hbs"):             "Hello {{name}}\n",
+		filepath.Join("src", "shop.js"):               "// shop\n",
+	} {
+		got, err := memFS.ReadFile(filepath.Join("out", path))
+		if err != nil {
+			t.Errorf("expected %s to be scaffolded: %v", path, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestProcessTemplate_HiddenAndMissingParameters(t *testing.T) {
+	manifest := &TemplateManifest{
+		Name: "demo",
+		Parameters: []Parameter{
+			{Name: "IncludeTesting", Type: "boolean"},
+			{Name: "TestingFramework", Type: "select", Options: []string{"Jest", "Vitest"}, Condition: "IncludeTesting == true"},
+			{Name: "Features", Type: "multiselect", Options: []string{"API", "Auth"}},
+		},
+	}
+	processor := NewTemplateProcessor(manifest, map[string]interface{}{
+		"ProjectName":    "api",
+		"IncludeTesting": false,
+	}, false)
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{`{{if .TestingFramework}}{{.TestingFramework}}{{else}}none{{end}}`, "none"},
+		{`{{len .Features}}`, "0"},
+		{`{{hasParam "IncludeTesting"}} {{hasParam "TestingFramework"}}`, "true false"},
+		{`{{paramOr "TestingFramework" "Jest"}} {{paramOr "ProjectName" "app"}}`, "Jest api"},
+		{`[{{get .Labels "team"}}]`, "[]"},
+		{`{{slug .Owner}}`, "jose-garcia"},
+	}
+	processor.values["Labels"] = map[string]string{"owner": "me"}
+	processor.values["Owner"] = "José García"
+
+	for _, tt := range tests {
+		got, err := processor.ProcessTemplate(tt.template)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.template, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.template, tt.want, got)
+		}
+	}
+
+	if _, err := processor.ProcessTemplate("{{.Undeclared}}"); err == nil {
+		t.Error("expected an error for an undeclared parameter")
+	}
+}
+
+func TestExecutePostScaffoldCommands_EnvironmentAndFailures(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands use a POSIX shell")
+	}
+	dir := t.TempDir()
+	manifest := &TemplateManifest{Name: "demo", PostScaffold: &PostScaffold{Commands: []CommandAction{
+		{Command: `printf %s "$OM_SHARED" > env.txt`, Description: "Write the environment"},
+		{Command: "exit 3", Description: "Fail"},
+		{Command: "touch skipped.txt", Description: "Skipped", Condition: "InstallDeps == true"},
+	}}}
+	processor := NewTemplateProcessor(manifest, map[string]interface{}{"InstallDeps": false}, false)
+	processor.progress.SetOutput(io.Discard)
+	processor.SetCommandEnvironment([]string{"OM_SHARED=yes"})
+
+	if !processor.HasPostScaffoldCommands() {
+		t.Fatal("expected the template to have post-scaffold commands")
+	}
+	if err := processor.ExecutePostScaffoldCommands(dir); err != nil {
+		t.Fatalf("ExecutePostScaffoldCommands failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "env.txt"))
+	if err != nil || string(data) != "yes" {
+		t.Errorf("expected the command environment to be passed, got %q (%v)", data, err)
+	}
+	if failed := processor.FailedCommands(); len(failed) != 1 || failed[0] != "exit 3" {
+		t.Errorf("FailedCommands() = %v, want [exit 3]", failed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "skipped.txt")); err == nil {
+		t.Error("expected the command whose condition does not hold to be skipped")
+	}
+}
@@ -5,22 +5,33 @@ package templating
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
 // ProgressReporter handles progress reporting for long-running operations.
 // This struct provides a consistent interface for reporting progress to users
 // with spinners, progress bars, and detailed status messages.
+//
+// A reporter is safe for concurrent use. A standalone reporter writes its
+// messages directly; a reporter returned by MultiProgress.Task sends them to
+// the MultiProgress renderer, which draws one line per task.
 type ProgressReporter struct {
-	currentStep    string    // Current operation being performed
-	totalSteps     int       // Total number of steps
-	currentStepNum int       // Current step number
-	startTime      time.Time // When the operation started
-	spinnerChars   []string  // Characters for spinner animation
-	spinnerIndex   int       // Current spinner character index
-	lastUpdate     time.Time // Last progress update time
-	verbose        bool      // Whether to show detailed progress
+	mu             sync.Mutex
+	task           string         // Task name when reporting to a MultiProgress
+	multi          *MultiProgress // Renderer of a task reporter, nil when standalone
+	currentStep    string         // Current operation being performed
+	totalSteps     int            // Total number of steps
+	currentStepNum int            // Current step number
+	startTime      time.Time      // When the operation started
+	spinnerChars   []string       // Characters for spinner animation
+	spinnerIndex   int            // Current spinner character index
+	lastUpdate     time.Time      // Last progress update time
+	verbose        bool           // Whether to show detailed progress
+	out            io.Writer      // Where progress is written
 }
 
 // NewProgressReporter creates a new progress reporter for tracking operations.
@@ -40,9 +51,28 @@ func NewProgressReporter(totalSteps int, verbose bool) *ProgressReporter {
 		startTime:    time.Now(),
 		spinnerChars: []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
 		lastUpdate:   time.Now(),
+		out:          os.Stdout,
 	}
 }
 
+// SetOutput redirects progress messages, e.g. to io.Discard when rendering
+// templates in bulk
+func (pr *ProgressReporter) SetOutput(w io.Writer) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.out = w
+}
+
+// sendToTask forwards a status update to the MultiProgress renderer. It
+// reports false for standalone reporters, which write their own output.
+func (pr *ProgressReporter) sendToTask(state taskState, status string) bool {
+	if pr.multi == nil {
+		return false
+	}
+	pr.multi.send(taskEvent{task: pr.task, state: state, status: status, at: time.Now()})
+	return true
+}
+
 // StartOperation begins a new operation and reports the initial status.
 // This function should be called at the beginning of any long-running operation
 // to set up progress tracking and provide initial feedback to the user.
@@ -50,15 +80,20 @@ func NewProgressReporter(totalSteps int, verbose bool) *ProgressReporter {
 // Parameters:
 //   - operation: The name of the operation being performed
 func (pr *ProgressReporter) StartOperation(operation string) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
 	pr.currentStep = operation
 	pr.currentStepNum = 0
 	pr.startTime = time.Now()
 	pr.lastUpdate = time.Now()
+	if pr.sendToTask(taskRunning, operation) {
+		return
+	}
 
 	if pr.verbose {
-		fmt.Printf("🚀 Starting: %s\n", operation)
+		fmt.Fprintf(pr.out, "🚀 Starting: %s\n", operation)
 	} else {
-		fmt.Printf("🔄 %s...", operation)
+		fmt.Fprintf(pr.out, "🔄 %s...", operation)
 	}
 }
 
@@ -71,8 +106,13 @@ func (pr *ProgressReporter) StartOperation(operation string) {
 //   - current: The current step number
 //   - total: The total number of steps
 func (pr *ProgressReporter) ReportProgress(step string, current, total int) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
 	pr.currentStep = step
 	pr.currentStepNum = current
+	if pr.sendToTask(taskRunning, fmt.Sprintf("%s (%d/%d)", step, current, total)) {
+		return
+	}
 
 	// Only update if enough time has passed to avoid spam
 	if time.Since(pr.lastUpdate) < 100*time.Millisecond {
@@ -83,11 +123,11 @@ func (pr *ProgressReporter) ReportProgress(step string, current, total int) {
 
 	if pr.verbose {
 		percentage := float64(current) / float64(total) * 100
-		fmt.Printf("  📋 %s (%d/%d) %.1f%%\n", step, current, total, percentage)
+		fmt.Fprintf(pr.out, "  📋 %s (%d/%d) %.1f%%\n", step, current, total, percentage)
 	} else {
 		// Update spinner in place
 		spinner := pr.spinnerChars[pr.spinnerIndex]
-		fmt.Printf("\r%s %s (%d/%d)", spinner, step, current, total)
+		fmt.Fprintf(pr.out, "\r%s %s (%d/%d)", spinner, step, current, total)
 		pr.spinnerIndex = (pr.spinnerIndex + 1) % len(pr.spinnerChars)
 	}
 }
@@ -101,25 +141,36 @@ func (pr *ProgressReporter) ReportProgress(step string, current, total int) {
 //   - success: Whether the step completed successfully
 //   - details: Optional details about the completion
 func (pr *ProgressReporter) CompleteStep(step string, success bool, details string) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if pr.multi != nil {
+		status := step + " completed"
+		if !success {
+			status = step + " failed"
+		}
+		pr.sendToTask(taskRunning, status)
+		return
+	}
+
 	if pr.verbose {
 		if success {
-			fmt.Printf("  ✅ %s completed", step)
+			fmt.Fprintf(pr.out, "  ✅ %s completed", step)
 			if details != "" {
-				fmt.Printf(": %s", details)
+				fmt.Fprintf(pr.out, ": %s", details)
 			}
-			fmt.Println()
+			fmt.Fprintln(pr.out)
 		} else {
-			fmt.Printf("  ❌ %s failed", step)
+			fmt.Fprintf(pr.out, "  ❌ %s failed", step)
 			if details != "" {
-				fmt.Printf(": %s", details)
+				fmt.Fprintf(pr.out, ": %s", details)
 			}
-			fmt.Println()
+			fmt.Fprintln(pr.out)
 		}
 	} else {
 		if success {
-			fmt.Printf("\r✅ %s completed\n", step)
+			fmt.Fprintf(pr.out, "\r✅ %s completed\n", step)
 		} else {
-			fmt.Printf("\r❌ %s failed\n", step)
+			fmt.Fprintf(pr.out, "\r❌ %s failed\n", step)
 		}
 	}
 }
@@ -132,22 +183,35 @@ func (pr *ProgressReporter) CompleteStep(step string, success bool, details stri
 //   - success: Whether the entire operation completed successfully
 //   - summary: Optional summary of the operation results
 func (pr *ProgressReporter) CompleteOperation(success bool, summary string) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
 	duration := time.Since(pr.startTime)
+	if pr.multi != nil {
+		state, status := taskSucceeded, pr.currentStep
+		if !success {
+			state = taskFailed
+		}
+		if summary != "" {
+			status = summary
+		}
+		pr.sendToTask(state, status)
+		return
+	}
 
 	if pr.verbose {
 		if success {
-			fmt.Printf("🎉 Operation completed successfully in %v\n", duration.Round(time.Millisecond))
+			fmt.Fprintf(pr.out, "🎉 Operation completed successfully in %v\n", duration.Round(time.Millisecond))
 		} else {
-			fmt.Printf("💥 Operation failed after %v\n", duration.Round(time.Millisecond))
+			fmt.Fprintf(pr.out, "💥 Operation failed after %v\n", duration.Round(time.Millisecond))
 		}
 		if summary != "" {
-			fmt.Printf("📋 Summary: %s\n", summary)
+			fmt.Fprintf(pr.out, "📋 Summary: %s\n", summary)
 		}
 	} else {
 		if success {
-			fmt.Printf("✅ Completed in %v\n", duration.Round(time.Millisecond))
+			fmt.Fprintf(pr.out, "✅ Completed in %v\n", duration.Round(time.Millisecond))
 		} else {
-			fmt.Printf("❌ Failed after %v\n", duration.Round(time.Millisecond))
+			fmt.Fprintf(pr.out, "❌ Failed after %v\n", duration.Round(time.Millisecond))
 		}
 	}
 }
@@ -174,11 +238,17 @@ func (pr *ProgressReporter) ReportFileOperation(operation, filePath string, curr
 //   - command: The command being executed
 //   - description: Human-readable description of the command
 func (pr *ProgressReporter) ReportCommandExecution(command, description string) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if pr.sendToTask(taskRunning, "🔧 "+description) {
+		return
+	}
+
 	if pr.verbose {
-		fmt.Printf("  🔧 Executing: %s\n", description)
-		fmt.Printf("    Command: %s\n", command)
+		fmt.Fprintf(pr.out, "  🔧 Executing: %s\n", description)
+		fmt.Fprintf(pr.out, "    Command: %s\n", command)
 	} else {
-		fmt.Printf("🔧 %s...", description)
+		fmt.Fprintf(pr.out, "🔧 %s...", description)
 	}
 }
 
@@ -191,25 +261,36 @@ func (pr *ProgressReporter) ReportCommandExecution(command, description string)
 //   - success: Whether the command executed successfully
 //   - output: Optional command output or error message
 func (pr *ProgressReporter) ReportCommandResult(command string, success bool, output string) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if pr.multi != nil {
+		status := command + " completed"
+		if !success {
+			status = command + " failed"
+		}
+		pr.sendToTask(taskRunning, status)
+		return
+	}
+
 	if pr.verbose {
 		if success {
-			fmt.Printf("  ✅ Command completed successfully")
+			fmt.Fprintf(pr.out, "  ✅ Command completed successfully")
 			if output != "" {
-				fmt.Printf(" (output: %s)", strings.TrimSpace(output))
+				fmt.Fprintf(pr.out, " (output: %s)", strings.TrimSpace(output))
 			}
-			fmt.Println()
+			fmt.Fprintln(pr.out)
 		} else {
-			fmt.Printf("  ❌ Command failed")
+			fmt.Fprintf(pr.out, "  ❌ Command failed")
 			if output != "" {
-				fmt.Printf(" (error: %s)", strings.TrimSpace(output))
+				fmt.Fprintf(pr.out, " (error: %s)", strings.TrimSpace(output))
 			}
-			fmt.Println()
+			fmt.Fprintln(pr.out)
 		}
 	} else {
 		if success {
-			fmt.Printf("✅ %s completed\n", command)
+			fmt.Fprintf(pr.out, "✅ %s completed\n", command)
 		} else {
-			fmt.Printf("❌ %s failed\n", command)
+			fmt.Fprintf(pr.out, "❌ %s failed\n", command)
 		}
 	}
 }
@@ -247,6 +328,8 @@ func (pr *ProgressReporter) ReportParameterCollection(parameterName string, curr
 // Returns:
 //   - The elapsed time as a duration
 func (pr *ProgressReporter) GetElapsedTime() time.Duration {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
 	return time.Since(pr.startTime)
 }
 
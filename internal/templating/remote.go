@@ -0,0 +1,195 @@
+package templating
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jashkahar/open-workbench-platform/internal/debuglog"
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+)
+
+// repoHosts are the hosts whose repositories are always <host>/<owner>/<repo>,
+// so whatever follows names a directory inside the repository
+var repoHosts = map[string]bool{
+	"github.com":    true,
+	"gitlab.com":    true,
+	"bitbucket.org": true,
+}
+
+// locationPattern and refPattern keep remote template names to characters
+// that are safe in paths, URLs, and git arguments
+var (
+	locationPattern = regexp.MustCompile(`^[A-Za-z0-9._/-]+$`)
+	refPattern      = regexp.MustCompile(`^[A-Za-z0-9._][A-Za-z0-9._/+-]*$`)
+)
+
+// RemoteSource is a template kept in a git repository, named like a Go
+// module: github.com/org/tpl@v1.2.0 for a template at the root of a
+// repository, github.com/org/templates/python-api@v1.2.0 for one in a
+// directory of it, and git.example.com/team/templates.git/python-api for
+// repositories on other hosts, where .git marks the end of the repository
+type RemoteSource struct {
+	Repo string // repository without scheme, e.g. github.com/org/templates
+	Dir  string // directory of the template inside the repository; empty for its root
+	Ref  string // tag or branch to check out; empty for the default branch
+}
+
+// IsRemoteTemplate reports whether a template name refers to a git
+// repository rather than a template built into om
+func IsRemoteTemplate(name string) bool {
+	host, _, found := strings.Cut(name, "/")
+	return found && strings.Contains(host, ".")
+}
+
+// ParseRemoteSource parses the name of a remote template
+func ParseRemoteSource(name string) (RemoteSource, error) {
+	location, ref, pinned := strings.Cut(name, "@")
+	if pinned && !refPattern.MatchString(ref) {
+		return RemoteSource{}, fmt.Errorf("template '%s' has an invalid version after @", name)
+	}
+	if !IsRemoteTemplate(location) || !fs.ValidPath(location) || !locationPattern.MatchString(location) {
+		return RemoteSource{}, fmt.Errorf("template '%s' is not a repository path such as github.com/org/template@v1.0.0", name)
+	}
+
+	segments := strings.Split(location, "/")
+	end := len(segments)
+	if repoHosts[segments[0]] {
+		if len(segments) < 3 {
+			return RemoteSource{}, fmt.Errorf("template '%s' names no repository; use %s/<owner>/<repository>", name, segments[0])
+		}
+		end = 3
+	} else {
+		for i, segment := range segments {
+			if i > 0 && strings.HasSuffix(segment, ".git") {
+				end = i + 1
+				break
+			}
+		}
+	}
+	return RemoteSource{
+		Repo: strings.Join(segments[:end], "/"),
+		Dir:  strings.Join(segments[end:], "/"),
+		Ref:  ref,
+	}, nil
+}
+
+// URL returns the address the repository is cloned from. Repositories that
+// need SSH can be redirected with git's url.<base>.insteadOf setting.
+func (s RemoteSource) URL() string {
+	return "https://" + s.Repo
+}
+
+// Fetch clones the repository of the template into cacheDir, unless a clone
+// of the same version is already there, and returns the directory holding
+// the template. Tags and branches are treated as immutable once cloned;
+// the default branch is cloned again on every fetch.
+func (s RemoteSource) Fetch(run deps.Runner, cacheDir, name string) (string, error) {
+	version := "@latest"
+	if s.Ref != "" {
+		version = "@" + url.PathEscape(s.Ref)
+	}
+	cloneDir := filepath.Join(cacheDir, filepath.FromSlash(s.Repo), version)
+	templateDir := filepath.Join(cloneDir, filepath.FromSlash(s.Dir))
+
+	if _, err := os.Stat(cloneDir); err == nil && s.Ref != "" {
+		debuglog.Printf("using cached template %s from %s", name, cloneDir)
+	} else {
+		if err := os.RemoveAll(cloneDir); err != nil {
+			return "", NewFileSystemError("clear cached template", cloneDir, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(cloneDir), 0755); err != nil {
+			return "", NewFileSystemError("create template cache", filepath.Dir(cloneDir), err)
+		}
+		args := []string{"clone", "--quiet", "--depth", "1"}
+		if s.Ref != "" {
+			args = append(args, "--branch", s.Ref)
+		}
+		args = append(args, s.URL(), cloneDir)
+		if _, err := run(filepath.Dir(cloneDir), "git", args...); err != nil {
+			os.RemoveAll(cloneDir)
+			return "", NewNetworkError(fmt.Sprintf("git clone of %s", s.URL()), err)
+		}
+		// The history would otherwise be scaffolded along with the template
+		if err := os.RemoveAll(filepath.Join(cloneDir, ".git")); err != nil {
+			return "", NewFileSystemError("remove git metadata", cloneDir, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(templateDir, "template.json")); err != nil {
+		return "", NewTemplateNotFoundError(name, err)
+	}
+	return templateDir, nil
+}
+
+// WithRemoteTemplate returns templateFS with the template in dir added to
+// it under name, so that catalogs, validation, and scaffolding treat it
+// like a template built into om
+func WithRemoteTemplate(templateFS fs.FS, name, dir string) fs.FS {
+	return &remoteFS{base: templateFS, root: "templates/" + name, template: os.DirFS(dir)}
+}
+
+// remoteFS serves one remote template on top of the built-in templates
+type remoteFS struct {
+	base     fs.FS
+	root     string
+	template fs.FS
+}
+
+// Open opens files under the remote template from its directory and every
+// other file from the built-in templates
+func (r *remoteFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == r.root {
+		return r.template.Open(".")
+	}
+	if rest, found := strings.CutPrefix(name, r.root+"/"); found {
+		return r.template.Open(rest)
+	}
+	return r.base.Open(name)
+}
+
+// ReadDir lists directories of the remote template from its directory and
+// every other directory from the built-in templates, so that listings keep
+// whatever the built-in templates merge in, such as local templates
+func (r *remoteFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == r.root {
+		return fs.ReadDir(r.template, ".")
+	}
+	if rest, found := strings.CutPrefix(name, r.root+"/"); found {
+		return fs.ReadDir(r.template, rest)
+	}
+	return fs.ReadDir(r.base, name)
+}
+
+// ResolveTemplate returns the file system to read a template from: the
+// built-in templates for built-in names, and the built-in templates with
+// the fetched template added for remote ones
+func ResolveTemplate(templateFS fs.FS, name, cacheDir string, run deps.Runner) (fs.FS, error) {
+	if !IsRemoteTemplate(name) {
+		return templateFS, nil
+	}
+	source, err := ParseRemoteSource(name)
+	if err != nil {
+		return nil, err
+	}
+	if cacheDir == "" {
+		return nil, errors.New("no directory to cache remote templates in")
+	}
+	dir, err := source.Fetch(run, cacheDir, name)
+	if err != nil {
+		return nil, err
+	}
+	debuglog.Printf("remote template %s is in %s", name, dir)
+	return WithRemoteTemplate(templateFS, name, dir), nil
+}
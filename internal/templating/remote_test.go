@@ -0,0 +1,183 @@
+package templating
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/deps"
+	"github.com/jashkahar/open-workbench-platform/internal/testutil"
+)
+
+func TestParseRemoteSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    RemoteSource
+		wantErr bool
+	}{
+		{name: "github.com/org/tpl@v1.2.0", want: RemoteSource{Repo: "github.com/org/tpl", Ref: "v1.2.0"}},
+		{name: "github.com/org/templates/python/api", want: RemoteSource{Repo: "github.com/org/templates", Dir: "python/api"}},
+		{name: "git.example.com/team/templates.git/api@main", want: RemoteSource{Repo: "git.example.com/team/templates.git", Dir: "api", Ref: "main"}},
+		{name: "git.example.com/team/api-template", want: RemoteSource{Repo: "git.example.com/team/api-template"}},
+		{name: "github.com/org", wantErr: true},
+		{name: "github.com/org/tpl@", wantErr: true},
+		{name: "github.com/org/tpl@-x", wantErr: true},
+		{name: "git.example.com/team/tpl;rm", wantErr: true},
+		{name: "github.com/org/../tpl", wantErr: true},
+		{name: "https://github.com/org/tpl", wantErr: true},
+		{name: "go-api", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRemoteSource(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRemoteSource(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRemoteSource(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if IsRemoteTemplate("go-api") || !IsRemoteTemplate("github.com/org/tpl") {
+		t.Error("expected only repository paths to be remote")
+	}
+}
+
+// fakeClone returns a runner that clones a repository holding an api
+// template, counting its clones
+func fakeClone(t *testing.T, clones *int) deps.Runner {
+	return func(dir, name string, args ...string) ([]byte, error) {
+		if name != "git" || args[0] != "clone" {
+			t.Fatalf("unexpected command %s %v", name, args)
+		}
+		*clones++
+		target := args[len(args)-1]
+		files := map[string]string{
+			".git/HEAD":            "ref: refs/heads/main",
+			"api/template.json":    `{"name": "api", "description": "Company API", "parameters": [{"name": "ServiceName", "prompt": "Name?", "type": "string"}]}`,
+			"api/README.md.tmpl":   "# {{.ServiceName}}",
+			"api/src/main.go.tmpl": "package main",
+			"other/template.json":  `{}`,
+		}
+		for file, content := range files {
+			path := filepath.Join(target, filepath.FromSlash(file))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+}
+
+func TestResolveTemplate(t *testing.T) {
+	cacheDir := t.TempDir()
+	builtin := testutil.NewCatalog(map[string]testutil.Template{
+		"web": {Manifest: `{"name": "web", "description": "A web app", "parameters": [{"name": "ServiceName", "prompt": "Name?", "type": "string"}]}`},
+	})
+	clones := 0
+	name := "git.example.com/team/templates.git/api@v1.0.0"
+
+	for i := 0; i < 2; i++ {
+		templateFS, err := ResolveTemplate(builtin, name, cacheDir, fakeClone(t, &clones))
+		if err != nil {
+			t.Fatalf("ResolveTemplate failed: %v", err)
+		}
+		manifest, err := NewTemplateCatalog(templateFS).Manifest(name)
+		if err != nil || manifest.Description != "Company API" {
+			t.Fatalf("expected the remote manifest, got %+v, %v", manifest, err)
+		}
+		if _, err := LoadTemplateManifest(templateFS, "web"); err != nil {
+			t.Errorf("expected built-in templates to stay available: %v", err)
+		}
+		files, _, err := TemplateFiles(templateFS, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(files, ",") != "README.md,src/main.go" {
+			t.Errorf("expected only the files of the template, got %v", files)
+		}
+	}
+	if clones != 1 {
+		t.Errorf("expected a pinned version to be cloned once, got %d clones", clones)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "git.example.com", "team", "templates.git", "@v1.0.0", ".git")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected the git metadata to be removed, got %v", err)
+	}
+
+	// The default branch is fetched again every time
+	for i := 0; i < 2; i++ {
+		if _, err := ResolveTemplate(builtin, "git.example.com/team/templates.git/api", cacheDir, fakeClone(t, &clones)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if clones != 3 {
+		t.Errorf("expected the default branch to be cloned on every fetch, got %d clones", clones)
+	}
+
+	templateFS, err := ResolveTemplate(builtin, "web", cacheDir, nil)
+	if _, remote := templateFS.(*remoteFS); err != nil || remote {
+		t.Errorf("expected built-in names to resolve to the built-in templates, got %v", err)
+	}
+	_, err = ResolveTemplate(builtin, "git.example.com/team/templates.git/missing@v1.0.0", cacheDir, fakeClone(t, &clones))
+	if GetErrorType(err) != ErrorTypeTemplateNotFound {
+		t.Errorf("expected a directory without a template to be not found, got %v", err)
+	}
+	failing := func(dir, name string, args ...string) ([]byte, error) { return nil, errors.New("repository not found") }
+	_, err = ResolveTemplate(builtin, "github.com/org/private@v2.0.0", cacheDir, failing)
+	if GetErrorType(err) != ErrorTypeNetwork {
+		t.Errorf("expected a failed clone to be a network error, got %v", err)
+	}
+}
+
+func TestResolveTemplate_WithLocalTemplates(t *testing.T) {
+	builtin := testutil.NewCatalog(map[string]testutil.Template{
+		"web": {Manifest: `{"name": "web", "description": "A web app", "parameters": [{"name": "ServiceName", "prompt": "Name?", "type": "string"}]}`},
+	})
+	localDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(localDir, "worker"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `{"name": "worker", "description": "My worker", "parameters": [{"name": "ServiceName", "prompt": "Name?", "type": "string"}]}`
+	if err := os.WriteFile(filepath.Join(localDir, "worker", "template.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	clones := 0
+	name := "git.example.com/team/templates.git/api@v1.0.0"
+
+	templateFS, err := ResolveTemplate(WithLocalTemplates(builtin, localDir), name, t.TempDir(), fakeClone(t, &clones))
+	if err != nil {
+		t.Fatalf("ResolveTemplate failed: %v", err)
+	}
+	if _, ok := templateFS.(fs.ReadDirFS); !ok {
+		t.Fatal("expected the remote template to keep directory listings")
+	}
+
+	// The local template stays listed and usable next to the remote one
+	templates, err := DiscoverTemplates(templateFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var listed []string
+	for _, template := range templates {
+		listed = append(listed, template.Name)
+	}
+	if got := strings.Join(listed, ","); got != "web,worker" {
+		t.Errorf("DiscoverTemplates() = %s", got)
+	}
+	if local, err := NewTemplateCatalog(templateFS).Manifest("worker"); err != nil || local.Description != "My worker" {
+		t.Errorf("expected the local manifest, got %+v, %v", local, err)
+	}
+	if remote, err := NewTemplateCatalog(templateFS).Manifest(name); err != nil || remote.Description != "Company API" {
+		t.Errorf("expected the remote manifest, got %+v, %v", remote, err)
+	}
+	files, _, err := TemplateFiles(templateFS, name)
+	if err != nil || strings.Join(files, ",") != "README.md,src/main.go" {
+		t.Errorf("TemplateFiles() = %v, %v", files, err)
+	}
+}
@@ -0,0 +1,85 @@
+// Package testutil provides shared helpers for end-to-end tests of the om
+// commands: an in-memory template catalog, a strict scripted prompter,
+// snapshots of in-memory workspaces, and golden-file assertions.
+package testutil
+
+import (
+	"io/fs"
+	"path"
+	"sync"
+	"testing/fstest"
+)
+
+// Template describes a template in a fake catalog
+type Template struct {
+	// Manifest is the raw template.json content
+	Manifest string
+	// Files maps paths relative to the template root to their content
+	Files map[string]string
+}
+
+// NewCatalog builds an in-memory catalog laid out like the embedded
+// templates directory (templates/<name>/template.json plus files)
+func NewCatalog(templates map[string]Template) fstest.MapFS {
+	catalog := fstest.MapFS{}
+	for name, template := range templates {
+		root := path.Join("templates", name)
+		catalog[path.Join(root, "template.json")] = &fstest.MapFile{Data: []byte(template.Manifest)}
+		for file, content := range template.Files {
+			catalog[path.Join(root, file)] = &fstest.MapFile{Data: []byte(content)}
+		}
+	}
+	return catalog
+}
+
+// DemoServiceTemplate is a small service template that exercises parameter
+// substitution and conditional post-scaffold file deletion
+var DemoServiceTemplate = Template{
+	Manifest: `{
+  "name": "demo-service",
+  "description": "A demo service",
+  "parameters": [
+    {"name": "ServiceName", "prompt": "Service name?", "type": "string", "required": true},
+    {"name": "IncludeDocs", "prompt": "Include docs?", "type": "boolean", "default": true}
+  ],
+  "postScaffold": {
+    "filesToDelete": [{"path": "docs/", "condition": "IncludeDocs == false"}]
+  }
+}`,
+	Files: map[string]string{
+		"README.md":     "# {{ .ServiceName }}\n",
+		"docs/index.md": "docs\n",
+	},
+}
+
+// DefaultCatalog returns a catalog containing only DemoServiceTemplate
+func DefaultCatalog() fstest.MapFS {
+	return NewCatalog(map[string]Template{"demo-service": DemoServiceTemplate})
+}
+
+// CountingFS wraps a catalog and counts how often each file is opened
+type CountingFS struct {
+	fs.FS
+	mu    sync.Mutex
+	opens map[string]int
+}
+
+// NewCountingFS wraps fsys in a CountingFS
+func NewCountingFS(fsys fs.FS) *CountingFS {
+	return &CountingFS{FS: fsys, opens: make(map[string]int)}
+}
+
+// Open opens a file and records the access
+func (c *CountingFS) Open(name string) (fs.File, error) {
+	c.mu.Lock()
+	c.opens[name]++
+	c.mu.Unlock()
+	return c.FS.Open(name)
+}
+
+// Opens returns how often name has been opened
+func (c *CountingFS) Opens(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.opens[name]
+}
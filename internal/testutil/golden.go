@@ -0,0 +1,62 @@
+package testutil
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+// update rewrites golden files instead of comparing against them:
+//
+//	go test ./cmd/... -update
+var update = flag.Bool("update", false, "update golden files")
+
+// AssertGolden compares got with testdata/<name>.golden in the calling
+// package's directory. Run the tests with -update to rewrite the file.
+func AssertGolden(t testing.TB, name string, got []byte) {
+	t.Helper()
+
+	goldenPath := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Errorf("output does not match %s (run with -update to accept):\n--- want\n%s\n--- got\n%s", goldenPath, want, got)
+	}
+}
+
+// Snapshot renders every file of m under root as a single document: each
+// file appears as a "== path ==" header followed by its content. Paths are
+// relative to root and use forward slashes, so snapshots are portable.
+func Snapshot(m *filesystem.MemFS, root string) []byte {
+	var buf bytes.Buffer
+	for _, name := range m.Files() {
+		rel, err := filepath.Rel(root, name)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		data, _ := m.ReadFile(name)
+		buf.WriteString("== " + filepath.ToSlash(rel) + " ==\n")
+		buf.Write(data)
+		if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+			buf.WriteString("\n")
+		}
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,21 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/jashkahar/open-workbench-platform/internal/prompt"
+)
+
+// NewPrompter returns a scripted prompter that fails the test at cleanup if
+// any of the scripted answers was never asked for, which keeps scripts in
+// sync with the prompts the commands actually show
+func NewPrompter(t testing.TB, answers map[string]interface{}) *prompt.Scripted {
+	t.Helper()
+	scripted := prompt.NewScripted(answers)
+	t.Cleanup(func() {
+		if unasked := scripted.Unasked(); len(unasked) > 0 {
+			t.Errorf("scripted answers were never asked for: %v", unasked)
+		}
+	})
+	return scripted
+}
@@ -0,0 +1,140 @@
+// Package textdiff renders line-based unified diffs, as printed by
+// 'diff -u', for showing how a regenerated file changed
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change
+const contextLines = 3
+
+// opKind is the kind of a line in an edit script
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// edit is one line of an edit script
+type edit struct {
+	kind opKind
+	line string
+	a, b int // line indexes in the old and new text
+}
+
+// Unified returns a unified diff from oldText to newText labelled with
+// oldName and newName, or "" when the texts are equal
+func Unified(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	a, b := splitLines(oldText), splitLines(newText)
+	edits := editScript(a, b)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+	for _, hunk := range hunks(edits) {
+		writeHunk(&out, edits[hunk[0]:hunk[1]])
+	}
+	return out.String()
+}
+
+// splitLines splits text into lines without their line endings
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// editScript returns the shortest edit script from a to b, computed from
+// their longest common subsequence
+func editScript(a, b []string) []edit {
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var edits []edit
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{kind: opEqual, line: a[i], a: i, b: j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{kind: opDelete, line: a[i], a: i, b: j})
+			i++
+		default:
+			edits = append(edits, edit{kind: opInsert, line: b[j], a: i, b: j})
+			j++
+		}
+	}
+	return edits
+}
+
+// hunks groups the changes of an edit script with their context into
+// [start, end) ranges, merging changes whose context overlaps
+func hunks(edits []edit) [][2]int {
+	var ranges [][2]int
+	for i, e := range edits {
+		if e.kind == opEqual {
+			continue
+		}
+		start, end := max(i-contextLines, 0), min(i+contextLines+1, len(edits))
+		if len(ranges) > 0 && start <= ranges[len(ranges)-1][1] {
+			ranges[len(ranges)-1][1] = end
+		} else {
+			ranges = append(ranges, [2]int{start, end})
+		}
+	}
+	return ranges
+}
+
+// writeHunk writes a hunk header and its lines
+func writeHunk(out *strings.Builder, hunk []edit) {
+	var oldCount, newCount int
+	for _, e := range hunk {
+		if e.kind != opInsert {
+			oldCount++
+		}
+		if e.kind != opDelete {
+			newCount++
+		}
+	}
+	oldStart, newStart := hunk[0].a+1, hunk[0].b+1
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, e := range hunk {
+		switch e.kind {
+		case opEqual:
+			out.WriteString(" " + e.line + "\n")
+		case opDelete:
+			out.WriteString("-" + e.line + "\n")
+		case opInsert:
+			out.WriteString("+" + e.line + "\n")
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package trash keeps the files om deletes for a while instead of removing
+// them at once. Deleted directories are moved under .om/trash/ in a folder
+// named after the time of deletion, keeping their path relative to the
+// project root, so restoring one is a copy back; folders older than the
+// retention window are purged on later deletions.
+package trash
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+	"github.com/jashkahar/open-workbench-platform/internal/manifest"
+)
+
+// Dir is the directory, relative to the project root, that holds deleted files
+var Dir = filepath.Join(".om", "trash")
+
+// Retention is how long deleted files are kept
+const Retention = 7 * 24 * time.Hour
+
+// stampLayout names the folder of a deletion after its time
+const stampLayout = "20060102-150405"
+
+// CheckContained returns an error unless rel, a path from workbench.yaml,
+// names a directory strictly inside the project at projectRoot. On the real
+// file system symbolic links are resolved first, so a link cannot point a
+// deletion outside the project.
+func CheckContained(fsys filesystem.FS, projectRoot, rel string) error {
+	if !manifest.IsProjectPath(rel) {
+		return fmt.Errorf("'%s' is not a path inside the project", rel)
+	}
+	if clean := path.Clean(filepath.ToSlash(rel)); clean == ".om" || strings.HasPrefix(clean, ".om/") {
+		return fmt.Errorf("'%s' is om's own data", rel)
+	}
+	if !filesystem.IsOS(fsys) {
+		return nil
+	}
+
+	root, err := filepath.EvalSymlinks(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the project root: %w", err)
+	}
+	target, err := filepath.EvalSymlinks(filepath.Join(projectRoot, rel))
+	if err != nil {
+		return fmt.Errorf("failed to resolve '%s': %w", rel, err)
+	}
+	inside, err := filepath.Rel(root, target)
+	if err != nil || !manifest.IsProjectPath(inside) {
+		return fmt.Errorf("'%s' resolves to %s, outside the project", rel, target)
+	}
+	return nil
+}
+
+// Move moves the directory at rel, relative to projectRoot, into the trash
+// and returns where it went, relative to the project root. Symbolic links
+// inside the directory are removed without being kept, so that moving never
+// reads outside the project.
+func Move(fsys filesystem.FS, projectRoot, rel string, now time.Time) (string, error) {
+	if err := CheckContained(fsys, projectRoot, rel); err != nil {
+		return "", err
+	}
+	source := filepath.Join(projectRoot, rel)
+	if _, err := fsys.Stat(source); err != nil {
+		return "", err
+	}
+
+	folder := filepath.Join(Dir, now.UTC().Format(stampLayout))
+	for i := 2; filesystem.Exists(fsys, filepath.Join(projectRoot, folder, rel)); i++ {
+		folder = filepath.Join(Dir, fmt.Sprintf("%s-%d", now.UTC().Format(stampLayout), i))
+	}
+	destination := filepath.Join(folder, filepath.Clean(rel))
+
+	if err := copyTree(fsys, source, filepath.Join(projectRoot, destination)); err != nil {
+		_ = fsys.RemoveAll(filepath.Join(projectRoot, destination))
+		return "", fmt.Errorf("failed to move '%s' to %s: %w", rel, filepath.ToSlash(folder), err)
+	}
+	if err := fsys.RemoveAll(source); err != nil {
+		return "", fmt.Errorf("failed to remove '%s' after moving it to %s: %w", rel, filepath.ToSlash(folder), err)
+	}
+	return destination, nil
+}
+
+// copyTree copies the directory source to destination, keeping file modes
+func copyTree(fsys filesystem.FS, source, destination string) error {
+	info, err := fsys.Stat(source)
+	if err != nil {
+		return err
+	}
+	if err := fsys.MkdirAll(destination, info.Mode().Perm()|0700); err != nil {
+		return err
+	}
+	entries, err := fsys.ReadDir(source)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		from, to := filepath.Join(source, entry.Name()), filepath.Join(destination, entry.Name())
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			continue
+		case entry.IsDir():
+			if err := copyTree(fsys, from, to); err != nil {
+				return err
+			}
+		default:
+			data, err := fsys.ReadFile(from)
+			if err != nil {
+				return err
+			}
+			mode := fs.FileMode(0644)
+			if info, err := entry.Info(); err == nil {
+				mode = info.Mode().Perm()
+			}
+			if err := fsys.WriteFile(to, data, mode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Purge removes the deletions older than the retention window and returns
+// how many it removed. Folders that are not named after a time are kept.
+func Purge(fsys filesystem.FS, projectRoot string, now time.Time) (int, error) {
+	dir := filepath.Join(projectRoot, Dir)
+	if !filesystem.Exists(fsys, dir) {
+		return 0, nil
+	}
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", Dir, err)
+	}
+
+	purged := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || len(name) < len(stampLayout) {
+			continue
+		}
+		deleted, err := time.Parse(stampLayout, name[:len(stampLayout)])
+		if err != nil || now.Sub(deleted) < Retention {
+			continue
+		}
+		if err := fsys.RemoveAll(filepath.Join(dir, name)); err != nil {
+			return purged, fmt.Errorf("failed to purge %s: %w", filepath.ToSlash(filepath.Join(Dir, name)), err)
+		}
+		purged++
+	}
+	return purged, nil
+}
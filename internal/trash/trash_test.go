@@ -0,0 +1,90 @@
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jashkahar/open-workbench-platform/internal/filesystem"
+)
+
+func TestCheckContained(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	for _, rel := range []string{"api", "./apps/api"} {
+		if err := CheckContained(fsys, "demo", rel); err != nil {
+			t.Errorf("expected %s to be deletable, got %v", rel, err)
+		}
+	}
+	for _, rel := range []string{"", ".", "..", "../outside", "apps/../..", "/etc", ".om", ".om/trash/x"} {
+		if err := CheckContained(fsys, "demo", rel); err == nil {
+			t.Errorf("expected %q to be refused", rel)
+		}
+	}
+}
+
+func TestCheckContained_Symlink(t *testing.T) {
+	root := t.TempDir()
+	project, outside := filepath.Join(root, "demo"), filepath.Join(root, "outside")
+	for _, dir := range []string{filepath.Join(project, "api"), outside} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(project, "linked")); err != nil {
+		t.Skipf("symbolic links are not supported: %v", err)
+	}
+
+	fsys := filesystem.NewOSFS()
+	if err := CheckContained(fsys, project, "api"); err != nil {
+		t.Errorf("expected api to be deletable, got %v", err)
+	}
+	if err := CheckContained(fsys, project, "linked"); err == nil {
+		t.Error("expected a link pointing outside the project to be refused")
+	}
+}
+
+func TestMoveAndPurge(t *testing.T) {
+	fsys := filesystem.NewMemFS()
+	if err := fsys.MkdirAll(filepath.Join("demo", "apps", "api", "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(filepath.Join("demo", "apps", "api", "src", "main.go"), []byte("package main\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	moved, err := Move(fsys, "demo", "./apps/api", deleted)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if want := filepath.Join(".om", "trash", "20260304-050607", "apps", "api"); moved != want {
+		t.Errorf("expected %s, got %s", want, moved)
+	}
+	if filesystem.Exists(fsys, filepath.Join("demo", "apps", "api")) {
+		t.Error("expected the directory to be removed")
+	}
+	info, err := fsys.Stat(filepath.Join("demo", moved, "src", "main.go"))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected main.go in the trash with its mode, got %v, %v", info, err)
+	}
+
+	// A second deletion of the same path in the same second gets its own folder
+	if err := fsys.MkdirAll(filepath.Join("demo", "apps", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	moved, err = Move(fsys, "demo", "apps/api", deleted)
+	if err != nil || moved != filepath.Join(".om", "trash", "20260304-050607-2", "apps", "api") {
+		t.Errorf("expected a separate folder, got %s, %v", moved, err)
+	}
+
+	if purged, err := Purge(fsys, "demo", deleted.Add(Retention-time.Second)); err != nil || purged != 0 {
+		t.Errorf("expected nothing to expire within the retention window, purged %d, %v", purged, err)
+	}
+	if purged, err := Purge(fsys, "demo", deleted.Add(Retention)); err != nil || purged != 2 {
+		t.Errorf("expected both deletions to expire, purged %d, %v", purged, err)
+	}
+	if filesystem.Exists(fsys, filepath.Join("demo", ".om", "trash", "20260304-050607")) {
+		t.Error("expected the expired deletion to be purged")
+	}
+}
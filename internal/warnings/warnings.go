@@ -0,0 +1,54 @@
+// Package warnings describes problems that do not stop a command but are
+// likely mistakes, such as a service without a health check. Validators and
+// generators return them alongside their results so commands can report
+// them together at the end, and fail on them with --strict.
+package warnings
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Warning is a non-fatal problem with a workbench.yaml entry
+type Warning struct {
+	Entry   string // Manifest entry the warning is about, e.g. services.api
+	Message string // What is wrong and how to fix it
+}
+
+// New creates a warning about entry with a formatted message
+func New(entry, format string, args ...interface{}) Warning {
+	return Warning{Entry: entry, Message: fmt.Sprintf(format, args...)}
+}
+
+// String returns the warning as "entry: message"
+func (w Warning) String() string {
+	if w.Entry == "" {
+		return w.Message
+	}
+	return fmt.Sprintf("%s: %s", w.Entry, w.Message)
+}
+
+// Source is implemented by validators and generators that report warnings
+type Source interface {
+	// Warnings returns the warnings found by the last run
+	Warnings() []Warning
+}
+
+// Sort orders warnings by entry, then message, and drops duplicates
+func Sort(list []Warning) []Warning {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Entry != list[j].Entry {
+			return list[i].Entry < list[j].Entry
+		}
+		return list[i].Message < list[j].Message
+	})
+
+	var unique []Warning
+	for i, warning := range list {
+		if i > 0 && warning == list[i-1] {
+			continue
+		}
+		unique = append(unique, warning)
+	}
+	return unique
+}
@@ -0,0 +1,26 @@
+package warnings
+
+import "testing"
+
+func TestSort(t *testing.T) {
+	list := Sort([]Warning{
+		New("services.web", "no healthcheck"),
+		New("services.api.resources.db", "version is not pinned"),
+		New("services.web", "no healthcheck"),
+		New("", "project has no services"),
+	})
+
+	want := []string{
+		"project has no services",
+		"services.api.resources.db: version is not pinned",
+		"services.web: no healthcheck",
+	}
+	if len(list) != len(want) {
+		t.Fatalf("Sort() returned %d warnings, want %d: %v", len(list), len(want), list)
+	}
+	for i, warning := range list {
+		if warning.String() != want[i] {
+			t.Errorf("warning %d = %q, want %q", i, warning.String(), want[i])
+		}
+	}
+}
@@ -0,0 +1,38 @@
+import { useEffect, useState } from 'react';
+import { StatusBar } from 'expo-status-bar';
+import { StyleSheet, Text, View } from 'react-native';
+import Constants from 'expo-constants';
+
+const API_URL = process.env.EXPO_PUBLIC_API_URL || Constants.expoConfig?.extra?.apiUrl;
+
+export default function App() {
+  const [status, setStatus] = useState('Checking API...');
+
+  useEffect(() => {
+    fetch(`${API_URL}/health`)
+      .then((response) => response.json())
+      .then((body) => setStatus(`API status: ${body.status}`))
+      .catch(() => setStatus(`Could not reach ${API_URL}`));
+  }, []);
+
+  return (
+    <View style={styles.container}>
+      <Text style={styles.title}>{{.ProjectName}}</Text>
+      <Text>{status}</Text>
+      <StatusBar style="auto" />
+    </View>
+  );
+}
+
+const styles = StyleSheet.create({
+  container: {
+    flex: 1,
+    backgroundColor: '#fff',
+    alignItems: 'center',
+    justifyContent: 'center',
+  },
+  title: {
+    fontSize: 24,
+    marginBottom: 12,
+  },
+});
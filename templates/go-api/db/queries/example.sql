@@ -0,0 +1,5 @@
+-- name: ListItems :many
+SELECT id, name, created_at FROM items ORDER BY id;
+
+-- name: CreateItem :one
+INSERT INTO items (name) VALUES ($1) RETURNING id, name, created_at;
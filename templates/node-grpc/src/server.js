@@ -0,0 +1,40 @@
+const path = require('path');
+const grpc = require('@grpc/grpc-js');
+const protoLoader = require('@grpc/proto-loader');
+const { HealthImplementation } = require('grpc-health-check');
+
+const PORT = process.env.PORT || '50051';
+const PROTO_PATH = path.join(__dirname, '..', 'proto', 'greeter.proto');
+
+const definition = protoLoader.loadSync(PROTO_PATH, {
+  keepCase: true,
+  longs: String,
+  enums: String,
+  defaults: true,
+  oneofs: true,
+});
+const { greeter } = grpc.loadPackageDefinition(definition);
+
+function sayHello(call, callback) {
+  const name = call.request.name || 'world';
+  callback(null, { message: `Hello, ${name}!` });
+}
+
+function main() {
+  const server = new grpc.Server();
+  server.addService(greeter.v1.Greeter.service, { sayHello });
+
+  // Standard gRPC health service, used by load balancers and gateways
+  const health = new HealthImplementation({ '': 'SERVING', 'greeter.v1.Greeter': 'SERVING' });
+  health.addToServer(server);
+
+  server.bindAsync(`0.0.0.0:${PORT}`, grpc.ServerCredentials.createInsecure(), (err) => {
+    if (err) {
+      console.error('Failed to start gRPC server:', err);
+      process.exit(1);
+    }
+    console.log(`{{.ProjectName}} gRPC server listening on port ${PORT}`);
+  });
+}
+
+main();